@@ -86,23 +86,41 @@ func run(c *cli.Context) error {
 		return "session-" + sessionID + "-device-" + strconv.Itoa(i)
 	}
 
+	groupID := "session-" + sessionID
+	_, err := client.CreateDeviceGroup(ctx, connect.NewRequest(&iotv1.CreateDeviceGroupRequest{
+		GroupId: groupID,
+	}))
+	if err != nil {
+		return fmt.Errorf("create device group '%s': %w", groupID, err)
+	}
+	_, err = client.ConfigureDeviceGroup(ctx, connect.NewRequest(&iotv1.ConfigureDeviceGroupRequest{
+		GroupId: groupID,
+		Settings: &iotv1.ConfigSettings{
+			TemperatureThreshold: &tempThresh,
+			BatteryThreshold:     &batteryThresh,
+		},
+	}))
+	if err != nil {
+		return fmt.Errorf("configure device group '%s': %w", groupID, err)
+	}
+	logger.Info("configure device group", "group_id", groupID, "temperature_threshold", tempThresh, "battery_threshold", batteryThresh)
+
 	logger.Info(fmt.Sprintf("starting pool with %d workers", f.numDevices))
 	pool := NewWorkerPool(f.numDevices, 5*time.Second)
 	poolErrs := pool.Start(ctx)
 
-	logger.Info(fmt.Sprintf("setting up configurations for %d devices", f.numDevices))
+	logger.Info(fmt.Sprintf("creating %d devices in group %s", f.numDevices, groupID))
 	for i := range f.numDevices {
 		pool.QueueJob(func(ctx context.Context) error {
 			deviceID := getDeviceID(i)
-			_, err := client.ConfigureDevice(ctx, connect.NewRequest(&iotv1.ConfigureDeviceRequest{
-				DeviceId:             deviceID,
-				TemperatureThreshold: tempThresh,
-				BatteryThreshold:     batteryThresh,
+			_, err := client.CreateDevice(ctx, connect.NewRequest(&iotv1.CreateDeviceRequest{
+				DeviceId: deviceID,
+				Device:   &iotv1.Device{GroupId: groupID},
 			}))
 			if err != nil {
-				return fmt.Errorf("configure device '%s': %v", deviceID, err)
+				return fmt.Errorf("create device '%s': %v", deviceID, err)
 			}
-			logger.Info("configure device", "device_id", deviceID, "temperature_threshold", tempThresh, "battery_threshold", batteryThresh)
+			logger.Info("create device", "device_id", deviceID, "group_id", groupID)
 			return nil
 		})
 	}
@@ -14,10 +14,12 @@ import (
 // Config holds application configuration loaded from environment variables
 // and/or a YAML file.
 type Config struct {
-	Port            int        `yaml:"port" env:"PORT"`            // default: 8080
-	SQLiteDir       string     `yaml:"sqliteDir" env:"SQLITE_DIR"` // default: ./data/
-	Logger          Logger     `yaml:"logger" envPrefix:"LOGGER_"`
-	DeviceRateLimit *RateLimit `yaml:"deviceRateLimit" envPrefix:"DEVICE_RATE_LIMIT_"`
+	Port            int             `yaml:"port" env:"PORT"`            // default: 8080
+	SQLiteDir       string          `yaml:"sqliteDir" env:"SQLITE_DIR"` // default: ./data/
+	Logger          Logger          `yaml:"logger" envPrefix:"LOGGER_"`
+	DeviceRateLimit *RateLimit      `yaml:"deviceRateLimit" envPrefix:"DEVICE_RATE_LIMIT_"`
+	OfflineDetector OfflineDetector `yaml:"offlineDetector" envPrefix:"OFFLINE_DETECTOR_"`
+	PageTokens      PageTokens      `yaml:"pageTokens" envPrefix:"PAGE_TOKENS_"`
 }
 
 func (c Config) Validate() []error {
@@ -36,6 +38,18 @@ func (c Config) Validate() []error {
 			errs = append(errs, errors.New("deviceRateLimit.seconds: must be greater than 0"))
 		}
 	}
+	if c.OfflineDetector.IntervalSeconds <= 0 {
+		errs = append(errs, errors.New("offlineDetector.intervalSeconds: must be greater than 0"))
+	}
+	if c.OfflineDetector.GraceFactor < 1 {
+		errs = append(errs, errors.New("offlineDetector.graceFactor: must be at least 1"))
+	}
+	if c.PageTokens.Key != "" && len(c.PageTokens.Key) < minPageTokenKeyLen {
+		errs = append(errs, fmt.Errorf("pageTokens.key: must be at least %d characters", minPageTokenKeyLen))
+	}
+	if c.PageTokens.TTLSeconds <= 0 {
+		errs = append(errs, errors.New("pageTokens.ttlSeconds: must be greater than 0"))
+	}
 	return errs
 }
 
@@ -53,6 +67,28 @@ type RateLimit struct {
 	Seconds int `yaml:"seconds" env:"SECONDS"`
 }
 
+// OfflineDetector specifies how devices are checked for not recording metrics
+// within their configured reporting interval.
+type OfflineDetector struct {
+	// Interval in seconds between checks.
+	IntervalSeconds int `yaml:"intervalSeconds" env:"INTERVAL_SECONDS"` // default: 30
+	// Multiple of a device's reporting interval without metrics after which
+	// the device is considered offline.
+	GraceFactor float64 `yaml:"graceFactor" env:"GRACE_FACTOR"` // default: 2
+}
+
+const minPageTokenKeyLen = 32
+
+// PageTokens specifies how page tokens returned by paginated requests are
+// authenticated.
+type PageTokens struct {
+	// Secret key used to sign page tokens. If empty, a random key is generated
+	// on startup, so page tokens are not valid across restarts or replicas.
+	Key string `yaml:"key" env:"KEY"`
+	// Duration in seconds that page tokens are valid for.
+	TTLSeconds int `yaml:"ttlSeconds" env:"TTL_SECONDS"` // default: 3600
+}
+
 // Load reads the application config from a YAML file and environment variables.
 func Load(configFile string) (*Config, error) {
 	cfg := Config{
@@ -62,6 +98,13 @@ func Load(configFile string) (*Config, error) {
 			Level:      "info",
 			Structured: true,
 		},
+		OfflineDetector: OfflineDetector{
+			IntervalSeconds: 30,
+			GraceFactor:     2,
+		},
+		PageTokens: PageTokens{
+			TTLSeconds: 3600,
+		},
 	}
 
 	if configFile != "" {
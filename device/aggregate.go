@@ -0,0 +1,132 @@
+package device
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	iotv1 "github.com/joshjon/iot-metrics/proto/gen/iot/v1"
+)
+
+const (
+	BucketWidthMinute      BucketWidth = "1m"
+	BucketWidthFiveMinutes BucketWidth = "5m"
+	BucketWidthHour        BucketWidth = "1h"
+	BucketWidthDay         BucketWidth = "1d"
+)
+
+// BucketWidth is the width of the time buckets that metrics and alerts are
+// aggregated in.
+type BucketWidth string
+
+// Duration returns the width of the buckets, or 0 if the width is invalid.
+func (w BucketWidth) Duration() time.Duration {
+	switch w {
+	case BucketWidthMinute:
+		return time.Minute
+	case BucketWidthFiveMinutes:
+		return 5 * time.Minute
+	case BucketWidthHour:
+		return time.Hour
+	case BucketWidthDay:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+func (w BucketWidth) Proto() iotv1.BucketWidth {
+	switch w {
+	case BucketWidthMinute:
+		return iotv1.BucketWidth_BUCKET_WIDTH_1M
+	case BucketWidthFiveMinutes:
+		return iotv1.BucketWidth_BUCKET_WIDTH_5M
+	case BucketWidthHour:
+		return iotv1.BucketWidth_BUCKET_WIDTH_1H
+	case BucketWidthDay:
+		return iotv1.BucketWidth_BUCKET_WIDTH_1D
+	}
+	return iotv1.BucketWidth_BUCKET_WIDTH_UNSPECIFIED
+}
+
+func bucketWidthFromProto(w iotv1.BucketWidth) (BucketWidth, bool) {
+	switch w {
+	case iotv1.BucketWidth_BUCKET_WIDTH_1M:
+		return BucketWidthMinute, true
+	case iotv1.BucketWidth_BUCKET_WIDTH_5M:
+		return BucketWidthFiveMinutes, true
+	case iotv1.BucketWidth_BUCKET_WIDTH_1H:
+		return BucketWidthHour, true
+	case iotv1.BucketWidth_BUCKET_WIDTH_1D:
+		return BucketWidthDay, true
+	}
+	return "", false
+}
+
+// bucketStart returns the start of the bucket containing t, aligned to a
+// multiple of the bucket width since the unix epoch.
+func bucketStart(t time.Time, width time.Duration) time.Time {
+	secs := int64(width / time.Second)
+	unix := t.Unix()
+	return time.Unix(unix-unix%secs, 0).UTC()
+}
+
+// MetricAggregates are the aggregated values of a named metric in consecutive
+// buckets, in chronological order.
+type MetricAggregates struct {
+	Name    string
+	Buckets []MetricBucket
+}
+
+func (a MetricAggregates) Proto() *iotv1.MetricAggregates {
+	pb := &iotv1.MetricAggregates{
+		Name:    a.Name,
+		Buckets: make([]*iotv1.MetricBucket, len(a.Buckets)),
+	}
+	for i, b := range a.Buckets {
+		pb.Buckets[i] = b.Proto()
+	}
+	return pb
+}
+
+// MetricBucket is the aggregate of the values of a metric recorded within a
+// bucket. The aggregated values are nil if the bucket is empty.
+type MetricBucket struct {
+	Start time.Time
+	Count int64
+	Min   *float64
+	Max   *float64
+	Avg   *float64
+	// First and Last are the values of the earliest and latest metric in the
+	// bucket.
+	First *float64
+	Last  *float64
+}
+
+func (b MetricBucket) Proto() *iotv1.MetricBucket {
+	return &iotv1.MetricBucket{
+		Start: timestamppb.New(b.Start),
+		Count: b.Count,
+		Min:   b.Min,
+		Max:   b.Max,
+		Avg:   b.Avg,
+		First: b.First,
+		Last:  b.Last,
+	}
+}
+
+// fillEmptyBuckets returns the buckets with an empty bucket for each bucket
+// without values between the buckets containing start and end.
+func fillEmptyBuckets(buckets []MetricBucket, start time.Time, end time.Time, width time.Duration) []MetricBucket {
+	first, last := bucketStart(start, width), bucketStart(end, width)
+	filled := make([]MetricBucket, 0, int(last.Sub(first)/width)+1)
+	i := 0
+	for t := first; !t.After(last); t = t.Add(width) {
+		if i < len(buckets) && buckets[i].Start.Equal(t) {
+			filled = append(filled, buckets[i])
+			i++
+			continue
+		}
+		filled = append(filled, MetricBucket{Start: t})
+	}
+	return filled
+}
@@ -0,0 +1,112 @@
+package device
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+// alertSubscriptionBufferSize is the number of alerts buffered for each
+// subscription before it is considered too slow and closed.
+const alertSubscriptionBufferSize = 256
+
+var (
+	errAlertSubscriptionLagged = errors.New("alert subscription fell behind")
+	errAlertBrokerClosed       = errors.New("alert broker closed")
+)
+
+// alertBroker fans out saved alerts to subscribers within the process.
+type alertBroker struct {
+	mu     sync.Mutex
+	subs   map[*alertSubscription]struct{}
+	closed bool
+}
+
+func newAlertBroker() *alertBroker {
+	return &alertBroker{
+		subs: make(map[*alertSubscription]struct{}),
+	}
+}
+
+// alertSubscription receives published alerts matching its filter. The alerts
+// channel is closed when the subscription ends, after which err reports why.
+type alertSubscription struct {
+	deviceID string
+	reasons  []AlertReason
+	alerts   chan Alert
+	err      error
+}
+
+func (s *alertSubscription) matches(alert Alert) bool {
+	if s.deviceID != "" && s.deviceID != alert.DeviceID {
+		return false
+	}
+	return len(s.reasons) == 0 || slices.Contains(s.reasons, alert.Reason)
+}
+
+// subscribe registers a subscription for alerts of the given device, or all
+// devices if deviceID is empty, optionally filtered by reason.
+func (b *alertBroker) subscribe(deviceID string, reasons []AlertReason) *alertSubscription {
+	sub := &alertSubscription{
+		deviceID: deviceID,
+		reasons:  reasons,
+		alerts:   make(chan Alert, alertSubscriptionBufferSize),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		sub.err = errAlertBrokerClosed
+		close(sub.alerts)
+		return sub
+	}
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// unsubscribe removes a subscription and closes its alerts channel.
+func (b *alertBroker) unsubscribe(sub *alertSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub, nil)
+}
+
+// publish sends an alert to all matching subscriptions. Subscriptions that are
+// not keeping up are closed rather than blocking the publisher.
+func (b *alertBroker) publish(alert Alert) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		if !sub.matches(alert) {
+			continue
+		}
+		select {
+		case sub.alerts <- alert:
+		default:
+			b.remove(sub, errAlertSubscriptionLagged)
+		}
+	}
+}
+
+// close ends all subscriptions and rejects new ones.
+func (b *alertBroker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subs {
+		b.remove(sub, errAlertBrokerClosed)
+	}
+}
+
+// remove must be called with the lock held.
+func (b *alertBroker) remove(sub *alertSubscription, err error) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	sub.err = err
+	close(sub.alerts)
+}
@@ -0,0 +1,239 @@
+package device
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	iotv1 "github.com/joshjon/iot-metrics/proto/gen/iot/v1"
+)
+
+// AlertRule triggers an alert when a named metric value compares against a
+// threshold using an operator. Range operators compare against the inclusive
+// range from Threshold to ThresholdHigh. Rate of change operators compare the
+// change in value over the last WindowSeconds against Threshold.
+type AlertRule struct {
+	ID            int64
+	Metric        string
+	Operator      RuleOperator
+	Threshold     float64
+	ThresholdHigh float64
+	Severity      AlertSeverity
+	WindowSeconds int64
+}
+
+func (r AlertRule) Proto() *iotv1.AlertRule {
+	return &iotv1.AlertRule{
+		Id:            r.ID,
+		Metric:        r.Metric,
+		Operator:      r.Operator.Proto(),
+		Threshold:     r.Threshold,
+		ThresholdHigh: r.ThresholdHigh,
+		Severity:      r.Severity.Proto(),
+		WindowSeconds: r.WindowSeconds,
+	}
+}
+
+// Window returns the window over which rate of change rules measure the change
+// in value.
+func (r AlertRule) Window() time.Duration {
+	return time.Duration(r.WindowSeconds) * time.Second
+}
+
+// Breached reports whether a metric value breaches the rule. For rate of
+// change rules, the value is the change measured over the rule's window.
+func (r AlertRule) Breached(value float64) bool {
+	switch r.Operator {
+	case RuleOperatorGreaterThan:
+		return value > r.Threshold
+	case RuleOperatorGreaterThanOrEqual:
+		return value >= r.Threshold
+	case RuleOperatorLessThan:
+		return value < r.Threshold
+	case RuleOperatorLessThanOrEqual:
+		return value <= r.Threshold
+	case RuleOperatorEqual:
+		return value == r.Threshold
+	case RuleOperatorOutsideRange:
+		return value < r.Threshold || value > r.ThresholdHigh
+	case RuleOperatorInsideRange:
+		return value >= r.Threshold && value <= r.ThresholdHigh
+	case RuleOperatorRise, RuleOperatorDrop:
+		return value > r.Threshold
+	}
+	return false
+}
+
+// Cleared reports whether a metric value has returned within the rule by more
+// than the hysteresis margin, e.g. a GREATER_THAN rule with a threshold of 50
+// and hysteresis of 2 clears once the value is 48 or below.
+func (r AlertRule) Cleared(value float64, hysteresis float64) bool {
+	switch r.Operator {
+	case RuleOperatorGreaterThan:
+		return value <= r.Threshold-hysteresis
+	case RuleOperatorGreaterThanOrEqual:
+		return value < r.Threshold-hysteresis
+	case RuleOperatorLessThan:
+		return value >= r.Threshold+hysteresis
+	case RuleOperatorLessThanOrEqual:
+		return value > r.Threshold+hysteresis
+	case RuleOperatorEqual:
+		return math.Abs(value-r.Threshold) > hysteresis
+	case RuleOperatorOutsideRange:
+		return value >= r.Threshold+hysteresis && value <= r.ThresholdHigh-hysteresis
+	case RuleOperatorInsideRange:
+		return value < r.Threshold-hysteresis || value > r.ThresholdHigh+hysteresis
+	case RuleOperatorRise, RuleOperatorDrop:
+		return value <= r.Threshold-hysteresis
+	}
+	return true
+}
+
+// Reason derives the reason of alerts triggered by the rule. Rules matching
+// the original fixed temperature and battery checks keep their dedicated
+// reasons so that existing consumers can continue to filter on them.
+func (r AlertRule) Reason() AlertReason {
+	switch {
+	case r.Operator == RuleOperatorRise:
+		return AlertReasonRapidRise
+	case r.Operator == RuleOperatorDrop:
+		return AlertReasonRapidDrop
+	case r.Metric == MetricTemperature &&
+		(r.Operator == RuleOperatorGreaterThan || r.Operator == RuleOperatorGreaterThanOrEqual):
+		return AlertReasonTemperatureHigh
+	case r.Metric == MetricBattery &&
+		(r.Operator == RuleOperatorLessThan || r.Operator == RuleOperatorLessThanOrEqual):
+		return AlertReasonBatteryLow
+	}
+	return AlertReasonThresholdBreached
+}
+
+// Desc describes a breach of the rule by a metric value, or by the change in
+// value for rate of change rules.
+func (r AlertRule) Desc(value float64) string {
+	name := strings.ToUpper(r.Metric[:1]) + r.Metric[1:]
+	switch r.Operator {
+	case RuleOperatorGreaterThan:
+		return fmt.Sprintf("%s (%g) exceeded configured threshold (%g)", name, value, r.Threshold)
+	case RuleOperatorGreaterThanOrEqual:
+		return fmt.Sprintf("%s (%g) reached configured threshold (%g)", name, value, r.Threshold)
+	case RuleOperatorLessThan:
+		return fmt.Sprintf("%s (%g) dropped below configured threshold (%g)", name, value, r.Threshold)
+	case RuleOperatorLessThanOrEqual:
+		return fmt.Sprintf("%s (%g) dropped to configured threshold (%g)", name, value, r.Threshold)
+	case RuleOperatorEqual:
+		return fmt.Sprintf("%s (%g) equals configured threshold (%g)", name, value, r.Threshold)
+	case RuleOperatorOutsideRange:
+		return fmt.Sprintf("%s (%g) is outside configured range (%g to %g)", name, value, r.Threshold, r.ThresholdHigh)
+	case RuleOperatorInsideRange:
+		return fmt.Sprintf("%s (%g) is inside configured range (%g to %g)", name, value, r.Threshold, r.ThresholdHigh)
+	case RuleOperatorRise:
+		return fmt.Sprintf("%s rose by %g within %s, exceeding configured threshold (%g)", name, value, r.Window(), r.Threshold)
+	case RuleOperatorDrop:
+		return fmt.Sprintf("%s dropped by %g within %s, exceeding configured threshold (%g)", name, value, r.Window(), r.Threshold)
+	}
+	return fmt.Sprintf("%s (%g) breached alert rule", name, value)
+}
+
+const (
+	RuleOperatorGreaterThan        RuleOperator = "GREATER_THAN"
+	RuleOperatorGreaterThanOrEqual RuleOperator = "GREATER_THAN_OR_EQUAL"
+	RuleOperatorLessThan           RuleOperator = "LESS_THAN"
+	RuleOperatorLessThanOrEqual    RuleOperator = "LESS_THAN_OR_EQUAL"
+	RuleOperatorEqual              RuleOperator = "EQUAL"
+	RuleOperatorOutsideRange       RuleOperator = "OUTSIDE_RANGE"
+	RuleOperatorInsideRange        RuleOperator = "INSIDE_RANGE"
+	RuleOperatorRise               RuleOperator = "RISE"
+	RuleOperatorDrop               RuleOperator = "DROP"
+)
+
+type RuleOperator string
+
+func (o RuleOperator) Proto() iotv1.AlertRule_Operator {
+	switch o {
+	case RuleOperatorGreaterThan:
+		return iotv1.AlertRule_OPERATOR_GREATER_THAN
+	case RuleOperatorGreaterThanOrEqual:
+		return iotv1.AlertRule_OPERATOR_GREATER_THAN_OR_EQUAL
+	case RuleOperatorLessThan:
+		return iotv1.AlertRule_OPERATOR_LESS_THAN
+	case RuleOperatorLessThanOrEqual:
+		return iotv1.AlertRule_OPERATOR_LESS_THAN_OR_EQUAL
+	case RuleOperatorEqual:
+		return iotv1.AlertRule_OPERATOR_EQUAL
+	case RuleOperatorOutsideRange:
+		return iotv1.AlertRule_OPERATOR_OUTSIDE_RANGE
+	case RuleOperatorInsideRange:
+		return iotv1.AlertRule_OPERATOR_INSIDE_RANGE
+	case RuleOperatorRise:
+		return iotv1.AlertRule_OPERATOR_RISE
+	case RuleOperatorDrop:
+		return iotv1.AlertRule_OPERATOR_DROP
+	}
+	return iotv1.AlertRule_OPERATOR_UNSPECIFIED
+}
+
+func (o RuleOperator) isRange() bool {
+	return o == RuleOperatorOutsideRange || o == RuleOperatorInsideRange
+}
+
+func (o RuleOperator) isRateOfChange() bool {
+	return o == RuleOperatorRise || o == RuleOperatorDrop
+}
+
+func ruleOperatorFromProto(o iotv1.AlertRule_Operator) (RuleOperator, bool) {
+	switch o {
+	case iotv1.AlertRule_OPERATOR_GREATER_THAN:
+		return RuleOperatorGreaterThan, true
+	case iotv1.AlertRule_OPERATOR_GREATER_THAN_OR_EQUAL:
+		return RuleOperatorGreaterThanOrEqual, true
+	case iotv1.AlertRule_OPERATOR_LESS_THAN:
+		return RuleOperatorLessThan, true
+	case iotv1.AlertRule_OPERATOR_LESS_THAN_OR_EQUAL:
+		return RuleOperatorLessThanOrEqual, true
+	case iotv1.AlertRule_OPERATOR_EQUAL:
+		return RuleOperatorEqual, true
+	case iotv1.AlertRule_OPERATOR_OUTSIDE_RANGE:
+		return RuleOperatorOutsideRange, true
+	case iotv1.AlertRule_OPERATOR_INSIDE_RANGE:
+		return RuleOperatorInsideRange, true
+	case iotv1.AlertRule_OPERATOR_RISE:
+		return RuleOperatorRise, true
+	case iotv1.AlertRule_OPERATOR_DROP:
+		return RuleOperatorDrop, true
+	}
+	return "", false
+}
+
+const (
+	AlertSeverityInfo     AlertSeverity = "INFO"
+	AlertSeverityWarning  AlertSeverity = "WARNING"
+	AlertSeverityCritical AlertSeverity = "CRITICAL"
+)
+
+type AlertSeverity string
+
+func (s AlertSeverity) Proto() iotv1.Severity {
+	switch s {
+	case AlertSeverityInfo:
+		return iotv1.Severity_SEVERITY_INFO
+	case AlertSeverityWarning:
+		return iotv1.Severity_SEVERITY_WARNING
+	case AlertSeverityCritical:
+		return iotv1.Severity_SEVERITY_CRITICAL
+	}
+	return iotv1.Severity_SEVERITY_UNSPECIFIED
+}
+
+func alertSeverityFromProto(s iotv1.Severity) (AlertSeverity, bool) {
+	switch s {
+	case iotv1.Severity_SEVERITY_INFO:
+		return AlertSeverityInfo, true
+	case iotv1.Severity_SEVERITY_WARNING:
+		return AlertSeverityWarning, true
+	case iotv1.Severity_SEVERITY_CRITICAL:
+		return AlertSeverityCritical, true
+	}
+	return "", false
+}
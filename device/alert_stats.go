@@ -0,0 +1,77 @@
+package device
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	iotv1 "github.com/joshjon/iot-metrics/proto/gen/iot/v1"
+)
+
+// AlertStats are the counts of the alerts triggered within a timeframe.
+type AlertStats struct {
+	Total int64
+	// ByReason are the counts of each reason that alerts were triggered for,
+	// ordered by reason.
+	ByReason []ReasonAlertCount
+	// ByDevice are the counts of each device that triggered alerts, ordered by
+	// device ID.
+	ByDevice []DeviceAlertCount
+	// Buckets are the counts of consecutive buckets, in chronological order.
+	Buckets []AlertCountBucket
+	// TopDevices are the devices that triggered the most alerts, most first.
+	TopDevices []DeviceAlertCount
+}
+
+type ReasonAlertCount struct {
+	Reason AlertReason
+	Count  int64
+}
+
+func (c ReasonAlertCount) Proto() *iotv1.ReasonAlertCount {
+	return &iotv1.ReasonAlertCount{
+		Reason: c.Reason.Proto(),
+		Count:  c.Count,
+	}
+}
+
+type DeviceAlertCount struct {
+	DeviceID string
+	Count    int64
+}
+
+func (c DeviceAlertCount) Proto() *iotv1.DeviceAlertCount {
+	return &iotv1.DeviceAlertCount{
+		DeviceId: c.DeviceID,
+		Count:    c.Count,
+	}
+}
+
+type AlertCountBucket struct {
+	Start time.Time
+	Count int64
+}
+
+func (b AlertCountBucket) Proto() *iotv1.AlertCountBucket {
+	return &iotv1.AlertCountBucket{
+		Start: timestamppb.New(b.Start),
+		Count: b.Count,
+	}
+}
+
+// fillEmptyAlertBuckets returns the buckets with a bucket with a count of 0 for
+// each bucket without alerts between the buckets containing start and end.
+func fillEmptyAlertBuckets(buckets []AlertCountBucket, start time.Time, end time.Time, width time.Duration) []AlertCountBucket {
+	first, last := bucketStart(start, width), bucketStart(end, width)
+	filled := make([]AlertCountBucket, 0, int(last.Sub(first)/width)+1)
+	i := 0
+	for t := first; !t.After(last); t = t.Add(width) {
+		if i < len(buckets) && buckets[i].Start.Equal(t) {
+			filled = append(filled, buckets[i])
+			i++
+			continue
+		}
+		filled = append(filled, AlertCountBucket{Start: t})
+	}
+	return filled
+}
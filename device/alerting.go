@@ -0,0 +1,362 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/joshjon/iot-metrics/log"
+)
+
+// deviceAlerting holds the conditions that metrics of a device are evaluated
+// against, along with the debouncing state of each condition.
+type deviceAlerting struct {
+	cfg         Config
+	rules       []AlertRule
+	expressions []*compiledExpression
+	// states is keyed by condition and only used if the config debounces.
+	states map[string]ConditionState
+}
+
+// usesPrev reports whether any expression references the previous reading.
+func (a *deviceAlerting) usesPrev() bool {
+	return slices.ContainsFunc(a.expressions, func(e *compiledExpression) bool {
+		return e.usesPrev
+	})
+}
+
+// debounce records whether a reading breached or cleared a condition and
+// reports whether an alert should be triggered. Without debouncing, every
+// breaching reading triggers an alert. Otherwise, an alert is triggered once
+// the configured number of consecutive readings breach the condition, and not
+// again until a reading clears it.
+func (a *deviceAlerting) debounce(condition string, breached bool, cleared bool) bool {
+	if !a.cfg.Debounces() {
+		return breached
+	}
+
+	state := a.states[condition]
+	defer func() {
+		a.states[condition] = state
+	}()
+
+	if state.Active {
+		if cleared {
+			state = ConditionState{}
+		}
+		return false
+	}
+	if !breached {
+		state.Breaches = 0
+		return false
+	}
+	state.Breaches++
+	if state.Breaches >= max(a.cfg.ConsecutiveBreaches, 1) {
+		state.Active = true
+		return true
+	}
+	return false
+}
+
+// deviceAlerting returns the conditions that metrics of a device are evaluated
+// against: the rules derived from the device config thresholds, if configured,
+// followed by the alert rules created for the device, and the config
+// expressions.
+func (s *Service) deviceAlerting(ctx context.Context, deviceID string) (*deviceAlerting, error) {
+	alerting := &deviceAlerting{}
+
+	cfg, err := s.repo.GetDeviceConfig(ctx, deviceID)
+	if err == nil {
+		alerting.cfg = cfg
+		alerting.rules = cfg.Rules()
+	} else if !errors.Is(err, ErrRepoItemNotFound) {
+		return nil, fmt.Errorf("get device config: %w", err)
+	}
+
+	created, err := s.repo.ListAlertRules(ctx, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("list alert rules: %w", err)
+	}
+	alerting.rules = append(alerting.rules, created...)
+
+	for _, expr := range cfg.Expressions {
+		compiled, err := s.exprs.compile(expr)
+		if err != nil {
+			// expressions are validated when configured, so this is not expected
+			s.logger.Warn("skipping invalid alert expression", "device_id", deviceID, "expr", expr.Expr, "error", err)
+			continue
+		}
+		alerting.expressions = append(alerting.expressions, compiled)
+	}
+
+	if cfg.Debounces() {
+		if alerting.states, err = s.repo.GetConditionStates(ctx, deviceID); err != nil {
+			return nil, fmt.Errorf("get alert states: %w", err)
+		}
+	}
+
+	return alerting, nil
+}
+
+// saveConditionStates persists the debouncing state of a device's conditions so
+// that it survives a restart.
+func (s *Service) saveConditionStates(ctx context.Context, deviceID string, alerting *deviceAlerting) error {
+	if !alerting.cfg.Debounces() || len(alerting.states) == 0 {
+		return nil
+	}
+	if err := s.repo.SaveConditionStates(ctx, deviceID, alerting.states); err != nil {
+		return fmt.Errorf("save alert states: %w", err)
+	}
+	return nil
+}
+
+// change returns the change in value of a rate of change rule's metric at the
+// time of a reading: the rise from the lowest, or the drop from the highest,
+// value recorded within the rule's window before the reading. It reports false
+// if no values were recorded within the window.
+func (s *Service) change(ctx context.Context, deviceID string, rule AlertRule, metric Metric, value float64) (float64, bool, error) {
+	r, err := s.repo.GetMetricRange(ctx, deviceID, rule.Metric, metric.Time.Add(-rule.Window()), metric.Time)
+	if err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("get %s range: %w", rule.Metric, err)
+	}
+	if rule.Operator == RuleOperatorDrop {
+		return r.High - value, true, nil
+	}
+	return value - r.Low, true, nil
+}
+
+// latestMetric returns the most recent metric recorded for a device, or nil if
+// none have been recorded.
+func (s *Service) latestMetric(ctx context.Context, deviceID string) (*Metric, error) {
+	page, err := s.repo.GetDeviceMetrics(ctx, deviceID, Timeframe{}, RepositoryPageOptions{Size: 1})
+	if err != nil {
+		return nil, fmt.Errorf("get latest device metric: %w", err)
+	}
+	if len(page.Items) == 0 {
+		return nil, nil
+	}
+	return &page.Items[0], nil
+}
+
+// evaluateMetric checks a recorded metric against the alert rules and
+// expressions of a device and saves an alert for each breached rule and
+// matched expression, subject to debouncing. Unresolved alerts of rules and
+// expressions that have cleared are resolved. Rules for metrics missing from
+// the reading, and rate of change rules without history within their window,
+// are skipped.
+func (s *Service) evaluateMetric(
+	ctx context.Context,
+	logger log.Logger,
+	deviceID string,
+	alerting *deviceAlerting,
+	metric Metric,
+	prev *Metric,
+) error {
+	var clearedConditions []string
+
+	for _, rule := range alerting.rules {
+		value, ok := metric.Value(rule.Metric)
+		if !ok {
+			continue
+		}
+		if rule.Operator.isRateOfChange() {
+			change, ok, err := s.change(ctx, deviceID, rule, metric, value)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			value = change
+		}
+		condition := ruleCondition(rule)
+		breached := rule.Breached(value)
+		cleared := rule.Cleared(value, alerting.cfg.Hysteresis)
+		if cleared {
+			clearedConditions = append(clearedConditions, condition)
+		}
+		if !alerting.debounce(condition, breached, cleared) {
+			continue
+		}
+		alert := Alert{
+			RuleID:    rule.ID,
+			Reason:    rule.Reason(),
+			Severity:  rule.Severity,
+			Desc:      rule.Desc(value),
+			Time:      metric.Time,
+			Condition: condition,
+		}
+		logger.Info("alert triggered",
+			"reason", alert.Reason,
+			"severity", alert.Severity,
+			"rule_id", rule.ID,
+			"metric", rule.Metric,
+			"value", value,
+			"operator", rule.Operator,
+			"threshold", rule.Threshold,
+		)
+		if err := s.raiseAlert(ctx, logger, deviceID, alerting, alert); err != nil {
+			return fmt.Errorf("save %s alert: %w", rule.Metric, err)
+		}
+	}
+
+	for _, expr := range alerting.expressions {
+		matched, err := expr.eval(metric, prev)
+		if err != nil {
+			logger.Warn("alert expression evaluation failed", "expr", expr.Expr, "error", err)
+			continue
+		}
+		condition := expressionCondition(expr.AlertExpression)
+		if !matched {
+			clearedConditions = append(clearedConditions, condition)
+		}
+		if !alerting.debounce(condition, matched, !matched) {
+			continue
+		}
+		alert := Alert{
+			Reason:    AlertReasonExpressionMatched,
+			Severity:  expr.Severity,
+			Desc:      expr.Desc(),
+			Time:      metric.Time,
+			Condition: condition,
+		}
+		logger.Info("alert triggered",
+			"reason", alert.Reason,
+			"severity", alert.Severity,
+			"expr", expr.Expr,
+		)
+		if err = s.raiseAlert(ctx, logger, deviceID, alerting, alert); err != nil {
+			return fmt.Errorf("save expression alert: %w", err)
+		}
+	}
+
+	if alerting.cfg.ReportingInterval > 0 {
+		// any reading brings an offline device back online
+		clearedConditions = append(clearedConditions, offlineCondition)
+	}
+
+	if len(clearedConditions) > 0 {
+		resolved, err := s.repo.ResolveDeviceAlertsByCondition(ctx, deviceID, clearedConditions, metric.Time)
+		if err != nil {
+			return fmt.Errorf("resolve cleared alerts: %w", err)
+		}
+		if resolved > 0 {
+			logger.Info("resolved cleared alerts", "count", resolved)
+		}
+	}
+
+	return nil
+}
+
+// raiseAlert saves a triggered alert as a new open alert and publishes it to
+// any active alert watches, unless it duplicates the latest alert of its
+// condition. If the latest alert is unresolved, the trigger is recorded as an
+// occurrence of it instead. If the latest alert was resolved within the
+// cooldown window of the alert's reason, the trigger is dropped, leaving the
+// resolved alert as it was.
+func (s *Service) raiseAlert(
+	ctx context.Context,
+	logger log.Logger,
+	deviceID string,
+	alerting *deviceAlerting,
+	alert Alert,
+) error {
+	latest, err := s.repo.GetLatestConditionAlert(ctx, deviceID, alert.Condition)
+	if err != nil && !errors.Is(err, ErrRepoItemNotFound) {
+		return fmt.Errorf("get latest alert: %w", err)
+	}
+	if err == nil {
+		if latest.State != AlertStateResolved {
+			if err = s.repo.RecordAlertOccurrence(ctx, deviceID, latest.ID, alert.Time); err != nil {
+				return fmt.Errorf("record alert occurrence: %w", err)
+			}
+			logger.Info("recorded alert occurrence", "alert_id", latest.ID)
+			return nil
+		}
+		resolvedAt := latest.LastSeen
+		if latest.ResolvedAt != nil {
+			resolvedAt = *latest.ResolvedAt
+		}
+		if alert.Time.Before(resolvedAt.Add(alerting.cfg.Cooldown(alert.Reason))) {
+			logger.Info("alert suppressed by cooldown", "alert_id", latest.ID, "resolved_at", resolvedAt)
+			return nil
+		}
+	}
+
+	alert.State = AlertStateOpen
+	alert.Occurrences = 1
+	alert.LastSeen = alert.Time
+	alert.ConfigVersion = alerting.cfg.Version
+	id, err := s.repo.SaveDeviceAlert(ctx, deviceID, alert)
+	if err != nil {
+		return err
+	}
+	alert.ID = id
+	alert.DeviceID = deviceID
+	s.alerts.publish(alert)
+	return nil
+}
+
+// deviceLocks serializes the evaluation of alerts per device, so that
+// concurrent readings of a device do not overwrite each other's debouncing
+// state or raise duplicate alerts.
+type deviceLocks struct {
+	mu    sync.Mutex
+	locks map[string]*deviceLock
+}
+
+type deviceLock struct {
+	mu sync.Mutex
+	// refs is the number of callers holding or waiting for the lock.
+	refs int
+}
+
+func newDeviceLocks() *deviceLocks {
+	return &deviceLocks{
+		locks: make(map[string]*deviceLock),
+	}
+}
+
+// lock locks the device and returns a function to unlock it. Locks are removed
+// once no callers hold or wait for them.
+func (l *deviceLocks) lock(deviceID string) (unlock func()) {
+	l.mu.Lock()
+	dl, ok := l.locks[deviceID]
+	if !ok {
+		dl = &deviceLock{}
+		l.locks[deviceID] = dl
+	}
+	dl.refs++
+	l.mu.Unlock()
+
+	dl.mu.Lock()
+	return func() {
+		dl.mu.Unlock()
+		l.mu.Lock()
+		if dl.refs--; dl.refs == 0 {
+			delete(l.locks, deviceID)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// ruleCondition identifies the condition of an alert rule when tracking its
+// state. Rules derived from the config thresholds are identified by metric.
+func ruleCondition(rule AlertRule) string {
+	if rule.ID == 0 {
+		return "config:" + rule.Metric
+	}
+	return "rule:" + strconv.FormatInt(rule.ID, 10)
+}
+
+// expressionCondition identifies the condition of an alert expression when
+// tracking its state.
+func expressionCondition(expr AlertExpression) string {
+	return "expr:" + expr.Expr
+}
@@ -0,0 +1,350 @@
+package device
+
+import (
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	iotv1 "github.com/joshjon/iot-metrics/proto/gen/iot/v1"
+)
+
+const (
+	// ConfigLevelDevice is the config set for a single device, which overrides
+	// the config of its group and the global defaults.
+	ConfigLevelDevice ConfigLevel = "DEVICE"
+	// ConfigLevelGroup is the config set for a device group, which overrides
+	// the global defaults for the devices of the group.
+	ConfigLevelGroup ConfigLevel = "GROUP"
+	// ConfigLevelGlobal is the default config of all devices.
+	ConfigLevelGlobal ConfigLevel = "GLOBAL"
+)
+
+// ConfigLevel is a level that config settings are set at. The effective config
+// of a device resolves each setting from the device, then its group, then the
+// global defaults.
+type ConfigLevel string
+
+func (l ConfigLevel) Proto() iotv1.ConfigLevel {
+	switch l {
+	case ConfigLevelDevice:
+		return iotv1.ConfigLevel_CONFIG_LEVEL_DEVICE
+	case ConfigLevelGroup:
+		return iotv1.ConfigLevel_CONFIG_LEVEL_GROUP
+	case ConfigLevelGlobal:
+		return iotv1.ConfigLevel_CONFIG_LEVEL_GLOBAL
+	}
+	return iotv1.ConfigLevel_CONFIG_LEVEL_UNSPECIFIED
+}
+
+const (
+	SettingTemperatureThreshold ConfigSetting = "temperature_threshold"
+	SettingBatteryThreshold     ConfigSetting = "battery_threshold"
+	SettingExpressions          ConfigSetting = "expressions"
+	SettingConsecutiveBreaches  ConfigSetting = "consecutive_breaches"
+	SettingHysteresis           ConfigSetting = "hysteresis"
+	SettingCooldowns            ConfigSetting = "cooldowns"
+	SettingReportingInterval    ConfigSetting = "reporting_interval_seconds"
+)
+
+// ConfigSetting is the name of a config setting, matching its request field.
+type ConfigSetting string
+
+// ConfigSettings holds the config settings set at a single level. Settings
+// that are nil are not set at the level and are inherited from the next level.
+// Lists that are empty but not nil are set, overriding the next level.
+type ConfigSettings struct {
+	TemperatureThreshold *float64
+	BatteryThreshold     *int32
+	Expressions          []AlertExpression
+	ConsecutiveBreaches  *int32
+	Hysteresis           *float64
+	Cooldowns            []AlertCooldown
+	ReportingInterval    *time.Duration
+}
+
+// Names returns the names of the settings that are set.
+func (s ConfigSettings) Names() []ConfigSetting {
+	var names []ConfigSetting
+	for name, set := range map[ConfigSetting]bool{
+		SettingTemperatureThreshold: s.TemperatureThreshold != nil,
+		SettingBatteryThreshold:     s.BatteryThreshold != nil,
+		SettingExpressions:          s.Expressions != nil,
+		SettingConsecutiveBreaches:  s.ConsecutiveBreaches != nil,
+		SettingHysteresis:           s.Hysteresis != nil,
+		SettingCooldowns:            s.Cooldowns != nil,
+		SettingReportingInterval:    s.ReportingInterval != nil,
+	} {
+		if set {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// configSettings are all config settings, in the order of their request fields.
+var configSettings = []ConfigSetting{
+	SettingTemperatureThreshold,
+	SettingBatteryThreshold,
+	SettingExpressions,
+	SettingConsecutiveBreaches,
+	SettingHysteresis,
+	SettingCooldowns,
+	SettingReportingInterval,
+}
+
+// merge returns the settings with each setting in the mask replaced by the
+// setting of patch. Settings in the mask that are unset in patch are cleared.
+func (s ConfigSettings) merge(patch ConfigSettings, mask []ConfigSetting) ConfigSettings {
+	for _, setting := range mask {
+		switch setting {
+		case SettingTemperatureThreshold:
+			s.TemperatureThreshold = patch.TemperatureThreshold
+		case SettingBatteryThreshold:
+			s.BatteryThreshold = patch.BatteryThreshold
+		case SettingExpressions:
+			s.Expressions = patch.Expressions
+		case SettingConsecutiveBreaches:
+			s.ConsecutiveBreaches = patch.ConsecutiveBreaches
+		case SettingHysteresis:
+			s.Hysteresis = patch.Hysteresis
+		case SettingCooldowns:
+			s.Cooldowns = patch.Cooldowns
+		case SettingReportingInterval:
+			s.ReportingInterval = patch.ReportingInterval
+		}
+	}
+	return s
+}
+
+// DeviceConfigVersion is an immutable version of the settings set for a
+// device. A new version is created each time the device is configured.
+type DeviceConfigVersion struct {
+	Version   int64
+	Settings  ConfigSettings
+	CreatedAt time.Time
+	// Author is the operator that made the change, if provided.
+	Author string
+	// Reason is why the change was made, if provided.
+	Reason string
+}
+
+func (v DeviceConfigVersion) Proto() *iotv1.DeviceConfigVersion {
+	return &iotv1.DeviceConfigVersion{
+		Version:   v.Version,
+		Settings:  v.Settings.Proto(),
+		CreatedAt: timestamppb.New(v.CreatedAt),
+		Author:    v.Author,
+		Reason:    v.Reason,
+	}
+}
+
+// configLevels are the config levels in order of precedence.
+var configLevels = []ConfigLevel{ConfigLevelDevice, ConfigLevelGroup, ConfigLevelGlobal}
+
+// ResolveConfig resolves a config from the settings set at each level, taking
+// each setting from the level with the highest precedence that sets it. Levels
+// without settings may be omitted.
+func ResolveConfig(levels map[ConfigLevel]ConfigSettings) Config {
+	cfg := Config{Sources: make(map[ConfigSetting]ConfigLevel)}
+	// apply the lowest precedence level first so that higher levels override it
+	for i := len(configLevels) - 1; i >= 0; i-- {
+		level := configLevels[i]
+		s, ok := levels[level]
+		if !ok {
+			continue
+		}
+		if s.TemperatureThreshold != nil {
+			cfg.TemperatureThreshold = *s.TemperatureThreshold
+			cfg.Sources[SettingTemperatureThreshold] = level
+		}
+		if s.BatteryThreshold != nil {
+			cfg.BatteryThreshold = *s.BatteryThreshold
+			cfg.Sources[SettingBatteryThreshold] = level
+		}
+		if s.Expressions != nil {
+			cfg.Expressions = s.Expressions
+			cfg.Sources[SettingExpressions] = level
+		}
+		if s.ConsecutiveBreaches != nil {
+			cfg.ConsecutiveBreaches = *s.ConsecutiveBreaches
+			cfg.Sources[SettingConsecutiveBreaches] = level
+		}
+		if s.Hysteresis != nil {
+			cfg.Hysteresis = *s.Hysteresis
+			cfg.Sources[SettingHysteresis] = level
+		}
+		if s.Cooldowns != nil {
+			cfg.Cooldowns = s.Cooldowns
+			cfg.Sources[SettingCooldowns] = level
+		}
+		if s.ReportingInterval != nil {
+			cfg.ReportingInterval = *s.ReportingInterval
+			cfg.Sources[SettingReportingInterval] = level
+		}
+	}
+	return cfg
+}
+
+// Settings returns the settings of the config that are set at any level.
+func (c Config) Settings() ConfigSettings {
+	var s ConfigSettings
+	if c.IsSet(SettingTemperatureThreshold) {
+		s.TemperatureThreshold = &c.TemperatureThreshold
+	}
+	if c.IsSet(SettingBatteryThreshold) {
+		s.BatteryThreshold = &c.BatteryThreshold
+	}
+	if c.IsSet(SettingExpressions) {
+		s.Expressions = c.Expressions
+	}
+	if c.IsSet(SettingConsecutiveBreaches) {
+		s.ConsecutiveBreaches = &c.ConsecutiveBreaches
+	}
+	if c.IsSet(SettingHysteresis) {
+		s.Hysteresis = &c.Hysteresis
+	}
+	if c.IsSet(SettingCooldowns) {
+		s.Cooldowns = c.Cooldowns
+	}
+	if c.IsSet(SettingReportingInterval) {
+		s.ReportingInterval = &c.ReportingInterval
+	}
+	return s
+}
+
+// IsSet reports whether the setting is set at any level.
+func (c Config) IsSet(setting ConfigSetting) bool {
+	_, ok := c.Sources[setting]
+	return ok
+}
+
+func (s ConfigSettings) Proto() *iotv1.ConfigSettings {
+	settingspb := &iotv1.ConfigSettings{
+		TemperatureThreshold: s.TemperatureThreshold,
+		BatteryThreshold:     s.BatteryThreshold,
+		ConsecutiveBreaches:  s.ConsecutiveBreaches,
+		Hysteresis:           s.Hysteresis,
+	}
+	if s.Expressions != nil {
+		settingspb.Expressions = make([]*iotv1.AlertExpression, len(s.Expressions))
+		for i, e := range s.Expressions {
+			settingspb.Expressions[i] = &iotv1.AlertExpression{
+				Expr:     e.Expr,
+				Severity: e.Severity.Proto(),
+			}
+		}
+	}
+	if s.Cooldowns != nil {
+		settingspb.Cooldowns = make([]*iotv1.AlertCooldown, len(s.Cooldowns))
+		for i, c := range s.Cooldowns {
+			settingspb.Cooldowns[i] = &iotv1.AlertCooldown{
+				Reason:        c.Reason.Proto(),
+				WindowSeconds: int64(c.Window / time.Second),
+			}
+		}
+	}
+	if s.ReportingInterval != nil {
+		settingspb.ReportingIntervalSeconds = ptr(int64(*s.ReportingInterval / time.Second))
+	}
+	return settingspb
+}
+
+// settings converts the body of a configure request to the settings of a
+// config level.
+func (b ConfigSettingsBody) settings() ConfigSettings {
+	s := ConfigSettings{
+		TemperatureThreshold: b.TemperatureThreshold,
+		BatteryThreshold:     b.BatteryThreshold,
+		ConsecutiveBreaches:  b.ConsecutiveBreaches,
+		Hysteresis:           b.Hysteresis,
+	}
+	if b.Expressions != nil {
+		s.Expressions = make([]AlertExpression, len(b.Expressions))
+		for i, e := range b.Expressions {
+			s.Expressions[i] = AlertExpression{Expr: e.Expr, Severity: e.Severity}
+		}
+	}
+	if b.Cooldowns != nil {
+		s.Cooldowns = make([]AlertCooldown, len(b.Cooldowns))
+		for i, c := range b.Cooldowns {
+			s.Cooldowns[i] = AlertCooldown{Reason: c.Reason, Window: time.Duration(c.WindowSeconds) * time.Second}
+		}
+	}
+	if b.ReportingIntervalSeconds != nil {
+		s.ReportingInterval = ptr(time.Duration(*b.ReportingIntervalSeconds) * time.Second)
+	}
+	return s
+}
+
+// masked returns the body with only the settings in the mask.
+func (b ConfigSettingsBody) masked(mask []ConfigSetting) ConfigSettingsBody {
+	var masked ConfigSettingsBody
+	for _, setting := range mask {
+		switch setting {
+		case SettingTemperatureThreshold:
+			masked.TemperatureThreshold = b.TemperatureThreshold
+		case SettingBatteryThreshold:
+			masked.BatteryThreshold = b.BatteryThreshold
+		case SettingExpressions:
+			masked.Expressions = b.Expressions
+		case SettingConsecutiveBreaches:
+			masked.ConsecutiveBreaches = b.ConsecutiveBreaches
+		case SettingHysteresis:
+			masked.Hysteresis = b.Hysteresis
+		case SettingCooldowns:
+			masked.Cooldowns = b.Cooldowns
+		case SettingReportingInterval:
+			masked.ReportingIntervalSeconds = b.ReportingIntervalSeconds
+		}
+	}
+	return masked
+}
+
+func newConfigSettingsBody(s ConfigSettings) ConfigSettingsBody {
+	b := ConfigSettingsBody{
+		TemperatureThreshold: s.TemperatureThreshold,
+		BatteryThreshold:     s.BatteryThreshold,
+		ConsecutiveBreaches:  s.ConsecutiveBreaches,
+		Hysteresis:           s.Hysteresis,
+	}
+	if s.Expressions != nil {
+		b.Expressions = make([]ConfigureDeviceExpression, len(s.Expressions))
+		for i, e := range s.Expressions {
+			b.Expressions[i] = ConfigureDeviceExpression{Expr: e.Expr, Severity: e.Severity}
+		}
+	}
+	if s.Cooldowns != nil {
+		b.Cooldowns = make([]ConfigureDeviceCooldown, len(s.Cooldowns))
+		for i, c := range s.Cooldowns {
+			b.Cooldowns[i] = ConfigureDeviceCooldown{Reason: c.Reason, WindowSeconds: int64(c.Window / time.Second)}
+		}
+	}
+	if s.ReportingInterval != nil {
+		b.ReportingIntervalSeconds = ptr(int64(*s.ReportingInterval / time.Second))
+	}
+	return b
+}
+
+func newDeviceConfigVersionBody(v DeviceConfigVersion) DeviceConfigVersionBody {
+	return DeviceConfigVersionBody{
+		Version:   v.Version,
+		Settings:  newConfigSettingsBody(v.Settings),
+		CreatedAt: v.CreatedAt,
+		Author:    v.Author,
+		Reason:    v.Reason,
+	}
+}
+
+// version converts the body of a config version response back to the config
+// version.
+func (b DeviceConfigVersionBody) version() DeviceConfigVersion {
+	return DeviceConfigVersion{
+		Version:   b.Version,
+		Settings:  b.Settings.settings(),
+		CreatedAt: b.CreatedAt,
+		Author:    b.Author,
+		Reason:    b.Reason,
+	}
+}
@@ -2,13 +2,22 @@ package device
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
 
 	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/joshjon/iot-metrics/proto/gen/iot/v1"
 	"github.com/joshjon/iot-metrics/proto/gen/iot/v1/iotv1connect"
 )
 
+// streamMetricsAckInterval is the number of metrics recorded on a stream
+// between acknowledgements.
+const streamMetricsAckInterval = 100
+
 var _ iotv1connect.DeviceServiceHandler = (*ConnectHandler)(nil)
 
 // ConnectHandler is a Connect/gRPC based handler for the IoT Device Metrics API.
@@ -26,14 +35,79 @@ func (s *ConnectHandler) ConfigureDevice(
 	ctx context.Context,
 	req *connect.Request[iotv1.ConfigureDeviceRequest],
 ) (*connect.Response[iotv1.ConfigureDeviceResponse], error) {
-	if err := s.svc.ConfigureDevice(ctx, ConfigureDeviceRequest{
-		DeviceID:             req.Msg.DeviceId,
-		TemperatureThreshold: req.Msg.TemperatureThreshold,
-		BatteryThreshold:     req.Msg.BatteryThreshold,
-	}); err != nil {
+	svcReq := ConfigureDeviceRequest{
+		DeviceID: req.Msg.DeviceId,
+		ConfigSettingsBody: configSettingsFromProto(&iotv1.ConfigSettings{
+			TemperatureThreshold:     req.Msg.TemperatureThreshold,
+			BatteryThreshold:         req.Msg.BatteryThreshold,
+			Expressions:              req.Msg.Expressions,
+			ConsecutiveBreaches:      req.Msg.ConsecutiveBreaches,
+			Hysteresis:               req.Msg.Hysteresis,
+			Cooldowns:                req.Msg.Cooldowns,
+			ReportingIntervalSeconds: req.Msg.ReportingIntervalSeconds,
+		}),
+		Author:           req.Msg.Author,
+		Reason:           req.Msg.Reason,
+		ExpectedRevision: req.Msg.ExpectedRevision,
+	}
+	revision, err := s.svc.ConfigureDevice(ctx, svcReq)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.ConfigureDeviceResponse{
+		Revision: revision,
+	}), nil
+}
+
+func (s *ConnectHandler) GetDeviceConfig(
+	ctx context.Context,
+	req *connect.Request[iotv1.GetDeviceConfigRequest],
+) (*connect.Response[iotv1.GetDeviceConfigResponse], error) {
+	cfg, err := s.svc.GetDeviceConfig(ctx, GetDeviceConfigRequest{
+		DeviceID: req.Msg.DeviceId,
+	})
+	if err != nil {
 		return nil, err
 	}
-	return &connect.Response[iotv1.ConfigureDeviceResponse]{}, nil
+	return connect.NewResponse(&iotv1.GetDeviceConfigResponse{
+		Config: cfg.version().Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) UpdateDeviceConfig(
+	ctx context.Context,
+	req *connect.Request[iotv1.UpdateDeviceConfigRequest],
+) (*connect.Response[iotv1.UpdateDeviceConfigResponse], error) {
+	svcReq := UpdateDeviceConfigRequest{
+		DeviceID:         req.Msg.DeviceId,
+		Settings:         configSettingsFromProto(req.Msg.Settings),
+		Author:           req.Msg.Author,
+		Reason:           req.Msg.Reason,
+		ExpectedRevision: req.Msg.ExpectedRevision,
+	}
+	for _, path := range req.Msg.GetUpdateMask().GetPaths() {
+		setting := ConfigSetting(path)
+		svcReq.UpdateMask = append(svcReq.UpdateMask, setting)
+		// an empty list in the mask overrides the inherited list rather than
+		// clearing the setting
+		switch setting {
+		case SettingExpressions:
+			if svcReq.Settings.Expressions == nil {
+				svcReq.Settings.Expressions = []ConfigureDeviceExpression{}
+			}
+		case SettingCooldowns:
+			if svcReq.Settings.Cooldowns == nil {
+				svcReq.Settings.Cooldowns = []ConfigureDeviceCooldown{}
+			}
+		}
+	}
+	cfg, err := s.svc.UpdateDeviceConfig(ctx, svcReq)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.UpdateDeviceConfigResponse{
+		Config: cfg.version().Proto(),
+	}), nil
 }
 
 func (s *ConnectHandler) RecordMetric(
@@ -44,6 +118,7 @@ func (s *ConnectHandler) RecordMetric(
 		DeviceID:    req.Msg.DeviceId,
 		Temperature: req.Msg.Temperature,
 		Battery:     req.Msg.Battery,
+		Values:      metricValuesFromProto(req.Msg.Values),
 	}
 	if req.Msg.Timestamp != nil {
 		svcReq.Timestamp = req.Msg.Timestamp.AsTime()
@@ -54,6 +129,91 @@ func (s *ConnectHandler) RecordMetric(
 	return &connect.Response[iotv1.RecordMetricResponse]{}, nil
 }
 
+func (s *ConnectHandler) RecordMetrics(
+	ctx context.Context,
+	req *connect.Request[iotv1.RecordMetricsRequest],
+) (*connect.Response[iotv1.RecordMetricsResponse], error) {
+	svcReq := RecordMetricsRequest{
+		DeviceID: req.Msg.DeviceId,
+		Metrics:  make([]RecordMetricsItem, len(req.Msg.Metrics)),
+	}
+	for i, m := range req.Msg.Metrics {
+		svcReq.Metrics[i] = RecordMetricsItem{
+			Temperature: m.Temperature,
+			Battery:     m.Battery,
+			Values:      metricValuesFromProto(m.Values),
+		}
+		if m.Timestamp != nil {
+			svcReq.Metrics[i].Timestamp = m.Timestamp.AsTime()
+		}
+	}
+	res, err := s.svc.RecordMetrics(ctx, svcReq)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.RecordMetricsResponse{
+		Recorded: int32(res.Recorded),
+	}), nil
+}
+
+func (s *ConnectHandler) StreamMetrics(
+	ctx context.Context,
+	stream *connect.BidiStream[iotv1.StreamMetricsRequest, iotv1.StreamMetricsResponse],
+) error {
+	var acknowledged, recorded int64
+	var lastTimestamp time.Time
+
+	ack := func() error {
+		acknowledged = recorded
+		return stream.Send(&iotv1.StreamMetricsResponse{
+			Acknowledged:  acknowledged,
+			LastTimestamp: timestamppb.New(lastTimestamp),
+		})
+	}
+
+	for {
+		msg, err := stream.Receive()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		svcReq := RecordMetricRequest{
+			DeviceID: msg.DeviceId,
+		}
+		if msg.Metric != nil {
+			svcReq.Temperature = msg.Metric.Temperature
+			svcReq.Battery = msg.Metric.Battery
+			svcReq.Values = metricValuesFromProto(msg.Metric.Values)
+			if msg.Metric.Timestamp != nil {
+				svcReq.Timestamp = msg.Metric.Timestamp.AsTime()
+			}
+		}
+		if err = s.svc.RecordMetric(ctx, svcReq); err != nil {
+			if recorded > acknowledged {
+				// let the client know which metrics were recorded before failing
+				_ = ack()
+			}
+			return err
+		}
+
+		recorded++
+		lastTimestamp = svcReq.Timestamp
+		if recorded-acknowledged >= streamMetricsAckInterval {
+			if err = ack(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if recorded > acknowledged {
+		return ack()
+	}
+	return nil
+}
+
 func (s *ConnectHandler) GetDeviceAlerts(
 	ctx context.Context,
 	req *connect.Request[iotv1.GetDeviceAlertsRequest],
@@ -63,6 +223,15 @@ func (s *ConnectHandler) GetDeviceAlerts(
 		PageSize:  int(req.Msg.PageSize),
 		PageToken: req.Msg.PageToken,
 	}
+	if req.Msg.State != iotv1.Alert_STATE_UNSPECIFIED {
+		svcReq.State = alertStateFromProtoOrName(req.Msg.State)
+	}
+	for _, r := range req.Msg.Reasons {
+		svcReq.Reasons = append(svcReq.Reasons, alertReasonFromProtoOrName(r))
+	}
+	if req.Msg.Order != iotv1.SortOrder_SORT_ORDER_UNSPECIFIED {
+		svcReq.Order = sortOrderFromProtoOrName(req.Msg.Order)
+	}
 	if req.Msg.Timeframe != nil {
 		if req.Msg.Timeframe.Start != nil {
 			svcReq.TimeframeStart = ptr(req.Msg.Timeframe.Start.AsTime().UTC())
@@ -85,3 +254,689 @@ func (s *ConnectHandler) GetDeviceAlerts(
 		NextPageToken: res.NextPageToken,
 	}), nil
 }
+
+func (s *ConnectHandler) SearchAlerts(
+	ctx context.Context,
+	req *connect.Request[iotv1.SearchAlertsRequest],
+) (*connect.Response[iotv1.SearchAlertsResponse], error) {
+	svcReq := SearchAlertsRequest{
+		DeviceIDs:     req.Msg.DeviceIds,
+		LabelSelector: req.Msg.LabelSelector,
+		PageSize:      int(req.Msg.PageSize),
+		PageToken:     req.Msg.PageToken,
+	}
+	for _, r := range req.Msg.Reasons {
+		svcReq.Reasons = append(svcReq.Reasons, alertReasonFromProtoOrName(r))
+	}
+	if req.Msg.Severity != iotv1.Severity_SEVERITY_UNSPECIFIED {
+		svcReq.Severity = alertSeverityFromProtoOrName(req.Msg.Severity)
+	}
+	if req.Msg.State != iotv1.Alert_STATE_UNSPECIFIED {
+		svcReq.State = alertStateFromProtoOrName(req.Msg.State)
+	}
+	if req.Msg.Timeframe != nil {
+		if req.Msg.Timeframe.Start != nil {
+			svcReq.TimeframeStart = ptr(req.Msg.Timeframe.Start.AsTime().UTC())
+		}
+		if req.Msg.Timeframe.End != nil {
+			svcReq.TimeframeEnd = ptr(req.Msg.Timeframe.End.AsTime().UTC())
+		}
+	}
+	res, err := s.svc.SearchAlerts(ctx, svcReq)
+	if err != nil {
+		return nil, err
+	}
+
+	alertspb := make([]*iotv1.Alert, len(res.Alerts))
+	for i, a := range res.Alerts {
+		alertspb[i] = a.Proto()
+	}
+	return connect.NewResponse(&iotv1.SearchAlertsResponse{
+		Alerts:        alertspb,
+		NextPageToken: res.NextPageToken,
+	}), nil
+}
+
+func (s *ConnectHandler) GetAlertStats(
+	ctx context.Context,
+	req *connect.Request[iotv1.GetAlertStatsRequest],
+) (*connect.Response[iotv1.GetAlertStatsResponse], error) {
+	svcReq := GetAlertStatsRequest{
+		BucketWidth: bucketWidthFromProtoOrName(req.Msg.BucketWidth),
+		TopN:        int(req.Msg.TopN),
+	}
+	if req.Msg.Timeframe != nil {
+		if req.Msg.Timeframe.Start != nil {
+			svcReq.TimeframeStart = ptr(req.Msg.Timeframe.Start.AsTime().UTC())
+		}
+		if req.Msg.Timeframe.End != nil {
+			svcReq.TimeframeEnd = ptr(req.Msg.Timeframe.End.AsTime().UTC())
+		}
+	}
+	res, err := s.svc.GetAlertStats(ctx, svcReq)
+	if err != nil {
+		return nil, err
+	}
+
+	respb := &iotv1.GetAlertStatsResponse{
+		Total:      res.Total,
+		ByReason:   make([]*iotv1.ReasonAlertCount, len(res.ByReason)),
+		ByDevice:   make([]*iotv1.DeviceAlertCount, len(res.ByDevice)),
+		Buckets:    make([]*iotv1.AlertCountBucket, len(res.Buckets)),
+		TopDevices: make([]*iotv1.DeviceAlertCount, len(res.TopDevices)),
+	}
+	for i, c := range res.ByReason {
+		respb.ByReason[i] = c.Proto()
+	}
+	for i, c := range res.ByDevice {
+		respb.ByDevice[i] = c.Proto()
+	}
+	for i, b := range res.Buckets {
+		respb.Buckets[i] = b.Proto()
+	}
+	for i, c := range res.TopDevices {
+		respb.TopDevices[i] = c.Proto()
+	}
+	return connect.NewResponse(respb), nil
+}
+
+func (s *ConnectHandler) GetDeviceMetrics(
+	ctx context.Context,
+	req *connect.Request[iotv1.GetDeviceMetricsRequest],
+) (*connect.Response[iotv1.GetDeviceMetricsResponse], error) {
+	svcReq := GetDeviceMetricsRequest{
+		DeviceID:  req.Msg.DeviceId,
+		PageSize:  int(req.Msg.PageSize),
+		PageToken: req.Msg.PageToken,
+	}
+	if req.Msg.Order != iotv1.SortOrder_SORT_ORDER_UNSPECIFIED {
+		svcReq.Order = sortOrderFromProtoOrName(req.Msg.Order)
+	}
+	if req.Msg.Timeframe != nil {
+		if req.Msg.Timeframe.Start != nil {
+			svcReq.TimeframeStart = ptr(req.Msg.Timeframe.Start.AsTime().UTC())
+		}
+		if req.Msg.Timeframe.End != nil {
+			svcReq.TimeframeEnd = ptr(req.Msg.Timeframe.End.AsTime().UTC())
+		}
+	}
+	res, err := s.svc.GetDeviceMetrics(ctx, svcReq)
+	if err != nil {
+		return nil, err
+	}
+
+	metricspb := make([]*iotv1.Metric, len(res.Metrics))
+	for i, m := range res.Metrics {
+		metricspb[i] = m.Proto()
+	}
+	return connect.NewResponse(&iotv1.GetDeviceMetricsResponse{
+		Metrics:       metricspb,
+		NextPageToken: res.NextPageToken,
+	}), nil
+}
+
+func (s *ConnectHandler) GetDeviceMetricAggregates(
+	ctx context.Context,
+	req *connect.Request[iotv1.GetDeviceMetricAggregatesRequest],
+) (*connect.Response[iotv1.GetDeviceMetricAggregatesResponse], error) {
+	svcReq := GetDeviceMetricAggregatesRequest{
+		DeviceID:    req.Msg.DeviceId,
+		BucketWidth: bucketWidthFromProtoOrName(req.Msg.BucketWidth),
+		FillEmpty:   req.Msg.FillEmpty,
+	}
+	if req.Msg.Timeframe != nil {
+		if req.Msg.Timeframe.Start != nil {
+			svcReq.TimeframeStart = ptr(req.Msg.Timeframe.Start.AsTime().UTC())
+		}
+		if req.Msg.Timeframe.End != nil {
+			svcReq.TimeframeEnd = ptr(req.Msg.Timeframe.End.AsTime().UTC())
+		}
+	}
+	res, err := s.svc.GetDeviceMetricAggregates(ctx, svcReq)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregatespb := make([]*iotv1.MetricAggregates, len(res.Metrics))
+	for i, a := range res.Metrics {
+		aggregatespb[i] = a.Proto()
+	}
+	return connect.NewResponse(&iotv1.GetDeviceMetricAggregatesResponse{
+		Metrics: aggregatespb,
+	}), nil
+}
+
+func (s *ConnectHandler) WatchDeviceAlerts(
+	ctx context.Context,
+	req *connect.Request[iotv1.WatchDeviceAlertsRequest],
+	stream *connect.ServerStream[iotv1.WatchDeviceAlertsResponse],
+) error {
+	svcReq := WatchDeviceAlertsRequest{
+		DeviceID: req.Msg.DeviceId,
+		Cursor:   req.Msg.Cursor,
+	}
+	for _, r := range req.Msg.Reasons {
+		svcReq.Reasons = append(svcReq.Reasons, alertReasonFromProtoOrName(r))
+	}
+
+	err := s.svc.WatchDeviceAlerts(ctx, svcReq, func(alert Alert, cursor string) error {
+		return stream.Send(&iotv1.WatchDeviceAlertsResponse{
+			Alert:  alert.Proto(),
+			Cursor: cursor,
+		})
+	})
+	if errors.Is(err, errAlertSubscriptionLagged) || errors.Is(err, errAlertBrokerClosed) {
+		return connect.NewError(connect.CodeUnavailable, fmt.Errorf("%w: resume watching from the last received cursor", err))
+	}
+	return err
+}
+
+func (s *ConnectHandler) CreateAlertRule(
+	ctx context.Context,
+	req *connect.Request[iotv1.CreateAlertRuleRequest],
+) (*connect.Response[iotv1.CreateAlertRuleResponse], error) {
+	svcReq := CreateAlertRuleRequest{
+		DeviceID: req.Msg.DeviceId,
+	}
+	if r := req.Msg.Rule; r != nil {
+		svcReq.Metric = r.Metric
+		svcReq.Operator = ruleOperatorFromProtoOrName(r.Operator)
+		svcReq.Threshold = r.Threshold
+		svcReq.ThresholdHigh = r.ThresholdHigh
+		svcReq.Severity = alertSeverityFromProtoOrName(r.Severity)
+		svcReq.WindowSeconds = r.WindowSeconds
+	}
+	rule, err := s.svc.CreateAlertRule(ctx, svcReq)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.CreateAlertRuleResponse{
+		Rule: rule.Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) GetAlertRule(
+	ctx context.Context,
+	req *connect.Request[iotv1.GetAlertRuleRequest],
+) (*connect.Response[iotv1.GetAlertRuleResponse], error) {
+	rule, err := s.svc.GetAlertRule(ctx, GetAlertRuleRequest{
+		DeviceID: req.Msg.DeviceId,
+		RuleID:   req.Msg.RuleId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.GetAlertRuleResponse{
+		Rule: rule.Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) ListAlertRules(
+	ctx context.Context,
+	req *connect.Request[iotv1.ListAlertRulesRequest],
+) (*connect.Response[iotv1.ListAlertRulesResponse], error) {
+	res, err := s.svc.ListAlertRules(ctx, ListAlertRulesRequest{
+		DeviceID: req.Msg.DeviceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rulespb := make([]*iotv1.AlertRule, len(res.Rules))
+	for i, r := range res.Rules {
+		rulespb[i] = r.Proto()
+	}
+	return connect.NewResponse(&iotv1.ListAlertRulesResponse{
+		Rules: rulespb,
+	}), nil
+}
+
+func (s *ConnectHandler) UpdateAlertRule(
+	ctx context.Context,
+	req *connect.Request[iotv1.UpdateAlertRuleRequest],
+) (*connect.Response[iotv1.UpdateAlertRuleResponse], error) {
+	svcReq := UpdateAlertRuleRequest{
+		DeviceID: req.Msg.DeviceId,
+	}
+	if r := req.Msg.Rule; r != nil {
+		svcReq.RuleID = r.Id
+		svcReq.Metric = r.Metric
+		svcReq.Operator = ruleOperatorFromProtoOrName(r.Operator)
+		svcReq.Threshold = r.Threshold
+		svcReq.ThresholdHigh = r.ThresholdHigh
+		svcReq.Severity = alertSeverityFromProtoOrName(r.Severity)
+		svcReq.WindowSeconds = r.WindowSeconds
+	}
+	rule, err := s.svc.UpdateAlertRule(ctx, svcReq)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.UpdateAlertRuleResponse{
+		Rule: rule.Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) DeleteAlertRule(
+	ctx context.Context,
+	req *connect.Request[iotv1.DeleteAlertRuleRequest],
+) (*connect.Response[iotv1.DeleteAlertRuleResponse], error) {
+	if err := s.svc.DeleteAlertRule(ctx, DeleteAlertRuleRequest{
+		DeviceID: req.Msg.DeviceId,
+		RuleID:   req.Msg.RuleId,
+	}); err != nil {
+		return nil, err
+	}
+	return &connect.Response[iotv1.DeleteAlertRuleResponse]{}, nil
+}
+
+func (s *ConnectHandler) AcknowledgeAlert(
+	ctx context.Context,
+	req *connect.Request[iotv1.AcknowledgeAlertRequest],
+) (*connect.Response[iotv1.AcknowledgeAlertResponse], error) {
+	alert, err := s.svc.AcknowledgeAlert(ctx, AcknowledgeAlertRequest{
+		DeviceID:       req.Msg.DeviceId,
+		AlertID:        req.Msg.AlertId,
+		AcknowledgedBy: req.Msg.AcknowledgedBy,
+		Comment:        req.Msg.Comment,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.AcknowledgeAlertResponse{
+		Alert: alert.Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) ResolveAlert(
+	ctx context.Context,
+	req *connect.Request[iotv1.ResolveAlertRequest],
+) (*connect.Response[iotv1.ResolveAlertResponse], error) {
+	alert, err := s.svc.ResolveAlert(ctx, ResolveAlertRequest{
+		DeviceID: req.Msg.DeviceId,
+		AlertID:  req.Msg.AlertId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.ResolveAlertResponse{
+		Alert: alert.Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) CreateDevice(
+	ctx context.Context,
+	req *connect.Request[iotv1.CreateDeviceRequest],
+) (*connect.Response[iotv1.CreateDeviceResponse], error) {
+	svcReq := CreateDeviceRequest{
+		DeviceID: req.Msg.DeviceId,
+	}
+	if d := req.Msg.Device; d != nil {
+		svcReq.DisplayName = d.DisplayName
+		svcReq.Model = d.Model
+		svcReq.FirmwareVersion = d.FirmwareVersion
+		svcReq.Location = d.Location
+		svcReq.Labels = d.Labels
+		svcReq.GroupID = d.GroupId
+	}
+	dev, err := s.svc.CreateDevice(ctx, svcReq)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.CreateDeviceResponse{
+		Device: dev.Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) GetDevice(
+	ctx context.Context,
+	req *connect.Request[iotv1.GetDeviceRequest],
+) (*connect.Response[iotv1.GetDeviceResponse], error) {
+	dev, err := s.svc.GetDevice(ctx, GetDeviceRequest{
+		DeviceID: req.Msg.DeviceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.GetDeviceResponse{
+		Device: dev.Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) UpdateDevice(
+	ctx context.Context,
+	req *connect.Request[iotv1.UpdateDeviceRequest],
+) (*connect.Response[iotv1.UpdateDeviceResponse], error) {
+	svcReq := UpdateDeviceRequest{
+		DeviceID: req.Msg.DeviceId,
+	}
+	if d := req.Msg.Device; d != nil {
+		svcReq.DisplayName = d.DisplayName
+		svcReq.Model = d.Model
+		svcReq.FirmwareVersion = d.FirmwareVersion
+		svcReq.Location = d.Location
+		svcReq.Labels = d.Labels
+		svcReq.GroupID = d.GroupId
+	}
+	dev, err := s.svc.UpdateDevice(ctx, svcReq)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.UpdateDeviceResponse{
+		Device: dev.Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) DeleteDevice(
+	ctx context.Context,
+	req *connect.Request[iotv1.DeleteDeviceRequest],
+) (*connect.Response[iotv1.DeleteDeviceResponse], error) {
+	op, err := s.svc.DeleteDevice(ctx, DeleteDeviceRequest{
+		DeviceID: req.Msg.DeviceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.DeleteDeviceResponse{
+		Operation: op.Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) GetOperation(
+	ctx context.Context,
+	req *connect.Request[iotv1.GetOperationRequest],
+) (*connect.Response[iotv1.GetOperationResponse], error) {
+	op, err := s.svc.GetOperation(ctx, GetOperationRequest{
+		OperationID: req.Msg.OperationId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.GetOperationResponse{
+		Operation: op.Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) ListDevices(
+	ctx context.Context,
+	req *connect.Request[iotv1.ListDevicesRequest],
+) (*connect.Response[iotv1.ListDevicesResponse], error) {
+	res, err := s.svc.ListDevices(ctx, ListDevicesRequest{
+		PageSize:      int(req.Msg.PageSize),
+		PageToken:     req.Msg.PageToken,
+		LabelSelector: req.Msg.LabelSelector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	devicespb := make([]*iotv1.Device, len(res.Devices))
+	for i, d := range res.Devices {
+		devicespb[i] = d.Proto()
+	}
+	return connect.NewResponse(&iotv1.ListDevicesResponse{
+		Devices:       devicespb,
+		NextPageToken: res.NextPageToken,
+	}), nil
+}
+
+func (s *ConnectHandler) GetFleetSnapshot(
+	ctx context.Context,
+	req *connect.Request[iotv1.GetFleetSnapshotRequest],
+) (*connect.Response[iotv1.GetFleetSnapshotResponse], error) {
+	res, err := s.svc.GetFleetSnapshot(ctx, GetFleetSnapshotRequest{
+		PageSize:      int(req.Msg.PageSize),
+		PageToken:     req.Msg.PageToken,
+		LabelSelector: req.Msg.LabelSelector,
+		GroupID:       req.Msg.GroupId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotspb := make([]*iotv1.DeviceSnapshot, len(res.Devices))
+	for i, d := range res.Devices {
+		snapshotspb[i] = d.Proto()
+	}
+	return connect.NewResponse(&iotv1.GetFleetSnapshotResponse{
+		Devices:       snapshotspb,
+		NextPageToken: res.NextPageToken,
+	}), nil
+}
+
+func (s *ConnectHandler) CreateDeviceGroup(
+	ctx context.Context,
+	req *connect.Request[iotv1.CreateDeviceGroupRequest],
+) (*connect.Response[iotv1.CreateDeviceGroupResponse], error) {
+	group, err := s.svc.CreateDeviceGroup(ctx, CreateDeviceGroupRequest{
+		GroupID:     req.Msg.GroupId,
+		DisplayName: req.Msg.DisplayName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.CreateDeviceGroupResponse{
+		Group: group.Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) GetDeviceGroup(
+	ctx context.Context,
+	req *connect.Request[iotv1.GetDeviceGroupRequest],
+) (*connect.Response[iotv1.GetDeviceGroupResponse], error) {
+	group, err := s.svc.GetDeviceGroup(ctx, GetDeviceGroupRequest{
+		GroupID: req.Msg.GroupId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.GetDeviceGroupResponse{
+		Group: group.Proto(),
+	}), nil
+}
+
+func (s *ConnectHandler) ListDeviceGroups(
+	ctx context.Context,
+	_ *connect.Request[iotv1.ListDeviceGroupsRequest],
+) (*connect.Response[iotv1.ListDeviceGroupsResponse], error) {
+	res, err := s.svc.ListDeviceGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groupspb := make([]*iotv1.DeviceGroup, len(res.Groups))
+	for i, g := range res.Groups {
+		groupspb[i] = g.Proto()
+	}
+	return connect.NewResponse(&iotv1.ListDeviceGroupsResponse{
+		Groups: groupspb,
+	}), nil
+}
+
+func (s *ConnectHandler) ConfigureDeviceGroup(
+	ctx context.Context,
+	req *connect.Request[iotv1.ConfigureDeviceGroupRequest],
+) (*connect.Response[iotv1.ConfigureDeviceGroupResponse], error) {
+	err := s.svc.ConfigureDeviceGroup(ctx, ConfigureDeviceGroupRequest{
+		GroupID:            req.Msg.GroupId,
+		ConfigSettingsBody: configSettingsFromProto(req.Msg.Settings),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &connect.Response[iotv1.ConfigureDeviceGroupResponse]{}, nil
+}
+
+func (s *ConnectHandler) ConfigureDefaults(
+	ctx context.Context,
+	req *connect.Request[iotv1.ConfigureDefaultsRequest],
+) (*connect.Response[iotv1.ConfigureDefaultsResponse], error) {
+	err := s.svc.ConfigureDefaults(ctx, ConfigureDefaultsRequest{
+		ConfigSettingsBody: configSettingsFromProto(req.Msg.Settings),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &connect.Response[iotv1.ConfigureDefaultsResponse]{}, nil
+}
+
+func (s *ConnectHandler) GetEffectiveDeviceConfig(
+	ctx context.Context,
+	req *connect.Request[iotv1.GetEffectiveDeviceConfigRequest],
+) (*connect.Response[iotv1.GetEffectiveDeviceConfigResponse], error) {
+	res, err := s.svc.GetEffectiveDeviceConfig(ctx, GetEffectiveDeviceConfigRequest{
+		DeviceID: req.Msg.DeviceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sourcespb := make(map[string]iotv1.ConfigLevel, len(res.Sources))
+	for setting, level := range res.Sources {
+		sourcespb[string(setting)] = level.Proto()
+	}
+	return connect.NewResponse(&iotv1.GetEffectiveDeviceConfigResponse{
+		GroupId:  res.GroupID,
+		Settings: res.Settings.settings().Proto(),
+		Sources:  sourcespb,
+	}), nil
+}
+
+func (s *ConnectHandler) ListDeviceConfigVersions(
+	ctx context.Context,
+	req *connect.Request[iotv1.ListDeviceConfigVersionsRequest],
+) (*connect.Response[iotv1.ListDeviceConfigVersionsResponse], error) {
+	res, err := s.svc.ListDeviceConfigVersions(ctx, ListDeviceConfigVersionsRequest{
+		DeviceID:  req.Msg.DeviceId,
+		PageSize:  int(req.Msg.PageSize),
+		PageToken: req.Msg.PageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	versionspb := make([]*iotv1.DeviceConfigVersion, len(res.Versions))
+	for i, v := range res.Versions {
+		versionspb[i] = v.version().Proto()
+	}
+	return connect.NewResponse(&iotv1.ListDeviceConfigVersionsResponse{
+		Versions:      versionspb,
+		NextPageToken: res.NextPageToken,
+	}), nil
+}
+
+func (s *ConnectHandler) RollbackDeviceConfig(
+	ctx context.Context,
+	req *connect.Request[iotv1.RollbackDeviceConfigRequest],
+) (*connect.Response[iotv1.RollbackDeviceConfigResponse], error) {
+	version, err := s.svc.RollbackDeviceConfig(ctx, RollbackDeviceConfigRequest{
+		DeviceID:         req.Msg.DeviceId,
+		Version:          req.Msg.Version,
+		Author:           req.Msg.Author,
+		Reason:           req.Msg.Reason,
+		ExpectedRevision: req.Msg.ExpectedRevision,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&iotv1.RollbackDeviceConfigResponse{
+		Version: version.version().Proto(),
+	}), nil
+}
+
+// ruleOperatorFromProtoOrName converts a proto operator, keeping the enum name
+// of unknown operators so that they are rejected by validation.
+func ruleOperatorFromProtoOrName(o iotv1.AlertRule_Operator) RuleOperator {
+	if op, ok := ruleOperatorFromProto(o); ok {
+		return op
+	}
+	return RuleOperator(o.String())
+}
+
+// alertSeverityFromProtoOrName converts a proto severity, keeping the enum
+// name of unknown severities so that they are rejected by validation.
+func alertSeverityFromProtoOrName(s iotv1.Severity) AlertSeverity {
+	if severity, ok := alertSeverityFromProto(s); ok {
+		return severity
+	}
+	return AlertSeverity(s.String())
+}
+
+// alertReasonFromProtoOrName converts a proto alert reason, keeping the enum
+// name of unknown reasons so that they are rejected by validation.
+func alertReasonFromProtoOrName(r iotv1.Alert_Reason) AlertReason {
+	if reason, ok := alertReasonFromProto(r); ok {
+		return reason
+	}
+	return AlertReason(r.String())
+}
+
+// alertStateFromProtoOrName converts a proto alert state, keeping the enum
+// name of unknown states so that they are rejected by validation.
+func alertStateFromProtoOrName(s iotv1.Alert_State) AlertState {
+	if state, ok := alertStateFromProto(s); ok {
+		return state
+	}
+	return AlertState(s.String())
+}
+
+// bucketWidthFromProtoOrName converts a proto bucket width, keeping the enum
+// name of unknown widths so that they are rejected by validation.
+func bucketWidthFromProtoOrName(w iotv1.BucketWidth) BucketWidth {
+	if width, ok := bucketWidthFromProto(w); ok {
+		return width
+	}
+	return BucketWidth(w.String())
+}
+
+// sortOrderFromProtoOrName converts a proto sort order, keeping the enum name
+// of unknown orders so that they are rejected by validation.
+func sortOrderFromProtoOrName(o iotv1.SortOrder) SortOrder {
+	if order, ok := sortOrderFromProto(o); ok {
+		return order
+	}
+	return SortOrder(o.String())
+}
+
+// configSettingsFromProto converts proto config settings. Empty lists are
+// treated as unset since proto3 cannot distinguish them, unless an update mask
+// says otherwise.
+func configSettingsFromProto(settings *iotv1.ConfigSettings) ConfigSettingsBody {
+	if settings == nil {
+		return ConfigSettingsBody{}
+	}
+	body := ConfigSettingsBody{
+		TemperatureThreshold:     settings.TemperatureThreshold,
+		BatteryThreshold:         settings.BatteryThreshold,
+		ConsecutiveBreaches:      settings.ConsecutiveBreaches,
+		Hysteresis:               settings.Hysteresis,
+		ReportingIntervalSeconds: settings.ReportingIntervalSeconds,
+	}
+	for _, e := range settings.Expressions {
+		body.Expressions = append(body.Expressions, ConfigureDeviceExpression{
+			Expr:     e.Expr,
+			Severity: alertSeverityFromProtoOrName(e.Severity),
+		})
+	}
+	for _, c := range settings.Cooldowns {
+		body.Cooldowns = append(body.Cooldowns, ConfigureDeviceCooldown{
+			Reason:        alertReasonFromProtoOrName(c.Reason),
+			WindowSeconds: c.WindowSeconds,
+		})
+	}
+	return body
+}
+
+func metricValuesFromProto(values []*iotv1.MetricValue) []RecordMetricValue {
+	if len(values) == 0 {
+		return nil
+	}
+	res := make([]RecordMetricValue, len(values))
+	for i, v := range values {
+		res[i] = RecordMetricValue{
+			Name:  v.Name,
+			Value: v.Value,
+			Unit:  v.Unit,
+		}
+	}
+	return res
+}
@@ -0,0 +1,152 @@
+package device
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	iotv1 "github.com/joshjon/iot-metrics/proto/gen/iot/v1"
+)
+
+var (
+	labelKeyRegex   = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._/-]{0,61}[a-zA-Z0-9])?$`)
+	labelValueRegex = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9._-]{0,61}[a-zA-Z0-9])?)?$`)
+)
+
+// Device is a registered device along with its metadata and labels.
+type Device struct {
+	ID              string
+	DisplayName     string
+	Model           string
+	FirmwareVersion string
+	Location        string
+	Labels          map[string]string
+	CreatedAt       time.Time
+	// LastSeen is when the device last recorded a metric, or nil if it has not
+	// recorded any.
+	LastSeen *time.Time
+	// GroupID is the group the device inherits config from, or empty if it
+	// does not belong to one.
+	GroupID string
+}
+
+func (d Device) Proto() *iotv1.Device {
+	devicepb := &iotv1.Device{
+		Id:              d.ID,
+		DisplayName:     d.DisplayName,
+		Model:           d.Model,
+		FirmwareVersion: d.FirmwareVersion,
+		Location:        d.Location,
+		Labels:          d.Labels,
+		CreatedAt:       timestamppb.New(d.CreatedAt),
+		GroupId:         d.GroupID,
+	}
+	if d.LastSeen != nil {
+		devicepb.LastSeen = timestamppb.New(*d.LastSeen)
+	}
+	return devicepb
+}
+
+// DeviceSnapshot is the latest state of a device.
+type DeviceSnapshot struct {
+	Device Device
+	// LatestMetric is the most recent metric recorded by the device, or nil if
+	// it has not recorded any.
+	LatestMetric *Metric
+	// OpenAlerts is the number of alerts of the device that are unresolved,
+	// whether open or acknowledged.
+	OpenAlerts int64
+}
+
+func (s DeviceSnapshot) Proto() *iotv1.DeviceSnapshot {
+	snapshotpb := &iotv1.DeviceSnapshot{
+		Device:     s.Device.Proto(),
+		OpenAlerts: s.OpenAlerts,
+	}
+	if s.LatestMetric != nil {
+		snapshotpb.LatestMetric = s.LatestMetric.Proto()
+	}
+	return snapshotpb
+}
+
+// DeviceGroup is a group of devices that inherit its config.
+type DeviceGroup struct {
+	ID          string
+	DisplayName string
+	CreatedAt   time.Time
+}
+
+func (g DeviceGroup) Proto() *iotv1.DeviceGroup {
+	return &iotv1.DeviceGroup{
+		Id:          g.ID,
+		DisplayName: g.DisplayName,
+		CreatedAt:   timestamppb.New(g.CreatedAt),
+	}
+}
+
+const (
+	// LabelOperatorEquals matches devices with the label set to the value.
+	LabelOperatorEquals LabelOperator = "="
+	// LabelOperatorNotEquals matches devices without the label set to the
+	// value, including devices without the label.
+	LabelOperatorNotEquals LabelOperator = "!="
+	// LabelOperatorExists matches devices with the label.
+	LabelOperatorExists LabelOperator = "exists"
+	// LabelOperatorNotExists matches devices without the label.
+	LabelOperatorNotExists LabelOperator = "!exists"
+)
+
+type LabelOperator string
+
+// LabelRequirement is a requirement of a label selector.
+type LabelRequirement struct {
+	Key      string
+	Operator LabelOperator
+	// Value is unused by the exists operators.
+	Value string
+}
+
+// LabelSelector selects devices whose labels match all of its requirements.
+type LabelSelector []LabelRequirement
+
+// parseLabelSelector parses a comma separated list of label requirements, each
+// one of `key=value`, `key==value`, `key!=value`, `key` or `!key`.
+func parseLabelSelector(s string) (LabelSelector, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var selector LabelSelector
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		var req LabelRequirement
+		switch {
+		case strings.Contains(part, "!="):
+			key, value, _ := strings.Cut(part, "!=")
+			req = LabelRequirement{Key: key, Operator: LabelOperatorNotEquals, Value: value}
+		case strings.Contains(part, "=="):
+			key, value, _ := strings.Cut(part, "==")
+			req = LabelRequirement{Key: key, Operator: LabelOperatorEquals, Value: value}
+		case strings.Contains(part, "="):
+			key, value, _ := strings.Cut(part, "=")
+			req = LabelRequirement{Key: key, Operator: LabelOperatorEquals, Value: value}
+		case strings.HasPrefix(part, "!"):
+			req = LabelRequirement{Key: strings.TrimPrefix(part, "!"), Operator: LabelOperatorNotExists}
+		default:
+			req = LabelRequirement{Key: part, Operator: LabelOperatorExists}
+		}
+
+		req.Key, req.Value = strings.TrimSpace(req.Key), strings.TrimSpace(req.Value)
+		if !labelKeyRegex.MatchString(req.Key) {
+			return nil, fmt.Errorf("invalid label key %q", req.Key)
+		}
+		if !labelValueRegex.MatchString(req.Value) {
+			return nil, fmt.Errorf("invalid label value %q", req.Value)
+		}
+		selector = append(selector, req)
+	}
+	return selector, nil
+}
@@ -1,9 +1,6 @@
 package device
 
-import (
-	"connectrpc.com/connect"
-	"github.com/labstack/echo/v4"
-)
+import "github.com/labstack/echo/v4"
 
 // EchoRequestDeviceIDGetter gets the device ID from a REST based request for
 // device rate limiting middleware.
@@ -15,10 +12,10 @@ func EchoRequestDeviceIDGetter(c echo.Context) (string, bool) {
 	return deviceID, true
 }
 
-// ConnectRequestDeviceIDGetter gets the device ID from a REST based request for
-// device rate limiting middleware.
-func ConnectRequestDeviceIDGetter(req connect.AnyRequest) (string, bool) {
-	if dr, ok := req.Any().(interface{ GetDeviceId() string }); ok {
+// ConnectRequestDeviceIDGetter gets the device ID from a Connect based request
+// or stream message for device rate limiting middleware.
+func ConnectRequestDeviceIDGetter(msg any) (string, bool) {
+	if dr, ok := msg.(interface{ GetDeviceId() string }); ok && dr.GetDeviceId() != "" {
 		return dr.GetDeviceId(), true
 	}
 	return "", false
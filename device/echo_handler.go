@@ -1,12 +1,31 @@
 package device
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
+
+	ihttp "github.com/joshjon/iot-metrics/http"
 )
 
+// sseKeepAliveInterval is the interval between keep-alive comments sent on
+// idle Server-Sent Events streams.
+const sseKeepAliveInterval = 15 * time.Second
+
+// maxConfigPatchSize is the maximum size in bytes of a JSON merge patch of a
+// device config, which is read in full before it is decoded.
+const maxConfigPatchSize = 64 << 10
+
 // EchoHandler is a REST based handler for the IoT Device Metrics API.
 type EchoHandler struct {
 	svc *Service
@@ -19,15 +38,216 @@ func NewEchoHandler(service *Service) *EchoHandler {
 }
 
 func (h *EchoHandler) Register(g *echo.Group, middleware ...echo.MiddlewareFunc) {
+	g.POST("/devices", h.CreateDevice, middleware...)
+	g.GET("/devices", h.ListDevices, middleware...)
+	g.GET("/devices/:device_id", h.GetDevice, middleware...)
+	g.PUT("/devices/:device_id", h.UpdateDevice, middleware...)
+	g.DELETE("/devices/:device_id", h.DeleteDevice, middleware...)
 	g.POST("/devices/:device_id/config", h.ConfigureDevice, middleware...)
+	g.GET("/devices/:device_id/config", h.GetDeviceConfig, middleware...)
+	g.PATCH("/devices/:device_id/config", h.UpdateDeviceConfig, middleware...)
+	g.GET("/devices/:device_id/config\\:effective", h.GetEffectiveDeviceConfig, middleware...)
+	g.GET("/devices/:device_id/config/versions", h.ListDeviceConfigVersions, middleware...)
+	g.POST("/devices/:device_id/config\\:rollback", h.RollbackDeviceConfig, middleware...)
+	g.POST("/groups", h.CreateDeviceGroup, middleware...)
+	g.GET("/groups", h.ListDeviceGroups, middleware...)
+	g.GET("/groups/:group_id", h.GetDeviceGroup, middleware...)
+	g.POST("/groups/:group_id/config", h.ConfigureDeviceGroup, middleware...)
+	g.POST("/config", h.ConfigureDefaults, middleware...)
 	g.POST("/devices/:device_id/metrics", h.RecordMetric, middleware...)
+	g.POST("/devices/:device_id/metrics\\:batch", h.RecordMetrics, middleware...)
+	g.GET("/devices/:device_id/metrics", h.GetDeviceMetrics, middleware...)
+	g.GET("/devices/:device_id/metrics\\:aggregate", h.GetDeviceMetricAggregates, middleware...)
 	g.GET("/devices/:device_id/alerts", h.GetDeviceAlerts, middleware...)
+	g.GET("/devices/:device_id/alerts/stream", h.StreamAlerts, middleware...)
+	g.POST("/devices/:device_id/alerts/:alert_id/acknowledge", h.AcknowledgeAlert, middleware...)
+	g.POST("/devices/:device_id/alerts/:alert_id/resolve", h.ResolveAlert, middleware...)
+	g.GET("/alerts", h.SearchAlerts, middleware...)
+	g.GET("/alerts/stream", h.StreamAlerts, middleware...)
+	g.GET("/alerts/stats", h.GetAlertStats, middleware...)
+	g.POST("/devices/:device_id/rules", h.CreateAlertRule, middleware...)
+	g.GET("/devices/:device_id/rules", h.ListAlertRules, middleware...)
+	g.GET("/devices/:device_id/rules/:rule_id", h.GetAlertRule, middleware...)
+	g.PUT("/devices/:device_id/rules/:rule_id", h.UpdateAlertRule, middleware...)
+	g.DELETE("/devices/:device_id/rules/:rule_id", h.DeleteAlertRule, middleware...)
+	g.GET("/operations/:operation_id", h.GetOperation, middleware...)
+	g.GET("/fleet/snapshot", h.GetFleetSnapshot, middleware...)
+}
+
+type CreateDeviceRequest struct {
+	DeviceID        string            `json:"device_id"`
+	DisplayName     string            `json:"display_name"`
+	Model           string            `json:"model"`
+	FirmwareVersion string            `json:"firmware_version"`
+	Location        string            `json:"location"`
+	Labels          map[string]string `json:"labels"`
+	GroupID         string            `json:"group_id"`
+}
+
+func (h *EchoHandler) CreateDevice(c echo.Context) error {
+	var req CreateDeviceRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	dev, err := h.svc.CreateDevice(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, dev)
+}
+
+type GetDeviceRequest struct {
+	DeviceID string `param:"device_id" json:"-"`
+}
+
+func (h *EchoHandler) GetDevice(c echo.Context) error {
+	var req GetDeviceRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	dev, err := h.svc.GetDevice(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, dev)
+}
+
+type UpdateDeviceRequest struct {
+	DeviceID        string            `param:"device_id" json:"-"`
+	DisplayName     string            `json:"display_name"`
+	Model           string            `json:"model"`
+	FirmwareVersion string            `json:"firmware_version"`
+	Location        string            `json:"location"`
+	Labels          map[string]string `json:"labels"`
+	GroupID         string            `json:"group_id"`
+}
+
+func (h *EchoHandler) UpdateDevice(c echo.Context) error {
+	var req UpdateDeviceRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	dev, err := h.svc.UpdateDevice(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, dev)
+}
+
+type DeleteDeviceRequest struct {
+	DeviceID string `param:"device_id" json:"-"`
+}
+
+// DeleteDevice responds with the operation purging the device's data, which
+// can be polled at the Location header.
+func (h *EchoHandler) DeleteDevice(c echo.Context) error {
+	var req DeleteDeviceRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	op, err := h.svc.DeleteDevice(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(echo.HeaderLocation, "/operations/"+strconv.FormatInt(op.ID, 10))
+	return c.JSON(http.StatusAccepted, op)
+}
+
+type GetOperationRequest struct {
+	OperationID int64 `param:"operation_id" json:"-"`
+}
+
+func (h *EchoHandler) GetOperation(c echo.Context) error {
+	var req GetOperationRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	op, err := h.svc.GetOperation(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, op)
+}
+
+type ListDevicesRequest struct {
+	PageSize      int    `query:"page.size" json:"-"`
+	PageToken     string `query:"page.token" json:"-"`
+	LabelSelector string `query:"label_selector" json:"-"`
+}
+
+type ListDevicesResponse struct {
+	Devices       []Device `json:"devices"`
+	NextPageToken string   `json:"next_page_token,omitempty"`
+}
+
+func (h *EchoHandler) ListDevices(c echo.Context) error {
+	var req ListDevicesRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	res, err := h.svc.ListDevices(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+type GetFleetSnapshotRequest struct {
+	PageSize      int    `query:"page.size" json:"-"`
+	PageToken     string `query:"page.token" json:"-"`
+	LabelSelector string `query:"label_selector" json:"-"`
+	GroupID       string `query:"group_id" json:"-"`
+}
+
+type GetFleetSnapshotResponse struct {
+	Devices       []DeviceSnapshot `json:"devices"`
+	NextPageToken string           `json:"next_page_token,omitempty"`
+}
+
+func (h *EchoHandler) GetFleetSnapshot(c echo.Context) error {
+	var req GetFleetSnapshotRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	res, err := h.svc.GetFleetSnapshot(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
 }
 
 type ConfigureDeviceRequest struct {
-	DeviceID             string  `param:"device_id" json:"-"`
-	TemperatureThreshold float64 `json:"temperature_threshold"`
-	BatteryThreshold     int32   `json:"battery_threshold"`
+	DeviceID string `param:"device_id" json:"-"`
+	ConfigSettingsBody
+	// Author and Reason are recorded in the config version created.
+	Author string `json:"author"`
+	Reason string `json:"reason"`
+	// ExpectedRevision is the revision the change is conditional on, if any,
+	// set from the If-Match header.
+	ExpectedRevision *int64 `json:"-"`
+}
+
+// ConfigSettingsBody holds the settings of a config level. Settings that are
+// omitted are inherited from the next level. Lists use omitzero so that an
+// empty list, which overrides the inherited list, is kept in responses.
+type ConfigSettingsBody struct {
+	TemperatureThreshold     *float64                    `json:"temperature_threshold,omitempty"`
+	BatteryThreshold         *int32                      `json:"battery_threshold,omitempty"`
+	Expressions              []ConfigureDeviceExpression `json:"expressions,omitzero"`
+	ConsecutiveBreaches      *int32                      `json:"consecutive_breaches,omitempty"`
+	Hysteresis               *float64                    `json:"hysteresis,omitempty"`
+	Cooldowns                []ConfigureDeviceCooldown   `json:"cooldowns,omitzero"`
+	ReportingIntervalSeconds *int64                      `json:"reporting_interval_seconds,omitempty"`
+}
+
+type ConfigureDeviceExpression struct {
+	Expr     string        `json:"expr"`
+	Severity AlertSeverity `json:"severity"`
+}
+
+type ConfigureDeviceCooldown struct {
+	Reason        AlertReason `json:"reason"`
+	WindowSeconds int64       `json:"window_seconds"`
 }
 
 func (h *EchoHandler) ConfigureDevice(c echo.Context) error {
@@ -35,17 +255,354 @@ func (h *EchoHandler) ConfigureDevice(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return err
 	}
-	if err := h.svc.ConfigureDevice(c.Request().Context(), req); err != nil {
+	var err error
+	if req.ExpectedRevision, err = h.ifMatchRevision(c, req.DeviceID); err != nil {
+		return err
+	}
+	revision, err := h.svc.ConfigureDevice(c.Request().Context(), req)
+	if err != nil {
 		return err
 	}
+	setRevisionETag(c, revision)
 	return c.NoContent(http.StatusCreated)
 }
 
+type GetDeviceConfigRequest struct {
+	DeviceID string `param:"device_id" json:"-"`
+}
+
+func (h *EchoHandler) GetDeviceConfig(c echo.Context) error {
+	var req GetDeviceConfigRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	cfg, err := h.svc.GetDeviceConfig(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	setRevisionETag(c, cfg.Version)
+	return c.JSON(http.StatusOK, cfg)
+}
+
+type UpdateDeviceConfigRequest struct {
+	DeviceID string             `param:"device_id" json:"-"`
+	Settings ConfigSettingsBody `json:"settings"`
+	Author   string             `json:"author"`
+	Reason   string             `json:"reason"`
+	// UpdateMask is the settings to update. Settings in the mask that are
+	// omitted from Settings are cleared so that they are inherited.
+	UpdateMask []ConfigSetting `json:"-"`
+	// ExpectedRevision is the revision the change is conditional on, if any,
+	// set from the If-Match header.
+	ExpectedRevision *int64 `json:"-"`
+}
+
+// UpdateDeviceConfig applies a JSON merge patch (RFC 7396) to the settings of
+// a device. The settings present in the patch form the update mask, where a
+// null setting is cleared.
+func (h *EchoHandler) UpdateDeviceConfig(c echo.Context) error {
+	var req UpdateDeviceConfigRequest
+	if err := (&echo.DefaultBinder{}).BindPathParams(c, &req); err != nil {
+		return err
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, maxConfigPatchSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge).SetInternal(err)
+		}
+		return err
+	}
+	// the body is decoded regardless of its content type, which is typically
+	// application/merge-patch+json
+	var patch struct {
+		Settings map[string]json.RawMessage `json:"settings"`
+	}
+	if err = json.Unmarshal(body, &req); err == nil {
+		err = json.Unmarshal(body, &patch)
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON merge patch").SetInternal(err)
+	}
+	for name := range patch.Settings {
+		req.UpdateMask = append(req.UpdateMask, ConfigSetting(name))
+	}
+	slices.Sort(req.UpdateMask)
+	if req.ExpectedRevision, err = h.ifMatchRevision(c, req.DeviceID); err != nil {
+		return err
+	}
+
+	cfg, err := h.svc.UpdateDeviceConfig(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	setRevisionETag(c, cfg.Version)
+	return c.JSON(http.StatusOK, cfg)
+}
+
+type CreateDeviceGroupRequest struct {
+	GroupID     string `json:"group_id"`
+	DisplayName string `json:"display_name"`
+}
+
+func (h *EchoHandler) CreateDeviceGroup(c echo.Context) error {
+	var req CreateDeviceGroupRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	group, err := h.svc.CreateDeviceGroup(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, group)
+}
+
+type GetDeviceGroupRequest struct {
+	GroupID string `param:"group_id" json:"-"`
+}
+
+func (h *EchoHandler) GetDeviceGroup(c echo.Context) error {
+	var req GetDeviceGroupRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	group, err := h.svc.GetDeviceGroup(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, group)
+}
+
+type ListDeviceGroupsResponse struct {
+	Groups []DeviceGroup `json:"groups"`
+}
+
+func (h *EchoHandler) ListDeviceGroups(c echo.Context) error {
+	res, err := h.svc.ListDeviceGroups(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+type ConfigureDeviceGroupRequest struct {
+	GroupID string `param:"group_id" json:"-"`
+	ConfigSettingsBody
+}
+
+func (h *EchoHandler) ConfigureDeviceGroup(c echo.Context) error {
+	var req ConfigureDeviceGroupRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := h.svc.ConfigureDeviceGroup(c.Request().Context(), req); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusCreated)
+}
+
+type ConfigureDefaultsRequest struct {
+	ConfigSettingsBody
+}
+
+func (h *EchoHandler) ConfigureDefaults(c echo.Context) error {
+	var req ConfigureDefaultsRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := h.svc.ConfigureDefaults(c.Request().Context(), req); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusCreated)
+}
+
+type ListDeviceConfigVersionsRequest struct {
+	DeviceID  string `param:"device_id" json:"-"`
+	PageSize  int    `query:"page.size" json:"-"`
+	PageToken string `query:"page.token" json:"-"`
+}
+
+type ListDeviceConfigVersionsResponse struct {
+	Versions      []DeviceConfigVersionBody `json:"versions"`
+	NextPageToken string                    `json:"next_page_token,omitempty"`
+}
+
+// DeviceConfigVersionBody is a version of the config settings set for a device.
+type DeviceConfigVersionBody struct {
+	Version   int64              `json:"version"`
+	Settings  ConfigSettingsBody `json:"settings"`
+	CreatedAt time.Time          `json:"created_at"`
+	Author    string             `json:"author,omitempty"`
+	Reason    string             `json:"reason,omitempty"`
+}
+
+func (h *EchoHandler) ListDeviceConfigVersions(c echo.Context) error {
+	var req ListDeviceConfigVersionsRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	res, err := h.svc.ListDeviceConfigVersions(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+type RollbackDeviceConfigRequest struct {
+	DeviceID string `param:"device_id" json:"-"`
+	Version  int64  `json:"version"`
+	Author   string `json:"author"`
+	Reason   string `json:"reason"`
+	// ExpectedRevision is the revision the rollback is conditional on, if any,
+	// set from the If-Match header.
+	ExpectedRevision *int64 `json:"-"`
+}
+
+func (h *EchoHandler) RollbackDeviceConfig(c echo.Context) error {
+	var req RollbackDeviceConfigRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	var err error
+	if req.ExpectedRevision, err = h.ifMatchRevision(c, req.DeviceID); err != nil {
+		return err
+	}
+	version, err := h.svc.RollbackDeviceConfig(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	setRevisionETag(c, version.Version)
+	return c.JSON(http.StatusCreated, version)
+}
+
+// ifMatchRevision returns the config revision that a change is conditional on
+// from the If-Match header, or nil if the header is not set. A revision is sent
+// as a strong entity tag of its version number, e.g. "3", where "0" matches a
+// device without a config. A list of entity tags, or * for any existing
+// config, is matched against the current revision, which the change is then
+// made conditional on so that it fails if the config changes in between.
+func (h *EchoHandler) ifMatchRevision(c echo.Context, deviceID string) (*int64, error) {
+	header := strings.TrimSpace(strings.Join(c.Request().Header.Values("If-Match"), ","))
+	if header == "" {
+		return nil, nil
+	}
+	wildcard, tags, ok := parseIfMatch(header)
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, `If-Match must be * or a list of entity tags, e.g. "3"`)
+	}
+
+	// weak tags and tags that are not revisions never match, since If-Match
+	// uses the strong comparison
+	var revisions []int64
+	for _, tag := range tags {
+		if tag.weak {
+			continue
+		}
+		if revision, err := strconv.ParseInt(tag.opaque, 10, 64); err == nil && revision >= 0 {
+			revisions = append(revisions, revision)
+		}
+	}
+	if !wildcard && len(revisions) == 1 {
+		return &revisions[0], nil
+	}
+	mismatch := &ihttp.PreconditionFailedError{
+		Message: fmt.Sprintf("config for device %s does not match If-Match", deviceID),
+	}
+	if !wildcard && len(revisions) == 0 {
+		return nil, mismatch
+	}
+
+	current, err := h.svc.configRevision(c.Request().Context(), deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if (wildcard && current == 0) || (!wildcard && !slices.Contains(revisions, current)) {
+		return nil, mismatch
+	}
+	return &current, nil
+}
+
+// entityTag is an entity tag of an If-Match header.
+type entityTag struct {
+	weak   bool
+	opaque string
+}
+
+// parseIfMatch parses an If-Match header (RFC 9110), which is either * or a
+// comma-separated list of entity tags. It reports false if the header is
+// malformed.
+func parseIfMatch(header string) (wildcard bool, tags []entityTag, ok bool) {
+	if header == "*" {
+		return true, nil, true
+	}
+	for rest := header; ; {
+		rest = strings.TrimLeft(rest, " \t,")
+		if rest == "" {
+			return false, tags, len(tags) > 0
+		}
+		var tag entityTag
+		rest, tag.weak = strings.CutPrefix(rest, "W/")
+		if !strings.HasPrefix(rest, `"`) {
+			return false, nil, false
+		}
+		end := strings.IndexByte(rest[1:], '"')
+		if end < 0 {
+			return false, nil, false
+		}
+		tag.opaque, rest = rest[1:end+1], rest[end+2:]
+		tags = append(tags, tag)
+		// tags are separated by commas
+		if rest = strings.TrimLeft(rest, " \t"); rest != "" && rest[0] != ',' {
+			return false, nil, false
+		}
+	}
+}
+
+// setRevisionETag sets the ETag header to the config revision, which can be
+// sent back in an If-Match header to make a change conditional on it.
+func setRevisionETag(c echo.Context, revision int64) {
+	c.Response().Header().Set("ETag", strconv.Quote(strconv.FormatInt(revision, 10)))
+}
+
+type GetEffectiveDeviceConfigRequest struct {
+	DeviceID string `param:"device_id" json:"-"`
+}
+
+type GetEffectiveDeviceConfigResponse struct {
+	GroupID string `json:"group_id,omitempty"`
+	// Settings holds the resolved settings, omitting settings that are not set
+	// at any level.
+	Settings ConfigSettingsBody `json:"settings"`
+	// Sources is the level that each setting was resolved from.
+	Sources map[ConfigSetting]ConfigLevel `json:"sources"`
+}
+
+func (h *EchoHandler) GetEffectiveDeviceConfig(c echo.Context) error {
+	var req GetEffectiveDeviceConfigRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	res, err := h.svc.GetEffectiveDeviceConfig(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
 type RecordMetricRequest struct {
-	DeviceID    string    `param:"device_id" json:"-"`
-	Temperature float64   `json:"temperature"`
-	Battery     int32     `json:"battery"`
-	Timestamp   time.Time `json:"timestamp"`
+	DeviceID string `param:"device_id" json:"-"`
+	// Deprecated: use Values instead. Ignored if Values is not empty.
+	Temperature float64 `json:"temperature"`
+	// Deprecated: use Values instead. Ignored if Values is not empty.
+	Battery   int32               `json:"battery"`
+	Values    []RecordMetricValue `json:"values"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
+type RecordMetricValue struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
 }
 
 func (h *EchoHandler) RecordMetric(c echo.Context) error {
@@ -59,12 +616,95 @@ func (h *EchoHandler) RecordMetric(c echo.Context) error {
 	return c.NoContent(http.StatusCreated)
 }
 
-type GetDeviceAlertsRequest struct {
+type RecordMetricsRequest struct {
+	DeviceID string              `param:"device_id" json:"-"`
+	Metrics  []RecordMetricsItem `json:"metrics"`
+}
+
+type RecordMetricsItem struct {
+	// Deprecated: use Values instead. Ignored if Values is not empty.
+	Temperature float64 `json:"temperature"`
+	// Deprecated: use Values instead. Ignored if Values is not empty.
+	Battery   int32               `json:"battery"`
+	Values    []RecordMetricValue `json:"values"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
+type RecordMetricsResponse struct {
+	Recorded int `json:"recorded"`
+}
+
+func (h *EchoHandler) RecordMetrics(c echo.Context) error {
+	var req RecordMetricsRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	res, err := h.svc.RecordMetrics(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+type GetDeviceMetricsRequest struct {
 	DeviceID       string     `param:"device_id" json:"-"`
 	TimeframeStart *time.Time `query:"timeframe.start" json:"-"`
 	TimeframeEnd   *time.Time `query:"timeframe.end" json:"-"`
 	PageSize       int        `query:"page.size" json:"-"`
 	PageToken      string     `query:"page.token" json:"-"`
+	Order          SortOrder  `query:"order" json:"-"`
+}
+
+type GetDeviceMetricsResponse struct {
+	Metrics       []Metric `json:"metrics"`
+	NextPageToken string   `json:"next_page_token,omitempty"`
+}
+
+func (h *EchoHandler) GetDeviceMetrics(c echo.Context) error {
+	var req GetDeviceMetricsRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	res, err := h.svc.GetDeviceMetrics(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+type GetDeviceMetricAggregatesRequest struct {
+	DeviceID       string      `param:"device_id" json:"-"`
+	TimeframeStart *time.Time  `query:"timeframe.start" json:"-"`
+	TimeframeEnd   *time.Time  `query:"timeframe.end" json:"-"`
+	BucketWidth    BucketWidth `query:"bucket_width" json:"-"`
+	FillEmpty      bool        `query:"fill_empty" json:"-"`
+}
+
+type GetDeviceMetricAggregatesResponse struct {
+	Metrics []MetricAggregates `json:"metrics"`
+}
+
+func (h *EchoHandler) GetDeviceMetricAggregates(c echo.Context) error {
+	var req GetDeviceMetricAggregatesRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	res, err := h.svc.GetDeviceMetricAggregates(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+type GetDeviceAlertsRequest struct {
+	DeviceID       string        `param:"device_id" json:"-"`
+	TimeframeStart *time.Time    `query:"timeframe.start" json:"-"`
+	TimeframeEnd   *time.Time    `query:"timeframe.end" json:"-"`
+	PageSize       int           `query:"page.size" json:"-"`
+	PageToken      string        `query:"page.token" json:"-"`
+	State          AlertState    `query:"state" json:"-"`
+	Reasons        []AlertReason `query:"reason" json:"-"`
+	Order          SortOrder     `query:"order" json:"-"`
 }
 
 type GetDeviceAlertsResponse struct {
@@ -83,3 +723,312 @@ func (h *EchoHandler) GetDeviceAlerts(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, res)
 }
+
+type SearchAlertsRequest struct {
+	DeviceIDs      []string      `query:"device_id" json:"-"`
+	LabelSelector  string        `query:"label_selector" json:"-"`
+	Reasons        []AlertReason `query:"reason" json:"-"`
+	Severity       AlertSeverity `query:"severity" json:"-"`
+	State          AlertState    `query:"state" json:"-"`
+	TimeframeStart *time.Time    `query:"timeframe.start" json:"-"`
+	TimeframeEnd   *time.Time    `query:"timeframe.end" json:"-"`
+	PageSize       int           `query:"page.size" json:"-"`
+	PageToken      string        `query:"page.token" json:"-"`
+}
+
+type SearchAlertsResponse struct {
+	Alerts        []Alert `json:"alerts"`
+	NextPageToken string  `json:"next_page_token,omitempty"`
+}
+
+func (h *EchoHandler) SearchAlerts(c echo.Context) error {
+	var req SearchAlertsRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	res, err := h.svc.SearchAlerts(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+type GetAlertStatsRequest struct {
+	TimeframeStart *time.Time  `query:"timeframe.start" json:"-"`
+	TimeframeEnd   *time.Time  `query:"timeframe.end" json:"-"`
+	BucketWidth    BucketWidth `query:"bucket_width" json:"-"`
+	TopN           int         `query:"top_n" json:"-"`
+}
+
+type GetAlertStatsResponse struct {
+	Total      int64              `json:"total"`
+	ByReason   []ReasonAlertCount `json:"by_reason"`
+	ByDevice   []DeviceAlertCount `json:"by_device"`
+	Buckets    []AlertCountBucket `json:"buckets"`
+	TopDevices []DeviceAlertCount `json:"top_devices"`
+}
+
+func (h *EchoHandler) GetAlertStats(c echo.Context) error {
+	var req GetAlertStatsRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	res, err := h.svc.GetAlertStats(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+type CreateAlertRuleRequest struct {
+	DeviceID      string        `param:"device_id" json:"-"`
+	Metric        string        `json:"metric"`
+	Operator      RuleOperator  `json:"operator"`
+	Threshold     float64       `json:"threshold"`
+	ThresholdHigh float64       `json:"threshold_high"`
+	Severity      AlertSeverity `json:"severity"`
+	WindowSeconds int64         `json:"window_seconds"`
+}
+
+func (h *EchoHandler) CreateAlertRule(c echo.Context) error {
+	var req CreateAlertRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	rule, err := h.svc.CreateAlertRule(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, rule)
+}
+
+type AcknowledgeAlertRequest struct {
+	DeviceID       string `param:"device_id" json:"-"`
+	AlertID        int64  `param:"alert_id" json:"-"`
+	AcknowledgedBy string `json:"acknowledged_by"`
+	Comment        string `json:"comment"`
+}
+
+func (h *EchoHandler) AcknowledgeAlert(c echo.Context) error {
+	var req AcknowledgeAlertRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	alert, err := h.svc.AcknowledgeAlert(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, alert)
+}
+
+type ResolveAlertRequest struct {
+	DeviceID string `param:"device_id" json:"-"`
+	AlertID  int64  `param:"alert_id" json:"-"`
+}
+
+func (h *EchoHandler) ResolveAlert(c echo.Context) error {
+	var req ResolveAlertRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	alert, err := h.svc.ResolveAlert(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, alert)
+}
+
+type GetAlertRuleRequest struct {
+	DeviceID string `param:"device_id" json:"-"`
+	RuleID   int64  `param:"rule_id" json:"-"`
+}
+
+func (h *EchoHandler) GetAlertRule(c echo.Context) error {
+	var req GetAlertRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	rule, err := h.svc.GetAlertRule(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, rule)
+}
+
+type ListAlertRulesRequest struct {
+	DeviceID string `param:"device_id" json:"-"`
+}
+
+type ListAlertRulesResponse struct {
+	Rules []AlertRule `json:"rules"`
+}
+
+func (h *EchoHandler) ListAlertRules(c echo.Context) error {
+	var req ListAlertRulesRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	res, err := h.svc.ListAlertRules(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+type UpdateAlertRuleRequest struct {
+	DeviceID      string        `param:"device_id" json:"-"`
+	RuleID        int64         `param:"rule_id" json:"-"`
+	Metric        string        `json:"metric"`
+	Operator      RuleOperator  `json:"operator"`
+	Threshold     float64       `json:"threshold"`
+	ThresholdHigh float64       `json:"threshold_high"`
+	Severity      AlertSeverity `json:"severity"`
+	WindowSeconds int64         `json:"window_seconds"`
+}
+
+func (h *EchoHandler) UpdateAlertRule(c echo.Context) error {
+	var req UpdateAlertRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	rule, err := h.svc.UpdateAlertRule(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, rule)
+}
+
+type DeleteAlertRuleRequest struct {
+	DeviceID string `param:"device_id" json:"-"`
+	RuleID   int64  `param:"rule_id" json:"-"`
+}
+
+func (h *EchoHandler) DeleteAlertRule(c echo.Context) error {
+	var req DeleteAlertRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := h.svc.DeleteAlertRule(c.Request().Context(), req); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+type StreamAlertsRequest struct {
+	DeviceID string   `param:"device_id" json:"-"`
+	Reasons  []string `query:"reason" json:"-"`
+}
+
+// StreamAlerts streams alerts for a device, or for all devices if no device ID
+// is provided, as Server-Sent Events. Each event ID is the alert ID, so clients
+// can resume with the Last-Event-ID header after reconnecting.
+func (h *EchoHandler) StreamAlerts(c echo.Context) error {
+	var req StreamAlertsRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	svcReq := WatchDeviceAlertsRequest{
+		DeviceID: req.DeviceID,
+	}
+	for _, reason := range req.Reasons {
+		svcReq.Reasons = append(svcReq.Reasons, AlertReason(reason))
+	}
+	if lastEventID := c.Request().Header.Get("Last-Event-ID"); lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil {
+			return &ihttp.BadRequestError{FieldViolations: map[string][]string{
+				"Last-Event-ID": {"Must be an alert ID"},
+			}}
+		}
+		if svcReq.Cursor, err = encodeCursor(RepositoryPageToken{LastID: &id}); err != nil {
+			return err
+		}
+	}
+
+	w := &sseWriter{res: c.Response()}
+	ctx, cancel := context.WithCancel(c.Request().Context())
+
+	// stop sending keep-alives before returning since the response is reused
+	// by Echo once the handler returns
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// Send headers as soon as the stream is subscribed so that clients see the
+	// stream is open before the first alert arrives. Validation errors raised
+	// beforehand are still returned as regular error responses.
+	svcReq.Subscribed = func() error {
+		w.open()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(sseKeepAliveInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := w.comment("keep-alive"); err != nil {
+						return
+					}
+				}
+			}
+		}()
+		return nil
+	}
+
+	err := h.svc.WatchDeviceAlerts(ctx, svcReq, func(alert Alert, _ string) error {
+		data, err := json.Marshal(alert)
+		if err != nil {
+			return err
+		}
+		return w.event(strconv.FormatInt(alert.ID, 10), "alert", data)
+	})
+	if errors.Is(err, errAlertSubscriptionLagged) || errors.Is(err, errAlertBrokerClosed) {
+		// end the stream so the client reconnects with Last-Event-ID
+		return nil
+	}
+	return err
+}
+
+// sseWriter writes Server-Sent Events to a response. The stream must be opened
+// before any events are written.
+type sseWriter struct {
+	mu  sync.Mutex
+	res *echo.Response
+}
+
+// open writes the event stream headers and flushes them to the client.
+func (w *sseWriter) open() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.res.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.res.WriteHeader(http.StatusOK)
+	w.res.Flush()
+}
+
+func (w *sseWriter) event(id string, event string, data []byte) error {
+	return w.write(fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", id, event, data))
+}
+
+func (w *sseWriter) comment(comment string) error {
+	return w.write(fmt.Sprintf(": %s\n\n", comment))
+}
+
+func (w *sseWriter) write(msg string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.res.Write([]byte(msg)); err != nil {
+		return err
+	}
+	w.res.Flush()
+	return nil
+}
@@ -0,0 +1,223 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+
+	iotv1 "github.com/joshjon/iot-metrics/proto/gen/iot/v1"
+)
+
+const (
+	// prevVar is the CEL variable holding the values of the previous reading.
+	prevVar = "prev"
+	// expressionCostLimit bounds the evaluation cost of a single expression.
+	expressionCostLimit = 10000
+	// maxCachedExpressions is the number of compiled expressions cached before
+	// the cache is cleared.
+	maxCachedExpressions = 1024
+)
+
+// AlertExpression triggers an alert when a Common Expression Language (CEL)
+// expression evaluates to true for a metric reading. Values of the reading are
+// available as variables by metric name, e.g. temperature, and values of the
+// previous reading through the prev map, e.g. prev.temperature.
+type AlertExpression struct {
+	Expr     string
+	Severity AlertSeverity
+}
+
+func (e AlertExpression) Proto() *iotv1.AlertExpression {
+	return &iotv1.AlertExpression{
+		Expr:     e.Expr,
+		Severity: e.Severity.Proto(),
+	}
+}
+
+// Desc describes a match of the expression.
+func (e AlertExpression) Desc() string {
+	return fmt.Sprintf("Expression (%s) matched", e.Expr)
+}
+
+// compiledExpression is a type checked alert expression ready for evaluation.
+type compiledExpression struct {
+	AlertExpression
+	program     cel.Program
+	metrics     []string
+	prevMetrics []string
+	usesPrev    bool
+}
+
+// compileExpression parses and type checks an alert expression. Identifiers
+// that are valid metric names are declared as double variables, so that
+// expressions can reference any metric, and must evaluate to a bool. Integer
+// literals may be compared with metric values, e.g. temperature > 40.
+func compileExpression(expr AlertExpression) (*compiledExpression, error) {
+	env, err := cel.NewEnv(
+		cel.Variable(prevVar, cel.MapType(cel.StringType, cel.DoubleType)),
+		cel.CrossTypeNumericComparisons(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, iss := env.Parse(expr.Expr)
+	if iss.Err() != nil {
+		return nil, issuesError(iss)
+	}
+
+	idents, prevMetrics := referencedIdents(parsed.NativeRep().Expr())
+	compiled := &compiledExpression{
+		AlertExpression: expr,
+		prevMetrics:     prevMetrics,
+	}
+	var vars []cel.EnvOption
+	for _, name := range idents {
+		switch {
+		case name == prevVar:
+			compiled.usesPrev = true
+		case metricNameRegex.MatchString(name):
+			compiled.metrics = append(compiled.metrics, name)
+			vars = append(vars, cel.Variable(name, cel.DoubleType))
+		}
+	}
+	if env, err = env.Extend(vars...); err != nil {
+		return nil, err
+	}
+
+	checked, iss := env.Check(parsed)
+	if iss.Err() != nil {
+		return nil, issuesError(iss)
+	}
+	if !checked.OutputType().IsExactType(cel.BoolType) {
+		return nil, fmt.Errorf("must evaluate to a bool, got %s", checked.OutputType())
+	}
+
+	if compiled.program, err = env.Program(checked, cel.CostLimit(expressionCostLimit)); err != nil {
+		return nil, err
+	}
+	return compiled, nil
+}
+
+// eval evaluates the expression against a metric reading and the previous
+// reading, if any. Expressions referencing metrics missing from either reading
+// do not match.
+func (c *compiledExpression) eval(metric Metric, prev *Metric) (bool, error) {
+	vars := make(map[string]any, len(c.metrics)+1)
+	for _, name := range c.metrics {
+		v, ok := metric.Value(name)
+		if !ok {
+			return false, nil
+		}
+		vars[name] = v
+	}
+	if c.usesPrev {
+		if prev == nil {
+			return false, nil
+		}
+		for _, name := range c.prevMetrics {
+			if _, ok := prev.Value(name); !ok {
+				return false, nil
+			}
+		}
+		prevValues := make(map[string]float64, len(prev.Values))
+		for _, v := range prev.Values {
+			prevValues[v.Name] = v.Value
+		}
+		vars[prevVar] = prevValues
+	}
+
+	out, _, err := c.program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	matched, ok := out.Value().(bool)
+	return ok && matched, nil
+}
+
+// referencedIdents returns the unique identifiers referenced by an expression,
+// excluding variables declared by comprehensions such as exists or all, along
+// with the metrics selected from the previous reading, e.g. prev.temperature or
+// prev["temperature"].
+func referencedIdents(expr ast.Expr) (idents []string, prevMetrics []string) {
+	var iterVars []string
+	ast.PreOrderVisit(expr, ast.NewExprVisitor(func(e ast.Expr) {
+		switch e.Kind() {
+		case ast.IdentKind:
+			if name := e.AsIdent(); !slices.Contains(idents, name) {
+				idents = append(idents, name)
+			}
+		case ast.SelectKind:
+			sel := e.AsSelect()
+			// presence tests such as has(prev.temperature) handle missing metrics
+			if !sel.IsTestOnly() && sel.Operand().Kind() == ast.IdentKind && sel.Operand().AsIdent() == prevVar {
+				prevMetrics = append(prevMetrics, sel.FieldName())
+			}
+		case ast.CallKind:
+			call := e.AsCall()
+			args := call.Args()
+			if call.FunctionName() == operators.Index && len(args) == 2 &&
+				args[0].Kind() == ast.IdentKind && args[0].AsIdent() == prevVar &&
+				args[1].Kind() == ast.LiteralKind {
+				if name, ok := args[1].AsLiteral().Value().(string); ok {
+					prevMetrics = append(prevMetrics, name)
+				}
+			}
+		case ast.ComprehensionKind:
+			c := e.AsComprehension()
+			iterVars = append(iterVars, c.IterVar(), c.AccuVar())
+			if c.HasIterVar2() {
+				iterVars = append(iterVars, c.IterVar2())
+			}
+		}
+	}))
+	idents = slices.DeleteFunc(idents, func(name string) bool {
+		return slices.Contains(iterVars, name)
+	})
+	return idents, prevMetrics
+}
+
+func issuesError(iss *cel.Issues) error {
+	msgs := make([]string, len(iss.Errors()))
+	for i, e := range iss.Errors() {
+		msgs[i] = fmt.Sprintf("%d:%d: %s", e.Location.Line(), e.Location.Column()+1, e.Message)
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// expressionCache caches compiled alert expressions so that they are not
+// recompiled for every recorded metric.
+type expressionCache struct {
+	mu       sync.Mutex
+	compiled map[AlertExpression]*compiledExpression
+}
+
+func newExpressionCache() *expressionCache {
+	return &expressionCache{
+		compiled: make(map[AlertExpression]*compiledExpression),
+	}
+}
+
+func (c *expressionCache) compile(expr AlertExpression) (*compiledExpression, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if compiled, ok := c.compiled[expr]; ok {
+		return compiled, nil
+	}
+	compiled, err := compileExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.compiled) >= maxCachedExpressions {
+		clear(c.compiled)
+	}
+	c.compiled[expr] = compiled
+	return compiled, nil
+}
@@ -0,0 +1,142 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// offlineCondition identifies the condition of a device not recording metrics
+// within its reporting interval when tracking alerts.
+const offlineCondition = "offline"
+
+// OfflineDetector periodically triggers a DEVICE_OFFLINE alert for each device
+// that has not recorded a metric within its reporting interval times a grace
+// factor. Offline alerts are resolved once the device records a metric.
+type OfflineDetector struct {
+	svc         *Service
+	interval    time.Duration
+	graceFactor float64
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+// NewOfflineDetector creates an OfflineDetector that checks for offline
+// devices every interval.
+func NewOfflineDetector(svc *Service, interval time.Duration, graceFactor float64) *OfflineDetector {
+	return &OfflineDetector{
+		svc:         svc,
+		interval:    interval,
+		graceFactor: graceFactor,
+	}
+}
+
+// Start begins checking for offline devices in the background until Stop is
+// called. Devices are only considered silent from the time Start is called,
+// so that devices are not reported offline for the time the server was down.
+func (d *OfflineDetector) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	startedAt := time.Now().UTC()
+
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := d.svc.detectOfflineDevices(ctx, now.UTC(), startedAt, d.graceFactor); err != nil && ctx.Err() == nil {
+					d.svc.logger.Error("failed to detect offline devices", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops checking for offline devices, canceling any check in progress,
+// and waits for the background goroutine to exit.
+func (d *OfflineDetector) Stop() {
+	if d.cancel == nil {
+		return
+	}
+	d.cancel()
+	<-d.done
+}
+
+// detectOfflineDevices triggers a DEVICE_OFFLINE alert for each device with a
+// reporting interval that has not recorded a metric since its deadline. A
+// device is silent from its last metric or from since, whichever is later.
+func (s *Service) detectOfflineDevices(ctx context.Context, now time.Time, since time.Time, graceFactor float64) error {
+	devices, err := s.repo.ListReportingDevices(ctx)
+	if err != nil {
+		return fmt.Errorf("list reporting devices: %w", err)
+	}
+	for _, dev := range devices {
+		if err = s.detectOfflineDevice(ctx, dev, now, since, graceFactor); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logger.Error("failed to detect offline device", "device_id", dev.DeviceID, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) detectOfflineDevice(ctx context.Context, dev ReportingDevice, now time.Time, since time.Time, graceFactor float64) error {
+	silentFrom := dev.LastReported
+	if silentFrom.Before(since) {
+		silentFrom = since
+	}
+	grace := time.Duration(float64(dev.ReportingInterval) * graceFactor)
+	if now.Before(silentFrom.Add(grace)) {
+		return nil
+	}
+
+	unlock := s.devices.lock(dev.DeviceID)
+	defer unlock()
+
+	// An alert seen after the last metric already covers this silence, even if
+	// an operator has since resolved it.
+	latest, err := s.repo.GetLatestConditionAlert(ctx, dev.DeviceID, offlineCondition)
+	if err == nil && !latest.LastSeen.Before(dev.LastReported) {
+		return nil
+	}
+	if err != nil && !errors.Is(err, ErrRepoItemNotFound) {
+		return fmt.Errorf("get latest offline alert: %w", err)
+	}
+
+	alerting := &deviceAlerting{}
+	if alerting.cfg, err = s.repo.GetDeviceConfig(ctx, dev.DeviceID); err != nil {
+		return fmt.Errorf("get device config: %w", err)
+	}
+
+	desc := fmt.Sprintf("No metrics recorded, expected every %s", dev.ReportingInterval)
+	if !dev.LastReported.IsZero() {
+		desc = fmt.Sprintf("No metrics recorded since %s, expected every %s",
+			dev.LastReported.Format(time.RFC3339), dev.ReportingInterval)
+	}
+	alert := Alert{
+		Reason:    AlertReasonDeviceOffline,
+		Severity:  AlertSeverityWarning,
+		Desc:      desc,
+		Time:      now,
+		Condition: offlineCondition,
+	}
+
+	logger := s.logger.With("device_id", dev.DeviceID)
+	logger.Info("alert triggered",
+		"reason", alert.Reason,
+		"severity", alert.Severity,
+		"last_reported", dev.LastReported,
+		"reporting_interval", dev.ReportingInterval,
+	)
+	if err = s.raiseAlert(ctx, logger, dev.DeviceID, alerting, alert); err != nil {
+		return fmt.Errorf("save offline alert: %w", err)
+	}
+	return nil
+}
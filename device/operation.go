@@ -0,0 +1,204 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	iotv1 "github.com/joshjon/iot-metrics/proto/gen/iot/v1"
+)
+
+// purgeBatchSize is the maximum number of metrics or alerts deleted at a time
+// when purging the data of a deleted device, so that large histories do not
+// hold the database connection for long.
+const purgeBatchSize = 1000
+
+const (
+	// OperationTypeDeleteDevice purges the metrics and alerts of a deleted
+	// device.
+	OperationTypeDeleteDevice OperationType = "DELETE_DEVICE"
+)
+
+type OperationType string
+
+func (t OperationType) Proto() iotv1.Operation_Type {
+	switch t {
+	case OperationTypeDeleteDevice:
+		return iotv1.Operation_TYPE_DELETE_DEVICE
+	}
+	return iotv1.Operation_TYPE_UNSPECIFIED
+}
+
+const (
+	// OperationStateRunning is the state of an operation until it finishes,
+	// including while it is interrupted by the server shutting down.
+	OperationStateRunning OperationState = "RUNNING"
+	// OperationStateSucceeded is the state of an operation that finished.
+	OperationStateSucceeded OperationState = "SUCCEEDED"
+	// OperationStateFailed is the state of an operation that stopped on an
+	// error.
+	OperationStateFailed OperationState = "FAILED"
+)
+
+type OperationState string
+
+func (s OperationState) Proto() iotv1.Operation_State {
+	switch s {
+	case OperationStateRunning:
+		return iotv1.Operation_STATE_RUNNING
+	case OperationStateSucceeded:
+		return iotv1.Operation_STATE_SUCCEEDED
+	case OperationStateFailed:
+		return iotv1.Operation_STATE_FAILED
+	}
+	return iotv1.Operation_STATE_UNSPECIFIED
+}
+
+// Operation is a long-running operation started by a request, whose status is
+// tracked so that it can be polled.
+type Operation struct {
+	ID       int64
+	Type     OperationType
+	DeviceID string
+	State    OperationState
+	// DeletedMetrics and DeletedAlerts are the progress of a DELETE_DEVICE
+	// operation.
+	DeletedMetrics int64
+	DeletedAlerts  int64
+	// MaxMetricID and MaxAlertID are the IDs of the latest metric and alert of
+	// the device when it was deleted. Only data up to them is purged, so that
+	// data recorded after the device is registered again is kept.
+	MaxMetricID int64 `json:"-"`
+	MaxAlertID  int64 `json:"-"`
+	// Error is why the operation failed, or empty unless it has failed.
+	Error     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (o Operation) Proto() *iotv1.Operation {
+	return &iotv1.Operation{
+		Id:             o.ID,
+		Type:           o.Type.Proto(),
+		DeviceId:       o.DeviceID,
+		State:          o.State.Proto(),
+		DeletedMetrics: o.DeletedMetrics,
+		DeletedAlerts:  o.DeletedAlerts,
+		Error:          o.Error,
+		CreatedAt:      timestamppb.New(o.CreatedAt),
+		UpdatedAt:      timestamppb.New(o.UpdatedAt),
+	}
+}
+
+// operationRunner runs operations in the background until it is closed.
+type operationRunner struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newOperationRunner() *operationRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &operationRunner{ctx: ctx, cancel: cancel}
+}
+
+// run calls fn in the background with a context that is canceled on close.
+func (r *operationRunner) run(fn func(ctx context.Context)) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		fn(r.ctx)
+	}()
+}
+
+// close cancels running operations and waits for them to return. Interrupted
+// operations are left running, so that they can be resumed.
+func (r *operationRunner) close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// ResumeOperations restarts the operations that were interrupted by the server
+// shutting down. It should be called once on startup.
+func (s *Service) ResumeOperations(ctx context.Context) error {
+	ops, err := s.repo.ListOperations(ctx, OperationStateRunning)
+	if err != nil {
+		return fmt.Errorf("list running operations: %w", err)
+	}
+	for _, op := range ops {
+		s.logger.Info("resuming operation", "operation_id", op.ID, "type", op.Type, "device_id", op.DeviceID)
+		s.startPurge(op)
+	}
+	return nil
+}
+
+// startPurge purges the metrics and alerts of a deleted device in the
+// background, recording the progress of its operation after each batch.
+func (s *Service) startPurge(op Operation) {
+	s.operations.run(func(ctx context.Context) {
+		logger := s.logger.With("device_id", op.DeviceID, "operation_id", op.ID)
+
+		err := s.purgeDeviceData(ctx, &op)
+		if ctx.Err() != nil {
+			logger.Info("device purge interrupted",
+				"deleted_metrics", op.DeletedMetrics,
+				"deleted_alerts", op.DeletedAlerts,
+			)
+			return
+		}
+
+		op.State = OperationStateSucceeded
+		if err != nil {
+			logger.Error("failed to purge device data", "error", err)
+			op.State = OperationStateFailed
+			op.Error = "failed to purge device data"
+		}
+		op.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+		if err = s.repo.UpdateOperation(ctx, op); err != nil {
+			logger.Error("failed to update operation", "error", err)
+			return
+		}
+
+		logger.Info("purged device data",
+			"state", op.State,
+			"deleted_metrics", op.DeletedMetrics,
+			"deleted_alerts", op.DeletedAlerts,
+		)
+	})
+}
+
+// purgeDeviceData deletes the metrics and then the alerts of a device that were
+// recorded before it was deleted in batches until none are left.
+func (s *Service) purgeDeviceData(ctx context.Context, op *Operation) error {
+	purges := []struct {
+		name    string
+		delete  func(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error)
+		maxID   int64
+		deleted *int64
+	}{
+		{name: "metrics", delete: s.repo.DeleteDeviceMetrics, maxID: op.MaxMetricID, deleted: &op.DeletedMetrics},
+		{name: "alerts", delete: s.repo.DeleteDeviceAlerts, maxID: op.MaxAlertID, deleted: &op.DeletedAlerts},
+	}
+	for _, purge := range purges {
+		for {
+			n, err := purge.delete(ctx, op.DeviceID, purge.maxID, purgeBatchSize)
+			if err != nil {
+				return fmt.Errorf("delete device %s: %w", purge.name, err)
+			}
+			if n > 0 {
+				*purge.deleted += n
+				op.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+				if err = s.repo.UpdateOperation(ctx, *op); err != nil {
+					return fmt.Errorf("update operation: %w", err)
+				}
+			}
+			if n < purgeBatchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
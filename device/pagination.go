@@ -1,12 +1,189 @@
 package device
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/joshjon/iot-metrics/http"
 )
 
-func encodePageToken(tkn RepositoryPageToken) (string, error) {
-	b, err := json.Marshal(tkn)
+const defaultPageTokenTTL = time.Hour
+
+// pageTokens issues and verifies opaque page tokens. A token is the cursor of
+// the next page along with the scope of the request it was issued for and an
+// expiry, authenticated with an HMAC so that clients cannot forge cursors or
+// page a different device or filters with it.
+type pageTokens struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+// newPageTokens returns page tokens authenticated with the key, or with a
+// random key if the key is empty.
+func newPageTokens(key []byte, ttl time.Duration) *pageTokens {
+	if len(key) == 0 {
+		key = make([]byte, sha256.Size)
+		_, _ = rand.Read(key) // never returns an error
+	}
+	if ttl <= 0 {
+		ttl = defaultPageTokenTTL
+	}
+	return &pageTokens{
+		key: key,
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// pageScope identifies the request that a page token continues.
+type pageScope struct {
+	// Method is the name of the paginated request.
+	Method   string
+	DeviceID string
+	// Filters are the request parameters that must be the same for each page,
+	// other than the device ID and order.
+	Filters any
+}
+
+// signedPageToken is the payload of an encoded page token.
+type signedPageToken struct {
+	Cursor    RepositoryPageToken `json:"cursor"`
+	DeviceID  string              `json:"device_id,omitempty"`
+	Filters   []byte              `json:"filters"` // digest of the method and filters
+	ExpiresAt int64               `json:"expires_at"`
+}
+
+// pageOptions converts a requested page size and encoded page token into
+// repository page options, applying default and maximum page sizes. The page
+// token must have been issued for a request with the same scope and must not
+// have expired.
+func (p *pageTokens) pageOptions(pageSize int, pageToken string, scope pageScope) (RepositoryPageOptions, error) {
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	} else if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	opts := RepositoryPageOptions{Size: pageSize}
+	if pageToken != "" {
+		cursor, err := p.decode(pageToken, scope)
+		if err != nil {
+			return RepositoryPageOptions{}, err
+		}
+		opts.Token = &cursor
+	}
+
+	return opts, nil
+}
+
+// sortedPageOptions converts a requested page size, encoded page token and
+// order into repository page options like pageOptions, defaulting to
+// descending order. Page tokens from requests in a different order are
+// rejected, since their cursor continues in the other direction.
+func (p *pageTokens) sortedPageOptions(pageSize int, pageToken string, scope pageScope, order SortOrder) (RepositoryPageOptions, error) {
+	if order == "" {
+		order = SortOrderDesc
+	}
+
+	opts, err := p.pageOptions(pageSize, pageToken, scope)
+	if err != nil {
+		return RepositoryPageOptions{}, err
+	}
+	if opts.Token != nil && opts.Token.Order != order {
+		return RepositoryPageOptions{}, pageTokenError("Must be a page token from a request with the same order")
+	}
+	opts.Order = order
+
+	return opts, nil
+}
+
+// encode encodes the cursor of the next page of a request with the scope.
+func (p *pageTokens) encode(cursor RepositoryPageToken, scope pageScope) (string, error) {
+	filters, err := scope.digest()
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(signedPageToken{
+		Cursor:    cursor,
+		DeviceID:  scope.DeviceID,
+		Filters:   filters,
+		ExpiresAt: p.now().Add(p.ttl).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal page token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(append(p.sign(payload), payload...)), nil
+}
+
+func (p *pageTokens) decode(pageToken string, scope pageScope) (RepositoryPageToken, error) {
+	invalid := pageTokenError("Must be a page token from a previous response")
+
+	dec, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil || len(dec) < sha256.Size {
+		return RepositoryPageToken{}, invalid
+	}
+	mac, payload := dec[:sha256.Size], dec[sha256.Size:]
+	if !hmac.Equal(mac, p.sign(payload)) {
+		return RepositoryPageToken{}, invalid
+	}
+
+	var tkn signedPageToken
+	if err = json.Unmarshal(payload, &tkn); err != nil {
+		return RepositoryPageToken{}, invalid
+	}
+	if !p.now().Before(time.Unix(tkn.ExpiresAt, 0)) {
+		return RepositoryPageToken{}, pageTokenError("Must not be expired, request the first page again")
+	}
+	if tkn.DeviceID != scope.DeviceID {
+		return RepositoryPageToken{}, pageTokenError("Must be a page token from a request for the same device")
+	}
+	filters, err := scope.digest()
+	if err != nil {
+		return RepositoryPageToken{}, err
+	}
+	if !hmac.Equal(tkn.Filters, filters) {
+		return RepositoryPageToken{}, pageTokenError("Must be a page token from a request with the same filters")
+	}
+
+	return tkn.Cursor, nil
+}
+
+func (p *pageTokens) sign(payload []byte) []byte {
+	h := hmac.New(sha256.New, p.key)
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+// digest returns a digest of the method and filters of the scope.
+func (s pageScope) digest() ([]byte, error) {
+	b, err := json.Marshal(struct {
+		Method  string
+		Filters any
+	}{s.Method, s.Filters})
+	if err != nil {
+		return nil, fmt.Errorf("marshal page token filters: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
+
+func pageTokenError(msg string) error {
+	return &http.BadRequestError{FieldViolations: map[string][]string{
+		"page_token": {msg},
+	}}
+}
+
+// encodeCursor encodes a cursor that resumes an alert watch. Unlike page
+// tokens, cursors are not authenticated since they only reference an alert
+// ID, which clients may also resume from directly.
+func encodeCursor(cursor RepositoryPageToken) (string, error) {
+	b, err := json.Marshal(cursor)
 	if err != nil {
 		return "", err
 	}
@@ -14,16 +191,16 @@ func encodePageToken(tkn RepositoryPageToken) (string, error) {
 	return enc, nil
 }
 
-func decodePageToken(tkn string) (RepositoryPageToken, error) {
-	dec, err := base64.RawURLEncoding.DecodeString(tkn)
+func decodeCursor(cursor string) (RepositoryPageToken, error) {
+	dec, err := base64.RawURLEncoding.DecodeString(cursor)
 	if err != nil {
 		return RepositoryPageToken{}, err
 	}
 
-	var pageTkn RepositoryPageToken
-	if err = json.Unmarshal(dec, &pageTkn); err != nil {
+	var tkn RepositoryPageToken
+	if err = json.Unmarshal(dec, &tkn); err != nil {
 		return RepositoryPageToken{}, err
 	}
 
-	return pageTkn, nil
+	return tkn, nil
 }
@@ -11,7 +11,13 @@ import (
 	iotv1 "github.com/joshjon/iot-metrics/proto/gen/iot/v1"
 )
 
-const ErrRepoItemNotFound repoErr = "not found"
+const (
+	ErrRepoItemNotFound repoErr = "not found"
+	ErrRepoItemExists   repoErr = "already exists"
+	// ErrRepoRevisionMismatch is returned by conditional writes when the item
+	// is not at the expected revision.
+	ErrRepoRevisionMismatch repoErr = "revision mismatch"
+)
 
 type repoErr string
 
@@ -19,12 +25,129 @@ func (e repoErr) Error() string { return string(e) }
 
 // Repository defines the persistence layer for device data.
 type Repository interface {
-	UpsertDeviceConfig(ctx context.Context, deviceID string, config Config) error
+	// UpsertDeviceConfig appends a version of the config settings set for a
+	// device and makes it the current version, registering the device if it is
+	// not already registered. The version number is assigned by the
+	// repository and returned. If expectedRevision is not nil and is not the
+	// current version number (0 if the device has not been configured),
+	// ErrRepoRevisionMismatch is returned and nothing is written.
+	UpsertDeviceConfig(ctx context.Context, deviceID string, version DeviceConfigVersion, expectedRevision *int64) (int64, error)
+	// ListDeviceConfigVersions returns the versions of the config settings set
+	// for a device, most recent first.
+	ListDeviceConfigVersions(ctx context.Context, deviceID string, pageOpts RepositoryPageOptions) (RepositoryPage[DeviceConfigVersion], error)
+	GetDeviceConfigVersion(ctx context.Context, deviceID string, version int64) (DeviceConfigVersion, error)
+	// GetLatestDeviceConfigVersion returns the current version of the config
+	// settings set for a device, or ErrRepoItemNotFound if the device has not
+	// been configured.
+	GetLatestDeviceConfigVersion(ctx context.Context, deviceID string) (DeviceConfigVersion, error)
+	// UpsertDeviceGroupConfig replaces the config settings set for a device
+	// group.
+	UpsertDeviceGroupConfig(ctx context.Context, groupID string, settings ConfigSettings) error
+	// UpsertGlobalConfig replaces the global default config settings.
+	UpsertGlobalConfig(ctx context.Context, settings ConfigSettings) error
 	SaveDeviceMetric(ctx context.Context, deviceID string, metric Metric) error
+	SaveDeviceMetrics(ctx context.Context, deviceID string, metrics []Metric) error
 	GetDeviceMetrics(ctx context.Context, deviceID string, timeframe Timeframe, pageOpts RepositoryPageOptions) (RepositoryPage[Metric], error)
+	// GetDeviceMetricAggregates returns the aggregated values of each metric
+	// recorded by a device from start to end inclusive, ordered by name, in
+	// buckets of the width aligned to the unix epoch. Empty buckets are
+	// omitted.
+	GetDeviceMetricAggregates(ctx context.Context, deviceID string, start time.Time, end time.Time, width time.Duration) ([]MetricAggregates, error)
+	// GetMetricRange returns the lowest and highest values of a named metric
+	// recorded by a device from start inclusive to end exclusive, or
+	// ErrRepoItemNotFound if none were recorded.
+	GetMetricRange(ctx context.Context, deviceID string, name string, start time.Time, end time.Time) (MetricRange, error)
+	// GetDeviceConfig returns the effective config of a device, resolved from
+	// the settings of the device, its group and the global defaults, or
+	// ErrRepoItemNotFound if no settings apply to the device.
 	GetDeviceConfig(ctx context.Context, deviceID string) (Config, error)
-	SaveDeviceAlert(ctx context.Context, deviceID string, alert Alert) error
-	GetDeviceAlerts(ctx context.Context, deviceID string, timeframe Timeframe, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error)
+	SaveDeviceAlert(ctx context.Context, deviceID string, alert Alert) (int64, error)
+	GetDeviceAlerts(ctx context.Context, deviceID string, timeframe Timeframe, filter AlertFilter, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error)
+	// SearchAlerts returns alerts across all devices, or the devices with the
+	// IDs if any are provided, whose devices meet the label selector.
+	SearchAlerts(ctx context.Context, deviceIDs []string, selector LabelSelector, timeframe Timeframe, filter AlertFilter, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error)
+	GetDeviceAlert(ctx context.Context, deviceID string, alertID int64) (Alert, error)
+	// GetAlertStats counts the alerts of all devices triggered within a
+	// timeframe, including the topN devices that triggered the most alerts.
+	GetAlertStats(ctx context.Context, start time.Time, end time.Time, width time.Duration, topN int) (AlertStats, error)
+	// AcknowledgeDeviceAlert acknowledges an open alert, returning
+	// ErrRepoItemNotFound if no open alert exists with the ID.
+	AcknowledgeDeviceAlert(ctx context.Context, deviceID string, alertID int64, by string, comment string, at time.Time) error
+	// ResolveDeviceAlert resolves an unresolved alert, returning
+	// ErrRepoItemNotFound if no unresolved alert exists with the ID.
+	ResolveDeviceAlert(ctx context.Context, deviceID string, alertID int64, at time.Time) error
+	// GetLatestConditionAlert returns the most recent alert triggered by a
+	// condition, or ErrRepoItemNotFound if none exists.
+	GetLatestConditionAlert(ctx context.Context, deviceID string, condition string) (Alert, error)
+	// RecordAlertOccurrence increments the occurrences of an alert and updates
+	// when it was last seen.
+	RecordAlertOccurrence(ctx context.Context, deviceID string, alertID int64, at time.Time) error
+	// ResolveDeviceAlertsByCondition resolves all unresolved alerts triggered by
+	// any of the conditions and returns the number of alerts resolved.
+	ResolveDeviceAlertsByCondition(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error)
+	// GetAlertsAfterID returns up to limit alerts with an ID greater than
+	// afterID in ascending ID order. Alerts for all devices are returned if
+	// deviceID is empty.
+	GetAlertsAfterID(ctx context.Context, deviceID string, afterID int64, limit int) ([]Alert, error)
+	CreateAlertRule(ctx context.Context, deviceID string, rule AlertRule) (int64, error)
+	GetAlertRule(ctx context.Context, deviceID string, ruleID int64) (AlertRule, error)
+	ListAlertRules(ctx context.Context, deviceID string) ([]AlertRule, error)
+	UpdateAlertRule(ctx context.Context, deviceID string, rule AlertRule) error
+	DeleteAlertRule(ctx context.Context, deviceID string, ruleID int64) error
+	// GetConditionStates returns the debouncing state of each tracked condition of
+	// a device, keyed by condition.
+	GetConditionStates(ctx context.Context, deviceID string) (map[string]ConditionState, error)
+	SaveConditionStates(ctx context.Context, deviceID string, states map[string]ConditionState) error
+	// ListReportingDevices returns the devices with an effective reporting
+	// interval along with when they last recorded a metric.
+	ListReportingDevices(ctx context.Context) ([]ReportingDevice, error)
+	// CreateDevice registers a device, returning ErrRepoItemExists if a device
+	// with the same ID is already registered.
+	CreateDevice(ctx context.Context, device Device) error
+	GetDevice(ctx context.Context, deviceID string) (Device, error)
+	// UpdateDevice replaces the metadata, labels and group of a registered
+	// device.
+	UpdateDevice(ctx context.Context, device Device) error
+	// ListDevices returns registered devices matching the label selector,
+	// most recently created first.
+	ListDevices(ctx context.Context, selector LabelSelector, pageOpts RepositoryPageOptions) (RepositoryPage[Device], error)
+	// CreateDeviceGroup creates a device group, returning ErrRepoItemExists if
+	// a group with the same ID already exists.
+	CreateDeviceGroup(ctx context.Context, group DeviceGroup) error
+	GetDeviceGroup(ctx context.Context, groupID string) (DeviceGroup, error)
+	ListDeviceGroups(ctx context.Context) ([]DeviceGroup, error)
+	// GetFleetSnapshot returns the latest state of the registered devices that
+	// match the label selector and, if groupID is not empty, belong to the
+	// group, most recently created first.
+	GetFleetSnapshot(ctx context.Context, selector LabelSelector, groupID string, pageOpts RepositoryPageOptions) (RepositoryPage[DeviceSnapshot], error)
+	// DeleteDevice unregisters a device and deletes its config, config
+	// versions, alert rules and condition states, returning ErrRepoItemNotFound
+	// if the device is not registered. The operation is created in the same
+	// transaction to track the purge of the device's remaining data, and is
+	// returned with its ID and the IDs of the device's latest metric and alert
+	// set.
+	DeleteDevice(ctx context.Context, deviceID string, op Operation) (Operation, error)
+	// DeleteDeviceMetrics deletes up to limit of the oldest metrics of a device
+	// with IDs up to maxID, returning the number deleted.
+	DeleteDeviceMetrics(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error)
+	// DeleteDeviceAlerts deletes up to limit of the oldest alerts of a device
+	// with IDs up to maxID, returning the number deleted.
+	DeleteDeviceAlerts(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error)
+	GetOperation(ctx context.Context, id int64) (Operation, error)
+	// UpdateOperation updates the state, progress and error of an operation.
+	UpdateOperation(ctx context.Context, op Operation) error
+	// ListOperations returns the operations in a state, oldest first.
+	ListOperations(ctx context.Context, state OperationState) ([]Operation, error)
+}
+
+// ReportingDevice is a device that is expected to record metrics at an
+// interval.
+type ReportingDevice struct {
+	DeviceID          string
+	ReportingInterval time.Duration
+	// LastReported is the time of the device's most recent metric, or zero if
+	// it has not recorded any.
+	LastReported time.Time
 }
 
 // RepositoryPageOptions specifies pagination parameters when querying
@@ -32,12 +155,44 @@ type Repository interface {
 type RepositoryPageOptions struct {
 	Size  int
 	Token *RepositoryPageToken
+	// Order is the order of the data by time, descending unless SortOrderAsc.
+	Order SortOrder
 }
 
 // RepositoryPageToken represents a cursor used to paginate through time-series data.
 type RepositoryPageToken struct {
 	LastTime *time.Time
 	LastID   *int64
+	// Order is the order of the page that the cursor continues from.
+	Order SortOrder
+}
+
+const (
+	SortOrderDesc SortOrder = "desc"
+	SortOrderAsc  SortOrder = "asc"
+)
+
+// SortOrder is the order that time-series data is returned in by time.
+type SortOrder string
+
+func (o SortOrder) Proto() iotv1.SortOrder {
+	switch o {
+	case SortOrderDesc:
+		return iotv1.SortOrder_SORT_ORDER_DESC
+	case SortOrderAsc:
+		return iotv1.SortOrder_SORT_ORDER_ASC
+	}
+	return iotv1.SortOrder_SORT_ORDER_UNSPECIFIED
+}
+
+func sortOrderFromProto(o iotv1.SortOrder) (SortOrder, bool) {
+	switch o {
+	case iotv1.SortOrder_SORT_ORDER_DESC:
+		return SortOrderDesc, true
+	case iotv1.SortOrder_SORT_ORDER_ASC:
+		return SortOrderAsc, true
+	}
+	return "", false
 }
 
 // RepositoryPage is a page of results along with a token to retrieve the next page.
@@ -49,31 +204,260 @@ type RepositoryPage[T any] struct {
 type Config struct {
 	TemperatureThreshold float64
 	BatteryThreshold     int32
+	Expressions          []AlertExpression
+	// ConsecutiveBreaches is the number of consecutive readings that must
+	// breach a condition before an alert is triggered.
+	ConsecutiveBreaches int32
+	// Hysteresis is the margin by which a value must return within the
+	// threshold of a triggered rule before it clears and can trigger again.
+	Hysteresis float64
+	// Cooldowns suppress new alerts of a reason for a window after an alert of
+	// the same rule or expression is resolved.
+	Cooldowns []AlertCooldown
+	// ReportingInterval is the expected interval between metrics of the
+	// device, or 0 if the device is not checked for being offline.
+	ReportingInterval time.Duration
+	// Sources is the level that each setting was resolved from. Settings that
+	// are not set at any level hold their zero value and have no source.
+	Sources map[ConfigSetting]ConfigLevel
+	// Version is the current version of the settings set for the device, or 0
+	// if the device has not been configured.
+	Version int64
+}
+
+// AlertCooldown is the window after an alert with the reason is resolved in
+// which the same rule or expression cannot trigger a new alert.
+type AlertCooldown struct {
+	Reason AlertReason
+	Window time.Duration
 }
 
+// Cooldown returns the cooldown window of alerts with the reason, or 0 if none
+// is configured.
+func (c Config) Cooldown(reason AlertReason) time.Duration {
+	for _, cd := range c.Cooldowns {
+		if cd.Reason == reason {
+			return cd.Window
+		}
+	}
+	return 0
+}
+
+// Debounces reports whether alerts are debounced, in which case a condition
+// triggers a single alert until it clears rather than an alert per breaching
+// reading.
+func (c Config) Debounces() bool {
+	return c.ConsecutiveBreaches > 0 || c.Hysteresis > 0
+}
+
+// ConditionState tracks whether a condition of a device, such as an alert rule,
+// is breaching when alerts are debounced.
+type ConditionState struct {
+	// Breaches is the number of consecutive readings that breached the
+	// condition.
+	Breaches int32
+	// Active is whether an alert has been triggered for the condition and it
+	// has not yet cleared.
+	Active bool
+}
+
+// Rules returns the alert rules derived from the config thresholds that are
+// set, which are evaluated alongside any alert rules created for the device.
+func (c Config) Rules() []AlertRule {
+	var rules []AlertRule
+	if c.IsSet(SettingTemperatureThreshold) {
+		rules = append(rules, AlertRule{
+			Metric:    MetricTemperature,
+			Operator:  RuleOperatorGreaterThan,
+			Threshold: c.TemperatureThreshold,
+			Severity:  AlertSeverityWarning,
+		})
+	}
+	if c.IsSet(SettingBatteryThreshold) {
+		rules = append(rules, AlertRule{
+			Metric:    MetricBattery,
+			Operator:  RuleOperatorLessThan,
+			Threshold: float64(c.BatteryThreshold),
+			Severity:  AlertSeverityWarning,
+		})
+	}
+	return rules
+}
+
+const (
+	MetricTemperature = "temperature"
+	MetricBattery     = "battery"
+)
+
+// Metric is a reading of one or more named metric values taken by a device at
+// a point in time.
 type Metric struct {
-	Temperature float64
-	Battery     int32
-	Time        time.Time
+	Values []MetricValue
+	Time   time.Time
+}
+
+// Value returns the value of the named metric if it is present in the reading.
+func (m Metric) Value(name string) (float64, bool) {
+	for _, v := range m.Values {
+		if v.Name == name {
+			return v.Value, true
+		}
+	}
+	return 0, false
+}
+
+func (m Metric) Proto() *iotv1.Metric {
+	pb := &iotv1.Metric{
+		Timestamp: timestamppb.New(m.Time),
+		Values:    make([]*iotv1.MetricValue, len(m.Values)),
+	}
+	for i, v := range m.Values {
+		pb.Values[i] = v.Proto()
+	}
+	// populate deprecated fields for backwards compatibility
+	if temp, ok := m.Value(MetricTemperature); ok {
+		pb.Temperature = temp
+	}
+	if battery, ok := m.Value(MetricBattery); ok {
+		pb.Battery = int32(battery)
+	}
+	return pb
+}
+
+// MetricRange is the lowest and highest values of a metric recorded within a
+// timeframe.
+type MetricRange struct {
+	Low  float64
+	High float64
 }
 
+// MetricValue is a single named numeric value within a metric reading.
+type MetricValue struct {
+	Name  string
+	Value float64
+	Unit  string
+}
+
+func (v MetricValue) Proto() *iotv1.MetricValue {
+	return &iotv1.MetricValue{
+		Name:  v.Name,
+		Value: v.Value,
+		Unit:  v.Unit,
+	}
+}
+
+// Alert is a triggered alert. Alerts are serialized as is in REST responses,
+// so each field is tagged with its JSON key, which is the field name.
 type Alert struct {
-	Reason AlertReason
-	Desc   string
-	Time   time.Time
+	// ID identifies the alert when acknowledging or resolving it, and is the
+	// event ID of alert streams.
+	ID       int64  `json:"ID"`
+	DeviceID string `json:"DeviceID"`
+	// RuleID is the alert rule that triggered the alert, or 0 if it was
+	// triggered by the device config thresholds.
+	RuleID   int64         `json:"RuleID"`
+	Reason   AlertReason   `json:"Reason"`
+	Severity AlertSeverity `json:"Severity"`
+	Desc     string        `json:"Desc"`
+	// Time is when the alert was first seen.
+	Time  time.Time  `json:"Time"`
+	State AlertState `json:"State"`
+	// Occurrences is the number of times the alert's rule or expression
+	// triggered while the alert was unresolved.
+	Occurrences int64     `json:"Occurrences"`
+	LastSeen    time.Time `json:"LastSeen"`
+	// ConfigVersion is the version of the device config the alert was
+	// evaluated against, or 0 if the device had not been configured.
+	ConfigVersion int64 `json:"ConfigVersion"`
+	// Condition identifies the alert rule or expression that triggered the
+	// alert, so that the alert can be resolved once the condition clears.
+	Condition      string     `json:"-"`
+	AcknowledgedBy string     `json:"AcknowledgedBy"`
+	AckComment     string     `json:"AckComment"`
+	AcknowledgedAt *time.Time `json:"AcknowledgedAt"`
+	ResolvedAt     *time.Time `json:"ResolvedAt"`
 }
 
 func (a Alert) Proto() *iotv1.Alert {
-	return &iotv1.Alert{
-		Reason:      a.Reason.Proto(),
-		Description: a.Desc,
-		Timestamp:   timestamppb.New(a.Time),
+	alertpb := &iotv1.Alert{
+		Id:             a.ID,
+		DeviceId:       a.DeviceID,
+		RuleId:         a.RuleID,
+		Reason:         a.Reason.Proto(),
+		Severity:       a.Severity.Proto(),
+		Description:    a.Desc,
+		Timestamp:      timestamppb.New(a.Time),
+		State:          a.State.Proto(),
+		Occurrences:    a.Occurrences,
+		LastSeen:       timestamppb.New(a.LastSeen),
+		ConfigVersion:  a.ConfigVersion,
+		AcknowledgedBy: a.AcknowledgedBy,
+		AckComment:     a.AckComment,
+	}
+	if a.AcknowledgedAt != nil {
+		alertpb.AcknowledgedAt = timestamppb.New(*a.AcknowledgedAt)
 	}
+	if a.ResolvedAt != nil {
+		alertpb.ResolvedAt = timestamppb.New(*a.ResolvedAt)
+	}
+	return alertpb
+}
+
+// AlertFilter restricts the alerts returned by the repository. Zero value
+// fields do not filter.
+type AlertFilter struct {
+	State    AlertState
+	Severity AlertSeverity
+	// Reasons restricts alerts to any of the reasons.
+	Reasons []AlertReason
 }
 
 const (
-	AlertReasonTemperatureHigh AlertReason = "TEMPERATURE_HIGH"
-	AlertReasonBatteryLow      AlertReason = "BATTERY_LOW"
+	// AlertStateOpen is the state of a triggered alert until it is
+	// acknowledged or resolved.
+	AlertStateOpen AlertState = "OPEN"
+	// AlertStateAcknowledged is the state of an alert an operator has
+	// acknowledged, but whose condition has not yet cleared.
+	AlertStateAcknowledged AlertState = "ACKNOWLEDGED"
+	// AlertStateResolved is the state of an alert whose condition has cleared
+	// or that an operator has resolved.
+	AlertStateResolved AlertState = "RESOLVED"
+)
+
+type AlertState string
+
+func (s AlertState) Proto() iotv1.Alert_State {
+	switch s {
+	case AlertStateOpen:
+		return iotv1.Alert_STATE_OPEN
+	case AlertStateAcknowledged:
+		return iotv1.Alert_STATE_ACKNOWLEDGED
+	case AlertStateResolved:
+		return iotv1.Alert_STATE_RESOLVED
+	}
+	return iotv1.Alert_STATE_UNSPECIFIED
+}
+
+func alertStateFromProto(s iotv1.Alert_State) (AlertState, bool) {
+	switch s {
+	case iotv1.Alert_STATE_OPEN:
+		return AlertStateOpen, true
+	case iotv1.Alert_STATE_ACKNOWLEDGED:
+		return AlertStateAcknowledged, true
+	case iotv1.Alert_STATE_RESOLVED:
+		return AlertStateResolved, true
+	}
+	return "", false
+}
+
+const (
+	AlertReasonTemperatureHigh   AlertReason = "TEMPERATURE_HIGH"
+	AlertReasonBatteryLow        AlertReason = "BATTERY_LOW"
+	AlertReasonThresholdBreached AlertReason = "THRESHOLD_BREACHED"
+	AlertReasonExpressionMatched AlertReason = "EXPRESSION_MATCHED"
+	AlertReasonRapidRise         AlertReason = "RAPID_RISE"
+	AlertReasonRapidDrop         AlertReason = "RAPID_DROP"
+	AlertReasonDeviceOffline     AlertReason = "DEVICE_OFFLINE"
 )
 
 type AlertReason string
@@ -84,10 +468,40 @@ func (r AlertReason) Proto() iotv1.Alert_Reason {
 		return iotv1.Alert_REASON_TEMPERATURE_HIGH
 	case AlertReasonBatteryLow:
 		return iotv1.Alert_REASON_BATTERY_LOW
+	case AlertReasonThresholdBreached:
+		return iotv1.Alert_REASON_THRESHOLD_BREACHED
+	case AlertReasonExpressionMatched:
+		return iotv1.Alert_REASON_EXPRESSION_MATCHED
+	case AlertReasonRapidRise:
+		return iotv1.Alert_REASON_RAPID_RISE
+	case AlertReasonRapidDrop:
+		return iotv1.Alert_REASON_RAPID_DROP
+	case AlertReasonDeviceOffline:
+		return iotv1.Alert_REASON_DEVICE_OFFLINE
 	}
 	return iotv1.Alert_REASON_UNSPECIFIED
 }
 
+func alertReasonFromProto(r iotv1.Alert_Reason) (AlertReason, bool) {
+	switch r {
+	case iotv1.Alert_REASON_TEMPERATURE_HIGH:
+		return AlertReasonTemperatureHigh, true
+	case iotv1.Alert_REASON_BATTERY_LOW:
+		return AlertReasonBatteryLow, true
+	case iotv1.Alert_REASON_THRESHOLD_BREACHED:
+		return AlertReasonThresholdBreached, true
+	case iotv1.Alert_REASON_EXPRESSION_MATCHED:
+		return AlertReasonExpressionMatched, true
+	case iotv1.Alert_REASON_RAPID_RISE:
+		return AlertReasonRapidRise, true
+	case iotv1.Alert_REASON_RAPID_DROP:
+		return AlertReasonRapidDrop, true
+	case iotv1.Alert_REASON_DEVICE_OFFLINE:
+		return AlertReasonDeviceOffline, true
+	}
+	return "", false
+}
+
 type Timeframe struct {
 	Start *time.Time
 	End   *time.Time
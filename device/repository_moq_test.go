@@ -6,6 +6,7 @@ package device
 import (
 	"context"
 	"sync"
+	"time"
 )
 
 // Ensure, that RepositoryMock does implement Repository.
@@ -18,24 +19,141 @@ var _ Repository = &RepositoryMock{}
 //
 //		// make and configure a mocked Repository
 //		mockedRepository := &RepositoryMock{
-//			GetDeviceAlertsFunc: func(ctx context.Context, deviceID string, timeframe Timeframe, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error) {
+//			AcknowledgeDeviceAlertFunc: func(ctx context.Context, deviceID string, alertID int64, by string, comment string, at time.Time) error {
+//				panic("mock out the AcknowledgeDeviceAlert method")
+//			},
+//			CreateAlertRuleFunc: func(ctx context.Context, deviceID string, rule AlertRule) (int64, error) {
+//				panic("mock out the CreateAlertRule method")
+//			},
+//			CreateDeviceFunc: func(ctx context.Context, device Device) error {
+//				panic("mock out the CreateDevice method")
+//			},
+//			CreateDeviceGroupFunc: func(ctx context.Context, group DeviceGroup) error {
+//				panic("mock out the CreateDeviceGroup method")
+//			},
+//			DeleteAlertRuleFunc: func(ctx context.Context, deviceID string, ruleID int64) error {
+//				panic("mock out the DeleteAlertRule method")
+//			},
+//			DeleteDeviceFunc: func(ctx context.Context, deviceID string, op Operation) (Operation, error) {
+//				panic("mock out the DeleteDevice method")
+//			},
+//			DeleteDeviceAlertsFunc: func(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error) {
+//				panic("mock out the DeleteDeviceAlerts method")
+//			},
+//			DeleteDeviceMetricsFunc: func(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error) {
+//				panic("mock out the DeleteDeviceMetrics method")
+//			},
+//			GetAlertRuleFunc: func(ctx context.Context, deviceID string, ruleID int64) (AlertRule, error) {
+//				panic("mock out the GetAlertRule method")
+//			},
+//			GetAlertStatsFunc: func(ctx context.Context, start time.Time, end time.Time, width time.Duration, topN int) (AlertStats, error) {
+//				panic("mock out the GetAlertStats method")
+//			},
+//			GetAlertsAfterIDFunc: func(ctx context.Context, deviceID string, afterID int64, limit int) ([]Alert, error) {
+//				panic("mock out the GetAlertsAfterID method")
+//			},
+//			GetConditionStatesFunc: func(ctx context.Context, deviceID string) (map[string]ConditionState, error) {
+//				panic("mock out the GetConditionStates method")
+//			},
+//			GetDeviceFunc: func(ctx context.Context, deviceID string) (Device, error) {
+//				panic("mock out the GetDevice method")
+//			},
+//			GetDeviceAlertFunc: func(ctx context.Context, deviceID string, alertID int64) (Alert, error) {
+//				panic("mock out the GetDeviceAlert method")
+//			},
+//			GetDeviceAlertsFunc: func(ctx context.Context, deviceID string, timeframe Timeframe, filter AlertFilter, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error) {
 //				panic("mock out the GetDeviceAlerts method")
 //			},
 //			GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
 //				panic("mock out the GetDeviceConfig method")
 //			},
+//			GetDeviceConfigVersionFunc: func(ctx context.Context, deviceID string, version int64) (DeviceConfigVersion, error) {
+//				panic("mock out the GetDeviceConfigVersion method")
+//			},
+//			GetDeviceGroupFunc: func(ctx context.Context, groupID string) (DeviceGroup, error) {
+//				panic("mock out the GetDeviceGroup method")
+//			},
+//			GetDeviceMetricAggregatesFunc: func(ctx context.Context, deviceID string, start time.Time, end time.Time, width time.Duration) ([]MetricAggregates, error) {
+//				panic("mock out the GetDeviceMetricAggregates method")
+//			},
 //			GetDeviceMetricsFunc: func(ctx context.Context, deviceID string, timeframe Timeframe, pageOpts RepositoryPageOptions) (RepositoryPage[Metric], error) {
 //				panic("mock out the GetDeviceMetrics method")
 //			},
-//			SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) error {
+//			GetFleetSnapshotFunc: func(ctx context.Context, selector LabelSelector, groupID string, pageOpts RepositoryPageOptions) (RepositoryPage[DeviceSnapshot], error) {
+//				panic("mock out the GetFleetSnapshot method")
+//			},
+//			GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+//				panic("mock out the GetLatestConditionAlert method")
+//			},
+//			GetLatestDeviceConfigVersionFunc: func(ctx context.Context, deviceID string) (DeviceConfigVersion, error) {
+//				panic("mock out the GetLatestDeviceConfigVersion method")
+//			},
+//			GetMetricRangeFunc: func(ctx context.Context, deviceID string, name string, start time.Time, end time.Time) (MetricRange, error) {
+//				panic("mock out the GetMetricRange method")
+//			},
+//			GetOperationFunc: func(ctx context.Context, id int64) (Operation, error) {
+//				panic("mock out the GetOperation method")
+//			},
+//			ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+//				panic("mock out the ListAlertRules method")
+//			},
+//			ListDeviceConfigVersionsFunc: func(ctx context.Context, deviceID string, pageOpts RepositoryPageOptions) (RepositoryPage[DeviceConfigVersion], error) {
+//				panic("mock out the ListDeviceConfigVersions method")
+//			},
+//			ListDeviceGroupsFunc: func(ctx context.Context) ([]DeviceGroup, error) {
+//				panic("mock out the ListDeviceGroups method")
+//			},
+//			ListDevicesFunc: func(ctx context.Context, selector LabelSelector, pageOpts RepositoryPageOptions) (RepositoryPage[Device], error) {
+//				panic("mock out the ListDevices method")
+//			},
+//			ListOperationsFunc: func(ctx context.Context, state OperationState) ([]Operation, error) {
+//				panic("mock out the ListOperations method")
+//			},
+//			ListReportingDevicesFunc: func(ctx context.Context) ([]ReportingDevice, error) {
+//				panic("mock out the ListReportingDevices method")
+//			},
+//			RecordAlertOccurrenceFunc: func(ctx context.Context, deviceID string, alertID int64, at time.Time) error {
+//				panic("mock out the RecordAlertOccurrence method")
+//			},
+//			ResolveDeviceAlertFunc: func(ctx context.Context, deviceID string, alertID int64, at time.Time) error {
+//				panic("mock out the ResolveDeviceAlert method")
+//			},
+//			ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+//				panic("mock out the ResolveDeviceAlertsByCondition method")
+//			},
+//			SaveConditionStatesFunc: func(ctx context.Context, deviceID string, states map[string]ConditionState) error {
+//				panic("mock out the SaveConditionStates method")
+//			},
+//			SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
 //				panic("mock out the SaveDeviceAlert method")
 //			},
 //			SaveDeviceMetricFunc: func(ctx context.Context, deviceID string, metric Metric) error {
 //				panic("mock out the SaveDeviceMetric method")
 //			},
-//			UpsertDeviceConfigFunc: func(ctx context.Context, deviceID string, config Config) error {
+//			SaveDeviceMetricsFunc: func(ctx context.Context, deviceID string, metrics []Metric) error {
+//				panic("mock out the SaveDeviceMetrics method")
+//			},
+//			SearchAlertsFunc: func(ctx context.Context, deviceIDs []string, selector LabelSelector, timeframe Timeframe, filter AlertFilter, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error) {
+//				panic("mock out the SearchAlerts method")
+//			},
+//			UpdateAlertRuleFunc: func(ctx context.Context, deviceID string, rule AlertRule) error {
+//				panic("mock out the UpdateAlertRule method")
+//			},
+//			UpdateDeviceFunc: func(ctx context.Context, device Device) error {
+//				panic("mock out the UpdateDevice method")
+//			},
+//			UpdateOperationFunc: func(ctx context.Context, op Operation) error {
+//				panic("mock out the UpdateOperation method")
+//			},
+//			UpsertDeviceConfigFunc: func(ctx context.Context, deviceID string, version DeviceConfigVersion, expectedRevision *int64) (int64, error) {
 //				panic("mock out the UpsertDeviceConfig method")
 //			},
+//			UpsertDeviceGroupConfigFunc: func(ctx context.Context, groupID string, settings ConfigSettings) error {
+//				panic("mock out the UpsertDeviceGroupConfig method")
+//			},
+//			UpsertGlobalConfigFunc: func(ctx context.Context, settings ConfigSettings) error {
+//				panic("mock out the UpsertGlobalConfig method")
+//			},
 //		}
 //
 //		// use mockedRepository in code that requires Repository
@@ -43,26 +161,277 @@ var _ Repository = &RepositoryMock{}
 //
 //	}
 type RepositoryMock struct {
+	// AcknowledgeDeviceAlertFunc mocks the AcknowledgeDeviceAlert method.
+	AcknowledgeDeviceAlertFunc func(ctx context.Context, deviceID string, alertID int64, by string, comment string, at time.Time) error
+
+	// CreateAlertRuleFunc mocks the CreateAlertRule method.
+	CreateAlertRuleFunc func(ctx context.Context, deviceID string, rule AlertRule) (int64, error)
+
+	// CreateDeviceFunc mocks the CreateDevice method.
+	CreateDeviceFunc func(ctx context.Context, device Device) error
+
+	// CreateDeviceGroupFunc mocks the CreateDeviceGroup method.
+	CreateDeviceGroupFunc func(ctx context.Context, group DeviceGroup) error
+
+	// DeleteAlertRuleFunc mocks the DeleteAlertRule method.
+	DeleteAlertRuleFunc func(ctx context.Context, deviceID string, ruleID int64) error
+
+	// DeleteDeviceFunc mocks the DeleteDevice method.
+	DeleteDeviceFunc func(ctx context.Context, deviceID string, op Operation) (Operation, error)
+
+	// DeleteDeviceAlertsFunc mocks the DeleteDeviceAlerts method.
+	DeleteDeviceAlertsFunc func(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error)
+
+	// DeleteDeviceMetricsFunc mocks the DeleteDeviceMetrics method.
+	DeleteDeviceMetricsFunc func(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error)
+
+	// GetAlertRuleFunc mocks the GetAlertRule method.
+	GetAlertRuleFunc func(ctx context.Context, deviceID string, ruleID int64) (AlertRule, error)
+
+	// GetAlertStatsFunc mocks the GetAlertStats method.
+	GetAlertStatsFunc func(ctx context.Context, start time.Time, end time.Time, width time.Duration, topN int) (AlertStats, error)
+
+	// GetAlertsAfterIDFunc mocks the GetAlertsAfterID method.
+	GetAlertsAfterIDFunc func(ctx context.Context, deviceID string, afterID int64, limit int) ([]Alert, error)
+
+	// GetConditionStatesFunc mocks the GetConditionStates method.
+	GetConditionStatesFunc func(ctx context.Context, deviceID string) (map[string]ConditionState, error)
+
+	// GetDeviceFunc mocks the GetDevice method.
+	GetDeviceFunc func(ctx context.Context, deviceID string) (Device, error)
+
+	// GetDeviceAlertFunc mocks the GetDeviceAlert method.
+	GetDeviceAlertFunc func(ctx context.Context, deviceID string, alertID int64) (Alert, error)
+
 	// GetDeviceAlertsFunc mocks the GetDeviceAlerts method.
-	GetDeviceAlertsFunc func(ctx context.Context, deviceID string, timeframe Timeframe, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error)
+	GetDeviceAlertsFunc func(ctx context.Context, deviceID string, timeframe Timeframe, filter AlertFilter, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error)
 
 	// GetDeviceConfigFunc mocks the GetDeviceConfig method.
 	GetDeviceConfigFunc func(ctx context.Context, deviceID string) (Config, error)
 
+	// GetDeviceConfigVersionFunc mocks the GetDeviceConfigVersion method.
+	GetDeviceConfigVersionFunc func(ctx context.Context, deviceID string, version int64) (DeviceConfigVersion, error)
+
+	// GetDeviceGroupFunc mocks the GetDeviceGroup method.
+	GetDeviceGroupFunc func(ctx context.Context, groupID string) (DeviceGroup, error)
+
+	// GetDeviceMetricAggregatesFunc mocks the GetDeviceMetricAggregates method.
+	GetDeviceMetricAggregatesFunc func(ctx context.Context, deviceID string, start time.Time, end time.Time, width time.Duration) ([]MetricAggregates, error)
+
 	// GetDeviceMetricsFunc mocks the GetDeviceMetrics method.
 	GetDeviceMetricsFunc func(ctx context.Context, deviceID string, timeframe Timeframe, pageOpts RepositoryPageOptions) (RepositoryPage[Metric], error)
 
+	// GetFleetSnapshotFunc mocks the GetFleetSnapshot method.
+	GetFleetSnapshotFunc func(ctx context.Context, selector LabelSelector, groupID string, pageOpts RepositoryPageOptions) (RepositoryPage[DeviceSnapshot], error)
+
+	// GetLatestConditionAlertFunc mocks the GetLatestConditionAlert method.
+	GetLatestConditionAlertFunc func(ctx context.Context, deviceID string, condition string) (Alert, error)
+
+	// GetLatestDeviceConfigVersionFunc mocks the GetLatestDeviceConfigVersion method.
+	GetLatestDeviceConfigVersionFunc func(ctx context.Context, deviceID string) (DeviceConfigVersion, error)
+
+	// GetMetricRangeFunc mocks the GetMetricRange method.
+	GetMetricRangeFunc func(ctx context.Context, deviceID string, name string, start time.Time, end time.Time) (MetricRange, error)
+
+	// GetOperationFunc mocks the GetOperation method.
+	GetOperationFunc func(ctx context.Context, id int64) (Operation, error)
+
+	// ListAlertRulesFunc mocks the ListAlertRules method.
+	ListAlertRulesFunc func(ctx context.Context, deviceID string) ([]AlertRule, error)
+
+	// ListDeviceConfigVersionsFunc mocks the ListDeviceConfigVersions method.
+	ListDeviceConfigVersionsFunc func(ctx context.Context, deviceID string, pageOpts RepositoryPageOptions) (RepositoryPage[DeviceConfigVersion], error)
+
+	// ListDeviceGroupsFunc mocks the ListDeviceGroups method.
+	ListDeviceGroupsFunc func(ctx context.Context) ([]DeviceGroup, error)
+
+	// ListDevicesFunc mocks the ListDevices method.
+	ListDevicesFunc func(ctx context.Context, selector LabelSelector, pageOpts RepositoryPageOptions) (RepositoryPage[Device], error)
+
+	// ListOperationsFunc mocks the ListOperations method.
+	ListOperationsFunc func(ctx context.Context, state OperationState) ([]Operation, error)
+
+	// ListReportingDevicesFunc mocks the ListReportingDevices method.
+	ListReportingDevicesFunc func(ctx context.Context) ([]ReportingDevice, error)
+
+	// RecordAlertOccurrenceFunc mocks the RecordAlertOccurrence method.
+	RecordAlertOccurrenceFunc func(ctx context.Context, deviceID string, alertID int64, at time.Time) error
+
+	// ResolveDeviceAlertFunc mocks the ResolveDeviceAlert method.
+	ResolveDeviceAlertFunc func(ctx context.Context, deviceID string, alertID int64, at time.Time) error
+
+	// ResolveDeviceAlertsByConditionFunc mocks the ResolveDeviceAlertsByCondition method.
+	ResolveDeviceAlertsByConditionFunc func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error)
+
+	// SaveConditionStatesFunc mocks the SaveConditionStates method.
+	SaveConditionStatesFunc func(ctx context.Context, deviceID string, states map[string]ConditionState) error
+
 	// SaveDeviceAlertFunc mocks the SaveDeviceAlert method.
-	SaveDeviceAlertFunc func(ctx context.Context, deviceID string, alert Alert) error
+	SaveDeviceAlertFunc func(ctx context.Context, deviceID string, alert Alert) (int64, error)
 
 	// SaveDeviceMetricFunc mocks the SaveDeviceMetric method.
 	SaveDeviceMetricFunc func(ctx context.Context, deviceID string, metric Metric) error
 
+	// SaveDeviceMetricsFunc mocks the SaveDeviceMetrics method.
+	SaveDeviceMetricsFunc func(ctx context.Context, deviceID string, metrics []Metric) error
+
+	// SearchAlertsFunc mocks the SearchAlerts method.
+	SearchAlertsFunc func(ctx context.Context, deviceIDs []string, selector LabelSelector, timeframe Timeframe, filter AlertFilter, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error)
+
+	// UpdateAlertRuleFunc mocks the UpdateAlertRule method.
+	UpdateAlertRuleFunc func(ctx context.Context, deviceID string, rule AlertRule) error
+
+	// UpdateDeviceFunc mocks the UpdateDevice method.
+	UpdateDeviceFunc func(ctx context.Context, device Device) error
+
+	// UpdateOperationFunc mocks the UpdateOperation method.
+	UpdateOperationFunc func(ctx context.Context, op Operation) error
+
 	// UpsertDeviceConfigFunc mocks the UpsertDeviceConfig method.
-	UpsertDeviceConfigFunc func(ctx context.Context, deviceID string, config Config) error
+	UpsertDeviceConfigFunc func(ctx context.Context, deviceID string, version DeviceConfigVersion, expectedRevision *int64) (int64, error)
+
+	// UpsertDeviceGroupConfigFunc mocks the UpsertDeviceGroupConfig method.
+	UpsertDeviceGroupConfigFunc func(ctx context.Context, groupID string, settings ConfigSettings) error
+
+	// UpsertGlobalConfigFunc mocks the UpsertGlobalConfig method.
+	UpsertGlobalConfigFunc func(ctx context.Context, settings ConfigSettings) error
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// AcknowledgeDeviceAlert holds details about calls to the AcknowledgeDeviceAlert method.
+		AcknowledgeDeviceAlert []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// AlertID is the alertID argument value.
+			AlertID int64
+			// By is the by argument value.
+			By string
+			// Comment is the comment argument value.
+			Comment string
+			// At is the at argument value.
+			At time.Time
+		}
+		// CreateAlertRule holds details about calls to the CreateAlertRule method.
+		CreateAlertRule []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// Rule is the rule argument value.
+			Rule AlertRule
+		}
+		// CreateDevice holds details about calls to the CreateDevice method.
+		CreateDevice []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Device is the device argument value.
+			Device Device
+		}
+		// CreateDeviceGroup holds details about calls to the CreateDeviceGroup method.
+		CreateDeviceGroup []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Group is the group argument value.
+			Group DeviceGroup
+		}
+		// DeleteAlertRule holds details about calls to the DeleteAlertRule method.
+		DeleteAlertRule []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// RuleID is the ruleID argument value.
+			RuleID int64
+		}
+		// DeleteDevice holds details about calls to the DeleteDevice method.
+		DeleteDevice []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// Op is the op argument value.
+			Op Operation
+		}
+		// DeleteDeviceAlerts holds details about calls to the DeleteDeviceAlerts method.
+		DeleteDeviceAlerts []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// MaxID is the maxID argument value.
+			MaxID int64
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// DeleteDeviceMetrics holds details about calls to the DeleteDeviceMetrics method.
+		DeleteDeviceMetrics []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// MaxID is the maxID argument value.
+			MaxID int64
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// GetAlertRule holds details about calls to the GetAlertRule method.
+		GetAlertRule []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// RuleID is the ruleID argument value.
+			RuleID int64
+		}
+		// GetAlertStats holds details about calls to the GetAlertStats method.
+		GetAlertStats []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Start is the start argument value.
+			Start time.Time
+			// End is the end argument value.
+			End time.Time
+			// Width is the width argument value.
+			Width time.Duration
+			// TopN is the topN argument value.
+			TopN int
+		}
+		// GetAlertsAfterID holds details about calls to the GetAlertsAfterID method.
+		GetAlertsAfterID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// AfterID is the afterID argument value.
+			AfterID int64
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// GetConditionStates holds details about calls to the GetConditionStates method.
+		GetConditionStates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+		}
+		// GetDevice holds details about calls to the GetDevice method.
+		GetDevice []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+		}
+		// GetDeviceAlert holds details about calls to the GetDeviceAlert method.
+		GetDeviceAlert []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// AlertID is the alertID argument value.
+			AlertID int64
+		}
 		// GetDeviceAlerts holds details about calls to the GetDeviceAlerts method.
 		GetDeviceAlerts []struct {
 			// Ctx is the ctx argument value.
@@ -71,6 +440,8 @@ type RepositoryMock struct {
 			DeviceID string
 			// Timeframe is the timeframe argument value.
 			Timeframe Timeframe
+			// Filter is the filter argument value.
+			Filter AlertFilter
 			// PageOpts is the pageOpts argument value.
 			PageOpts RepositoryPageOptions
 		}
@@ -81,6 +452,35 @@ type RepositoryMock struct {
 			// DeviceID is the deviceID argument value.
 			DeviceID string
 		}
+		// GetDeviceConfigVersion holds details about calls to the GetDeviceConfigVersion method.
+		GetDeviceConfigVersion []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// Version is the version argument value.
+			Version int64
+		}
+		// GetDeviceGroup holds details about calls to the GetDeviceGroup method.
+		GetDeviceGroup []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// GroupID is the groupID argument value.
+			GroupID string
+		}
+		// GetDeviceMetricAggregates holds details about calls to the GetDeviceMetricAggregates method.
+		GetDeviceMetricAggregates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// Start is the start argument value.
+			Start time.Time
+			// End is the end argument value.
+			End time.Time
+			// Width is the width argument value.
+			Width time.Duration
+		}
 		// GetDeviceMetrics holds details about calls to the GetDeviceMetrics method.
 		GetDeviceMetrics []struct {
 			// Ctx is the ctx argument value.
@@ -92,6 +492,137 @@ type RepositoryMock struct {
 			// PageOpts is the pageOpts argument value.
 			PageOpts RepositoryPageOptions
 		}
+		// GetFleetSnapshot holds details about calls to the GetFleetSnapshot method.
+		GetFleetSnapshot []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Selector is the selector argument value.
+			Selector LabelSelector
+			// GroupID is the groupID argument value.
+			GroupID string
+			// PageOpts is the pageOpts argument value.
+			PageOpts RepositoryPageOptions
+		}
+		// GetLatestConditionAlert holds details about calls to the GetLatestConditionAlert method.
+		GetLatestConditionAlert []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// Condition is the condition argument value.
+			Condition string
+		}
+		// GetLatestDeviceConfigVersion holds details about calls to the GetLatestDeviceConfigVersion method.
+		GetLatestDeviceConfigVersion []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+		}
+		// GetMetricRange holds details about calls to the GetMetricRange method.
+		GetMetricRange []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// Name is the name argument value.
+			Name string
+			// Start is the start argument value.
+			Start time.Time
+			// End is the end argument value.
+			End time.Time
+		}
+		// GetOperation holds details about calls to the GetOperation method.
+		GetOperation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// ListAlertRules holds details about calls to the ListAlertRules method.
+		ListAlertRules []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+		}
+		// ListDeviceConfigVersions holds details about calls to the ListDeviceConfigVersions method.
+		ListDeviceConfigVersions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// PageOpts is the pageOpts argument value.
+			PageOpts RepositoryPageOptions
+		}
+		// ListDeviceGroups holds details about calls to the ListDeviceGroups method.
+		ListDeviceGroups []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// ListDevices holds details about calls to the ListDevices method.
+		ListDevices []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Selector is the selector argument value.
+			Selector LabelSelector
+			// PageOpts is the pageOpts argument value.
+			PageOpts RepositoryPageOptions
+		}
+		// ListOperations holds details about calls to the ListOperations method.
+		ListOperations []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// State is the state argument value.
+			State OperationState
+		}
+		// ListReportingDevices holds details about calls to the ListReportingDevices method.
+		ListReportingDevices []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// RecordAlertOccurrence holds details about calls to the RecordAlertOccurrence method.
+		RecordAlertOccurrence []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// AlertID is the alertID argument value.
+			AlertID int64
+			// At is the at argument value.
+			At time.Time
+		}
+		// ResolveDeviceAlert holds details about calls to the ResolveDeviceAlert method.
+		ResolveDeviceAlert []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// AlertID is the alertID argument value.
+			AlertID int64
+			// At is the at argument value.
+			At time.Time
+		}
+		// ResolveDeviceAlertsByCondition holds details about calls to the ResolveDeviceAlertsByCondition method.
+		ResolveDeviceAlertsByCondition []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// Conditions is the conditions argument value.
+			Conditions []string
+			// At is the at argument value.
+			At time.Time
+		}
+		// SaveConditionStates holds details about calls to the SaveConditionStates method.
+		SaveConditionStates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// States is the states argument value.
+			States map[string]ConditionState
+		}
 		// SaveDeviceAlert holds details about calls to the SaveDeviceAlert method.
 		SaveDeviceAlert []struct {
 			// Ctx is the ctx argument value.
@@ -110,150 +641,1550 @@ type RepositoryMock struct {
 			// Metric is the metric argument value.
 			Metric Metric
 		}
+		// SaveDeviceMetrics holds details about calls to the SaveDeviceMetrics method.
+		SaveDeviceMetrics []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// Metrics is the metrics argument value.
+			Metrics []Metric
+		}
+		// SearchAlerts holds details about calls to the SearchAlerts method.
+		SearchAlerts []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceIDs is the deviceIDs argument value.
+			DeviceIDs []string
+			// Selector is the selector argument value.
+			Selector LabelSelector
+			// Timeframe is the timeframe argument value.
+			Timeframe Timeframe
+			// Filter is the filter argument value.
+			Filter AlertFilter
+			// PageOpts is the pageOpts argument value.
+			PageOpts RepositoryPageOptions
+		}
+		// UpdateAlertRule holds details about calls to the UpdateAlertRule method.
+		UpdateAlertRule []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DeviceID is the deviceID argument value.
+			DeviceID string
+			// Rule is the rule argument value.
+			Rule AlertRule
+		}
+		// UpdateDevice holds details about calls to the UpdateDevice method.
+		UpdateDevice []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Device is the device argument value.
+			Device Device
+		}
+		// UpdateOperation holds details about calls to the UpdateOperation method.
+		UpdateOperation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Op is the op argument value.
+			Op Operation
+		}
 		// UpsertDeviceConfig holds details about calls to the UpsertDeviceConfig method.
 		UpsertDeviceConfig []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 			// DeviceID is the deviceID argument value.
 			DeviceID string
-			// Config is the config argument value.
-			Config Config
+			// Version is the version argument value.
+			Version DeviceConfigVersion
+			// ExpectedRevision is the expectedRevision argument value.
+			ExpectedRevision *int64
+		}
+		// UpsertDeviceGroupConfig holds details about calls to the UpsertDeviceGroupConfig method.
+		UpsertDeviceGroupConfig []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// GroupID is the groupID argument value.
+			GroupID string
+			// Settings is the settings argument value.
+			Settings ConfigSettings
+		}
+		// UpsertGlobalConfig holds details about calls to the UpsertGlobalConfig method.
+		UpsertGlobalConfig []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Settings is the settings argument value.
+			Settings ConfigSettings
 		}
 	}
-	lockGetDeviceAlerts    sync.RWMutex
-	lockGetDeviceConfig    sync.RWMutex
-	lockGetDeviceMetrics   sync.RWMutex
-	lockSaveDeviceAlert    sync.RWMutex
-	lockSaveDeviceMetric   sync.RWMutex
-	lockUpsertDeviceConfig sync.RWMutex
+	lockAcknowledgeDeviceAlert         sync.RWMutex
+	lockCreateAlertRule                sync.RWMutex
+	lockCreateDevice                   sync.RWMutex
+	lockCreateDeviceGroup              sync.RWMutex
+	lockDeleteAlertRule                sync.RWMutex
+	lockDeleteDevice                   sync.RWMutex
+	lockDeleteDeviceAlerts             sync.RWMutex
+	lockDeleteDeviceMetrics            sync.RWMutex
+	lockGetAlertRule                   sync.RWMutex
+	lockGetAlertStats                  sync.RWMutex
+	lockGetAlertsAfterID               sync.RWMutex
+	lockGetConditionStates             sync.RWMutex
+	lockGetDevice                      sync.RWMutex
+	lockGetDeviceAlert                 sync.RWMutex
+	lockGetDeviceAlerts                sync.RWMutex
+	lockGetDeviceConfig                sync.RWMutex
+	lockGetDeviceConfigVersion         sync.RWMutex
+	lockGetDeviceGroup                 sync.RWMutex
+	lockGetDeviceMetricAggregates      sync.RWMutex
+	lockGetDeviceMetrics               sync.RWMutex
+	lockGetFleetSnapshot               sync.RWMutex
+	lockGetLatestConditionAlert        sync.RWMutex
+	lockGetLatestDeviceConfigVersion   sync.RWMutex
+	lockGetMetricRange                 sync.RWMutex
+	lockGetOperation                   sync.RWMutex
+	lockListAlertRules                 sync.RWMutex
+	lockListDeviceConfigVersions       sync.RWMutex
+	lockListDeviceGroups               sync.RWMutex
+	lockListDevices                    sync.RWMutex
+	lockListOperations                 sync.RWMutex
+	lockListReportingDevices           sync.RWMutex
+	lockRecordAlertOccurrence          sync.RWMutex
+	lockResolveDeviceAlert             sync.RWMutex
+	lockResolveDeviceAlertsByCondition sync.RWMutex
+	lockSaveConditionStates            sync.RWMutex
+	lockSaveDeviceAlert                sync.RWMutex
+	lockSaveDeviceMetric               sync.RWMutex
+	lockSaveDeviceMetrics              sync.RWMutex
+	lockSearchAlerts                   sync.RWMutex
+	lockUpdateAlertRule                sync.RWMutex
+	lockUpdateDevice                   sync.RWMutex
+	lockUpdateOperation                sync.RWMutex
+	lockUpsertDeviceConfig             sync.RWMutex
+	lockUpsertDeviceGroupConfig        sync.RWMutex
+	lockUpsertGlobalConfig             sync.RWMutex
+}
+
+// AcknowledgeDeviceAlert calls AcknowledgeDeviceAlertFunc.
+func (mock *RepositoryMock) AcknowledgeDeviceAlert(ctx context.Context, deviceID string, alertID int64, by string, comment string, at time.Time) error {
+	if mock.AcknowledgeDeviceAlertFunc == nil {
+		panic("RepositoryMock.AcknowledgeDeviceAlertFunc: method is nil but Repository.AcknowledgeDeviceAlert was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		AlertID  int64
+		By       string
+		Comment  string
+		At       time.Time
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		AlertID:  alertID,
+		By:       by,
+		Comment:  comment,
+		At:       at,
+	}
+	mock.lockAcknowledgeDeviceAlert.Lock()
+	mock.calls.AcknowledgeDeviceAlert = append(mock.calls.AcknowledgeDeviceAlert, callInfo)
+	mock.lockAcknowledgeDeviceAlert.Unlock()
+	return mock.AcknowledgeDeviceAlertFunc(ctx, deviceID, alertID, by, comment, at)
+}
+
+// AcknowledgeDeviceAlertCalls gets all the calls that were made to AcknowledgeDeviceAlert.
+// Check the length with:
+//
+//	len(mockedRepository.AcknowledgeDeviceAlertCalls())
+func (mock *RepositoryMock) AcknowledgeDeviceAlertCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	AlertID  int64
+	By       string
+	Comment  string
+	At       time.Time
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		AlertID  int64
+		By       string
+		Comment  string
+		At       time.Time
+	}
+	mock.lockAcknowledgeDeviceAlert.RLock()
+	calls = mock.calls.AcknowledgeDeviceAlert
+	mock.lockAcknowledgeDeviceAlert.RUnlock()
+	return calls
+}
+
+// CreateAlertRule calls CreateAlertRuleFunc.
+func (mock *RepositoryMock) CreateAlertRule(ctx context.Context, deviceID string, rule AlertRule) (int64, error) {
+	if mock.CreateAlertRuleFunc == nil {
+		panic("RepositoryMock.CreateAlertRuleFunc: method is nil but Repository.CreateAlertRule was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		Rule     AlertRule
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		Rule:     rule,
+	}
+	mock.lockCreateAlertRule.Lock()
+	mock.calls.CreateAlertRule = append(mock.calls.CreateAlertRule, callInfo)
+	mock.lockCreateAlertRule.Unlock()
+	return mock.CreateAlertRuleFunc(ctx, deviceID, rule)
+}
+
+// CreateAlertRuleCalls gets all the calls that were made to CreateAlertRule.
+// Check the length with:
+//
+//	len(mockedRepository.CreateAlertRuleCalls())
+func (mock *RepositoryMock) CreateAlertRuleCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	Rule     AlertRule
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		Rule     AlertRule
+	}
+	mock.lockCreateAlertRule.RLock()
+	calls = mock.calls.CreateAlertRule
+	mock.lockCreateAlertRule.RUnlock()
+	return calls
+}
+
+// CreateDevice calls CreateDeviceFunc.
+func (mock *RepositoryMock) CreateDevice(ctx context.Context, device Device) error {
+	if mock.CreateDeviceFunc == nil {
+		panic("RepositoryMock.CreateDeviceFunc: method is nil but Repository.CreateDevice was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Device Device
+	}{
+		Ctx:    ctx,
+		Device: device,
+	}
+	mock.lockCreateDevice.Lock()
+	mock.calls.CreateDevice = append(mock.calls.CreateDevice, callInfo)
+	mock.lockCreateDevice.Unlock()
+	return mock.CreateDeviceFunc(ctx, device)
+}
+
+// CreateDeviceCalls gets all the calls that were made to CreateDevice.
+// Check the length with:
+//
+//	len(mockedRepository.CreateDeviceCalls())
+func (mock *RepositoryMock) CreateDeviceCalls() []struct {
+	Ctx    context.Context
+	Device Device
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Device Device
+	}
+	mock.lockCreateDevice.RLock()
+	calls = mock.calls.CreateDevice
+	mock.lockCreateDevice.RUnlock()
+	return calls
+}
+
+// CreateDeviceGroup calls CreateDeviceGroupFunc.
+func (mock *RepositoryMock) CreateDeviceGroup(ctx context.Context, group DeviceGroup) error {
+	if mock.CreateDeviceGroupFunc == nil {
+		panic("RepositoryMock.CreateDeviceGroupFunc: method is nil but Repository.CreateDeviceGroup was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Group DeviceGroup
+	}{
+		Ctx:   ctx,
+		Group: group,
+	}
+	mock.lockCreateDeviceGroup.Lock()
+	mock.calls.CreateDeviceGroup = append(mock.calls.CreateDeviceGroup, callInfo)
+	mock.lockCreateDeviceGroup.Unlock()
+	return mock.CreateDeviceGroupFunc(ctx, group)
+}
+
+// CreateDeviceGroupCalls gets all the calls that were made to CreateDeviceGroup.
+// Check the length with:
+//
+//	len(mockedRepository.CreateDeviceGroupCalls())
+func (mock *RepositoryMock) CreateDeviceGroupCalls() []struct {
+	Ctx   context.Context
+	Group DeviceGroup
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Group DeviceGroup
+	}
+	mock.lockCreateDeviceGroup.RLock()
+	calls = mock.calls.CreateDeviceGroup
+	mock.lockCreateDeviceGroup.RUnlock()
+	return calls
+}
+
+// DeleteAlertRule calls DeleteAlertRuleFunc.
+func (mock *RepositoryMock) DeleteAlertRule(ctx context.Context, deviceID string, ruleID int64) error {
+	if mock.DeleteAlertRuleFunc == nil {
+		panic("RepositoryMock.DeleteAlertRuleFunc: method is nil but Repository.DeleteAlertRule was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		RuleID   int64
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		RuleID:   ruleID,
+	}
+	mock.lockDeleteAlertRule.Lock()
+	mock.calls.DeleteAlertRule = append(mock.calls.DeleteAlertRule, callInfo)
+	mock.lockDeleteAlertRule.Unlock()
+	return mock.DeleteAlertRuleFunc(ctx, deviceID, ruleID)
+}
+
+// DeleteAlertRuleCalls gets all the calls that were made to DeleteAlertRule.
+// Check the length with:
+//
+//	len(mockedRepository.DeleteAlertRuleCalls())
+func (mock *RepositoryMock) DeleteAlertRuleCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	RuleID   int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		RuleID   int64
+	}
+	mock.lockDeleteAlertRule.RLock()
+	calls = mock.calls.DeleteAlertRule
+	mock.lockDeleteAlertRule.RUnlock()
+	return calls
+}
+
+// DeleteDevice calls DeleteDeviceFunc.
+func (mock *RepositoryMock) DeleteDevice(ctx context.Context, deviceID string, op Operation) (Operation, error) {
+	if mock.DeleteDeviceFunc == nil {
+		panic("RepositoryMock.DeleteDeviceFunc: method is nil but Repository.DeleteDevice was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		Op       Operation
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		Op:       op,
+	}
+	mock.lockDeleteDevice.Lock()
+	mock.calls.DeleteDevice = append(mock.calls.DeleteDevice, callInfo)
+	mock.lockDeleteDevice.Unlock()
+	return mock.DeleteDeviceFunc(ctx, deviceID, op)
+}
+
+// DeleteDeviceCalls gets all the calls that were made to DeleteDevice.
+// Check the length with:
+//
+//	len(mockedRepository.DeleteDeviceCalls())
+func (mock *RepositoryMock) DeleteDeviceCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	Op       Operation
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		Op       Operation
+	}
+	mock.lockDeleteDevice.RLock()
+	calls = mock.calls.DeleteDevice
+	mock.lockDeleteDevice.RUnlock()
+	return calls
+}
+
+// DeleteDeviceAlerts calls DeleteDeviceAlertsFunc.
+func (mock *RepositoryMock) DeleteDeviceAlerts(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error) {
+	if mock.DeleteDeviceAlertsFunc == nil {
+		panic("RepositoryMock.DeleteDeviceAlertsFunc: method is nil but Repository.DeleteDeviceAlerts was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		MaxID    int64
+		Limit    int
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		MaxID:    maxID,
+		Limit:    limit,
+	}
+	mock.lockDeleteDeviceAlerts.Lock()
+	mock.calls.DeleteDeviceAlerts = append(mock.calls.DeleteDeviceAlerts, callInfo)
+	mock.lockDeleteDeviceAlerts.Unlock()
+	return mock.DeleteDeviceAlertsFunc(ctx, deviceID, maxID, limit)
+}
+
+// DeleteDeviceAlertsCalls gets all the calls that were made to DeleteDeviceAlerts.
+// Check the length with:
+//
+//	len(mockedRepository.DeleteDeviceAlertsCalls())
+func (mock *RepositoryMock) DeleteDeviceAlertsCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	MaxID    int64
+	Limit    int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		MaxID    int64
+		Limit    int
+	}
+	mock.lockDeleteDeviceAlerts.RLock()
+	calls = mock.calls.DeleteDeviceAlerts
+	mock.lockDeleteDeviceAlerts.RUnlock()
+	return calls
+}
+
+// DeleteDeviceMetrics calls DeleteDeviceMetricsFunc.
+func (mock *RepositoryMock) DeleteDeviceMetrics(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error) {
+	if mock.DeleteDeviceMetricsFunc == nil {
+		panic("RepositoryMock.DeleteDeviceMetricsFunc: method is nil but Repository.DeleteDeviceMetrics was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		MaxID    int64
+		Limit    int
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		MaxID:    maxID,
+		Limit:    limit,
+	}
+	mock.lockDeleteDeviceMetrics.Lock()
+	mock.calls.DeleteDeviceMetrics = append(mock.calls.DeleteDeviceMetrics, callInfo)
+	mock.lockDeleteDeviceMetrics.Unlock()
+	return mock.DeleteDeviceMetricsFunc(ctx, deviceID, maxID, limit)
+}
+
+// DeleteDeviceMetricsCalls gets all the calls that were made to DeleteDeviceMetrics.
+// Check the length with:
+//
+//	len(mockedRepository.DeleteDeviceMetricsCalls())
+func (mock *RepositoryMock) DeleteDeviceMetricsCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	MaxID    int64
+	Limit    int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		MaxID    int64
+		Limit    int
+	}
+	mock.lockDeleteDeviceMetrics.RLock()
+	calls = mock.calls.DeleteDeviceMetrics
+	mock.lockDeleteDeviceMetrics.RUnlock()
+	return calls
+}
+
+// GetAlertRule calls GetAlertRuleFunc.
+func (mock *RepositoryMock) GetAlertRule(ctx context.Context, deviceID string, ruleID int64) (AlertRule, error) {
+	if mock.GetAlertRuleFunc == nil {
+		panic("RepositoryMock.GetAlertRuleFunc: method is nil but Repository.GetAlertRule was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		RuleID   int64
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		RuleID:   ruleID,
+	}
+	mock.lockGetAlertRule.Lock()
+	mock.calls.GetAlertRule = append(mock.calls.GetAlertRule, callInfo)
+	mock.lockGetAlertRule.Unlock()
+	return mock.GetAlertRuleFunc(ctx, deviceID, ruleID)
+}
+
+// GetAlertRuleCalls gets all the calls that were made to GetAlertRule.
+// Check the length with:
+//
+//	len(mockedRepository.GetAlertRuleCalls())
+func (mock *RepositoryMock) GetAlertRuleCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	RuleID   int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		RuleID   int64
+	}
+	mock.lockGetAlertRule.RLock()
+	calls = mock.calls.GetAlertRule
+	mock.lockGetAlertRule.RUnlock()
+	return calls
+}
+
+// GetAlertStats calls GetAlertStatsFunc.
+func (mock *RepositoryMock) GetAlertStats(ctx context.Context, start time.Time, end time.Time, width time.Duration, topN int) (AlertStats, error) {
+	if mock.GetAlertStatsFunc == nil {
+		panic("RepositoryMock.GetAlertStatsFunc: method is nil but Repository.GetAlertStats was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Start time.Time
+		End   time.Time
+		Width time.Duration
+		TopN  int
+	}{
+		Ctx:   ctx,
+		Start: start,
+		End:   end,
+		Width: width,
+		TopN:  topN,
+	}
+	mock.lockGetAlertStats.Lock()
+	mock.calls.GetAlertStats = append(mock.calls.GetAlertStats, callInfo)
+	mock.lockGetAlertStats.Unlock()
+	return mock.GetAlertStatsFunc(ctx, start, end, width, topN)
+}
+
+// GetAlertStatsCalls gets all the calls that were made to GetAlertStats.
+// Check the length with:
+//
+//	len(mockedRepository.GetAlertStatsCalls())
+func (mock *RepositoryMock) GetAlertStatsCalls() []struct {
+	Ctx   context.Context
+	Start time.Time
+	End   time.Time
+	Width time.Duration
+	TopN  int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Start time.Time
+		End   time.Time
+		Width time.Duration
+		TopN  int
+	}
+	mock.lockGetAlertStats.RLock()
+	calls = mock.calls.GetAlertStats
+	mock.lockGetAlertStats.RUnlock()
+	return calls
+}
+
+// GetAlertsAfterID calls GetAlertsAfterIDFunc.
+func (mock *RepositoryMock) GetAlertsAfterID(ctx context.Context, deviceID string, afterID int64, limit int) ([]Alert, error) {
+	if mock.GetAlertsAfterIDFunc == nil {
+		panic("RepositoryMock.GetAlertsAfterIDFunc: method is nil but Repository.GetAlertsAfterID was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		AfterID  int64
+		Limit    int
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		AfterID:  afterID,
+		Limit:    limit,
+	}
+	mock.lockGetAlertsAfterID.Lock()
+	mock.calls.GetAlertsAfterID = append(mock.calls.GetAlertsAfterID, callInfo)
+	mock.lockGetAlertsAfterID.Unlock()
+	return mock.GetAlertsAfterIDFunc(ctx, deviceID, afterID, limit)
+}
+
+// GetAlertsAfterIDCalls gets all the calls that were made to GetAlertsAfterID.
+// Check the length with:
+//
+//	len(mockedRepository.GetAlertsAfterIDCalls())
+func (mock *RepositoryMock) GetAlertsAfterIDCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	AfterID  int64
+	Limit    int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		AfterID  int64
+		Limit    int
+	}
+	mock.lockGetAlertsAfterID.RLock()
+	calls = mock.calls.GetAlertsAfterID
+	mock.lockGetAlertsAfterID.RUnlock()
+	return calls
+}
+
+// GetConditionStates calls GetConditionStatesFunc.
+func (mock *RepositoryMock) GetConditionStates(ctx context.Context, deviceID string) (map[string]ConditionState, error) {
+	if mock.GetConditionStatesFunc == nil {
+		panic("RepositoryMock.GetConditionStatesFunc: method is nil but Repository.GetConditionStates was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+	}
+	mock.lockGetConditionStates.Lock()
+	mock.calls.GetConditionStates = append(mock.calls.GetConditionStates, callInfo)
+	mock.lockGetConditionStates.Unlock()
+	return mock.GetConditionStatesFunc(ctx, deviceID)
+}
+
+// GetConditionStatesCalls gets all the calls that were made to GetConditionStates.
+// Check the length with:
+//
+//	len(mockedRepository.GetConditionStatesCalls())
+func (mock *RepositoryMock) GetConditionStatesCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+	}
+	mock.lockGetConditionStates.RLock()
+	calls = mock.calls.GetConditionStates
+	mock.lockGetConditionStates.RUnlock()
+	return calls
+}
+
+// GetDevice calls GetDeviceFunc.
+func (mock *RepositoryMock) GetDevice(ctx context.Context, deviceID string) (Device, error) {
+	if mock.GetDeviceFunc == nil {
+		panic("RepositoryMock.GetDeviceFunc: method is nil but Repository.GetDevice was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+	}
+	mock.lockGetDevice.Lock()
+	mock.calls.GetDevice = append(mock.calls.GetDevice, callInfo)
+	mock.lockGetDevice.Unlock()
+	return mock.GetDeviceFunc(ctx, deviceID)
+}
+
+// GetDeviceCalls gets all the calls that were made to GetDevice.
+// Check the length with:
+//
+//	len(mockedRepository.GetDeviceCalls())
+func (mock *RepositoryMock) GetDeviceCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+	}
+	mock.lockGetDevice.RLock()
+	calls = mock.calls.GetDevice
+	mock.lockGetDevice.RUnlock()
+	return calls
+}
+
+// GetDeviceAlert calls GetDeviceAlertFunc.
+func (mock *RepositoryMock) GetDeviceAlert(ctx context.Context, deviceID string, alertID int64) (Alert, error) {
+	if mock.GetDeviceAlertFunc == nil {
+		panic("RepositoryMock.GetDeviceAlertFunc: method is nil but Repository.GetDeviceAlert was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		AlertID  int64
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		AlertID:  alertID,
+	}
+	mock.lockGetDeviceAlert.Lock()
+	mock.calls.GetDeviceAlert = append(mock.calls.GetDeviceAlert, callInfo)
+	mock.lockGetDeviceAlert.Unlock()
+	return mock.GetDeviceAlertFunc(ctx, deviceID, alertID)
+}
+
+// GetDeviceAlertCalls gets all the calls that were made to GetDeviceAlert.
+// Check the length with:
+//
+//	len(mockedRepository.GetDeviceAlertCalls())
+func (mock *RepositoryMock) GetDeviceAlertCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	AlertID  int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		AlertID  int64
+	}
+	mock.lockGetDeviceAlert.RLock()
+	calls = mock.calls.GetDeviceAlert
+	mock.lockGetDeviceAlert.RUnlock()
+	return calls
+}
+
+// GetDeviceAlerts calls GetDeviceAlertsFunc.
+func (mock *RepositoryMock) GetDeviceAlerts(ctx context.Context, deviceID string, timeframe Timeframe, filter AlertFilter, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error) {
+	if mock.GetDeviceAlertsFunc == nil {
+		panic("RepositoryMock.GetDeviceAlertsFunc: method is nil but Repository.GetDeviceAlerts was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		DeviceID  string
+		Timeframe Timeframe
+		Filter    AlertFilter
+		PageOpts  RepositoryPageOptions
+	}{
+		Ctx:       ctx,
+		DeviceID:  deviceID,
+		Timeframe: timeframe,
+		Filter:    filter,
+		PageOpts:  pageOpts,
+	}
+	mock.lockGetDeviceAlerts.Lock()
+	mock.calls.GetDeviceAlerts = append(mock.calls.GetDeviceAlerts, callInfo)
+	mock.lockGetDeviceAlerts.Unlock()
+	return mock.GetDeviceAlertsFunc(ctx, deviceID, timeframe, filter, pageOpts)
+}
+
+// GetDeviceAlertsCalls gets all the calls that were made to GetDeviceAlerts.
+// Check the length with:
+//
+//	len(mockedRepository.GetDeviceAlertsCalls())
+func (mock *RepositoryMock) GetDeviceAlertsCalls() []struct {
+	Ctx       context.Context
+	DeviceID  string
+	Timeframe Timeframe
+	Filter    AlertFilter
+	PageOpts  RepositoryPageOptions
+} {
+	var calls []struct {
+		Ctx       context.Context
+		DeviceID  string
+		Timeframe Timeframe
+		Filter    AlertFilter
+		PageOpts  RepositoryPageOptions
+	}
+	mock.lockGetDeviceAlerts.RLock()
+	calls = mock.calls.GetDeviceAlerts
+	mock.lockGetDeviceAlerts.RUnlock()
+	return calls
+}
+
+// GetDeviceConfig calls GetDeviceConfigFunc.
+func (mock *RepositoryMock) GetDeviceConfig(ctx context.Context, deviceID string) (Config, error) {
+	if mock.GetDeviceConfigFunc == nil {
+		panic("RepositoryMock.GetDeviceConfigFunc: method is nil but Repository.GetDeviceConfig was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+	}
+	mock.lockGetDeviceConfig.Lock()
+	mock.calls.GetDeviceConfig = append(mock.calls.GetDeviceConfig, callInfo)
+	mock.lockGetDeviceConfig.Unlock()
+	return mock.GetDeviceConfigFunc(ctx, deviceID)
+}
+
+// GetDeviceConfigCalls gets all the calls that were made to GetDeviceConfig.
+// Check the length with:
+//
+//	len(mockedRepository.GetDeviceConfigCalls())
+func (mock *RepositoryMock) GetDeviceConfigCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+	}
+	mock.lockGetDeviceConfig.RLock()
+	calls = mock.calls.GetDeviceConfig
+	mock.lockGetDeviceConfig.RUnlock()
+	return calls
+}
+
+// GetDeviceConfigVersion calls GetDeviceConfigVersionFunc.
+func (mock *RepositoryMock) GetDeviceConfigVersion(ctx context.Context, deviceID string, version int64) (DeviceConfigVersion, error) {
+	if mock.GetDeviceConfigVersionFunc == nil {
+		panic("RepositoryMock.GetDeviceConfigVersionFunc: method is nil but Repository.GetDeviceConfigVersion was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		Version  int64
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		Version:  version,
+	}
+	mock.lockGetDeviceConfigVersion.Lock()
+	mock.calls.GetDeviceConfigVersion = append(mock.calls.GetDeviceConfigVersion, callInfo)
+	mock.lockGetDeviceConfigVersion.Unlock()
+	return mock.GetDeviceConfigVersionFunc(ctx, deviceID, version)
+}
+
+// GetDeviceConfigVersionCalls gets all the calls that were made to GetDeviceConfigVersion.
+// Check the length with:
+//
+//	len(mockedRepository.GetDeviceConfigVersionCalls())
+func (mock *RepositoryMock) GetDeviceConfigVersionCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	Version  int64
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		Version  int64
+	}
+	mock.lockGetDeviceConfigVersion.RLock()
+	calls = mock.calls.GetDeviceConfigVersion
+	mock.lockGetDeviceConfigVersion.RUnlock()
+	return calls
+}
+
+// GetDeviceGroup calls GetDeviceGroupFunc.
+func (mock *RepositoryMock) GetDeviceGroup(ctx context.Context, groupID string) (DeviceGroup, error) {
+	if mock.GetDeviceGroupFunc == nil {
+		panic("RepositoryMock.GetDeviceGroupFunc: method is nil but Repository.GetDeviceGroup was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		GroupID string
+	}{
+		Ctx:     ctx,
+		GroupID: groupID,
+	}
+	mock.lockGetDeviceGroup.Lock()
+	mock.calls.GetDeviceGroup = append(mock.calls.GetDeviceGroup, callInfo)
+	mock.lockGetDeviceGroup.Unlock()
+	return mock.GetDeviceGroupFunc(ctx, groupID)
+}
+
+// GetDeviceGroupCalls gets all the calls that were made to GetDeviceGroup.
+// Check the length with:
+//
+//	len(mockedRepository.GetDeviceGroupCalls())
+func (mock *RepositoryMock) GetDeviceGroupCalls() []struct {
+	Ctx     context.Context
+	GroupID string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		GroupID string
+	}
+	mock.lockGetDeviceGroup.RLock()
+	calls = mock.calls.GetDeviceGroup
+	mock.lockGetDeviceGroup.RUnlock()
+	return calls
+}
+
+// GetDeviceMetricAggregates calls GetDeviceMetricAggregatesFunc.
+func (mock *RepositoryMock) GetDeviceMetricAggregates(ctx context.Context, deviceID string, start time.Time, end time.Time, width time.Duration) ([]MetricAggregates, error) {
+	if mock.GetDeviceMetricAggregatesFunc == nil {
+		panic("RepositoryMock.GetDeviceMetricAggregatesFunc: method is nil but Repository.GetDeviceMetricAggregates was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		Start    time.Time
+		End      time.Time
+		Width    time.Duration
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		Start:    start,
+		End:      end,
+		Width:    width,
+	}
+	mock.lockGetDeviceMetricAggregates.Lock()
+	mock.calls.GetDeviceMetricAggregates = append(mock.calls.GetDeviceMetricAggregates, callInfo)
+	mock.lockGetDeviceMetricAggregates.Unlock()
+	return mock.GetDeviceMetricAggregatesFunc(ctx, deviceID, start, end, width)
+}
+
+// GetDeviceMetricAggregatesCalls gets all the calls that were made to GetDeviceMetricAggregates.
+// Check the length with:
+//
+//	len(mockedRepository.GetDeviceMetricAggregatesCalls())
+func (mock *RepositoryMock) GetDeviceMetricAggregatesCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	Start    time.Time
+	End      time.Time
+	Width    time.Duration
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		Start    time.Time
+		End      time.Time
+		Width    time.Duration
+	}
+	mock.lockGetDeviceMetricAggregates.RLock()
+	calls = mock.calls.GetDeviceMetricAggregates
+	mock.lockGetDeviceMetricAggregates.RUnlock()
+	return calls
+}
+
+// GetDeviceMetrics calls GetDeviceMetricsFunc.
+func (mock *RepositoryMock) GetDeviceMetrics(ctx context.Context, deviceID string, timeframe Timeframe, pageOpts RepositoryPageOptions) (RepositoryPage[Metric], error) {
+	if mock.GetDeviceMetricsFunc == nil {
+		panic("RepositoryMock.GetDeviceMetricsFunc: method is nil but Repository.GetDeviceMetrics was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		DeviceID  string
+		Timeframe Timeframe
+		PageOpts  RepositoryPageOptions
+	}{
+		Ctx:       ctx,
+		DeviceID:  deviceID,
+		Timeframe: timeframe,
+		PageOpts:  pageOpts,
+	}
+	mock.lockGetDeviceMetrics.Lock()
+	mock.calls.GetDeviceMetrics = append(mock.calls.GetDeviceMetrics, callInfo)
+	mock.lockGetDeviceMetrics.Unlock()
+	return mock.GetDeviceMetricsFunc(ctx, deviceID, timeframe, pageOpts)
+}
+
+// GetDeviceMetricsCalls gets all the calls that were made to GetDeviceMetrics.
+// Check the length with:
+//
+//	len(mockedRepository.GetDeviceMetricsCalls())
+func (mock *RepositoryMock) GetDeviceMetricsCalls() []struct {
+	Ctx       context.Context
+	DeviceID  string
+	Timeframe Timeframe
+	PageOpts  RepositoryPageOptions
+} {
+	var calls []struct {
+		Ctx       context.Context
+		DeviceID  string
+		Timeframe Timeframe
+		PageOpts  RepositoryPageOptions
+	}
+	mock.lockGetDeviceMetrics.RLock()
+	calls = mock.calls.GetDeviceMetrics
+	mock.lockGetDeviceMetrics.RUnlock()
+	return calls
+}
+
+// GetFleetSnapshot calls GetFleetSnapshotFunc.
+func (mock *RepositoryMock) GetFleetSnapshot(ctx context.Context, selector LabelSelector, groupID string, pageOpts RepositoryPageOptions) (RepositoryPage[DeviceSnapshot], error) {
+	if mock.GetFleetSnapshotFunc == nil {
+		panic("RepositoryMock.GetFleetSnapshotFunc: method is nil but Repository.GetFleetSnapshot was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Selector LabelSelector
+		GroupID  string
+		PageOpts RepositoryPageOptions
+	}{
+		Ctx:      ctx,
+		Selector: selector,
+		GroupID:  groupID,
+		PageOpts: pageOpts,
+	}
+	mock.lockGetFleetSnapshot.Lock()
+	mock.calls.GetFleetSnapshot = append(mock.calls.GetFleetSnapshot, callInfo)
+	mock.lockGetFleetSnapshot.Unlock()
+	return mock.GetFleetSnapshotFunc(ctx, selector, groupID, pageOpts)
+}
+
+// GetFleetSnapshotCalls gets all the calls that were made to GetFleetSnapshot.
+// Check the length with:
+//
+//	len(mockedRepository.GetFleetSnapshotCalls())
+func (mock *RepositoryMock) GetFleetSnapshotCalls() []struct {
+	Ctx      context.Context
+	Selector LabelSelector
+	GroupID  string
+	PageOpts RepositoryPageOptions
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Selector LabelSelector
+		GroupID  string
+		PageOpts RepositoryPageOptions
+	}
+	mock.lockGetFleetSnapshot.RLock()
+	calls = mock.calls.GetFleetSnapshot
+	mock.lockGetFleetSnapshot.RUnlock()
+	return calls
+}
+
+// GetLatestConditionAlert calls GetLatestConditionAlertFunc.
+func (mock *RepositoryMock) GetLatestConditionAlert(ctx context.Context, deviceID string, condition string) (Alert, error) {
+	if mock.GetLatestConditionAlertFunc == nil {
+		panic("RepositoryMock.GetLatestConditionAlertFunc: method is nil but Repository.GetLatestConditionAlert was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		DeviceID  string
+		Condition string
+	}{
+		Ctx:       ctx,
+		DeviceID:  deviceID,
+		Condition: condition,
+	}
+	mock.lockGetLatestConditionAlert.Lock()
+	mock.calls.GetLatestConditionAlert = append(mock.calls.GetLatestConditionAlert, callInfo)
+	mock.lockGetLatestConditionAlert.Unlock()
+	return mock.GetLatestConditionAlertFunc(ctx, deviceID, condition)
+}
+
+// GetLatestConditionAlertCalls gets all the calls that were made to GetLatestConditionAlert.
+// Check the length with:
+//
+//	len(mockedRepository.GetLatestConditionAlertCalls())
+func (mock *RepositoryMock) GetLatestConditionAlertCalls() []struct {
+	Ctx       context.Context
+	DeviceID  string
+	Condition string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		DeviceID  string
+		Condition string
+	}
+	mock.lockGetLatestConditionAlert.RLock()
+	calls = mock.calls.GetLatestConditionAlert
+	mock.lockGetLatestConditionAlert.RUnlock()
+	return calls
+}
+
+// GetLatestDeviceConfigVersion calls GetLatestDeviceConfigVersionFunc.
+func (mock *RepositoryMock) GetLatestDeviceConfigVersion(ctx context.Context, deviceID string) (DeviceConfigVersion, error) {
+	if mock.GetLatestDeviceConfigVersionFunc == nil {
+		panic("RepositoryMock.GetLatestDeviceConfigVersionFunc: method is nil but Repository.GetLatestDeviceConfigVersion was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+	}
+	mock.lockGetLatestDeviceConfigVersion.Lock()
+	mock.calls.GetLatestDeviceConfigVersion = append(mock.calls.GetLatestDeviceConfigVersion, callInfo)
+	mock.lockGetLatestDeviceConfigVersion.Unlock()
+	return mock.GetLatestDeviceConfigVersionFunc(ctx, deviceID)
+}
+
+// GetLatestDeviceConfigVersionCalls gets all the calls that were made to GetLatestDeviceConfigVersion.
+// Check the length with:
+//
+//	len(mockedRepository.GetLatestDeviceConfigVersionCalls())
+func (mock *RepositoryMock) GetLatestDeviceConfigVersionCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+	}
+	mock.lockGetLatestDeviceConfigVersion.RLock()
+	calls = mock.calls.GetLatestDeviceConfigVersion
+	mock.lockGetLatestDeviceConfigVersion.RUnlock()
+	return calls
+}
+
+// GetMetricRange calls GetMetricRangeFunc.
+func (mock *RepositoryMock) GetMetricRange(ctx context.Context, deviceID string, name string, start time.Time, end time.Time) (MetricRange, error) {
+	if mock.GetMetricRangeFunc == nil {
+		panic("RepositoryMock.GetMetricRangeFunc: method is nil but Repository.GetMetricRange was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		Name     string
+		Start    time.Time
+		End      time.Time
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		Name:     name,
+		Start:    start,
+		End:      end,
+	}
+	mock.lockGetMetricRange.Lock()
+	mock.calls.GetMetricRange = append(mock.calls.GetMetricRange, callInfo)
+	mock.lockGetMetricRange.Unlock()
+	return mock.GetMetricRangeFunc(ctx, deviceID, name, start, end)
+}
+
+// GetMetricRangeCalls gets all the calls that were made to GetMetricRange.
+// Check the length with:
+//
+//	len(mockedRepository.GetMetricRangeCalls())
+func (mock *RepositoryMock) GetMetricRangeCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	Name     string
+	Start    time.Time
+	End      time.Time
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		Name     string
+		Start    time.Time
+		End      time.Time
+	}
+	mock.lockGetMetricRange.RLock()
+	calls = mock.calls.GetMetricRange
+	mock.lockGetMetricRange.RUnlock()
+	return calls
+}
+
+// GetOperation calls GetOperationFunc.
+func (mock *RepositoryMock) GetOperation(ctx context.Context, id int64) (Operation, error) {
+	if mock.GetOperationFunc == nil {
+		panic("RepositoryMock.GetOperationFunc: method is nil but Repository.GetOperation was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetOperation.Lock()
+	mock.calls.GetOperation = append(mock.calls.GetOperation, callInfo)
+	mock.lockGetOperation.Unlock()
+	return mock.GetOperationFunc(ctx, id)
+}
+
+// GetOperationCalls gets all the calls that were made to GetOperation.
+// Check the length with:
+//
+//	len(mockedRepository.GetOperationCalls())
+func (mock *RepositoryMock) GetOperationCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetOperation.RLock()
+	calls = mock.calls.GetOperation
+	mock.lockGetOperation.RUnlock()
+	return calls
+}
+
+// ListAlertRules calls ListAlertRulesFunc.
+func (mock *RepositoryMock) ListAlertRules(ctx context.Context, deviceID string) ([]AlertRule, error) {
+	if mock.ListAlertRulesFunc == nil {
+		panic("RepositoryMock.ListAlertRulesFunc: method is nil but Repository.ListAlertRules was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+	}
+	mock.lockListAlertRules.Lock()
+	mock.calls.ListAlertRules = append(mock.calls.ListAlertRules, callInfo)
+	mock.lockListAlertRules.Unlock()
+	return mock.ListAlertRulesFunc(ctx, deviceID)
+}
+
+// ListAlertRulesCalls gets all the calls that were made to ListAlertRules.
+// Check the length with:
+//
+//	len(mockedRepository.ListAlertRulesCalls())
+func (mock *RepositoryMock) ListAlertRulesCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+	}
+	mock.lockListAlertRules.RLock()
+	calls = mock.calls.ListAlertRules
+	mock.lockListAlertRules.RUnlock()
+	return calls
+}
+
+// ListDeviceConfigVersions calls ListDeviceConfigVersionsFunc.
+func (mock *RepositoryMock) ListDeviceConfigVersions(ctx context.Context, deviceID string, pageOpts RepositoryPageOptions) (RepositoryPage[DeviceConfigVersion], error) {
+	if mock.ListDeviceConfigVersionsFunc == nil {
+		panic("RepositoryMock.ListDeviceConfigVersionsFunc: method is nil but Repository.ListDeviceConfigVersions was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		PageOpts RepositoryPageOptions
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		PageOpts: pageOpts,
+	}
+	mock.lockListDeviceConfigVersions.Lock()
+	mock.calls.ListDeviceConfigVersions = append(mock.calls.ListDeviceConfigVersions, callInfo)
+	mock.lockListDeviceConfigVersions.Unlock()
+	return mock.ListDeviceConfigVersionsFunc(ctx, deviceID, pageOpts)
+}
+
+// ListDeviceConfigVersionsCalls gets all the calls that were made to ListDeviceConfigVersions.
+// Check the length with:
+//
+//	len(mockedRepository.ListDeviceConfigVersionsCalls())
+func (mock *RepositoryMock) ListDeviceConfigVersionsCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	PageOpts RepositoryPageOptions
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		PageOpts RepositoryPageOptions
+	}
+	mock.lockListDeviceConfigVersions.RLock()
+	calls = mock.calls.ListDeviceConfigVersions
+	mock.lockListDeviceConfigVersions.RUnlock()
+	return calls
+}
+
+// ListDeviceGroups calls ListDeviceGroupsFunc.
+func (mock *RepositoryMock) ListDeviceGroups(ctx context.Context) ([]DeviceGroup, error) {
+	if mock.ListDeviceGroupsFunc == nil {
+		panic("RepositoryMock.ListDeviceGroupsFunc: method is nil but Repository.ListDeviceGroups was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockListDeviceGroups.Lock()
+	mock.calls.ListDeviceGroups = append(mock.calls.ListDeviceGroups, callInfo)
+	mock.lockListDeviceGroups.Unlock()
+	return mock.ListDeviceGroupsFunc(ctx)
+}
+
+// ListDeviceGroupsCalls gets all the calls that were made to ListDeviceGroups.
+// Check the length with:
+//
+//	len(mockedRepository.ListDeviceGroupsCalls())
+func (mock *RepositoryMock) ListDeviceGroupsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockListDeviceGroups.RLock()
+	calls = mock.calls.ListDeviceGroups
+	mock.lockListDeviceGroups.RUnlock()
+	return calls
+}
+
+// ListDevices calls ListDevicesFunc.
+func (mock *RepositoryMock) ListDevices(ctx context.Context, selector LabelSelector, pageOpts RepositoryPageOptions) (RepositoryPage[Device], error) {
+	if mock.ListDevicesFunc == nil {
+		panic("RepositoryMock.ListDevicesFunc: method is nil but Repository.ListDevices was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Selector LabelSelector
+		PageOpts RepositoryPageOptions
+	}{
+		Ctx:      ctx,
+		Selector: selector,
+		PageOpts: pageOpts,
+	}
+	mock.lockListDevices.Lock()
+	mock.calls.ListDevices = append(mock.calls.ListDevices, callInfo)
+	mock.lockListDevices.Unlock()
+	return mock.ListDevicesFunc(ctx, selector, pageOpts)
+}
+
+// ListDevicesCalls gets all the calls that were made to ListDevices.
+// Check the length with:
+//
+//	len(mockedRepository.ListDevicesCalls())
+func (mock *RepositoryMock) ListDevicesCalls() []struct {
+	Ctx      context.Context
+	Selector LabelSelector
+	PageOpts RepositoryPageOptions
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Selector LabelSelector
+		PageOpts RepositoryPageOptions
+	}
+	mock.lockListDevices.RLock()
+	calls = mock.calls.ListDevices
+	mock.lockListDevices.RUnlock()
+	return calls
+}
+
+// ListOperations calls ListOperationsFunc.
+func (mock *RepositoryMock) ListOperations(ctx context.Context, state OperationState) ([]Operation, error) {
+	if mock.ListOperationsFunc == nil {
+		panic("RepositoryMock.ListOperationsFunc: method is nil but Repository.ListOperations was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		State OperationState
+	}{
+		Ctx:   ctx,
+		State: state,
+	}
+	mock.lockListOperations.Lock()
+	mock.calls.ListOperations = append(mock.calls.ListOperations, callInfo)
+	mock.lockListOperations.Unlock()
+	return mock.ListOperationsFunc(ctx, state)
+}
+
+// ListOperationsCalls gets all the calls that were made to ListOperations.
+// Check the length with:
+//
+//	len(mockedRepository.ListOperationsCalls())
+func (mock *RepositoryMock) ListOperationsCalls() []struct {
+	Ctx   context.Context
+	State OperationState
+} {
+	var calls []struct {
+		Ctx   context.Context
+		State OperationState
+	}
+	mock.lockListOperations.RLock()
+	calls = mock.calls.ListOperations
+	mock.lockListOperations.RUnlock()
+	return calls
+}
+
+// ListReportingDevices calls ListReportingDevicesFunc.
+func (mock *RepositoryMock) ListReportingDevices(ctx context.Context) ([]ReportingDevice, error) {
+	if mock.ListReportingDevicesFunc == nil {
+		panic("RepositoryMock.ListReportingDevicesFunc: method is nil but Repository.ListReportingDevices was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockListReportingDevices.Lock()
+	mock.calls.ListReportingDevices = append(mock.calls.ListReportingDevices, callInfo)
+	mock.lockListReportingDevices.Unlock()
+	return mock.ListReportingDevicesFunc(ctx)
 }
 
-// GetDeviceAlerts calls GetDeviceAlertsFunc.
-func (mock *RepositoryMock) GetDeviceAlerts(ctx context.Context, deviceID string, timeframe Timeframe, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error) {
-	if mock.GetDeviceAlertsFunc == nil {
-		panic("RepositoryMock.GetDeviceAlertsFunc: method is nil but Repository.GetDeviceAlerts was just called")
+// ListReportingDevicesCalls gets all the calls that were made to ListReportingDevices.
+// Check the length with:
+//
+//	len(mockedRepository.ListReportingDevicesCalls())
+func (mock *RepositoryMock) ListReportingDevicesCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockListReportingDevices.RLock()
+	calls = mock.calls.ListReportingDevices
+	mock.lockListReportingDevices.RUnlock()
+	return calls
+}
+
+// RecordAlertOccurrence calls RecordAlertOccurrenceFunc.
+func (mock *RepositoryMock) RecordAlertOccurrence(ctx context.Context, deviceID string, alertID int64, at time.Time) error {
+	if mock.RecordAlertOccurrenceFunc == nil {
+		panic("RepositoryMock.RecordAlertOccurrenceFunc: method is nil but Repository.RecordAlertOccurrence was just called")
 	}
 	callInfo := struct {
-		Ctx       context.Context
-		DeviceID  string
-		Timeframe Timeframe
-		PageOpts  RepositoryPageOptions
+		Ctx      context.Context
+		DeviceID string
+		AlertID  int64
+		At       time.Time
 	}{
-		Ctx:       ctx,
-		DeviceID:  deviceID,
-		Timeframe: timeframe,
-		PageOpts:  pageOpts,
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		AlertID:  alertID,
+		At:       at,
 	}
-	mock.lockGetDeviceAlerts.Lock()
-	mock.calls.GetDeviceAlerts = append(mock.calls.GetDeviceAlerts, callInfo)
-	mock.lockGetDeviceAlerts.Unlock()
-	return mock.GetDeviceAlertsFunc(ctx, deviceID, timeframe, pageOpts)
+	mock.lockRecordAlertOccurrence.Lock()
+	mock.calls.RecordAlertOccurrence = append(mock.calls.RecordAlertOccurrence, callInfo)
+	mock.lockRecordAlertOccurrence.Unlock()
+	return mock.RecordAlertOccurrenceFunc(ctx, deviceID, alertID, at)
 }
 
-// GetDeviceAlertsCalls gets all the calls that were made to GetDeviceAlerts.
+// RecordAlertOccurrenceCalls gets all the calls that were made to RecordAlertOccurrence.
 // Check the length with:
 //
-//	len(mockedRepository.GetDeviceAlertsCalls())
-func (mock *RepositoryMock) GetDeviceAlertsCalls() []struct {
-	Ctx       context.Context
-	DeviceID  string
-	Timeframe Timeframe
-	PageOpts  RepositoryPageOptions
+//	len(mockedRepository.RecordAlertOccurrenceCalls())
+func (mock *RepositoryMock) RecordAlertOccurrenceCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	AlertID  int64
+	At       time.Time
 } {
 	var calls []struct {
-		Ctx       context.Context
-		DeviceID  string
-		Timeframe Timeframe
-		PageOpts  RepositoryPageOptions
+		Ctx      context.Context
+		DeviceID string
+		AlertID  int64
+		At       time.Time
 	}
-	mock.lockGetDeviceAlerts.RLock()
-	calls = mock.calls.GetDeviceAlerts
-	mock.lockGetDeviceAlerts.RUnlock()
+	mock.lockRecordAlertOccurrence.RLock()
+	calls = mock.calls.RecordAlertOccurrence
+	mock.lockRecordAlertOccurrence.RUnlock()
 	return calls
 }
 
-// GetDeviceConfig calls GetDeviceConfigFunc.
-func (mock *RepositoryMock) GetDeviceConfig(ctx context.Context, deviceID string) (Config, error) {
-	if mock.GetDeviceConfigFunc == nil {
-		panic("RepositoryMock.GetDeviceConfigFunc: method is nil but Repository.GetDeviceConfig was just called")
+// ResolveDeviceAlert calls ResolveDeviceAlertFunc.
+func (mock *RepositoryMock) ResolveDeviceAlert(ctx context.Context, deviceID string, alertID int64, at time.Time) error {
+	if mock.ResolveDeviceAlertFunc == nil {
+		panic("RepositoryMock.ResolveDeviceAlertFunc: method is nil but Repository.ResolveDeviceAlert was just called")
 	}
 	callInfo := struct {
 		Ctx      context.Context
 		DeviceID string
+		AlertID  int64
+		At       time.Time
 	}{
 		Ctx:      ctx,
 		DeviceID: deviceID,
+		AlertID:  alertID,
+		At:       at,
 	}
-	mock.lockGetDeviceConfig.Lock()
-	mock.calls.GetDeviceConfig = append(mock.calls.GetDeviceConfig, callInfo)
-	mock.lockGetDeviceConfig.Unlock()
-	return mock.GetDeviceConfigFunc(ctx, deviceID)
+	mock.lockResolveDeviceAlert.Lock()
+	mock.calls.ResolveDeviceAlert = append(mock.calls.ResolveDeviceAlert, callInfo)
+	mock.lockResolveDeviceAlert.Unlock()
+	return mock.ResolveDeviceAlertFunc(ctx, deviceID, alertID, at)
 }
 
-// GetDeviceConfigCalls gets all the calls that were made to GetDeviceConfig.
+// ResolveDeviceAlertCalls gets all the calls that were made to ResolveDeviceAlert.
 // Check the length with:
 //
-//	len(mockedRepository.GetDeviceConfigCalls())
-func (mock *RepositoryMock) GetDeviceConfigCalls() []struct {
+//	len(mockedRepository.ResolveDeviceAlertCalls())
+func (mock *RepositoryMock) ResolveDeviceAlertCalls() []struct {
 	Ctx      context.Context
 	DeviceID string
+	AlertID  int64
+	At       time.Time
 } {
 	var calls []struct {
 		Ctx      context.Context
 		DeviceID string
+		AlertID  int64
+		At       time.Time
 	}
-	mock.lockGetDeviceConfig.RLock()
-	calls = mock.calls.GetDeviceConfig
-	mock.lockGetDeviceConfig.RUnlock()
+	mock.lockResolveDeviceAlert.RLock()
+	calls = mock.calls.ResolveDeviceAlert
+	mock.lockResolveDeviceAlert.RUnlock()
 	return calls
 }
 
-// GetDeviceMetrics calls GetDeviceMetricsFunc.
-func (mock *RepositoryMock) GetDeviceMetrics(ctx context.Context, deviceID string, timeframe Timeframe, pageOpts RepositoryPageOptions) (RepositoryPage[Metric], error) {
-	if mock.GetDeviceMetricsFunc == nil {
-		panic("RepositoryMock.GetDeviceMetricsFunc: method is nil but Repository.GetDeviceMetrics was just called")
+// ResolveDeviceAlertsByCondition calls ResolveDeviceAlertsByConditionFunc.
+func (mock *RepositoryMock) ResolveDeviceAlertsByCondition(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+	if mock.ResolveDeviceAlertsByConditionFunc == nil {
+		panic("RepositoryMock.ResolveDeviceAlertsByConditionFunc: method is nil but Repository.ResolveDeviceAlertsByCondition was just called")
 	}
 	callInfo := struct {
-		Ctx       context.Context
-		DeviceID  string
-		Timeframe Timeframe
-		PageOpts  RepositoryPageOptions
+		Ctx        context.Context
+		DeviceID   string
+		Conditions []string
+		At         time.Time
 	}{
-		Ctx:       ctx,
-		DeviceID:  deviceID,
-		Timeframe: timeframe,
-		PageOpts:  pageOpts,
+		Ctx:        ctx,
+		DeviceID:   deviceID,
+		Conditions: conditions,
+		At:         at,
 	}
-	mock.lockGetDeviceMetrics.Lock()
-	mock.calls.GetDeviceMetrics = append(mock.calls.GetDeviceMetrics, callInfo)
-	mock.lockGetDeviceMetrics.Unlock()
-	return mock.GetDeviceMetricsFunc(ctx, deviceID, timeframe, pageOpts)
+	mock.lockResolveDeviceAlertsByCondition.Lock()
+	mock.calls.ResolveDeviceAlertsByCondition = append(mock.calls.ResolveDeviceAlertsByCondition, callInfo)
+	mock.lockResolveDeviceAlertsByCondition.Unlock()
+	return mock.ResolveDeviceAlertsByConditionFunc(ctx, deviceID, conditions, at)
 }
 
-// GetDeviceMetricsCalls gets all the calls that were made to GetDeviceMetrics.
+// ResolveDeviceAlertsByConditionCalls gets all the calls that were made to ResolveDeviceAlertsByCondition.
 // Check the length with:
 //
-//	len(mockedRepository.GetDeviceMetricsCalls())
-func (mock *RepositoryMock) GetDeviceMetricsCalls() []struct {
-	Ctx       context.Context
-	DeviceID  string
-	Timeframe Timeframe
-	PageOpts  RepositoryPageOptions
+//	len(mockedRepository.ResolveDeviceAlertsByConditionCalls())
+func (mock *RepositoryMock) ResolveDeviceAlertsByConditionCalls() []struct {
+	Ctx        context.Context
+	DeviceID   string
+	Conditions []string
+	At         time.Time
 } {
 	var calls []struct {
-		Ctx       context.Context
-		DeviceID  string
-		Timeframe Timeframe
-		PageOpts  RepositoryPageOptions
+		Ctx        context.Context
+		DeviceID   string
+		Conditions []string
+		At         time.Time
 	}
-	mock.lockGetDeviceMetrics.RLock()
-	calls = mock.calls.GetDeviceMetrics
-	mock.lockGetDeviceMetrics.RUnlock()
+	mock.lockResolveDeviceAlertsByCondition.RLock()
+	calls = mock.calls.ResolveDeviceAlertsByCondition
+	mock.lockResolveDeviceAlertsByCondition.RUnlock()
+	return calls
+}
+
+// SaveConditionStates calls SaveConditionStatesFunc.
+func (mock *RepositoryMock) SaveConditionStates(ctx context.Context, deviceID string, states map[string]ConditionState) error {
+	if mock.SaveConditionStatesFunc == nil {
+		panic("RepositoryMock.SaveConditionStatesFunc: method is nil but Repository.SaveConditionStates was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		States   map[string]ConditionState
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		States:   states,
+	}
+	mock.lockSaveConditionStates.Lock()
+	mock.calls.SaveConditionStates = append(mock.calls.SaveConditionStates, callInfo)
+	mock.lockSaveConditionStates.Unlock()
+	return mock.SaveConditionStatesFunc(ctx, deviceID, states)
+}
+
+// SaveConditionStatesCalls gets all the calls that were made to SaveConditionStates.
+// Check the length with:
+//
+//	len(mockedRepository.SaveConditionStatesCalls())
+func (mock *RepositoryMock) SaveConditionStatesCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	States   map[string]ConditionState
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		States   map[string]ConditionState
+	}
+	mock.lockSaveConditionStates.RLock()
+	calls = mock.calls.SaveConditionStates
+	mock.lockSaveConditionStates.RUnlock()
 	return calls
 }
 
 // SaveDeviceAlert calls SaveDeviceAlertFunc.
-func (mock *RepositoryMock) SaveDeviceAlert(ctx context.Context, deviceID string, alert Alert) error {
+func (mock *RepositoryMock) SaveDeviceAlert(ctx context.Context, deviceID string, alert Alert) (int64, error) {
 	if mock.SaveDeviceAlertFunc == nil {
 		panic("RepositoryMock.SaveDeviceAlertFunc: method is nil but Repository.SaveDeviceAlert was just called")
 	}
@@ -332,24 +2263,230 @@ func (mock *RepositoryMock) SaveDeviceMetricCalls() []struct {
 	return calls
 }
 
-// UpsertDeviceConfig calls UpsertDeviceConfigFunc.
-func (mock *RepositoryMock) UpsertDeviceConfig(ctx context.Context, deviceID string, config Config) error {
-	if mock.UpsertDeviceConfigFunc == nil {
-		panic("RepositoryMock.UpsertDeviceConfigFunc: method is nil but Repository.UpsertDeviceConfig was just called")
+// SaveDeviceMetrics calls SaveDeviceMetricsFunc.
+func (mock *RepositoryMock) SaveDeviceMetrics(ctx context.Context, deviceID string, metrics []Metric) error {
+	if mock.SaveDeviceMetricsFunc == nil {
+		panic("RepositoryMock.SaveDeviceMetricsFunc: method is nil but Repository.SaveDeviceMetrics was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		DeviceID string
+		Metrics  []Metric
+	}{
+		Ctx:      ctx,
+		DeviceID: deviceID,
+		Metrics:  metrics,
+	}
+	mock.lockSaveDeviceMetrics.Lock()
+	mock.calls.SaveDeviceMetrics = append(mock.calls.SaveDeviceMetrics, callInfo)
+	mock.lockSaveDeviceMetrics.Unlock()
+	return mock.SaveDeviceMetricsFunc(ctx, deviceID, metrics)
+}
+
+// SaveDeviceMetricsCalls gets all the calls that were made to SaveDeviceMetrics.
+// Check the length with:
+//
+//	len(mockedRepository.SaveDeviceMetricsCalls())
+func (mock *RepositoryMock) SaveDeviceMetricsCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	Metrics  []Metric
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		Metrics  []Metric
+	}
+	mock.lockSaveDeviceMetrics.RLock()
+	calls = mock.calls.SaveDeviceMetrics
+	mock.lockSaveDeviceMetrics.RUnlock()
+	return calls
+}
+
+// SearchAlerts calls SearchAlertsFunc.
+func (mock *RepositoryMock) SearchAlerts(ctx context.Context, deviceIDs []string, selector LabelSelector, timeframe Timeframe, filter AlertFilter, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error) {
+	if mock.SearchAlertsFunc == nil {
+		panic("RepositoryMock.SearchAlertsFunc: method is nil but Repository.SearchAlerts was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		DeviceIDs []string
+		Selector  LabelSelector
+		Timeframe Timeframe
+		Filter    AlertFilter
+		PageOpts  RepositoryPageOptions
+	}{
+		Ctx:       ctx,
+		DeviceIDs: deviceIDs,
+		Selector:  selector,
+		Timeframe: timeframe,
+		Filter:    filter,
+		PageOpts:  pageOpts,
+	}
+	mock.lockSearchAlerts.Lock()
+	mock.calls.SearchAlerts = append(mock.calls.SearchAlerts, callInfo)
+	mock.lockSearchAlerts.Unlock()
+	return mock.SearchAlertsFunc(ctx, deviceIDs, selector, timeframe, filter, pageOpts)
+}
+
+// SearchAlertsCalls gets all the calls that were made to SearchAlerts.
+// Check the length with:
+//
+//	len(mockedRepository.SearchAlertsCalls())
+func (mock *RepositoryMock) SearchAlertsCalls() []struct {
+	Ctx       context.Context
+	DeviceIDs []string
+	Selector  LabelSelector
+	Timeframe Timeframe
+	Filter    AlertFilter
+	PageOpts  RepositoryPageOptions
+} {
+	var calls []struct {
+		Ctx       context.Context
+		DeviceIDs []string
+		Selector  LabelSelector
+		Timeframe Timeframe
+		Filter    AlertFilter
+		PageOpts  RepositoryPageOptions
+	}
+	mock.lockSearchAlerts.RLock()
+	calls = mock.calls.SearchAlerts
+	mock.lockSearchAlerts.RUnlock()
+	return calls
+}
+
+// UpdateAlertRule calls UpdateAlertRuleFunc.
+func (mock *RepositoryMock) UpdateAlertRule(ctx context.Context, deviceID string, rule AlertRule) error {
+	if mock.UpdateAlertRuleFunc == nil {
+		panic("RepositoryMock.UpdateAlertRuleFunc: method is nil but Repository.UpdateAlertRule was just called")
 	}
 	callInfo := struct {
 		Ctx      context.Context
 		DeviceID string
-		Config   Config
+		Rule     AlertRule
 	}{
 		Ctx:      ctx,
 		DeviceID: deviceID,
-		Config:   config,
+		Rule:     rule,
+	}
+	mock.lockUpdateAlertRule.Lock()
+	mock.calls.UpdateAlertRule = append(mock.calls.UpdateAlertRule, callInfo)
+	mock.lockUpdateAlertRule.Unlock()
+	return mock.UpdateAlertRuleFunc(ctx, deviceID, rule)
+}
+
+// UpdateAlertRuleCalls gets all the calls that were made to UpdateAlertRule.
+// Check the length with:
+//
+//	len(mockedRepository.UpdateAlertRuleCalls())
+func (mock *RepositoryMock) UpdateAlertRuleCalls() []struct {
+	Ctx      context.Context
+	DeviceID string
+	Rule     AlertRule
+} {
+	var calls []struct {
+		Ctx      context.Context
+		DeviceID string
+		Rule     AlertRule
+	}
+	mock.lockUpdateAlertRule.RLock()
+	calls = mock.calls.UpdateAlertRule
+	mock.lockUpdateAlertRule.RUnlock()
+	return calls
+}
+
+// UpdateDevice calls UpdateDeviceFunc.
+func (mock *RepositoryMock) UpdateDevice(ctx context.Context, device Device) error {
+	if mock.UpdateDeviceFunc == nil {
+		panic("RepositoryMock.UpdateDeviceFunc: method is nil but Repository.UpdateDevice was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Device Device
+	}{
+		Ctx:    ctx,
+		Device: device,
+	}
+	mock.lockUpdateDevice.Lock()
+	mock.calls.UpdateDevice = append(mock.calls.UpdateDevice, callInfo)
+	mock.lockUpdateDevice.Unlock()
+	return mock.UpdateDeviceFunc(ctx, device)
+}
+
+// UpdateDeviceCalls gets all the calls that were made to UpdateDevice.
+// Check the length with:
+//
+//	len(mockedRepository.UpdateDeviceCalls())
+func (mock *RepositoryMock) UpdateDeviceCalls() []struct {
+	Ctx    context.Context
+	Device Device
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Device Device
+	}
+	mock.lockUpdateDevice.RLock()
+	calls = mock.calls.UpdateDevice
+	mock.lockUpdateDevice.RUnlock()
+	return calls
+}
+
+// UpdateOperation calls UpdateOperationFunc.
+func (mock *RepositoryMock) UpdateOperation(ctx context.Context, op Operation) error {
+	if mock.UpdateOperationFunc == nil {
+		panic("RepositoryMock.UpdateOperationFunc: method is nil but Repository.UpdateOperation was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Op  Operation
+	}{
+		Ctx: ctx,
+		Op:  op,
+	}
+	mock.lockUpdateOperation.Lock()
+	mock.calls.UpdateOperation = append(mock.calls.UpdateOperation, callInfo)
+	mock.lockUpdateOperation.Unlock()
+	return mock.UpdateOperationFunc(ctx, op)
+}
+
+// UpdateOperationCalls gets all the calls that were made to UpdateOperation.
+// Check the length with:
+//
+//	len(mockedRepository.UpdateOperationCalls())
+func (mock *RepositoryMock) UpdateOperationCalls() []struct {
+	Ctx context.Context
+	Op  Operation
+} {
+	var calls []struct {
+		Ctx context.Context
+		Op  Operation
+	}
+	mock.lockUpdateOperation.RLock()
+	calls = mock.calls.UpdateOperation
+	mock.lockUpdateOperation.RUnlock()
+	return calls
+}
+
+// UpsertDeviceConfig calls UpsertDeviceConfigFunc.
+func (mock *RepositoryMock) UpsertDeviceConfig(ctx context.Context, deviceID string, version DeviceConfigVersion, expectedRevision *int64) (int64, error) {
+	if mock.UpsertDeviceConfigFunc == nil {
+		panic("RepositoryMock.UpsertDeviceConfigFunc: method is nil but Repository.UpsertDeviceConfig was just called")
+	}
+	callInfo := struct {
+		Ctx              context.Context
+		DeviceID         string
+		Version          DeviceConfigVersion
+		ExpectedRevision *int64
+	}{
+		Ctx:              ctx,
+		DeviceID:         deviceID,
+		Version:          version,
+		ExpectedRevision: expectedRevision,
 	}
 	mock.lockUpsertDeviceConfig.Lock()
 	mock.calls.UpsertDeviceConfig = append(mock.calls.UpsertDeviceConfig, callInfo)
 	mock.lockUpsertDeviceConfig.Unlock()
-	return mock.UpsertDeviceConfigFunc(ctx, deviceID, config)
+	return mock.UpsertDeviceConfigFunc(ctx, deviceID, version, expectedRevision)
 }
 
 // UpsertDeviceConfigCalls gets all the calls that were made to UpsertDeviceConfig.
@@ -357,17 +2494,95 @@ func (mock *RepositoryMock) UpsertDeviceConfig(ctx context.Context, deviceID str
 //
 //	len(mockedRepository.UpsertDeviceConfigCalls())
 func (mock *RepositoryMock) UpsertDeviceConfigCalls() []struct {
-	Ctx      context.Context
-	DeviceID string
-	Config   Config
+	Ctx              context.Context
+	DeviceID         string
+	Version          DeviceConfigVersion
+	ExpectedRevision *int64
 } {
 	var calls []struct {
-		Ctx      context.Context
-		DeviceID string
-		Config   Config
+		Ctx              context.Context
+		DeviceID         string
+		Version          DeviceConfigVersion
+		ExpectedRevision *int64
 	}
 	mock.lockUpsertDeviceConfig.RLock()
 	calls = mock.calls.UpsertDeviceConfig
 	mock.lockUpsertDeviceConfig.RUnlock()
 	return calls
 }
+
+// UpsertDeviceGroupConfig calls UpsertDeviceGroupConfigFunc.
+func (mock *RepositoryMock) UpsertDeviceGroupConfig(ctx context.Context, groupID string, settings ConfigSettings) error {
+	if mock.UpsertDeviceGroupConfigFunc == nil {
+		panic("RepositoryMock.UpsertDeviceGroupConfigFunc: method is nil but Repository.UpsertDeviceGroupConfig was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		GroupID  string
+		Settings ConfigSettings
+	}{
+		Ctx:      ctx,
+		GroupID:  groupID,
+		Settings: settings,
+	}
+	mock.lockUpsertDeviceGroupConfig.Lock()
+	mock.calls.UpsertDeviceGroupConfig = append(mock.calls.UpsertDeviceGroupConfig, callInfo)
+	mock.lockUpsertDeviceGroupConfig.Unlock()
+	return mock.UpsertDeviceGroupConfigFunc(ctx, groupID, settings)
+}
+
+// UpsertDeviceGroupConfigCalls gets all the calls that were made to UpsertDeviceGroupConfig.
+// Check the length with:
+//
+//	len(mockedRepository.UpsertDeviceGroupConfigCalls())
+func (mock *RepositoryMock) UpsertDeviceGroupConfigCalls() []struct {
+	Ctx      context.Context
+	GroupID  string
+	Settings ConfigSettings
+} {
+	var calls []struct {
+		Ctx      context.Context
+		GroupID  string
+		Settings ConfigSettings
+	}
+	mock.lockUpsertDeviceGroupConfig.RLock()
+	calls = mock.calls.UpsertDeviceGroupConfig
+	mock.lockUpsertDeviceGroupConfig.RUnlock()
+	return calls
+}
+
+// UpsertGlobalConfig calls UpsertGlobalConfigFunc.
+func (mock *RepositoryMock) UpsertGlobalConfig(ctx context.Context, settings ConfigSettings) error {
+	if mock.UpsertGlobalConfigFunc == nil {
+		panic("RepositoryMock.UpsertGlobalConfigFunc: method is nil but Repository.UpsertGlobalConfig was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Settings ConfigSettings
+	}{
+		Ctx:      ctx,
+		Settings: settings,
+	}
+	mock.lockUpsertGlobalConfig.Lock()
+	mock.calls.UpsertGlobalConfig = append(mock.calls.UpsertGlobalConfig, callInfo)
+	mock.lockUpsertGlobalConfig.Unlock()
+	return mock.UpsertGlobalConfigFunc(ctx, settings)
+}
+
+// UpsertGlobalConfigCalls gets all the calls that were made to UpsertGlobalConfig.
+// Check the length with:
+//
+//	len(mockedRepository.UpsertGlobalConfigCalls())
+func (mock *RepositoryMock) UpsertGlobalConfigCalls() []struct {
+	Ctx      context.Context
+	Settings ConfigSettings
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Settings ConfigSettings
+	}
+	mock.lockUpsertGlobalConfig.RLock()
+	calls = mock.calls.UpsertGlobalConfig
+	mock.lockUpsertGlobalConfig.RUnlock()
+	return calls
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/joshjon/iot-metrics/http"
@@ -15,110 +17,395 @@ const (
 	defaultPageSize, maxPageSize   = 100, 250
 	minTemperature, maxTemperature = -10000.00, 10000.00
 	minBattery, maxBattery         = 0, 100
+	maxRecordMetricsBatchSize      = 1000
+	maxMetricValues                = 32
+	maxMetricUnitLen               = 32
+	maxConfigExpressions           = 16
+	maxExpressionLen               = 1024
+	maxConsecutiveBreaches         = 100
+	maxAcknowledgedByLen           = 128
+	maxAckCommentLen               = 1024
+	maxConfigAuthorLen             = 128
+	maxConfigReasonLen             = 1024
+	maxCooldownWindow              = 7 * 24 * time.Hour
+	maxRuleWindow                  = 24 * time.Hour
+	maxReportingInterval           = 7 * 24 * time.Hour
+	maxDeviceIDLen                 = 128
+	maxDeviceFieldLen              = 256
+	maxDeviceLabels                = 64
+	maxAggregateBuckets            = 50000
+	maxSearchDeviceIDs             = 100
+	defaultTopN, maxTopN           = 10, 100
 )
 
 // Service handles business logic for devices.
 type Service struct {
 	repo   Repository
 	logger log.Logger
+	alerts *alertBroker
+	exprs  *expressionCache
+	// devices serializes the evaluation of alerts for each device.
+	devices *deviceLocks
+	// operations runs long-running operations, such as device purges.
+	operations *operationRunner
+	pageTokens *pageTokens
 }
 
-func NewService(repo Repository, logger log.Logger) *Service {
-	return &Service{
-		repo:   repo,
-		logger: logger,
+// ServiceOption configures a Service.
+type ServiceOption func(s *Service)
+
+// WithPageTokens sets the key used to authenticate page tokens and how long
+// page tokens are valid for. Without a key, a random key is generated, so page
+// tokens are not valid across restarts or between replicas.
+func WithPageTokens(key []byte, ttl time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.pageTokens = newPageTokens(key, ttl)
+	}
+}
+
+func NewService(repo Repository, logger log.Logger, opts ...ServiceOption) *Service {
+	s := &Service{
+		repo:       repo,
+		logger:     logger,
+		alerts:     newAlertBroker(),
+		exprs:      newExpressionCache(),
+		devices:    newDeviceLocks(),
+		operations: newOperationRunner(),
+		pageTokens: newPageTokens(nil, defaultPageTokenTTL),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Close ends all active alert watches and interrupts running operations. It
+// should be called when the server is shutting down so that long-lived streams
+// and operations do not block a graceful shutdown.
+func (s *Service) Close() {
+	s.alerts.close()
+	s.operations.close()
 }
 
-// ConfigureDevice validates and stores threshold configuration for a device.
-func (s *Service) ConfigureDevice(ctx context.Context, req ConfigureDeviceRequest) error {
+// ConfigureDevice validates and stores the config settings of a device as a
+// new config version, replacing any existing device settings, and returns the
+// new revision. Settings that are omitted are inherited from the device group
+// or the global defaults.
+func (s *Service) ConfigureDevice(ctx context.Context, req ConfigureDeviceRequest) (int64, error) {
 	if err := validateConfigureDeviceReq(req); err != nil {
-		return err
+		return 0, err
 	}
 
-	cfg := Config{
-		TemperatureThreshold: req.TemperatureThreshold,
-		BatteryThreshold:     req.BatteryThreshold,
+	version := DeviceConfigVersion{
+		Settings:  req.settings(),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Author:    req.Author,
+		Reason:    req.Reason,
 	}
-	if err := s.repo.UpsertDeviceConfig(ctx, req.DeviceID, cfg); err != nil {
-		return fmt.Errorf("upsert device config: %w", err)
+	num, err := s.repo.UpsertDeviceConfig(ctx, req.DeviceID, version, req.ExpectedRevision)
+	if err != nil {
+		return 0, upsertDeviceConfigErr(req.DeviceID, req.ExpectedRevision, err)
 	}
 
 	s.logger.Info("configured device",
 		"device_id", req.DeviceID,
-		"temperature_threshold", req.TemperatureThreshold,
-		"battery_threshold", req.BatteryThreshold,
+		"version", num,
+		"settings", version.Settings.Names(),
+	)
+
+	return num, nil
+}
+
+// GetDeviceConfig retrieves the current version of the config settings set for
+// a device. Settings inherited from its group or the defaults are not included.
+func (s *Service) GetDeviceConfig(ctx context.Context, req GetDeviceConfigRequest) (DeviceConfigVersionBody, error) {
+	if err := validateGetDeviceConfigReq(req); err != nil {
+		return DeviceConfigVersionBody{}, err
+	}
+
+	version, err := s.repo.GetLatestDeviceConfigVersion(ctx, req.DeviceID)
+	if err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return DeviceConfigVersionBody{}, &http.NotFoundError{
+				Message: fmt.Sprintf("config for device %s not found", req.DeviceID),
+			}
+		}
+		return DeviceConfigVersionBody{}, fmt.Errorf("get latest device config version: %w", err)
+	}
+	return newDeviceConfigVersionBody(version), nil
+}
+
+// UpdateDeviceConfig validates and updates the config settings in the update
+// mask of a device, keeping its other settings, and stores the result as a new
+// config version. Settings in the mask that are omitted are cleared so that
+// they are inherited.
+func (s *Service) UpdateDeviceConfig(ctx context.Context, req UpdateDeviceConfigRequest) (DeviceConfigVersionBody, error) {
+	if err := validateUpdateDeviceConfigReq(req); err != nil {
+		return DeviceConfigVersionBody{}, err
+	}
+
+	current, err := s.repo.GetLatestDeviceConfigVersion(ctx, req.DeviceID)
+	if err != nil && !errors.Is(err, ErrRepoItemNotFound) {
+		return DeviceConfigVersionBody{}, fmt.Errorf("get latest device config version: %w", err)
+	}
+
+	version := DeviceConfigVersion{
+		Settings:  current.Settings.merge(req.Settings.settings(), req.UpdateMask),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Author:    req.Author,
+		Reason:    req.Reason,
+	}
+	if version.Version, err = s.repo.UpsertDeviceConfig(ctx, req.DeviceID, version, req.ExpectedRevision); err != nil {
+		return DeviceConfigVersionBody{}, upsertDeviceConfigErr(req.DeviceID, req.ExpectedRevision, err)
+	}
+
+	s.logger.Info("updated device config",
+		"device_id", req.DeviceID,
+		"version", version.Version,
+		"settings", req.UpdateMask,
+	)
+
+	return newDeviceConfigVersionBody(version), nil
+}
+
+// ListDeviceConfigVersions retrieves paginated versions of the config settings
+// set for a device, most recent first.
+func (s *Service) ListDeviceConfigVersions(
+	ctx context.Context,
+	req ListDeviceConfigVersionsRequest,
+) (ListDeviceConfigVersionsResponse, error) {
+	if err := validateListDeviceConfigVersionsReq(req); err != nil {
+		return ListDeviceConfigVersionsResponse{}, err
+	}
+
+	scope := pageScope{Method: "ListDeviceConfigVersions", DeviceID: req.DeviceID}
+	pageOpts, err := s.pageTokens.pageOptions(req.PageSize, req.PageToken, scope)
+	if err != nil {
+		return ListDeviceConfigVersionsResponse{}, err
+	}
+
+	page, err := s.repo.ListDeviceConfigVersions(ctx, req.DeviceID, pageOpts)
+	if err != nil {
+		return ListDeviceConfigVersionsResponse{}, fmt.Errorf("list device config versions: %w", err)
+	}
+
+	var nextPageTkn string
+	if page.NextPageToken != nil {
+		if nextPageTkn, err = s.pageTokens.encode(*page.NextPageToken, scope); err != nil {
+			return ListDeviceConfigVersionsResponse{}, err
+		}
+	}
+
+	versions := make([]DeviceConfigVersionBody, len(page.Items))
+	for i, v := range page.Items {
+		versions[i] = newDeviceConfigVersionBody(v)
+	}
+	return ListDeviceConfigVersionsResponse{
+		Versions:      versions,
+		NextPageToken: nextPageTkn,
+	}, nil
+}
+
+// RollbackDeviceConfig restores the settings of a previous config version of a
+// device. History is append-only, so the settings are stored as a new version.
+func (s *Service) RollbackDeviceConfig(ctx context.Context, req RollbackDeviceConfigRequest) (DeviceConfigVersionBody, error) {
+	if err := validateRollbackDeviceConfigReq(req); err != nil {
+		return DeviceConfigVersionBody{}, err
+	}
+
+	prev, err := s.repo.GetDeviceConfigVersion(ctx, req.DeviceID, req.Version)
+	if err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return DeviceConfigVersionBody{}, &http.NotFoundError{
+				Message: fmt.Sprintf("config version %d not found", req.Version),
+			}
+		}
+		return DeviceConfigVersionBody{}, fmt.Errorf("get device config version: %w", err)
+	}
+
+	version := DeviceConfigVersion{
+		Settings:  prev.Settings,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Author:    req.Author,
+		Reason:    req.Reason,
+	}
+	if version.Reason == "" {
+		version.Reason = fmt.Sprintf("Rollback to version %d", req.Version)
+	}
+	if version.Version, err = s.repo.UpsertDeviceConfig(ctx, req.DeviceID, version, req.ExpectedRevision); err != nil {
+		return DeviceConfigVersionBody{}, upsertDeviceConfigErr(req.DeviceID, req.ExpectedRevision, err)
+	}
+
+	s.logger.Info("rolled back device config",
+		"device_id", req.DeviceID,
+		"version", version.Version,
+		"rolled_back_to", req.Version,
 	)
 
+	return newDeviceConfigVersionBody(version), nil
+}
+
+// ConfigureDeviceGroup validates and stores the config settings inherited by
+// the devices of a group, replacing any existing group settings.
+func (s *Service) ConfigureDeviceGroup(ctx context.Context, req ConfigureDeviceGroupRequest) error {
+	if err := validateConfigureDeviceGroupReq(req); err != nil {
+		return err
+	}
+
+	if _, err := s.getDeviceGroup(ctx, req.GroupID); err != nil {
+		return err
+	}
+	settings := req.settings()
+	if err := s.repo.UpsertDeviceGroupConfig(ctx, req.GroupID, settings); err != nil {
+		return fmt.Errorf("upsert device group config: %w", err)
+	}
+
+	s.logger.Info("configured device group", "group_id", req.GroupID, "settings", settings.Names())
+
+	return nil
+}
+
+// ConfigureDefaults validates and stores the global default config settings
+// inherited by all devices, replacing any existing defaults.
+func (s *Service) ConfigureDefaults(ctx context.Context, req ConfigureDefaultsRequest) error {
+	if err := validateConfigureDefaultsReq(req); err != nil {
+		return err
+	}
+
+	settings := req.settings()
+	if err := s.repo.UpsertGlobalConfig(ctx, settings); err != nil {
+		return fmt.Errorf("upsert global config: %w", err)
+	}
+
+	s.logger.Info("configured defaults", "settings", settings.Names())
+
 	return nil
 }
 
+// GetEffectiveDeviceConfig retrieves the config of a registered device resolved
+// from the device, its group and the global defaults, along with the level
+// that each setting was resolved from.
+func (s *Service) GetEffectiveDeviceConfig(
+	ctx context.Context,
+	req GetEffectiveDeviceConfigRequest,
+) (GetEffectiveDeviceConfigResponse, error) {
+	if err := validateGetEffectiveDeviceConfigReq(req); err != nil {
+		return GetEffectiveDeviceConfigResponse{}, err
+	}
+
+	dev, err := s.getDevice(ctx, req.DeviceID)
+	if err != nil {
+		return GetEffectiveDeviceConfigResponse{}, err
+	}
+	cfg, err := s.repo.GetDeviceConfig(ctx, req.DeviceID)
+	if err != nil && !errors.Is(err, ErrRepoItemNotFound) {
+		return GetEffectiveDeviceConfigResponse{}, fmt.Errorf("get device config: %w", err)
+	}
+
+	sources := cfg.Sources
+	if sources == nil {
+		sources = make(map[ConfigSetting]ConfigLevel)
+	}
+	return GetEffectiveDeviceConfigResponse{
+		GroupID:  dev.GroupID,
+		Settings: newConfigSettingsBody(cfg.Settings()),
+		Sources:  sources,
+	}, nil
+}
+
 // RecordMetric validates and saves a metric for a device, then evaluates it
-// against configured thresholds to determine if an alert should be triggered.
+// against configured alert rules to determine if an alert should be triggered.
 func (s *Service) RecordMetric(ctx context.Context, req RecordMetricRequest) error {
 	if err := validateRecordMetricReq(req); err != nil {
 		return err
 	}
 
-	timestamp := req.Timestamp.UTC()
-	logger := s.logger.With("device_id", req.DeviceID, "timestamp", timestamp.Format(time.RFC3339))
+	metric := newMetric(req.Timestamp, req.Temperature, req.Battery, req.Values)
+	logger := s.logger.With("device_id", req.DeviceID, "timestamp", metric.Time.Format(time.RFC3339))
+
+	unlock := s.devices.lock(req.DeviceID)
+	defer unlock()
+
+	alerting, err := s.deviceAlerting(ctx, req.DeviceID)
+	if err != nil {
+		return err
+	}
 
-	metric := Metric{
-		Temperature: req.Temperature,
-		Battery:     req.Battery,
-		Time:        timestamp,
+	var prev *Metric
+	if alerting.usesPrev() {
+		if prev, err = s.latestMetric(ctx, req.DeviceID); err != nil {
+			return err
+		}
 	}
-	if err := s.repo.SaveDeviceMetric(ctx, req.DeviceID, metric); err != nil {
+
+	if err = s.repo.SaveDeviceMetric(ctx, req.DeviceID, metric); err != nil {
 		return fmt.Errorf("save device metric: %w", err)
 	}
 
-	logger.Info("recorded metric", "temperature", req.Temperature, "battery", req.Battery)
+	logger.Info("recorded metric", metricLogArgs(metric)...)
 
-	cfg, err := s.repo.GetDeviceConfig(ctx, req.DeviceID)
+	if err = s.evaluateMetric(ctx, logger, req.DeviceID, alerting, metric, prev); err != nil {
+		return err
+	}
+
+	return s.saveConditionStates(ctx, req.DeviceID, alerting)
+}
+
+// RecordMetrics validates and saves a batch of metrics for a device in a
+// single transaction, then evaluates each metric in timestamp order against
+// configured alert rules to determine if alerts should be triggered. Since the
+// metrics are saved by then, evaluation errors are logged rather than returned
+// so that clients do not retry and record the batch twice.
+func (s *Service) RecordMetrics(ctx context.Context, req RecordMetricsRequest) (RecordMetricsResponse, error) {
+	if err := validateRecordMetricsReq(req); err != nil {
+		return RecordMetricsResponse{}, err
+	}
+
+	metrics := make([]Metric, len(req.Metrics))
+	for i, item := range req.Metrics {
+		metrics[i] = newMetric(item.Timestamp, item.Temperature, item.Battery, item.Values)
+	}
+	// buffered readings flushed by a device that was offline may be out of
+	// order, and each metric is evaluated against the one before it
+	slices.SortStableFunc(metrics, func(a, b Metric) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	unlock := s.devices.lock(req.DeviceID)
+	defer unlock()
+
+	alerting, err := s.deviceAlerting(ctx, req.DeviceID)
 	if err != nil {
-		if errors.Is(err, ErrRepoItemNotFound) {
-			// no thresholds configured for the device
-			return nil
-		}
+		return RecordMetricsResponse{}, err
 	}
 
-	if req.Temperature > cfg.TemperatureThreshold {
-		alert := Alert{
-			Reason: AlertReasonTemperatureHigh,
-			Desc:   tempHighDesc(req.Temperature, cfg.TemperatureThreshold),
-			Time:   timestamp,
-		}
-		logger.Info("alert triggered",
-			"reason", alert.Reason,
-			"temperature", req.Temperature,
-			"threshold", cfg.TemperatureThreshold,
-			"difference", fmt.Sprintf("%.2f", req.Temperature-cfg.TemperatureThreshold),
-		)
-		err = s.repo.SaveDeviceAlert(ctx, req.DeviceID, alert)
-		if err != nil {
-			return fmt.Errorf("save temperature alert: %w", err)
+	var prev *Metric
+	if alerting.usesPrev() {
+		if prev, err = s.latestMetric(ctx, req.DeviceID); err != nil {
+			return RecordMetricsResponse{}, err
 		}
 	}
 
-	if req.Battery < cfg.BatteryThreshold {
-		alert := Alert{
-			Reason: AlertReasonBatteryLow,
-			Desc:   batteryLowDesc(req.Battery, cfg.BatteryThreshold),
-			Time:   timestamp,
-		}
-		logger.Info("alert triggered",
-			"reason", alert.Reason,
-			"battery", req.Battery,
-			"threshold", cfg.BatteryThreshold,
-			"difference", cfg.BatteryThreshold-req.Battery,
-		)
-		err = s.repo.SaveDeviceAlert(ctx, req.DeviceID, alert)
-		if err != nil {
-			return fmt.Errorf("save battery alert: %w", err)
+	if err = s.repo.SaveDeviceMetrics(ctx, req.DeviceID, metrics); err != nil {
+		return RecordMetricsResponse{}, fmt.Errorf("save device metrics: %w", err)
+	}
+
+	s.logger.Info("recorded metrics", "device_id", req.DeviceID, "count", len(metrics))
+
+	for i, metric := range metrics {
+		logger := s.logger.With("device_id", req.DeviceID, "timestamp", metric.Time.Format(time.RFC3339))
+		if err = s.evaluateMetric(ctx, logger, req.DeviceID, alerting, metric, prev); err != nil {
+			logger.Error("failed to evaluate metric", "error", err)
+			break
 		}
+		prev = &metrics[i]
 	}
 
-	return nil
+	if err = s.saveConditionStates(ctx, req.DeviceID, alerting); err != nil {
+		s.logger.Error("failed to save condition states", "device_id", req.DeviceID, "error", err)
+	}
+
+	return RecordMetricsResponse{Recorded: len(metrics)}, nil
 }
 
 // GetDeviceAlerts retrieves paginated alerts for a device.
@@ -127,29 +414,25 @@ func (s *Service) GetDeviceAlerts(ctx context.Context, req GetDeviceAlertsReques
 		return GetDeviceAlertsResponse{}, err
 	}
 
-	if req.PageSize == 0 {
-		req.PageSize = defaultPageSize
-	} else if req.PageSize > maxPageSize {
-		req.PageSize = maxPageSize
-	}
-
-	var pageTkn *RepositoryPageToken
-	if req.PageToken != "" {
-		dec, err := decodePageToken(req.PageToken)
-		if err != nil {
-			return GetDeviceAlertsResponse{}, &http.BadRequestError{}
-		}
-		pageTkn = &dec
-	}
-
 	timeframe := Timeframe{
 		Start: req.TimeframeStart,
 		End:   req.TimeframeEnd,
 	}
-	page, err := s.repo.GetDeviceAlerts(ctx, req.DeviceID, timeframe, RepositoryPageOptions{
-		Size:  req.PageSize,
-		Token: pageTkn,
-	})
+	filter := AlertFilter{
+		State:   req.State,
+		Reasons: req.Reasons,
+	}
+	scope := pageScope{
+		Method:   "GetDeviceAlerts",
+		DeviceID: req.DeviceID,
+		Filters:  []any{timeframe, filter},
+	}
+	pageOpts, err := s.pageTokens.sortedPageOptions(req.PageSize, req.PageToken, scope, req.Order)
+	if err != nil {
+		return GetDeviceAlertsResponse{}, err
+	}
+
+	page, err := s.repo.GetDeviceAlerts(ctx, req.DeviceID, timeframe, filter, pageOpts)
 	if err != nil {
 		return GetDeviceAlertsResponse{}, fmt.Errorf("get device alerts: %w", err)
 	}
@@ -161,7 +444,7 @@ func (s *Service) GetDeviceAlerts(ctx context.Context, req GetDeviceAlertsReques
 
 	var nextPageTkn string
 	if page.NextPageToken != nil {
-		if nextPageTkn, err = encodePageToken(*page.NextPageToken); err != nil {
+		if nextPageTkn, err = s.pageTokens.encode(*page.NextPageToken, scope); err != nil {
 			return GetDeviceAlertsResponse{}, err
 		}
 	}
@@ -172,12 +455,744 @@ func (s *Service) GetDeviceAlerts(ctx context.Context, req GetDeviceAlertsReques
 	}, nil
 }
 
-func tempHighDesc(temp float64, threshold float64) string {
-	return fmt.Sprintf("Temperature (%.2f) exceeded configured threshold (%.2f)", temp, threshold)
+// SearchAlerts retrieves paginated alerts across all devices, or the requested
+// devices, matching the filters.
+func (s *Service) SearchAlerts(ctx context.Context, req SearchAlertsRequest) (SearchAlertsResponse, error) {
+	if err := validateSearchAlertsReq(req); err != nil {
+		return SearchAlertsResponse{}, err
+	}
+
+	selector, err := parseLabelSelector(req.LabelSelector)
+	if err != nil {
+		return SearchAlertsResponse{}, err
+	}
+	timeframe := Timeframe{
+		Start: req.TimeframeStart,
+		End:   req.TimeframeEnd,
+	}
+	filter := AlertFilter{
+		State:    req.State,
+		Severity: req.Severity,
+		Reasons:  req.Reasons,
+	}
+	scope := pageScope{
+		Method:  "SearchAlerts",
+		Filters: []any{req.DeviceIDs, req.LabelSelector, timeframe, filter},
+	}
+	pageOpts, err := s.pageTokens.pageOptions(req.PageSize, req.PageToken, scope)
+	if err != nil {
+		return SearchAlertsResponse{}, err
+	}
+
+	page, err := s.repo.SearchAlerts(ctx, req.DeviceIDs, selector, timeframe, filter, pageOpts)
+	if err != nil {
+		return SearchAlertsResponse{}, fmt.Errorf("search alerts: %w", err)
+	}
+
+	var nextPageTkn string
+	if page.NextPageToken != nil {
+		if nextPageTkn, err = s.pageTokens.encode(*page.NextPageToken, scope); err != nil {
+			return SearchAlertsResponse{}, err
+		}
+	}
+
+	return SearchAlertsResponse{
+		Alerts:        page.Items,
+		NextPageToken: nextPageTkn,
+	}, nil
 }
 
-func batteryLowDesc(battery int32, threshold int32) string {
-	return fmt.Sprintf("Battery (%d) dropped below configured threshold (%d)", battery, threshold)
+// GetAlertStats counts the alerts of all devices triggered over a timeframe by
+// reason, by device and by bucket, along with the noisiest devices.
+func (s *Service) GetAlertStats(ctx context.Context, req GetAlertStatsRequest) (GetAlertStatsResponse, error) {
+	if err := validateGetAlertStatsReq(req); err != nil {
+		return GetAlertStatsResponse{}, err
+	}
+
+	topN := req.TopN
+	if topN == 0 {
+		topN = defaultTopN
+	}
+	width := req.BucketWidth.Duration()
+	stats, err := s.repo.GetAlertStats(ctx, *req.TimeframeStart, *req.TimeframeEnd, width, topN)
+	if err != nil {
+		return GetAlertStatsResponse{}, fmt.Errorf("get alert stats: %w", err)
+	}
+
+	return GetAlertStatsResponse{
+		Total:      stats.Total,
+		ByReason:   stats.ByReason,
+		ByDevice:   stats.ByDevice,
+		Buckets:    fillEmptyAlertBuckets(stats.Buckets, *req.TimeframeStart, *req.TimeframeEnd, width),
+		TopDevices: stats.TopDevices,
+	}, nil
+}
+
+// WatchDeviceAlertsRequest specifies which alerts to watch and where to resume
+// watching from.
+type WatchDeviceAlertsRequest struct {
+	DeviceID string
+	Reasons  []AlertReason
+	Cursor   string
+	// Subscribed, if set, is called once the request is validated and the
+	// subscription is in place, before any alerts are sent.
+	Subscribed func() error
+}
+
+// WatchDeviceAlerts calls send for each alert triggered for a device, or for
+// all devices if no device ID is provided, until the context is canceled. If a
+// cursor is provided, alerts triggered after the cursor are replayed first.
+func (s *Service) WatchDeviceAlerts(ctx context.Context, req WatchDeviceAlertsRequest, send func(alert Alert, cursor string) error) error {
+	if err := validateWatchDeviceAlertsReq(req); err != nil {
+		return err
+	}
+
+	var cursor *RepositoryPageToken
+	if req.Cursor != "" {
+		dec, err := decodeCursor(req.Cursor)
+		if err != nil || dec.LastID == nil {
+			return &http.BadRequestError{FieldViolations: map[string][]string{
+				"cursor": {"Must be a cursor from a previous response"},
+			}}
+		}
+		cursor = &dec
+	}
+
+	// Subscribe before replaying so that no alerts are missed in between.
+	sub := s.alerts.subscribe(req.DeviceID, req.Reasons)
+	defer s.alerts.unsubscribe(sub)
+
+	if req.Subscribed != nil {
+		if err := req.Subscribed(); err != nil {
+			return err
+		}
+	}
+
+	sendAlert := func(alert Alert) error {
+		cursor, err := encodeCursor(RepositoryPageToken{
+			LastTime: &alert.Time,
+			LastID:   &alert.ID,
+		})
+		if err != nil {
+			return err
+		}
+		return send(alert, cursor)
+	}
+
+	var replayedID int64
+	if cursor != nil {
+		replayedID = *cursor.LastID
+		for {
+			alerts, err := s.repo.GetAlertsAfterID(ctx, req.DeviceID, replayedID, maxPageSize)
+			if err != nil {
+				return fmt.Errorf("get alerts after id: %w", err)
+			}
+			for _, alert := range alerts {
+				replayedID = alert.ID
+				if !sub.matches(alert) {
+					continue
+				}
+				if err = sendAlert(alert); err != nil {
+					return err
+				}
+			}
+			if len(alerts) < maxPageSize {
+				break
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case alert, ok := <-sub.alerts:
+			if !ok {
+				return sub.err
+			}
+			if alert.ID <= replayedID {
+				continue // already sent during replay
+			}
+			if err := sendAlert(alert); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// GetDeviceMetrics retrieves paginated metrics for a device.
+func (s *Service) GetDeviceMetrics(ctx context.Context, req GetDeviceMetricsRequest) (GetDeviceMetricsResponse, error) {
+	if err := validateGetDeviceMetricsReq(req); err != nil {
+		return GetDeviceMetricsResponse{}, err
+	}
+
+	timeframe := Timeframe{
+		Start: req.TimeframeStart,
+		End:   req.TimeframeEnd,
+	}
+	scope := pageScope{
+		Method:   "GetDeviceMetrics",
+		DeviceID: req.DeviceID,
+		Filters:  timeframe,
+	}
+	pageOpts, err := s.pageTokens.sortedPageOptions(req.PageSize, req.PageToken, scope, req.Order)
+	if err != nil {
+		return GetDeviceMetricsResponse{}, err
+	}
+
+	page, err := s.repo.GetDeviceMetrics(ctx, req.DeviceID, timeframe, pageOpts)
+	if err != nil {
+		return GetDeviceMetricsResponse{}, fmt.Errorf("get device metrics: %w", err)
+	}
+
+	var nextPageTkn string
+	if page.NextPageToken != nil {
+		if nextPageTkn, err = s.pageTokens.encode(*page.NextPageToken, scope); err != nil {
+			return GetDeviceMetricsResponse{}, err
+		}
+	}
+
+	return GetDeviceMetricsResponse{
+		Metrics:       page.Items,
+		NextPageToken: nextPageTkn,
+	}, nil
+}
+
+// GetDeviceMetricAggregates retrieves the metrics of a device over a timeframe
+// aggregated in buckets, optionally including empty buckets.
+func (s *Service) GetDeviceMetricAggregates(
+	ctx context.Context,
+	req GetDeviceMetricAggregatesRequest,
+) (GetDeviceMetricAggregatesResponse, error) {
+	if err := validateGetDeviceMetricAggregatesReq(req); err != nil {
+		return GetDeviceMetricAggregatesResponse{}, err
+	}
+
+	width := req.BucketWidth.Duration()
+	aggregates, err := s.repo.GetDeviceMetricAggregates(ctx, req.DeviceID, *req.TimeframeStart, *req.TimeframeEnd, width)
+	if err != nil {
+		return GetDeviceMetricAggregatesResponse{}, fmt.Errorf("get device metric aggregates: %w", err)
+	}
+
+	if req.FillEmpty {
+		for i, a := range aggregates {
+			aggregates[i].Buckets = fillEmptyBuckets(a.Buckets, *req.TimeframeStart, *req.TimeframeEnd, width)
+		}
+	}
+
+	return GetDeviceMetricAggregatesResponse{
+		Metrics: aggregates,
+	}, nil
+}
+
+// CreateAlertRule validates and creates an alert rule for a device.
+func (s *Service) CreateAlertRule(ctx context.Context, req CreateAlertRuleRequest) (AlertRule, error) {
+	if err := validateCreateAlertRuleReq(req); err != nil {
+		return AlertRule{}, err
+	}
+
+	rule := AlertRule{
+		Metric:        req.Metric,
+		Operator:      req.Operator,
+		Threshold:     req.Threshold,
+		ThresholdHigh: req.ThresholdHigh,
+		Severity:      req.Severity,
+		WindowSeconds: req.WindowSeconds,
+	}
+	id, err := s.repo.CreateAlertRule(ctx, req.DeviceID, rule)
+	if err != nil {
+		return AlertRule{}, fmt.Errorf("create alert rule: %w", err)
+	}
+	rule.ID = id
+
+	s.logger.Info("created alert rule", "device_id", req.DeviceID, "rule_id", rule.ID)
+
+	return rule, nil
+}
+
+// GetAlertRule retrieves an alert rule of a device.
+func (s *Service) GetAlertRule(ctx context.Context, req GetAlertRuleRequest) (AlertRule, error) {
+	if err := validateAlertRuleIDReq(req.DeviceID, req.RuleID); err != nil {
+		return AlertRule{}, err
+	}
+
+	rule, err := s.repo.GetAlertRule(ctx, req.DeviceID, req.RuleID)
+	if err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return AlertRule{}, alertRuleNotFoundErr(req.RuleID)
+		}
+		return AlertRule{}, fmt.Errorf("get alert rule: %w", err)
+	}
+	return rule, nil
+}
+
+// ListAlertRules retrieves all alert rules of a device. Rules derived from the
+// device config thresholds are not included.
+func (s *Service) ListAlertRules(ctx context.Context, req ListAlertRulesRequest) (ListAlertRulesResponse, error) {
+	if err := validateListAlertRulesReq(req); err != nil {
+		return ListAlertRulesResponse{}, err
+	}
+
+	rules, err := s.repo.ListAlertRules(ctx, req.DeviceID)
+	if err != nil {
+		return ListAlertRulesResponse{}, fmt.Errorf("list alert rules: %w", err)
+	}
+	return ListAlertRulesResponse{Rules: rules}, nil
+}
+
+// UpdateAlertRule validates and replaces an alert rule of a device.
+func (s *Service) UpdateAlertRule(ctx context.Context, req UpdateAlertRuleRequest) (AlertRule, error) {
+	if err := validateUpdateAlertRuleReq(req); err != nil {
+		return AlertRule{}, err
+	}
+
+	rule := AlertRule{
+		ID:            req.RuleID,
+		Metric:        req.Metric,
+		Operator:      req.Operator,
+		Threshold:     req.Threshold,
+		ThresholdHigh: req.ThresholdHigh,
+		Severity:      req.Severity,
+		WindowSeconds: req.WindowSeconds,
+	}
+	if err := s.repo.UpdateAlertRule(ctx, req.DeviceID, rule); err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return AlertRule{}, alertRuleNotFoundErr(req.RuleID)
+		}
+		return AlertRule{}, fmt.Errorf("update alert rule: %w", err)
+	}
+
+	s.logger.Info("updated alert rule", "device_id", req.DeviceID, "rule_id", rule.ID)
+
+	return rule, nil
+}
+
+// DeleteAlertRule deletes an alert rule of a device.
+func (s *Service) DeleteAlertRule(ctx context.Context, req DeleteAlertRuleRequest) error {
+	if err := validateAlertRuleIDReq(req.DeviceID, req.RuleID); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteAlertRule(ctx, req.DeviceID, req.RuleID); err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return alertRuleNotFoundErr(req.RuleID)
+		}
+		return fmt.Errorf("delete alert rule: %w", err)
+	}
+
+	s.logger.Info("deleted alert rule", "device_id", req.DeviceID, "rule_id", req.RuleID)
+
+	return nil
+}
+
+// AcknowledgeAlert records that an operator is handling an open alert of a
+// device. The alert remains acknowledged until it is resolved.
+func (s *Service) AcknowledgeAlert(ctx context.Context, req AcknowledgeAlertRequest) (Alert, error) {
+	if err := validateAcknowledgeAlertReq(req); err != nil {
+		return Alert{}, err
+	}
+
+	alert, err := s.getAlert(ctx, req.DeviceID, req.AlertID)
+	if err != nil {
+		return Alert{}, err
+	}
+	if alert.State != AlertStateOpen {
+		return Alert{}, alertStateConflictErr(alert)
+	}
+
+	now := time.Now().UTC()
+	err = s.repo.AcknowledgeDeviceAlert(ctx, req.DeviceID, req.AlertID, req.AcknowledgedBy, req.Comment, now)
+	if err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			// the alert changed state since it was retrieved
+			return Alert{}, &http.ConflictError{Message: fmt.Sprintf("alert %d is no longer open", req.AlertID)}
+		}
+		return Alert{}, fmt.Errorf("acknowledge alert: %w", err)
+	}
+
+	s.logger.Info("acknowledged alert", "device_id", req.DeviceID, "alert_id", req.AlertID, "by", req.AcknowledgedBy)
+
+	alert.State = AlertStateAcknowledged
+	alert.AcknowledgedBy = req.AcknowledgedBy
+	alert.AckComment = req.Comment
+	alert.AcknowledgedAt = &now
+	return alert, nil
+}
+
+// ResolveAlert resolves an open or acknowledged alert of a device, e.g. when an
+// operator has fixed the cause before readings return within the threshold.
+func (s *Service) ResolveAlert(ctx context.Context, req ResolveAlertRequest) (Alert, error) {
+	if err := validateResolveAlertReq(req); err != nil {
+		return Alert{}, err
+	}
+
+	alert, err := s.getAlert(ctx, req.DeviceID, req.AlertID)
+	if err != nil {
+		return Alert{}, err
+	}
+	if alert.State == AlertStateResolved {
+		return Alert{}, alertStateConflictErr(alert)
+	}
+
+	now := time.Now().UTC()
+	if err = s.repo.ResolveDeviceAlert(ctx, req.DeviceID, req.AlertID, now); err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			// the alert was resolved since it was retrieved
+			return Alert{}, &http.ConflictError{Message: fmt.Sprintf("alert %d is already resolved", req.AlertID)}
+		}
+		return Alert{}, fmt.Errorf("resolve alert: %w", err)
+	}
+
+	s.logger.Info("resolved alert", "device_id", req.DeviceID, "alert_id", req.AlertID)
+
+	alert.State = AlertStateResolved
+	alert.ResolvedAt = &now
+	return alert, nil
+}
+
+// CreateDevice validates and registers a device.
+func (s *Service) CreateDevice(ctx context.Context, req CreateDeviceRequest) (Device, error) {
+	if err := validateCreateDeviceReq(req); err != nil {
+		return Device{}, err
+	}
+
+	dev := Device{
+		ID:              req.DeviceID,
+		DisplayName:     req.DisplayName,
+		Model:           req.Model,
+		FirmwareVersion: req.FirmwareVersion,
+		Location:        req.Location,
+		Labels:          req.Labels,
+		CreatedAt:       time.Now().UTC().Truncate(time.Second),
+		GroupID:         req.GroupID,
+	}
+	if err := s.validateDeviceGroupExists(ctx, req.GroupID); err != nil {
+		return Device{}, err
+	}
+	if err := s.repo.CreateDevice(ctx, dev); err != nil {
+		if errors.Is(err, ErrRepoItemExists) {
+			return Device{}, &http.AlreadyExistsError{Message: fmt.Sprintf("device %s already exists", req.DeviceID)}
+		}
+		return Device{}, fmt.Errorf("create device: %w", err)
+	}
+
+	s.logger.Info("created device", "device_id", dev.ID)
+
+	return dev, nil
+}
+
+// GetDevice retrieves a registered device.
+func (s *Service) GetDevice(ctx context.Context, req GetDeviceRequest) (Device, error) {
+	if err := validateGetDeviceReq(req); err != nil {
+		return Device{}, err
+	}
+	return s.getDevice(ctx, req.DeviceID)
+}
+
+// UpdateDevice validates and replaces the metadata, labels and group of a
+// registered device.
+func (s *Service) UpdateDevice(ctx context.Context, req UpdateDeviceRequest) (Device, error) {
+	if err := validateUpdateDeviceReq(req); err != nil {
+		return Device{}, err
+	}
+
+	dev := Device{
+		ID:              req.DeviceID,
+		DisplayName:     req.DisplayName,
+		Model:           req.Model,
+		FirmwareVersion: req.FirmwareVersion,
+		Location:        req.Location,
+		Labels:          req.Labels,
+		GroupID:         req.GroupID,
+	}
+	if err := s.validateDeviceGroupExists(ctx, req.GroupID); err != nil {
+		return Device{}, err
+	}
+	if err := s.repo.UpdateDevice(ctx, dev); err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return Device{}, deviceNotFoundErr(req.DeviceID)
+		}
+		return Device{}, fmt.Errorf("update device: %w", err)
+	}
+
+	s.logger.Info("updated device", "device_id", dev.ID)
+
+	return s.getDevice(ctx, req.DeviceID)
+}
+
+// DeleteDevice unregisters a device and deletes its config and alert rules,
+// then purges its metrics and alerts in batches in the background. It returns
+// the operation tracking the purge.
+func (s *Service) DeleteDevice(ctx context.Context, req DeleteDeviceRequest) (Operation, error) {
+	if err := validateDeleteDeviceReq(req); err != nil {
+		return Operation{}, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	op := Operation{
+		Type:      OperationTypeDeleteDevice,
+		DeviceID:  req.DeviceID,
+		State:     OperationStateRunning,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	op, err := s.repo.DeleteDevice(ctx, req.DeviceID, op)
+	if err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return Operation{}, deviceNotFoundErr(req.DeviceID)
+		}
+		return Operation{}, fmt.Errorf("delete device: %w", err)
+	}
+
+	s.logger.Info("deleted device", "device_id", req.DeviceID, "operation_id", op.ID)
+	s.startPurge(op)
+
+	return op, nil
+}
+
+// GetOperation retrieves the status of a long-running operation.
+func (s *Service) GetOperation(ctx context.Context, req GetOperationRequest) (Operation, error) {
+	if err := validateGetOperationReq(req); err != nil {
+		return Operation{}, err
+	}
+
+	op, err := s.repo.GetOperation(ctx, req.OperationID)
+	if err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return Operation{}, &http.NotFoundError{Message: fmt.Sprintf("operation %d not found", req.OperationID)}
+		}
+		return Operation{}, fmt.Errorf("get operation: %w", err)
+	}
+	return op, nil
+}
+
+// ListDevices retrieves paginated registered devices, optionally filtered by a
+// label selector.
+func (s *Service) ListDevices(ctx context.Context, req ListDevicesRequest) (ListDevicesResponse, error) {
+	if err := validateListDevicesReq(req); err != nil {
+		return ListDevicesResponse{}, err
+	}
+
+	scope := pageScope{Method: "ListDevices", Filters: req.LabelSelector}
+	pageOpts, err := s.pageTokens.pageOptions(req.PageSize, req.PageToken, scope)
+	if err != nil {
+		return ListDevicesResponse{}, err
+	}
+
+	selector, err := parseLabelSelector(req.LabelSelector)
+	if err != nil {
+		return ListDevicesResponse{}, err
+	}
+	page, err := s.repo.ListDevices(ctx, selector, pageOpts)
+	if err != nil {
+		return ListDevicesResponse{}, fmt.Errorf("list devices: %w", err)
+	}
+
+	var nextPageTkn string
+	if page.NextPageToken != nil {
+		if nextPageTkn, err = s.pageTokens.encode(*page.NextPageToken, scope); err != nil {
+			return ListDevicesResponse{}, err
+		}
+	}
+
+	return ListDevicesResponse{
+		Devices:       page.Items,
+		NextPageToken: nextPageTkn,
+	}, nil
+}
+
+// GetFleetSnapshot retrieves paginated snapshots of the latest metric and open
+// alert count of registered devices, optionally filtered by a label selector
+// and group.
+func (s *Service) GetFleetSnapshot(ctx context.Context, req GetFleetSnapshotRequest) (GetFleetSnapshotResponse, error) {
+	if err := validateGetFleetSnapshotReq(req); err != nil {
+		return GetFleetSnapshotResponse{}, err
+	}
+
+	scope := pageScope{
+		Method:  "GetFleetSnapshot",
+		Filters: []any{req.LabelSelector, req.GroupID},
+	}
+	pageOpts, err := s.pageTokens.pageOptions(req.PageSize, req.PageToken, scope)
+	if err != nil {
+		return GetFleetSnapshotResponse{}, err
+	}
+
+	selector, err := parseLabelSelector(req.LabelSelector)
+	if err != nil {
+		return GetFleetSnapshotResponse{}, err
+	}
+	page, err := s.repo.GetFleetSnapshot(ctx, selector, req.GroupID, pageOpts)
+	if err != nil {
+		return GetFleetSnapshotResponse{}, fmt.Errorf("get fleet snapshot: %w", err)
+	}
+
+	var nextPageTkn string
+	if page.NextPageToken != nil {
+		if nextPageTkn, err = s.pageTokens.encode(*page.NextPageToken, scope); err != nil {
+			return GetFleetSnapshotResponse{}, err
+		}
+	}
+
+	return GetFleetSnapshotResponse{
+		Devices:       page.Items,
+		NextPageToken: nextPageTkn,
+	}, nil
+}
+
+// CreateDeviceGroup validates and creates a device group.
+func (s *Service) CreateDeviceGroup(ctx context.Context, req CreateDeviceGroupRequest) (DeviceGroup, error) {
+	if err := validateCreateDeviceGroupReq(req); err != nil {
+		return DeviceGroup{}, err
+	}
+
+	group := DeviceGroup{
+		ID:          req.GroupID,
+		DisplayName: req.DisplayName,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+	if err := s.repo.CreateDeviceGroup(ctx, group); err != nil {
+		if errors.Is(err, ErrRepoItemExists) {
+			return DeviceGroup{}, &http.AlreadyExistsError{Message: fmt.Sprintf("device group %s already exists", req.GroupID)}
+		}
+		return DeviceGroup{}, fmt.Errorf("create device group: %w", err)
+	}
+
+	s.logger.Info("created device group", "group_id", group.ID)
+
+	return group, nil
+}
+
+// GetDeviceGroup retrieves a device group.
+func (s *Service) GetDeviceGroup(ctx context.Context, req GetDeviceGroupRequest) (DeviceGroup, error) {
+	if err := validateGetDeviceGroupReq(req); err != nil {
+		return DeviceGroup{}, err
+	}
+	return s.getDeviceGroup(ctx, req.GroupID)
+}
+
+// ListDeviceGroups retrieves all device groups.
+func (s *Service) ListDeviceGroups(ctx context.Context) (ListDeviceGroupsResponse, error) {
+	groups, err := s.repo.ListDeviceGroups(ctx)
+	if err != nil {
+		return ListDeviceGroupsResponse{}, fmt.Errorf("list device groups: %w", err)
+	}
+	return ListDeviceGroupsResponse{Groups: groups}, nil
+}
+
+func (s *Service) getDeviceGroup(ctx context.Context, groupID string) (DeviceGroup, error) {
+	group, err := s.repo.GetDeviceGroup(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return DeviceGroup{}, &http.NotFoundError{Message: fmt.Sprintf("device group %s not found", groupID)}
+		}
+		return DeviceGroup{}, fmt.Errorf("get device group: %w", err)
+	}
+	return group, nil
+}
+
+// validateDeviceGroupExists returns a bad request error if a device is being
+// assigned to a group that does not exist.
+func (s *Service) validateDeviceGroupExists(ctx context.Context, groupID string) error {
+	if groupID == "" {
+		return nil
+	}
+	_, err := s.repo.GetDeviceGroup(ctx, groupID)
+	if errors.Is(err, ErrRepoItemNotFound) {
+		v := http.NewRequestValidator()
+		v.Field("group_id").When(true).Messagef("Device group %s does not exist", groupID)
+		return v.Error()
+	}
+	if err != nil {
+		return fmt.Errorf("get device group: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) getDevice(ctx context.Context, deviceID string) (Device, error) {
+	dev, err := s.repo.GetDevice(ctx, deviceID)
+	if err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return Device{}, deviceNotFoundErr(deviceID)
+		}
+		return Device{}, fmt.Errorf("get device: %w", err)
+	}
+	return dev, nil
+}
+
+func (s *Service) getAlert(ctx context.Context, deviceID string, alertID int64) (Alert, error) {
+	alert, err := s.repo.GetDeviceAlert(ctx, deviceID, alertID)
+	if err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return Alert{}, &http.NotFoundError{Message: fmt.Sprintf("alert %d not found", alertID)}
+		}
+		return Alert{}, fmt.Errorf("get alert: %w", err)
+	}
+	return alert, nil
+}
+
+func alertStateConflictErr(alert Alert) error {
+	return &http.ConflictError{
+		Message: fmt.Sprintf("alert %d is already %s", alert.ID, strings.ToLower(string(alert.State))),
+	}
+}
+
+// configRevision returns the current revision of the config set for a device,
+// or 0 if the device has not been configured.
+func (s *Service) configRevision(ctx context.Context, deviceID string) (int64, error) {
+	version, err := s.repo.GetLatestDeviceConfigVersion(ctx, deviceID)
+	if err != nil {
+		if errors.Is(err, ErrRepoItemNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("get latest device config version: %w", err)
+	}
+	return version.Version, nil
+}
+
+// upsertDeviceConfigErr converts an error from upserting a device config,
+// reporting a revision mismatch as a failed precondition.
+func upsertDeviceConfigErr(deviceID string, expectedRevision *int64, err error) error {
+	if errors.Is(err, ErrRepoRevisionMismatch) && expectedRevision != nil {
+		return &http.PreconditionFailedError{
+			Message: fmt.Sprintf("config for device %s is not at revision %d", deviceID, *expectedRevision),
+		}
+	}
+	return fmt.Errorf("upsert device config: %w", err)
+}
+
+func deviceNotFoundErr(deviceID string) error {
+	return &http.NotFoundError{Message: fmt.Sprintf("device %s not found", deviceID)}
+}
+
+func alertRuleNotFoundErr(ruleID int64) error {
+	return &http.NotFoundError{Message: fmt.Sprintf("alert rule %d not found", ruleID)}
+}
+
+// newMetric creates a metric from request values, falling back to the
+// deprecated temperature and battery fields if no values are provided.
+func newMetric(timestamp time.Time, temperature float64, battery int32, values []RecordMetricValue) Metric {
+	metric := Metric{Time: timestamp.UTC()}
+	if len(values) == 0 {
+		metric.Values = []MetricValue{
+			{Name: MetricTemperature, Value: temperature},
+			{Name: MetricBattery, Value: float64(battery)},
+		}
+		return metric
+	}
+	metric.Values = make([]MetricValue, len(values))
+	for i, v := range values {
+		metric.Values[i] = MetricValue{Name: v.Name, Value: v.Value, Unit: v.Unit}
+	}
+	return metric
+}
+
+func metricLogArgs(metric Metric) []any {
+	args := make([]any, 0, len(metric.Values)*2)
+	for _, v := range metric.Values {
+		args = append(args, v.Name, v.Value)
+	}
+	return args
 }
 
 func ptr[T any](v T) *T {
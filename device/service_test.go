@@ -2,36 +2,72 @@ package device
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	nethttp "net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"connectrpc.com/connect"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
+	"github.com/joshjon/iot-metrics/http"
 	"github.com/joshjon/iot-metrics/log"
+	iotv1 "github.com/joshjon/iot-metrics/proto/gen/iot/v1"
 )
 
+// thresholdSources marks the config thresholds as set for the device, so that
+// the rules derived from them are evaluated.
+var thresholdSources = map[ConfigSetting]ConfigLevel{
+	SettingTemperatureThreshold: ConfigLevelDevice,
+	SettingBatteryThreshold:     ConfigLevelDevice,
+}
+
 func TestHandler_ConfigureDevice(t *testing.T) {
 	ctx := t.Context()
 
 	req := ConfigureDeviceRequest{
-		DeviceID:             "foo",
-		TemperatureThreshold: 5.55,
-		BatteryThreshold:     5,
+		DeviceID: "foo",
+		ConfigSettingsBody: ConfigSettingsBody{
+			TemperatureThreshold:     ptr(5.55),
+			BatteryThreshold:         ptr(int32(5)),
+			Cooldowns:                []ConfigureDeviceCooldown{},
+			ReportingIntervalSeconds: ptr(int64(60)),
+		},
+		Author: "jane",
+		Reason: "Reduce false alarms",
 	}
 
 	r := &RepositoryMock{
-		UpsertDeviceConfigFunc: func(ctx context.Context, deviceID string, cfg Config) error {
+		UpsertDeviceConfigFunc: func(ctx context.Context, deviceID string, version DeviceConfigVersion, expectedRevision *int64) (int64, error) {
 			assert.Equal(t, req.DeviceID, deviceID)
-			assert.Equal(t, req.TemperatureThreshold, cfg.TemperatureThreshold)
-			assert.Equal(t, req.BatteryThreshold, cfg.BatteryThreshold)
-			return nil
+			assert.Equal(t, ConfigSettings{
+				TemperatureThreshold: req.TemperatureThreshold,
+				BatteryThreshold:     req.BatteryThreshold,
+				Cooldowns:            []AlertCooldown{},
+				ReportingInterval:    ptr(time.Minute),
+			}, version.Settings)
+			assert.Equal(t, req.Author, version.Author)
+			assert.Equal(t, req.Reason, version.Reason)
+			assert.WithinDuration(t, time.Now(), version.CreatedAt, 2*time.Second)
+			return 1, nil
 		},
 	}
 
 	s := NewService(r, log.NewLogger())
-	err := s.ConfigureDevice(ctx, req)
-	assert.NoError(t, err)
+	revision, err := s.ConfigureDevice(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), revision)
 }
 
 func TestHandler_ConfigureDevice_requestValidation(t *testing.T) {
@@ -47,32 +83,129 @@ func TestHandler_ConfigureDevice_requestValidation(t *testing.T) {
 				req.DeviceID = ""
 			},
 		},
+		{
+			name:      "author too long",
+			fieldName: "author",
+			override: func(req *ConfigureDeviceRequest) {
+				req.Author = strings.Repeat("a", maxConfigAuthorLen+1)
+			},
+		},
+		{
+			name:      "reason too long",
+			fieldName: "reason",
+			override: func(req *ConfigureDeviceRequest) {
+				req.Reason = strings.Repeat("a", maxConfigReasonLen+1)
+			},
+		},
+		{
+			name:      "negative expected revision",
+			fieldName: "expected_revision",
+			override: func(req *ConfigureDeviceRequest) {
+				req.ExpectedRevision = ptr(int64(-1))
+			},
+		},
 		{
 			name:      "temp threshold below minimum",
 			fieldName: "temperature_threshold",
 			override: func(req *ConfigureDeviceRequest) {
-				req.TemperatureThreshold = minTemperature - 0.01
+				req.TemperatureThreshold = ptr(minTemperature - 0.01)
 			},
 		},
 		{
 			name:      "temp threshold above maximum",
 			fieldName: "temperature_threshold",
 			override: func(req *ConfigureDeviceRequest) {
-				req.TemperatureThreshold = maxTemperature + 0.01
+				req.TemperatureThreshold = ptr(maxTemperature + 0.01)
 			},
 		},
 		{
 			name:      "temp threshold below minimum",
 			fieldName: "battery_threshold",
 			override: func(req *ConfigureDeviceRequest) {
-				req.BatteryThreshold = minBattery - 1
+				req.BatteryThreshold = ptr(int32(minBattery - 1))
 			},
 		},
 		{
 			name:      "battery threshold above maximum",
 			fieldName: "battery_threshold",
 			override: func(req *ConfigureDeviceRequest) {
-				req.BatteryThreshold = maxBattery + 1
+				req.BatteryThreshold = ptr(int32(maxBattery + 1))
+			},
+		},
+		{
+			name:      "expression syntax error",
+			fieldName: "expressions[0].expr",
+			override: func(req *ConfigureDeviceRequest) {
+				req.Expressions = []ConfigureDeviceExpression{{Expr: "temperature >", Severity: AlertSeverityWarning}}
+			},
+		},
+		{
+			name:      "expression undeclared reference",
+			fieldName: "expressions[0].expr",
+			override: func(req *ConfigureDeviceRequest) {
+				req.Expressions = []ConfigureDeviceExpression{{Expr: "Temperature > 40", Severity: AlertSeverityWarning}}
+			},
+		},
+		{
+			name:      "expression not a bool",
+			fieldName: "expressions[1].expr",
+			override: func(req *ConfigureDeviceRequest) {
+				req.Expressions = []ConfigureDeviceExpression{
+					{Expr: "temperature > 40", Severity: AlertSeverityWarning},
+					{Expr: "temperature - prev.temperature", Severity: AlertSeverityWarning},
+				}
+			},
+		},
+		{
+			name:      "expression invalid severity",
+			fieldName: "expressions[0].severity",
+			override: func(req *ConfigureDeviceRequest) {
+				req.Expressions = []ConfigureDeviceExpression{{Expr: "temperature > 40"}}
+			},
+		},
+		{
+			name:      "negative consecutive breaches",
+			fieldName: "consecutive_breaches",
+			override: func(req *ConfigureDeviceRequest) {
+				req.ConsecutiveBreaches = ptr(int32(-1))
+			},
+		},
+		{
+			name:      "consecutive breaches above maximum",
+			fieldName: "consecutive_breaches",
+			override: func(req *ConfigureDeviceRequest) {
+				req.ConsecutiveBreaches = ptr(int32(maxConsecutiveBreaches + 1))
+			},
+		},
+		{
+			name:      "negative hysteresis",
+			fieldName: "hysteresis",
+			override: func(req *ConfigureDeviceRequest) {
+				req.Hysteresis = ptr(-0.5)
+			},
+		},
+		{
+			name:      "cooldown invalid reason",
+			fieldName: "cooldowns[0].reason",
+			override: func(req *ConfigureDeviceRequest) {
+				req.Cooldowns = []ConfigureDeviceCooldown{{Reason: "LOW", WindowSeconds: 60}}
+			},
+		},
+		{
+			name:      "cooldown duplicate reason",
+			fieldName: "cooldowns[1].reason",
+			override: func(req *ConfigureDeviceRequest) {
+				req.Cooldowns = []ConfigureDeviceCooldown{
+					{Reason: AlertReasonBatteryLow, WindowSeconds: 60},
+					{Reason: AlertReasonBatteryLow, WindowSeconds: 120},
+				}
+			},
+		},
+		{
+			name:      "cooldown window not positive",
+			fieldName: "cooldowns[0].window_seconds",
+			override: func(req *ConfigureDeviceRequest) {
+				req.Cooldowns = []ConfigureDeviceCooldown{{Reason: AlertReasonBatteryLow}}
 			},
 		},
 	}
@@ -82,22 +215,26 @@ func TestHandler_ConfigureDevice_requestValidation(t *testing.T) {
 			ctx := t.Context()
 
 			req := ConfigureDeviceRequest{
-				DeviceID:             "foo",
-				TemperatureThreshold: 5.55,
-				BatteryThreshold:     5,
+				DeviceID: "foo",
+				ConfigSettingsBody: ConfigSettingsBody{
+					TemperatureThreshold: ptr(5.55),
+					BatteryThreshold:     ptr(int32(5)),
+				},
 			}
 			require.NotNil(t, tt.override, "test config `override` field must not be nil")
 			tt.override(&req)
 
 			h := NewService(nil, log.NewLogger())
-			err := h.ConfigureDevice(ctx, req)
-			require.Error(t, err)
+			_, err := h.ConfigureDevice(ctx, req)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Contains(t, brErr.FieldViolations, tt.fieldName)
 		})
 	}
 }
 
 func TestHandler_RecordMetric(t *testing.T) {
-	stubCfg := &Config{TemperatureThreshold: 5.55, BatteryThreshold: 5}
+	stubCfg := &Config{TemperatureThreshold: 5.55, BatteryThreshold: 5, Sources: thresholdSources, Version: 3}
 
 	tests := []struct {
 		name             string
@@ -157,8 +294,10 @@ func TestHandler_RecordMetric(t *testing.T) {
 			r := &RepositoryMock{
 				SaveDeviceMetricFunc: func(ctx context.Context, deviceID string, metric Metric) error {
 					assert.Equal(t, req.DeviceID, deviceID)
-					assert.Equal(t, req.Temperature, metric.Temperature)
-					assert.Equal(t, req.Battery, metric.Battery)
+					assert.Equal(t, []MetricValue{
+						{Name: MetricTemperature, Value: req.Temperature},
+						{Name: MetricBattery, Value: float64(req.Battery)},
+					}, metric.Values)
 					assert.Equal(t, req.Timestamp, metric.Time)
 					return nil
 				},
@@ -169,10 +308,19 @@ func TestHandler_RecordMetric(t *testing.T) {
 					}
 					return *tt.deviceCfg, nil
 				},
-				SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) error {
+				ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+					return nil, nil
+				},
+				ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+					return 0, nil
+				},
+				GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+					return Alert{}, ErrRepoItemNotFound
+				},
+				SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
 					assert.Equal(t, req.DeviceID, deviceID)
 					gotAlerts = append(gotAlerts, alert)
-					return nil
+					return int64(len(gotAlerts)), nil
 				},
 			}
 
@@ -185,17 +333,29 @@ func TestHandler_RecordMetric(t *testing.T) {
 			if tt.wantTempAlert {
 				wantAlertsLen++
 				assert.Contains(t, gotAlerts, Alert{
-					Reason: AlertReasonTemperatureHigh,
-					Desc:   tempHighDesc(req.Temperature, tt.deviceCfg.TemperatureThreshold),
-					Time:   req.Timestamp,
+					Reason:        AlertReasonTemperatureHigh,
+					Severity:      AlertSeverityWarning,
+					Desc:          "Temperature (" + fmt.Sprint(req.Temperature) + ") exceeded configured threshold (5.55)",
+					Time:          req.Timestamp,
+					State:         AlertStateOpen,
+					Occurrences:   1,
+					LastSeen:      req.Timestamp,
+					ConfigVersion: stubCfg.Version,
+					Condition:     "config:temperature",
 				})
 			}
 			if tt.wantBatteryAlert {
 				wantAlertsLen++
 				assert.Contains(t, gotAlerts, Alert{
-					Reason: AlertReasonBatteryLow,
-					Desc:   batteryLowDesc(req.Battery, tt.deviceCfg.BatteryThreshold),
-					Time:   req.Timestamp,
+					Reason:        AlertReasonBatteryLow,
+					Severity:      AlertSeverityWarning,
+					Desc:          "Battery (" + fmt.Sprint(req.Battery) + ") dropped below configured threshold (5)",
+					Time:          req.Timestamp,
+					State:         AlertStateOpen,
+					Occurrences:   1,
+					LastSeen:      req.Timestamp,
+					ConfigVersion: stubCfg.Version,
+					Condition:     "config:battery",
 				})
 			}
 			require.Len(t, gotAlerts, wantAlertsLen)
@@ -203,6 +363,71 @@ func TestHandler_RecordMetric(t *testing.T) {
 	}
 }
 
+func TestHandler_RecordMetric_values(t *testing.T) {
+	ctx := t.Context()
+
+	req := RecordMetricRequest{
+		DeviceID: "foo",
+		// deprecated fields are ignored when values are provided
+		Temperature: 100,
+		Battery:     100,
+		Values: []RecordMetricValue{
+			{Name: MetricTemperature, Value: 30, Unit: "celsius"},
+			{Name: "humidity", Value: 99.5, Unit: "%"},
+		},
+		Timestamp: time.Now().UTC(),
+	}
+	cfg := Config{TemperatureThreshold: 25, BatteryThreshold: 50, Sources: thresholdSources}
+
+	var gotAlerts []Alert
+
+	r := &RepositoryMock{
+		SaveDeviceMetricFunc: func(ctx context.Context, deviceID string, metric Metric) error {
+			assert.Equal(t, Metric{
+				Values: []MetricValue{
+					{Name: MetricTemperature, Value: 30, Unit: "celsius"},
+					{Name: "humidity", Value: 99.5, Unit: "%"},
+				},
+				Time: req.Timestamp,
+			}, metric)
+			return nil
+		},
+		GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+			return cfg, nil
+		},
+		ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+			return nil, nil
+		},
+		ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+			return 0, nil
+		},
+		GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+			return Alert{}, ErrRepoItemNotFound
+		},
+		SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+			gotAlerts = append(gotAlerts, alert)
+			return int64(len(gotAlerts)), nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	err := h.RecordMetric(ctx, req)
+	require.NoError(t, err)
+
+	// battery is missing from the reading so only temperature is evaluated
+	require.Equal(t, []Alert{{
+		Reason:      AlertReasonTemperatureHigh,
+		Severity:    AlertSeverityWarning,
+		Desc:        "Temperature (30) exceeded configured threshold (25)",
+		Time:        req.Timestamp,
+		State:       AlertStateOpen,
+		Occurrences: 1,
+		LastSeen:    req.Timestamp,
+		Condition:   "config:temperature",
+	}}, gotAlerts)
+}
+
 func TestHandler_RecordMetric_requestValidation(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -251,6 +476,27 @@ func TestHandler_RecordMetric_requestValidation(t *testing.T) {
 				req.Timestamp = time.Time{}
 			},
 		},
+		{
+			name:      "invalid value name",
+			fieldName: "values[0].name",
+			override: func(req *RecordMetricRequest) {
+				req.Values = []RecordMetricValue{{Name: "Not Valid", Value: 1}}
+			},
+		},
+		{
+			name:      "duplicate value name",
+			fieldName: "values[1].name",
+			override: func(req *RecordMetricRequest) {
+				req.Values = []RecordMetricValue{{Name: "co2", Value: 1}, {Name: "co2", Value: 2}}
+			},
+		},
+		{
+			name:      "temperature value above maximum",
+			fieldName: "values[0].value",
+			override: func(req *RecordMetricRequest) {
+				req.Values = []RecordMetricValue{{Name: MetricTemperature, Value: maxTemperature + 0.01}}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -274,6 +520,164 @@ func TestHandler_RecordMetric_requestValidation(t *testing.T) {
 	}
 }
 
+func TestHandler_RecordMetrics(t *testing.T) {
+	ctx := t.Context()
+
+	cfg := Config{TemperatureThreshold: 5.55, BatteryThreshold: 5, Sources: thresholdSources}
+	now := time.Now().UTC()
+	req := RecordMetricsRequest{
+		DeviceID: "foo",
+		Metrics: []RecordMetricsItem{
+			{Temperature: 5.55, Battery: 5, Timestamp: now.Add(-2 * time.Second)},
+			{Temperature: 5.56, Battery: 5, Timestamp: now.Add(-time.Second)},
+			{Temperature: 5.55, Battery: 4, Timestamp: now},
+		},
+	}
+
+	var gotAlerts []Alert
+
+	r := &RepositoryMock{
+		SaveDeviceMetricsFunc: func(ctx context.Context, deviceID string, metrics []Metric) error {
+			assert.Equal(t, req.DeviceID, deviceID)
+			require.Len(t, metrics, len(req.Metrics))
+			for i, m := range metrics {
+				assert.Equal(t, []MetricValue{
+					{Name: MetricTemperature, Value: req.Metrics[i].Temperature},
+					{Name: MetricBattery, Value: float64(req.Metrics[i].Battery)},
+				}, m.Values)
+				assert.Equal(t, req.Metrics[i].Timestamp, m.Time)
+			}
+			return nil
+		},
+		GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+			assert.Equal(t, req.DeviceID, deviceID)
+			return cfg, nil
+		},
+		ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+			return nil, nil
+		},
+		ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+			return 0, nil
+		},
+		GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+			return Alert{}, ErrRepoItemNotFound
+		},
+		SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+			assert.Equal(t, req.DeviceID, deviceID)
+			gotAlerts = append(gotAlerts, alert)
+			return int64(len(gotAlerts)), nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	res, err := h.RecordMetrics(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, len(req.Metrics), res.Recorded)
+
+	wantAlerts := []Alert{
+		{
+			Reason:      AlertReasonTemperatureHigh,
+			Severity:    AlertSeverityWarning,
+			Desc:        "Temperature (5.56) exceeded configured threshold (5.55)",
+			Time:        req.Metrics[1].Timestamp,
+			State:       AlertStateOpen,
+			Occurrences: 1,
+			LastSeen:    req.Metrics[1].Timestamp,
+			Condition:   "config:temperature",
+		},
+		{
+			Reason:      AlertReasonBatteryLow,
+			Severity:    AlertSeverityWarning,
+			Desc:        "Battery (4) dropped below configured threshold (5)",
+			Time:        req.Metrics[2].Timestamp,
+			State:       AlertStateOpen,
+			Occurrences: 1,
+			LastSeen:    req.Metrics[2].Timestamp,
+			Condition:   "config:battery",
+		},
+	}
+	assert.Equal(t, wantAlerts, gotAlerts)
+	assert.Len(t, r.GetDeviceConfigCalls(), 1)
+}
+
+func TestHandler_RecordMetrics_requestValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		override  func(req *RecordMetricsRequest)
+	}{
+		{
+			name:      "empty device id",
+			fieldName: "device_id",
+			override: func(req *RecordMetricsRequest) {
+				req.DeviceID = ""
+			},
+		},
+		{
+			name:      "empty metrics",
+			fieldName: "metrics",
+			override: func(req *RecordMetricsRequest) {
+				req.Metrics = nil
+			},
+		},
+		{
+			name:      "too many metrics",
+			fieldName: "metrics",
+			override: func(req *RecordMetricsRequest) {
+				req.Metrics = make([]RecordMetricsItem, maxRecordMetricsBatchSize+1)
+				for i := range req.Metrics {
+					req.Metrics[i] = RecordMetricsItem{Temperature: 5.55, Battery: 5, Timestamp: time.Now().UTC()}
+				}
+			},
+		},
+		{
+			name:      "item temp above maximum",
+			fieldName: "metrics[1].temperature",
+			override: func(req *RecordMetricsRequest) {
+				req.Metrics[1].Temperature = maxTemperature + 0.01
+			},
+		},
+		{
+			name:      "item battery below minimum",
+			fieldName: "metrics[0].battery",
+			override: func(req *RecordMetricsRequest) {
+				req.Metrics[0].Battery = minBattery - 1
+			},
+		},
+		{
+			name:      "item empty timestamp",
+			fieldName: "metrics[1].timestamp",
+			override: func(req *RecordMetricsRequest) {
+				req.Metrics[1].Timestamp = time.Time{}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			req := RecordMetricsRequest{
+				DeviceID: "foo",
+				Metrics: []RecordMetricsItem{
+					{Temperature: 5.55, Battery: 5, Timestamp: time.Now().UTC()},
+					{Temperature: 5.55, Battery: 5, Timestamp: time.Now().UTC()},
+				},
+			}
+			require.NotNil(t, tt.override, "test config `override` field must not be nil")
+			tt.override(&req)
+
+			h := NewService(nil, log.NewLogger())
+
+			_, err := h.RecordMetrics(ctx, req)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Contains(t, brErr.FieldViolations, tt.fieldName)
+		})
+	}
+}
+
 func TestHandler_GetDeviceAlerts(t *testing.T) {
 	ctx := t.Context()
 
@@ -287,32 +691,44 @@ func TestHandler_GetDeviceAlerts(t *testing.T) {
 		TimeframeStart: wantTimeframe.Start,
 		TimeframeEnd:   wantTimeframe.End,
 		PageSize:       10,
+		State:          AlertStateOpen,
+		Reasons:        []AlertReason{AlertReasonTemperatureHigh, AlertReasonBatteryLow},
+		Order:          SortOrderAsc,
 	}
 	ptkn := RepositoryPageToken{
 		LastTime: ptr(time.Now().Add(-10 * time.Second).UTC()),
 		LastID:   ptr[int64](1),
+		Order:    SortOrderAsc,
+	}
+	scope := pageScope{
+		Method:   "GetDeviceAlerts",
+		DeviceID: req.DeviceID,
+		Filters:  []any{wantTimeframe, AlertFilter{State: AlertStateOpen, Reasons: req.Reasons}},
 	}
-	reqTkn, err := encodePageToken(ptkn)
+	tokens := newTestPageTokens()
+	reqTkn, err := tokens.encode(ptkn, scope)
 	require.NoError(t, err)
 	req.PageToken = reqTkn
 
 	alerts := []Alert{
-		{Reason: AlertReasonTemperatureHigh, Desc: tempHighDesc(5.56, 5.55), Time: time.Now()},
-		{Reason: AlertReasonBatteryLow, Desc: batteryLowDesc(5, 6), Time: time.Now()},
+		{Reason: AlertReasonTemperatureHigh, Desc: "Temperature (5.56) exceeded configured threshold (5.55)", Time: time.Now()},
+		{Reason: AlertReasonBatteryLow, Desc: "Battery (5) dropped below configured threshold (6)", Time: time.Now()},
 	}
 	nextPageTkn := RepositoryPageToken{
 		LastTime: ptr(time.Now().Add(-20 * time.Second)),
 		LastID:   ptr[int64](2),
 	}
-	wantNextPageTkn, err := encodePageToken(nextPageTkn)
+	wantNextPageTkn, err := tokens.encode(nextPageTkn, scope)
 	require.NoError(t, err)
 
 	r := &RepositoryMock{
-		GetDeviceAlertsFunc: func(ctx context.Context, deviceID string, timeframe Timeframe, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error) {
+		GetDeviceAlertsFunc: func(ctx context.Context, deviceID string, timeframe Timeframe, filter AlertFilter, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error) {
 			assert.Equal(t, req.DeviceID, deviceID)
 			assert.Equal(t, wantTimeframe, timeframe)
+			assert.Equal(t, AlertFilter{State: AlertStateOpen, Reasons: req.Reasons}, filter)
 			assert.Equal(t, int(req.PageSize), pageOpts.Size)
 			assert.Equal(t, ptkn, *pageOpts.Token)
+			assert.Equal(t, SortOrderAsc, pageOpts.Order)
 			return RepositoryPage[Alert]{
 				Items:         alerts,
 				NextPageToken: &nextPageTkn,
@@ -321,6 +737,7 @@ func TestHandler_GetDeviceAlerts(t *testing.T) {
 	}
 
 	h := NewService(r, log.NewLogger())
+	h.pageTokens = tokens
 
 	gotRes, err := h.GetDeviceAlerts(ctx, req)
 	require.NoError(t, err)
@@ -368,6 +785,34 @@ func TestHandler_GetDeviceAlerts_requestValidation(t *testing.T) {
 				req.TimeframeEnd = ptr(time.Now().Add(-time.Minute).UTC())
 			},
 		},
+		{
+			name:      "invalid state",
+			fieldName: "state",
+			override: func(req *GetDeviceAlertsRequest) {
+				req.State = "CLOSED"
+			},
+		},
+		{
+			name:      "invalid reason",
+			fieldName: "reasons[1]",
+			override: func(req *GetDeviceAlertsRequest) {
+				req.Reasons = []AlertReason{AlertReasonBatteryLow, "BATTERY_HIGH"}
+			},
+		},
+		{
+			name:      "invalid order",
+			fieldName: "order",
+			override: func(req *GetDeviceAlertsRequest) {
+				req.Order = "oldest"
+			},
+		},
+		{
+			name:      "page token from request with different order",
+			fieldName: "page_token",
+			override: func(req *GetDeviceAlertsRequest) {
+				req.Order = SortOrderAsc
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -380,10 +825,17 @@ func TestHandler_GetDeviceAlerts_requestValidation(t *testing.T) {
 				TimeframeEnd:   ptr(time.Now().UTC()),
 				PageSize:       10,
 			}
-			reqTkn, err := encodePageToken(RepositoryPageToken{
+			scope := pageScope{
+				Method:   "GetDeviceAlerts",
+				DeviceID: req.DeviceID,
+				Filters:  []any{Timeframe{Start: req.TimeframeStart, End: req.TimeframeEnd}, AlertFilter{}},
+			}
+			tokens := newTestPageTokens()
+			reqTkn, err := tokens.encode(RepositoryPageToken{
 				LastTime: ptr(time.Now().Add(-10 * time.Second).UTC()),
 				LastID:   ptr[int64](1),
-			})
+				Order:    SortOrderDesc,
+			}, scope)
 			require.NoError(t, err)
 			req.PageToken = reqTkn
 
@@ -391,9 +843,3426 @@ func TestHandler_GetDeviceAlerts_requestValidation(t *testing.T) {
 			tt.override(&req)
 
 			h := NewService(nil, log.NewLogger())
+			h.pageTokens = tokens
 
 			_, err = h.GetDeviceAlerts(ctx, req)
-			require.Error(t, err)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Contains(t, brErr.FieldViolations, tt.fieldName)
 		})
 	}
 }
+
+func TestHandler_SearchAlerts(t *testing.T) {
+	ctx := t.Context()
+
+	wantTimeframe := Timeframe{
+		Start: ptr(time.Now().Add(-time.Hour).UTC()),
+		End:   ptr(time.Now().UTC()),
+	}
+
+	req := SearchAlertsRequest{
+		DeviceIDs:      []string{"foo", "bar"},
+		LabelSelector:  "env=prod",
+		Reasons:        []AlertReason{AlertReasonTemperatureHigh, AlertReasonBatteryLow},
+		Severity:       AlertSeverityCritical,
+		State:          AlertStateOpen,
+		TimeframeStart: wantTimeframe.Start,
+		TimeframeEnd:   wantTimeframe.End,
+		PageSize:       10,
+	}
+	ptkn := RepositoryPageToken{
+		LastTime: ptr(time.Now().Add(-10 * time.Second).UTC()),
+		LastID:   ptr[int64](3),
+	}
+	scope := pageScope{
+		Method: "SearchAlerts",
+		Filters: []any{req.DeviceIDs, req.LabelSelector, wantTimeframe, AlertFilter{
+			State:    AlertStateOpen,
+			Severity: AlertSeverityCritical,
+			Reasons:  req.Reasons,
+		}},
+	}
+	tokens := newTestPageTokens()
+	reqTkn, err := tokens.encode(ptkn, scope)
+	require.NoError(t, err)
+	req.PageToken = reqTkn
+
+	alerts := []Alert{
+		{ID: 2, DeviceID: "bar", Reason: AlertReasonBatteryLow, Severity: AlertSeverityCritical, Time: time.Now()},
+		{ID: 1, DeviceID: "foo", Reason: AlertReasonTemperatureHigh, Severity: AlertSeverityCritical, Time: time.Now()},
+	}
+	nextPageTkn := RepositoryPageToken{
+		LastTime: ptr(time.Now().Add(-20 * time.Second)),
+		LastID:   ptr[int64](1),
+	}
+	wantNextPageTkn, err := tokens.encode(nextPageTkn, scope)
+	require.NoError(t, err)
+
+	r := &RepositoryMock{
+		SearchAlertsFunc: func(ctx context.Context, deviceIDs []string, selector LabelSelector, timeframe Timeframe, filter AlertFilter, pageOpts RepositoryPageOptions) (RepositoryPage[Alert], error) {
+			assert.Equal(t, req.DeviceIDs, deviceIDs)
+			assert.Equal(t, LabelSelector{{Key: "env", Operator: LabelOperatorEquals, Value: "prod"}}, selector)
+			assert.Equal(t, wantTimeframe, timeframe)
+			wantFilter := AlertFilter{
+				State:    AlertStateOpen,
+				Severity: AlertSeverityCritical,
+				Reasons:  req.Reasons,
+			}
+			assert.Equal(t, wantFilter, filter)
+			assert.Equal(t, req.PageSize, pageOpts.Size)
+			assert.Equal(t, ptkn, *pageOpts.Token)
+			return RepositoryPage[Alert]{
+				Items:         alerts,
+				NextPageToken: &nextPageTkn,
+			}, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+	h.pageTokens = tokens
+
+	gotRes, err := h.SearchAlerts(ctx, req)
+	require.NoError(t, err)
+
+	wantRes := SearchAlertsResponse{
+		Alerts:        alerts,
+		NextPageToken: wantNextPageTkn,
+	}
+	assert.Equal(t, wantRes, gotRes)
+}
+
+func TestHandler_SearchAlerts_requestValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		override  func(req *SearchAlertsRequest)
+	}{
+		{
+			name:      "blank device id",
+			fieldName: "device_ids[1]",
+			override: func(req *SearchAlertsRequest) {
+				req.DeviceIDs = []string{"foo", " "}
+			},
+		},
+		{
+			name:      "too many device ids",
+			fieldName: "device_ids",
+			override: func(req *SearchAlertsRequest) {
+				req.DeviceIDs = make([]string, maxSearchDeviceIDs+1)
+				for i := range req.DeviceIDs {
+					req.DeviceIDs[i] = fmt.Sprintf("d-%d", i)
+				}
+			},
+		},
+		{
+			name:      "invalid label selector",
+			fieldName: "label_selector",
+			override: func(req *SearchAlertsRequest) {
+				req.LabelSelector = "env=prod,=bad"
+			},
+		},
+		{
+			name:      "invalid reason",
+			fieldName: "reasons[0]",
+			override: func(req *SearchAlertsRequest) {
+				req.Reasons = []AlertReason{"TEMPERATURE_LOW"}
+			},
+		},
+		{
+			name:      "invalid severity",
+			fieldName: "severity",
+			override: func(req *SearchAlertsRequest) {
+				req.Severity = "FATAL"
+			},
+		},
+		{
+			name:      "invalid state",
+			fieldName: "state",
+			override: func(req *SearchAlertsRequest) {
+				req.State = "CLOSED"
+			},
+		},
+		{
+			name:      "Timeframe start is after end",
+			fieldName: "timeframe.start",
+			override: func(req *SearchAlertsRequest) {
+				req.TimeframeStart = ptr(time.Now().UTC())
+				req.TimeframeEnd = ptr(time.Now().Add(-time.Minute).UTC())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			req := SearchAlertsRequest{
+				DeviceIDs:      []string{"foo"},
+				TimeframeStart: ptr(time.Now().Add(-time.Minute).UTC()),
+				TimeframeEnd:   ptr(time.Now().UTC()),
+				PageSize:       10,
+			}
+
+			require.NotNil(t, tt.override, "test config `override` field must not be nil")
+			tt.override(&req)
+
+			h := NewService(nil, log.NewLogger())
+
+			_, err := h.SearchAlerts(ctx, req)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Contains(t, brErr.FieldViolations, tt.fieldName)
+		})
+	}
+}
+
+func TestHandler_GetAlertStats(t *testing.T) {
+	ctx := t.Context()
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(2*time.Hour + 30*time.Minute)
+
+	stats := AlertStats{
+		Total: 3,
+		ByReason: []ReasonAlertCount{
+			{Reason: AlertReasonBatteryLow, Count: 1},
+			{Reason: AlertReasonTemperatureHigh, Count: 2},
+		},
+		ByDevice: []DeviceAlertCount{
+			{DeviceID: "bar", Count: 1},
+			{DeviceID: "foo", Count: 2},
+		},
+		Buckets: []AlertCountBucket{
+			{Start: start.Add(time.Hour), Count: 3},
+		},
+		TopDevices: []DeviceAlertCount{
+			{DeviceID: "foo", Count: 2},
+		},
+	}
+
+	r := &RepositoryMock{
+		GetAlertStatsFunc: func(ctx context.Context, gotStart time.Time, gotEnd time.Time, width time.Duration, topN int) (AlertStats, error) {
+			assert.Equal(t, start, gotStart)
+			assert.Equal(t, end, gotEnd)
+			assert.Equal(t, time.Hour, width)
+			assert.Equal(t, defaultTopN, topN)
+			return stats, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	res, err := h.GetAlertStats(ctx, GetAlertStatsRequest{
+		TimeframeStart: &start,
+		TimeframeEnd:   &end,
+		BucketWidth:    BucketWidthHour,
+	})
+	require.NoError(t, err)
+
+	want := GetAlertStatsResponse{
+		Total:    stats.Total,
+		ByReason: stats.ByReason,
+		ByDevice: stats.ByDevice,
+		// empty buckets are filled
+		Buckets: []AlertCountBucket{
+			{Start: start},
+			{Start: start.Add(time.Hour), Count: 3},
+			{Start: start.Add(2 * time.Hour)},
+		},
+		TopDevices: stats.TopDevices,
+	}
+	assert.Equal(t, want, res)
+}
+
+func TestHandler_GetAlertStats_requestValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		override  func(req *GetAlertStatsRequest)
+	}{
+		{
+			name:      "missing timeframe start",
+			fieldName: "timeframe.start",
+			override: func(req *GetAlertStatsRequest) {
+				req.TimeframeStart = nil
+			},
+		},
+		{
+			name:      "missing timeframe end",
+			fieldName: "timeframe.end",
+			override: func(req *GetAlertStatsRequest) {
+				req.TimeframeEnd = nil
+			},
+		},
+		{
+			name:      "invalid bucket width",
+			fieldName: "bucket_width",
+			override: func(req *GetAlertStatsRequest) {
+				req.BucketWidth = "2m"
+			},
+		},
+		{
+			name:      "too many buckets",
+			fieldName: "timeframe",
+			override: func(req *GetAlertStatsRequest) {
+				req.TimeframeStart = ptr(req.TimeframeEnd.Add(-maxAggregateBuckets * time.Minute))
+			},
+		},
+		{
+			name:      "negative top n",
+			fieldName: "top_n",
+			override: func(req *GetAlertStatsRequest) {
+				req.TopN = -1
+			},
+		},
+		{
+			name:      "top n exceeds max",
+			fieldName: "top_n",
+			override: func(req *GetAlertStatsRequest) {
+				req.TopN = maxTopN + 1
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			req := GetAlertStatsRequest{
+				TimeframeStart: ptr(time.Now().Add(-time.Hour).UTC()),
+				TimeframeEnd:   ptr(time.Now().UTC()),
+				BucketWidth:    BucketWidthMinute,
+			}
+			require.NotNil(t, tt.override, "test config `override` field must not be nil")
+			tt.override(&req)
+
+			h := NewService(nil, log.NewLogger())
+
+			_, err := h.GetAlertStats(ctx, req)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Contains(t, brErr.FieldViolations, tt.fieldName)
+		})
+	}
+}
+
+func TestHandler_WatchDeviceAlerts(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	now := time.Now().UTC()
+	replayAlerts := []Alert{
+		{ID: 2, DeviceID: "foo", Reason: AlertReasonTemperatureHigh, Desc: "Temperature (5.56) exceeded configured threshold (5.55)", Time: now},
+		{ID: 3, DeviceID: "foo", Reason: AlertReasonBatteryLow, Desc: "Battery (4) dropped below configured threshold (5)", Time: now},
+	}
+
+	cursor, err := encodeCursor(RepositoryPageToken{LastID: ptr[int64](1)})
+	require.NoError(t, err)
+
+	r := &RepositoryMock{
+		GetAlertsAfterIDFunc: func(ctx context.Context, deviceID string, afterID int64, limit int) ([]Alert, error) {
+			assert.Equal(t, "foo", deviceID)
+			assert.Equal(t, int64(1), afterID)
+			return replayAlerts, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	gotCh := make(chan Alert)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- h.WatchDeviceAlerts(ctx, WatchDeviceAlertsRequest{
+			DeviceID: "foo",
+			Reasons:  []AlertReason{AlertReasonTemperatureHigh},
+			Cursor:   cursor,
+		}, func(alert Alert, cursor string) error {
+			tkn, err := decodeCursor(cursor)
+			require.NoError(t, err)
+			assert.Equal(t, alert.ID, *tkn.LastID)
+			gotCh <- alert
+			return nil
+		})
+	}()
+
+	// replayed alerts are filtered by reason
+	require.Equal(t, replayAlerts[0], <-gotCh)
+
+	liveAlerts := []Alert{
+		replayAlerts[0], // already replayed
+		{ID: 4, DeviceID: "bar", Reason: AlertReasonTemperatureHigh, Time: now},
+		{ID: 5, DeviceID: "foo", Reason: AlertReasonBatteryLow, Time: now},
+		{ID: 6, DeviceID: "foo", Reason: AlertReasonTemperatureHigh, Time: now},
+	}
+	for _, alert := range liveAlerts {
+		h.alerts.publish(alert)
+	}
+	require.Equal(t, liveAlerts[3], <-gotCh)
+
+	cancel()
+	require.NoError(t, <-errCh)
+}
+
+func TestHandler_WatchDeviceAlerts_subscribed(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	h := NewService(&RepositoryMock{}, log.NewLogger())
+
+	// not called when the request is invalid
+	err := h.WatchDeviceAlerts(ctx, WatchDeviceAlertsRequest{
+		Cursor: "invalid",
+		Subscribed: func() error {
+			t.Error("unexpected call to Subscribed")
+			return nil
+		},
+	}, func(alert Alert, cursor string) error {
+		return nil
+	})
+	var brErr *http.BadRequestError
+	require.ErrorAs(t, err, &brErr)
+
+	// called before any alerts are sent
+	subscribedCh := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- h.WatchDeviceAlerts(ctx, WatchDeviceAlertsRequest{
+			Subscribed: func() error {
+				close(subscribedCh)
+				return nil
+			},
+		}, func(alert Alert, cursor string) error {
+			return nil
+		})
+	}()
+	<-subscribedCh
+
+	cancel()
+	require.NoError(t, <-errCh)
+
+	// errors end the watch
+	wantErr := errors.New("subscribed")
+	err = h.WatchDeviceAlerts(t.Context(), WatchDeviceAlertsRequest{
+		Subscribed: func() error {
+			return wantErr
+		},
+	}, func(alert Alert, cursor string) error {
+		return nil
+	})
+	require.ErrorIs(t, err, wantErr)
+}
+
+func TestHandler_WatchDeviceAlerts_closed(t *testing.T) {
+	h := NewService(&RepositoryMock{}, log.NewLogger())
+	h.Close()
+
+	err := h.WatchDeviceAlerts(t.Context(), WatchDeviceAlertsRequest{}, func(alert Alert, cursor string) error {
+		return nil
+	})
+	require.ErrorIs(t, err, errAlertBrokerClosed)
+}
+
+func TestHandler_GetDeviceMetrics(t *testing.T) {
+	ctx := t.Context()
+
+	wantTimeframe := Timeframe{
+		Start: ptr(time.Now().Add(-time.Minute).UTC()),
+		End:   ptr(time.Now().UTC()),
+	}
+
+	req := GetDeviceMetricsRequest{
+		DeviceID:       "foo",
+		TimeframeStart: wantTimeframe.Start,
+		TimeframeEnd:   wantTimeframe.End,
+		PageSize:       10,
+	}
+	ptkn := RepositoryPageToken{
+		LastTime: ptr(time.Now().Add(-10 * time.Second).UTC()),
+		LastID:   ptr[int64](1),
+		Order:    SortOrderDesc,
+	}
+	scope := pageScope{Method: "GetDeviceMetrics", DeviceID: req.DeviceID, Filters: wantTimeframe}
+	tokens := newTestPageTokens()
+	reqTkn, err := tokens.encode(ptkn, scope)
+	require.NoError(t, err)
+	req.PageToken = reqTkn
+
+	metrics := []Metric{
+		{Values: []MetricValue{{Name: MetricTemperature, Value: 5.56}, {Name: MetricBattery, Value: 5}}, Time: time.Now()},
+		{Values: []MetricValue{{Name: "humidity", Value: 40.5, Unit: "%"}}, Time: time.Now()},
+	}
+	nextPageTkn := RepositoryPageToken{
+		LastTime: ptr(time.Now().Add(-20 * time.Second)),
+		LastID:   ptr[int64](2),
+	}
+	wantNextPageTkn, err := tokens.encode(nextPageTkn, scope)
+	require.NoError(t, err)
+
+	r := &RepositoryMock{
+		GetDeviceMetricsFunc: func(ctx context.Context, deviceID string, timeframe Timeframe, pageOpts RepositoryPageOptions) (RepositoryPage[Metric], error) {
+			assert.Equal(t, req.DeviceID, deviceID)
+			assert.Equal(t, wantTimeframe, timeframe)
+			assert.Equal(t, req.PageSize, pageOpts.Size)
+			assert.Equal(t, ptkn, *pageOpts.Token)
+			assert.Equal(t, SortOrderDesc, pageOpts.Order, "defaults to descending")
+			return RepositoryPage[Metric]{
+				Items:         metrics,
+				NextPageToken: &nextPageTkn,
+			}, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+	h.pageTokens = tokens
+
+	gotRes, err := h.GetDeviceMetrics(ctx, req)
+	require.NoError(t, err)
+
+	wantRes := GetDeviceMetricsResponse{
+		Metrics:       metrics,
+		NextPageToken: wantNextPageTkn,
+	}
+	assert.Equal(t, wantRes, gotRes)
+}
+
+func TestHandler_GetDeviceMetrics_requestValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		override  func(req *GetDeviceMetricsRequest)
+	}{
+		{
+			name:      "empty device id",
+			fieldName: "device_id",
+			override: func(req *GetDeviceMetricsRequest) {
+				req.DeviceID = ""
+			},
+		},
+		{
+			name:      "Timeframe start is after end",
+			fieldName: "timeframe.start",
+			override: func(req *GetDeviceMetricsRequest) {
+				req.TimeframeStart = ptr(time.Now().UTC())
+				req.TimeframeEnd = ptr(time.Now().Add(-time.Minute).UTC())
+			},
+		},
+		{
+			name:      "negative page size",
+			fieldName: "page.size",
+			override: func(req *GetDeviceMetricsRequest) {
+				req.PageSize = -1
+			},
+		},
+		{
+			name:      "malformed page token",
+			fieldName: "page_token",
+			override: func(req *GetDeviceMetricsRequest) {
+				req.PageToken = "not a token"
+			},
+		},
+		{
+			name:      "invalid order",
+			fieldName: "order",
+			override: func(req *GetDeviceMetricsRequest) {
+				req.Order = "newest"
+			},
+		},
+		{
+			name:      "page token from request with different order",
+			fieldName: "page_token",
+			override: func(req *GetDeviceMetricsRequest) {
+				scope := pageScope{
+					Method:   "GetDeviceMetrics",
+					DeviceID: req.DeviceID,
+					Filters:  Timeframe{Start: req.TimeframeStart, End: req.TimeframeEnd},
+				}
+				tkn, err := newTestPageTokens().encode(RepositoryPageToken{
+					LastTime: ptr(time.Now().UTC()),
+					LastID:   ptr[int64](1),
+					Order:    SortOrderAsc,
+				}, scope)
+				require.NoError(t, err)
+				req.PageToken = tkn
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			req := GetDeviceMetricsRequest{
+				DeviceID:       "foo",
+				TimeframeStart: ptr(time.Now().Add(-time.Minute).UTC()),
+				TimeframeEnd:   ptr(time.Now().UTC()),
+				PageSize:       10,
+			}
+			require.NotNil(t, tt.override, "test config `override` field must not be nil")
+			tt.override(&req)
+
+			h := NewService(nil, log.NewLogger())
+			h.pageTokens = newTestPageTokens()
+
+			_, err := h.GetDeviceMetrics(ctx, req)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestHandler_GetDeviceMetrics_pageTokenScope(t *testing.T) {
+	start, end := ptr(time.Now().Add(-time.Hour).UTC()), ptr(time.Now().UTC())
+	cursor := RepositoryPageToken{
+		LastTime: ptr(time.Now().Add(-time.Minute).UTC()),
+		LastID:   ptr[int64](1),
+		Order:    SortOrderDesc,
+	}
+	scope := pageScope{
+		Method:   "GetDeviceMetrics",
+		DeviceID: "foo",
+		Filters:  Timeframe{Start: start, End: end},
+	}
+
+	tests := []struct {
+		name     string
+		override func(req *GetDeviceMetricsRequest, tokens *pageTokens)
+		wantMsg  string
+	}{
+		{
+			name: "forged page token",
+			override: func(req *GetDeviceMetricsRequest, tokens *pageTokens) {
+				forged := newPageTokens([]byte("forged-page-token-key"), time.Hour)
+				tkn, err := forged.encode(cursor, scope)
+				require.NoError(t, err)
+				req.PageToken = tkn
+			},
+			wantMsg: "Must be a page token from a previous response",
+		},
+		{
+			name: "tampered page token",
+			override: func(req *GetDeviceMetricsRequest, tokens *pageTokens) {
+				tkn, err := tokens.encode(cursor, scope)
+				require.NoError(t, err)
+				dec, err := base64.RawURLEncoding.DecodeString(tkn)
+				require.NoError(t, err)
+				dec[len(dec)-2] ^= 1
+				req.PageToken = base64.RawURLEncoding.EncodeToString(dec)
+			},
+			wantMsg: "Must be a page token from a previous response",
+		},
+		{
+			name: "expired page token",
+			override: func(req *GetDeviceMetricsRequest, tokens *pageTokens) {
+				tkn, err := tokens.encode(cursor, scope)
+				require.NoError(t, err)
+				req.PageToken = tkn
+				tokens.now = func() time.Time { return testPageTokensNow.Add(time.Hour) }
+			},
+			wantMsg: "Must not be expired, request the first page again",
+		},
+		{
+			name: "page token from another device",
+			override: func(req *GetDeviceMetricsRequest, tokens *pageTokens) {
+				tkn, err := tokens.encode(cursor, scope)
+				require.NoError(t, err)
+				req.PageToken = tkn
+				req.DeviceID = "bar"
+			},
+			wantMsg: "Must be a page token from a request for the same device",
+		},
+		{
+			name: "page token from request with different timeframe",
+			override: func(req *GetDeviceMetricsRequest, tokens *pageTokens) {
+				tkn, err := tokens.encode(cursor, scope)
+				require.NoError(t, err)
+				req.PageToken = tkn
+				req.TimeframeStart = ptr(start.Add(-time.Hour))
+			},
+			wantMsg: "Must be a page token from a request with the same filters",
+		},
+		{
+			name: "page token from another method",
+			override: func(req *GetDeviceMetricsRequest, tokens *pageTokens) {
+				tkn, err := tokens.encode(cursor, pageScope{Method: "ListDeviceConfigVersions", DeviceID: "foo"})
+				require.NoError(t, err)
+				req.PageToken = tkn
+			},
+			wantMsg: "Must be a page token from a request with the same filters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			req := GetDeviceMetricsRequest{
+				DeviceID:       "foo",
+				TimeframeStart: start,
+				TimeframeEnd:   end,
+			}
+			tokens := newTestPageTokens()
+			require.NotNil(t, tt.override, "test config `override` field must not be nil")
+			tt.override(&req, tokens)
+
+			h := NewService(nil, log.NewLogger())
+			h.pageTokens = tokens
+
+			_, err := h.GetDeviceMetrics(ctx, req)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Equal(t, []string{tt.wantMsg}, brErr.FieldViolations["page_token"])
+		})
+	}
+}
+
+func TestHandler_GetDeviceMetricAggregates(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC)
+	end := start.Add(3 * time.Minute)
+
+	temperature := MetricAggregates{
+		Name: MetricTemperature,
+		Buckets: []MetricBucket{
+			{Start: start.Add(time.Minute).Truncate(time.Minute), Count: 2, Min: ptr(1.0), Max: ptr(3.0), Avg: ptr(2.0), First: ptr(3.0), Last: ptr(1.0)},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		fillEmpty bool
+		want      []MetricBucket
+	}{
+		{
+			name: "empty buckets omitted",
+			want: temperature.Buckets,
+		},
+		{
+			name:      "empty buckets filled",
+			fillEmpty: true,
+			want: []MetricBucket{
+				{Start: start.Truncate(time.Minute)},
+				temperature.Buckets[0],
+				{Start: start.Add(2 * time.Minute).Truncate(time.Minute)},
+				{Start: end.Truncate(time.Minute)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			r := &RepositoryMock{
+				GetDeviceMetricAggregatesFunc: func(ctx context.Context, deviceID string, gotStart time.Time, gotEnd time.Time, width time.Duration) ([]MetricAggregates, error) {
+					assert.Equal(t, "foo", deviceID)
+					assert.Equal(t, start, gotStart)
+					assert.Equal(t, end, gotEnd)
+					assert.Equal(t, time.Minute, width)
+					return []MetricAggregates{{Name: temperature.Name, Buckets: slices.Clone(temperature.Buckets)}}, nil
+				},
+			}
+
+			h := NewService(r, log.NewLogger())
+
+			res, err := h.GetDeviceMetricAggregates(ctx, GetDeviceMetricAggregatesRequest{
+				DeviceID:       "foo",
+				TimeframeStart: &start,
+				TimeframeEnd:   &end,
+				BucketWidth:    BucketWidthMinute,
+				FillEmpty:      tt.fillEmpty,
+			})
+			require.NoError(t, err)
+			require.Len(t, res.Metrics, 1)
+			assert.Equal(t, MetricTemperature, res.Metrics[0].Name)
+			assert.Equal(t, tt.want, res.Metrics[0].Buckets)
+		})
+	}
+}
+
+func TestHandler_GetDeviceMetricAggregates_requestValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		override  func(req *GetDeviceMetricAggregatesRequest)
+	}{
+		{
+			name:      "empty device id",
+			fieldName: "device_id",
+			override: func(req *GetDeviceMetricAggregatesRequest) {
+				req.DeviceID = ""
+			},
+		},
+		{
+			name:      "missing timeframe start",
+			fieldName: "timeframe.start",
+			override: func(req *GetDeviceMetricAggregatesRequest) {
+				req.TimeframeStart = nil
+			},
+		},
+		{
+			name:      "missing timeframe end",
+			fieldName: "timeframe.end",
+			override: func(req *GetDeviceMetricAggregatesRequest) {
+				req.TimeframeEnd = nil
+			},
+		},
+		{
+			name:      "invalid bucket width",
+			fieldName: "bucket_width",
+			override: func(req *GetDeviceMetricAggregatesRequest) {
+				req.BucketWidth = "2m"
+			},
+		},
+		{
+			name:      "too many buckets",
+			fieldName: "timeframe",
+			override: func(req *GetDeviceMetricAggregatesRequest) {
+				req.TimeframeStart = ptr(req.TimeframeEnd.Add(-maxAggregateBuckets * time.Minute))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			req := GetDeviceMetricAggregatesRequest{
+				DeviceID:       "foo",
+				TimeframeStart: ptr(time.Now().Add(-time.Hour).UTC()),
+				TimeframeEnd:   ptr(time.Now().UTC()),
+				BucketWidth:    BucketWidthMinute,
+			}
+			require.NotNil(t, tt.override, "test config `override` field must not be nil")
+			tt.override(&req)
+
+			h := NewService(nil, log.NewLogger())
+
+			_, err := h.GetDeviceMetricAggregates(ctx, req)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Contains(t, brErr.FieldViolations, tt.fieldName)
+		})
+	}
+}
+
+func TestHandler_RecordMetric_alertRules(t *testing.T) {
+	rules := []AlertRule{
+		{ID: 1, Metric: "humidity", Operator: RuleOperatorGreaterThanOrEqual, Threshold: 80, Severity: AlertSeverityInfo},
+		{ID: 2, Metric: "humidity", Operator: RuleOperatorOutsideRange, Threshold: 20, ThresholdHigh: 85, Severity: AlertSeverityCritical},
+		{ID: 3, Metric: "co2", Operator: RuleOperatorInsideRange, Threshold: 1000, ThresholdHigh: 2000, Severity: AlertSeverityWarning},
+		{ID: 4, Metric: MetricTemperature, Operator: RuleOperatorLessThanOrEqual, Threshold: 0, Severity: AlertSeverityWarning},
+		{ID: 5, Metric: "pressure", Operator: RuleOperatorEqual, Threshold: 0, Severity: AlertSeverityCritical},
+	}
+
+	tests := []struct {
+		name        string
+		values      []RecordMetricValue
+		wantRuleIDs []int64
+	}{
+		{
+			name:   "no rules breached",
+			values: []RecordMetricValue{{Name: "humidity", Value: 50}, {Name: "co2", Value: 999}},
+		},
+		{
+			name:        "greater than or equal",
+			values:      []RecordMetricValue{{Name: "humidity", Value: 80}},
+			wantRuleIDs: []int64{1},
+		},
+		{
+			name:        "outside range",
+			values:      []RecordMetricValue{{Name: "humidity", Value: 85.1}},
+			wantRuleIDs: []int64{1, 2},
+		},
+		{
+			name:        "inside range",
+			values:      []RecordMetricValue{{Name: "co2", Value: 2000}},
+			wantRuleIDs: []int64{3},
+		},
+		{
+			name:        "less than or equal",
+			values:      []RecordMetricValue{{Name: MetricTemperature, Value: 0}},
+			wantRuleIDs: []int64{4},
+		},
+		{
+			name:        "equal",
+			values:      []RecordMetricValue{{Name: "pressure", Value: 0}},
+			wantRuleIDs: []int64{5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			req := RecordMetricRequest{
+				DeviceID:  "foo",
+				Values:    tt.values,
+				Timestamp: time.Now().UTC(),
+			}
+
+			var gotAlerts []Alert
+
+			r := &RepositoryMock{
+				SaveDeviceMetricFunc: func(ctx context.Context, deviceID string, metric Metric) error {
+					return nil
+				},
+				GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+					return Config{}, ErrRepoItemNotFound
+				},
+				ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+					assert.Equal(t, req.DeviceID, deviceID)
+					return rules, nil
+				},
+				ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+					return 0, nil
+				},
+				GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+					return Alert{}, ErrRepoItemNotFound
+				},
+				SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+					gotAlerts = append(gotAlerts, alert)
+					return int64(len(gotAlerts)), nil
+				},
+			}
+
+			h := NewService(r, log.NewLogger())
+
+			err := h.RecordMetric(ctx, req)
+			require.NoError(t, err)
+
+			var gotRuleIDs []int64
+			for _, alert := range gotAlerts {
+				gotRuleIDs = append(gotRuleIDs, alert.RuleID)
+				rule := rules[alert.RuleID-1]
+				assert.Equal(t, rule.Reason(), alert.Reason)
+				assert.Equal(t, rule.Severity, alert.Severity)
+				assert.Equal(t, req.Timestamp, alert.Time)
+			}
+			assert.Equal(t, tt.wantRuleIDs, gotRuleIDs)
+		})
+	}
+}
+
+func TestHandler_RecordMetric_rateOfChange(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	rules := []AlertRule{
+		{ID: 1, Metric: MetricTemperature, Operator: RuleOperatorRise, Threshold: 5, WindowSeconds: 600, Severity: AlertSeverityWarning},
+		{ID: 2, Metric: MetricBattery, Operator: RuleOperatorDrop, Threshold: 10, WindowSeconds: 3600, Severity: AlertSeverityCritical},
+	}
+	history := []Metric{
+		newMetric(now.Add(-5*time.Minute), 22, 80, nil),
+		newMetric(now.Add(-20*time.Minute), 18, 85, nil),
+		newMetric(now.Add(-90*time.Minute), 10, 100, nil),
+	}
+	windows := map[string]time.Duration{
+		MetricTemperature: 10 * time.Minute,
+		MetricBattery:     time.Hour,
+	}
+
+	tests := []struct {
+		name        string
+		temperature float64
+		battery     int32
+		history     []Metric
+		wantRuleIDs []int64
+	}{
+		{
+			name:        "no history",
+			temperature: 40,
+			battery:     1,
+		},
+		{
+			name:        "within thresholds",
+			temperature: 26,
+			battery:     78,
+			history:     history,
+		},
+		{
+			// the reading 20 minutes ago is outside the temperature window
+			name:        "temperature rise",
+			temperature: 27.5,
+			battery:     80,
+			history:     history,
+			wantRuleIDs: []int64{1},
+		},
+		{
+			// the reading 90 minutes ago is outside the battery window
+			name:        "battery drop",
+			temperature: 22,
+			battery:     74,
+			history:     history,
+			wantRuleIDs: []int64{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			req := RecordMetricRequest{
+				DeviceID:    "foo",
+				Temperature: tt.temperature,
+				Battery:     tt.battery,
+				Timestamp:   now,
+			}
+
+			var gotAlerts []Alert
+
+			r := &RepositoryMock{
+				SaveDeviceMetricFunc: func(ctx context.Context, deviceID string, metric Metric) error {
+					return nil
+				},
+				GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+					return Config{}, ErrRepoItemNotFound
+				},
+				ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+					return rules, nil
+				},
+				GetMetricRangeFunc: func(ctx context.Context, deviceID string, name string, start time.Time, end time.Time) (MetricRange, error) {
+					assert.Equal(t, req.DeviceID, deviceID)
+					assert.Equal(t, now.Add(-windows[name]), start)
+					assert.Equal(t, now, end)
+					// the recorded metric is saved before it is evaluated
+					metrics := append([]Metric{newMetric(now, tt.temperature, tt.battery, nil)}, tt.history...)
+					return metricRange(metrics, name, start, end)
+				},
+				ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+					return 0, nil
+				},
+				GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+					return Alert{}, ErrRepoItemNotFound
+				},
+				SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+					gotAlerts = append(gotAlerts, alert)
+					return int64(len(gotAlerts)), nil
+				},
+			}
+
+			h := NewService(r, log.NewLogger())
+
+			err := h.RecordMetric(ctx, req)
+			require.NoError(t, err)
+
+			var gotRuleIDs []int64
+			for _, alert := range gotAlerts {
+				gotRuleIDs = append(gotRuleIDs, alert.RuleID)
+				rule := rules[alert.RuleID-1]
+				assert.Equal(t, rule.Reason(), alert.Reason)
+				assert.Equal(t, rule.Severity, alert.Severity)
+			}
+			assert.Equal(t, tt.wantRuleIDs, gotRuleIDs)
+		})
+	}
+}
+
+func TestHandler_RecordMetrics_rateOfChange(t *testing.T) {
+	ctx := t.Context()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	rule := AlertRule{ID: 1, Metric: MetricTemperature, Operator: RuleOperatorRise, Threshold: 5, WindowSeconds: 600, Severity: AlertSeverityWarning}
+
+	req := RecordMetricsRequest{
+		DeviceID: "foo",
+		Metrics: []RecordMetricsItem{
+			{Temperature: 20, Battery: 80, Timestamp: now.Add(-2 * time.Minute)},
+			{Temperature: 23, Battery: 80, Timestamp: now.Add(-time.Minute)},
+			{Temperature: 26, Battery: 80, Timestamp: now},
+		},
+	}
+
+	var saved []Metric
+	var gotAlerts []Alert
+
+	r := &RepositoryMock{
+		SaveDeviceMetricsFunc: func(ctx context.Context, deviceID string, metrics []Metric) error {
+			saved = metrics
+			return nil
+		},
+		GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+			return Config{}, ErrRepoItemNotFound
+		},
+		ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+			return []AlertRule{rule}, nil
+		},
+		GetMetricRangeFunc: func(ctx context.Context, deviceID string, name string, start time.Time, end time.Time) (MetricRange, error) {
+			assert.Equal(t, MetricTemperature, name)
+			assert.Equal(t, end.Add(-rule.Window()), start)
+			return metricRange(saved, name, start, end)
+		},
+		ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+			return 0, nil
+		},
+		GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+			return Alert{}, ErrRepoItemNotFound
+		},
+		SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+			gotAlerts = append(gotAlerts, alert)
+			return int64(len(gotAlerts)), nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	_, err := h.RecordMetrics(ctx, req)
+	require.NoError(t, err)
+
+	// only the last reading rose by more than 5 from an earlier reading of the batch
+	require.Len(t, gotAlerts, 1)
+	assert.Equal(t, AlertReasonRapidRise, gotAlerts[0].Reason)
+	assert.Equal(t, now, gotAlerts[0].Time)
+	assert.Equal(t, "Temperature rose by 6 within 10m0s, exceeding configured threshold (5)", gotAlerts[0].Desc)
+}
+
+func TestHandler_RecordMetric_expressions(t *testing.T) {
+	cfg := Config{
+		TemperatureThreshold: maxTemperature,
+		Expressions: []AlertExpression{
+			{Expr: "temperature > 40 && battery < 15", Severity: AlertSeverityCritical},
+			{Expr: "temperature - prev.temperature > 5", Severity: AlertSeverityWarning},
+			{Expr: "has(prev.humidity) ? humidity != prev.humidity : false", Severity: AlertSeverityInfo},
+			{Expr: `co2 > prev["co2"]`, Severity: AlertSeverityInfo},
+		},
+		Sources: thresholdSources,
+	}
+
+	tests := []struct {
+		name      string
+		values    []RecordMetricValue
+		prev      []MetricValue
+		wantExprs []int
+	}{
+		{
+			name:   "no expressions matched",
+			values: []RecordMetricValue{{Name: MetricTemperature, Value: 41}, {Name: MetricBattery, Value: 15}},
+			prev:   []MetricValue{{Name: MetricTemperature, Value: 40}},
+		},
+		{
+			name:      "multiple metrics",
+			values:    []RecordMetricValue{{Name: MetricTemperature, Value: 41}, {Name: MetricBattery, Value: 14}},
+			prev:      []MetricValue{{Name: MetricTemperature, Value: 40}},
+			wantExprs: []int{0},
+		},
+		{
+			name:      "previous reading",
+			values:    []RecordMetricValue{{Name: MetricTemperature, Value: 30.5}},
+			prev:      []MetricValue{{Name: MetricTemperature, Value: 25}},
+			wantExprs: []int{1},
+		},
+		{
+			name:   "without previous reading",
+			values: []RecordMetricValue{{Name: MetricTemperature, Value: 41}, {Name: "humidity", Value: 50}},
+		},
+		{
+			name:   "metric missing from previous reading",
+			values: []RecordMetricValue{{Name: MetricTemperature, Value: 41}, {Name: "humidity", Value: 50}},
+			prev:   []MetricValue{{Name: MetricBattery, Value: 100}},
+		},
+		{
+			name:   "metric missing from previous reading by index",
+			values: []RecordMetricValue{{Name: "co2", Value: 900}},
+			prev:   []MetricValue{{Name: MetricBattery, Value: 100}},
+		},
+		{
+			name:      "presence test on previous reading",
+			values:    []RecordMetricValue{{Name: "humidity", Value: 50}},
+			prev:      []MetricValue{{Name: "humidity", Value: 49}},
+			wantExprs: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			req := RecordMetricRequest{
+				DeviceID:  "foo",
+				Values:    tt.values,
+				Timestamp: time.Now().UTC(),
+			}
+
+			var gotAlerts []Alert
+			var prevRead bool
+
+			r := &RepositoryMock{
+				GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+					return cfg, nil
+				},
+				ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+					return nil, nil
+				},
+				ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+					return 0, nil
+				},
+				GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+					return Alert{}, ErrRepoItemNotFound
+				},
+				GetDeviceMetricsFunc: func(ctx context.Context, deviceID string, timeframe Timeframe, pageOpts RepositoryPageOptions) (RepositoryPage[Metric], error) {
+					assert.Equal(t, req.DeviceID, deviceID)
+					assert.Equal(t, 1, pageOpts.Size)
+					prevRead = true
+					if tt.prev == nil {
+						return RepositoryPage[Metric]{}, nil
+					}
+					return RepositoryPage[Metric]{Items: []Metric{{Values: tt.prev}}}, nil
+				},
+				SaveDeviceMetricFunc: func(ctx context.Context, deviceID string, metric Metric) error {
+					// previous reading must be read before the new one is saved
+					assert.True(t, prevRead)
+					return nil
+				},
+				SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+					gotAlerts = append(gotAlerts, alert)
+					return int64(len(gotAlerts)), nil
+				},
+			}
+
+			h := NewService(r, log.NewLogger())
+
+			err := h.RecordMetric(ctx, req)
+			require.NoError(t, err)
+
+			var wantAlerts []Alert
+			for _, i := range tt.wantExprs {
+				wantAlerts = append(wantAlerts, Alert{
+					Reason:      AlertReasonExpressionMatched,
+					Severity:    cfg.Expressions[i].Severity,
+					Desc:        cfg.Expressions[i].Desc(),
+					Time:        req.Timestamp,
+					State:       AlertStateOpen,
+					Occurrences: 1,
+					LastSeen:    req.Timestamp,
+					Condition:   expressionCondition(cfg.Expressions[i]),
+				})
+			}
+			assert.Equal(t, wantAlerts, gotAlerts)
+		})
+	}
+}
+
+func TestHandler_RecordMetric_debouncing(t *testing.T) {
+	cfg := Config{
+		TemperatureThreshold: 50,
+		BatteryThreshold:     0,
+		ConsecutiveBreaches:  2,
+		Hysteresis:           2,
+		Sources:              thresholdSources,
+	}
+
+	tests := []struct {
+		name         string
+		temperatures []float64
+		wantAlerts   int
+	}{
+		{
+			name:         "single breach",
+			temperatures: []float64{51, 49, 51},
+			wantAlerts:   0,
+		},
+		{
+			name:         "consecutive breaches",
+			temperatures: []float64{51, 52, 53, 54},
+			wantAlerts:   1,
+		},
+		{
+			name:         "within hysteresis",
+			temperatures: []float64{51, 52, 49, 51, 52},
+			wantAlerts:   1,
+		},
+		{
+			name:         "cleared by hysteresis",
+			temperatures: []float64{51, 52, 48, 51, 52},
+			wantAlerts:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			// states persist across readings as they would in the repository
+			states := map[string]ConditionState{}
+			var gotAlerts []Alert
+
+			r := &RepositoryMock{
+				SaveDeviceMetricFunc: func(ctx context.Context, deviceID string, metric Metric) error {
+					return nil
+				},
+				GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+					return cfg, nil
+				},
+				ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+					return nil, nil
+				},
+				ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+					return 0, nil
+				},
+				GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+					return Alert{}, ErrRepoItemNotFound
+				},
+				GetConditionStatesFunc: func(ctx context.Context, deviceID string) (map[string]ConditionState, error) {
+					return maps.Clone(states), nil
+				},
+				SaveConditionStatesFunc: func(ctx context.Context, deviceID string, saved map[string]ConditionState) error {
+					states = maps.Clone(saved)
+					return nil
+				},
+				SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+					gotAlerts = append(gotAlerts, alert)
+					return int64(len(gotAlerts)), nil
+				},
+			}
+
+			h := NewService(r, log.NewLogger())
+
+			for _, temperature := range tt.temperatures {
+				err := h.RecordMetric(ctx, RecordMetricRequest{
+					DeviceID:    "foo",
+					Temperature: temperature,
+					Battery:     50,
+					Timestamp:   time.Now().UTC(),
+				})
+				require.NoError(t, err)
+			}
+
+			require.Len(t, gotAlerts, tt.wantAlerts)
+			for _, alert := range gotAlerts {
+				assert.Equal(t, AlertReasonTemperatureHigh, alert.Reason)
+			}
+		})
+	}
+}
+
+func TestHandler_RecordMetric_concurrentDebouncing(t *testing.T) {
+	ctx := t.Context()
+	cfg := Config{
+		TemperatureThreshold: 50,
+		ConsecutiveBreaches:  2,
+		Sources:              thresholdSources,
+	}
+
+	var mu sync.Mutex
+	states := map[string]ConditionState{}
+	var gotAlerts []Alert
+
+	r := &RepositoryMock{
+		SaveDeviceMetricFunc: func(ctx context.Context, deviceID string, metric Metric) error {
+			return nil
+		},
+		SaveDeviceMetricsFunc: func(ctx context.Context, deviceID string, metrics []Metric) error {
+			return nil
+		},
+		GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+			return cfg, nil
+		},
+		ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+			return nil, nil
+		},
+		ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+			return 0, nil
+		},
+		GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+			return Alert{}, ErrRepoItemNotFound
+		},
+		GetConditionStatesFunc: func(ctx context.Context, deviceID string) (map[string]ConditionState, error) {
+			mu.Lock()
+			got := maps.Clone(states)
+			mu.Unlock()
+			// widen the window between reading and saving the states
+			time.Sleep(10 * time.Millisecond)
+			return got, nil
+		},
+		SaveConditionStatesFunc: func(ctx context.Context, deviceID string, saved map[string]ConditionState) error {
+			mu.Lock()
+			defer mu.Unlock()
+			states = maps.Clone(saved)
+			return nil
+		},
+		SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			gotAlerts = append(gotAlerts, alert)
+			return int64(len(gotAlerts)), nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	// a single reading and a batch reading recorded at the same time
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, h.RecordMetric(ctx, RecordMetricRequest{
+			DeviceID:    "foo",
+			Temperature: 51,
+			Battery:     50,
+			Timestamp:   time.Now().UTC(),
+		}))
+	}()
+	go func() {
+		defer wg.Done()
+		_, err := h.RecordMetrics(ctx, RecordMetricsRequest{
+			DeviceID: "foo",
+			Metrics:  []RecordMetricsItem{{Temperature: 52, Battery: 50, Timestamp: time.Now().UTC()}},
+		})
+		assert.NoError(t, err)
+	}()
+	wg.Wait()
+
+	// neither reading's breach is lost, so the second triggers an alert
+	require.Len(t, gotAlerts, 1)
+	assert.Equal(t, AlertReasonTemperatureHigh, gotAlerts[0].Reason)
+	assert.Equal(t, ConditionState{Active: true, Breaches: 2}, states["config:temperature"])
+}
+
+func TestHandler_RecordMetrics_debouncing(t *testing.T) {
+	ctx := t.Context()
+
+	var savedStates map[string]ConditionState
+	var gotAlerts []Alert
+
+	r := &RepositoryMock{
+		SaveDeviceMetricsFunc: func(ctx context.Context, deviceID string, metrics []Metric) error {
+			return nil
+		},
+		GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+			return Config{TemperatureThreshold: 50, ConsecutiveBreaches: 3, Sources: thresholdSources}, nil
+		},
+		ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+			return nil, nil
+		},
+		ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+			return 0, nil
+		},
+		GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+			return Alert{}, ErrRepoItemNotFound
+		},
+		GetConditionStatesFunc: func(ctx context.Context, deviceID string) (map[string]ConditionState, error) {
+			// the previous request left the condition one breach short
+			return map[string]ConditionState{"config:" + MetricTemperature: {Breaches: 2}}, nil
+		},
+		SaveConditionStatesFunc: func(ctx context.Context, deviceID string, states map[string]ConditionState) error {
+			savedStates = states
+			return nil
+		},
+		SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+			gotAlerts = append(gotAlerts, alert)
+			return int64(len(gotAlerts)), nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	now := time.Now().UTC()
+	_, err := h.RecordMetrics(ctx, RecordMetricsRequest{
+		DeviceID: "foo",
+		Metrics: []RecordMetricsItem{
+			{Temperature: 51, Battery: 50, Timestamp: now},
+			{Temperature: 52, Battery: 50, Timestamp: now.Add(time.Second)},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, gotAlerts, 1)
+	assert.Equal(t, now, gotAlerts[0].Time)
+	assert.Equal(t, ConditionState{Breaches: 3, Active: true}, savedStates["config:"+MetricTemperature])
+	assert.Equal(t, ConditionState{}, savedStates["config:"+MetricBattery])
+}
+
+func TestHandler_RecordMetrics_outOfOrder(t *testing.T) {
+	ctx := t.Context()
+
+	var savedMetrics []Metric
+	var gotAlerts []Alert
+
+	r := &RepositoryMock{
+		SaveDeviceMetricsFunc: func(ctx context.Context, deviceID string, metrics []Metric) error {
+			savedMetrics = metrics
+			return nil
+		},
+		GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+			return Config{TemperatureThreshold: 50, ConsecutiveBreaches: 2, Sources: thresholdSources}, nil
+		},
+		ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+			return nil, nil
+		},
+		ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+			return 0, nil
+		},
+		GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+			return Alert{}, ErrRepoItemNotFound
+		},
+		GetConditionStatesFunc: func(ctx context.Context, deviceID string) (map[string]ConditionState, error) {
+			return map[string]ConditionState{}, nil
+		},
+		SaveConditionStatesFunc: func(ctx context.Context, deviceID string, states map[string]ConditionState) error {
+			return nil
+		},
+		SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+			gotAlerts = append(gotAlerts, alert)
+			return int64(len(gotAlerts)), nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	// a buffered flush in which the two breaches are not next to each other
+	now := time.Now().UTC()
+	_, err := h.RecordMetrics(ctx, RecordMetricsRequest{
+		DeviceID: "foo",
+		Metrics: []RecordMetricsItem{
+			{Temperature: 52, Battery: 50, Timestamp: now.Add(time.Second)},
+			{Temperature: 40, Battery: 50, Timestamp: now.Add(2 * time.Second)},
+			{Temperature: 51, Battery: 50, Timestamp: now},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, savedMetrics, 3)
+	for i, want := range []time.Time{now, now.Add(time.Second), now.Add(2 * time.Second)} {
+		assert.Equal(t, want, savedMetrics[i].Time)
+	}
+	require.Len(t, gotAlerts, 1)
+	assert.Equal(t, now.Add(time.Second), gotAlerts[0].Time)
+}
+
+func TestHandler_RecordMetrics_evaluationError(t *testing.T) {
+	ctx := t.Context()
+
+	r := &RepositoryMock{
+		SaveDeviceMetricsFunc: func(ctx context.Context, deviceID string, metrics []Metric) error {
+			return nil
+		},
+		GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+			return Config{TemperatureThreshold: 50, Sources: thresholdSources}, nil
+		},
+		ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+			return nil, nil
+		},
+		ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+			return 0, nil
+		},
+		GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+			return Alert{}, assert.AnError
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	// the metrics are saved, so a failure to evaluate them must not make the
+	// client retry and record them twice
+	res, err := h.RecordMetrics(ctx, RecordMetricsRequest{
+		DeviceID: "foo",
+		Metrics: []RecordMetricsItem{
+			{Temperature: 51, Battery: 50, Timestamp: time.Now().UTC()},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Recorded)
+	assert.Len(t, r.SaveDeviceMetricsCalls(), 1)
+	assert.Len(t, r.GetLatestConditionAlertCalls(), 1)
+}
+
+func TestHandler_RecordMetric_resolvesClearedAlerts(t *testing.T) {
+	ctx := t.Context()
+
+	req := RecordMetricRequest{
+		DeviceID: "foo",
+		Values: []RecordMetricValue{
+			{Name: MetricTemperature, Value: 49},
+			{Name: "humidity", Value: 90},
+		},
+		Timestamp: time.Now().UTC(),
+	}
+	cfg := Config{
+		TemperatureThreshold: 50,
+		Hysteresis:           2,
+		Expressions: []AlertExpression{
+			{Expr: "humidity > 80", Severity: AlertSeverityWarning},
+			{Expr: "humidity < 10", Severity: AlertSeverityWarning},
+		},
+		ReportingInterval: time.Minute,
+		Sources:           thresholdSources,
+	}
+	rules := []AlertRule{
+		{ID: 1, Metric: "humidity", Operator: RuleOperatorGreaterThan, Threshold: 95, Severity: AlertSeverityInfo},
+	}
+
+	var gotConditions []string
+
+	r := &RepositoryMock{
+		SaveDeviceMetricFunc: func(ctx context.Context, deviceID string, metric Metric) error {
+			return nil
+		},
+		GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+			return cfg, nil
+		},
+		ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+			return rules, nil
+		},
+		GetConditionStatesFunc: func(ctx context.Context, deviceID string) (map[string]ConditionState, error) {
+			return map[string]ConditionState{}, nil
+		},
+		SaveConditionStatesFunc: func(ctx context.Context, deviceID string, states map[string]ConditionState) error {
+			return nil
+		},
+		SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+			return 1, nil
+		},
+		ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+			assert.Equal(t, req.DeviceID, deviceID)
+			assert.Equal(t, req.Timestamp, at)
+			gotConditions = conditions
+			return 1, nil
+		},
+		GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+			return Alert{}, ErrRepoItemNotFound
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	err := h.RecordMetric(ctx, req)
+	require.NoError(t, err)
+
+	// temperature is below the threshold but within the hysteresis band, and
+	// battery is missing from the reading
+	assert.Equal(t, []string{"rule:1", "expr:humidity < 10", offlineCondition}, gotConditions)
+}
+
+func TestHandler_detectOfflineDevices(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name         string
+		startedAt    time.Time
+		lastReported time.Time
+		latest       *Alert
+		wantAlert    bool
+	}{
+		{
+			name:         "within grace period",
+			lastReported: now.Add(-3 * time.Minute),
+		},
+		{
+			name:         "past grace period",
+			lastReported: now.Add(-5 * time.Minute),
+			wantAlert:    true,
+		},
+		{
+			name:      "never reported",
+			wantAlert: true,
+		},
+		{
+			// silence while the server was down does not count
+			name:         "silent since before start",
+			startedAt:    now.Add(-3 * time.Minute),
+			lastReported: now.Add(-time.Hour),
+		},
+		{
+			name:         "already alerted",
+			lastReported: now.Add(-10 * time.Minute),
+			latest:       &Alert{ID: 1, State: AlertStateResolved, LastSeen: now.Add(-time.Minute)},
+		},
+		{
+			name:         "alerted before last report",
+			lastReported: now.Add(-10 * time.Minute),
+			latest:       &Alert{ID: 1, State: AlertStateResolved, Time: now.Add(-time.Hour), LastSeen: now.Add(-20 * time.Minute)},
+			wantAlert:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			startedAt := tt.startedAt
+			if startedAt.IsZero() {
+				startedAt = now.Add(-time.Hour)
+			}
+
+			var gotAlerts []Alert
+
+			r := &RepositoryMock{
+				ListReportingDevicesFunc: func(ctx context.Context) ([]ReportingDevice, error) {
+					return []ReportingDevice{{DeviceID: "foo", ReportingInterval: 2 * time.Minute, LastReported: tt.lastReported}}, nil
+				},
+				GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+					assert.Equal(t, "foo", deviceID)
+					assert.Equal(t, offlineCondition, condition)
+					if tt.latest == nil {
+						return Alert{}, ErrRepoItemNotFound
+					}
+					return *tt.latest, nil
+				},
+				GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+					return Config{ReportingInterval: 2 * time.Minute}, nil
+				},
+				SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+					gotAlerts = append(gotAlerts, alert)
+					return int64(len(gotAlerts)), nil
+				},
+			}
+
+			h := NewService(r, log.NewLogger())
+
+			err := h.detectOfflineDevices(ctx, now, startedAt, 2)
+			require.NoError(t, err)
+
+			if !tt.wantAlert {
+				assert.Empty(t, gotAlerts)
+				return
+			}
+			require.Len(t, gotAlerts, 1)
+			assert.Equal(t, AlertReasonDeviceOffline, gotAlerts[0].Reason)
+			assert.Equal(t, offlineCondition, gotAlerts[0].Condition)
+			assert.Equal(t, now, gotAlerts[0].Time)
+			assert.Equal(t, AlertStateOpen, gotAlerts[0].State)
+		})
+	}
+}
+
+func TestOfflineDetector_StartStop(t *testing.T) {
+	checked := make(chan struct{}, 1)
+	r := &RepositoryMock{
+		ListReportingDevicesFunc: func(ctx context.Context) ([]ReportingDevice, error) {
+			select {
+			case checked <- struct{}{}:
+			default:
+			}
+			return nil, nil
+		},
+	}
+
+	d := NewOfflineDetector(NewService(r, log.NewLogger()), time.Millisecond, 2)
+	d.Stop() // no-op before start
+	d.Start()
+
+	select {
+	case <-checked:
+	case <-time.After(time.Second):
+		t.Fatal("offline devices were not checked")
+	}
+	d.Stop()
+}
+
+func TestHandler_RecordMetric_deduplication(t *testing.T) {
+	now := time.Now().UTC()
+	cfg := Config{
+		TemperatureThreshold: 50,
+		BatteryThreshold:     20,
+		Cooldowns: []AlertCooldown{
+			{Reason: AlertReasonBatteryLow, Window: 15 * time.Minute},
+		},
+		Sources: thresholdSources,
+	}
+
+	tests := []struct {
+		name           string
+		latest         *Alert
+		wantSaved      bool
+		wantOccurrence bool
+	}{
+		{
+			name:      "no previous alert",
+			wantSaved: true,
+		},
+		{
+			name:           "open alert",
+			latest:         &Alert{ID: 7, State: AlertStateOpen, Time: now.Add(-time.Hour)},
+			wantOccurrence: true,
+		},
+		{
+			name:           "acknowledged alert",
+			latest:         &Alert{ID: 7, State: AlertStateAcknowledged, Time: now.Add(-time.Hour)},
+			wantOccurrence: true,
+		},
+		{
+			name: "resolved alert within cooldown",
+			latest: &Alert{
+				ID:         7,
+				State:      AlertStateResolved,
+				Time:       now.Add(-10 * time.Minute),
+				ResolvedAt: ptr(now.Add(-5 * time.Minute)),
+			},
+		},
+		{
+			name: "resolved long after first seen, re-trigger inside the cooldown",
+			latest: &Alert{
+				ID:         7,
+				State:      AlertStateResolved,
+				Time:       now.Add(-2 * time.Hour),
+				LastSeen:   now.Add(-90 * time.Minute),
+				ResolvedAt: ptr(now.Add(-time.Minute)),
+			},
+		},
+		{
+			name: "resolved alert after cooldown",
+			latest: &Alert{
+				ID:         7,
+				State:      AlertStateResolved,
+				Time:       now.Add(-time.Hour),
+				ResolvedAt: ptr(now.Add(-20 * time.Minute)),
+			},
+			wantSaved: true,
+		},
+		{
+			name:   "resolved alert without resolved time last seen within cooldown",
+			latest: &Alert{ID: 7, State: AlertStateResolved, Time: now.Add(-time.Hour), LastSeen: now.Add(-5 * time.Minute)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			var gotSaved []Alert
+			var gotOccurrences []int64
+
+			r := &RepositoryMock{
+				SaveDeviceMetricFunc: func(ctx context.Context, deviceID string, metric Metric) error {
+					return nil
+				},
+				GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+					return cfg, nil
+				},
+				ListAlertRulesFunc: func(ctx context.Context, deviceID string) ([]AlertRule, error) {
+					return nil, nil
+				},
+				ResolveDeviceAlertsByConditionFunc: func(ctx context.Context, deviceID string, conditions []string, at time.Time) (int64, error) {
+					return 0, nil
+				},
+				GetLatestConditionAlertFunc: func(ctx context.Context, deviceID string, condition string) (Alert, error) {
+					assert.Equal(t, "config:battery", condition)
+					if tt.latest == nil {
+						return Alert{}, ErrRepoItemNotFound
+					}
+					return *tt.latest, nil
+				},
+				RecordAlertOccurrenceFunc: func(ctx context.Context, deviceID string, alertID int64, at time.Time) error {
+					assert.Equal(t, now, at)
+					gotOccurrences = append(gotOccurrences, alertID)
+					return nil
+				},
+				SaveDeviceAlertFunc: func(ctx context.Context, deviceID string, alert Alert) (int64, error) {
+					gotSaved = append(gotSaved, alert)
+					return 8, nil
+				},
+			}
+
+			h := NewService(r, log.NewLogger())
+
+			err := h.RecordMetric(ctx, RecordMetricRequest{
+				DeviceID:    "foo",
+				Temperature: 20,
+				Battery:     10,
+				Timestamp:   now,
+			})
+			require.NoError(t, err)
+
+			if tt.wantSaved {
+				require.Len(t, gotSaved, 1)
+				assert.Equal(t, AlertReasonBatteryLow, gotSaved[0].Reason)
+			} else {
+				assert.Empty(t, gotSaved)
+			}
+			if tt.wantOccurrence {
+				assert.Equal(t, []int64{tt.latest.ID}, gotOccurrences)
+			} else {
+				assert.Empty(t, gotOccurrences)
+			}
+		})
+	}
+}
+
+func TestHandler_AcknowledgeAlert(t *testing.T) {
+	ctx := t.Context()
+
+	req := AcknowledgeAlertRequest{
+		DeviceID:       "foo",
+		AlertID:        1,
+		AcknowledgedBy: "jane",
+		Comment:        "replacing fan",
+	}
+	stored := Alert{ID: 1, DeviceID: "foo", Reason: AlertReasonTemperatureHigh, State: AlertStateOpen}
+
+	r := &RepositoryMock{
+		GetDeviceAlertFunc: func(ctx context.Context, deviceID string, alertID int64) (Alert, error) {
+			assert.Equal(t, req.DeviceID, deviceID)
+			assert.Equal(t, req.AlertID, alertID)
+			return stored, nil
+		},
+		AcknowledgeDeviceAlertFunc: func(ctx context.Context, deviceID string, alertID int64, by string, comment string, at time.Time) error {
+			assert.Equal(t, req.AcknowledgedBy, by)
+			assert.Equal(t, req.Comment, comment)
+			return nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	got, err := h.AcknowledgeAlert(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, AlertStateAcknowledged, got.State)
+	assert.Equal(t, req.AcknowledgedBy, got.AcknowledgedBy)
+	assert.Equal(t, req.Comment, got.AckComment)
+	assert.NotNil(t, got.AcknowledgedAt)
+
+	for _, state := range []AlertState{AlertStateAcknowledged, AlertStateResolved} {
+		stored.State = state
+		_, err = h.AcknowledgeAlert(ctx, req)
+		var cfErr *http.ConflictError
+		require.ErrorAs(t, err, &cfErr)
+	}
+	require.Len(t, r.AcknowledgeDeviceAlertCalls(), 1)
+}
+
+func TestHandler_ResolveAlert(t *testing.T) {
+	ctx := t.Context()
+
+	req := ResolveAlertRequest{DeviceID: "foo", AlertID: 1}
+	stored := Alert{ID: 1, DeviceID: "foo", Reason: AlertReasonTemperatureHigh, State: AlertStateAcknowledged}
+
+	r := &RepositoryMock{
+		GetDeviceAlertFunc: func(ctx context.Context, deviceID string, alertID int64) (Alert, error) {
+			return stored, nil
+		},
+		ResolveDeviceAlertFunc: func(ctx context.Context, deviceID string, alertID int64, at time.Time) error {
+			assert.Equal(t, req.DeviceID, deviceID)
+			assert.Equal(t, req.AlertID, alertID)
+			return nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	got, err := h.ResolveAlert(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, AlertStateResolved, got.State)
+	assert.NotNil(t, got.ResolvedAt)
+
+	stored.State = AlertStateResolved
+	_, err = h.ResolveAlert(ctx, req)
+	var cfErr *http.ConflictError
+	require.ErrorAs(t, err, &cfErr)
+}
+
+func TestHandler_Alert_notFound(t *testing.T) {
+	ctx := t.Context()
+
+	r := &RepositoryMock{
+		GetDeviceAlertFunc: func(ctx context.Context, deviceID string, alertID int64) (Alert, error) {
+			return Alert{}, ErrRepoItemNotFound
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	var nfErr *http.NotFoundError
+	_, err := h.AcknowledgeAlert(ctx, AcknowledgeAlertRequest{DeviceID: "foo", AlertID: 1, AcknowledgedBy: "jane"})
+	require.ErrorAs(t, err, &nfErr)
+	_, err = h.ResolveAlert(ctx, ResolveAlertRequest{DeviceID: "foo", AlertID: 1})
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func TestHandler_AcknowledgeAlert_requestValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		override  func(req *AcknowledgeAlertRequest)
+	}{
+		{
+			name:      "invalid alert id",
+			fieldName: "alert_id",
+			override: func(req *AcknowledgeAlertRequest) {
+				req.AlertID = 0
+			},
+		},
+		{
+			name:      "blank acknowledged by",
+			fieldName: "acknowledged_by",
+			override: func(req *AcknowledgeAlertRequest) {
+				req.AcknowledgedBy = " "
+			},
+		},
+		{
+			name:      "comment too long",
+			fieldName: "comment",
+			override: func(req *AcknowledgeAlertRequest) {
+				req.Comment = strings.Repeat("a", maxAckCommentLen+1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := AcknowledgeAlertRequest{
+				DeviceID:       "foo",
+				AlertID:        1,
+				AcknowledgedBy: "jane",
+			}
+			tt.override(&req)
+
+			h := NewService(nil, log.NewLogger())
+			_, err := h.AcknowledgeAlert(t.Context(), req)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Contains(t, brErr.FieldViolations, tt.fieldName)
+		})
+	}
+}
+
+func TestAlertRule_Cleared(t *testing.T) {
+	tests := []struct {
+		rule  AlertRule
+		value float64
+		want  bool
+	}{
+		{AlertRule{Operator: RuleOperatorGreaterThan, Threshold: 50}, 48.5, false},
+		{AlertRule{Operator: RuleOperatorGreaterThan, Threshold: 50}, 48, true},
+		{AlertRule{Operator: RuleOperatorGreaterThanOrEqual, Threshold: 50}, 48, false},
+		{AlertRule{Operator: RuleOperatorLessThan, Threshold: 20}, 21, false},
+		{AlertRule{Operator: RuleOperatorLessThan, Threshold: 20}, 22, true},
+		{AlertRule{Operator: RuleOperatorLessThanOrEqual, Threshold: 20}, 22.5, true},
+		{AlertRule{Operator: RuleOperatorEqual, Threshold: 0}, 2, false},
+		{AlertRule{Operator: RuleOperatorEqual, Threshold: 0}, -3, true},
+		{AlertRule{Operator: RuleOperatorOutsideRange, Threshold: 20, ThresholdHigh: 80}, 79, false},
+		{AlertRule{Operator: RuleOperatorOutsideRange, Threshold: 20, ThresholdHigh: 80}, 50, true},
+		{AlertRule{Operator: RuleOperatorInsideRange, Threshold: 20, ThresholdHigh: 80}, 81, false},
+		{AlertRule{Operator: RuleOperatorInsideRange, Threshold: 20, ThresholdHigh: 80}, 83, true},
+		{AlertRule{Operator: RuleOperatorRise, Threshold: 5}, 4, false},
+		{AlertRule{Operator: RuleOperatorDrop, Threshold: 5}, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %g", tt.rule.Operator, tt.value), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule.Cleared(tt.value, 2))
+		})
+	}
+}
+
+func TestAlertRule_Reason(t *testing.T) {
+	tests := []struct {
+		rule AlertRule
+		want AlertReason
+	}{
+		{AlertRule{Metric: MetricTemperature, Operator: RuleOperatorGreaterThan}, AlertReasonTemperatureHigh},
+		{AlertRule{Metric: MetricTemperature, Operator: RuleOperatorGreaterThanOrEqual}, AlertReasonTemperatureHigh},
+		{AlertRule{Metric: MetricTemperature, Operator: RuleOperatorLessThan}, AlertReasonThresholdBreached},
+		{AlertRule{Metric: MetricBattery, Operator: RuleOperatorLessThan}, AlertReasonBatteryLow},
+		{AlertRule{Metric: MetricBattery, Operator: RuleOperatorLessThanOrEqual}, AlertReasonBatteryLow},
+		{AlertRule{Metric: MetricBattery, Operator: RuleOperatorOutsideRange}, AlertReasonThresholdBreached},
+		{AlertRule{Metric: "humidity", Operator: RuleOperatorGreaterThan}, AlertReasonThresholdBreached},
+		{AlertRule{Metric: MetricTemperature, Operator: RuleOperatorRise}, AlertReasonRapidRise},
+		{AlertRule{Metric: MetricBattery, Operator: RuleOperatorDrop}, AlertReasonRapidDrop},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.rule.Reason(), "%s %s", tt.rule.Metric, tt.rule.Operator)
+	}
+}
+
+func TestHandler_CreateAlertRule(t *testing.T) {
+	ctx := t.Context()
+
+	req := CreateAlertRuleRequest{
+		DeviceID:      "foo",
+		Metric:        "humidity",
+		Operator:      RuleOperatorOutsideRange,
+		Threshold:     20,
+		ThresholdHigh: 80,
+		Severity:      AlertSeverityCritical,
+	}
+	wantRule := AlertRule{
+		ID:            7,
+		Metric:        req.Metric,
+		Operator:      req.Operator,
+		Threshold:     req.Threshold,
+		ThresholdHigh: req.ThresholdHigh,
+		Severity:      req.Severity,
+	}
+
+	r := &RepositoryMock{
+		CreateAlertRuleFunc: func(ctx context.Context, deviceID string, rule AlertRule) (int64, error) {
+			assert.Equal(t, req.DeviceID, deviceID)
+			assert.Zero(t, rule.ID)
+			rule.ID = wantRule.ID
+			assert.Equal(t, wantRule, rule)
+			return wantRule.ID, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	got, err := h.CreateAlertRule(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, wantRule, got)
+}
+
+func TestHandler_CreateAlertRule_requestValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		override  func(req *CreateAlertRuleRequest)
+	}{
+		{
+			name:      "empty device id",
+			fieldName: "device_id",
+			override: func(req *CreateAlertRuleRequest) {
+				req.DeviceID = ""
+			},
+		},
+		{
+			name:      "invalid metric",
+			fieldName: "metric",
+			override: func(req *CreateAlertRuleRequest) {
+				req.Metric = "Humidity"
+			},
+		},
+		{
+			name:      "invalid operator",
+			fieldName: "operator",
+			override: func(req *CreateAlertRuleRequest) {
+				req.Operator = ">"
+			},
+		},
+		{
+			name:      "range upper bound below lower bound",
+			fieldName: "threshold_high",
+			override: func(req *CreateAlertRuleRequest) {
+				req.Operator = RuleOperatorInsideRange
+				req.Threshold = 10
+				req.ThresholdHigh = 9
+			},
+		},
+		{
+			name:      "invalid severity",
+			fieldName: "severity",
+			override: func(req *CreateAlertRuleRequest) {
+				req.Severity = ""
+			},
+		},
+		{
+			name:      "rate of change without window",
+			fieldName: "window_seconds",
+			override: func(req *CreateAlertRuleRequest) {
+				req.Operator = RuleOperatorRise
+			},
+		},
+		{
+			name:      "rate of change window too long",
+			fieldName: "window_seconds",
+			override: func(req *CreateAlertRuleRequest) {
+				req.Operator = RuleOperatorDrop
+				req.WindowSeconds = int64(maxRuleWindow/time.Second) + 1
+			},
+		},
+		{
+			name:      "rate of change non-positive threshold",
+			fieldName: "threshold",
+			override: func(req *CreateAlertRuleRequest) {
+				req.Operator = RuleOperatorRise
+				req.Threshold = 0
+				req.WindowSeconds = 600
+			},
+		},
+		{
+			name:      "window without rate of change",
+			fieldName: "window_seconds",
+			override: func(req *CreateAlertRuleRequest) {
+				req.WindowSeconds = 600
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			req := CreateAlertRuleRequest{
+				DeviceID:  "foo",
+				Metric:    "humidity",
+				Operator:  RuleOperatorGreaterThan,
+				Threshold: 80,
+				Severity:  AlertSeverityWarning,
+			}
+			require.NotNil(t, tt.override, "test config `override` field must not be nil")
+			tt.override(&req)
+
+			h := NewService(nil, log.NewLogger())
+
+			_, err := h.CreateAlertRule(ctx, req)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Contains(t, brErr.FieldViolations, tt.fieldName)
+		})
+	}
+}
+
+func TestHandler_AlertRule_notFound(t *testing.T) {
+	ctx := t.Context()
+
+	r := &RepositoryMock{
+		GetAlertRuleFunc: func(ctx context.Context, deviceID string, ruleID int64) (AlertRule, error) {
+			return AlertRule{}, ErrRepoItemNotFound
+		},
+		UpdateAlertRuleFunc: func(ctx context.Context, deviceID string, rule AlertRule) error {
+			return ErrRepoItemNotFound
+		},
+		DeleteAlertRuleFunc: func(ctx context.Context, deviceID string, ruleID int64) error {
+			return ErrRepoItemNotFound
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	var nfErr *http.NotFoundError
+
+	_, err := h.GetAlertRule(ctx, GetAlertRuleRequest{DeviceID: "foo", RuleID: 1})
+	require.ErrorAs(t, err, &nfErr)
+
+	_, err = h.UpdateAlertRule(ctx, UpdateAlertRuleRequest{
+		DeviceID:  "foo",
+		RuleID:    1,
+		Metric:    "humidity",
+		Operator:  RuleOperatorGreaterThan,
+		Threshold: 80,
+		Severity:  AlertSeverityWarning,
+	})
+	require.ErrorAs(t, err, &nfErr)
+
+	err = h.DeleteAlertRule(ctx, DeleteAlertRuleRequest{DeviceID: "foo", RuleID: 1})
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func TestHandler_CreateDevice(t *testing.T) {
+	ctx := t.Context()
+
+	req := CreateDeviceRequest{
+		DeviceID:        "foo",
+		DisplayName:     "Greenhouse sensor",
+		Model:           "TH-200",
+		FirmwareVersion: "1.4.2",
+		Location:        "greenhouse-1",
+		Labels:          map[string]string{"env": "prod", "site": "north"},
+	}
+
+	var created Device
+	r := &RepositoryMock{
+		CreateDeviceFunc: func(ctx context.Context, dev Device) error {
+			created = dev
+			return nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	got, err := h.CreateDevice(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, created, got)
+	assert.Equal(t, req.DeviceID, got.ID)
+	assert.Equal(t, req.DisplayName, got.DisplayName)
+	assert.Equal(t, req.Model, got.Model)
+	assert.Equal(t, req.FirmwareVersion, got.FirmwareVersion)
+	assert.Equal(t, req.Location, got.Location)
+	assert.Equal(t, req.Labels, got.Labels)
+	assert.WithinDuration(t, time.Now(), got.CreatedAt, 2*time.Second)
+	assert.Nil(t, got.LastSeen)
+}
+
+func TestHandler_CreateDevice_exists(t *testing.T) {
+	ctx := t.Context()
+
+	r := &RepositoryMock{
+		CreateDeviceFunc: func(ctx context.Context, dev Device) error {
+			return ErrRepoItemExists
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	_, err := h.CreateDevice(ctx, CreateDeviceRequest{DeviceID: "foo"})
+	var aeErr *http.AlreadyExistsError
+	require.ErrorAs(t, err, &aeErr)
+	// distinct from a failed precondition such as a revision mismatch
+	assert.Equal(t, connect.CodeAlreadyExists, aeErr.ConnectError().Code())
+}
+
+func TestHandler_CreateDevice_requestValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		override  func(req *CreateDeviceRequest)
+	}{
+		{
+			name:      "empty device id",
+			fieldName: "device_id",
+			override: func(req *CreateDeviceRequest) {
+				req.DeviceID = ""
+			},
+		},
+		{
+			name:      "device id too long",
+			fieldName: "device_id",
+			override: func(req *CreateDeviceRequest) {
+				req.DeviceID = strings.Repeat("a", maxDeviceIDLen+1)
+			},
+		},
+		{
+			name:      "display name too long",
+			fieldName: "display_name",
+			override: func(req *CreateDeviceRequest) {
+				req.DisplayName = strings.Repeat("a", maxDeviceFieldLen+1)
+			},
+		},
+		{
+			name:      "too many labels",
+			fieldName: "labels",
+			override: func(req *CreateDeviceRequest) {
+				req.Labels = make(map[string]string)
+				for i := range maxDeviceLabels + 1 {
+					req.Labels[fmt.Sprintf("key%d", i)] = "value"
+				}
+			},
+		},
+		{
+			name:      "invalid label key",
+			fieldName: "labels",
+			override: func(req *CreateDeviceRequest) {
+				req.Labels = map[string]string{"-env": "prod"}
+			},
+		},
+		{
+			name:      "invalid label value",
+			fieldName: "labels.env",
+			override: func(req *CreateDeviceRequest) {
+				req.Labels = map[string]string{"env": "prod east"}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			req := CreateDeviceRequest{
+				DeviceID: "foo",
+				Labels:   map[string]string{"env": "prod"},
+			}
+			require.NotNil(t, tt.override, "test config `override` field must not be nil")
+			tt.override(&req)
+
+			h := NewService(nil, log.NewLogger())
+
+			_, err := h.CreateDevice(ctx, req)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Contains(t, brErr.FieldViolations, tt.fieldName)
+		})
+	}
+}
+
+func TestHandler_UpdateDevice(t *testing.T) {
+	ctx := t.Context()
+
+	req := UpdateDeviceRequest{
+		DeviceID:    "foo",
+		DisplayName: "Greenhouse sensor",
+		Labels:      map[string]string{"env": "staging"},
+	}
+	want := Device{
+		ID:          req.DeviceID,
+		DisplayName: req.DisplayName,
+		Labels:      req.Labels,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+
+	r := &RepositoryMock{
+		UpdateDeviceFunc: func(ctx context.Context, dev Device) error {
+			assert.Equal(t, Device{ID: want.ID, DisplayName: want.DisplayName, Labels: want.Labels}, dev)
+			return nil
+		},
+		GetDeviceFunc: func(ctx context.Context, deviceID string) (Device, error) {
+			assert.Equal(t, req.DeviceID, deviceID)
+			return want, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	got, err := h.UpdateDevice(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestHandler_Device_notFound(t *testing.T) {
+	ctx := t.Context()
+
+	r := &RepositoryMock{
+		GetDeviceFunc: func(ctx context.Context, deviceID string) (Device, error) {
+			return Device{}, ErrRepoItemNotFound
+		},
+		UpdateDeviceFunc: func(ctx context.Context, dev Device) error {
+			return ErrRepoItemNotFound
+		},
+		DeleteDeviceFunc: func(ctx context.Context, deviceID string, op Operation) (Operation, error) {
+			return Operation{}, ErrRepoItemNotFound
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	var nfErr *http.NotFoundError
+
+	_, err := h.GetDevice(ctx, GetDeviceRequest{DeviceID: "foo"})
+	require.ErrorAs(t, err, &nfErr)
+
+	_, err = h.UpdateDevice(ctx, UpdateDeviceRequest{DeviceID: "foo"})
+	require.ErrorAs(t, err, &nfErr)
+
+	_, err = h.DeleteDevice(ctx, DeleteDeviceRequest{DeviceID: "foo"})
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func TestHandler_DeleteDevice(t *testing.T) {
+	tests := []struct {
+		name         string
+		alertsErr    error
+		wantState    OperationState
+		wantDeleted  [2]int64
+		wantUpdates  int
+		wantMetricsN int
+	}{
+		{
+			name:         "purge succeeds",
+			wantState:    OperationStateSucceeded,
+			wantDeleted:  [2]int64{purgeBatchSize + 3, 2},
+			wantUpdates:  4,
+			wantMetricsN: 2,
+		},
+		{
+			name:         "purge fails",
+			alertsErr:    assert.AnError,
+			wantState:    OperationStateFailed,
+			wantDeleted:  [2]int64{purgeBatchSize + 3, 0},
+			wantUpdates:  3,
+			wantMetricsN: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			// metrics are deleted in a full batch then a partial batch
+			metricBatches := []int64{purgeBatchSize, 3}
+			var updates []Operation
+
+			r := &RepositoryMock{
+				DeleteDeviceFunc: func(ctx context.Context, deviceID string, op Operation) (Operation, error) {
+					assert.Equal(t, "foo", deviceID)
+					assert.Equal(t, OperationTypeDeleteDevice, op.Type)
+					assert.Equal(t, deviceID, op.DeviceID)
+					assert.Equal(t, OperationStateRunning, op.State)
+					op.ID, op.MaxMetricID, op.MaxAlertID = 7, 2000, 30
+					return op, nil
+				},
+				DeleteDeviceMetricsFunc: func(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error) {
+					// data recorded after the device was deleted is kept
+					assert.Equal(t, int64(2000), maxID)
+					assert.Equal(t, purgeBatchSize, limit)
+					n := metricBatches[0]
+					metricBatches = metricBatches[1:]
+					return n, nil
+				},
+				DeleteDeviceAlertsFunc: func(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error) {
+					assert.Equal(t, int64(30), maxID)
+					if tt.alertsErr != nil {
+						return 0, tt.alertsErr
+					}
+					return 2, nil
+				},
+				UpdateOperationFunc: func(ctx context.Context, op Operation) error {
+					updates = append(updates, op)
+					return nil
+				},
+			}
+
+			h := NewService(r, log.NewLogger())
+
+			op, err := h.DeleteDevice(ctx, DeleteDeviceRequest{DeviceID: "foo"})
+			require.NoError(t, err)
+			assert.Equal(t, int64(7), op.ID)
+			assert.Equal(t, OperationStateRunning, op.State)
+
+			h.operations.wg.Wait() // waits for the purge to finish
+
+			assert.Len(t, r.DeleteDeviceMetricsCalls(), tt.wantMetricsN)
+			require.Len(t, updates, tt.wantUpdates)
+			final := updates[len(updates)-1]
+			assert.Equal(t, op.ID, final.ID)
+			assert.Equal(t, tt.wantState, final.State)
+			assert.Equal(t, tt.wantDeleted, [2]int64{final.DeletedMetrics, final.DeletedAlerts})
+			if tt.alertsErr != nil {
+				assert.NotEmpty(t, final.Error)
+				assert.NotContains(t, final.Error, tt.alertsErr.Error())
+			}
+		})
+	}
+}
+
+func TestService_ResumeOperations(t *testing.T) {
+	ctx := t.Context()
+
+	interrupted := Operation{
+		ID:             3,
+		Type:           OperationTypeDeleteDevice,
+		DeviceID:       "foo",
+		State:          OperationStateRunning,
+		DeletedMetrics: purgeBatchSize,
+	}
+	var final Operation
+
+	r := &RepositoryMock{
+		ListOperationsFunc: func(ctx context.Context, state OperationState) ([]Operation, error) {
+			assert.Equal(t, OperationStateRunning, state)
+			return []Operation{interrupted}, nil
+		},
+		DeleteDeviceMetricsFunc: func(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error) {
+			return 1, nil
+		},
+		DeleteDeviceAlertsFunc: func(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error) {
+			return 0, nil
+		},
+		UpdateOperationFunc: func(ctx context.Context, op Operation) error {
+			final = op
+			return nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+	require.NoError(t, h.ResumeOperations(ctx))
+	h.operations.wg.Wait()
+
+	assert.Equal(t, interrupted.ID, final.ID)
+	assert.Equal(t, OperationStateSucceeded, final.State)
+	// progress continues from where the operation was interrupted
+	assert.Equal(t, int64(purgeBatchSize+1), final.DeletedMetrics)
+}
+
+func TestHandler_GetOperation(t *testing.T) {
+	ctx := t.Context()
+
+	want := Operation{ID: 3, Type: OperationTypeDeleteDevice, DeviceID: "foo", State: OperationStateSucceeded}
+
+	r := &RepositoryMock{
+		GetOperationFunc: func(ctx context.Context, id int64) (Operation, error) {
+			if id != want.ID {
+				return Operation{}, ErrRepoItemNotFound
+			}
+			return want, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	got, err := h.GetOperation(ctx, GetOperationRequest{OperationID: 3})
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = h.GetOperation(ctx, GetOperationRequest{OperationID: 4})
+	var nfErr *http.NotFoundError
+	require.ErrorAs(t, err, &nfErr)
+
+	_, err = h.GetOperation(ctx, GetOperationRequest{})
+	var brErr *http.BadRequestError
+	require.ErrorAs(t, err, &brErr)
+	assert.Contains(t, brErr.FieldViolations, "operation_id")
+
+	_, err = h.DeleteDevice(ctx, DeleteDeviceRequest{DeviceID: " "})
+	require.ErrorAs(t, err, &brErr)
+	assert.Contains(t, brErr.FieldViolations, "device_id")
+}
+
+func TestHandler_ListDevices(t *testing.T) {
+	ctx := t.Context()
+
+	req := ListDevicesRequest{
+		PageSize:      2,
+		LabelSelector: "env=prod, site!=north, tier, !deprecated",
+	}
+	devices := []Device{{ID: "foo"}, {ID: "bar"}}
+	nextTkn := &RepositoryPageToken{
+		LastID:   ptr(int64(2)),
+		LastTime: ptr(time.Now().UTC().Truncate(time.Second)),
+	}
+
+	r := &RepositoryMock{
+		ListDevicesFunc: func(ctx context.Context, selector LabelSelector, pageOpts RepositoryPageOptions) (RepositoryPage[Device], error) {
+			assert.Equal(t, LabelSelector{
+				{Key: "env", Operator: LabelOperatorEquals, Value: "prod"},
+				{Key: "site", Operator: LabelOperatorNotEquals, Value: "north"},
+				{Key: "tier", Operator: LabelOperatorExists},
+				{Key: "deprecated", Operator: LabelOperatorNotExists},
+			}, selector)
+			assert.Equal(t, req.PageSize, pageOpts.Size)
+			assert.Nil(t, pageOpts.Token)
+			return RepositoryPage[Device]{Items: devices, NextPageToken: nextTkn}, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+	h.pageTokens = newTestPageTokens()
+
+	got, err := h.ListDevices(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, devices, got.Devices)
+	wantTkn, err := h.pageTokens.encode(*nextTkn, pageScope{Method: "ListDevices", Filters: req.LabelSelector})
+	require.NoError(t, err)
+	assert.Equal(t, wantTkn, got.NextPageToken)
+}
+
+func TestHandler_GetFleetSnapshot(t *testing.T) {
+	ctx := t.Context()
+
+	req := GetFleetSnapshotRequest{
+		PageSize:      2,
+		LabelSelector: "env=prod",
+		GroupID:       "greenhouse",
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	snapshots := []DeviceSnapshot{
+		{
+			Device:       Device{ID: "foo", LastSeen: &now},
+			LatestMetric: &Metric{Values: []MetricValue{{Name: MetricTemperature, Value: 21.5}}, Time: now},
+			OpenAlerts:   2,
+		},
+		{Device: Device{ID: "bar"}},
+	}
+	nextTkn := &RepositoryPageToken{
+		LastID:   ptr(int64(2)),
+		LastTime: ptr(now),
+	}
+
+	r := &RepositoryMock{
+		GetFleetSnapshotFunc: func(ctx context.Context, selector LabelSelector, groupID string, pageOpts RepositoryPageOptions) (RepositoryPage[DeviceSnapshot], error) {
+			assert.Equal(t, LabelSelector{{Key: "env", Operator: LabelOperatorEquals, Value: "prod"}}, selector)
+			assert.Equal(t, req.GroupID, groupID)
+			assert.Equal(t, req.PageSize, pageOpts.Size)
+			return RepositoryPage[DeviceSnapshot]{Items: snapshots, NextPageToken: nextTkn}, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+	h.pageTokens = newTestPageTokens()
+
+	got, err := h.GetFleetSnapshot(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, snapshots, got.Devices)
+	wantTkn, err := h.pageTokens.encode(*nextTkn, pageScope{
+		Method:  "GetFleetSnapshot",
+		Filters: []any{req.LabelSelector, req.GroupID},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, wantTkn, got.NextPageToken)
+
+	_, err = h.GetFleetSnapshot(ctx, GetFleetSnapshotRequest{LabelSelector: "env=prod,=bad"})
+	var brErr *http.BadRequestError
+	require.ErrorAs(t, err, &brErr)
+	assert.Contains(t, brErr.FieldViolations, "label_selector")
+}
+
+func TestHandler_ListDevices_requestValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		req       ListDevicesRequest
+	}{
+		{
+			name:      "negative page size",
+			fieldName: "page.size",
+			req:       ListDevicesRequest{PageSize: -1},
+		},
+		{
+			name:      "invalid label selector key",
+			fieldName: "label_selector",
+			req:       ListDevicesRequest{LabelSelector: "env=prod,=north"},
+		},
+		{
+			name:      "invalid label selector value",
+			fieldName: "label_selector",
+			req:       ListDevicesRequest{LabelSelector: "env=prod east"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			h := NewService(nil, log.NewLogger())
+
+			_, err := h.ListDevices(ctx, tt.req)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Contains(t, brErr.FieldViolations, tt.fieldName)
+		})
+	}
+}
+
+func TestResolveConfig(t *testing.T) {
+	levels := map[ConfigLevel]ConfigSettings{
+		ConfigLevelGlobal: {
+			TemperatureThreshold: ptr(60.0),
+			BatteryThreshold:     ptr(int32(10)),
+			Cooldowns:            []AlertCooldown{{Reason: AlertReasonBatteryLow, Window: time.Hour}},
+		},
+		ConfigLevelGroup: {
+			TemperatureThreshold: ptr(40.0),
+			ReportingInterval:    ptr(time.Minute),
+		},
+		ConfigLevelDevice: {
+			TemperatureThreshold: ptr(35.0),
+			Cooldowns:            []AlertCooldown{},
+		},
+	}
+
+	got := ResolveConfig(levels)
+	assert.Equal(t, Config{
+		TemperatureThreshold: 35,
+		BatteryThreshold:     10,
+		Cooldowns:            []AlertCooldown{},
+		ReportingInterval:    time.Minute,
+		Sources: map[ConfigSetting]ConfigLevel{
+			SettingTemperatureThreshold: ConfigLevelDevice,
+			SettingBatteryThreshold:     ConfigLevelGlobal,
+			SettingCooldowns:            ConfigLevelDevice,
+			SettingReportingInterval:    ConfigLevelGroup,
+		},
+	}, got)
+
+	// only thresholds that are set at some level derive rules
+	rules := ResolveConfig(map[ConfigLevel]ConfigSettings{
+		ConfigLevelGroup: {BatteryThreshold: ptr(int32(10))},
+	}).Rules()
+	require.Len(t, rules, 1)
+	assert.Equal(t, MetricBattery, rules[0].Metric)
+}
+
+func TestHandler_ConfigureDeviceGroup(t *testing.T) {
+	ctx := t.Context()
+
+	req := ConfigureDeviceGroupRequest{
+		GroupID: "sensors",
+		ConfigSettingsBody: ConfigSettingsBody{
+			TemperatureThreshold: ptr(40.0),
+		},
+	}
+
+	var configured bool
+	r := &RepositoryMock{
+		GetDeviceGroupFunc: func(ctx context.Context, groupID string) (DeviceGroup, error) {
+			assert.Equal(t, req.GroupID, groupID)
+			return DeviceGroup{ID: groupID}, nil
+		},
+		UpsertDeviceGroupConfigFunc: func(ctx context.Context, groupID string, settings ConfigSettings) error {
+			assert.Equal(t, req.GroupID, groupID)
+			assert.Equal(t, ConfigSettings{TemperatureThreshold: req.TemperatureThreshold}, settings)
+			configured = true
+			return nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	require.NoError(t, h.ConfigureDeviceGroup(ctx, req))
+	assert.True(t, configured)
+}
+
+func TestHandler_DeviceGroup_notFound(t *testing.T) {
+	ctx := t.Context()
+
+	r := &RepositoryMock{
+		GetDeviceGroupFunc: func(ctx context.Context, groupID string) (DeviceGroup, error) {
+			return DeviceGroup{}, ErrRepoItemNotFound
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	var nfErr *http.NotFoundError
+
+	_, err := h.GetDeviceGroup(ctx, GetDeviceGroupRequest{GroupID: "sensors"})
+	require.ErrorAs(t, err, &nfErr)
+
+	err = h.ConfigureDeviceGroup(ctx, ConfigureDeviceGroupRequest{GroupID: "sensors"})
+	require.ErrorAs(t, err, &nfErr)
+
+	// assigning a device to a group that does not exist is a bad request
+	var brErr *http.BadRequestError
+	_, err = h.CreateDevice(ctx, CreateDeviceRequest{DeviceID: "foo", GroupID: "sensors"})
+	require.ErrorAs(t, err, &brErr)
+	assert.Contains(t, brErr.FieldViolations, "group_id")
+}
+
+func TestHandler_CreateDeviceGroup(t *testing.T) {
+	ctx := t.Context()
+
+	req := CreateDeviceGroupRequest{GroupID: "sensors", DisplayName: "Sensors"}
+
+	var created DeviceGroup
+	r := &RepositoryMock{
+		CreateDeviceGroupFunc: func(ctx context.Context, group DeviceGroup) error {
+			if created.ID != "" {
+				return ErrRepoItemExists
+			}
+			created = group
+			return nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	got, err := h.CreateDeviceGroup(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, created, got)
+	assert.Equal(t, req.GroupID, got.ID)
+	assert.Equal(t, req.DisplayName, got.DisplayName)
+	assert.WithinDuration(t, time.Now(), got.CreatedAt, 2*time.Second)
+
+	_, err = h.CreateDeviceGroup(ctx, req)
+	var aeErr *http.AlreadyExistsError
+	require.ErrorAs(t, err, &aeErr)
+
+	_, err = h.CreateDeviceGroup(ctx, CreateDeviceGroupRequest{GroupID: " "})
+	var brErr *http.BadRequestError
+	require.ErrorAs(t, err, &brErr)
+	assert.Contains(t, brErr.FieldViolations, "group_id")
+}
+
+func TestHandler_GetEffectiveDeviceConfig(t *testing.T) {
+	ctx := t.Context()
+
+	cfg := ResolveConfig(map[ConfigLevel]ConfigSettings{
+		ConfigLevelGroup:  {TemperatureThreshold: ptr(40.0)},
+		ConfigLevelGlobal: {TemperatureThreshold: ptr(60.0), BatteryThreshold: ptr(int32(10))},
+	})
+
+	r := &RepositoryMock{
+		GetDeviceFunc: func(ctx context.Context, deviceID string) (Device, error) {
+			return Device{ID: deviceID, GroupID: "sensors"}, nil
+		},
+		GetDeviceConfigFunc: func(ctx context.Context, deviceID string) (Config, error) {
+			assert.Equal(t, "foo", deviceID)
+			return cfg, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	got, err := h.GetEffectiveDeviceConfig(ctx, GetEffectiveDeviceConfigRequest{DeviceID: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, GetEffectiveDeviceConfigResponse{
+		GroupID: "sensors",
+		Settings: ConfigSettingsBody{
+			TemperatureThreshold: ptr(40.0),
+			BatteryThreshold:     ptr(int32(10)),
+		},
+		Sources: map[ConfigSetting]ConfigLevel{
+			SettingTemperatureThreshold: ConfigLevelGroup,
+			SettingBatteryThreshold:     ConfigLevelGlobal,
+		},
+	}, got)
+
+	// devices without any settings have an empty effective config
+	r.GetDeviceConfigFunc = func(ctx context.Context, deviceID string) (Config, error) {
+		return Config{}, ErrRepoItemNotFound
+	}
+	got, err = h.GetEffectiveDeviceConfig(ctx, GetEffectiveDeviceConfigRequest{DeviceID: "foo"})
+	require.NoError(t, err)
+	assert.Empty(t, got.Sources)
+	assert.Equal(t, ConfigSettingsBody{}, got.Settings)
+}
+
+func TestHandler_ListDeviceConfigVersions(t *testing.T) {
+	ctx := t.Context()
+
+	req := ListDeviceConfigVersionsRequest{DeviceID: "foo", PageSize: 2}
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	versions := []DeviceConfigVersion{
+		{Version: 2, Settings: ConfigSettings{TemperatureThreshold: ptr(35.0)}, CreatedAt: createdAt, Author: "jane"},
+		{Version: 1, Settings: ConfigSettings{BatteryThreshold: ptr(int32(10))}, CreatedAt: createdAt, Reason: "Initial"},
+	}
+	nextTkn := &RepositoryPageToken{LastID: ptr(int64(1)), LastTime: &createdAt}
+
+	r := &RepositoryMock{
+		ListDeviceConfigVersionsFunc: func(ctx context.Context, deviceID string, pageOpts RepositoryPageOptions) (RepositoryPage[DeviceConfigVersion], error) {
+			assert.Equal(t, req.DeviceID, deviceID)
+			assert.Equal(t, req.PageSize, pageOpts.Size)
+			return RepositoryPage[DeviceConfigVersion]{Items: versions, NextPageToken: nextTkn}, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+	h.pageTokens = newTestPageTokens()
+
+	got, err := h.ListDeviceConfigVersions(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, []DeviceConfigVersionBody{
+		{Version: 2, Settings: ConfigSettingsBody{TemperatureThreshold: ptr(35.0)}, CreatedAt: createdAt, Author: "jane"},
+		{Version: 1, Settings: ConfigSettingsBody{BatteryThreshold: ptr(int32(10))}, CreatedAt: createdAt, Reason: "Initial"},
+	}, got.Versions)
+	wantTkn, err := h.pageTokens.encode(*nextTkn, pageScope{Method: "ListDeviceConfigVersions", DeviceID: req.DeviceID})
+	require.NoError(t, err)
+	assert.Equal(t, wantTkn, got.NextPageToken)
+}
+
+func TestHandler_RollbackDeviceConfig(t *testing.T) {
+	ctx := t.Context()
+
+	req := RollbackDeviceConfigRequest{DeviceID: "foo", Version: 2, Author: "jane"}
+	prev := DeviceConfigVersion{
+		Version:   2,
+		Settings:  ConfigSettings{TemperatureThreshold: ptr(35.0), Expressions: []AlertExpression{}},
+		CreatedAt: time.Now().Add(-time.Hour).UTC(),
+		Author:    "john",
+	}
+
+	r := &RepositoryMock{
+		GetDeviceConfigVersionFunc: func(ctx context.Context, deviceID string, version int64) (DeviceConfigVersion, error) {
+			assert.Equal(t, req.DeviceID, deviceID)
+			if version != prev.Version {
+				return DeviceConfigVersion{}, ErrRepoItemNotFound
+			}
+			return prev, nil
+		},
+		UpsertDeviceConfigFunc: func(ctx context.Context, deviceID string, version DeviceConfigVersion, expectedRevision *int64) (int64, error) {
+			assert.Equal(t, req.DeviceID, deviceID)
+			assert.Equal(t, prev.Settings, version.Settings)
+			assert.Equal(t, req.Author, version.Author)
+			assert.Equal(t, "Rollback to version 2", version.Reason)
+			return 5, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	got, err := h.RollbackDeviceConfig(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), got.Version)
+	assert.Equal(t, newConfigSettingsBody(prev.Settings), got.Settings)
+	assert.Equal(t, req.Author, got.Author)
+	assert.WithinDuration(t, time.Now(), got.CreatedAt, 2*time.Second)
+
+	_, err = h.RollbackDeviceConfig(ctx, RollbackDeviceConfigRequest{DeviceID: "foo", Version: 3})
+	var nfErr *http.NotFoundError
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func TestHandler_RollbackDeviceConfig_requestValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		req       RollbackDeviceConfigRequest
+	}{
+		{
+			name:      "empty device id",
+			fieldName: "device_id",
+			req:       RollbackDeviceConfigRequest{Version: 1},
+		},
+		{
+			name:      "zero version",
+			fieldName: "version",
+			req:       RollbackDeviceConfigRequest{DeviceID: "foo"},
+		},
+		{
+			name:      "author too long",
+			fieldName: "author",
+			req:       RollbackDeviceConfigRequest{DeviceID: "foo", Version: 1, Author: strings.Repeat("a", maxConfigAuthorLen+1)},
+		},
+		{
+			name:      "reason too long",
+			fieldName: "reason",
+			req:       RollbackDeviceConfigRequest{DeviceID: "foo", Version: 1, Reason: strings.Repeat("a", maxConfigReasonLen+1)},
+		},
+		{
+			name:      "negative expected revision",
+			fieldName: "expected_revision",
+			req:       RollbackDeviceConfigRequest{DeviceID: "foo", Version: 1, ExpectedRevision: ptr(int64(-1))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			h := NewService(nil, log.NewLogger())
+
+			_, err := h.RollbackDeviceConfig(ctx, tt.req)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Contains(t, brErr.FieldViolations, tt.fieldName)
+		})
+	}
+}
+
+func TestHandler_GetDeviceConfig(t *testing.T) {
+	ctx := t.Context()
+
+	current := DeviceConfigVersion{
+		Version:   3,
+		Settings:  ConfigSettings{BatteryThreshold: ptr(int32(10))},
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Author:    "jane",
+	}
+
+	r := &RepositoryMock{
+		GetLatestDeviceConfigVersionFunc: func(ctx context.Context, deviceID string) (DeviceConfigVersion, error) {
+			if deviceID != "foo" {
+				return DeviceConfigVersion{}, ErrRepoItemNotFound
+			}
+			return current, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	got, err := h.GetDeviceConfig(ctx, GetDeviceConfigRequest{DeviceID: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, newDeviceConfigVersionBody(current), got)
+
+	_, err = h.GetDeviceConfig(ctx, GetDeviceConfigRequest{DeviceID: "bar"})
+	var nfErr *http.NotFoundError
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func TestHandler_GetDeviceConfig_emptyLists(t *testing.T) {
+	ctx := t.Context()
+
+	// empty lists override inherited lists so they must not be returned as
+	// omitted
+	r := &RepositoryMock{
+		GetLatestDeviceConfigVersionFunc: func(ctx context.Context, deviceID string) (DeviceConfigVersion, error) {
+			return DeviceConfigVersion{
+				Version: 1,
+				Settings: ConfigSettings{
+					Expressions: []AlertExpression{},
+					Cooldowns:   []AlertCooldown{},
+				},
+			}, nil
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	got, err := h.GetDeviceConfig(ctx, GetDeviceConfigRequest{DeviceID: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, []ConfigureDeviceExpression{}, got.Settings.Expressions)
+	assert.Equal(t, []ConfigureDeviceCooldown{}, got.Settings.Cooldowns)
+
+	data, err := json.Marshal(got.Settings)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"expressions": [], "cooldowns": []}`, string(data))
+
+	settingspb := got.Settings.settings().Proto()
+	assert.NotNil(t, settingspb.Expressions)
+	assert.Empty(t, settingspb.Expressions)
+	assert.NotNil(t, settingspb.Cooldowns)
+	assert.Empty(t, settingspb.Cooldowns)
+}
+
+func TestHandler_UpdateDeviceConfig(t *testing.T) {
+	current := ConfigSettings{
+		TemperatureThreshold: ptr(30.0),
+		BatteryThreshold:     ptr(int32(10)),
+		Cooldowns:            []AlertCooldown{{Reason: AlertReasonBatteryLow, Window: time.Hour}},
+	}
+
+	tests := []struct {
+		name          string
+		currentExists bool
+		req           UpdateDeviceConfigRequest
+		want          ConfigSettings
+	}{
+		{
+			name:          "update setting",
+			currentExists: true,
+			req: UpdateDeviceConfigRequest{
+				Settings: ConfigSettingsBody{
+					BatteryThreshold: ptr(int32(20)),
+					// not in the mask, so neither updated nor validated
+					TemperatureThreshold: ptr(maxTemperature + 1),
+				},
+				UpdateMask:       []ConfigSetting{SettingBatteryThreshold},
+				ExpectedRevision: ptr(int64(1)),
+			},
+			want: ConfigSettings{
+				TemperatureThreshold: ptr(30.0),
+				BatteryThreshold:     ptr(int32(20)),
+				Cooldowns:            current.Cooldowns,
+			},
+		},
+		{
+			name:          "clear settings",
+			currentExists: true,
+			req: UpdateDeviceConfigRequest{
+				Settings:   ConfigSettingsBody{Hysteresis: ptr(1.5)},
+				UpdateMask: []ConfigSetting{SettingTemperatureThreshold, SettingCooldowns, SettingHysteresis},
+			},
+			want: ConfigSettings{
+				BatteryThreshold: ptr(int32(10)),
+				Hysteresis:       ptr(1.5),
+			},
+		},
+		{
+			name: "without existing config",
+			req: UpdateDeviceConfigRequest{
+				Settings:   ConfigSettingsBody{ReportingIntervalSeconds: ptr(int64(60))},
+				UpdateMask: []ConfigSetting{SettingReportingInterval},
+			},
+			want: ConfigSettings{ReportingInterval: ptr(time.Minute)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			req := tt.req
+			req.DeviceID = "foo"
+			req.Author = "jane"
+
+			r := &RepositoryMock{
+				GetLatestDeviceConfigVersionFunc: func(ctx context.Context, deviceID string) (DeviceConfigVersion, error) {
+					assert.Equal(t, req.DeviceID, deviceID)
+					if !tt.currentExists {
+						return DeviceConfigVersion{}, ErrRepoItemNotFound
+					}
+					return DeviceConfigVersion{Version: 1, Settings: current}, nil
+				},
+				UpsertDeviceConfigFunc: func(ctx context.Context, deviceID string, version DeviceConfigVersion, expectedRevision *int64) (int64, error) {
+					assert.Equal(t, req.DeviceID, deviceID)
+					assert.Equal(t, tt.want, version.Settings)
+					assert.Equal(t, req.Author, version.Author)
+					assert.Equal(t, req.ExpectedRevision, expectedRevision)
+					return 2, nil
+				},
+			}
+
+			h := NewService(r, log.NewLogger())
+
+			got, err := h.UpdateDeviceConfig(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), got.Version)
+			assert.Equal(t, newConfigSettingsBody(tt.want), got.Settings)
+		})
+	}
+}
+
+func TestConnectHandler_UpdateDeviceConfig_emptyLists(t *testing.T) {
+	ctx := t.Context()
+
+	var got ConfigSettings
+	r := &RepositoryMock{
+		GetLatestDeviceConfigVersionFunc: func(ctx context.Context, deviceID string) (DeviceConfigVersion, error) {
+			return DeviceConfigVersion{}, ErrRepoItemNotFound
+		},
+		UpsertDeviceConfigFunc: func(ctx context.Context, deviceID string, version DeviceConfigVersion, expectedRevision *int64) (int64, error) {
+			got = version.Settings
+			return 1, nil
+		},
+	}
+
+	h := NewConnectHandler(NewService(r, log.NewLogger()))
+
+	// lists in the update mask that are empty override inherited lists, while
+	// other settings in the mask that are unset are cleared
+	_, err := h.UpdateDeviceConfig(ctx, connect.NewRequest(&iotv1.UpdateDeviceConfigRequest{
+		DeviceId: "foo",
+		Settings: &iotv1.ConfigSettings{},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{
+			string(SettingExpressions),
+			string(SettingCooldowns),
+			string(SettingBatteryThreshold),
+		}},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, ConfigSettings{
+		Expressions: []AlertExpression{},
+		Cooldowns:   []AlertCooldown{},
+	}, got)
+}
+
+func TestEchoHandler_UpdateDeviceConfig(t *testing.T) {
+	current := ConfigSettings{
+		BatteryThreshold: ptr(int32(10)),
+		Cooldowns:        []AlertCooldown{{Reason: AlertReasonTemperatureHigh, Window: time.Minute}},
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		want         ConfigSettings
+		wantCode     int
+		wantViolated string
+	}{
+		{
+			name: "null value",
+			body: `{"settings": {"battery_threshold": null}}`,
+			want: ConfigSettings{Cooldowns: current.Cooldowns},
+		},
+		{
+			name: "empty list",
+			body: `{"settings": {"cooldowns": []}}`,
+			want: ConfigSettings{BatteryThreshold: current.BatteryThreshold, Cooldowns: []AlertCooldown{}},
+		},
+		{
+			name: "value",
+			body: `{"settings": {"hysteresis": 1.5}, "author": "jane"}`,
+			want: ConfigSettings{BatteryThreshold: current.BatteryThreshold, Cooldowns: current.Cooldowns, Hysteresis: ptr(1.5)},
+		},
+		{
+			name:         "unknown key",
+			body:         `{"settings": {"humidity_threshold": 80}}`,
+			wantViolated: "update_mask",
+		},
+		{
+			name:         "missing settings",
+			body:         `{"author": "jane"}`,
+			wantViolated: "update_mask",
+		},
+		{
+			name:     "invalid json",
+			body:     `{"settings": `,
+			wantCode: nethttp.StatusBadRequest,
+		},
+		{
+			name:     "too large",
+			body:     `{"settings": {}, "reason": "` + strings.Repeat("a", maxConfigPatchSize) + `"}`,
+			wantCode: nethttp.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RepositoryMock{
+				GetLatestDeviceConfigVersionFunc: func(ctx context.Context, deviceID string) (DeviceConfigVersion, error) {
+					return DeviceConfigVersion{Version: 1, Settings: current}, nil
+				},
+				UpsertDeviceConfigFunc: func(ctx context.Context, deviceID string, version DeviceConfigVersion, expectedRevision *int64) (int64, error) {
+					assert.Equal(t, "foo", deviceID)
+					assert.Equal(t, tt.want, version.Settings)
+					return 2, nil
+				},
+			}
+			h := NewEchoHandler(NewService(r, log.NewLogger()))
+
+			req := httptest.NewRequest(nethttp.MethodPatch, "/devices/foo/config", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, "application/merge-patch+json")
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			c.SetParamNames("device_id")
+			c.SetParamValues("foo")
+
+			err := h.UpdateDeviceConfig(c)
+			switch {
+			case tt.wantViolated != "":
+				var brErr *http.BadRequestError
+				require.ErrorAs(t, err, &brErr)
+				assert.Contains(t, brErr.FieldViolations, tt.wantViolated)
+			case tt.wantCode != 0:
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, tt.wantCode, httpErr.Code)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, nethttp.StatusOK, rec.Code)
+				assert.Len(t, r.UpsertDeviceConfigCalls(), 1)
+				return
+			}
+			assert.Empty(t, r.UpsertDeviceConfigCalls())
+		})
+	}
+}
+
+func TestEchoHandler_ifMatchRevision(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   []string
+		current  int64
+		want     *int64
+		wantCode int
+	}{
+		{name: "not set", current: 3},
+		{name: "revision", header: []string{`"3"`}, current: 3, want: ptr[int64](3)},
+		{name: "no config", header: []string{`"0"`}, want: ptr[int64](0)},
+		{name: "any", header: []string{`*`}, current: 3, want: ptr[int64](3)},
+		{name: "any without config", header: []string{`*`}, wantCode: nethttp.StatusPreconditionFailed},
+		{name: "list", header: []string{`"2", "3"`}, current: 3, want: ptr[int64](3)},
+		{name: "list across headers", header: []string{`"2"`, `"3"`}, current: 3, want: ptr[int64](3)},
+		{name: "list mismatch", header: []string{`"1","2"`}, current: 3, wantCode: nethttp.StatusPreconditionFailed},
+		{name: "weak", header: []string{`W/"3"`}, current: 3, wantCode: nethttp.StatusPreconditionFailed},
+		{name: "weak and strong", header: []string{`W/"2", "3"`}, current: 3, want: ptr[int64](3)},
+		{name: "not a revision", header: []string{`"abc"`}, current: 3, wantCode: nethttp.StatusPreconditionFailed},
+		{name: "unquoted", header: []string{`3`}, current: 3, wantCode: nethttp.StatusBadRequest},
+		{name: "unterminated", header: []string{`"3`}, current: 3, wantCode: nethttp.StatusBadRequest},
+		{name: "any in list", header: []string{`*, "3"`}, current: 3, wantCode: nethttp.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RepositoryMock{
+				GetLatestDeviceConfigVersionFunc: func(ctx context.Context, deviceID string) (DeviceConfigVersion, error) {
+					assert.Equal(t, "foo", deviceID)
+					if tt.current == 0 {
+						return DeviceConfigVersion{}, ErrRepoItemNotFound
+					}
+					return DeviceConfigVersion{Version: tt.current}, nil
+				},
+			}
+			h := NewEchoHandler(NewService(r, log.NewLogger()))
+
+			req := httptest.NewRequest(nethttp.MethodPatch, "/devices/foo/config", nil)
+			for _, v := range tt.header {
+				req.Header.Add("If-Match", v)
+			}
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			got, err := h.ifMatchRevision(c, "foo")
+			switch tt.wantCode {
+			case 0:
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			case nethttp.StatusPreconditionFailed:
+				var pfErr *http.PreconditionFailedError
+				require.ErrorAs(t, err, &pfErr)
+			default:
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, tt.wantCode, httpErr.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_DeviceConfig_revisionMismatch(t *testing.T) {
+	ctx := t.Context()
+
+	r := &RepositoryMock{
+		GetLatestDeviceConfigVersionFunc: func(ctx context.Context, deviceID string) (DeviceConfigVersion, error) {
+			return DeviceConfigVersion{Version: 3}, nil
+		},
+		GetDeviceConfigVersionFunc: func(ctx context.Context, deviceID string, version int64) (DeviceConfigVersion, error) {
+			return DeviceConfigVersion{Version: version}, nil
+		},
+		UpsertDeviceConfigFunc: func(ctx context.Context, deviceID string, version DeviceConfigVersion, expectedRevision *int64) (int64, error) {
+			assert.Equal(t, ptr(int64(2)), expectedRevision)
+			return 0, ErrRepoRevisionMismatch
+		},
+	}
+
+	h := NewService(r, log.NewLogger())
+
+	_, err := h.ConfigureDevice(ctx, ConfigureDeviceRequest{DeviceID: "foo", ExpectedRevision: ptr(int64(2))})
+	var pfErr *http.PreconditionFailedError
+	require.ErrorAs(t, err, &pfErr)
+	assert.Equal(t, "config for device foo is not at revision 2", pfErr.Message)
+
+	_, err = h.UpdateDeviceConfig(ctx, UpdateDeviceConfigRequest{
+		DeviceID:         "foo",
+		UpdateMask:       []ConfigSetting{SettingHysteresis},
+		ExpectedRevision: ptr(int64(2)),
+	})
+	require.ErrorAs(t, err, &pfErr)
+
+	_, err = h.RollbackDeviceConfig(ctx, RollbackDeviceConfigRequest{
+		DeviceID:         "foo",
+		Version:          1,
+		ExpectedRevision: ptr(int64(2)),
+	})
+	require.ErrorAs(t, err, &pfErr)
+}
+
+func TestHandler_UpdateDeviceConfig_requestValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		req       UpdateDeviceConfigRequest
+	}{
+		{
+			name:      "empty device id",
+			fieldName: "device_id",
+			req:       UpdateDeviceConfigRequest{UpdateMask: []ConfigSetting{SettingHysteresis}},
+		},
+		{
+			name:      "empty update mask",
+			fieldName: "update_mask",
+			req:       UpdateDeviceConfigRequest{DeviceID: "foo"},
+		},
+		{
+			name:      "unknown setting",
+			fieldName: "update_mask",
+			req:       UpdateDeviceConfigRequest{DeviceID: "foo", UpdateMask: []ConfigSetting{"humidity_threshold"}},
+		},
+		{
+			name:      "invalid setting",
+			fieldName: "settings.battery_threshold",
+			req: UpdateDeviceConfigRequest{
+				DeviceID:   "foo",
+				Settings:   ConfigSettingsBody{BatteryThreshold: ptr(int32(maxBattery + 1))},
+				UpdateMask: []ConfigSetting{SettingBatteryThreshold},
+			},
+		},
+		{
+			name:      "invalid expression",
+			fieldName: "settings.expressions[0].expr",
+			req: UpdateDeviceConfigRequest{
+				DeviceID: "foo",
+				Settings: ConfigSettingsBody{Expressions: []ConfigureDeviceExpression{
+					{Expr: "temperature >", Severity: AlertSeverityWarning},
+				}},
+				UpdateMask: []ConfigSetting{SettingExpressions},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			h := NewService(nil, log.NewLogger())
+
+			_, err := h.UpdateDeviceConfig(ctx, tt.req)
+			var brErr *http.BadRequestError
+			require.ErrorAs(t, err, &brErr)
+			assert.Contains(t, brErr.FieldViolations, tt.fieldName)
+		})
+	}
+}
+
+var testPageTokensNow = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestPageTokens returns page tokens with a fixed key and clock, so that
+// tokens encoded by tests match the tokens returned by the service.
+func newTestPageTokens() *pageTokens {
+	tokens := newPageTokens([]byte("test-page-token-key"), time.Hour)
+	tokens.now = func() time.Time { return testPageTokensNow }
+	return tokens
+}
+
+// metricRange returns the range of the named metric within the metrics
+// recorded from start inclusive to end exclusive, like the repository.
+func metricRange(metrics []Metric, name string, start time.Time, end time.Time) (MetricRange, error) {
+	var r MetricRange
+	found := false
+	for _, m := range metrics {
+		v, ok := m.Value(name)
+		if !ok || m.Time.Before(start) || !m.Time.Before(end) {
+			continue
+		}
+		if !found {
+			r, found = MetricRange{Low: v, High: v}, true
+			continue
+		}
+		r.Low, r.High = min(r.Low, v), max(r.High, v)
+	}
+	if !found {
+		return MetricRange{}, ErrRepoItemNotFound
+	}
+	return r, nil
+}
@@ -1,54 +1,527 @@
 package device
 
 import (
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/joshjon/iot-metrics/http"
+	"github.com/joshjon/iot-metrics/proto/gen/iot/v1"
 )
 
+var metricNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
 func validateConfigureDeviceReq(req ConfigureDeviceRequest) error {
 	v := http.NewRequestValidator()
 	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
-	v.Field("temperature_threshold").
-		When(req.TemperatureThreshold < minTemperature || req.TemperatureThreshold > maxTemperature).
-		Messagef("Must be between %.2f and %.2f", minTemperature, maxTemperature)
-	v.Field("battery_threshold").
-		When(req.BatteryThreshold < minBattery || req.BatteryThreshold > maxBattery).
-		Messagef("Must be between %d and %d", minBattery, maxBattery)
+	validateConfigSettings(v, "", req.ConfigSettingsBody)
+	validateConfigChange(v, req.Author, req.Reason, req.ExpectedRevision)
+	return v.Error()
+}
+
+func validateGetDeviceConfigReq(req GetDeviceConfigRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	return v.Error()
+}
+
+func validateUpdateDeviceConfigReq(req UpdateDeviceConfigRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	v.Field("update_mask").When(len(req.UpdateMask) == 0).Message("Must not be empty")
+	for _, setting := range req.UpdateMask {
+		v.Field("update_mask").
+			When(!slices.Contains(configSettings, setting)).
+			Messagef("Unknown setting %q", setting)
+	}
+	// only the settings being updated are validated
+	validateConfigSettings(v, "settings.", req.Settings.masked(req.UpdateMask))
+	validateConfigChange(v, req.Author, req.Reason, req.ExpectedRevision)
+	return v.Error()
+}
+
+func validateListDeviceConfigVersionsReq(req ListDeviceConfigVersionsRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	v.Field("page.size").When(req.PageSize < 0).Message("Must be greater than 0")
+	return v.Error()
+}
+
+func validateRollbackDeviceConfigReq(req RollbackDeviceConfigRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	v.Field("version").When(req.Version <= 0).Message("Must be greater than 0")
+	validateConfigChange(v, req.Author, req.Reason, req.ExpectedRevision)
 	return v.Error()
 }
 
+// validateConfigChange validates the author and reason recorded in a config
+// version and the revision the change is conditional on.
+func validateConfigChange(v *http.RequestValidator, author string, reason string, expectedRevision *int64) {
+	v.Field("author").
+		When(len(author) > maxConfigAuthorLen).
+		Messagef("Must not exceed %d characters", maxConfigAuthorLen)
+	v.Field("reason").
+		When(len(reason) > maxConfigReasonLen).
+		Messagef("Must not exceed %d characters", maxConfigReasonLen)
+	v.Field("expected_revision").
+		When(expectedRevision != nil && *expectedRevision < 0).
+		Message("Must not be negative")
+}
+
+func validateConfigureDeviceGroupReq(req ConfigureDeviceGroupRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("group_id").When(isBlank(req.GroupID)).Message("Must not be blank")
+	validateConfigSettings(v, "", req.ConfigSettingsBody)
+	return v.Error()
+}
+
+func validateConfigureDefaultsReq(req ConfigureDefaultsRequest) error {
+	v := http.NewRequestValidator()
+	validateConfigSettings(v, "", req.ConfigSettingsBody)
+	return v.Error()
+}
+
+// validateConfigSettings validates the settings of a config level. Settings
+// that are omitted are not validated. The prefix is prepended to each field
+// name.
+func validateConfigSettings(v *http.RequestValidator, prefix string, b ConfigSettingsBody) {
+	if t := b.TemperatureThreshold; t != nil {
+		v.Field(prefix+"temperature_threshold").
+			When(*t < minTemperature || *t > maxTemperature).
+			Messagef("Must be between %.2f and %.2f", minTemperature, maxTemperature)
+	}
+	if t := b.BatteryThreshold; t != nil {
+		v.Field(prefix+"battery_threshold").
+			When(*t < minBattery || *t > maxBattery).
+			Messagef("Must be between %d and %d", minBattery, maxBattery)
+	}
+	v.Field(prefix+"expressions").
+		When(len(b.Expressions) > maxConfigExpressions).
+		Messagef("Must not contain more than %d items", maxConfigExpressions)
+	for i, e := range b.Expressions {
+		field := fmt.Sprintf("%sexpressions[%d].", prefix, i)
+		if len(e.Expr) > maxExpressionLen {
+			v.Field(field+"expr").When(true).Messagef("Must not exceed %d characters", maxExpressionLen)
+		} else if _, err := compileExpression(AlertExpression{Expr: e.Expr, Severity: e.Severity}); err != nil {
+			v.Field(field+"expr").When(true).Messagef("Must be a valid CEL expression: %s", err)
+		}
+		v.Field(field + "severity").
+			When(e.Severity.Proto() == iotv1.Severity_SEVERITY_UNSPECIFIED).
+			Message("Must be a valid severity")
+	}
+	if n := b.ConsecutiveBreaches; n != nil {
+		v.Field(prefix+"consecutive_breaches").
+			When(*n < 0 || *n > maxConsecutiveBreaches).
+			Messagef("Must be between 0 and %d", maxConsecutiveBreaches)
+	}
+	if h := b.Hysteresis; h != nil {
+		v.Field(prefix+"hysteresis").
+			When(math.IsNaN(*h) || *h < 0 || *h > maxTemperature).
+			Messagef("Must be between 0 and %.2f", maxTemperature)
+	}
+	var cooldownReasons []AlertReason
+	for i, c := range b.Cooldowns {
+		field := fmt.Sprintf("%scooldowns[%d].", prefix, i)
+		v.Field(field + "reason").
+			When(c.Reason.Proto() == iotv1.Alert_REASON_UNSPECIFIED).
+			Message("Must be a valid alert reason")
+		v.Field(field + "reason").
+			When(slices.Contains(cooldownReasons, c.Reason)).
+			Message("Must be unique")
+		v.Field(field+"window_seconds").
+			When(c.WindowSeconds <= 0 || c.WindowSeconds > int64(maxCooldownWindow/time.Second)).
+			Messagef("Must be between 1 and %d", int64(maxCooldownWindow/time.Second))
+		cooldownReasons = append(cooldownReasons, c.Reason)
+	}
+	if secs := b.ReportingIntervalSeconds; secs != nil {
+		v.Field(prefix+"reporting_interval_seconds").
+			When(*secs < 0 || *secs > int64(maxReportingInterval/time.Second)).
+			Messagef("Must be between 0 and %d", int64(maxReportingInterval/time.Second))
+	}
+}
+
 func validateRecordMetricReq(req RecordMetricRequest) error {
 	v := http.NewRequestValidator()
 	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
-	v.Field("timestamp").When(req.Timestamp.IsZero()).Message("Must not be empty")
-	v.Field("temperature").
-		When(req.Temperature < minTemperature || req.Temperature > maxTemperature).
-		Messagef("Must be between %.2f and %.2f", minTemperature, maxTemperature)
-	v.Field("battery").
-		When(req.Battery < minBattery || req.Battery > maxBattery).
-		Messagef("Must be between %d and %d", minBattery, maxBattery)
+	validateMetricReading(v, "", req.Timestamp, req.Temperature, req.Battery, req.Values)
 	return v.Error()
 }
 
+func validateRecordMetricsReq(req RecordMetricsRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	v.Field("metrics").When(len(req.Metrics) == 0).Message("Must not be empty")
+	v.Field("metrics").
+		When(len(req.Metrics) > maxRecordMetricsBatchSize).
+		Messagef("Must not contain more than %d items", maxRecordMetricsBatchSize)
+	for i, item := range req.Metrics {
+		prefix := fmt.Sprintf("metrics[%d].", i)
+		validateMetricReading(v, prefix, item.Timestamp, item.Temperature, item.Battery, item.Values)
+	}
+	return v.Error()
+}
+
+// validateMetricReading validates the fields of a single metric reading. The
+// prefix is prepended to each field name to identify items within a batch. The
+// deprecated temperature and battery fields are only validated if no values
+// are provided.
+func validateMetricReading(
+	v *http.RequestValidator,
+	prefix string,
+	timestamp time.Time,
+	temperature float64,
+	battery int32,
+	values []RecordMetricValue,
+) {
+	v.Field(prefix + "timestamp").When(timestamp.IsZero()).Message("Must not be empty")
+	if len(values) == 0 {
+		v.Field(prefix+"temperature").
+			When(temperature < minTemperature || temperature > maxTemperature).
+			Messagef("Must be between %.2f and %.2f", minTemperature, maxTemperature)
+		v.Field(prefix+"battery").
+			When(battery < minBattery || battery > maxBattery).
+			Messagef("Must be between %d and %d", minBattery, maxBattery)
+		return
+	}
+
+	v.Field(prefix+"values").
+		When(len(values) > maxMetricValues).
+		Messagef("Must not contain more than %d items", maxMetricValues)
+
+	seen := make(map[string]bool, len(values))
+	for i, mv := range values {
+		field := fmt.Sprintf("%svalues[%d].", prefix, i)
+		v.Field(field + "name").
+			When(!metricNameRegex.MatchString(mv.Name)).
+			Message("Must start with a lowercase letter and only contain lowercase letters, digits and underscores (max 64 characters)")
+		v.Field(field + "name").When(seen[mv.Name]).Message("Must be unique")
+		seen[mv.Name] = true
+		v.Field(field + "value").When(math.IsNaN(mv.Value) || math.IsInf(mv.Value, 0)).Message("Must be a finite number")
+		v.Field(field+"unit").When(len(mv.Unit) > maxMetricUnitLen).Messagef("Must not exceed %d characters", maxMetricUnitLen)
+
+		switch mv.Name {
+		case MetricTemperature:
+			v.Field(field+"value").
+				When(mv.Value < minTemperature || mv.Value > maxTemperature).
+				Messagef("Must be between %.2f and %.2f", minTemperature, maxTemperature)
+		case MetricBattery:
+			v.Field(field+"value").
+				When(mv.Value < minBattery || mv.Value > maxBattery).
+				Messagef("Must be between %d and %d", minBattery, maxBattery)
+		}
+	}
+}
+
 func validateGetDeviceAlertsReq(req GetDeviceAlertsRequest) error {
 	v := http.NewRequestValidator()
 	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
-	if req.TimeframeStart != nil {
-		v.Field("timeframe.start").When(req.TimeframeStart.IsZero()).Message("Must not be empty")
-		if req.TimeframeEnd != nil {
+	validateTimeframe(v, req.TimeframeStart, req.TimeframeEnd)
+	v.Field("page.size").When(req.PageSize < 0).Message("Must be greater than 0")
+	v.Field("state").
+		When(req.State != "" && req.State.Proto() == iotv1.Alert_STATE_UNSPECIFIED).
+		Message("Must be one of OPEN, ACKNOWLEDGED or RESOLVED")
+	for i, reason := range req.Reasons {
+		v.Field(fmt.Sprintf("reasons[%d]", i)).
+			When(reason.Proto() == iotv1.Alert_REASON_UNSPECIFIED).
+			Message("Must be a valid alert reason")
+	}
+	validateSortOrder(v, req.Order)
+	return v.Error()
+}
+
+func validateSearchAlertsReq(req SearchAlertsRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_ids").
+		When(len(req.DeviceIDs) > maxSearchDeviceIDs).
+		Messagef("Must not exceed %d device IDs", maxSearchDeviceIDs)
+	for i, id := range req.DeviceIDs {
+		v.Field(fmt.Sprintf("device_ids[%d]", i)).When(isBlank(id)).Message("Must not be blank")
+	}
+	if _, err := parseLabelSelector(req.LabelSelector); err != nil {
+		v.Field("label_selector").When(true).Messagef("Must be a valid label selector: %s", err)
+	}
+	for i, reason := range req.Reasons {
+		v.Field(fmt.Sprintf("reasons[%d]", i)).
+			When(reason.Proto() == iotv1.Alert_REASON_UNSPECIFIED).
+			Message("Must be a valid alert reason")
+	}
+	v.Field("severity").
+		When(req.Severity != "" && req.Severity.Proto() == iotv1.Severity_SEVERITY_UNSPECIFIED).
+		Message("Must be a valid severity")
+	v.Field("state").
+		When(req.State != "" && req.State.Proto() == iotv1.Alert_STATE_UNSPECIFIED).
+		Message("Must be one of OPEN, ACKNOWLEDGED or RESOLVED")
+	validateTimeframe(v, req.TimeframeStart, req.TimeframeEnd)
+	v.Field("page.size").When(req.PageSize < 0).Message("Must be greater than 0")
+	return v.Error()
+}
+
+func validateGetDeviceMetricsReq(req GetDeviceMetricsRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	validateTimeframe(v, req.TimeframeStart, req.TimeframeEnd)
+	v.Field("page.size").When(req.PageSize < 0).Message("Must be greater than 0")
+	validateSortOrder(v, req.Order)
+	return v.Error()
+}
+
+func validateSortOrder(v *http.RequestValidator, order SortOrder) {
+	v.Field("order").
+		When(order != "" && order.Proto() == iotv1.SortOrder_SORT_ORDER_UNSPECIFIED).
+		Message("Must be one of asc or desc")
+}
+
+func validateGetDeviceMetricAggregatesReq(req GetDeviceMetricAggregatesRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	v.Field("timeframe.start").When(req.TimeframeStart == nil).Message("Must be set")
+	v.Field("timeframe.end").When(req.TimeframeEnd == nil).Message("Must be set")
+	validateTimeframe(v, req.TimeframeStart, req.TimeframeEnd)
+	width := req.BucketWidth.Duration()
+	v.Field("bucket_width").When(width == 0).Message("Must be one of 1m, 5m, 1h or 1d")
+	if width != 0 && req.TimeframeStart != nil && req.TimeframeEnd != nil {
+		buckets := bucketStart(*req.TimeframeEnd, width).Sub(bucketStart(*req.TimeframeStart, width))/width + 1
+		v.Field("timeframe").
+			When(buckets > maxAggregateBuckets).
+			Messagef("Must not span more than %d buckets", maxAggregateBuckets)
+	}
+	return v.Error()
+}
+
+func validateGetAlertStatsReq(req GetAlertStatsRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("timeframe.start").When(req.TimeframeStart == nil).Message("Must be set")
+	v.Field("timeframe.end").When(req.TimeframeEnd == nil).Message("Must be set")
+	validateTimeframe(v, req.TimeframeStart, req.TimeframeEnd)
+	width := req.BucketWidth.Duration()
+	v.Field("bucket_width").When(width == 0).Message("Must be one of 1m, 5m, 1h or 1d")
+	if width != 0 && req.TimeframeStart != nil && req.TimeframeEnd != nil {
+		buckets := bucketStart(*req.TimeframeEnd, width).Sub(bucketStart(*req.TimeframeStart, width))/width + 1
+		v.Field("timeframe").
+			When(buckets > maxAggregateBuckets).
+			Messagef("Must not span more than %d buckets", maxAggregateBuckets)
+	}
+	v.Field("top_n").When(req.TopN < 0 || req.TopN > maxTopN).Messagef("Must be between 0 and %d", maxTopN)
+	return v.Error()
+}
+
+func validateWatchDeviceAlertsReq(req WatchDeviceAlertsRequest) error {
+	v := http.NewRequestValidator()
+	for i, reason := range req.Reasons {
+		v.Field(fmt.Sprintf("reasons[%d]", i)).
+			When(reason.Proto() == iotv1.Alert_REASON_UNSPECIFIED).
+			Message("Must be a valid alert reason")
+	}
+	return v.Error()
+}
+
+func validateCreateAlertRuleReq(req CreateAlertRuleRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	validateAlertRule(v, req.Metric, req.Operator, req.Threshold, req.ThresholdHigh, req.Severity, req.WindowSeconds)
+	return v.Error()
+}
+
+func validateUpdateAlertRuleReq(req UpdateAlertRuleRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	v.Field("rule_id").When(req.RuleID <= 0).Message("Must be greater than 0")
+	validateAlertRule(v, req.Metric, req.Operator, req.Threshold, req.ThresholdHigh, req.Severity, req.WindowSeconds)
+	return v.Error()
+}
+
+func validateListAlertRulesReq(req ListAlertRulesRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	return v.Error()
+}
+
+func validateAlertRuleIDReq(deviceID string, ruleID int64) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(deviceID)).Message("Must not be blank")
+	v.Field("rule_id").When(ruleID <= 0).Message("Must be greater than 0")
+	return v.Error()
+}
+
+func validateAcknowledgeAlertReq(req AcknowledgeAlertRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	v.Field("alert_id").When(req.AlertID <= 0).Message("Must be greater than 0")
+	v.Field("acknowledged_by").When(isBlank(req.AcknowledgedBy)).Message("Must not be blank")
+	v.Field("acknowledged_by").
+		When(len(req.AcknowledgedBy) > maxAcknowledgedByLen).
+		Messagef("Must not exceed %d characters", maxAcknowledgedByLen)
+	v.Field("comment").
+		When(len(req.Comment) > maxAckCommentLen).
+		Messagef("Must not exceed %d characters", maxAckCommentLen)
+	return v.Error()
+}
+
+func validateResolveAlertReq(req ResolveAlertRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	v.Field("alert_id").When(req.AlertID <= 0).Message("Must be greater than 0")
+	return v.Error()
+}
+
+func validateCreateDeviceReq(req CreateDeviceRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	v.Field("device_id").
+		When(len(req.DeviceID) > maxDeviceIDLen).
+		Messagef("Must not exceed %d characters", maxDeviceIDLen)
+	validateDeviceMetadata(v, req.DisplayName, req.Model, req.FirmwareVersion, req.Location, req.Labels)
+	return v.Error()
+}
+
+func validateGetDeviceReq(req GetDeviceRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	return v.Error()
+}
+
+func validateDeleteDeviceReq(req DeleteDeviceRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	return v.Error()
+}
+
+func validateGetOperationReq(req GetOperationRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("operation_id").When(req.OperationID <= 0).Message("Must be greater than 0")
+	return v.Error()
+}
+
+func validateUpdateDeviceReq(req UpdateDeviceRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	validateDeviceMetadata(v, req.DisplayName, req.Model, req.FirmwareVersion, req.Location, req.Labels)
+	return v.Error()
+}
+
+func validateListDevicesReq(req ListDevicesRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("page.size").When(req.PageSize < 0).Message("Must be greater than 0")
+	if _, err := parseLabelSelector(req.LabelSelector); err != nil {
+		v.Field("label_selector").When(true).Messagef("Must be a valid label selector: %s", err)
+	}
+	return v.Error()
+}
+
+func validateGetFleetSnapshotReq(req GetFleetSnapshotRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("page.size").When(req.PageSize < 0).Message("Must be greater than 0")
+	if _, err := parseLabelSelector(req.LabelSelector); err != nil {
+		v.Field("label_selector").When(true).Messagef("Must be a valid label selector: %s", err)
+	}
+	return v.Error()
+}
+
+func validateDeviceMetadata(
+	v *http.RequestValidator,
+	displayName string,
+	model string,
+	firmwareVersion string,
+	location string,
+	labels map[string]string,
+) {
+	for field, value := range map[string]string{
+		"display_name":     displayName,
+		"model":            model,
+		"firmware_version": firmwareVersion,
+		"location":         location,
+	} {
+		v.Field(field).When(len(value) > maxDeviceFieldLen).Messagef("Must not exceed %d characters", maxDeviceFieldLen)
+	}
+	v.Field("labels").
+		When(len(labels) > maxDeviceLabels).
+		Messagef("Must not contain more than %d labels", maxDeviceLabels)
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		v.Field("labels").
+			When(!labelKeyRegex.MatchString(key)).
+			Messagef("Key %q must be at most 63 characters, start and end with a letter or digit and only contain letters, digits, '.', '_', '-' and '/'", key)
+		v.Field("labels." + key).
+			When(labelKeyRegex.MatchString(key) && !labelValueRegex.MatchString(labels[key])).
+			Message("Must be at most 63 characters, start and end with a letter or digit and only contain letters, digits, '.', '_' and '-'")
+	}
+}
+
+func validateAlertRule(
+	v *http.RequestValidator,
+	metric string,
+	operator RuleOperator,
+	threshold float64,
+	thresholdHigh float64,
+	severity AlertSeverity,
+	windowSeconds int64,
+) {
+	v.Field("metric").
+		When(!metricNameRegex.MatchString(metric)).
+		Message("Must start with a lowercase letter and only contain lowercase letters, digits and underscores (max 64 characters)")
+	v.Field("operator").
+		When(operator.Proto() == iotv1.AlertRule_OPERATOR_UNSPECIFIED).
+		Message("Must be a valid rule operator")
+	v.Field("threshold").When(math.IsNaN(threshold) || math.IsInf(threshold, 0)).Message("Must be a finite number")
+	v.Field("threshold_high").When(math.IsNaN(thresholdHigh) || math.IsInf(thresholdHigh, 0)).Message("Must be a finite number")
+	if operator.isRange() {
+		v.Field("threshold_high").When(thresholdHigh < threshold).Message("Must not be less than threshold")
+	}
+	if operator.isRateOfChange() {
+		v.Field("threshold").When(threshold <= 0).Message("Must be greater than 0")
+		v.Field("window_seconds").
+			When(windowSeconds < 1 || windowSeconds > int64(maxRuleWindow/time.Second)).
+			Messagef("Must be between 1 and %d", int64(maxRuleWindow/time.Second))
+	} else {
+		v.Field("window_seconds").When(windowSeconds != 0).Message("Must only be set for RISE and DROP operators")
+	}
+	v.Field("severity").
+		When(severity.Proto() == iotv1.Severity_SEVERITY_UNSPECIFIED).
+		Message("Must be a valid severity")
+}
+
+func validateTimeframe(v *http.RequestValidator, start *time.Time, end *time.Time) {
+	if start != nil {
+		v.Field("timeframe.start").When(start.IsZero()).Message("Must not be empty")
+		if end != nil {
 			v.Field("timeframe.start").
-				When(req.TimeframeStart.After(*req.TimeframeEnd)).
+				When(start.After(*end)).
 				Message("Must be before timeframe.end")
 		}
 	}
-	if req.TimeframeEnd != nil {
-		v.Field("timeframe.end").When(req.TimeframeEnd.IsZero()).Message("Must not be empty")
+	if end != nil {
+		v.Field("timeframe.end").When(end.IsZero()).Message("Must not be empty")
 	}
-	v.Field("page.size").When(req.PageSize < 0).Message("Must be greater than 0")
-	return v.Error()
 }
 
 func isBlank(s string) bool {
 	return strings.TrimSpace(s) == ""
 }
+
+func validateCreateDeviceGroupReq(req CreateDeviceGroupRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("group_id").When(isBlank(req.GroupID)).Message("Must not be blank")
+	v.Field("group_id").
+		When(len(req.GroupID) > maxDeviceIDLen).
+		Messagef("Must not exceed %d characters", maxDeviceIDLen)
+	v.Field("display_name").
+		When(len(req.DisplayName) > maxDeviceFieldLen).
+		Messagef("Must not exceed %d characters", maxDeviceFieldLen)
+	return v.Error()
+}
+
+func validateGetDeviceGroupReq(req GetDeviceGroupRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("group_id").When(isBlank(req.GroupID)).Message("Must not be blank")
+	return v.Error()
+}
+
+func validateGetEffectiveDeviceConfigReq(req GetEffectiveDeviceConfigRequest) error {
+	v := http.NewRequestValidator()
+	v.Field("device_id").When(isBlank(req.DeviceID)).Message("Must not be blank")
+	return v.Error()
+}
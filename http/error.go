@@ -89,6 +89,111 @@ func (e *BadRequestError) ConnectError() *connect.Error {
 	return cErr
 }
 
+// NotFoundError represents a requested resource that does not exist for both
+// REST and Connect handlers.
+type NotFoundError struct {
+	Message string
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Message == "" {
+		return "not found"
+	}
+	return e.Message
+}
+
+// RestError converts a NotFoundError into a RestError.
+func (e *NotFoundError) RestError() RestError {
+	return RestError{
+		Code:    http.StatusNotFound,
+		Message: e.Error(),
+	}
+}
+
+// ConnectError converts a NotFoundError into a connect.Error.
+func (e *NotFoundError) ConnectError() *connect.Error {
+	return connect.NewError(connect.CodeNotFound, errors.New(e.Error()))
+}
+
+// ConflictError represents a request that conflicts with the current state of
+// a resource for both REST and Connect handlers.
+type ConflictError struct {
+	Message string
+}
+
+func (e *ConflictError) Error() string {
+	if e.Message == "" {
+		return "conflict"
+	}
+	return e.Message
+}
+
+// RestError converts a ConflictError into a RestError.
+func (e *ConflictError) RestError() RestError {
+	return RestError{
+		Code:    http.StatusConflict,
+		Message: e.Error(),
+	}
+}
+
+// ConnectError converts a ConflictError into a connect.Error.
+func (e *ConflictError) ConnectError() *connect.Error {
+	return connect.NewError(connect.CodeFailedPrecondition, errors.New(e.Error()))
+}
+
+// AlreadyExistsError represents a request to create a resource that already
+// exists for both REST and Connect handlers.
+type AlreadyExistsError struct {
+	Message string
+}
+
+func (e *AlreadyExistsError) Error() string {
+	if e.Message == "" {
+		return "already exists"
+	}
+	return e.Message
+}
+
+// RestError converts an AlreadyExistsError into a RestError.
+func (e *AlreadyExistsError) RestError() RestError {
+	return RestError{
+		Code:    http.StatusConflict,
+		Message: e.Error(),
+	}
+}
+
+// ConnectError converts an AlreadyExistsError into a connect.Error.
+func (e *AlreadyExistsError) ConnectError() *connect.Error {
+	return connect.NewError(connect.CodeAlreadyExists, errors.New(e.Error()))
+}
+
+// PreconditionFailedError represents a conditional request whose precondition,
+// such as an expected revision, does not match the current state of a resource
+// for both REST and Connect handlers.
+type PreconditionFailedError struct {
+	Message string
+}
+
+func (e *PreconditionFailedError) Error() string {
+	if e.Message == "" {
+		return "precondition failed"
+	}
+	return e.Message
+}
+
+// RestError converts a PreconditionFailedError into a RestError.
+func (e *PreconditionFailedError) RestError() RestError {
+	return RestError{
+		Code:    http.StatusPreconditionFailed,
+		Message: e.Error(),
+	}
+}
+
+// ConnectError converts a PreconditionFailedError into a connect.Error.
+func (e *PreconditionFailedError) ConnectError() *connect.Error {
+	return connect.NewError(connect.CodeFailedPrecondition, errors.New(e.Error()))
+}
+
 // NewEchoErrorMiddleware returns an Echo middleware that transforms errors
 // into structured responses.
 func NewEchoErrorMiddleware() echo.MiddlewareFunc {
@@ -117,6 +222,26 @@ func NewEchoErrorMiddleware() echo.MiddlewareFunc {
 					return brErr.RestError()
 				}
 
+				var nfErr *NotFoundError
+				if errors.As(err, &nfErr) {
+					return nfErr.RestError()
+				}
+
+				var cfErr *ConflictError
+				if errors.As(err, &cfErr) {
+					return cfErr.RestError()
+				}
+
+				var aeErr *AlreadyExistsError
+				if errors.As(err, &aeErr) {
+					return aeErr.RestError()
+				}
+
+				var pfErr *PreconditionFailedError
+				if errors.As(err, &pfErr) {
+					return pfErr.RestError()
+				}
+
 				return RestError{
 					Code:    http.StatusInternalServerError,
 					Message: http.StatusText(http.StatusInternalServerError),
@@ -129,26 +254,67 @@ func NewEchoErrorMiddleware() echo.MiddlewareFunc {
 }
 
 // NewConnectErrorInterceptor returns a Connect interceptor that transforms
-// errors into structured connect.Errors.
+// errors into structured connect.Errors for both unary and streaming RPCs.
 func NewConnectErrorInterceptor() connect.Interceptor {
-	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
-		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
-			res, err := next(ctx, req)
-			if err == nil {
-				return res, nil
-			}
+	return &connectErrorInterceptor{}
+}
 
-			var cerr *connect.Error
-			if errors.As(err, &cerr) {
-				return nil, cerr
-			}
+type connectErrorInterceptor struct{}
 
-			var brErr *BadRequestError
-			if errors.As(err, &brErr) {
-				return nil, brErr.ConnectError()
-			}
+func (i *connectErrorInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		res, err := next(ctx, req)
+		if err != nil {
+			return nil, connectError(err)
+		}
+		return res, nil
+	}
+}
+
+func (i *connectErrorInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
 
-			return nil, connect.NewError(connect.CodeInternal, errors.New("internal server error"))
+func (i *connectErrorInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := next(ctx, conn); err != nil {
+			return connectError(err)
 		}
-	})
+		return nil
+	}
+}
+
+// connectError converts a handler error into a structured connect.Error.
+func connectError(err error) *connect.Error {
+	var cerr *connect.Error
+	if errors.As(err, &cerr) {
+		return cerr
+	}
+
+	var brErr *BadRequestError
+	if errors.As(err, &brErr) {
+		return brErr.ConnectError()
+	}
+
+	var nfErr *NotFoundError
+	if errors.As(err, &nfErr) {
+		return nfErr.ConnectError()
+	}
+
+	var cfErr *ConflictError
+	if errors.As(err, &cfErr) {
+		return cfErr.ConnectError()
+	}
+
+	var aeErr *AlreadyExistsError
+	if errors.As(err, &aeErr) {
+		return aeErr.ConnectError()
+	}
+
+	var pfErr *PreconditionFailedError
+	if errors.As(err, &pfErr) {
+		return pfErr.ConnectError()
+	}
+
+	return connect.NewError(connect.CodeInternal, errors.New("internal server error"))
 }
@@ -52,41 +52,65 @@ func NewEchoLogMiddleware(logger Logger) echo.MiddlewareFunc {
 	})
 }
 
-// NewConnectLogInterceptor returns a Connect interceptor that logs handler requests.
+// NewConnectLogInterceptor returns a Connect interceptor that logs handler
+// requests for both unary and streaming RPCs.
 func NewConnectLogInterceptor(logger Logger) connect.Interceptor {
-	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
-		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
-			keyVals := []any{
-				"protocol", "connect",
-			}
-			procParts := strings.Split(req.Spec().Procedure, "/")
-			if len(procParts) == 3 {
-				keyVals = append(keyVals,
-					"connect.service", strings.TrimSuffix(procParts[1], "/"),
-					"connect.method", procParts[2],
-				)
-			} else {
-				keyVals = append(keyVals, "connect.procedure", req.Spec().Procedure)
-			}
+	return &connectLogInterceptor{logger: logger}
+}
 
-			start := time.Now()
-			keyVals = append(keyVals, "start_time", start.Format(time.RFC3339))
+type connectLogInterceptor struct {
+	logger Logger
+}
 
-			level := slog.LevelInfo
-			res, err := next(ctx, req)
-			if err != nil {
-				level = slog.LevelError
-				var cErr *connect.Error
-				if errors.As(err, &cErr) {
-					keyVals = append(keyVals, "error_code", fmt.Sprintf("(%d) %s", cErr.Code(), cErr.Code().String()))
-				}
-				keyVals = append(keyVals, "error", err)
-			}
+func (i *connectLogInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		start := time.Now()
+		res, err := next(ctx, req)
+		i.log(ctx, "unary rpc called", req.Spec(), start, err)
+		return res, err
+	}
+}
 
-			keyVals = append(keyVals, "duration", time.Since(start).String())
+func (i *connectLogInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *connectLogInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		start := time.Now()
+		err := next(ctx, conn)
+		i.log(ctx, "streaming rpc called", conn.Spec(), start, err)
+		return err
+	}
+}
+
+func (i *connectLogInterceptor) log(ctx context.Context, msg string, spec connect.Spec, start time.Time, err error) {
+	keyVals := []any{
+		"protocol", "connect",
+	}
+	procParts := strings.Split(spec.Procedure, "/")
+	if len(procParts) == 3 {
+		keyVals = append(keyVals,
+			"connect.service", strings.TrimSuffix(procParts[1], "/"),
+			"connect.method", procParts[2],
+		)
+	} else {
+		keyVals = append(keyVals, "connect.procedure", spec.Procedure)
+	}
 
-			logger.Log(ctx, level, "unary rpc called", keyVals...)
-			return res, err
+	keyVals = append(keyVals, "start_time", start.Format(time.RFC3339))
+
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelError
+		var cErr *connect.Error
+		if errors.As(err, &cErr) {
+			keyVals = append(keyVals, "error_code", fmt.Sprintf("(%d) %s", cErr.Code(), cErr.Code().String()))
 		}
-	})
+		keyVals = append(keyVals, "error", err)
+	}
+
+	keyVals = append(keyVals, "duration", time.Since(start).String())
+
+	i.logger.Log(ctx, level, msg, keyVals...)
 }
@@ -37,25 +37,73 @@ func NewEchoRateLimiterMiddleware(limiter RateLimiter, keyGetter RateLimitEchoKe
 	}
 }
 
-// RateLimitConnectKeyGetter extracts a rate limit key from a Connect request.
-type RateLimitConnectKeyGetter func(req connect.AnyRequest) (string, bool)
+// RateLimitConnectKeyGetter extracts a rate limit key from a Connect request
+// message. For streaming RPCs it is called for every received message.
+type RateLimitConnectKeyGetter func(msg any) (string, bool)
 
 // NewConnectRateLimitInterceptor returns a Connect interceptor that applies
-// per-request rate limiting using the provided RateLimiter and key getter.
+// rate limiting using the provided RateLimiter and key getter. Unary RPCs are
+// limited per request and streaming RPCs are limited per received message.
 func NewConnectRateLimitInterceptor(limiter RateLimiter, keyGetter RateLimitConnectKeyGetter) connect.Interceptor {
-	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
-		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
-			key, ok := keyGetter(req)
-			if !ok {
-				key = req.Peer().Addr // default to client IP
-			}
-			if err := limiter.Wait(ctx, key); err != nil {
-				if errors.Is(err, context.Canceled) {
-					return nil, connect.NewError(connect.CodeCanceled, errors.New("canceled"))
-				}
-				return nil, connect.NewError(connect.CodeResourceExhausted, errors.New("resource exhausted"))
-			}
-			return next(ctx, req)
+	return &connectRateLimitInterceptor{
+		limiter:   limiter,
+		keyGetter: keyGetter,
+	}
+}
+
+type connectRateLimitInterceptor struct {
+	limiter   RateLimiter
+	keyGetter RateLimitConnectKeyGetter
+}
+
+func (i *connectRateLimitInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := i.wait(ctx, req.Any(), req.Peer()); err != nil {
+			return nil, err
 		}
-	})
+		return next(ctx, req)
+	}
+}
+
+func (i *connectRateLimitInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *connectRateLimitInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(ctx, &rateLimitStreamingHandlerConn{
+			StreamingHandlerConn: conn,
+			ctx:                  ctx,
+			interceptor:          i,
+		})
+	}
+}
+
+func (i *connectRateLimitInterceptor) wait(ctx context.Context, msg any, peer connect.Peer) error {
+	key, ok := i.keyGetter(msg)
+	if !ok {
+		key = peer.Addr // default to client IP
+	}
+	if err := i.limiter.Wait(ctx, key); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return connect.NewError(connect.CodeCanceled, errors.New("canceled"))
+		}
+		return connect.NewError(connect.CodeResourceExhausted, errors.New("resource exhausted"))
+	}
+	return nil
+}
+
+// rateLimitStreamingHandlerConn applies rate limiting to each message received
+// on a stream.
+type rateLimitStreamingHandlerConn struct {
+	connect.StreamingHandlerConn
+	ctx         context.Context
+	interceptor *connectRateLimitInterceptor
+}
+
+func (c *rateLimitStreamingHandlerConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	return c.interceptor.wait(c.ctx, msg, c.Peer())
 }
@@ -20,6 +20,7 @@ type Server struct {
 	echo            *echo.Echo
 	httpSrv         *http.Server
 	connectServices []string
+	onShutdown      []func()
 }
 
 // NewServer returns a new Server.
@@ -53,6 +54,13 @@ func (s *Server) RegisterConnect(path string, handler http.Handler) {
 	s.connectServices = append(s.connectServices, path)
 }
 
+// OnShutdown registers a function to call when the server begins shutting
+// down, such as to end long-lived streams. Functions are called in the order
+// they were registered, and Stop waits for them to return.
+func (s *Server) OnShutdown(f func()) {
+	s.onShutdown = append(s.onShutdown, f)
+}
+
 // Serve starts the HTTP server.
 func (s *Server) Serve() error {
 	var connectSvcNames []string
@@ -76,9 +84,23 @@ func (s *Server) Serve() error {
 	return s.httpSrv.ListenAndServe()
 }
 
-// Stop gracefully shuts down the HTTP server.
+// Stop gracefully shuts down the HTTP server, calling the functions
+// registered with OnShutdown, and waits for them to return.
 func (s *Server) Stop(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	return s.httpSrv.Shutdown(ctx)
+
+	// the functions are called alongside the shutdown since it waits for the
+	// streams that they end
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, f := range s.onShutdown {
+			f()
+		}
+	}()
+
+	err := s.httpSrv.Shutdown(ctx)
+	<-done
+	return err
 }
@@ -76,6 +76,13 @@ func run(c *cli.Context) error {
 		return fmt.Errorf("open sqlite: %w", err)
 	}
 	logger.Info("opened sqlite database connection")
+	// closed after the server is stopped, since stopping it waits for
+	// background work that uses the database
+	defer func() {
+		if err := db.Close(); err != nil {
+			logger.Error("failed to close sqlite database", "error", err)
+		}
+	}()
 
 	if err = sqlite.Migrate(db, migrations.FS()); err != nil {
 		return fmt.Errorf("migrate sqlite: %w", err)
@@ -96,11 +103,25 @@ func run(c *cli.Context) error {
 		logger.Info("device rate limiter enabled")
 	}
 
-	svc := device.NewService(repo, logger)
+	pt := cfg.PageTokens
+	if pt.Key == "" {
+		logger.Warn("page token key not configured, page tokens will not be valid across restarts")
+	}
+	svc := device.NewService(repo, logger, device.WithPageTokens([]byte(pt.Key), time.Duration(pt.TTLSeconds)*time.Second))
+	if err = svc.ResumeOperations(ctx); err != nil {
+		return fmt.Errorf("resume operations: %w", err)
+	}
 
 	hostPort := ":" + strconv.Itoa(cfg.Port)
 	srv := http.NewServer(hostPort)
 
+	od := cfg.OfflineDetector
+	offlineDetector := device.NewOfflineDetector(svc, time.Duration(od.IntervalSeconds)*time.Second, od.GraceFactor)
+	offlineDetector.Start()
+	// the detector is stopped before the service it triggers alerts with
+	srv.OnShutdown(offlineDetector.Stop)
+	srv.OnShutdown(svc.Close)
+
 	restHandler := device.NewEchoHandler(svc)
 	srv.RegisterEcho(restHandler, middleware...)
 
@@ -36,19 +36,177 @@ const (
 	// DeviceServiceRecordMetricProcedure is the fully-qualified name of the DeviceService's
 	// RecordMetric RPC.
 	DeviceServiceRecordMetricProcedure = "/iot.v1.DeviceService/RecordMetric"
+	// DeviceServiceRecordMetricsProcedure is the fully-qualified name of the DeviceService's
+	// RecordMetrics RPC.
+	DeviceServiceRecordMetricsProcedure = "/iot.v1.DeviceService/RecordMetrics"
+	// DeviceServiceStreamMetricsProcedure is the fully-qualified name of the DeviceService's
+	// StreamMetrics RPC.
+	DeviceServiceStreamMetricsProcedure = "/iot.v1.DeviceService/StreamMetrics"
 	// DeviceServiceConfigureDeviceProcedure is the fully-qualified name of the DeviceService's
 	// ConfigureDevice RPC.
 	DeviceServiceConfigureDeviceProcedure = "/iot.v1.DeviceService/ConfigureDevice"
+	// DeviceServiceGetDeviceConfigProcedure is the fully-qualified name of the DeviceService's
+	// GetDeviceConfig RPC.
+	DeviceServiceGetDeviceConfigProcedure = "/iot.v1.DeviceService/GetDeviceConfig"
+	// DeviceServiceUpdateDeviceConfigProcedure is the fully-qualified name of the DeviceService's
+	// UpdateDeviceConfig RPC.
+	DeviceServiceUpdateDeviceConfigProcedure = "/iot.v1.DeviceService/UpdateDeviceConfig"
+	// DeviceServiceGetDeviceMetricsProcedure is the fully-qualified name of the DeviceService's
+	// GetDeviceMetrics RPC.
+	DeviceServiceGetDeviceMetricsProcedure = "/iot.v1.DeviceService/GetDeviceMetrics"
+	// DeviceServiceGetDeviceMetricAggregatesProcedure is the fully-qualified name of the
+	// DeviceService's GetDeviceMetricAggregates RPC.
+	DeviceServiceGetDeviceMetricAggregatesProcedure = "/iot.v1.DeviceService/GetDeviceMetricAggregates"
 	// DeviceServiceGetDeviceAlertsProcedure is the fully-qualified name of the DeviceService's
 	// GetDeviceAlerts RPC.
 	DeviceServiceGetDeviceAlertsProcedure = "/iot.v1.DeviceService/GetDeviceAlerts"
+	// DeviceServiceSearchAlertsProcedure is the fully-qualified name of the DeviceService's
+	// SearchAlerts RPC.
+	DeviceServiceSearchAlertsProcedure = "/iot.v1.DeviceService/SearchAlerts"
+	// DeviceServiceGetAlertStatsProcedure is the fully-qualified name of the DeviceService's
+	// GetAlertStats RPC.
+	DeviceServiceGetAlertStatsProcedure = "/iot.v1.DeviceService/GetAlertStats"
+	// DeviceServiceWatchDeviceAlertsProcedure is the fully-qualified name of the DeviceService's
+	// WatchDeviceAlerts RPC.
+	DeviceServiceWatchDeviceAlertsProcedure = "/iot.v1.DeviceService/WatchDeviceAlerts"
+	// DeviceServiceCreateAlertRuleProcedure is the fully-qualified name of the DeviceService's
+	// CreateAlertRule RPC.
+	DeviceServiceCreateAlertRuleProcedure = "/iot.v1.DeviceService/CreateAlertRule"
+	// DeviceServiceGetAlertRuleProcedure is the fully-qualified name of the DeviceService's
+	// GetAlertRule RPC.
+	DeviceServiceGetAlertRuleProcedure = "/iot.v1.DeviceService/GetAlertRule"
+	// DeviceServiceListAlertRulesProcedure is the fully-qualified name of the DeviceService's
+	// ListAlertRules RPC.
+	DeviceServiceListAlertRulesProcedure = "/iot.v1.DeviceService/ListAlertRules"
+	// DeviceServiceUpdateAlertRuleProcedure is the fully-qualified name of the DeviceService's
+	// UpdateAlertRule RPC.
+	DeviceServiceUpdateAlertRuleProcedure = "/iot.v1.DeviceService/UpdateAlertRule"
+	// DeviceServiceDeleteAlertRuleProcedure is the fully-qualified name of the DeviceService's
+	// DeleteAlertRule RPC.
+	DeviceServiceDeleteAlertRuleProcedure = "/iot.v1.DeviceService/DeleteAlertRule"
+	// DeviceServiceAcknowledgeAlertProcedure is the fully-qualified name of the DeviceService's
+	// AcknowledgeAlert RPC.
+	DeviceServiceAcknowledgeAlertProcedure = "/iot.v1.DeviceService/AcknowledgeAlert"
+	// DeviceServiceResolveAlertProcedure is the fully-qualified name of the DeviceService's
+	// ResolveAlert RPC.
+	DeviceServiceResolveAlertProcedure = "/iot.v1.DeviceService/ResolveAlert"
+	// DeviceServiceCreateDeviceProcedure is the fully-qualified name of the DeviceService's
+	// CreateDevice RPC.
+	DeviceServiceCreateDeviceProcedure = "/iot.v1.DeviceService/CreateDevice"
+	// DeviceServiceGetDeviceProcedure is the fully-qualified name of the DeviceService's GetDevice RPC.
+	DeviceServiceGetDeviceProcedure = "/iot.v1.DeviceService/GetDevice"
+	// DeviceServiceUpdateDeviceProcedure is the fully-qualified name of the DeviceService's
+	// UpdateDevice RPC.
+	DeviceServiceUpdateDeviceProcedure = "/iot.v1.DeviceService/UpdateDevice"
+	// DeviceServiceListDevicesProcedure is the fully-qualified name of the DeviceService's ListDevices
+	// RPC.
+	DeviceServiceListDevicesProcedure = "/iot.v1.DeviceService/ListDevices"
+	// DeviceServiceGetFleetSnapshotProcedure is the fully-qualified name of the DeviceService's
+	// GetFleetSnapshot RPC.
+	DeviceServiceGetFleetSnapshotProcedure = "/iot.v1.DeviceService/GetFleetSnapshot"
+	// DeviceServiceDeleteDeviceProcedure is the fully-qualified name of the DeviceService's
+	// DeleteDevice RPC.
+	DeviceServiceDeleteDeviceProcedure = "/iot.v1.DeviceService/DeleteDevice"
+	// DeviceServiceGetOperationProcedure is the fully-qualified name of the DeviceService's
+	// GetOperation RPC.
+	DeviceServiceGetOperationProcedure = "/iot.v1.DeviceService/GetOperation"
+	// DeviceServiceCreateDeviceGroupProcedure is the fully-qualified name of the DeviceService's
+	// CreateDeviceGroup RPC.
+	DeviceServiceCreateDeviceGroupProcedure = "/iot.v1.DeviceService/CreateDeviceGroup"
+	// DeviceServiceGetDeviceGroupProcedure is the fully-qualified name of the DeviceService's
+	// GetDeviceGroup RPC.
+	DeviceServiceGetDeviceGroupProcedure = "/iot.v1.DeviceService/GetDeviceGroup"
+	// DeviceServiceListDeviceGroupsProcedure is the fully-qualified name of the DeviceService's
+	// ListDeviceGroups RPC.
+	DeviceServiceListDeviceGroupsProcedure = "/iot.v1.DeviceService/ListDeviceGroups"
+	// DeviceServiceConfigureDeviceGroupProcedure is the fully-qualified name of the DeviceService's
+	// ConfigureDeviceGroup RPC.
+	DeviceServiceConfigureDeviceGroupProcedure = "/iot.v1.DeviceService/ConfigureDeviceGroup"
+	// DeviceServiceConfigureDefaultsProcedure is the fully-qualified name of the DeviceService's
+	// ConfigureDefaults RPC.
+	DeviceServiceConfigureDefaultsProcedure = "/iot.v1.DeviceService/ConfigureDefaults"
+	// DeviceServiceGetEffectiveDeviceConfigProcedure is the fully-qualified name of the DeviceService's
+	// GetEffectiveDeviceConfig RPC.
+	DeviceServiceGetEffectiveDeviceConfigProcedure = "/iot.v1.DeviceService/GetEffectiveDeviceConfig"
+	// DeviceServiceListDeviceConfigVersionsProcedure is the fully-qualified name of the DeviceService's
+	// ListDeviceConfigVersions RPC.
+	DeviceServiceListDeviceConfigVersionsProcedure = "/iot.v1.DeviceService/ListDeviceConfigVersions"
+	// DeviceServiceRollbackDeviceConfigProcedure is the fully-qualified name of the DeviceService's
+	// RollbackDeviceConfig RPC.
+	DeviceServiceRollbackDeviceConfigProcedure = "/iot.v1.DeviceService/RollbackDeviceConfig"
 )
 
 // DeviceServiceClient is a client for the iot.v1.DeviceService service.
 type DeviceServiceClient interface {
 	RecordMetric(context.Context, *connect.Request[v1.RecordMetricRequest]) (*connect.Response[v1.RecordMetricResponse], error)
+	RecordMetrics(context.Context, *connect.Request[v1.RecordMetricsRequest]) (*connect.Response[v1.RecordMetricsResponse], error)
+	// StreamMetrics records metrics sent on a long-lived stream. Each metric is
+	// processed as it is received and acknowledged periodically.
+	StreamMetrics(context.Context) *connect.BidiStreamForClient[v1.StreamMetricsRequest, v1.StreamMetricsResponse]
 	ConfigureDevice(context.Context, *connect.Request[v1.ConfigureDeviceRequest]) (*connect.Response[v1.ConfigureDeviceResponse], error)
+	// GetDeviceConfig returns the current version of the settings set for a
+	// device, excluding settings inherited from its group or the defaults.
+	GetDeviceConfig(context.Context, *connect.Request[v1.GetDeviceConfigRequest]) (*connect.Response[v1.GetDeviceConfigResponse], error)
+	// UpdateDeviceConfig updates the settings of a device in the update mask,
+	// keeping all other settings, and stores the result as a new version.
+	UpdateDeviceConfig(context.Context, *connect.Request[v1.UpdateDeviceConfigRequest]) (*connect.Response[v1.UpdateDeviceConfigResponse], error)
+	GetDeviceMetrics(context.Context, *connect.Request[v1.GetDeviceMetricsRequest]) (*connect.Response[v1.GetDeviceMetricsResponse], error)
+	// GetDeviceMetricAggregates downsamples the metrics of a device over a
+	// timeframe, returning the min, max, avg, count, first and last value of
+	// each metric per bucket.
+	GetDeviceMetricAggregates(context.Context, *connect.Request[v1.GetDeviceMetricAggregatesRequest]) (*connect.Response[v1.GetDeviceMetricAggregatesResponse], error)
 	GetDeviceAlerts(context.Context, *connect.Request[v1.GetDeviceAlertsRequest]) (*connect.Response[v1.GetDeviceAlertsResponse], error)
+	// SearchAlerts returns the alerts of all devices matching the filters, most
+	// recent first.
+	SearchAlerts(context.Context, *connect.Request[v1.SearchAlertsRequest]) (*connect.Response[v1.SearchAlertsResponse], error)
+	// GetAlertStats counts the alerts of all devices triggered over a timeframe
+	// by reason, by device and by bucket, along with the noisiest devices.
+	GetAlertStats(context.Context, *connect.Request[v1.GetAlertStatsRequest]) (*connect.Response[v1.GetAlertStatsResponse], error)
+	// WatchDeviceAlerts streams alerts as they are triggered. Alerts triggered
+	// after a cursor from a previous response are replayed before new alerts.
+	WatchDeviceAlerts(context.Context, *connect.Request[v1.WatchDeviceAlertsRequest]) (*connect.ServerStreamForClient[v1.WatchDeviceAlertsResponse], error)
+	CreateAlertRule(context.Context, *connect.Request[v1.CreateAlertRuleRequest]) (*connect.Response[v1.CreateAlertRuleResponse], error)
+	GetAlertRule(context.Context, *connect.Request[v1.GetAlertRuleRequest]) (*connect.Response[v1.GetAlertRuleResponse], error)
+	ListAlertRules(context.Context, *connect.Request[v1.ListAlertRulesRequest]) (*connect.Response[v1.ListAlertRulesResponse], error)
+	UpdateAlertRule(context.Context, *connect.Request[v1.UpdateAlertRuleRequest]) (*connect.Response[v1.UpdateAlertRuleResponse], error)
+	DeleteAlertRule(context.Context, *connect.Request[v1.DeleteAlertRuleRequest]) (*connect.Response[v1.DeleteAlertRuleResponse], error)
+	// AcknowledgeAlert records that an operator is handling an open alert.
+	AcknowledgeAlert(context.Context, *connect.Request[v1.AcknowledgeAlertRequest]) (*connect.Response[v1.AcknowledgeAlertResponse], error)
+	// ResolveAlert resolves an open or acknowledged alert. Alerts are also
+	// resolved automatically once readings return within the threshold.
+	ResolveAlert(context.Context, *connect.Request[v1.ResolveAlertRequest]) (*connect.Response[v1.ResolveAlertResponse], error)
+	CreateDevice(context.Context, *connect.Request[v1.CreateDeviceRequest]) (*connect.Response[v1.CreateDeviceResponse], error)
+	GetDevice(context.Context, *connect.Request[v1.GetDeviceRequest]) (*connect.Response[v1.GetDeviceResponse], error)
+	// UpdateDevice replaces the metadata, labels and group of a device.
+	UpdateDevice(context.Context, *connect.Request[v1.UpdateDeviceRequest]) (*connect.Response[v1.UpdateDeviceResponse], error)
+	ListDevices(context.Context, *connect.Request[v1.ListDevicesRequest]) (*connect.Response[v1.ListDevicesResponse], error)
+	// GetFleetSnapshot returns the latest metric, last seen time and number of
+	// unresolved alerts of each registered device.
+	GetFleetSnapshot(context.Context, *connect.Request[v1.GetFleetSnapshotRequest]) (*connect.Response[v1.GetFleetSnapshotResponse], error)
+	// DeleteDevice removes a device along with its config and alert rules, and
+	// starts an operation that purges its metrics and alerts in the background.
+	DeleteDevice(context.Context, *connect.Request[v1.DeleteDeviceRequest]) (*connect.Response[v1.DeleteDeviceResponse], error)
+	// GetOperation returns the status of a long-running operation.
+	GetOperation(context.Context, *connect.Request[v1.GetOperationRequest]) (*connect.Response[v1.GetOperationResponse], error)
+	CreateDeviceGroup(context.Context, *connect.Request[v1.CreateDeviceGroupRequest]) (*connect.Response[v1.CreateDeviceGroupResponse], error)
+	GetDeviceGroup(context.Context, *connect.Request[v1.GetDeviceGroupRequest]) (*connect.Response[v1.GetDeviceGroupResponse], error)
+	ListDeviceGroups(context.Context, *connect.Request[v1.ListDeviceGroupsRequest]) (*connect.Response[v1.ListDeviceGroupsResponse], error)
+	// ConfigureDeviceGroup sets the config inherited by the devices of a group,
+	// replacing any existing group config.
+	ConfigureDeviceGroup(context.Context, *connect.Request[v1.ConfigureDeviceGroupRequest]) (*connect.Response[v1.ConfigureDeviceGroupResponse], error)
+	// ConfigureDefaults sets the global default config inherited by all devices,
+	// replacing any existing defaults.
+	ConfigureDefaults(context.Context, *connect.Request[v1.ConfigureDefaultsRequest]) (*connect.Response[v1.ConfigureDefaultsResponse], error)
+	// GetEffectiveDeviceConfig returns the config of a device resolved from the
+	// device, its group and the global defaults, along with the level that each
+	// setting was resolved from.
+	GetEffectiveDeviceConfig(context.Context, *connect.Request[v1.GetEffectiveDeviceConfigRequest]) (*connect.Response[v1.GetEffectiveDeviceConfigResponse], error)
+	// ListDeviceConfigVersions lists the versions of a device's config, most
+	// recent first. A version is created each time the device is configured.
+	ListDeviceConfigVersions(context.Context, *connect.Request[v1.ListDeviceConfigVersionsRequest]) (*connect.Response[v1.ListDeviceConfigVersionsResponse], error)
+	// RollbackDeviceConfig restores the settings of a previous version of a
+	// device's config as a new version.
+	RollbackDeviceConfig(context.Context, *connect.Request[v1.RollbackDeviceConfigRequest]) (*connect.Response[v1.RollbackDeviceConfigResponse], error)
 }
 
 // NewDeviceServiceClient constructs a client for the iot.v1.DeviceService service. By default, it
@@ -68,26 +226,243 @@ func NewDeviceServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(deviceServiceMethods.ByName("RecordMetric")),
 			connect.WithClientOptions(opts...),
 		),
+		recordMetrics: connect.NewClient[v1.RecordMetricsRequest, v1.RecordMetricsResponse](
+			httpClient,
+			baseURL+DeviceServiceRecordMetricsProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("RecordMetrics")),
+			connect.WithClientOptions(opts...),
+		),
+		streamMetrics: connect.NewClient[v1.StreamMetricsRequest, v1.StreamMetricsResponse](
+			httpClient,
+			baseURL+DeviceServiceStreamMetricsProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("StreamMetrics")),
+			connect.WithClientOptions(opts...),
+		),
 		configureDevice: connect.NewClient[v1.ConfigureDeviceRequest, v1.ConfigureDeviceResponse](
 			httpClient,
 			baseURL+DeviceServiceConfigureDeviceProcedure,
 			connect.WithSchema(deviceServiceMethods.ByName("ConfigureDevice")),
 			connect.WithClientOptions(opts...),
 		),
+		getDeviceConfig: connect.NewClient[v1.GetDeviceConfigRequest, v1.GetDeviceConfigResponse](
+			httpClient,
+			baseURL+DeviceServiceGetDeviceConfigProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetDeviceConfig")),
+			connect.WithClientOptions(opts...),
+		),
+		updateDeviceConfig: connect.NewClient[v1.UpdateDeviceConfigRequest, v1.UpdateDeviceConfigResponse](
+			httpClient,
+			baseURL+DeviceServiceUpdateDeviceConfigProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("UpdateDeviceConfig")),
+			connect.WithClientOptions(opts...),
+		),
+		getDeviceMetrics: connect.NewClient[v1.GetDeviceMetricsRequest, v1.GetDeviceMetricsResponse](
+			httpClient,
+			baseURL+DeviceServiceGetDeviceMetricsProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetDeviceMetrics")),
+			connect.WithClientOptions(opts...),
+		),
+		getDeviceMetricAggregates: connect.NewClient[v1.GetDeviceMetricAggregatesRequest, v1.GetDeviceMetricAggregatesResponse](
+			httpClient,
+			baseURL+DeviceServiceGetDeviceMetricAggregatesProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetDeviceMetricAggregates")),
+			connect.WithClientOptions(opts...),
+		),
 		getDeviceAlerts: connect.NewClient[v1.GetDeviceAlertsRequest, v1.GetDeviceAlertsResponse](
 			httpClient,
 			baseURL+DeviceServiceGetDeviceAlertsProcedure,
 			connect.WithSchema(deviceServiceMethods.ByName("GetDeviceAlerts")),
 			connect.WithClientOptions(opts...),
 		),
+		searchAlerts: connect.NewClient[v1.SearchAlertsRequest, v1.SearchAlertsResponse](
+			httpClient,
+			baseURL+DeviceServiceSearchAlertsProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("SearchAlerts")),
+			connect.WithClientOptions(opts...),
+		),
+		getAlertStats: connect.NewClient[v1.GetAlertStatsRequest, v1.GetAlertStatsResponse](
+			httpClient,
+			baseURL+DeviceServiceGetAlertStatsProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetAlertStats")),
+			connect.WithClientOptions(opts...),
+		),
+		watchDeviceAlerts: connect.NewClient[v1.WatchDeviceAlertsRequest, v1.WatchDeviceAlertsResponse](
+			httpClient,
+			baseURL+DeviceServiceWatchDeviceAlertsProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("WatchDeviceAlerts")),
+			connect.WithClientOptions(opts...),
+		),
+		createAlertRule: connect.NewClient[v1.CreateAlertRuleRequest, v1.CreateAlertRuleResponse](
+			httpClient,
+			baseURL+DeviceServiceCreateAlertRuleProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("CreateAlertRule")),
+			connect.WithClientOptions(opts...),
+		),
+		getAlertRule: connect.NewClient[v1.GetAlertRuleRequest, v1.GetAlertRuleResponse](
+			httpClient,
+			baseURL+DeviceServiceGetAlertRuleProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetAlertRule")),
+			connect.WithClientOptions(opts...),
+		),
+		listAlertRules: connect.NewClient[v1.ListAlertRulesRequest, v1.ListAlertRulesResponse](
+			httpClient,
+			baseURL+DeviceServiceListAlertRulesProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ListAlertRules")),
+			connect.WithClientOptions(opts...),
+		),
+		updateAlertRule: connect.NewClient[v1.UpdateAlertRuleRequest, v1.UpdateAlertRuleResponse](
+			httpClient,
+			baseURL+DeviceServiceUpdateAlertRuleProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("UpdateAlertRule")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteAlertRule: connect.NewClient[v1.DeleteAlertRuleRequest, v1.DeleteAlertRuleResponse](
+			httpClient,
+			baseURL+DeviceServiceDeleteAlertRuleProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("DeleteAlertRule")),
+			connect.WithClientOptions(opts...),
+		),
+		acknowledgeAlert: connect.NewClient[v1.AcknowledgeAlertRequest, v1.AcknowledgeAlertResponse](
+			httpClient,
+			baseURL+DeviceServiceAcknowledgeAlertProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("AcknowledgeAlert")),
+			connect.WithClientOptions(opts...),
+		),
+		resolveAlert: connect.NewClient[v1.ResolveAlertRequest, v1.ResolveAlertResponse](
+			httpClient,
+			baseURL+DeviceServiceResolveAlertProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ResolveAlert")),
+			connect.WithClientOptions(opts...),
+		),
+		createDevice: connect.NewClient[v1.CreateDeviceRequest, v1.CreateDeviceResponse](
+			httpClient,
+			baseURL+DeviceServiceCreateDeviceProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("CreateDevice")),
+			connect.WithClientOptions(opts...),
+		),
+		getDevice: connect.NewClient[v1.GetDeviceRequest, v1.GetDeviceResponse](
+			httpClient,
+			baseURL+DeviceServiceGetDeviceProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetDevice")),
+			connect.WithClientOptions(opts...),
+		),
+		updateDevice: connect.NewClient[v1.UpdateDeviceRequest, v1.UpdateDeviceResponse](
+			httpClient,
+			baseURL+DeviceServiceUpdateDeviceProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("UpdateDevice")),
+			connect.WithClientOptions(opts...),
+		),
+		listDevices: connect.NewClient[v1.ListDevicesRequest, v1.ListDevicesResponse](
+			httpClient,
+			baseURL+DeviceServiceListDevicesProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ListDevices")),
+			connect.WithClientOptions(opts...),
+		),
+		getFleetSnapshot: connect.NewClient[v1.GetFleetSnapshotRequest, v1.GetFleetSnapshotResponse](
+			httpClient,
+			baseURL+DeviceServiceGetFleetSnapshotProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetFleetSnapshot")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteDevice: connect.NewClient[v1.DeleteDeviceRequest, v1.DeleteDeviceResponse](
+			httpClient,
+			baseURL+DeviceServiceDeleteDeviceProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("DeleteDevice")),
+			connect.WithClientOptions(opts...),
+		),
+		getOperation: connect.NewClient[v1.GetOperationRequest, v1.GetOperationResponse](
+			httpClient,
+			baseURL+DeviceServiceGetOperationProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetOperation")),
+			connect.WithClientOptions(opts...),
+		),
+		createDeviceGroup: connect.NewClient[v1.CreateDeviceGroupRequest, v1.CreateDeviceGroupResponse](
+			httpClient,
+			baseURL+DeviceServiceCreateDeviceGroupProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("CreateDeviceGroup")),
+			connect.WithClientOptions(opts...),
+		),
+		getDeviceGroup: connect.NewClient[v1.GetDeviceGroupRequest, v1.GetDeviceGroupResponse](
+			httpClient,
+			baseURL+DeviceServiceGetDeviceGroupProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetDeviceGroup")),
+			connect.WithClientOptions(opts...),
+		),
+		listDeviceGroups: connect.NewClient[v1.ListDeviceGroupsRequest, v1.ListDeviceGroupsResponse](
+			httpClient,
+			baseURL+DeviceServiceListDeviceGroupsProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ListDeviceGroups")),
+			connect.WithClientOptions(opts...),
+		),
+		configureDeviceGroup: connect.NewClient[v1.ConfigureDeviceGroupRequest, v1.ConfigureDeviceGroupResponse](
+			httpClient,
+			baseURL+DeviceServiceConfigureDeviceGroupProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ConfigureDeviceGroup")),
+			connect.WithClientOptions(opts...),
+		),
+		configureDefaults: connect.NewClient[v1.ConfigureDefaultsRequest, v1.ConfigureDefaultsResponse](
+			httpClient,
+			baseURL+DeviceServiceConfigureDefaultsProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ConfigureDefaults")),
+			connect.WithClientOptions(opts...),
+		),
+		getEffectiveDeviceConfig: connect.NewClient[v1.GetEffectiveDeviceConfigRequest, v1.GetEffectiveDeviceConfigResponse](
+			httpClient,
+			baseURL+DeviceServiceGetEffectiveDeviceConfigProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetEffectiveDeviceConfig")),
+			connect.WithClientOptions(opts...),
+		),
+		listDeviceConfigVersions: connect.NewClient[v1.ListDeviceConfigVersionsRequest, v1.ListDeviceConfigVersionsResponse](
+			httpClient,
+			baseURL+DeviceServiceListDeviceConfigVersionsProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ListDeviceConfigVersions")),
+			connect.WithClientOptions(opts...),
+		),
+		rollbackDeviceConfig: connect.NewClient[v1.RollbackDeviceConfigRequest, v1.RollbackDeviceConfigResponse](
+			httpClient,
+			baseURL+DeviceServiceRollbackDeviceConfigProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("RollbackDeviceConfig")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // deviceServiceClient implements DeviceServiceClient.
 type deviceServiceClient struct {
-	recordMetric    *connect.Client[v1.RecordMetricRequest, v1.RecordMetricResponse]
-	configureDevice *connect.Client[v1.ConfigureDeviceRequest, v1.ConfigureDeviceResponse]
-	getDeviceAlerts *connect.Client[v1.GetDeviceAlertsRequest, v1.GetDeviceAlertsResponse]
+	recordMetric              *connect.Client[v1.RecordMetricRequest, v1.RecordMetricResponse]
+	recordMetrics             *connect.Client[v1.RecordMetricsRequest, v1.RecordMetricsResponse]
+	streamMetrics             *connect.Client[v1.StreamMetricsRequest, v1.StreamMetricsResponse]
+	configureDevice           *connect.Client[v1.ConfigureDeviceRequest, v1.ConfigureDeviceResponse]
+	getDeviceConfig           *connect.Client[v1.GetDeviceConfigRequest, v1.GetDeviceConfigResponse]
+	updateDeviceConfig        *connect.Client[v1.UpdateDeviceConfigRequest, v1.UpdateDeviceConfigResponse]
+	getDeviceMetrics          *connect.Client[v1.GetDeviceMetricsRequest, v1.GetDeviceMetricsResponse]
+	getDeviceMetricAggregates *connect.Client[v1.GetDeviceMetricAggregatesRequest, v1.GetDeviceMetricAggregatesResponse]
+	getDeviceAlerts           *connect.Client[v1.GetDeviceAlertsRequest, v1.GetDeviceAlertsResponse]
+	searchAlerts              *connect.Client[v1.SearchAlertsRequest, v1.SearchAlertsResponse]
+	getAlertStats             *connect.Client[v1.GetAlertStatsRequest, v1.GetAlertStatsResponse]
+	watchDeviceAlerts         *connect.Client[v1.WatchDeviceAlertsRequest, v1.WatchDeviceAlertsResponse]
+	createAlertRule           *connect.Client[v1.CreateAlertRuleRequest, v1.CreateAlertRuleResponse]
+	getAlertRule              *connect.Client[v1.GetAlertRuleRequest, v1.GetAlertRuleResponse]
+	listAlertRules            *connect.Client[v1.ListAlertRulesRequest, v1.ListAlertRulesResponse]
+	updateAlertRule           *connect.Client[v1.UpdateAlertRuleRequest, v1.UpdateAlertRuleResponse]
+	deleteAlertRule           *connect.Client[v1.DeleteAlertRuleRequest, v1.DeleteAlertRuleResponse]
+	acknowledgeAlert          *connect.Client[v1.AcknowledgeAlertRequest, v1.AcknowledgeAlertResponse]
+	resolveAlert              *connect.Client[v1.ResolveAlertRequest, v1.ResolveAlertResponse]
+	createDevice              *connect.Client[v1.CreateDeviceRequest, v1.CreateDeviceResponse]
+	getDevice                 *connect.Client[v1.GetDeviceRequest, v1.GetDeviceResponse]
+	updateDevice              *connect.Client[v1.UpdateDeviceRequest, v1.UpdateDeviceResponse]
+	listDevices               *connect.Client[v1.ListDevicesRequest, v1.ListDevicesResponse]
+	getFleetSnapshot          *connect.Client[v1.GetFleetSnapshotRequest, v1.GetFleetSnapshotResponse]
+	deleteDevice              *connect.Client[v1.DeleteDeviceRequest, v1.DeleteDeviceResponse]
+	getOperation              *connect.Client[v1.GetOperationRequest, v1.GetOperationResponse]
+	createDeviceGroup         *connect.Client[v1.CreateDeviceGroupRequest, v1.CreateDeviceGroupResponse]
+	getDeviceGroup            *connect.Client[v1.GetDeviceGroupRequest, v1.GetDeviceGroupResponse]
+	listDeviceGroups          *connect.Client[v1.ListDeviceGroupsRequest, v1.ListDeviceGroupsResponse]
+	configureDeviceGroup      *connect.Client[v1.ConfigureDeviceGroupRequest, v1.ConfigureDeviceGroupResponse]
+	configureDefaults         *connect.Client[v1.ConfigureDefaultsRequest, v1.ConfigureDefaultsResponse]
+	getEffectiveDeviceConfig  *connect.Client[v1.GetEffectiveDeviceConfigRequest, v1.GetEffectiveDeviceConfigResponse]
+	listDeviceConfigVersions  *connect.Client[v1.ListDeviceConfigVersionsRequest, v1.ListDeviceConfigVersionsResponse]
+	rollbackDeviceConfig      *connect.Client[v1.RollbackDeviceConfigRequest, v1.RollbackDeviceConfigResponse]
 }
 
 // RecordMetric calls iot.v1.DeviceService.RecordMetric.
@@ -95,21 +470,242 @@ func (c *deviceServiceClient) RecordMetric(ctx context.Context, req *connect.Req
 	return c.recordMetric.CallUnary(ctx, req)
 }
 
+// RecordMetrics calls iot.v1.DeviceService.RecordMetrics.
+func (c *deviceServiceClient) RecordMetrics(ctx context.Context, req *connect.Request[v1.RecordMetricsRequest]) (*connect.Response[v1.RecordMetricsResponse], error) {
+	return c.recordMetrics.CallUnary(ctx, req)
+}
+
+// StreamMetrics calls iot.v1.DeviceService.StreamMetrics.
+func (c *deviceServiceClient) StreamMetrics(ctx context.Context) *connect.BidiStreamForClient[v1.StreamMetricsRequest, v1.StreamMetricsResponse] {
+	return c.streamMetrics.CallBidiStream(ctx)
+}
+
 // ConfigureDevice calls iot.v1.DeviceService.ConfigureDevice.
 func (c *deviceServiceClient) ConfigureDevice(ctx context.Context, req *connect.Request[v1.ConfigureDeviceRequest]) (*connect.Response[v1.ConfigureDeviceResponse], error) {
 	return c.configureDevice.CallUnary(ctx, req)
 }
 
+// GetDeviceConfig calls iot.v1.DeviceService.GetDeviceConfig.
+func (c *deviceServiceClient) GetDeviceConfig(ctx context.Context, req *connect.Request[v1.GetDeviceConfigRequest]) (*connect.Response[v1.GetDeviceConfigResponse], error) {
+	return c.getDeviceConfig.CallUnary(ctx, req)
+}
+
+// UpdateDeviceConfig calls iot.v1.DeviceService.UpdateDeviceConfig.
+func (c *deviceServiceClient) UpdateDeviceConfig(ctx context.Context, req *connect.Request[v1.UpdateDeviceConfigRequest]) (*connect.Response[v1.UpdateDeviceConfigResponse], error) {
+	return c.updateDeviceConfig.CallUnary(ctx, req)
+}
+
+// GetDeviceMetrics calls iot.v1.DeviceService.GetDeviceMetrics.
+func (c *deviceServiceClient) GetDeviceMetrics(ctx context.Context, req *connect.Request[v1.GetDeviceMetricsRequest]) (*connect.Response[v1.GetDeviceMetricsResponse], error) {
+	return c.getDeviceMetrics.CallUnary(ctx, req)
+}
+
+// GetDeviceMetricAggregates calls iot.v1.DeviceService.GetDeviceMetricAggregates.
+func (c *deviceServiceClient) GetDeviceMetricAggregates(ctx context.Context, req *connect.Request[v1.GetDeviceMetricAggregatesRequest]) (*connect.Response[v1.GetDeviceMetricAggregatesResponse], error) {
+	return c.getDeviceMetricAggregates.CallUnary(ctx, req)
+}
+
 // GetDeviceAlerts calls iot.v1.DeviceService.GetDeviceAlerts.
 func (c *deviceServiceClient) GetDeviceAlerts(ctx context.Context, req *connect.Request[v1.GetDeviceAlertsRequest]) (*connect.Response[v1.GetDeviceAlertsResponse], error) {
 	return c.getDeviceAlerts.CallUnary(ctx, req)
 }
 
+// SearchAlerts calls iot.v1.DeviceService.SearchAlerts.
+func (c *deviceServiceClient) SearchAlerts(ctx context.Context, req *connect.Request[v1.SearchAlertsRequest]) (*connect.Response[v1.SearchAlertsResponse], error) {
+	return c.searchAlerts.CallUnary(ctx, req)
+}
+
+// GetAlertStats calls iot.v1.DeviceService.GetAlertStats.
+func (c *deviceServiceClient) GetAlertStats(ctx context.Context, req *connect.Request[v1.GetAlertStatsRequest]) (*connect.Response[v1.GetAlertStatsResponse], error) {
+	return c.getAlertStats.CallUnary(ctx, req)
+}
+
+// WatchDeviceAlerts calls iot.v1.DeviceService.WatchDeviceAlerts.
+func (c *deviceServiceClient) WatchDeviceAlerts(ctx context.Context, req *connect.Request[v1.WatchDeviceAlertsRequest]) (*connect.ServerStreamForClient[v1.WatchDeviceAlertsResponse], error) {
+	return c.watchDeviceAlerts.CallServerStream(ctx, req)
+}
+
+// CreateAlertRule calls iot.v1.DeviceService.CreateAlertRule.
+func (c *deviceServiceClient) CreateAlertRule(ctx context.Context, req *connect.Request[v1.CreateAlertRuleRequest]) (*connect.Response[v1.CreateAlertRuleResponse], error) {
+	return c.createAlertRule.CallUnary(ctx, req)
+}
+
+// GetAlertRule calls iot.v1.DeviceService.GetAlertRule.
+func (c *deviceServiceClient) GetAlertRule(ctx context.Context, req *connect.Request[v1.GetAlertRuleRequest]) (*connect.Response[v1.GetAlertRuleResponse], error) {
+	return c.getAlertRule.CallUnary(ctx, req)
+}
+
+// ListAlertRules calls iot.v1.DeviceService.ListAlertRules.
+func (c *deviceServiceClient) ListAlertRules(ctx context.Context, req *connect.Request[v1.ListAlertRulesRequest]) (*connect.Response[v1.ListAlertRulesResponse], error) {
+	return c.listAlertRules.CallUnary(ctx, req)
+}
+
+// UpdateAlertRule calls iot.v1.DeviceService.UpdateAlertRule.
+func (c *deviceServiceClient) UpdateAlertRule(ctx context.Context, req *connect.Request[v1.UpdateAlertRuleRequest]) (*connect.Response[v1.UpdateAlertRuleResponse], error) {
+	return c.updateAlertRule.CallUnary(ctx, req)
+}
+
+// DeleteAlertRule calls iot.v1.DeviceService.DeleteAlertRule.
+func (c *deviceServiceClient) DeleteAlertRule(ctx context.Context, req *connect.Request[v1.DeleteAlertRuleRequest]) (*connect.Response[v1.DeleteAlertRuleResponse], error) {
+	return c.deleteAlertRule.CallUnary(ctx, req)
+}
+
+// AcknowledgeAlert calls iot.v1.DeviceService.AcknowledgeAlert.
+func (c *deviceServiceClient) AcknowledgeAlert(ctx context.Context, req *connect.Request[v1.AcknowledgeAlertRequest]) (*connect.Response[v1.AcknowledgeAlertResponse], error) {
+	return c.acknowledgeAlert.CallUnary(ctx, req)
+}
+
+// ResolveAlert calls iot.v1.DeviceService.ResolveAlert.
+func (c *deviceServiceClient) ResolveAlert(ctx context.Context, req *connect.Request[v1.ResolveAlertRequest]) (*connect.Response[v1.ResolveAlertResponse], error) {
+	return c.resolveAlert.CallUnary(ctx, req)
+}
+
+// CreateDevice calls iot.v1.DeviceService.CreateDevice.
+func (c *deviceServiceClient) CreateDevice(ctx context.Context, req *connect.Request[v1.CreateDeviceRequest]) (*connect.Response[v1.CreateDeviceResponse], error) {
+	return c.createDevice.CallUnary(ctx, req)
+}
+
+// GetDevice calls iot.v1.DeviceService.GetDevice.
+func (c *deviceServiceClient) GetDevice(ctx context.Context, req *connect.Request[v1.GetDeviceRequest]) (*connect.Response[v1.GetDeviceResponse], error) {
+	return c.getDevice.CallUnary(ctx, req)
+}
+
+// UpdateDevice calls iot.v1.DeviceService.UpdateDevice.
+func (c *deviceServiceClient) UpdateDevice(ctx context.Context, req *connect.Request[v1.UpdateDeviceRequest]) (*connect.Response[v1.UpdateDeviceResponse], error) {
+	return c.updateDevice.CallUnary(ctx, req)
+}
+
+// ListDevices calls iot.v1.DeviceService.ListDevices.
+func (c *deviceServiceClient) ListDevices(ctx context.Context, req *connect.Request[v1.ListDevicesRequest]) (*connect.Response[v1.ListDevicesResponse], error) {
+	return c.listDevices.CallUnary(ctx, req)
+}
+
+// GetFleetSnapshot calls iot.v1.DeviceService.GetFleetSnapshot.
+func (c *deviceServiceClient) GetFleetSnapshot(ctx context.Context, req *connect.Request[v1.GetFleetSnapshotRequest]) (*connect.Response[v1.GetFleetSnapshotResponse], error) {
+	return c.getFleetSnapshot.CallUnary(ctx, req)
+}
+
+// DeleteDevice calls iot.v1.DeviceService.DeleteDevice.
+func (c *deviceServiceClient) DeleteDevice(ctx context.Context, req *connect.Request[v1.DeleteDeviceRequest]) (*connect.Response[v1.DeleteDeviceResponse], error) {
+	return c.deleteDevice.CallUnary(ctx, req)
+}
+
+// GetOperation calls iot.v1.DeviceService.GetOperation.
+func (c *deviceServiceClient) GetOperation(ctx context.Context, req *connect.Request[v1.GetOperationRequest]) (*connect.Response[v1.GetOperationResponse], error) {
+	return c.getOperation.CallUnary(ctx, req)
+}
+
+// CreateDeviceGroup calls iot.v1.DeviceService.CreateDeviceGroup.
+func (c *deviceServiceClient) CreateDeviceGroup(ctx context.Context, req *connect.Request[v1.CreateDeviceGroupRequest]) (*connect.Response[v1.CreateDeviceGroupResponse], error) {
+	return c.createDeviceGroup.CallUnary(ctx, req)
+}
+
+// GetDeviceGroup calls iot.v1.DeviceService.GetDeviceGroup.
+func (c *deviceServiceClient) GetDeviceGroup(ctx context.Context, req *connect.Request[v1.GetDeviceGroupRequest]) (*connect.Response[v1.GetDeviceGroupResponse], error) {
+	return c.getDeviceGroup.CallUnary(ctx, req)
+}
+
+// ListDeviceGroups calls iot.v1.DeviceService.ListDeviceGroups.
+func (c *deviceServiceClient) ListDeviceGroups(ctx context.Context, req *connect.Request[v1.ListDeviceGroupsRequest]) (*connect.Response[v1.ListDeviceGroupsResponse], error) {
+	return c.listDeviceGroups.CallUnary(ctx, req)
+}
+
+// ConfigureDeviceGroup calls iot.v1.DeviceService.ConfigureDeviceGroup.
+func (c *deviceServiceClient) ConfigureDeviceGroup(ctx context.Context, req *connect.Request[v1.ConfigureDeviceGroupRequest]) (*connect.Response[v1.ConfigureDeviceGroupResponse], error) {
+	return c.configureDeviceGroup.CallUnary(ctx, req)
+}
+
+// ConfigureDefaults calls iot.v1.DeviceService.ConfigureDefaults.
+func (c *deviceServiceClient) ConfigureDefaults(ctx context.Context, req *connect.Request[v1.ConfigureDefaultsRequest]) (*connect.Response[v1.ConfigureDefaultsResponse], error) {
+	return c.configureDefaults.CallUnary(ctx, req)
+}
+
+// GetEffectiveDeviceConfig calls iot.v1.DeviceService.GetEffectiveDeviceConfig.
+func (c *deviceServiceClient) GetEffectiveDeviceConfig(ctx context.Context, req *connect.Request[v1.GetEffectiveDeviceConfigRequest]) (*connect.Response[v1.GetEffectiveDeviceConfigResponse], error) {
+	return c.getEffectiveDeviceConfig.CallUnary(ctx, req)
+}
+
+// ListDeviceConfigVersions calls iot.v1.DeviceService.ListDeviceConfigVersions.
+func (c *deviceServiceClient) ListDeviceConfigVersions(ctx context.Context, req *connect.Request[v1.ListDeviceConfigVersionsRequest]) (*connect.Response[v1.ListDeviceConfigVersionsResponse], error) {
+	return c.listDeviceConfigVersions.CallUnary(ctx, req)
+}
+
+// RollbackDeviceConfig calls iot.v1.DeviceService.RollbackDeviceConfig.
+func (c *deviceServiceClient) RollbackDeviceConfig(ctx context.Context, req *connect.Request[v1.RollbackDeviceConfigRequest]) (*connect.Response[v1.RollbackDeviceConfigResponse], error) {
+	return c.rollbackDeviceConfig.CallUnary(ctx, req)
+}
+
 // DeviceServiceHandler is an implementation of the iot.v1.DeviceService service.
 type DeviceServiceHandler interface {
 	RecordMetric(context.Context, *connect.Request[v1.RecordMetricRequest]) (*connect.Response[v1.RecordMetricResponse], error)
+	RecordMetrics(context.Context, *connect.Request[v1.RecordMetricsRequest]) (*connect.Response[v1.RecordMetricsResponse], error)
+	// StreamMetrics records metrics sent on a long-lived stream. Each metric is
+	// processed as it is received and acknowledged periodically.
+	StreamMetrics(context.Context, *connect.BidiStream[v1.StreamMetricsRequest, v1.StreamMetricsResponse]) error
 	ConfigureDevice(context.Context, *connect.Request[v1.ConfigureDeviceRequest]) (*connect.Response[v1.ConfigureDeviceResponse], error)
+	// GetDeviceConfig returns the current version of the settings set for a
+	// device, excluding settings inherited from its group or the defaults.
+	GetDeviceConfig(context.Context, *connect.Request[v1.GetDeviceConfigRequest]) (*connect.Response[v1.GetDeviceConfigResponse], error)
+	// UpdateDeviceConfig updates the settings of a device in the update mask,
+	// keeping all other settings, and stores the result as a new version.
+	UpdateDeviceConfig(context.Context, *connect.Request[v1.UpdateDeviceConfigRequest]) (*connect.Response[v1.UpdateDeviceConfigResponse], error)
+	GetDeviceMetrics(context.Context, *connect.Request[v1.GetDeviceMetricsRequest]) (*connect.Response[v1.GetDeviceMetricsResponse], error)
+	// GetDeviceMetricAggregates downsamples the metrics of a device over a
+	// timeframe, returning the min, max, avg, count, first and last value of
+	// each metric per bucket.
+	GetDeviceMetricAggregates(context.Context, *connect.Request[v1.GetDeviceMetricAggregatesRequest]) (*connect.Response[v1.GetDeviceMetricAggregatesResponse], error)
 	GetDeviceAlerts(context.Context, *connect.Request[v1.GetDeviceAlertsRequest]) (*connect.Response[v1.GetDeviceAlertsResponse], error)
+	// SearchAlerts returns the alerts of all devices matching the filters, most
+	// recent first.
+	SearchAlerts(context.Context, *connect.Request[v1.SearchAlertsRequest]) (*connect.Response[v1.SearchAlertsResponse], error)
+	// GetAlertStats counts the alerts of all devices triggered over a timeframe
+	// by reason, by device and by bucket, along with the noisiest devices.
+	GetAlertStats(context.Context, *connect.Request[v1.GetAlertStatsRequest]) (*connect.Response[v1.GetAlertStatsResponse], error)
+	// WatchDeviceAlerts streams alerts as they are triggered. Alerts triggered
+	// after a cursor from a previous response are replayed before new alerts.
+	WatchDeviceAlerts(context.Context, *connect.Request[v1.WatchDeviceAlertsRequest], *connect.ServerStream[v1.WatchDeviceAlertsResponse]) error
+	CreateAlertRule(context.Context, *connect.Request[v1.CreateAlertRuleRequest]) (*connect.Response[v1.CreateAlertRuleResponse], error)
+	GetAlertRule(context.Context, *connect.Request[v1.GetAlertRuleRequest]) (*connect.Response[v1.GetAlertRuleResponse], error)
+	ListAlertRules(context.Context, *connect.Request[v1.ListAlertRulesRequest]) (*connect.Response[v1.ListAlertRulesResponse], error)
+	UpdateAlertRule(context.Context, *connect.Request[v1.UpdateAlertRuleRequest]) (*connect.Response[v1.UpdateAlertRuleResponse], error)
+	DeleteAlertRule(context.Context, *connect.Request[v1.DeleteAlertRuleRequest]) (*connect.Response[v1.DeleteAlertRuleResponse], error)
+	// AcknowledgeAlert records that an operator is handling an open alert.
+	AcknowledgeAlert(context.Context, *connect.Request[v1.AcknowledgeAlertRequest]) (*connect.Response[v1.AcknowledgeAlertResponse], error)
+	// ResolveAlert resolves an open or acknowledged alert. Alerts are also
+	// resolved automatically once readings return within the threshold.
+	ResolveAlert(context.Context, *connect.Request[v1.ResolveAlertRequest]) (*connect.Response[v1.ResolveAlertResponse], error)
+	CreateDevice(context.Context, *connect.Request[v1.CreateDeviceRequest]) (*connect.Response[v1.CreateDeviceResponse], error)
+	GetDevice(context.Context, *connect.Request[v1.GetDeviceRequest]) (*connect.Response[v1.GetDeviceResponse], error)
+	// UpdateDevice replaces the metadata, labels and group of a device.
+	UpdateDevice(context.Context, *connect.Request[v1.UpdateDeviceRequest]) (*connect.Response[v1.UpdateDeviceResponse], error)
+	ListDevices(context.Context, *connect.Request[v1.ListDevicesRequest]) (*connect.Response[v1.ListDevicesResponse], error)
+	// GetFleetSnapshot returns the latest metric, last seen time and number of
+	// unresolved alerts of each registered device.
+	GetFleetSnapshot(context.Context, *connect.Request[v1.GetFleetSnapshotRequest]) (*connect.Response[v1.GetFleetSnapshotResponse], error)
+	// DeleteDevice removes a device along with its config and alert rules, and
+	// starts an operation that purges its metrics and alerts in the background.
+	DeleteDevice(context.Context, *connect.Request[v1.DeleteDeviceRequest]) (*connect.Response[v1.DeleteDeviceResponse], error)
+	// GetOperation returns the status of a long-running operation.
+	GetOperation(context.Context, *connect.Request[v1.GetOperationRequest]) (*connect.Response[v1.GetOperationResponse], error)
+	CreateDeviceGroup(context.Context, *connect.Request[v1.CreateDeviceGroupRequest]) (*connect.Response[v1.CreateDeviceGroupResponse], error)
+	GetDeviceGroup(context.Context, *connect.Request[v1.GetDeviceGroupRequest]) (*connect.Response[v1.GetDeviceGroupResponse], error)
+	ListDeviceGroups(context.Context, *connect.Request[v1.ListDeviceGroupsRequest]) (*connect.Response[v1.ListDeviceGroupsResponse], error)
+	// ConfigureDeviceGroup sets the config inherited by the devices of a group,
+	// replacing any existing group config.
+	ConfigureDeviceGroup(context.Context, *connect.Request[v1.ConfigureDeviceGroupRequest]) (*connect.Response[v1.ConfigureDeviceGroupResponse], error)
+	// ConfigureDefaults sets the global default config inherited by all devices,
+	// replacing any existing defaults.
+	ConfigureDefaults(context.Context, *connect.Request[v1.ConfigureDefaultsRequest]) (*connect.Response[v1.ConfigureDefaultsResponse], error)
+	// GetEffectiveDeviceConfig returns the config of a device resolved from the
+	// device, its group and the global defaults, along with the level that each
+	// setting was resolved from.
+	GetEffectiveDeviceConfig(context.Context, *connect.Request[v1.GetEffectiveDeviceConfigRequest]) (*connect.Response[v1.GetEffectiveDeviceConfigResponse], error)
+	// ListDeviceConfigVersions lists the versions of a device's config, most
+	// recent first. A version is created each time the device is configured.
+	ListDeviceConfigVersions(context.Context, *connect.Request[v1.ListDeviceConfigVersionsRequest]) (*connect.Response[v1.ListDeviceConfigVersionsResponse], error)
+	// RollbackDeviceConfig restores the settings of a previous version of a
+	// device's config as a new version.
+	RollbackDeviceConfig(context.Context, *connect.Request[v1.RollbackDeviceConfigRequest]) (*connect.Response[v1.RollbackDeviceConfigResponse], error)
 }
 
 // NewDeviceServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -125,26 +721,274 @@ func NewDeviceServiceHandler(svc DeviceServiceHandler, opts ...connect.HandlerOp
 		connect.WithSchema(deviceServiceMethods.ByName("RecordMetric")),
 		connect.WithHandlerOptions(opts...),
 	)
+	deviceServiceRecordMetricsHandler := connect.NewUnaryHandler(
+		DeviceServiceRecordMetricsProcedure,
+		svc.RecordMetrics,
+		connect.WithSchema(deviceServiceMethods.ByName("RecordMetrics")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceStreamMetricsHandler := connect.NewBidiStreamHandler(
+		DeviceServiceStreamMetricsProcedure,
+		svc.StreamMetrics,
+		connect.WithSchema(deviceServiceMethods.ByName("StreamMetrics")),
+		connect.WithHandlerOptions(opts...),
+	)
 	deviceServiceConfigureDeviceHandler := connect.NewUnaryHandler(
 		DeviceServiceConfigureDeviceProcedure,
 		svc.ConfigureDevice,
 		connect.WithSchema(deviceServiceMethods.ByName("ConfigureDevice")),
 		connect.WithHandlerOptions(opts...),
 	)
+	deviceServiceGetDeviceConfigHandler := connect.NewUnaryHandler(
+		DeviceServiceGetDeviceConfigProcedure,
+		svc.GetDeviceConfig,
+		connect.WithSchema(deviceServiceMethods.ByName("GetDeviceConfig")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceUpdateDeviceConfigHandler := connect.NewUnaryHandler(
+		DeviceServiceUpdateDeviceConfigProcedure,
+		svc.UpdateDeviceConfig,
+		connect.WithSchema(deviceServiceMethods.ByName("UpdateDeviceConfig")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceGetDeviceMetricsHandler := connect.NewUnaryHandler(
+		DeviceServiceGetDeviceMetricsProcedure,
+		svc.GetDeviceMetrics,
+		connect.WithSchema(deviceServiceMethods.ByName("GetDeviceMetrics")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceGetDeviceMetricAggregatesHandler := connect.NewUnaryHandler(
+		DeviceServiceGetDeviceMetricAggregatesProcedure,
+		svc.GetDeviceMetricAggregates,
+		connect.WithSchema(deviceServiceMethods.ByName("GetDeviceMetricAggregates")),
+		connect.WithHandlerOptions(opts...),
+	)
 	deviceServiceGetDeviceAlertsHandler := connect.NewUnaryHandler(
 		DeviceServiceGetDeviceAlertsProcedure,
 		svc.GetDeviceAlerts,
 		connect.WithSchema(deviceServiceMethods.ByName("GetDeviceAlerts")),
 		connect.WithHandlerOptions(opts...),
 	)
+	deviceServiceSearchAlertsHandler := connect.NewUnaryHandler(
+		DeviceServiceSearchAlertsProcedure,
+		svc.SearchAlerts,
+		connect.WithSchema(deviceServiceMethods.ByName("SearchAlerts")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceGetAlertStatsHandler := connect.NewUnaryHandler(
+		DeviceServiceGetAlertStatsProcedure,
+		svc.GetAlertStats,
+		connect.WithSchema(deviceServiceMethods.ByName("GetAlertStats")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceWatchDeviceAlertsHandler := connect.NewServerStreamHandler(
+		DeviceServiceWatchDeviceAlertsProcedure,
+		svc.WatchDeviceAlerts,
+		connect.WithSchema(deviceServiceMethods.ByName("WatchDeviceAlerts")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceCreateAlertRuleHandler := connect.NewUnaryHandler(
+		DeviceServiceCreateAlertRuleProcedure,
+		svc.CreateAlertRule,
+		connect.WithSchema(deviceServiceMethods.ByName("CreateAlertRule")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceGetAlertRuleHandler := connect.NewUnaryHandler(
+		DeviceServiceGetAlertRuleProcedure,
+		svc.GetAlertRule,
+		connect.WithSchema(deviceServiceMethods.ByName("GetAlertRule")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceListAlertRulesHandler := connect.NewUnaryHandler(
+		DeviceServiceListAlertRulesProcedure,
+		svc.ListAlertRules,
+		connect.WithSchema(deviceServiceMethods.ByName("ListAlertRules")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceUpdateAlertRuleHandler := connect.NewUnaryHandler(
+		DeviceServiceUpdateAlertRuleProcedure,
+		svc.UpdateAlertRule,
+		connect.WithSchema(deviceServiceMethods.ByName("UpdateAlertRule")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceDeleteAlertRuleHandler := connect.NewUnaryHandler(
+		DeviceServiceDeleteAlertRuleProcedure,
+		svc.DeleteAlertRule,
+		connect.WithSchema(deviceServiceMethods.ByName("DeleteAlertRule")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceAcknowledgeAlertHandler := connect.NewUnaryHandler(
+		DeviceServiceAcknowledgeAlertProcedure,
+		svc.AcknowledgeAlert,
+		connect.WithSchema(deviceServiceMethods.ByName("AcknowledgeAlert")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceResolveAlertHandler := connect.NewUnaryHandler(
+		DeviceServiceResolveAlertProcedure,
+		svc.ResolveAlert,
+		connect.WithSchema(deviceServiceMethods.ByName("ResolveAlert")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceCreateDeviceHandler := connect.NewUnaryHandler(
+		DeviceServiceCreateDeviceProcedure,
+		svc.CreateDevice,
+		connect.WithSchema(deviceServiceMethods.ByName("CreateDevice")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceGetDeviceHandler := connect.NewUnaryHandler(
+		DeviceServiceGetDeviceProcedure,
+		svc.GetDevice,
+		connect.WithSchema(deviceServiceMethods.ByName("GetDevice")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceUpdateDeviceHandler := connect.NewUnaryHandler(
+		DeviceServiceUpdateDeviceProcedure,
+		svc.UpdateDevice,
+		connect.WithSchema(deviceServiceMethods.ByName("UpdateDevice")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceListDevicesHandler := connect.NewUnaryHandler(
+		DeviceServiceListDevicesProcedure,
+		svc.ListDevices,
+		connect.WithSchema(deviceServiceMethods.ByName("ListDevices")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceGetFleetSnapshotHandler := connect.NewUnaryHandler(
+		DeviceServiceGetFleetSnapshotProcedure,
+		svc.GetFleetSnapshot,
+		connect.WithSchema(deviceServiceMethods.ByName("GetFleetSnapshot")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceDeleteDeviceHandler := connect.NewUnaryHandler(
+		DeviceServiceDeleteDeviceProcedure,
+		svc.DeleteDevice,
+		connect.WithSchema(deviceServiceMethods.ByName("DeleteDevice")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceGetOperationHandler := connect.NewUnaryHandler(
+		DeviceServiceGetOperationProcedure,
+		svc.GetOperation,
+		connect.WithSchema(deviceServiceMethods.ByName("GetOperation")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceCreateDeviceGroupHandler := connect.NewUnaryHandler(
+		DeviceServiceCreateDeviceGroupProcedure,
+		svc.CreateDeviceGroup,
+		connect.WithSchema(deviceServiceMethods.ByName("CreateDeviceGroup")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceGetDeviceGroupHandler := connect.NewUnaryHandler(
+		DeviceServiceGetDeviceGroupProcedure,
+		svc.GetDeviceGroup,
+		connect.WithSchema(deviceServiceMethods.ByName("GetDeviceGroup")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceListDeviceGroupsHandler := connect.NewUnaryHandler(
+		DeviceServiceListDeviceGroupsProcedure,
+		svc.ListDeviceGroups,
+		connect.WithSchema(deviceServiceMethods.ByName("ListDeviceGroups")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceConfigureDeviceGroupHandler := connect.NewUnaryHandler(
+		DeviceServiceConfigureDeviceGroupProcedure,
+		svc.ConfigureDeviceGroup,
+		connect.WithSchema(deviceServiceMethods.ByName("ConfigureDeviceGroup")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceConfigureDefaultsHandler := connect.NewUnaryHandler(
+		DeviceServiceConfigureDefaultsProcedure,
+		svc.ConfigureDefaults,
+		connect.WithSchema(deviceServiceMethods.ByName("ConfigureDefaults")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceGetEffectiveDeviceConfigHandler := connect.NewUnaryHandler(
+		DeviceServiceGetEffectiveDeviceConfigProcedure,
+		svc.GetEffectiveDeviceConfig,
+		connect.WithSchema(deviceServiceMethods.ByName("GetEffectiveDeviceConfig")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceListDeviceConfigVersionsHandler := connect.NewUnaryHandler(
+		DeviceServiceListDeviceConfigVersionsProcedure,
+		svc.ListDeviceConfigVersions,
+		connect.WithSchema(deviceServiceMethods.ByName("ListDeviceConfigVersions")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceRollbackDeviceConfigHandler := connect.NewUnaryHandler(
+		DeviceServiceRollbackDeviceConfigProcedure,
+		svc.RollbackDeviceConfig,
+		connect.WithSchema(deviceServiceMethods.ByName("RollbackDeviceConfig")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/iot.v1.DeviceService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case DeviceServiceRecordMetricProcedure:
 			deviceServiceRecordMetricHandler.ServeHTTP(w, r)
+		case DeviceServiceRecordMetricsProcedure:
+			deviceServiceRecordMetricsHandler.ServeHTTP(w, r)
+		case DeviceServiceStreamMetricsProcedure:
+			deviceServiceStreamMetricsHandler.ServeHTTP(w, r)
 		case DeviceServiceConfigureDeviceProcedure:
 			deviceServiceConfigureDeviceHandler.ServeHTTP(w, r)
+		case DeviceServiceGetDeviceConfigProcedure:
+			deviceServiceGetDeviceConfigHandler.ServeHTTP(w, r)
+		case DeviceServiceUpdateDeviceConfigProcedure:
+			deviceServiceUpdateDeviceConfigHandler.ServeHTTP(w, r)
+		case DeviceServiceGetDeviceMetricsProcedure:
+			deviceServiceGetDeviceMetricsHandler.ServeHTTP(w, r)
+		case DeviceServiceGetDeviceMetricAggregatesProcedure:
+			deviceServiceGetDeviceMetricAggregatesHandler.ServeHTTP(w, r)
 		case DeviceServiceGetDeviceAlertsProcedure:
 			deviceServiceGetDeviceAlertsHandler.ServeHTTP(w, r)
+		case DeviceServiceSearchAlertsProcedure:
+			deviceServiceSearchAlertsHandler.ServeHTTP(w, r)
+		case DeviceServiceGetAlertStatsProcedure:
+			deviceServiceGetAlertStatsHandler.ServeHTTP(w, r)
+		case DeviceServiceWatchDeviceAlertsProcedure:
+			deviceServiceWatchDeviceAlertsHandler.ServeHTTP(w, r)
+		case DeviceServiceCreateAlertRuleProcedure:
+			deviceServiceCreateAlertRuleHandler.ServeHTTP(w, r)
+		case DeviceServiceGetAlertRuleProcedure:
+			deviceServiceGetAlertRuleHandler.ServeHTTP(w, r)
+		case DeviceServiceListAlertRulesProcedure:
+			deviceServiceListAlertRulesHandler.ServeHTTP(w, r)
+		case DeviceServiceUpdateAlertRuleProcedure:
+			deviceServiceUpdateAlertRuleHandler.ServeHTTP(w, r)
+		case DeviceServiceDeleteAlertRuleProcedure:
+			deviceServiceDeleteAlertRuleHandler.ServeHTTP(w, r)
+		case DeviceServiceAcknowledgeAlertProcedure:
+			deviceServiceAcknowledgeAlertHandler.ServeHTTP(w, r)
+		case DeviceServiceResolveAlertProcedure:
+			deviceServiceResolveAlertHandler.ServeHTTP(w, r)
+		case DeviceServiceCreateDeviceProcedure:
+			deviceServiceCreateDeviceHandler.ServeHTTP(w, r)
+		case DeviceServiceGetDeviceProcedure:
+			deviceServiceGetDeviceHandler.ServeHTTP(w, r)
+		case DeviceServiceUpdateDeviceProcedure:
+			deviceServiceUpdateDeviceHandler.ServeHTTP(w, r)
+		case DeviceServiceListDevicesProcedure:
+			deviceServiceListDevicesHandler.ServeHTTP(w, r)
+		case DeviceServiceGetFleetSnapshotProcedure:
+			deviceServiceGetFleetSnapshotHandler.ServeHTTP(w, r)
+		case DeviceServiceDeleteDeviceProcedure:
+			deviceServiceDeleteDeviceHandler.ServeHTTP(w, r)
+		case DeviceServiceGetOperationProcedure:
+			deviceServiceGetOperationHandler.ServeHTTP(w, r)
+		case DeviceServiceCreateDeviceGroupProcedure:
+			deviceServiceCreateDeviceGroupHandler.ServeHTTP(w, r)
+		case DeviceServiceGetDeviceGroupProcedure:
+			deviceServiceGetDeviceGroupHandler.ServeHTTP(w, r)
+		case DeviceServiceListDeviceGroupsProcedure:
+			deviceServiceListDeviceGroupsHandler.ServeHTTP(w, r)
+		case DeviceServiceConfigureDeviceGroupProcedure:
+			deviceServiceConfigureDeviceGroupHandler.ServeHTTP(w, r)
+		case DeviceServiceConfigureDefaultsProcedure:
+			deviceServiceConfigureDefaultsHandler.ServeHTTP(w, r)
+		case DeviceServiceGetEffectiveDeviceConfigProcedure:
+			deviceServiceGetEffectiveDeviceConfigHandler.ServeHTTP(w, r)
+		case DeviceServiceListDeviceConfigVersionsProcedure:
+			deviceServiceListDeviceConfigVersionsHandler.ServeHTTP(w, r)
+		case DeviceServiceRollbackDeviceConfigProcedure:
+			deviceServiceRollbackDeviceConfigHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -158,10 +1002,134 @@ func (UnimplementedDeviceServiceHandler) RecordMetric(context.Context, *connect.
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.RecordMetric is not implemented"))
 }
 
+func (UnimplementedDeviceServiceHandler) RecordMetrics(context.Context, *connect.Request[v1.RecordMetricsRequest]) (*connect.Response[v1.RecordMetricsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.RecordMetrics is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) StreamMetrics(context.Context, *connect.BidiStream[v1.StreamMetricsRequest, v1.StreamMetricsResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.StreamMetrics is not implemented"))
+}
+
 func (UnimplementedDeviceServiceHandler) ConfigureDevice(context.Context, *connect.Request[v1.ConfigureDeviceRequest]) (*connect.Response[v1.ConfigureDeviceResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.ConfigureDevice is not implemented"))
 }
 
+func (UnimplementedDeviceServiceHandler) GetDeviceConfig(context.Context, *connect.Request[v1.GetDeviceConfigRequest]) (*connect.Response[v1.GetDeviceConfigResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.GetDeviceConfig is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) UpdateDeviceConfig(context.Context, *connect.Request[v1.UpdateDeviceConfigRequest]) (*connect.Response[v1.UpdateDeviceConfigResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.UpdateDeviceConfig is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) GetDeviceMetrics(context.Context, *connect.Request[v1.GetDeviceMetricsRequest]) (*connect.Response[v1.GetDeviceMetricsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.GetDeviceMetrics is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) GetDeviceMetricAggregates(context.Context, *connect.Request[v1.GetDeviceMetricAggregatesRequest]) (*connect.Response[v1.GetDeviceMetricAggregatesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.GetDeviceMetricAggregates is not implemented"))
+}
+
 func (UnimplementedDeviceServiceHandler) GetDeviceAlerts(context.Context, *connect.Request[v1.GetDeviceAlertsRequest]) (*connect.Response[v1.GetDeviceAlertsResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.GetDeviceAlerts is not implemented"))
 }
+
+func (UnimplementedDeviceServiceHandler) SearchAlerts(context.Context, *connect.Request[v1.SearchAlertsRequest]) (*connect.Response[v1.SearchAlertsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.SearchAlerts is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) GetAlertStats(context.Context, *connect.Request[v1.GetAlertStatsRequest]) (*connect.Response[v1.GetAlertStatsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.GetAlertStats is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) WatchDeviceAlerts(context.Context, *connect.Request[v1.WatchDeviceAlertsRequest], *connect.ServerStream[v1.WatchDeviceAlertsResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.WatchDeviceAlerts is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) CreateAlertRule(context.Context, *connect.Request[v1.CreateAlertRuleRequest]) (*connect.Response[v1.CreateAlertRuleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.CreateAlertRule is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) GetAlertRule(context.Context, *connect.Request[v1.GetAlertRuleRequest]) (*connect.Response[v1.GetAlertRuleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.GetAlertRule is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ListAlertRules(context.Context, *connect.Request[v1.ListAlertRulesRequest]) (*connect.Response[v1.ListAlertRulesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.ListAlertRules is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) UpdateAlertRule(context.Context, *connect.Request[v1.UpdateAlertRuleRequest]) (*connect.Response[v1.UpdateAlertRuleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.UpdateAlertRule is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) DeleteAlertRule(context.Context, *connect.Request[v1.DeleteAlertRuleRequest]) (*connect.Response[v1.DeleteAlertRuleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.DeleteAlertRule is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) AcknowledgeAlert(context.Context, *connect.Request[v1.AcknowledgeAlertRequest]) (*connect.Response[v1.AcknowledgeAlertResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.AcknowledgeAlert is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ResolveAlert(context.Context, *connect.Request[v1.ResolveAlertRequest]) (*connect.Response[v1.ResolveAlertResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.ResolveAlert is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) CreateDevice(context.Context, *connect.Request[v1.CreateDeviceRequest]) (*connect.Response[v1.CreateDeviceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.CreateDevice is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) GetDevice(context.Context, *connect.Request[v1.GetDeviceRequest]) (*connect.Response[v1.GetDeviceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.GetDevice is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) UpdateDevice(context.Context, *connect.Request[v1.UpdateDeviceRequest]) (*connect.Response[v1.UpdateDeviceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.UpdateDevice is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ListDevices(context.Context, *connect.Request[v1.ListDevicesRequest]) (*connect.Response[v1.ListDevicesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.ListDevices is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) GetFleetSnapshot(context.Context, *connect.Request[v1.GetFleetSnapshotRequest]) (*connect.Response[v1.GetFleetSnapshotResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.GetFleetSnapshot is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) DeleteDevice(context.Context, *connect.Request[v1.DeleteDeviceRequest]) (*connect.Response[v1.DeleteDeviceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.DeleteDevice is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) GetOperation(context.Context, *connect.Request[v1.GetOperationRequest]) (*connect.Response[v1.GetOperationResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.GetOperation is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) CreateDeviceGroup(context.Context, *connect.Request[v1.CreateDeviceGroupRequest]) (*connect.Response[v1.CreateDeviceGroupResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.CreateDeviceGroup is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) GetDeviceGroup(context.Context, *connect.Request[v1.GetDeviceGroupRequest]) (*connect.Response[v1.GetDeviceGroupResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.GetDeviceGroup is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ListDeviceGroups(context.Context, *connect.Request[v1.ListDeviceGroupsRequest]) (*connect.Response[v1.ListDeviceGroupsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.ListDeviceGroups is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ConfigureDeviceGroup(context.Context, *connect.Request[v1.ConfigureDeviceGroupRequest]) (*connect.Response[v1.ConfigureDeviceGroupResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.ConfigureDeviceGroup is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ConfigureDefaults(context.Context, *connect.Request[v1.ConfigureDefaultsRequest]) (*connect.Response[v1.ConfigureDefaultsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.ConfigureDefaults is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) GetEffectiveDeviceConfig(context.Context, *connect.Request[v1.GetEffectiveDeviceConfigRequest]) (*connect.Response[v1.GetEffectiveDeviceConfigResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.GetEffectiveDeviceConfig is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ListDeviceConfigVersions(context.Context, *connect.Request[v1.ListDeviceConfigVersionsRequest]) (*connect.Response[v1.ListDeviceConfigVersionsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.ListDeviceConfigVersions is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) RollbackDeviceConfig(context.Context, *connect.Request[v1.RollbackDeviceConfigRequest]) (*connect.Response[v1.RollbackDeviceConfigResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("iot.v1.DeviceService.RollbackDeviceConfig is not implemented"))
+}
@@ -9,6 +9,7 @@ package iotv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -22,12 +23,236 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ConfigLevel is a level that config settings are resolved from, in order of
+// precedence.
+type ConfigLevel int32
+
+const (
+	ConfigLevel_CONFIG_LEVEL_UNSPECIFIED ConfigLevel = 0
+	ConfigLevel_CONFIG_LEVEL_DEVICE      ConfigLevel = 1
+	ConfigLevel_CONFIG_LEVEL_GROUP       ConfigLevel = 2
+	ConfigLevel_CONFIG_LEVEL_GLOBAL      ConfigLevel = 3
+)
+
+// Enum value maps for ConfigLevel.
+var (
+	ConfigLevel_name = map[int32]string{
+		0: "CONFIG_LEVEL_UNSPECIFIED",
+		1: "CONFIG_LEVEL_DEVICE",
+		2: "CONFIG_LEVEL_GROUP",
+		3: "CONFIG_LEVEL_GLOBAL",
+	}
+	ConfigLevel_value = map[string]int32{
+		"CONFIG_LEVEL_UNSPECIFIED": 0,
+		"CONFIG_LEVEL_DEVICE":      1,
+		"CONFIG_LEVEL_GROUP":       2,
+		"CONFIG_LEVEL_GLOBAL":      3,
+	}
+)
+
+func (x ConfigLevel) Enum() *ConfigLevel {
+	p := new(ConfigLevel)
+	*p = x
+	return p
+}
+
+func (x ConfigLevel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ConfigLevel) Descriptor() protoreflect.EnumDescriptor {
+	return file_iot_v1_service_proto_enumTypes[0].Descriptor()
+}
+
+func (ConfigLevel) Type() protoreflect.EnumType {
+	return &file_iot_v1_service_proto_enumTypes[0]
+}
+
+func (x ConfigLevel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ConfigLevel.Descriptor instead.
+func (ConfigLevel) EnumDescriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{0}
+}
+
+// BucketWidth is the width of the time buckets that metrics and alerts are
+// aggregated in.
+type BucketWidth int32
+
+const (
+	BucketWidth_BUCKET_WIDTH_UNSPECIFIED BucketWidth = 0
+	BucketWidth_BUCKET_WIDTH_1M          BucketWidth = 1
+	BucketWidth_BUCKET_WIDTH_5M          BucketWidth = 2
+	BucketWidth_BUCKET_WIDTH_1H          BucketWidth = 3
+	BucketWidth_BUCKET_WIDTH_1D          BucketWidth = 4
+)
+
+// Enum value maps for BucketWidth.
+var (
+	BucketWidth_name = map[int32]string{
+		0: "BUCKET_WIDTH_UNSPECIFIED",
+		1: "BUCKET_WIDTH_1M",
+		2: "BUCKET_WIDTH_5M",
+		3: "BUCKET_WIDTH_1H",
+		4: "BUCKET_WIDTH_1D",
+	}
+	BucketWidth_value = map[string]int32{
+		"BUCKET_WIDTH_UNSPECIFIED": 0,
+		"BUCKET_WIDTH_1M":          1,
+		"BUCKET_WIDTH_5M":          2,
+		"BUCKET_WIDTH_1H":          3,
+		"BUCKET_WIDTH_1D":          4,
+	}
+)
+
+func (x BucketWidth) Enum() *BucketWidth {
+	p := new(BucketWidth)
+	*p = x
+	return p
+}
+
+func (x BucketWidth) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BucketWidth) Descriptor() protoreflect.EnumDescriptor {
+	return file_iot_v1_service_proto_enumTypes[1].Descriptor()
+}
+
+func (BucketWidth) Type() protoreflect.EnumType {
+	return &file_iot_v1_service_proto_enumTypes[1]
+}
+
+func (x BucketWidth) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BucketWidth.Descriptor instead.
+func (BucketWidth) EnumDescriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{1}
+}
+
+// SortOrder is the order that time-series data is returned in by time. Page
+// tokens can only be used with the order of the request that returned them.
+type SortOrder int32
+
+const (
+	SortOrder_SORT_ORDER_UNSPECIFIED SortOrder = 0
+	SortOrder_SORT_ORDER_DESC        SortOrder = 1
+	SortOrder_SORT_ORDER_ASC         SortOrder = 2
+)
+
+// Enum value maps for SortOrder.
+var (
+	SortOrder_name = map[int32]string{
+		0: "SORT_ORDER_UNSPECIFIED",
+		1: "SORT_ORDER_DESC",
+		2: "SORT_ORDER_ASC",
+	}
+	SortOrder_value = map[string]int32{
+		"SORT_ORDER_UNSPECIFIED": 0,
+		"SORT_ORDER_DESC":        1,
+		"SORT_ORDER_ASC":         2,
+	}
+)
+
+func (x SortOrder) Enum() *SortOrder {
+	p := new(SortOrder)
+	*p = x
+	return p
+}
+
+func (x SortOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_iot_v1_service_proto_enumTypes[2].Descriptor()
+}
+
+func (SortOrder) Type() protoreflect.EnumType {
+	return &file_iot_v1_service_proto_enumTypes[2]
+}
+
+func (x SortOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortOrder.Descriptor instead.
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{2}
+}
+
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0
+	Severity_SEVERITY_INFO        Severity = 1
+	Severity_SEVERITY_WARNING     Severity = 2
+	Severity_SEVERITY_CRITICAL    Severity = 3
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_INFO",
+		2: "SEVERITY_WARNING",
+		3: "SEVERITY_CRITICAL",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_INFO":        1,
+		"SEVERITY_WARNING":     2,
+		"SEVERITY_CRITICAL":    3,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_iot_v1_service_proto_enumTypes[3].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_iot_v1_service_proto_enumTypes[3]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{3}
+}
+
 type Alert_Reason int32
 
 const (
 	Alert_REASON_UNSPECIFIED      Alert_Reason = 0
 	Alert_REASON_TEMPERATURE_HIGH Alert_Reason = 1
 	Alert_REASON_BATTERY_LOW      Alert_Reason = 2
+	// A rule other than a high temperature or low battery rule was breached.
+	Alert_REASON_THRESHOLD_BREACHED Alert_Reason = 3
+	// An alert expression of the device config matched.
+	Alert_REASON_EXPRESSION_MATCHED Alert_Reason = 4
+	// A RISE rule was breached.
+	Alert_REASON_RAPID_RISE Alert_Reason = 5
+	// A DROP rule was breached.
+	Alert_REASON_RAPID_DROP Alert_Reason = 6
+	// No metric was recorded within the device's expected reporting interval.
+	Alert_REASON_DEVICE_OFFLINE Alert_Reason = 7
 )
 
 // Enum value maps for Alert_Reason.
@@ -36,11 +261,21 @@ var (
 		0: "REASON_UNSPECIFIED",
 		1: "REASON_TEMPERATURE_HIGH",
 		2: "REASON_BATTERY_LOW",
+		3: "REASON_THRESHOLD_BREACHED",
+		4: "REASON_EXPRESSION_MATCHED",
+		5: "REASON_RAPID_RISE",
+		6: "REASON_RAPID_DROP",
+		7: "REASON_DEVICE_OFFLINE",
 	}
 	Alert_Reason_value = map[string]int32{
-		"REASON_UNSPECIFIED":      0,
-		"REASON_TEMPERATURE_HIGH": 1,
-		"REASON_BATTERY_LOW":      2,
+		"REASON_UNSPECIFIED":        0,
+		"REASON_TEMPERATURE_HIGH":   1,
+		"REASON_BATTERY_LOW":        2,
+		"REASON_THRESHOLD_BREACHED": 3,
+		"REASON_EXPRESSION_MATCHED": 4,
+		"REASON_RAPID_RISE":         5,
+		"REASON_RAPID_DROP":         6,
+		"REASON_DEVICE_OFFLINE":     7,
 	}
 )
 
@@ -55,11 +290,11 @@ func (x Alert_Reason) String() string {
 }
 
 func (Alert_Reason) Descriptor() protoreflect.EnumDescriptor {
-	return file_iot_v1_service_proto_enumTypes[0].Descriptor()
+	return file_iot_v1_service_proto_enumTypes[4].Descriptor()
 }
 
 func (Alert_Reason) Type() protoreflect.EnumType {
-	return &file_iot_v1_service_proto_enumTypes[0]
+	return &file_iot_v1_service_proto_enumTypes[4]
 }
 
 func (x Alert_Reason) Number() protoreflect.EnumNumber {
@@ -68,15 +303,248 @@ func (x Alert_Reason) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Alert_Reason.Descriptor instead.
 func (Alert_Reason) EnumDescriptor() ([]byte, []int) {
-	return file_iot_v1_service_proto_rawDescGZIP(), []int{7, 0}
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{79, 0}
+}
+
+type Alert_State int32
+
+const (
+	Alert_STATE_UNSPECIFIED Alert_State = 0
+	// The alert was triggered and has not been acknowledged or resolved.
+	Alert_STATE_OPEN Alert_State = 1
+	// An operator acknowledged the alert, but its condition has not cleared.
+	Alert_STATE_ACKNOWLEDGED Alert_State = 2
+	// The alert's condition cleared or an operator resolved the alert.
+	Alert_STATE_RESOLVED Alert_State = 3
+)
+
+// Enum value maps for Alert_State.
+var (
+	Alert_State_name = map[int32]string{
+		0: "STATE_UNSPECIFIED",
+		1: "STATE_OPEN",
+		2: "STATE_ACKNOWLEDGED",
+		3: "STATE_RESOLVED",
+	}
+	Alert_State_value = map[string]int32{
+		"STATE_UNSPECIFIED":  0,
+		"STATE_OPEN":         1,
+		"STATE_ACKNOWLEDGED": 2,
+		"STATE_RESOLVED":     3,
+	}
+)
+
+func (x Alert_State) Enum() *Alert_State {
+	p := new(Alert_State)
+	*p = x
+	return p
+}
+
+func (x Alert_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Alert_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_iot_v1_service_proto_enumTypes[5].Descriptor()
+}
+
+func (Alert_State) Type() protoreflect.EnumType {
+	return &file_iot_v1_service_proto_enumTypes[5]
+}
+
+func (x Alert_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Alert_State.Descriptor instead.
+func (Alert_State) EnumDescriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{79, 1}
+}
+
+type AlertRule_Operator int32
+
+const (
+	AlertRule_OPERATOR_UNSPECIFIED           AlertRule_Operator = 0
+	AlertRule_OPERATOR_GREATER_THAN          AlertRule_Operator = 1
+	AlertRule_OPERATOR_GREATER_THAN_OR_EQUAL AlertRule_Operator = 2
+	AlertRule_OPERATOR_LESS_THAN             AlertRule_Operator = 3
+	AlertRule_OPERATOR_LESS_THAN_OR_EQUAL    AlertRule_Operator = 4
+	AlertRule_OPERATOR_EQUAL                 AlertRule_Operator = 5
+	// Breached when the value is outside the inclusive range.
+	AlertRule_OPERATOR_OUTSIDE_RANGE AlertRule_Operator = 6
+	// Breached when the value is inside the inclusive range.
+	AlertRule_OPERATOR_INSIDE_RANGE AlertRule_Operator = 7
+	// Breached when the value rose by more than the threshold from the lowest
+	// value recorded within the window.
+	AlertRule_OPERATOR_RISE AlertRule_Operator = 8
+	// Breached when the value dropped by more than the threshold from the
+	// highest value recorded within the window.
+	AlertRule_OPERATOR_DROP AlertRule_Operator = 9
+)
+
+// Enum value maps for AlertRule_Operator.
+var (
+	AlertRule_Operator_name = map[int32]string{
+		0: "OPERATOR_UNSPECIFIED",
+		1: "OPERATOR_GREATER_THAN",
+		2: "OPERATOR_GREATER_THAN_OR_EQUAL",
+		3: "OPERATOR_LESS_THAN",
+		4: "OPERATOR_LESS_THAN_OR_EQUAL",
+		5: "OPERATOR_EQUAL",
+		6: "OPERATOR_OUTSIDE_RANGE",
+		7: "OPERATOR_INSIDE_RANGE",
+		8: "OPERATOR_RISE",
+		9: "OPERATOR_DROP",
+	}
+	AlertRule_Operator_value = map[string]int32{
+		"OPERATOR_UNSPECIFIED":           0,
+		"OPERATOR_GREATER_THAN":          1,
+		"OPERATOR_GREATER_THAN_OR_EQUAL": 2,
+		"OPERATOR_LESS_THAN":             3,
+		"OPERATOR_LESS_THAN_OR_EQUAL":    4,
+		"OPERATOR_EQUAL":                 5,
+		"OPERATOR_OUTSIDE_RANGE":         6,
+		"OPERATOR_INSIDE_RANGE":          7,
+		"OPERATOR_RISE":                  8,
+		"OPERATOR_DROP":                  9,
+	}
+)
+
+func (x AlertRule_Operator) Enum() *AlertRule_Operator {
+	p := new(AlertRule_Operator)
+	*p = x
+	return p
+}
+
+func (x AlertRule_Operator) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertRule_Operator) Descriptor() protoreflect.EnumDescriptor {
+	return file_iot_v1_service_proto_enumTypes[6].Descriptor()
+}
+
+func (AlertRule_Operator) Type() protoreflect.EnumType {
+	return &file_iot_v1_service_proto_enumTypes[6]
+}
+
+func (x AlertRule_Operator) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertRule_Operator.Descriptor instead.
+func (AlertRule_Operator) EnumDescriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{84, 0}
+}
+
+type Operation_Type int32
+
+const (
+	Operation_TYPE_UNSPECIFIED Operation_Type = 0
+	// Purges the metrics and alerts of a deleted device.
+	Operation_TYPE_DELETE_DEVICE Operation_Type = 1
+)
+
+// Enum value maps for Operation_Type.
+var (
+	Operation_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_DELETE_DEVICE",
+	}
+	Operation_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED":   0,
+		"TYPE_DELETE_DEVICE": 1,
+	}
+)
+
+func (x Operation_Type) Enum() *Operation_Type {
+	p := new(Operation_Type)
+	*p = x
+	return p
+}
+
+func (x Operation_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Operation_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_iot_v1_service_proto_enumTypes[7].Descriptor()
+}
+
+func (Operation_Type) Type() protoreflect.EnumType {
+	return &file_iot_v1_service_proto_enumTypes[7]
+}
+
+func (x Operation_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Operation_Type.Descriptor instead.
+func (Operation_Type) EnumDescriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{85, 0}
+}
+
+type Operation_State int32
+
+const (
+	Operation_STATE_UNSPECIFIED Operation_State = 0
+	Operation_STATE_RUNNING     Operation_State = 1
+	Operation_STATE_SUCCEEDED   Operation_State = 2
+	Operation_STATE_FAILED      Operation_State = 3
+)
+
+// Enum value maps for Operation_State.
+var (
+	Operation_State_name = map[int32]string{
+		0: "STATE_UNSPECIFIED",
+		1: "STATE_RUNNING",
+		2: "STATE_SUCCEEDED",
+		3: "STATE_FAILED",
+	}
+	Operation_State_value = map[string]int32{
+		"STATE_UNSPECIFIED": 0,
+		"STATE_RUNNING":     1,
+		"STATE_SUCCEEDED":   2,
+		"STATE_FAILED":      3,
+	}
+)
+
+func (x Operation_State) Enum() *Operation_State {
+	p := new(Operation_State)
+	*p = x
+	return p
+}
+
+func (x Operation_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Operation_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_iot_v1_service_proto_enumTypes[8].Descriptor()
+}
+
+func (Operation_State) Type() protoreflect.EnumType {
+	return &file_iot_v1_service_proto_enumTypes[8]
+}
+
+func (x Operation_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Operation_State.Descriptor instead.
+func (Operation_State) EnumDescriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{85, 1}
 }
 
 type RecordMetricRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Temperature   float64                `protobuf:"fixed64,3,opt,name=temperature,proto3" json:"temperature,omitempty"`
-	Battery       int32                  `protobuf:"varint,4,opt,name=battery,proto3" json:"battery,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId  string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Deprecated: use values instead. Ignored if values is not empty.
+	Temperature float64 `protobuf:"fixed64,3,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	// Deprecated: use values instead. Ignored if values is not empty.
+	Battery       int32          `protobuf:"varint,4,opt,name=battery,proto3" json:"battery,omitempty"`
+	Values        []*MetricValue `protobuf:"bytes,5,rep,name=values,proto3" json:"values,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -139,27 +607,4545 @@ func (x *RecordMetricRequest) GetBattery() int32 {
 	return 0
 }
 
+func (x *RecordMetricRequest) GetValues() []*MetricValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
 type RecordMetricResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RecordMetricResponse) Reset() {
-	*x = RecordMetricResponse{}
-	mi := &file_iot_v1_service_proto_msgTypes[1]
+func (x *RecordMetricResponse) Reset() {
+	*x = RecordMetricResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordMetricResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordMetricResponse) ProtoMessage() {}
+
+func (x *RecordMetricResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordMetricResponse.ProtoReflect.Descriptor instead.
+func (*RecordMetricResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{1}
+}
+
+type RecordMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Metrics       []*Metric              `protobuf:"bytes,2,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordMetricsRequest) Reset() {
+	*x = RecordMetricsRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordMetricsRequest) ProtoMessage() {}
+
+func (x *RecordMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordMetricsRequest.ProtoReflect.Descriptor instead.
+func (*RecordMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RecordMetricsRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *RecordMetricsRequest) GetMetrics() []*Metric {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+type RecordMetricsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Recorded      int32                  `protobuf:"varint,1,opt,name=recorded,proto3" json:"recorded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordMetricsResponse) Reset() {
+	*x = RecordMetricsResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordMetricsResponse) ProtoMessage() {}
+
+func (x *RecordMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordMetricsResponse.ProtoReflect.Descriptor instead.
+func (*RecordMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RecordMetricsResponse) GetRecorded() int32 {
+	if x != nil {
+		return x.Recorded
+	}
+	return 0
+}
+
+type StreamMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Metric        *Metric                `protobuf:"bytes,2,opt,name=metric,proto3" json:"metric,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamMetricsRequest) Reset() {
+	*x = StreamMetricsRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamMetricsRequest) ProtoMessage() {}
+
+func (x *StreamMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamMetricsRequest.ProtoReflect.Descriptor instead.
+func (*StreamMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StreamMetricsRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *StreamMetricsRequest) GetMetric() *Metric {
+	if x != nil {
+		return x.Metric
+	}
+	return nil
+}
+
+type StreamMetricsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Total number of metrics recorded on the stream so far.
+	Acknowledged int64 `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
+	// Timestamp of the most recently recorded metric.
+	LastTimestamp *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_timestamp,json=lastTimestamp,proto3" json:"last_timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamMetricsResponse) Reset() {
+	*x = StreamMetricsResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamMetricsResponse) ProtoMessage() {}
+
+func (x *StreamMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamMetricsResponse.ProtoReflect.Descriptor instead.
+func (*StreamMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamMetricsResponse) GetAcknowledged() int64 {
+	if x != nil {
+		return x.Acknowledged
+	}
+	return 0
+}
+
+func (x *StreamMetricsResponse) GetLastTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastTimestamp
+	}
+	return nil
+}
+
+// ConfigureDeviceRequest sets the config of a device, replacing any existing
+// device config. Settings that are unset, including empty lists, are inherited
+// from the device group or the global defaults.
+type ConfigureDeviceRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId             string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	TemperatureThreshold *float64               `protobuf:"fixed64,2,opt,name=temperature_threshold,json=temperatureThreshold,proto3,oneof" json:"temperature_threshold,omitempty"`
+	BatteryThreshold     *int32                 `protobuf:"varint,3,opt,name=battery_threshold,json=batteryThreshold,proto3,oneof" json:"battery_threshold,omitempty"`
+	// Optional CEL expressions that trigger an alert when they evaluate to true
+	// for a recorded metric.
+	Expressions []*AlertExpression `protobuf:"bytes,4,rep,name=expressions,proto3" json:"expressions,omitempty"`
+	// Number of consecutive readings that must breach a rule or match an
+	// expression before an alert is triggered. When set, or when hysteresis is
+	// set, a condition triggers a single alert until it clears.
+	ConsecutiveBreaches *int32 `protobuf:"varint,5,opt,name=consecutive_breaches,json=consecutiveBreaches,proto3,oneof" json:"consecutive_breaches,omitempty"`
+	// Margin by which a value must return within the threshold of a triggered
+	// rule before it clears and can trigger again.
+	Hysteresis *float64 `protobuf:"fixed64,6,opt,name=hysteresis,proto3,oneof" json:"hysteresis,omitempty"`
+	// Optional cooldown windows by alert reason.
+	Cooldowns []*AlertCooldown `protobuf:"bytes,7,rep,name=cooldowns,proto3" json:"cooldowns,omitempty"`
+	// Expected interval between metrics of the device. When set, a
+	// DEVICE_OFFLINE alert is triggered if no metric is recorded within the
+	// interval times the server's grace factor.
+	ReportingIntervalSeconds *int64 `protobuf:"varint,8,opt,name=reporting_interval_seconds,json=reportingIntervalSeconds,proto3,oneof" json:"reporting_interval_seconds,omitempty"`
+	// Optional operator making the change, recorded in the config version.
+	Author string `protobuf:"bytes,9,opt,name=author,proto3" json:"author,omitempty"`
+	// Optional reason for the change, recorded in the config version.
+	Reason string `protobuf:"bytes,10,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Optional revision, the version number of the current config (0 if the
+	// device has not been configured), that the change is conditional on. The
+	// change fails with FAILED_PRECONDITION if the config has since changed.
+	ExpectedRevision *int64 `protobuf:"varint,11,opt,name=expected_revision,json=expectedRevision,proto3,oneof" json:"expected_revision,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ConfigureDeviceRequest) Reset() {
+	*x = ConfigureDeviceRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigureDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigureDeviceRequest) ProtoMessage() {}
+
+func (x *ConfigureDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigureDeviceRequest.ProtoReflect.Descriptor instead.
+func (*ConfigureDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ConfigureDeviceRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ConfigureDeviceRequest) GetTemperatureThreshold() float64 {
+	if x != nil && x.TemperatureThreshold != nil {
+		return *x.TemperatureThreshold
+	}
+	return 0
+}
+
+func (x *ConfigureDeviceRequest) GetBatteryThreshold() int32 {
+	if x != nil && x.BatteryThreshold != nil {
+		return *x.BatteryThreshold
+	}
+	return 0
+}
+
+func (x *ConfigureDeviceRequest) GetExpressions() []*AlertExpression {
+	if x != nil {
+		return x.Expressions
+	}
+	return nil
+}
+
+func (x *ConfigureDeviceRequest) GetConsecutiveBreaches() int32 {
+	if x != nil && x.ConsecutiveBreaches != nil {
+		return *x.ConsecutiveBreaches
+	}
+	return 0
+}
+
+func (x *ConfigureDeviceRequest) GetHysteresis() float64 {
+	if x != nil && x.Hysteresis != nil {
+		return *x.Hysteresis
+	}
+	return 0
+}
+
+func (x *ConfigureDeviceRequest) GetCooldowns() []*AlertCooldown {
+	if x != nil {
+		return x.Cooldowns
+	}
+	return nil
+}
+
+func (x *ConfigureDeviceRequest) GetReportingIntervalSeconds() int64 {
+	if x != nil && x.ReportingIntervalSeconds != nil {
+		return *x.ReportingIntervalSeconds
+	}
+	return 0
+}
+
+func (x *ConfigureDeviceRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *ConfigureDeviceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ConfigureDeviceRequest) GetExpectedRevision() int64 {
+	if x != nil && x.ExpectedRevision != nil {
+		return *x.ExpectedRevision
+	}
+	return 0
+}
+
+// AlertCooldown suppresses new alerts of a reason for a window after an alert
+// of a rule or expression is resolved. Triggers within the window are dropped.
+type AlertCooldown struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        Alert_Reason           `protobuf:"varint,1,opt,name=reason,proto3,enum=iot.v1.Alert_Reason" json:"reason,omitempty"`
+	WindowSeconds int64                  `protobuf:"varint,2,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertCooldown) Reset() {
+	*x = AlertCooldown{}
+	mi := &file_iot_v1_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertCooldown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertCooldown) ProtoMessage() {}
+
+func (x *AlertCooldown) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertCooldown.ProtoReflect.Descriptor instead.
+func (*AlertCooldown) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AlertCooldown) GetReason() Alert_Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return Alert_REASON_UNSPECIFIED
+}
+
+func (x *AlertCooldown) GetWindowSeconds() int64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+type ConfigureDeviceResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The revision of the config, its new version number.
+	Revision      int64 `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigureDeviceResponse) Reset() {
+	*x = ConfigureDeviceResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigureDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigureDeviceResponse) ProtoMessage() {}
+
+func (x *ConfigureDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigureDeviceResponse.ProtoReflect.Descriptor instead.
+func (*ConfigureDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ConfigureDeviceResponse) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type GetDeviceConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceConfigRequest) Reset() {
+	*x = GetDeviceConfigRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceConfigRequest) ProtoMessage() {}
+
+func (x *GetDeviceConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetDeviceConfigRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetDeviceConfigRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+type GetDeviceConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        *DeviceConfigVersion   `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceConfigResponse) Reset() {
+	*x = GetDeviceConfigResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceConfigResponse) ProtoMessage() {}
+
+func (x *GetDeviceConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceConfigResponse.ProtoReflect.Descriptor instead.
+func (*GetDeviceConfigResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetDeviceConfigResponse) GetConfig() *DeviceConfigVersion {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type UpdateDeviceConfigRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// The settings to update. Settings in the update mask that are unset are
+	// cleared so that they are inherited, while lists in the update mask that
+	// are empty override the inherited lists.
+	Settings *ConfigSettings `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	// The settings to update, e.g. battery_threshold. Must not be empty.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// Optional operator making the change, recorded in the config version.
+	Author string `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
+	// Optional reason for the change, recorded in the config version.
+	Reason string `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Optional revision, the version number of the current config (0 if the
+	// device has not been configured), that the change is conditional on. The
+	// change fails with FAILED_PRECONDITION if the config has since changed.
+	ExpectedRevision *int64 `protobuf:"varint,6,opt,name=expected_revision,json=expectedRevision,proto3,oneof" json:"expected_revision,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UpdateDeviceConfigRequest) Reset() {
+	*x = UpdateDeviceConfigRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDeviceConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDeviceConfigRequest) ProtoMessage() {}
+
+func (x *UpdateDeviceConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDeviceConfigRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDeviceConfigRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateDeviceConfigRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *UpdateDeviceConfigRequest) GetSettings() *ConfigSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *UpdateDeviceConfigRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateDeviceConfigRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *UpdateDeviceConfigRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *UpdateDeviceConfigRequest) GetExpectedRevision() int64 {
+	if x != nil && x.ExpectedRevision != nil {
+		return *x.ExpectedRevision
+	}
+	return 0
+}
+
+type UpdateDeviceConfigResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The version created by the update.
+	Config        *DeviceConfigVersion `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDeviceConfigResponse) Reset() {
+	*x = UpdateDeviceConfigResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDeviceConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDeviceConfigResponse) ProtoMessage() {}
+
+func (x *UpdateDeviceConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDeviceConfigResponse.ProtoReflect.Descriptor instead.
+func (*UpdateDeviceConfigResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateDeviceConfigResponse) GetConfig() *DeviceConfigVersion {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type GetDeviceMetricsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId  string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Timeframe *Timeframe             `protobuf:"bytes,2,opt,name=timeframe,proto3,oneof" json:"timeframe,omitempty"`
+	PageSize  int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Order to return metrics in by time, most recent first if unspecified.
+	Order         SortOrder `protobuf:"varint,5,opt,name=order,proto3,enum=iot.v1.SortOrder" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceMetricsRequest) Reset() {
+	*x = GetDeviceMetricsRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceMetricsRequest) ProtoMessage() {}
+
+func (x *GetDeviceMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetDeviceMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetDeviceMetricsRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *GetDeviceMetricsRequest) GetTimeframe() *Timeframe {
+	if x != nil {
+		return x.Timeframe
+	}
+	return nil
+}
+
+func (x *GetDeviceMetricsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetDeviceMetricsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetDeviceMetricsRequest) GetOrder() SortOrder {
+	if x != nil {
+		return x.Order
+	}
+	return SortOrder_SORT_ORDER_UNSPECIFIED
+}
+
+type GetDeviceMetricsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metrics       []*Metric              `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceMetricsResponse) Reset() {
+	*x = GetDeviceMetricsResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceMetricsResponse) ProtoMessage() {}
+
+func (x *GetDeviceMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceMetricsResponse.ProtoReflect.Descriptor instead.
+func (*GetDeviceMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetDeviceMetricsResponse) GetMetrics() []*Metric {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *GetDeviceMetricsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetDeviceMetricAggregatesRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// The timeframe to aggregate, which must have a start and an end.
+	Timeframe   *Timeframe  `protobuf:"bytes,2,opt,name=timeframe,proto3" json:"timeframe,omitempty"`
+	BucketWidth BucketWidth `protobuf:"varint,3,opt,name=bucket_width,json=bucketWidth,proto3,enum=iot.v1.BucketWidth" json:"bucket_width,omitempty"`
+	// Whether to include buckets without any values, with a count of 0 and
+	// unset aggregates.
+	FillEmpty     bool `protobuf:"varint,4,opt,name=fill_empty,json=fillEmpty,proto3" json:"fill_empty,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceMetricAggregatesRequest) Reset() {
+	*x = GetDeviceMetricAggregatesRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceMetricAggregatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceMetricAggregatesRequest) ProtoMessage() {}
+
+func (x *GetDeviceMetricAggregatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceMetricAggregatesRequest.ProtoReflect.Descriptor instead.
+func (*GetDeviceMetricAggregatesRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetDeviceMetricAggregatesRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *GetDeviceMetricAggregatesRequest) GetTimeframe() *Timeframe {
+	if x != nil {
+		return x.Timeframe
+	}
+	return nil
+}
+
+func (x *GetDeviceMetricAggregatesRequest) GetBucketWidth() BucketWidth {
+	if x != nil {
+		return x.BucketWidth
+	}
+	return BucketWidth_BUCKET_WIDTH_UNSPECIFIED
+}
+
+func (x *GetDeviceMetricAggregatesRequest) GetFillEmpty() bool {
+	if x != nil {
+		return x.FillEmpty
+	}
+	return false
+}
+
+type GetDeviceMetricAggregatesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The aggregates of each metric recorded in the timeframe, ordered by name.
+	Metrics       []*MetricAggregates `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceMetricAggregatesResponse) Reset() {
+	*x = GetDeviceMetricAggregatesResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceMetricAggregatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceMetricAggregatesResponse) ProtoMessage() {}
+
+func (x *GetDeviceMetricAggregatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceMetricAggregatesResponse.ProtoReflect.Descriptor instead.
+func (*GetDeviceMetricAggregatesResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetDeviceMetricAggregatesResponse) GetMetrics() []*MetricAggregates {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+type GetDeviceAlertsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId  string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Timeframe *Timeframe             `protobuf:"bytes,2,opt,name=timeframe,proto3,oneof" json:"timeframe,omitempty"`
+	PageSize  int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Optional state to filter alerts by.
+	State Alert_State `protobuf:"varint,5,opt,name=state,proto3,enum=iot.v1.Alert_State" json:"state,omitempty"`
+	// Optional reasons to filter alerts by.
+	Reasons []Alert_Reason `protobuf:"varint,6,rep,packed,name=reasons,proto3,enum=iot.v1.Alert_Reason" json:"reasons,omitempty"`
+	// Order to return alerts in by time, most recent first if unspecified.
+	Order         SortOrder `protobuf:"varint,7,opt,name=order,proto3,enum=iot.v1.SortOrder" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceAlertsRequest) Reset() {
+	*x = GetDeviceAlertsRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceAlertsRequest) ProtoMessage() {}
+
+func (x *GetDeviceAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceAlertsRequest.ProtoReflect.Descriptor instead.
+func (*GetDeviceAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetDeviceAlertsRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *GetDeviceAlertsRequest) GetTimeframe() *Timeframe {
+	if x != nil {
+		return x.Timeframe
+	}
+	return nil
+}
+
+func (x *GetDeviceAlertsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetDeviceAlertsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetDeviceAlertsRequest) GetState() Alert_State {
+	if x != nil {
+		return x.State
+	}
+	return Alert_STATE_UNSPECIFIED
+}
+
+func (x *GetDeviceAlertsRequest) GetReasons() []Alert_Reason {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *GetDeviceAlertsRequest) GetOrder() SortOrder {
+	if x != nil {
+		return x.Order
+	}
+	return SortOrder_SORT_ORDER_UNSPECIFIED
+}
+
+type GetDeviceAlertsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alerts        []*Alert               `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceAlertsResponse) Reset() {
+	*x = GetDeviceAlertsResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceAlertsResponse) ProtoMessage() {}
+
+func (x *GetDeviceAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceAlertsResponse.ProtoReflect.Descriptor instead.
+func (*GetDeviceAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetDeviceAlertsResponse) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+func (x *GetDeviceAlertsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type SearchAlertsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional devices to search the alerts of. Alerts of all devices are
+	// searched if empty.
+	DeviceIds []string `protobuf:"bytes,1,rep,name=device_ids,json=deviceIds,proto3" json:"device_ids,omitempty"`
+	// Optional label selector that the devices of the alerts must meet.
+	LabelSelector string `protobuf:"bytes,2,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	// Optional reasons to filter alerts by.
+	Reasons []Alert_Reason `protobuf:"varint,3,rep,packed,name=reasons,proto3,enum=iot.v1.Alert_Reason" json:"reasons,omitempty"`
+	// Optional severity to filter alerts by.
+	Severity Severity `protobuf:"varint,4,opt,name=severity,proto3,enum=iot.v1.Severity" json:"severity,omitempty"`
+	// Optional state to filter alerts by.
+	State         Alert_State `protobuf:"varint,5,opt,name=state,proto3,enum=iot.v1.Alert_State" json:"state,omitempty"`
+	Timeframe     *Timeframe  `protobuf:"bytes,6,opt,name=timeframe,proto3,oneof" json:"timeframe,omitempty"`
+	PageSize      int32       `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string      `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchAlertsRequest) Reset() {
+	*x = SearchAlertsRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchAlertsRequest) ProtoMessage() {}
+
+func (x *SearchAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchAlertsRequest.ProtoReflect.Descriptor instead.
+func (*SearchAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SearchAlertsRequest) GetDeviceIds() []string {
+	if x != nil {
+		return x.DeviceIds
+	}
+	return nil
+}
+
+func (x *SearchAlertsRequest) GetLabelSelector() string {
+	if x != nil {
+		return x.LabelSelector
+	}
+	return ""
+}
+
+func (x *SearchAlertsRequest) GetReasons() []Alert_Reason {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *SearchAlertsRequest) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *SearchAlertsRequest) GetState() Alert_State {
+	if x != nil {
+		return x.State
+	}
+	return Alert_STATE_UNSPECIFIED
+}
+
+func (x *SearchAlertsRequest) GetTimeframe() *Timeframe {
+	if x != nil {
+		return x.Timeframe
+	}
+	return nil
+}
+
+func (x *SearchAlertsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchAlertsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type SearchAlertsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alerts        []*Alert               `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchAlertsResponse) Reset() {
+	*x = SearchAlertsResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchAlertsResponse) ProtoMessage() {}
+
+func (x *SearchAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchAlertsResponse.ProtoReflect.Descriptor instead.
+func (*SearchAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SearchAlertsResponse) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+func (x *SearchAlertsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetAlertStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The timeframe to count alerts in, which must have a start and an end.
+	Timeframe   *Timeframe  `protobuf:"bytes,1,opt,name=timeframe,proto3" json:"timeframe,omitempty"`
+	BucketWidth BucketWidth `protobuf:"varint,2,opt,name=bucket_width,json=bucketWidth,proto3,enum=iot.v1.BucketWidth" json:"bucket_width,omitempty"`
+	// Number of noisiest devices to return, 10 if unset.
+	TopN          int32 `protobuf:"varint,3,opt,name=top_n,json=topN,proto3" json:"top_n,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAlertStatsRequest) Reset() {
+	*x = GetAlertStatsRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAlertStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAlertStatsRequest) ProtoMessage() {}
+
+func (x *GetAlertStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAlertStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetAlertStatsRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetAlertStatsRequest) GetTimeframe() *Timeframe {
+	if x != nil {
+		return x.Timeframe
+	}
+	return nil
+}
+
+func (x *GetAlertStatsRequest) GetBucketWidth() BucketWidth {
+	if x != nil {
+		return x.BucketWidth
+	}
+	return BucketWidth_BUCKET_WIDTH_UNSPECIFIED
+}
+
+func (x *GetAlertStatsRequest) GetTopN() int32 {
+	if x != nil {
+		return x.TopN
+	}
+	return 0
+}
+
+type GetAlertStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Total int64                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	// Counts of each reason, ordered by reason.
+	ByReason []*ReasonAlertCount `protobuf:"bytes,2,rep,name=by_reason,json=byReason,proto3" json:"by_reason,omitempty"`
+	// Counts of each device that triggered alerts, ordered by device ID.
+	ByDevice []*DeviceAlertCount `protobuf:"bytes,3,rep,name=by_device,json=byDevice,proto3" json:"by_device,omitempty"`
+	// Counts of each bucket in the timeframe, in chronological order.
+	Buckets []*AlertCountBucket `protobuf:"bytes,4,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	// The devices that triggered the most alerts, most first.
+	TopDevices    []*DeviceAlertCount `protobuf:"bytes,5,rep,name=top_devices,json=topDevices,proto3" json:"top_devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAlertStatsResponse) Reset() {
+	*x = GetAlertStatsResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAlertStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAlertStatsResponse) ProtoMessage() {}
+
+func (x *GetAlertStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAlertStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetAlertStatsResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetAlertStatsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetAlertStatsResponse) GetByReason() []*ReasonAlertCount {
+	if x != nil {
+		return x.ByReason
+	}
+	return nil
+}
+
+func (x *GetAlertStatsResponse) GetByDevice() []*DeviceAlertCount {
+	if x != nil {
+		return x.ByDevice
+	}
+	return nil
+}
+
+func (x *GetAlertStatsResponse) GetBuckets() []*AlertCountBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *GetAlertStatsResponse) GetTopDevices() []*DeviceAlertCount {
+	if x != nil {
+		return x.TopDevices
+	}
+	return nil
+}
+
+type WatchDeviceAlertsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional device to watch alerts for. Alerts for all devices are watched if
+	// empty.
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// Optional reasons to filter alerts by.
+	Reasons []Alert_Reason `protobuf:"varint,2,rep,packed,name=reasons,proto3,enum=iot.v1.Alert_Reason" json:"reasons,omitempty"`
+	// Optional cursor from a previous response to resume watching from.
+	Cursor        string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchDeviceAlertsRequest) Reset() {
+	*x = WatchDeviceAlertsRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDeviceAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDeviceAlertsRequest) ProtoMessage() {}
+
+func (x *WatchDeviceAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDeviceAlertsRequest.ProtoReflect.Descriptor instead.
+func (*WatchDeviceAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *WatchDeviceAlertsRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *WatchDeviceAlertsRequest) GetReasons() []Alert_Reason {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *WatchDeviceAlertsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type WatchDeviceAlertsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Alert *Alert                 `protobuf:"bytes,1,opt,name=alert,proto3" json:"alert,omitempty"`
+	// Cursor to resume watching from this alert.
+	Cursor        string `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchDeviceAlertsResponse) Reset() {
+	*x = WatchDeviceAlertsResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDeviceAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDeviceAlertsResponse) ProtoMessage() {}
+
+func (x *WatchDeviceAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDeviceAlertsResponse.ProtoReflect.Descriptor instead.
+func (*WatchDeviceAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *WatchDeviceAlertsResponse) GetAlert() *Alert {
+	if x != nil {
+		return x.Alert
+	}
+	return nil
+}
+
+func (x *WatchDeviceAlertsResponse) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type CreateAlertRuleRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// The rule to create. The id is ignored.
+	Rule          *AlertRule `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAlertRuleRequest) Reset() {
+	*x = CreateAlertRuleRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAlertRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAlertRuleRequest) ProtoMessage() {}
+
+func (x *CreateAlertRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAlertRuleRequest.ProtoReflect.Descriptor instead.
+func (*CreateAlertRuleRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CreateAlertRuleRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *CreateAlertRuleRequest) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type CreateAlertRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *AlertRule             `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAlertRuleResponse) Reset() {
+	*x = CreateAlertRuleResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAlertRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAlertRuleResponse) ProtoMessage() {}
+
+func (x *CreateAlertRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAlertRuleResponse.ProtoReflect.Descriptor instead.
+func (*CreateAlertRuleResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CreateAlertRuleResponse) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type GetAlertRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	RuleId        int64                  `protobuf:"varint,2,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAlertRuleRequest) Reset() {
+	*x = GetAlertRuleRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAlertRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAlertRuleRequest) ProtoMessage() {}
+
+func (x *GetAlertRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAlertRuleRequest.ProtoReflect.Descriptor instead.
+func (*GetAlertRuleRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetAlertRuleRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *GetAlertRuleRequest) GetRuleId() int64 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+type GetAlertRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *AlertRule             `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAlertRuleResponse) Reset() {
+	*x = GetAlertRuleResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAlertRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAlertRuleResponse) ProtoMessage() {}
+
+func (x *GetAlertRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAlertRuleResponse.ProtoReflect.Descriptor instead.
+func (*GetAlertRuleResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetAlertRuleResponse) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type ListAlertRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertRulesRequest) Reset() {
+	*x = ListAlertRulesRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertRulesRequest) ProtoMessage() {}
+
+func (x *ListAlertRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListAlertRulesRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListAlertRulesRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+type ListAlertRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*AlertRule           `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertRulesResponse) Reset() {
+	*x = ListAlertRulesResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertRulesResponse) ProtoMessage() {}
+
+func (x *ListAlertRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertRulesResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListAlertRulesResponse) GetRules() []*AlertRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type UpdateAlertRuleRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// The rule to replace, identified by its id.
+	Rule          *AlertRule `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAlertRuleRequest) Reset() {
+	*x = UpdateAlertRuleRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAlertRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAlertRuleRequest) ProtoMessage() {}
+
+func (x *UpdateAlertRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAlertRuleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAlertRuleRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UpdateAlertRuleRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *UpdateAlertRuleRequest) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type UpdateAlertRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *AlertRule             `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAlertRuleResponse) Reset() {
+	*x = UpdateAlertRuleResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAlertRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAlertRuleResponse) ProtoMessage() {}
+
+func (x *UpdateAlertRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAlertRuleResponse.ProtoReflect.Descriptor instead.
+func (*UpdateAlertRuleResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UpdateAlertRuleResponse) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type DeleteAlertRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	RuleId        int64                  `protobuf:"varint,2,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAlertRuleRequest) Reset() {
+	*x = DeleteAlertRuleRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAlertRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlertRuleRequest) ProtoMessage() {}
+
+func (x *DeleteAlertRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlertRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAlertRuleRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *DeleteAlertRuleRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DeleteAlertRuleRequest) GetRuleId() int64 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+type DeleteAlertRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAlertRuleResponse) Reset() {
+	*x = DeleteAlertRuleResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAlertRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlertRuleResponse) ProtoMessage() {}
+
+func (x *DeleteAlertRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlertRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAlertRuleResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{34}
+}
+
+type AcknowledgeAlertRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	AlertId  int64                  `protobuf:"varint,2,opt,name=alert_id,json=alertId,proto3" json:"alert_id,omitempty"`
+	// The operator acknowledging the alert.
+	AcknowledgedBy string `protobuf:"bytes,3,opt,name=acknowledged_by,json=acknowledgedBy,proto3" json:"acknowledged_by,omitempty"`
+	// Optional comment, e.g. the action being taken.
+	Comment       string `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcknowledgeAlertRequest) Reset() {
+	*x = AcknowledgeAlertRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcknowledgeAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcknowledgeAlertRequest) ProtoMessage() {}
+
+func (x *AcknowledgeAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcknowledgeAlertRequest.ProtoReflect.Descriptor instead.
+func (*AcknowledgeAlertRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *AcknowledgeAlertRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *AcknowledgeAlertRequest) GetAlertId() int64 {
+	if x != nil {
+		return x.AlertId
+	}
+	return 0
+}
+
+func (x *AcknowledgeAlertRequest) GetAcknowledgedBy() string {
+	if x != nil {
+		return x.AcknowledgedBy
+	}
+	return ""
+}
+
+func (x *AcknowledgeAlertRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type AcknowledgeAlertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alert         *Alert                 `protobuf:"bytes,1,opt,name=alert,proto3" json:"alert,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcknowledgeAlertResponse) Reset() {
+	*x = AcknowledgeAlertResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcknowledgeAlertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcknowledgeAlertResponse) ProtoMessage() {}
+
+func (x *AcknowledgeAlertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcknowledgeAlertResponse.ProtoReflect.Descriptor instead.
+func (*AcknowledgeAlertResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *AcknowledgeAlertResponse) GetAlert() *Alert {
+	if x != nil {
+		return x.Alert
+	}
+	return nil
+}
+
+type ResolveAlertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	AlertId       int64                  `protobuf:"varint,2,opt,name=alert_id,json=alertId,proto3" json:"alert_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveAlertRequest) Reset() {
+	*x = ResolveAlertRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveAlertRequest) ProtoMessage() {}
+
+func (x *ResolveAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveAlertRequest.ProtoReflect.Descriptor instead.
+func (*ResolveAlertRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ResolveAlertRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ResolveAlertRequest) GetAlertId() int64 {
+	if x != nil {
+		return x.AlertId
+	}
+	return 0
+}
+
+type ResolveAlertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alert         *Alert                 `protobuf:"bytes,1,opt,name=alert,proto3" json:"alert,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveAlertResponse) Reset() {
+	*x = ResolveAlertResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveAlertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveAlertResponse) ProtoMessage() {}
+
+func (x *ResolveAlertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveAlertResponse.ProtoReflect.Descriptor instead.
+func (*ResolveAlertResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ResolveAlertResponse) GetAlert() *Alert {
+	if x != nil {
+		return x.Alert
+	}
+	return nil
+}
+
+type CreateDeviceRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// The device to create. The id, created_at and last_seen fields are ignored.
+	Device        *Device `protobuf:"bytes,2,opt,name=device,proto3" json:"device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDeviceRequest) Reset() {
+	*x = CreateDeviceRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDeviceRequest) ProtoMessage() {}
+
+func (x *CreateDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDeviceRequest.ProtoReflect.Descriptor instead.
+func (*CreateDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *CreateDeviceRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *CreateDeviceRequest) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+type CreateDeviceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        *Device                `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDeviceResponse) Reset() {
+	*x = CreateDeviceResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDeviceResponse) ProtoMessage() {}
+
+func (x *CreateDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDeviceResponse.ProtoReflect.Descriptor instead.
+func (*CreateDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *CreateDeviceResponse) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+type GetDeviceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceRequest) Reset() {
+	*x = GetDeviceRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceRequest) ProtoMessage() {}
+
+func (x *GetDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceRequest.ProtoReflect.Descriptor instead.
+func (*GetDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetDeviceRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+type GetDeviceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        *Device                `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceResponse) Reset() {
+	*x = GetDeviceResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceResponse) ProtoMessage() {}
+
+func (x *GetDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceResponse.ProtoReflect.Descriptor instead.
+func (*GetDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *GetDeviceResponse) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+type DeleteDeviceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDeviceRequest) Reset() {
+	*x = DeleteDeviceRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDeviceRequest) ProtoMessage() {}
+
+func (x *DeleteDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDeviceRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *DeleteDeviceRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+type DeleteDeviceResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The operation purging the data of the device.
+	Operation     *Operation `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDeviceResponse) Reset() {
+	*x = DeleteDeviceResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDeviceResponse) ProtoMessage() {}
+
+func (x *DeleteDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDeviceResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *DeleteDeviceResponse) GetOperation() *Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+type GetOperationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OperationId   int64                  `protobuf:"varint,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOperationRequest) Reset() {
+	*x = GetOperationRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOperationRequest) ProtoMessage() {}
+
+func (x *GetOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOperationRequest.ProtoReflect.Descriptor instead.
+func (*GetOperationRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *GetOperationRequest) GetOperationId() int64 {
+	if x != nil {
+		return x.OperationId
+	}
+	return 0
+}
+
+type GetOperationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Operation     *Operation             `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOperationResponse) Reset() {
+	*x = GetOperationResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOperationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOperationResponse) ProtoMessage() {}
+
+func (x *GetOperationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOperationResponse.ProtoReflect.Descriptor instead.
+func (*GetOperationResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetOperationResponse) GetOperation() *Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+type UpdateDeviceRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// The device metadata, labels and group. The id, created_at and last_seen
+	// fields are ignored.
+	Device        *Device `protobuf:"bytes,2,opt,name=device,proto3" json:"device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDeviceRequest) Reset() {
+	*x = UpdateDeviceRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDeviceRequest) ProtoMessage() {}
+
+func (x *UpdateDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDeviceRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *UpdateDeviceRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *UpdateDeviceRequest) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+type UpdateDeviceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        *Device                `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDeviceResponse) Reset() {
+	*x = UpdateDeviceResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDeviceResponse) ProtoMessage() {}
+
+func (x *UpdateDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDeviceResponse.ProtoReflect.Descriptor instead.
+func (*UpdateDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *UpdateDeviceResponse) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+type ListDevicesRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	PageSize  int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Optional comma separated label requirements that devices must all match,
+	// e.g. `env=prod,tier!=edge,region,!deprecated`.
+	LabelSelector string `protobuf:"bytes,3,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesRequest) Reset() {
+	*x = ListDevicesRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesRequest) ProtoMessage() {}
+
+func (x *ListDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListDevicesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDevicesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListDevicesRequest) GetLabelSelector() string {
+	if x != nil {
+		return x.LabelSelector
+	}
+	return ""
+}
+
+type ListDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesResponse) Reset() {
+	*x = ListDevicesResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesResponse) ProtoMessage() {}
+
+func (x *ListDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ListDevicesResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *ListDevicesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetFleetSnapshotRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	PageSize  int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Comma separated label requirements that devices must all meet, in the
+	// same format as ListDevicesRequest.label_selector.
+	LabelSelector string `protobuf:"bytes,3,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	// Only include devices in the group, if set.
+	GroupId       string `protobuf:"bytes,4,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFleetSnapshotRequest) Reset() {
+	*x = GetFleetSnapshotRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFleetSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFleetSnapshotRequest) ProtoMessage() {}
+
+func (x *GetFleetSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFleetSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*GetFleetSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *GetFleetSnapshotRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetFleetSnapshotRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetFleetSnapshotRequest) GetLabelSelector() string {
+	if x != nil {
+		return x.LabelSelector
+	}
+	return ""
+}
+
+func (x *GetFleetSnapshotRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+type GetFleetSnapshotResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Devices are ordered most recently created first.
+	Devices       []*DeviceSnapshot `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	NextPageToken string            `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFleetSnapshotResponse) Reset() {
+	*x = GetFleetSnapshotResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFleetSnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFleetSnapshotResponse) ProtoMessage() {}
+
+func (x *GetFleetSnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFleetSnapshotResponse.ProtoReflect.Descriptor instead.
+func (*GetFleetSnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetFleetSnapshotResponse) GetDevices() []*DeviceSnapshot {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *GetFleetSnapshotResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type CreateDeviceGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	DisplayName   string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDeviceGroupRequest) Reset() {
+	*x = CreateDeviceGroupRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDeviceGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDeviceGroupRequest) ProtoMessage() {}
+
+func (x *CreateDeviceGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDeviceGroupRequest.ProtoReflect.Descriptor instead.
+func (*CreateDeviceGroupRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *CreateDeviceGroupRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *CreateDeviceGroupRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+type CreateDeviceGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *DeviceGroup           `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDeviceGroupResponse) Reset() {
+	*x = CreateDeviceGroupResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDeviceGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDeviceGroupResponse) ProtoMessage() {}
+
+func (x *CreateDeviceGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDeviceGroupResponse.ProtoReflect.Descriptor instead.
+func (*CreateDeviceGroupResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *CreateDeviceGroupResponse) GetGroup() *DeviceGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type GetDeviceGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceGroupRequest) Reset() {
+	*x = GetDeviceGroupRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceGroupRequest) ProtoMessage() {}
+
+func (x *GetDeviceGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceGroupRequest.ProtoReflect.Descriptor instead.
+func (*GetDeviceGroupRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *GetDeviceGroupRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+type GetDeviceGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *DeviceGroup           `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceGroupResponse) Reset() {
+	*x = GetDeviceGroupResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceGroupResponse) ProtoMessage() {}
+
+func (x *GetDeviceGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceGroupResponse.ProtoReflect.Descriptor instead.
+func (*GetDeviceGroupResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *GetDeviceGroupResponse) GetGroup() *DeviceGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type ListDeviceGroupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeviceGroupsRequest) Reset() {
+	*x = ListDeviceGroupsRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeviceGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeviceGroupsRequest) ProtoMessage() {}
+
+func (x *ListDeviceGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeviceGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListDeviceGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{57}
+}
+
+type ListDeviceGroupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*DeviceGroup         `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeviceGroupsResponse) Reset() {
+	*x = ListDeviceGroupsResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeviceGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeviceGroupsResponse) ProtoMessage() {}
+
+func (x *ListDeviceGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeviceGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListDeviceGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ListDeviceGroupsResponse) GetGroups() []*DeviceGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type ConfigureDeviceGroupRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	GroupId string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// Settings that are unset are inherited from the global defaults.
+	Settings      *ConfigSettings `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigureDeviceGroupRequest) Reset() {
+	*x = ConfigureDeviceGroupRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigureDeviceGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigureDeviceGroupRequest) ProtoMessage() {}
+
+func (x *ConfigureDeviceGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigureDeviceGroupRequest.ProtoReflect.Descriptor instead.
+func (*ConfigureDeviceGroupRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ConfigureDeviceGroupRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *ConfigureDeviceGroupRequest) GetSettings() *ConfigSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+type ConfigureDeviceGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigureDeviceGroupResponse) Reset() {
+	*x = ConfigureDeviceGroupResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigureDeviceGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigureDeviceGroupResponse) ProtoMessage() {}
+
+func (x *ConfigureDeviceGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigureDeviceGroupResponse.ProtoReflect.Descriptor instead.
+func (*ConfigureDeviceGroupResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{60}
+}
+
+type ConfigureDefaultsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Settings      *ConfigSettings        `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigureDefaultsRequest) Reset() {
+	*x = ConfigureDefaultsRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigureDefaultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigureDefaultsRequest) ProtoMessage() {}
+
+func (x *ConfigureDefaultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigureDefaultsRequest.ProtoReflect.Descriptor instead.
+func (*ConfigureDefaultsRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ConfigureDefaultsRequest) GetSettings() *ConfigSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+type ConfigureDefaultsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigureDefaultsResponse) Reset() {
+	*x = ConfigureDefaultsResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigureDefaultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigureDefaultsResponse) ProtoMessage() {}
+
+func (x *ConfigureDefaultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigureDefaultsResponse.ProtoReflect.Descriptor instead.
+func (*ConfigureDefaultsResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{62}
+}
+
+type GetEffectiveDeviceConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEffectiveDeviceConfigRequest) Reset() {
+	*x = GetEffectiveDeviceConfigRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEffectiveDeviceConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEffectiveDeviceConfigRequest) ProtoMessage() {}
+
+func (x *GetEffectiveDeviceConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEffectiveDeviceConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetEffectiveDeviceConfigRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *GetEffectiveDeviceConfigRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+type GetEffectiveDeviceConfigResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The group of the device, empty if it does not belong to one.
+	GroupId string `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// The resolved settings. Settings that are not set at any level are unset.
+	Settings *ConfigSettings `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	// The level that each setting was resolved from, keyed by setting name, e.g.
+	// temperature_threshold.
+	Sources       map[string]ConfigLevel `protobuf:"bytes,3,rep,name=sources,proto3" json:"sources,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value,enum=iot.v1.ConfigLevel"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEffectiveDeviceConfigResponse) Reset() {
+	*x = GetEffectiveDeviceConfigResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEffectiveDeviceConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEffectiveDeviceConfigResponse) ProtoMessage() {}
+
+func (x *GetEffectiveDeviceConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEffectiveDeviceConfigResponse.ProtoReflect.Descriptor instead.
+func (*GetEffectiveDeviceConfigResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *GetEffectiveDeviceConfigResponse) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *GetEffectiveDeviceConfigResponse) GetSettings() *ConfigSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *GetEffectiveDeviceConfigResponse) GetSources() map[string]ConfigLevel {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type ListDeviceConfigVersionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeviceConfigVersionsRequest) Reset() {
+	*x = ListDeviceConfigVersionsRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeviceConfigVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeviceConfigVersionsRequest) ProtoMessage() {}
+
+func (x *ListDeviceConfigVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeviceConfigVersionsRequest.ProtoReflect.Descriptor instead.
+func (*ListDeviceConfigVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *ListDeviceConfigVersionsRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ListDeviceConfigVersionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDeviceConfigVersionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListDeviceConfigVersionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []*DeviceConfigVersion `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeviceConfigVersionsResponse) Reset() {
+	*x = ListDeviceConfigVersionsResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeviceConfigVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeviceConfigVersionsResponse) ProtoMessage() {}
+
+func (x *ListDeviceConfigVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeviceConfigVersionsResponse.ProtoReflect.Descriptor instead.
+func (*ListDeviceConfigVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ListDeviceConfigVersionsResponse) GetVersions() []*DeviceConfigVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *ListDeviceConfigVersionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type RollbackDeviceConfigRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// The version to restore the settings of.
+	Version int64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Optional operator making the change.
+	Author string `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	// Optional reason for the change. Defaults to the version rolled back to.
+	Reason string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Optional revision, the version number of the current config (0 if the
+	// device has not been configured), that the change is conditional on. The
+	// change fails with FAILED_PRECONDITION if the config has since changed.
+	ExpectedRevision *int64 `protobuf:"varint,5,opt,name=expected_revision,json=expectedRevision,proto3,oneof" json:"expected_revision,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RollbackDeviceConfigRequest) Reset() {
+	*x = RollbackDeviceConfigRequest{}
+	mi := &file_iot_v1_service_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackDeviceConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackDeviceConfigRequest) ProtoMessage() {}
+
+func (x *RollbackDeviceConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackDeviceConfigRequest.ProtoReflect.Descriptor instead.
+func (*RollbackDeviceConfigRequest) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *RollbackDeviceConfigRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *RollbackDeviceConfigRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RollbackDeviceConfigRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *RollbackDeviceConfigRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RollbackDeviceConfigRequest) GetExpectedRevision() int64 {
+	if x != nil && x.ExpectedRevision != nil {
+		return *x.ExpectedRevision
+	}
+	return 0
+}
+
+type RollbackDeviceConfigResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The version created by the rollback.
+	Version       *DeviceConfigVersion `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackDeviceConfigResponse) Reset() {
+	*x = RollbackDeviceConfigResponse{}
+	mi := &file_iot_v1_service_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackDeviceConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackDeviceConfigResponse) ProtoMessage() {}
+
+func (x *RollbackDeviceConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackDeviceConfigResponse.ProtoReflect.Descriptor instead.
+func (*RollbackDeviceConfigResponse) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *RollbackDeviceConfigResponse) GetVersion() *DeviceConfigVersion {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+// DeviceConfigVersion is an immutable version of the settings set for a
+// device.
+type DeviceConfigVersion struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The version number, which is the revision of the config while it is the
+	// current version.
+	Version       int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Settings      *ConfigSettings        `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Author        string                 `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceConfigVersion) Reset() {
+	*x = DeviceConfigVersion{}
+	mi := &file_iot_v1_service_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceConfigVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceConfigVersion) ProtoMessage() {}
+
+func (x *DeviceConfigVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceConfigVersion.ProtoReflect.Descriptor instead.
+func (*DeviceConfigVersion) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *DeviceConfigVersion) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *DeviceConfigVersion) GetSettings() *ConfigSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *DeviceConfigVersion) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *DeviceConfigVersion) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *DeviceConfigVersion) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// ConfigSettings are the settings of a config level, see
+// ConfigureDeviceRequest.
+type ConfigSettings struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	TemperatureThreshold     *float64               `protobuf:"fixed64,1,opt,name=temperature_threshold,json=temperatureThreshold,proto3,oneof" json:"temperature_threshold,omitempty"`
+	BatteryThreshold         *int32                 `protobuf:"varint,2,opt,name=battery_threshold,json=batteryThreshold,proto3,oneof" json:"battery_threshold,omitempty"`
+	Expressions              []*AlertExpression     `protobuf:"bytes,3,rep,name=expressions,proto3" json:"expressions,omitempty"`
+	ConsecutiveBreaches      *int32                 `protobuf:"varint,4,opt,name=consecutive_breaches,json=consecutiveBreaches,proto3,oneof" json:"consecutive_breaches,omitempty"`
+	Hysteresis               *float64               `protobuf:"fixed64,5,opt,name=hysteresis,proto3,oneof" json:"hysteresis,omitempty"`
+	Cooldowns                []*AlertCooldown       `protobuf:"bytes,6,rep,name=cooldowns,proto3" json:"cooldowns,omitempty"`
+	ReportingIntervalSeconds *int64                 `protobuf:"varint,7,opt,name=reporting_interval_seconds,json=reportingIntervalSeconds,proto3,oneof" json:"reporting_interval_seconds,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *ConfigSettings) Reset() {
+	*x = ConfigSettings{}
+	mi := &file_iot_v1_service_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigSettings) ProtoMessage() {}
+
+func (x *ConfigSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigSettings.ProtoReflect.Descriptor instead.
+func (*ConfigSettings) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *ConfigSettings) GetTemperatureThreshold() float64 {
+	if x != nil && x.TemperatureThreshold != nil {
+		return *x.TemperatureThreshold
+	}
+	return 0
+}
+
+func (x *ConfigSettings) GetBatteryThreshold() int32 {
+	if x != nil && x.BatteryThreshold != nil {
+		return *x.BatteryThreshold
+	}
+	return 0
+}
+
+func (x *ConfigSettings) GetExpressions() []*AlertExpression {
+	if x != nil {
+		return x.Expressions
+	}
+	return nil
+}
+
+func (x *ConfigSettings) GetConsecutiveBreaches() int32 {
+	if x != nil && x.ConsecutiveBreaches != nil {
+		return *x.ConsecutiveBreaches
+	}
+	return 0
+}
+
+func (x *ConfigSettings) GetHysteresis() float64 {
+	if x != nil && x.Hysteresis != nil {
+		return *x.Hysteresis
+	}
+	return 0
+}
+
+func (x *ConfigSettings) GetCooldowns() []*AlertCooldown {
+	if x != nil {
+		return x.Cooldowns
+	}
+	return nil
+}
+
+func (x *ConfigSettings) GetReportingIntervalSeconds() int64 {
+	if x != nil && x.ReportingIntervalSeconds != nil {
+		return *x.ReportingIntervalSeconds
+	}
+	return 0
+}
+
+type Timeframe struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3,oneof" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3,oneof" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Timeframe) Reset() {
+	*x = Timeframe{}
+	mi := &file_iot_v1_service_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Timeframe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Timeframe) ProtoMessage() {}
+
+func (x *Timeframe) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Timeframe.ProtoReflect.Descriptor instead.
+func (*Timeframe) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *Timeframe) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Timeframe) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type Metric struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Deprecated: use values instead. Ignored on input if values is not empty.
+	Temperature float64 `protobuf:"fixed64,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	// Deprecated: use values instead. Ignored on input if values is not empty.
+	Battery       int32          `protobuf:"varint,3,opt,name=battery,proto3" json:"battery,omitempty"`
+	Values        []*MetricValue `protobuf:"bytes,4,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Metric) Reset() {
+	*x = Metric{}
+	mi := &file_iot_v1_service_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metric) ProtoMessage() {}
+
+func (x *Metric) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metric.ProtoReflect.Descriptor instead.
+func (*Metric) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *Metric) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Metric) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *Metric) GetBattery() int32 {
+	if x != nil {
+		return x.Battery
+	}
+	return 0
+}
+
+func (x *Metric) GetValues() []*MetricValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type MetricValue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the metric, e.g. temperature, humidity or co2.
+	Name  string  `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Optional unit of the value, e.g. celsius or ppm.
+	Unit          string `protobuf:"bytes,3,opt,name=unit,proto3" json:"unit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricValue) Reset() {
+	*x = MetricValue{}
+	mi := &file_iot_v1_service_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricValue) ProtoMessage() {}
+
+func (x *MetricValue) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricValue.ProtoReflect.Descriptor instead.
+func (*MetricValue) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *MetricValue) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MetricValue) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *MetricValue) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+// MetricAggregates are the aggregated values of a named metric in consecutive
+// buckets.
+type MetricAggregates struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Buckets in chronological order.
+	Buckets       []*MetricBucket `protobuf:"bytes,2,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricAggregates) Reset() {
+	*x = MetricAggregates{}
+	mi := &file_iot_v1_service_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricAggregates) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricAggregates) ProtoMessage() {}
+
+func (x *MetricAggregates) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricAggregates.ProtoReflect.Descriptor instead.
+func (*MetricAggregates) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *MetricAggregates) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MetricAggregates) GetBuckets() []*MetricBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type MetricBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Start of the bucket, aligned to a multiple of the bucket width since the
+	// unix epoch.
+	Start *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Count int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// The min, max, avg, first and last values are unset for empty buckets.
+	Min *float64 `protobuf:"fixed64,3,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	Max *float64 `protobuf:"fixed64,4,opt,name=max,proto3,oneof" json:"max,omitempty"`
+	Avg *float64 `protobuf:"fixed64,5,opt,name=avg,proto3,oneof" json:"avg,omitempty"`
+	// Value of the earliest metric in the bucket.
+	First *float64 `protobuf:"fixed64,6,opt,name=first,proto3,oneof" json:"first,omitempty"`
+	// Value of the latest metric in the bucket.
+	Last          *float64 `protobuf:"fixed64,7,opt,name=last,proto3,oneof" json:"last,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricBucket) Reset() {
+	*x = MetricBucket{}
+	mi := &file_iot_v1_service_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricBucket) ProtoMessage() {}
+
+func (x *MetricBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricBucket.ProtoReflect.Descriptor instead.
+func (*MetricBucket) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *MetricBucket) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *MetricBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *MetricBucket) GetMin() float64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *MetricBucket) GetMax() float64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+func (x *MetricBucket) GetAvg() float64 {
+	if x != nil && x.Avg != nil {
+		return *x.Avg
+	}
+	return 0
+}
+
+func (x *MetricBucket) GetFirst() float64 {
+	if x != nil && x.First != nil {
+		return *x.First
+	}
+	return 0
+}
+
+func (x *MetricBucket) GetLast() float64 {
+	if x != nil && x.Last != nil {
+		return *x.Last
+	}
+	return 0
+}
+
+type ReasonAlertCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        Alert_Reason           `protobuf:"varint,1,opt,name=reason,proto3,enum=iot.v1.Alert_Reason" json:"reason,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReasonAlertCount) Reset() {
+	*x = ReasonAlertCount{}
+	mi := &file_iot_v1_service_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReasonAlertCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReasonAlertCount) ProtoMessage() {}
+
+func (x *ReasonAlertCount) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReasonAlertCount.ProtoReflect.Descriptor instead.
+func (*ReasonAlertCount) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ReasonAlertCount) GetReason() Alert_Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return Alert_REASON_UNSPECIFIED
+}
+
+func (x *ReasonAlertCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type DeviceAlertCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceAlertCount) Reset() {
+	*x = DeviceAlertCount{}
+	mi := &file_iot_v1_service_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceAlertCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceAlertCount) ProtoMessage() {}
+
+func (x *DeviceAlertCount) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceAlertCount.ProtoReflect.Descriptor instead.
+func (*DeviceAlertCount) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *DeviceAlertCount) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DeviceAlertCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type AlertCountBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertCountBucket) Reset() {
+	*x = AlertCountBucket{}
+	mi := &file_iot_v1_service_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertCountBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertCountBucket) ProtoMessage() {}
+
+func (x *AlertCountBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertCountBucket.ProtoReflect.Descriptor instead.
+func (*AlertCountBucket) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *AlertCountBucket) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *AlertCountBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type Alert struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// When the alert was first seen.
+	Timestamp   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Reason      Alert_Reason           `protobuf:"varint,2,opt,name=reason,proto3,enum=iot.v1.Alert_Reason" json:"reason,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	DeviceId    string                 `protobuf:"bytes,4,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// The alert rule that triggered the alert, or 0 if it was triggered by the
+	// device config thresholds.
+	RuleId   int64       `protobuf:"varint,5,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	Severity Severity    `protobuf:"varint,6,opt,name=severity,proto3,enum=iot.v1.Severity" json:"severity,omitempty"`
+	Id       int64       `protobuf:"varint,7,opt,name=id,proto3" json:"id,omitempty"`
+	State    Alert_State `protobuf:"varint,8,opt,name=state,proto3,enum=iot.v1.Alert_State" json:"state,omitempty"`
+	// The operator that acknowledged the alert, if acknowledged.
+	AcknowledgedBy string                 `protobuf:"bytes,9,opt,name=acknowledged_by,json=acknowledgedBy,proto3" json:"acknowledged_by,omitempty"`
+	AckComment     string                 `protobuf:"bytes,10,opt,name=ack_comment,json=ackComment,proto3" json:"ack_comment,omitempty"`
+	AcknowledgedAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=acknowledged_at,json=acknowledgedAt,proto3,oneof" json:"acknowledged_at,omitempty"`
+	ResolvedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=resolved_at,json=resolvedAt,proto3,oneof" json:"resolved_at,omitempty"`
+	// Number of times the alert's rule or expression triggered while the alert
+	// was unresolved.
+	Occurrences int64                  `protobuf:"varint,13,opt,name=occurrences,proto3" json:"occurrences,omitempty"`
+	LastSeen    *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	// The version of the device config the alert was evaluated against, or 0 if
+	// no config was set for the device.
+	ConfigVersion int64 `protobuf:"varint,15,opt,name=config_version,json=configVersion,proto3" json:"config_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_iot_v1_service_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *Alert) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Alert) GetReason() Alert_Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return Alert_REASON_UNSPECIFIED
+}
+
+func (x *Alert) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Alert) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *Alert) GetRuleId() int64 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+func (x *Alert) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *Alert) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Alert) GetState() Alert_State {
+	if x != nil {
+		return x.State
+	}
+	return Alert_STATE_UNSPECIFIED
+}
+
+func (x *Alert) GetAcknowledgedBy() string {
+	if x != nil {
+		return x.AcknowledgedBy
+	}
+	return ""
+}
+
+func (x *Alert) GetAckComment() string {
+	if x != nil {
+		return x.AckComment
+	}
+	return ""
+}
+
+func (x *Alert) GetAcknowledgedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AcknowledgedAt
+	}
+	return nil
+}
+
+func (x *Alert) GetResolvedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return nil
+}
+
+func (x *Alert) GetOccurrences() int64 {
+	if x != nil {
+		return x.Occurrences
+	}
+	return 0
+}
+
+func (x *Alert) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *Alert) GetConfigVersion() int64 {
+	if x != nil {
+		return x.ConfigVersion
+	}
+	return 0
+}
+
+type Device struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DisplayName     string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Model           string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	FirmwareVersion string                 `protobuf:"bytes,4,opt,name=firmware_version,json=firmwareVersion,proto3" json:"firmware_version,omitempty"`
+	Location        string                 `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
+	// Free-form labels for grouping and selecting devices, e.g. env=prod.
+	Labels    map[string]string      `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// When the device last recorded a metric, unset if it has not recorded any.
+	LastSeen *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_seen,json=lastSeen,proto3,oneof" json:"last_seen,omitempty"`
+	// The group the device inherits config from, empty if none.
+	GroupId       string `protobuf:"bytes,9,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_iot_v1_service_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RecordMetricResponse) String() string {
+func (x *Device) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RecordMetricResponse) ProtoMessage() {}
+func (*Device) ProtoMessage() {}
 
-func (x *RecordMetricResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_iot_v1_service_proto_msgTypes[1]
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -170,92 +5156,103 @@ func (x *RecordMetricResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RecordMetricResponse.ProtoReflect.Descriptor instead.
-func (*RecordMetricResponse) Descriptor() ([]byte, []int) {
-	return file_iot_v1_service_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{80}
 }
 
-type ConfigureDeviceRequest struct {
-	state                protoimpl.MessageState `protogen:"open.v1"`
-	DeviceId             string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
-	TemperatureThreshold float64                `protobuf:"fixed64,2,opt,name=temperature_threshold,json=temperatureThreshold,proto3" json:"temperature_threshold,omitempty"`
-	BatteryThreshold     int32                  `protobuf:"varint,3,opt,name=battery_threshold,json=batteryThreshold,proto3" json:"battery_threshold,omitempty"`
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+func (x *Device) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
 }
 
-func (x *ConfigureDeviceRequest) Reset() {
-	*x = ConfigureDeviceRequest{}
-	mi := &file_iot_v1_service_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Device) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
 }
 
-func (x *ConfigureDeviceRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Device) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
 }
 
-func (*ConfigureDeviceRequest) ProtoMessage() {}
+func (x *Device) GetFirmwareVersion() string {
+	if x != nil {
+		return x.FirmwareVersion
+	}
+	return ""
+}
 
-func (x *ConfigureDeviceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_iot_v1_service_proto_msgTypes[2]
+func (x *Device) GetLocation() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Location
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use ConfigureDeviceRequest.ProtoReflect.Descriptor instead.
-func (*ConfigureDeviceRequest) Descriptor() ([]byte, []int) {
-	return file_iot_v1_service_proto_rawDescGZIP(), []int{2}
+func (x *Device) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
 }
 
-func (x *ConfigureDeviceRequest) GetDeviceId() string {
+func (x *Device) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.DeviceId
+		return x.CreatedAt
 	}
-	return ""
+	return nil
 }
 
-func (x *ConfigureDeviceRequest) GetTemperatureThreshold() float64 {
+func (x *Device) GetLastSeen() *timestamppb.Timestamp {
 	if x != nil {
-		return x.TemperatureThreshold
+		return x.LastSeen
 	}
-	return 0
+	return nil
 }
 
-func (x *ConfigureDeviceRequest) GetBatteryThreshold() int32 {
+func (x *Device) GetGroupId() string {
 	if x != nil {
-		return x.BatteryThreshold
+		return x.GroupId
 	}
-	return 0
+	return ""
 }
 
-type ConfigureDeviceResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+// DeviceSnapshot is the latest state of a device.
+type DeviceSnapshot struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The device, whose last_seen is when it last recorded a metric.
+	Device *Device `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	// The most recent metric recorded by the device, unset if it has not
+	// recorded any.
+	LatestMetric *Metric `protobuf:"bytes,2,opt,name=latest_metric,json=latestMetric,proto3" json:"latest_metric,omitempty"`
+	// Number of unresolved alerts of the device, whether open or acknowledged.
+	OpenAlerts    int64 `protobuf:"varint,3,opt,name=open_alerts,json=openAlerts,proto3" json:"open_alerts,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ConfigureDeviceResponse) Reset() {
-	*x = ConfigureDeviceResponse{}
-	mi := &file_iot_v1_service_proto_msgTypes[3]
+func (x *DeviceSnapshot) Reset() {
+	*x = DeviceSnapshot{}
+	mi := &file_iot_v1_service_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ConfigureDeviceResponse) String() string {
+func (x *DeviceSnapshot) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConfigureDeviceResponse) ProtoMessage() {}
+func (*DeviceSnapshot) ProtoMessage() {}
 
-func (x *ConfigureDeviceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_iot_v1_service_proto_msgTypes[3]
+func (x *DeviceSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -266,36 +5263,56 @@ func (x *ConfigureDeviceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConfigureDeviceResponse.ProtoReflect.Descriptor instead.
-func (*ConfigureDeviceResponse) Descriptor() ([]byte, []int) {
-	return file_iot_v1_service_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use DeviceSnapshot.ProtoReflect.Descriptor instead.
+func (*DeviceSnapshot) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{81}
 }
 
-type GetDeviceAlertsRequest struct {
+func (x *DeviceSnapshot) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+func (x *DeviceSnapshot) GetLatestMetric() *Metric {
+	if x != nil {
+		return x.LatestMetric
+	}
+	return nil
+}
+
+func (x *DeviceSnapshot) GetOpenAlerts() int64 {
+	if x != nil {
+		return x.OpenAlerts
+	}
+	return 0
+}
+
+type DeviceGroup struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
-	Timeframe     *Timeframe             `protobuf:"bytes,2,opt,name=timeframe,proto3,oneof" json:"timeframe,omitempty"`
-	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DisplayName   string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDeviceAlertsRequest) Reset() {
-	*x = GetDeviceAlertsRequest{}
-	mi := &file_iot_v1_service_proto_msgTypes[4]
+func (x *DeviceGroup) Reset() {
+	*x = DeviceGroup{}
+	mi := &file_iot_v1_service_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDeviceAlertsRequest) String() string {
+func (x *DeviceGroup) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDeviceAlertsRequest) ProtoMessage() {}
+func (*DeviceGroup) ProtoMessage() {}
 
-func (x *GetDeviceAlertsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_iot_v1_service_proto_msgTypes[4]
+func (x *DeviceGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -306,62 +5323,59 @@ func (x *GetDeviceAlertsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDeviceAlertsRequest.ProtoReflect.Descriptor instead.
-func (*GetDeviceAlertsRequest) Descriptor() ([]byte, []int) {
-	return file_iot_v1_service_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use DeviceGroup.ProtoReflect.Descriptor instead.
+func (*DeviceGroup) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *GetDeviceAlertsRequest) GetDeviceId() string {
+func (x *DeviceGroup) GetId() string {
 	if x != nil {
-		return x.DeviceId
+		return x.Id
 	}
 	return ""
 }
 
-func (x *GetDeviceAlertsRequest) GetTimeframe() *Timeframe {
-	if x != nil {
-		return x.Timeframe
-	}
-	return nil
-}
-
-func (x *GetDeviceAlertsRequest) GetPageSize() int32 {
+func (x *DeviceGroup) GetDisplayName() string {
 	if x != nil {
-		return x.PageSize
+		return x.DisplayName
 	}
-	return 0
+	return ""
 }
 
-func (x *GetDeviceAlertsRequest) GetPageToken() string {
+func (x *DeviceGroup) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.PageToken
+		return x.CreatedAt
 	}
-	return ""
+	return nil
 }
 
-type GetDeviceAlertsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Alerts        []*Alert               `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
-	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+type AlertExpression struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Common Expression Language (CEL) expression evaluating to a bool. Values of
+	// the recorded metric are available as variables by name and values of the
+	// previous metric through the prev map, e.g.
+	// `temperature > 40 && battery < 15` or `temperature - prev.temperature > 5`.
+	Expr          string   `protobuf:"bytes,1,opt,name=expr,proto3" json:"expr,omitempty"`
+	Severity      Severity `protobuf:"varint,2,opt,name=severity,proto3,enum=iot.v1.Severity" json:"severity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDeviceAlertsResponse) Reset() {
-	*x = GetDeviceAlertsResponse{}
-	mi := &file_iot_v1_service_proto_msgTypes[5]
+func (x *AlertExpression) Reset() {
+	*x = AlertExpression{}
+	mi := &file_iot_v1_service_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDeviceAlertsResponse) String() string {
+func (x *AlertExpression) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDeviceAlertsResponse) ProtoMessage() {}
+func (*AlertExpression) ProtoMessage() {}
 
-func (x *GetDeviceAlertsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_iot_v1_service_proto_msgTypes[5]
+func (x *AlertExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -372,48 +5386,59 @@ func (x *GetDeviceAlertsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDeviceAlertsResponse.ProtoReflect.Descriptor instead.
-func (*GetDeviceAlertsResponse) Descriptor() ([]byte, []int) {
-	return file_iot_v1_service_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use AlertExpression.ProtoReflect.Descriptor instead.
+func (*AlertExpression) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{83}
 }
 
-func (x *GetDeviceAlertsResponse) GetAlerts() []*Alert {
+func (x *AlertExpression) GetExpr() string {
 	if x != nil {
-		return x.Alerts
+		return x.Expr
 	}
-	return nil
+	return ""
 }
 
-func (x *GetDeviceAlertsResponse) GetNextPageToken() string {
+func (x *AlertExpression) GetSeverity() Severity {
 	if x != nil {
-		return x.NextPageToken
+		return x.Severity
 	}
-	return ""
+	return Severity_SEVERITY_UNSPECIFIED
 }
 
-type Timeframe struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3,oneof" json:"start,omitempty"`
-	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3,oneof" json:"end,omitempty"`
+type AlertRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Name of the metric the rule applies to, e.g. temperature or humidity.
+	Metric   string             `protobuf:"bytes,2,opt,name=metric,proto3" json:"metric,omitempty"`
+	Operator AlertRule_Operator `protobuf:"varint,3,opt,name=operator,proto3,enum=iot.v1.AlertRule_Operator" json:"operator,omitempty"`
+	// Threshold compared against, the lower bound of the range for range
+	// operators, or the change in value for rate of change operators.
+	Threshold float64 `protobuf:"fixed64,4,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// Upper bound of the range for range operators. Unused by other operators.
+	ThresholdHigh float64  `protobuf:"fixed64,5,opt,name=threshold_high,json=thresholdHigh,proto3" json:"threshold_high,omitempty"`
+	Severity      Severity `protobuf:"varint,6,opt,name=severity,proto3,enum=iot.v1.Severity" json:"severity,omitempty"`
+	// Window over which the change in value is measured for rate of change
+	// operators. Unused by other operators.
+	WindowSeconds int64 `protobuf:"varint,7,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Timeframe) Reset() {
-	*x = Timeframe{}
-	mi := &file_iot_v1_service_proto_msgTypes[6]
+func (x *AlertRule) Reset() {
+	*x = AlertRule{}
+	mi := &file_iot_v1_service_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Timeframe) String() string {
+func (x *AlertRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Timeframe) ProtoMessage() {}
+func (*AlertRule) ProtoMessage() {}
 
-func (x *Timeframe) ProtoReflect() protoreflect.Message {
-	mi := &file_iot_v1_service_proto_msgTypes[6]
+func (x *AlertRule) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -424,49 +5449,96 @@ func (x *Timeframe) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Timeframe.ProtoReflect.Descriptor instead.
-func (*Timeframe) Descriptor() ([]byte, []int) {
-	return file_iot_v1_service_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use AlertRule.ProtoReflect.Descriptor instead.
+func (*AlertRule) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{84}
 }
 
-func (x *Timeframe) GetStart() *timestamppb.Timestamp {
+func (x *AlertRule) GetId() int64 {
 	if x != nil {
-		return x.Start
+		return x.Id
 	}
-	return nil
+	return 0
 }
 
-func (x *Timeframe) GetEnd() *timestamppb.Timestamp {
+func (x *AlertRule) GetMetric() string {
 	if x != nil {
-		return x.End
+		return x.Metric
 	}
-	return nil
+	return ""
 }
 
-type Alert struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Reason        Alert_Reason           `protobuf:"varint,2,opt,name=reason,proto3,enum=iot.v1.Alert_Reason" json:"reason,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+func (x *AlertRule) GetOperator() AlertRule_Operator {
+	if x != nil {
+		return x.Operator
+	}
+	return AlertRule_OPERATOR_UNSPECIFIED
+}
+
+func (x *AlertRule) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *AlertRule) GetThresholdHigh() float64 {
+	if x != nil {
+		return x.ThresholdHigh
+	}
+	return 0
+}
+
+func (x *AlertRule) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *AlertRule) GetWindowSeconds() int64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+// Operation is a long-running operation started by a request.
+type Operation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type  Operation_Type         `protobuf:"varint,2,opt,name=type,proto3,enum=iot.v1.Operation_Type" json:"type,omitempty"`
+	// The device the operation applies to.
+	DeviceId string          `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	State    Operation_State `protobuf:"varint,4,opt,name=state,proto3,enum=iot.v1.Operation_State" json:"state,omitempty"`
+	// Number of metrics deleted so far by a DELETE_DEVICE operation.
+	DeletedMetrics int64 `protobuf:"varint,5,opt,name=deleted_metrics,json=deletedMetrics,proto3" json:"deleted_metrics,omitempty"`
+	// Number of alerts deleted so far by a DELETE_DEVICE operation.
+	DeletedAlerts int64 `protobuf:"varint,6,opt,name=deleted_alerts,json=deletedAlerts,proto3" json:"deleted_alerts,omitempty"`
+	// Why the operation failed, empty unless it has failed.
+	Error     string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// When the operation last made progress or finished.
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Alert) Reset() {
-	*x = Alert{}
-	mi := &file_iot_v1_service_proto_msgTypes[7]
+func (x *Operation) Reset() {
+	*x = Operation{}
+	mi := &file_iot_v1_service_proto_msgTypes[85]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Alert) String() string {
+func (x *Operation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Alert) ProtoMessage() {}
+func (*Operation) ProtoMessage() {}
 
-func (x *Alert) ProtoReflect() protoreflect.Message {
-	mi := &file_iot_v1_service_proto_msgTypes[7]
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_iot_v1_service_proto_msgTypes[85]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -477,76 +5549,545 @@ func (x *Alert) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
-func (*Alert) Descriptor() ([]byte, []int) {
-	return file_iot_v1_service_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_iot_v1_service_proto_rawDescGZIP(), []int{85}
 }
 
-func (x *Alert) GetTimestamp() *timestamppb.Timestamp {
+func (x *Operation) GetId() int64 {
 	if x != nil {
-		return x.Timestamp
+		return x.Id
 	}
-	return nil
+	return 0
 }
 
-func (x *Alert) GetReason() Alert_Reason {
+func (x *Operation) GetType() Operation_Type {
 	if x != nil {
-		return x.Reason
+		return x.Type
 	}
-	return Alert_REASON_UNSPECIFIED
+	return Operation_TYPE_UNSPECIFIED
 }
 
-func (x *Alert) GetDescription() string {
+func (x *Operation) GetDeviceId() string {
 	if x != nil {
-		return x.Description
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *Operation) GetState() Operation_State {
+	if x != nil {
+		return x.State
+	}
+	return Operation_STATE_UNSPECIFIED
+}
+
+func (x *Operation) GetDeletedMetrics() int64 {
+	if x != nil {
+		return x.DeletedMetrics
+	}
+	return 0
+}
+
+func (x *Operation) GetDeletedAlerts() int64 {
+	if x != nil {
+		return x.DeletedAlerts
+	}
+	return 0
+}
+
+func (x *Operation) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
+func (x *Operation) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Operation) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
 var File_iot_v1_service_proto protoreflect.FileDescriptor
 
 const file_iot_v1_service_proto_rawDesc = "" +
 	"\n" +
-	"\x14iot/v1/service.proto\x12\x06iot.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa8\x01\n" +
+	"\x14iot/v1/service.proto\x12\x06iot.v1\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd5\x01\n" +
 	"\x13RecordMetricRequest\x12\x1b\n" +
 	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x128\n" +
 	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12 \n" +
 	"\vtemperature\x18\x03 \x01(\x01R\vtemperature\x12\x18\n" +
-	"\abattery\x18\x04 \x01(\x05R\abattery\"\x16\n" +
-	"\x14RecordMetricResponse\"\x97\x01\n" +
+	"\abattery\x18\x04 \x01(\x05R\abattery\x12+\n" +
+	"\x06values\x18\x05 \x03(\v2\x13.iot.v1.MetricValueR\x06values\"\x16\n" +
+	"\x14RecordMetricResponse\"]\n" +
+	"\x14RecordMetricsRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12(\n" +
+	"\ametrics\x18\x02 \x03(\v2\x0e.iot.v1.MetricR\ametrics\"3\n" +
+	"\x15RecordMetricsResponse\x12\x1a\n" +
+	"\brecorded\x18\x01 \x01(\x05R\brecorded\"[\n" +
+	"\x14StreamMetricsRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12&\n" +
+	"\x06metric\x18\x02 \x01(\v2\x0e.iot.v1.MetricR\x06metric\"~\n" +
+	"\x15StreamMetricsResponse\x12\"\n" +
+	"\facknowledged\x18\x01 \x01(\x03R\facknowledged\x12A\n" +
+	"\x0elast_timestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\rlastTimestamp\"\xa0\x05\n" +
 	"\x16ConfigureDeviceRequest\x12\x1b\n" +
-	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x123\n" +
-	"\x15temperature_threshold\x18\x02 \x01(\x01R\x14temperatureThreshold\x12+\n" +
-	"\x11battery_threshold\x18\x03 \x01(\x05R\x10batteryThreshold\"\x19\n" +
-	"\x17ConfigureDeviceResponse\"\xb5\x01\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x128\n" +
+	"\x15temperature_threshold\x18\x02 \x01(\x01H\x00R\x14temperatureThreshold\x88\x01\x01\x120\n" +
+	"\x11battery_threshold\x18\x03 \x01(\x05H\x01R\x10batteryThreshold\x88\x01\x01\x129\n" +
+	"\vexpressions\x18\x04 \x03(\v2\x17.iot.v1.AlertExpressionR\vexpressions\x126\n" +
+	"\x14consecutive_breaches\x18\x05 \x01(\x05H\x02R\x13consecutiveBreaches\x88\x01\x01\x12#\n" +
+	"\n" +
+	"hysteresis\x18\x06 \x01(\x01H\x03R\n" +
+	"hysteresis\x88\x01\x01\x123\n" +
+	"\tcooldowns\x18\a \x03(\v2\x15.iot.v1.AlertCooldownR\tcooldowns\x12A\n" +
+	"\x1areporting_interval_seconds\x18\b \x01(\x03H\x04R\x18reportingIntervalSeconds\x88\x01\x01\x12\x16\n" +
+	"\x06author\x18\t \x01(\tR\x06author\x12\x16\n" +
+	"\x06reason\x18\n" +
+	" \x01(\tR\x06reason\x120\n" +
+	"\x11expected_revision\x18\v \x01(\x03H\x05R\x10expectedRevision\x88\x01\x01B\x18\n" +
+	"\x16_temperature_thresholdB\x14\n" +
+	"\x12_battery_thresholdB\x17\n" +
+	"\x15_consecutive_breachesB\r\n" +
+	"\v_hysteresisB\x1d\n" +
+	"\x1b_reporting_interval_secondsB\x14\n" +
+	"\x12_expected_revision\"d\n" +
+	"\rAlertCooldown\x12,\n" +
+	"\x06reason\x18\x01 \x01(\x0e2\x14.iot.v1.Alert.ReasonR\x06reason\x12%\n" +
+	"\x0ewindow_seconds\x18\x02 \x01(\x03R\rwindowSeconds\"5\n" +
+	"\x17ConfigureDeviceResponse\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x03R\brevision\"5\n" +
+	"\x16GetDeviceConfigRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\"N\n" +
+	"\x17GetDeviceConfigResponse\x123\n" +
+	"\x06config\x18\x01 \x01(\v2\x1b.iot.v1.DeviceConfigVersionR\x06config\"\xa1\x02\n" +
+	"\x19UpdateDeviceConfigRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x122\n" +
+	"\bsettings\x18\x02 \x01(\v2\x16.iot.v1.ConfigSettingsR\bsettings\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12\x16\n" +
+	"\x06author\x18\x04 \x01(\tR\x06author\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x120\n" +
+	"\x11expected_revision\x18\x06 \x01(\x03H\x00R\x10expectedRevision\x88\x01\x01B\x14\n" +
+	"\x12_expected_revision\"Q\n" +
+	"\x1aUpdateDeviceConfigResponse\x123\n" +
+	"\x06config\x18\x01 \x01(\v2\x1b.iot.v1.DeviceConfigVersionR\x06config\"\xdf\x01\n" +
+	"\x17GetDeviceMetricsRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x124\n" +
+	"\ttimeframe\x18\x02 \x01(\v2\x11.iot.v1.TimeframeH\x00R\ttimeframe\x88\x01\x01\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12'\n" +
+	"\x05order\x18\x05 \x01(\x0e2\x11.iot.v1.SortOrderR\x05orderB\f\n" +
+	"\n" +
+	"_timeframe\"l\n" +
+	"\x18GetDeviceMetricsResponse\x12(\n" +
+	"\ametrics\x18\x01 \x03(\v2\x0e.iot.v1.MetricR\ametrics\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xc7\x01\n" +
+	" GetDeviceMetricAggregatesRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12/\n" +
+	"\ttimeframe\x18\x02 \x01(\v2\x11.iot.v1.TimeframeR\ttimeframe\x126\n" +
+	"\fbucket_width\x18\x03 \x01(\x0e2\x13.iot.v1.BucketWidthR\vbucketWidth\x12\x1d\n" +
+	"\n" +
+	"fill_empty\x18\x04 \x01(\bR\tfillEmpty\"W\n" +
+	"!GetDeviceMetricAggregatesResponse\x122\n" +
+	"\ametrics\x18\x01 \x03(\v2\x18.iot.v1.MetricAggregatesR\ametrics\"\xb9\x02\n" +
 	"\x16GetDeviceAlertsRequest\x12\x1b\n" +
 	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x124\n" +
 	"\ttimeframe\x18\x02 \x01(\v2\x11.iot.v1.TimeframeH\x00R\ttimeframe\x88\x01\x01\x12\x1b\n" +
 	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
 	"\n" +
-	"page_token\x18\x04 \x01(\tR\tpageTokenB\f\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12)\n" +
+	"\x05state\x18\x05 \x01(\x0e2\x13.iot.v1.Alert.StateR\x05state\x12.\n" +
+	"\areasons\x18\x06 \x03(\x0e2\x14.iot.v1.Alert.ReasonR\areasons\x12'\n" +
+	"\x05order\x18\a \x01(\x0e2\x11.iot.v1.SortOrderR\x05orderB\f\n" +
 	"\n" +
 	"_timeframe\"h\n" +
 	"\x17GetDeviceAlertsResponse\x12%\n" +
 	"\x06alerts\x18\x01 \x03(\v2\r.iot.v1.AlertR\x06alerts\x12&\n" +
-	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x87\x01\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xe4\x02\n" +
+	"\x13SearchAlertsRequest\x12\x1d\n" +
+	"\n" +
+	"device_ids\x18\x01 \x03(\tR\tdeviceIds\x12%\n" +
+	"\x0elabel_selector\x18\x02 \x01(\tR\rlabelSelector\x12.\n" +
+	"\areasons\x18\x03 \x03(\x0e2\x14.iot.v1.Alert.ReasonR\areasons\x12,\n" +
+	"\bseverity\x18\x04 \x01(\x0e2\x10.iot.v1.SeverityR\bseverity\x12)\n" +
+	"\x05state\x18\x05 \x01(\x0e2\x13.iot.v1.Alert.StateR\x05state\x124\n" +
+	"\ttimeframe\x18\x06 \x01(\v2\x11.iot.v1.TimeframeH\x00R\ttimeframe\x88\x01\x01\x12\x1b\n" +
+	"\tpage_size\x18\a \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\b \x01(\tR\tpageTokenB\f\n" +
+	"\n" +
+	"_timeframe\"e\n" +
+	"\x14SearchAlertsResponse\x12%\n" +
+	"\x06alerts\x18\x01 \x03(\v2\r.iot.v1.AlertR\x06alerts\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x94\x01\n" +
+	"\x14GetAlertStatsRequest\x12/\n" +
+	"\ttimeframe\x18\x01 \x01(\v2\x11.iot.v1.TimeframeR\ttimeframe\x126\n" +
+	"\fbucket_width\x18\x02 \x01(\x0e2\x13.iot.v1.BucketWidthR\vbucketWidth\x12\x13\n" +
+	"\x05top_n\x18\x03 \x01(\x05R\x04topN\"\x8a\x02\n" +
+	"\x15GetAlertStatsResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x03R\x05total\x125\n" +
+	"\tby_reason\x18\x02 \x03(\v2\x18.iot.v1.ReasonAlertCountR\bbyReason\x125\n" +
+	"\tby_device\x18\x03 \x03(\v2\x18.iot.v1.DeviceAlertCountR\bbyDevice\x122\n" +
+	"\abuckets\x18\x04 \x03(\v2\x18.iot.v1.AlertCountBucketR\abuckets\x129\n" +
+	"\vtop_devices\x18\x05 \x03(\v2\x18.iot.v1.DeviceAlertCountR\n" +
+	"topDevices\"\x7f\n" +
+	"\x18WatchDeviceAlertsRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12.\n" +
+	"\areasons\x18\x02 \x03(\x0e2\x14.iot.v1.Alert.ReasonR\areasons\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"X\n" +
+	"\x19WatchDeviceAlertsResponse\x12#\n" +
+	"\x05alert\x18\x01 \x01(\v2\r.iot.v1.AlertR\x05alert\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\"\\\n" +
+	"\x16CreateAlertRuleRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12%\n" +
+	"\x04rule\x18\x02 \x01(\v2\x11.iot.v1.AlertRuleR\x04rule\"@\n" +
+	"\x17CreateAlertRuleResponse\x12%\n" +
+	"\x04rule\x18\x01 \x01(\v2\x11.iot.v1.AlertRuleR\x04rule\"K\n" +
+	"\x13GetAlertRuleRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x17\n" +
+	"\arule_id\x18\x02 \x01(\x03R\x06ruleId\"=\n" +
+	"\x14GetAlertRuleResponse\x12%\n" +
+	"\x04rule\x18\x01 \x01(\v2\x11.iot.v1.AlertRuleR\x04rule\"4\n" +
+	"\x15ListAlertRulesRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\"A\n" +
+	"\x16ListAlertRulesResponse\x12'\n" +
+	"\x05rules\x18\x01 \x03(\v2\x11.iot.v1.AlertRuleR\x05rules\"\\\n" +
+	"\x16UpdateAlertRuleRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12%\n" +
+	"\x04rule\x18\x02 \x01(\v2\x11.iot.v1.AlertRuleR\x04rule\"@\n" +
+	"\x17UpdateAlertRuleResponse\x12%\n" +
+	"\x04rule\x18\x01 \x01(\v2\x11.iot.v1.AlertRuleR\x04rule\"N\n" +
+	"\x16DeleteAlertRuleRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x17\n" +
+	"\arule_id\x18\x02 \x01(\x03R\x06ruleId\"\x19\n" +
+	"\x17DeleteAlertRuleResponse\"\x94\x01\n" +
+	"\x17AcknowledgeAlertRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x19\n" +
+	"\balert_id\x18\x02 \x01(\x03R\aalertId\x12'\n" +
+	"\x0facknowledged_by\x18\x03 \x01(\tR\x0eacknowledgedBy\x12\x18\n" +
+	"\acomment\x18\x04 \x01(\tR\acomment\"?\n" +
+	"\x18AcknowledgeAlertResponse\x12#\n" +
+	"\x05alert\x18\x01 \x01(\v2\r.iot.v1.AlertR\x05alert\"M\n" +
+	"\x13ResolveAlertRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x19\n" +
+	"\balert_id\x18\x02 \x01(\x03R\aalertId\";\n" +
+	"\x14ResolveAlertResponse\x12#\n" +
+	"\x05alert\x18\x01 \x01(\v2\r.iot.v1.AlertR\x05alert\"Z\n" +
+	"\x13CreateDeviceRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12&\n" +
+	"\x06device\x18\x02 \x01(\v2\x0e.iot.v1.DeviceR\x06device\">\n" +
+	"\x14CreateDeviceResponse\x12&\n" +
+	"\x06device\x18\x01 \x01(\v2\x0e.iot.v1.DeviceR\x06device\"/\n" +
+	"\x10GetDeviceRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\";\n" +
+	"\x11GetDeviceResponse\x12&\n" +
+	"\x06device\x18\x01 \x01(\v2\x0e.iot.v1.DeviceR\x06device\"2\n" +
+	"\x13DeleteDeviceRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\"G\n" +
+	"\x14DeleteDeviceResponse\x12/\n" +
+	"\toperation\x18\x01 \x01(\v2\x11.iot.v1.OperationR\toperation\"8\n" +
+	"\x13GetOperationRequest\x12!\n" +
+	"\foperation_id\x18\x01 \x01(\x03R\voperationId\"G\n" +
+	"\x14GetOperationResponse\x12/\n" +
+	"\toperation\x18\x01 \x01(\v2\x11.iot.v1.OperationR\toperation\"Z\n" +
+	"\x13UpdateDeviceRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12&\n" +
+	"\x06device\x18\x02 \x01(\v2\x0e.iot.v1.DeviceR\x06device\">\n" +
+	"\x14UpdateDeviceResponse\x12&\n" +
+	"\x06device\x18\x01 \x01(\v2\x0e.iot.v1.DeviceR\x06device\"w\n" +
+	"\x12ListDevicesRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12%\n" +
+	"\x0elabel_selector\x18\x03 \x01(\tR\rlabelSelector\"g\n" +
+	"\x13ListDevicesResponse\x12(\n" +
+	"\adevices\x18\x01 \x03(\v2\x0e.iot.v1.DeviceR\adevices\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x97\x01\n" +
+	"\x17GetFleetSnapshotRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12%\n" +
+	"\x0elabel_selector\x18\x03 \x01(\tR\rlabelSelector\x12\x19\n" +
+	"\bgroup_id\x18\x04 \x01(\tR\agroupId\"t\n" +
+	"\x18GetFleetSnapshotResponse\x120\n" +
+	"\adevices\x18\x01 \x03(\v2\x16.iot.v1.DeviceSnapshotR\adevices\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"X\n" +
+	"\x18CreateDeviceGroupRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\"F\n" +
+	"\x19CreateDeviceGroupResponse\x12)\n" +
+	"\x05group\x18\x01 \x01(\v2\x13.iot.v1.DeviceGroupR\x05group\"2\n" +
+	"\x15GetDeviceGroupRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\"C\n" +
+	"\x16GetDeviceGroupResponse\x12)\n" +
+	"\x05group\x18\x01 \x01(\v2\x13.iot.v1.DeviceGroupR\x05group\"\x19\n" +
+	"\x17ListDeviceGroupsRequest\"G\n" +
+	"\x18ListDeviceGroupsResponse\x12+\n" +
+	"\x06groups\x18\x01 \x03(\v2\x13.iot.v1.DeviceGroupR\x06groups\"l\n" +
+	"\x1bConfigureDeviceGroupRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\x122\n" +
+	"\bsettings\x18\x02 \x01(\v2\x16.iot.v1.ConfigSettingsR\bsettings\"\x1e\n" +
+	"\x1cConfigureDeviceGroupResponse\"N\n" +
+	"\x18ConfigureDefaultsRequest\x122\n" +
+	"\bsettings\x18\x01 \x01(\v2\x16.iot.v1.ConfigSettingsR\bsettings\"\x1b\n" +
+	"\x19ConfigureDefaultsResponse\">\n" +
+	"\x1fGetEffectiveDeviceConfigRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\"\x93\x02\n" +
+	" GetEffectiveDeviceConfigResponse\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\x122\n" +
+	"\bsettings\x18\x02 \x01(\v2\x16.iot.v1.ConfigSettingsR\bsettings\x12O\n" +
+	"\asources\x18\x03 \x03(\v25.iot.v1.GetEffectiveDeviceConfigResponse.SourcesEntryR\asources\x1aO\n" +
+	"\fSourcesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12)\n" +
+	"\x05value\x18\x02 \x01(\x0e2\x13.iot.v1.ConfigLevelR\x05value:\x028\x01\"z\n" +
+	"\x1fListDeviceConfigVersionsRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"\x83\x01\n" +
+	" ListDeviceConfigVersionsResponse\x127\n" +
+	"\bversions\x18\x01 \x03(\v2\x1b.iot.v1.DeviceConfigVersionR\bversions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xcc\x01\n" +
+	"\x1bRollbackDeviceConfigRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\x12\x16\n" +
+	"\x06author\x18\x03 \x01(\tR\x06author\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x120\n" +
+	"\x11expected_revision\x18\x05 \x01(\x03H\x00R\x10expectedRevision\x88\x01\x01B\x14\n" +
+	"\x12_expected_revision\"U\n" +
+	"\x1cRollbackDeviceConfigResponse\x125\n" +
+	"\aversion\x18\x01 \x01(\v2\x1b.iot.v1.DeviceConfigVersionR\aversion\"\xce\x01\n" +
+	"\x13DeviceConfigVersion\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\x122\n" +
+	"\bsettings\x18\x02 \x01(\v2\x16.iot.v1.ConfigSettingsR\bsettings\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x16\n" +
+	"\x06author\x18\x04 \x01(\tR\x06author\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\"\x83\x04\n" +
+	"\x0eConfigSettings\x128\n" +
+	"\x15temperature_threshold\x18\x01 \x01(\x01H\x00R\x14temperatureThreshold\x88\x01\x01\x120\n" +
+	"\x11battery_threshold\x18\x02 \x01(\x05H\x01R\x10batteryThreshold\x88\x01\x01\x129\n" +
+	"\vexpressions\x18\x03 \x03(\v2\x17.iot.v1.AlertExpressionR\vexpressions\x126\n" +
+	"\x14consecutive_breaches\x18\x04 \x01(\x05H\x02R\x13consecutiveBreaches\x88\x01\x01\x12#\n" +
+	"\n" +
+	"hysteresis\x18\x05 \x01(\x01H\x03R\n" +
+	"hysteresis\x88\x01\x01\x123\n" +
+	"\tcooldowns\x18\x06 \x03(\v2\x15.iot.v1.AlertCooldownR\tcooldowns\x12A\n" +
+	"\x1areporting_interval_seconds\x18\a \x01(\x03H\x04R\x18reportingIntervalSeconds\x88\x01\x01B\x18\n" +
+	"\x16_temperature_thresholdB\x14\n" +
+	"\x12_battery_thresholdB\x17\n" +
+	"\x15_consecutive_breachesB\r\n" +
+	"\v_hysteresisB\x1d\n" +
+	"\x1b_reporting_interval_seconds\"\x87\x01\n" +
 	"\tTimeframe\x125\n" +
 	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\x05start\x88\x01\x01\x121\n" +
 	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampH\x01R\x03end\x88\x01\x01B\b\n" +
 	"\x06_startB\x06\n" +
-	"\x04_end\"\xe8\x01\n" +
+	"\x04_end\"\xab\x01\n" +
+	"\x06Metric\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12 \n" +
+	"\vtemperature\x18\x02 \x01(\x01R\vtemperature\x12\x18\n" +
+	"\abattery\x18\x03 \x01(\x05R\abattery\x12+\n" +
+	"\x06values\x18\x04 \x03(\v2\x13.iot.v1.MetricValueR\x06values\"K\n" +
+	"\vMetricValue\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\x12\x12\n" +
+	"\x04unit\x18\x03 \x01(\tR\x04unit\"V\n" +
+	"\x10MetricAggregates\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12.\n" +
+	"\abuckets\x18\x02 \x03(\v2\x14.iot.v1.MetricBucketR\abuckets\"\xfa\x01\n" +
+	"\fMetricBucket\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x15\n" +
+	"\x03min\x18\x03 \x01(\x01H\x00R\x03min\x88\x01\x01\x12\x15\n" +
+	"\x03max\x18\x04 \x01(\x01H\x01R\x03max\x88\x01\x01\x12\x15\n" +
+	"\x03avg\x18\x05 \x01(\x01H\x02R\x03avg\x88\x01\x01\x12\x19\n" +
+	"\x05first\x18\x06 \x01(\x01H\x03R\x05first\x88\x01\x01\x12\x17\n" +
+	"\x04last\x18\a \x01(\x01H\x04R\x04last\x88\x01\x01B\x06\n" +
+	"\x04_minB\x06\n" +
+	"\x04_maxB\x06\n" +
+	"\x04_avgB\b\n" +
+	"\x06_firstB\a\n" +
+	"\x05_last\"V\n" +
+	"\x10ReasonAlertCount\x12,\n" +
+	"\x06reason\x18\x01 \x01(\x0e2\x14.iot.v1.Alert.ReasonR\x06reason\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"E\n" +
+	"\x10DeviceAlertCount\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"Z\n" +
+	"\x10AlertCountBucket\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xe7\a\n" +
 	"\x05Alert\x128\n" +
 	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12,\n" +
 	"\x06reason\x18\x02 \x01(\x0e2\x14.iot.v1.Alert.ReasonR\x06reason\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\"U\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tdevice_id\x18\x04 \x01(\tR\bdeviceId\x12\x17\n" +
+	"\arule_id\x18\x05 \x01(\x03R\x06ruleId\x12,\n" +
+	"\bseverity\x18\x06 \x01(\x0e2\x10.iot.v1.SeverityR\bseverity\x12\x0e\n" +
+	"\x02id\x18\a \x01(\x03R\x02id\x12)\n" +
+	"\x05state\x18\b \x01(\x0e2\x13.iot.v1.Alert.StateR\x05state\x12'\n" +
+	"\x0facknowledged_by\x18\t \x01(\tR\x0eacknowledgedBy\x12\x1f\n" +
+	"\vack_comment\x18\n" +
+	" \x01(\tR\n" +
+	"ackComment\x12H\n" +
+	"\x0facknowledged_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampH\x00R\x0eacknowledgedAt\x88\x01\x01\x12@\n" +
+	"\vresolved_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampH\x01R\n" +
+	"resolvedAt\x88\x01\x01\x12 \n" +
+	"\voccurrences\x18\r \x01(\x03R\voccurrences\x127\n" +
+	"\tlast_seen\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\x12%\n" +
+	"\x0econfig_version\x18\x0f \x01(\x03R\rconfigVersion\"\xdc\x01\n" +
 	"\x06Reason\x12\x16\n" +
 	"\x12REASON_UNSPECIFIED\x10\x00\x12\x1b\n" +
 	"\x17REASON_TEMPERATURE_HIGH\x10\x01\x12\x16\n" +
-	"\x12REASON_BATTERY_LOW\x10\x022\x88\x02\n" +
+	"\x12REASON_BATTERY_LOW\x10\x02\x12\x1d\n" +
+	"\x19REASON_THRESHOLD_BREACHED\x10\x03\x12\x1d\n" +
+	"\x19REASON_EXPRESSION_MATCHED\x10\x04\x12\x15\n" +
+	"\x11REASON_RAPID_RISE\x10\x05\x12\x15\n" +
+	"\x11REASON_RAPID_DROP\x10\x06\x12\x19\n" +
+	"\x15REASON_DEVICE_OFFLINE\x10\a\"Z\n" +
+	"\x05State\x12\x15\n" +
+	"\x11STATE_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"STATE_OPEN\x10\x01\x12\x16\n" +
+	"\x12STATE_ACKNOWLEDGED\x10\x02\x12\x12\n" +
+	"\x0eSTATE_RESOLVED\x10\x03B\x12\n" +
+	"\x10_acknowledged_atB\x0e\n" +
+	"\f_resolved_at\"\xa9\x03\n" +
+	"\x06Device\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12)\n" +
+	"\x10firmware_version\x18\x04 \x01(\tR\x0ffirmwareVersion\x12\x1a\n" +
+	"\blocation\x18\x05 \x01(\tR\blocation\x122\n" +
+	"\x06labels\x18\x06 \x03(\v2\x1a.iot.v1.Device.LabelsEntryR\x06labels\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12<\n" +
+	"\tlast_seen\x18\b \x01(\v2\x1a.google.protobuf.TimestampH\x00R\blastSeen\x88\x01\x01\x12\x19\n" +
+	"\bgroup_id\x18\t \x01(\tR\agroupId\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\f\n" +
+	"\n" +
+	"_last_seen\"\x8e\x01\n" +
+	"\x0eDeviceSnapshot\x12&\n" +
+	"\x06device\x18\x01 \x01(\v2\x0e.iot.v1.DeviceR\x06device\x123\n" +
+	"\rlatest_metric\x18\x02 \x01(\v2\x0e.iot.v1.MetricR\flatestMetric\x12\x1f\n" +
+	"\vopen_alerts\x18\x03 \x01(\x03R\n" +
+	"openAlerts\"{\n" +
+	"\vDeviceGroup\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"S\n" +
+	"\x0fAlertExpression\x12\x12\n" +
+	"\x04expr\x18\x01 \x01(\tR\x04expr\x12,\n" +
+	"\bseverity\x18\x02 \x01(\x0e2\x10.iot.v1.SeverityR\bseverity\"\x95\x04\n" +
+	"\tAlertRule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06metric\x18\x02 \x01(\tR\x06metric\x126\n" +
+	"\boperator\x18\x03 \x01(\x0e2\x1a.iot.v1.AlertRule.OperatorR\boperator\x12\x1c\n" +
+	"\tthreshold\x18\x04 \x01(\x01R\tthreshold\x12%\n" +
+	"\x0ethreshold_high\x18\x05 \x01(\x01R\rthresholdHigh\x12,\n" +
+	"\bseverity\x18\x06 \x01(\x0e2\x10.iot.v1.SeverityR\bseverity\x12%\n" +
+	"\x0ewindow_seconds\x18\a \x01(\x03R\rwindowSeconds\"\x8d\x02\n" +
+	"\bOperator\x12\x18\n" +
+	"\x14OPERATOR_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15OPERATOR_GREATER_THAN\x10\x01\x12\"\n" +
+	"\x1eOPERATOR_GREATER_THAN_OR_EQUAL\x10\x02\x12\x16\n" +
+	"\x12OPERATOR_LESS_THAN\x10\x03\x12\x1f\n" +
+	"\x1bOPERATOR_LESS_THAN_OR_EQUAL\x10\x04\x12\x12\n" +
+	"\x0eOPERATOR_EQUAL\x10\x05\x12\x1a\n" +
+	"\x16OPERATOR_OUTSIDE_RANGE\x10\x06\x12\x19\n" +
+	"\x15OPERATOR_INSIDE_RANGE\x10\a\x12\x11\n" +
+	"\rOPERATOR_RISE\x10\b\x12\x11\n" +
+	"\rOPERATOR_DROP\x10\t\"\xff\x03\n" +
+	"\tOperation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12*\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x16.iot.v1.Operation.TypeR\x04type\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12-\n" +
+	"\x05state\x18\x04 \x01(\x0e2\x17.iot.v1.Operation.StateR\x05state\x12'\n" +
+	"\x0fdeleted_metrics\x18\x05 \x01(\x03R\x0edeletedMetrics\x12%\n" +
+	"\x0edeleted_alerts\x18\x06 \x01(\x03R\rdeletedAlerts\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"4\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12TYPE_DELETE_DEVICE\x10\x01\"X\n" +
+	"\x05State\x12\x15\n" +
+	"\x11STATE_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rSTATE_RUNNING\x10\x01\x12\x13\n" +
+	"\x0fSTATE_SUCCEEDED\x10\x02\x12\x10\n" +
+	"\fSTATE_FAILED\x10\x03*u\n" +
+	"\vConfigLevel\x12\x1c\n" +
+	"\x18CONFIG_LEVEL_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13CONFIG_LEVEL_DEVICE\x10\x01\x12\x16\n" +
+	"\x12CONFIG_LEVEL_GROUP\x10\x02\x12\x17\n" +
+	"\x13CONFIG_LEVEL_GLOBAL\x10\x03*\x7f\n" +
+	"\vBucketWidth\x12\x1c\n" +
+	"\x18BUCKET_WIDTH_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fBUCKET_WIDTH_1M\x10\x01\x12\x13\n" +
+	"\x0fBUCKET_WIDTH_5M\x10\x02\x12\x13\n" +
+	"\x0fBUCKET_WIDTH_1H\x10\x03\x12\x13\n" +
+	"\x0fBUCKET_WIDTH_1D\x10\x04*P\n" +
+	"\tSortOrder\x12\x1a\n" +
+	"\x16SORT_ORDER_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fSORT_ORDER_DESC\x10\x01\x12\x12\n" +
+	"\x0eSORT_ORDER_ASC\x10\x02*d\n" +
+	"\bSeverity\x12\x18\n" +
+	"\x14SEVERITY_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rSEVERITY_INFO\x10\x01\x12\x14\n" +
+	"\x10SEVERITY_WARNING\x10\x02\x12\x15\n" +
+	"\x11SEVERITY_CRITICAL\x10\x032\x9c\x17\n" +
 	"\rDeviceService\x12K\n" +
-	"\fRecordMetric\x12\x1b.iot.v1.RecordMetricRequest\x1a\x1c.iot.v1.RecordMetricResponse\"\x00\x12T\n" +
+	"\fRecordMetric\x12\x1b.iot.v1.RecordMetricRequest\x1a\x1c.iot.v1.RecordMetricResponse\"\x00\x12N\n" +
+	"\rRecordMetrics\x12\x1c.iot.v1.RecordMetricsRequest\x1a\x1d.iot.v1.RecordMetricsResponse\"\x00\x12R\n" +
+	"\rStreamMetrics\x12\x1c.iot.v1.StreamMetricsRequest\x1a\x1d.iot.v1.StreamMetricsResponse\"\x00(\x010\x01\x12T\n" +
 	"\x0fConfigureDevice\x12\x1e.iot.v1.ConfigureDeviceRequest\x1a\x1f.iot.v1.ConfigureDeviceResponse\"\x00\x12T\n" +
-	"\x0fGetDeviceAlerts\x12\x1e.iot.v1.GetDeviceAlertsRequest\x1a\x1f.iot.v1.GetDeviceAlertsResponse\"\x00B\x8a\x01\n" +
+	"\x0fGetDeviceConfig\x12\x1e.iot.v1.GetDeviceConfigRequest\x1a\x1f.iot.v1.GetDeviceConfigResponse\"\x00\x12]\n" +
+	"\x12UpdateDeviceConfig\x12!.iot.v1.UpdateDeviceConfigRequest\x1a\".iot.v1.UpdateDeviceConfigResponse\"\x00\x12W\n" +
+	"\x10GetDeviceMetrics\x12\x1f.iot.v1.GetDeviceMetricsRequest\x1a .iot.v1.GetDeviceMetricsResponse\"\x00\x12r\n" +
+	"\x19GetDeviceMetricAggregates\x12(.iot.v1.GetDeviceMetricAggregatesRequest\x1a).iot.v1.GetDeviceMetricAggregatesResponse\"\x00\x12T\n" +
+	"\x0fGetDeviceAlerts\x12\x1e.iot.v1.GetDeviceAlertsRequest\x1a\x1f.iot.v1.GetDeviceAlertsResponse\"\x00\x12K\n" +
+	"\fSearchAlerts\x12\x1b.iot.v1.SearchAlertsRequest\x1a\x1c.iot.v1.SearchAlertsResponse\"\x00\x12N\n" +
+	"\rGetAlertStats\x12\x1c.iot.v1.GetAlertStatsRequest\x1a\x1d.iot.v1.GetAlertStatsResponse\"\x00\x12\\\n" +
+	"\x11WatchDeviceAlerts\x12 .iot.v1.WatchDeviceAlertsRequest\x1a!.iot.v1.WatchDeviceAlertsResponse\"\x000\x01\x12T\n" +
+	"\x0fCreateAlertRule\x12\x1e.iot.v1.CreateAlertRuleRequest\x1a\x1f.iot.v1.CreateAlertRuleResponse\"\x00\x12K\n" +
+	"\fGetAlertRule\x12\x1b.iot.v1.GetAlertRuleRequest\x1a\x1c.iot.v1.GetAlertRuleResponse\"\x00\x12Q\n" +
+	"\x0eListAlertRules\x12\x1d.iot.v1.ListAlertRulesRequest\x1a\x1e.iot.v1.ListAlertRulesResponse\"\x00\x12T\n" +
+	"\x0fUpdateAlertRule\x12\x1e.iot.v1.UpdateAlertRuleRequest\x1a\x1f.iot.v1.UpdateAlertRuleResponse\"\x00\x12T\n" +
+	"\x0fDeleteAlertRule\x12\x1e.iot.v1.DeleteAlertRuleRequest\x1a\x1f.iot.v1.DeleteAlertRuleResponse\"\x00\x12W\n" +
+	"\x10AcknowledgeAlert\x12\x1f.iot.v1.AcknowledgeAlertRequest\x1a .iot.v1.AcknowledgeAlertResponse\"\x00\x12K\n" +
+	"\fResolveAlert\x12\x1b.iot.v1.ResolveAlertRequest\x1a\x1c.iot.v1.ResolveAlertResponse\"\x00\x12K\n" +
+	"\fCreateDevice\x12\x1b.iot.v1.CreateDeviceRequest\x1a\x1c.iot.v1.CreateDeviceResponse\"\x00\x12B\n" +
+	"\tGetDevice\x12\x18.iot.v1.GetDeviceRequest\x1a\x19.iot.v1.GetDeviceResponse\"\x00\x12K\n" +
+	"\fUpdateDevice\x12\x1b.iot.v1.UpdateDeviceRequest\x1a\x1c.iot.v1.UpdateDeviceResponse\"\x00\x12H\n" +
+	"\vListDevices\x12\x1a.iot.v1.ListDevicesRequest\x1a\x1b.iot.v1.ListDevicesResponse\"\x00\x12W\n" +
+	"\x10GetFleetSnapshot\x12\x1f.iot.v1.GetFleetSnapshotRequest\x1a .iot.v1.GetFleetSnapshotResponse\"\x00\x12K\n" +
+	"\fDeleteDevice\x12\x1b.iot.v1.DeleteDeviceRequest\x1a\x1c.iot.v1.DeleteDeviceResponse\"\x00\x12K\n" +
+	"\fGetOperation\x12\x1b.iot.v1.GetOperationRequest\x1a\x1c.iot.v1.GetOperationResponse\"\x00\x12Z\n" +
+	"\x11CreateDeviceGroup\x12 .iot.v1.CreateDeviceGroupRequest\x1a!.iot.v1.CreateDeviceGroupResponse\"\x00\x12Q\n" +
+	"\x0eGetDeviceGroup\x12\x1d.iot.v1.GetDeviceGroupRequest\x1a\x1e.iot.v1.GetDeviceGroupResponse\"\x00\x12W\n" +
+	"\x10ListDeviceGroups\x12\x1f.iot.v1.ListDeviceGroupsRequest\x1a .iot.v1.ListDeviceGroupsResponse\"\x00\x12c\n" +
+	"\x14ConfigureDeviceGroup\x12#.iot.v1.ConfigureDeviceGroupRequest\x1a$.iot.v1.ConfigureDeviceGroupResponse\"\x00\x12Z\n" +
+	"\x11ConfigureDefaults\x12 .iot.v1.ConfigureDefaultsRequest\x1a!.iot.v1.ConfigureDefaultsResponse\"\x00\x12o\n" +
+	"\x18GetEffectiveDeviceConfig\x12'.iot.v1.GetEffectiveDeviceConfigRequest\x1a(.iot.v1.GetEffectiveDeviceConfigResponse\"\x00\x12o\n" +
+	"\x18ListDeviceConfigVersions\x12'.iot.v1.ListDeviceConfigVersionsRequest\x1a(.iot.v1.ListDeviceConfigVersionsResponse\"\x00\x12c\n" +
+	"\x14RollbackDeviceConfig\x12#.iot.v1.RollbackDeviceConfigRequest\x1a$.iot.v1.RollbackDeviceConfigResponse\"\x00B\x8a\x01\n" +
 	"\n" +
 	"com.iot.v1B\fServiceProtoP\x01Z5github.com/joshjon/iot-metrics/proto/gen/iot/v1;iotv1\xa2\x02\x03IXX\xaa\x02\x06Iot.V1\xca\x02\x06Iot\\V1\xe2\x02\x12Iot\\V1\\GPBMetadata\xea\x02\aIot::V1b\x06proto3"
 
@@ -562,39 +6103,278 @@ func file_iot_v1_service_proto_rawDescGZIP() []byte {
 	return file_iot_v1_service_proto_rawDescData
 }
 
-var file_iot_v1_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_iot_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_iot_v1_service_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_iot_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 88)
 var file_iot_v1_service_proto_goTypes = []any{
-	(Alert_Reason)(0),               // 0: iot.v1.Alert.Reason
-	(*RecordMetricRequest)(nil),     // 1: iot.v1.RecordMetricRequest
-	(*RecordMetricResponse)(nil),    // 2: iot.v1.RecordMetricResponse
-	(*ConfigureDeviceRequest)(nil),  // 3: iot.v1.ConfigureDeviceRequest
-	(*ConfigureDeviceResponse)(nil), // 4: iot.v1.ConfigureDeviceResponse
-	(*GetDeviceAlertsRequest)(nil),  // 5: iot.v1.GetDeviceAlertsRequest
-	(*GetDeviceAlertsResponse)(nil), // 6: iot.v1.GetDeviceAlertsResponse
-	(*Timeframe)(nil),               // 7: iot.v1.Timeframe
-	(*Alert)(nil),                   // 8: iot.v1.Alert
-	(*timestamppb.Timestamp)(nil),   // 9: google.protobuf.Timestamp
+	(ConfigLevel)(0),                          // 0: iot.v1.ConfigLevel
+	(BucketWidth)(0),                          // 1: iot.v1.BucketWidth
+	(SortOrder)(0),                            // 2: iot.v1.SortOrder
+	(Severity)(0),                             // 3: iot.v1.Severity
+	(Alert_Reason)(0),                         // 4: iot.v1.Alert.Reason
+	(Alert_State)(0),                          // 5: iot.v1.Alert.State
+	(AlertRule_Operator)(0),                   // 6: iot.v1.AlertRule.Operator
+	(Operation_Type)(0),                       // 7: iot.v1.Operation.Type
+	(Operation_State)(0),                      // 8: iot.v1.Operation.State
+	(*RecordMetricRequest)(nil),               // 9: iot.v1.RecordMetricRequest
+	(*RecordMetricResponse)(nil),              // 10: iot.v1.RecordMetricResponse
+	(*RecordMetricsRequest)(nil),              // 11: iot.v1.RecordMetricsRequest
+	(*RecordMetricsResponse)(nil),             // 12: iot.v1.RecordMetricsResponse
+	(*StreamMetricsRequest)(nil),              // 13: iot.v1.StreamMetricsRequest
+	(*StreamMetricsResponse)(nil),             // 14: iot.v1.StreamMetricsResponse
+	(*ConfigureDeviceRequest)(nil),            // 15: iot.v1.ConfigureDeviceRequest
+	(*AlertCooldown)(nil),                     // 16: iot.v1.AlertCooldown
+	(*ConfigureDeviceResponse)(nil),           // 17: iot.v1.ConfigureDeviceResponse
+	(*GetDeviceConfigRequest)(nil),            // 18: iot.v1.GetDeviceConfigRequest
+	(*GetDeviceConfigResponse)(nil),           // 19: iot.v1.GetDeviceConfigResponse
+	(*UpdateDeviceConfigRequest)(nil),         // 20: iot.v1.UpdateDeviceConfigRequest
+	(*UpdateDeviceConfigResponse)(nil),        // 21: iot.v1.UpdateDeviceConfigResponse
+	(*GetDeviceMetricsRequest)(nil),           // 22: iot.v1.GetDeviceMetricsRequest
+	(*GetDeviceMetricsResponse)(nil),          // 23: iot.v1.GetDeviceMetricsResponse
+	(*GetDeviceMetricAggregatesRequest)(nil),  // 24: iot.v1.GetDeviceMetricAggregatesRequest
+	(*GetDeviceMetricAggregatesResponse)(nil), // 25: iot.v1.GetDeviceMetricAggregatesResponse
+	(*GetDeviceAlertsRequest)(nil),            // 26: iot.v1.GetDeviceAlertsRequest
+	(*GetDeviceAlertsResponse)(nil),           // 27: iot.v1.GetDeviceAlertsResponse
+	(*SearchAlertsRequest)(nil),               // 28: iot.v1.SearchAlertsRequest
+	(*SearchAlertsResponse)(nil),              // 29: iot.v1.SearchAlertsResponse
+	(*GetAlertStatsRequest)(nil),              // 30: iot.v1.GetAlertStatsRequest
+	(*GetAlertStatsResponse)(nil),             // 31: iot.v1.GetAlertStatsResponse
+	(*WatchDeviceAlertsRequest)(nil),          // 32: iot.v1.WatchDeviceAlertsRequest
+	(*WatchDeviceAlertsResponse)(nil),         // 33: iot.v1.WatchDeviceAlertsResponse
+	(*CreateAlertRuleRequest)(nil),            // 34: iot.v1.CreateAlertRuleRequest
+	(*CreateAlertRuleResponse)(nil),           // 35: iot.v1.CreateAlertRuleResponse
+	(*GetAlertRuleRequest)(nil),               // 36: iot.v1.GetAlertRuleRequest
+	(*GetAlertRuleResponse)(nil),              // 37: iot.v1.GetAlertRuleResponse
+	(*ListAlertRulesRequest)(nil),             // 38: iot.v1.ListAlertRulesRequest
+	(*ListAlertRulesResponse)(nil),            // 39: iot.v1.ListAlertRulesResponse
+	(*UpdateAlertRuleRequest)(nil),            // 40: iot.v1.UpdateAlertRuleRequest
+	(*UpdateAlertRuleResponse)(nil),           // 41: iot.v1.UpdateAlertRuleResponse
+	(*DeleteAlertRuleRequest)(nil),            // 42: iot.v1.DeleteAlertRuleRequest
+	(*DeleteAlertRuleResponse)(nil),           // 43: iot.v1.DeleteAlertRuleResponse
+	(*AcknowledgeAlertRequest)(nil),           // 44: iot.v1.AcknowledgeAlertRequest
+	(*AcknowledgeAlertResponse)(nil),          // 45: iot.v1.AcknowledgeAlertResponse
+	(*ResolveAlertRequest)(nil),               // 46: iot.v1.ResolveAlertRequest
+	(*ResolveAlertResponse)(nil),              // 47: iot.v1.ResolveAlertResponse
+	(*CreateDeviceRequest)(nil),               // 48: iot.v1.CreateDeviceRequest
+	(*CreateDeviceResponse)(nil),              // 49: iot.v1.CreateDeviceResponse
+	(*GetDeviceRequest)(nil),                  // 50: iot.v1.GetDeviceRequest
+	(*GetDeviceResponse)(nil),                 // 51: iot.v1.GetDeviceResponse
+	(*DeleteDeviceRequest)(nil),               // 52: iot.v1.DeleteDeviceRequest
+	(*DeleteDeviceResponse)(nil),              // 53: iot.v1.DeleteDeviceResponse
+	(*GetOperationRequest)(nil),               // 54: iot.v1.GetOperationRequest
+	(*GetOperationResponse)(nil),              // 55: iot.v1.GetOperationResponse
+	(*UpdateDeviceRequest)(nil),               // 56: iot.v1.UpdateDeviceRequest
+	(*UpdateDeviceResponse)(nil),              // 57: iot.v1.UpdateDeviceResponse
+	(*ListDevicesRequest)(nil),                // 58: iot.v1.ListDevicesRequest
+	(*ListDevicesResponse)(nil),               // 59: iot.v1.ListDevicesResponse
+	(*GetFleetSnapshotRequest)(nil),           // 60: iot.v1.GetFleetSnapshotRequest
+	(*GetFleetSnapshotResponse)(nil),          // 61: iot.v1.GetFleetSnapshotResponse
+	(*CreateDeviceGroupRequest)(nil),          // 62: iot.v1.CreateDeviceGroupRequest
+	(*CreateDeviceGroupResponse)(nil),         // 63: iot.v1.CreateDeviceGroupResponse
+	(*GetDeviceGroupRequest)(nil),             // 64: iot.v1.GetDeviceGroupRequest
+	(*GetDeviceGroupResponse)(nil),            // 65: iot.v1.GetDeviceGroupResponse
+	(*ListDeviceGroupsRequest)(nil),           // 66: iot.v1.ListDeviceGroupsRequest
+	(*ListDeviceGroupsResponse)(nil),          // 67: iot.v1.ListDeviceGroupsResponse
+	(*ConfigureDeviceGroupRequest)(nil),       // 68: iot.v1.ConfigureDeviceGroupRequest
+	(*ConfigureDeviceGroupResponse)(nil),      // 69: iot.v1.ConfigureDeviceGroupResponse
+	(*ConfigureDefaultsRequest)(nil),          // 70: iot.v1.ConfigureDefaultsRequest
+	(*ConfigureDefaultsResponse)(nil),         // 71: iot.v1.ConfigureDefaultsResponse
+	(*GetEffectiveDeviceConfigRequest)(nil),   // 72: iot.v1.GetEffectiveDeviceConfigRequest
+	(*GetEffectiveDeviceConfigResponse)(nil),  // 73: iot.v1.GetEffectiveDeviceConfigResponse
+	(*ListDeviceConfigVersionsRequest)(nil),   // 74: iot.v1.ListDeviceConfigVersionsRequest
+	(*ListDeviceConfigVersionsResponse)(nil),  // 75: iot.v1.ListDeviceConfigVersionsResponse
+	(*RollbackDeviceConfigRequest)(nil),       // 76: iot.v1.RollbackDeviceConfigRequest
+	(*RollbackDeviceConfigResponse)(nil),      // 77: iot.v1.RollbackDeviceConfigResponse
+	(*DeviceConfigVersion)(nil),               // 78: iot.v1.DeviceConfigVersion
+	(*ConfigSettings)(nil),                    // 79: iot.v1.ConfigSettings
+	(*Timeframe)(nil),                         // 80: iot.v1.Timeframe
+	(*Metric)(nil),                            // 81: iot.v1.Metric
+	(*MetricValue)(nil),                       // 82: iot.v1.MetricValue
+	(*MetricAggregates)(nil),                  // 83: iot.v1.MetricAggregates
+	(*MetricBucket)(nil),                      // 84: iot.v1.MetricBucket
+	(*ReasonAlertCount)(nil),                  // 85: iot.v1.ReasonAlertCount
+	(*DeviceAlertCount)(nil),                  // 86: iot.v1.DeviceAlertCount
+	(*AlertCountBucket)(nil),                  // 87: iot.v1.AlertCountBucket
+	(*Alert)(nil),                             // 88: iot.v1.Alert
+	(*Device)(nil),                            // 89: iot.v1.Device
+	(*DeviceSnapshot)(nil),                    // 90: iot.v1.DeviceSnapshot
+	(*DeviceGroup)(nil),                       // 91: iot.v1.DeviceGroup
+	(*AlertExpression)(nil),                   // 92: iot.v1.AlertExpression
+	(*AlertRule)(nil),                         // 93: iot.v1.AlertRule
+	(*Operation)(nil),                         // 94: iot.v1.Operation
+	nil,                                       // 95: iot.v1.GetEffectiveDeviceConfigResponse.SourcesEntry
+	nil,                                       // 96: iot.v1.Device.LabelsEntry
+	(*timestamppb.Timestamp)(nil),             // 97: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),             // 98: google.protobuf.FieldMask
 }
 var file_iot_v1_service_proto_depIdxs = []int32{
-	9,  // 0: iot.v1.RecordMetricRequest.timestamp:type_name -> google.protobuf.Timestamp
-	7,  // 1: iot.v1.GetDeviceAlertsRequest.timeframe:type_name -> iot.v1.Timeframe
-	8,  // 2: iot.v1.GetDeviceAlertsResponse.alerts:type_name -> iot.v1.Alert
-	9,  // 3: iot.v1.Timeframe.start:type_name -> google.protobuf.Timestamp
-	9,  // 4: iot.v1.Timeframe.end:type_name -> google.protobuf.Timestamp
-	9,  // 5: iot.v1.Alert.timestamp:type_name -> google.protobuf.Timestamp
-	0,  // 6: iot.v1.Alert.reason:type_name -> iot.v1.Alert.Reason
-	1,  // 7: iot.v1.DeviceService.RecordMetric:input_type -> iot.v1.RecordMetricRequest
-	3,  // 8: iot.v1.DeviceService.ConfigureDevice:input_type -> iot.v1.ConfigureDeviceRequest
-	5,  // 9: iot.v1.DeviceService.GetDeviceAlerts:input_type -> iot.v1.GetDeviceAlertsRequest
-	2,  // 10: iot.v1.DeviceService.RecordMetric:output_type -> iot.v1.RecordMetricResponse
-	4,  // 11: iot.v1.DeviceService.ConfigureDevice:output_type -> iot.v1.ConfigureDeviceResponse
-	6,  // 12: iot.v1.DeviceService.GetDeviceAlerts:output_type -> iot.v1.GetDeviceAlertsResponse
-	10, // [10:13] is the sub-list for method output_type
-	7,  // [7:10] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	97,  // 0: iot.v1.RecordMetricRequest.timestamp:type_name -> google.protobuf.Timestamp
+	82,  // 1: iot.v1.RecordMetricRequest.values:type_name -> iot.v1.MetricValue
+	81,  // 2: iot.v1.RecordMetricsRequest.metrics:type_name -> iot.v1.Metric
+	81,  // 3: iot.v1.StreamMetricsRequest.metric:type_name -> iot.v1.Metric
+	97,  // 4: iot.v1.StreamMetricsResponse.last_timestamp:type_name -> google.protobuf.Timestamp
+	92,  // 5: iot.v1.ConfigureDeviceRequest.expressions:type_name -> iot.v1.AlertExpression
+	16,  // 6: iot.v1.ConfigureDeviceRequest.cooldowns:type_name -> iot.v1.AlertCooldown
+	4,   // 7: iot.v1.AlertCooldown.reason:type_name -> iot.v1.Alert.Reason
+	78,  // 8: iot.v1.GetDeviceConfigResponse.config:type_name -> iot.v1.DeviceConfigVersion
+	79,  // 9: iot.v1.UpdateDeviceConfigRequest.settings:type_name -> iot.v1.ConfigSettings
+	98,  // 10: iot.v1.UpdateDeviceConfigRequest.update_mask:type_name -> google.protobuf.FieldMask
+	78,  // 11: iot.v1.UpdateDeviceConfigResponse.config:type_name -> iot.v1.DeviceConfigVersion
+	80,  // 12: iot.v1.GetDeviceMetricsRequest.timeframe:type_name -> iot.v1.Timeframe
+	2,   // 13: iot.v1.GetDeviceMetricsRequest.order:type_name -> iot.v1.SortOrder
+	81,  // 14: iot.v1.GetDeviceMetricsResponse.metrics:type_name -> iot.v1.Metric
+	80,  // 15: iot.v1.GetDeviceMetricAggregatesRequest.timeframe:type_name -> iot.v1.Timeframe
+	1,   // 16: iot.v1.GetDeviceMetricAggregatesRequest.bucket_width:type_name -> iot.v1.BucketWidth
+	83,  // 17: iot.v1.GetDeviceMetricAggregatesResponse.metrics:type_name -> iot.v1.MetricAggregates
+	80,  // 18: iot.v1.GetDeviceAlertsRequest.timeframe:type_name -> iot.v1.Timeframe
+	5,   // 19: iot.v1.GetDeviceAlertsRequest.state:type_name -> iot.v1.Alert.State
+	4,   // 20: iot.v1.GetDeviceAlertsRequest.reasons:type_name -> iot.v1.Alert.Reason
+	2,   // 21: iot.v1.GetDeviceAlertsRequest.order:type_name -> iot.v1.SortOrder
+	88,  // 22: iot.v1.GetDeviceAlertsResponse.alerts:type_name -> iot.v1.Alert
+	4,   // 23: iot.v1.SearchAlertsRequest.reasons:type_name -> iot.v1.Alert.Reason
+	3,   // 24: iot.v1.SearchAlertsRequest.severity:type_name -> iot.v1.Severity
+	5,   // 25: iot.v1.SearchAlertsRequest.state:type_name -> iot.v1.Alert.State
+	80,  // 26: iot.v1.SearchAlertsRequest.timeframe:type_name -> iot.v1.Timeframe
+	88,  // 27: iot.v1.SearchAlertsResponse.alerts:type_name -> iot.v1.Alert
+	80,  // 28: iot.v1.GetAlertStatsRequest.timeframe:type_name -> iot.v1.Timeframe
+	1,   // 29: iot.v1.GetAlertStatsRequest.bucket_width:type_name -> iot.v1.BucketWidth
+	85,  // 30: iot.v1.GetAlertStatsResponse.by_reason:type_name -> iot.v1.ReasonAlertCount
+	86,  // 31: iot.v1.GetAlertStatsResponse.by_device:type_name -> iot.v1.DeviceAlertCount
+	87,  // 32: iot.v1.GetAlertStatsResponse.buckets:type_name -> iot.v1.AlertCountBucket
+	86,  // 33: iot.v1.GetAlertStatsResponse.top_devices:type_name -> iot.v1.DeviceAlertCount
+	4,   // 34: iot.v1.WatchDeviceAlertsRequest.reasons:type_name -> iot.v1.Alert.Reason
+	88,  // 35: iot.v1.WatchDeviceAlertsResponse.alert:type_name -> iot.v1.Alert
+	93,  // 36: iot.v1.CreateAlertRuleRequest.rule:type_name -> iot.v1.AlertRule
+	93,  // 37: iot.v1.CreateAlertRuleResponse.rule:type_name -> iot.v1.AlertRule
+	93,  // 38: iot.v1.GetAlertRuleResponse.rule:type_name -> iot.v1.AlertRule
+	93,  // 39: iot.v1.ListAlertRulesResponse.rules:type_name -> iot.v1.AlertRule
+	93,  // 40: iot.v1.UpdateAlertRuleRequest.rule:type_name -> iot.v1.AlertRule
+	93,  // 41: iot.v1.UpdateAlertRuleResponse.rule:type_name -> iot.v1.AlertRule
+	88,  // 42: iot.v1.AcknowledgeAlertResponse.alert:type_name -> iot.v1.Alert
+	88,  // 43: iot.v1.ResolveAlertResponse.alert:type_name -> iot.v1.Alert
+	89,  // 44: iot.v1.CreateDeviceRequest.device:type_name -> iot.v1.Device
+	89,  // 45: iot.v1.CreateDeviceResponse.device:type_name -> iot.v1.Device
+	89,  // 46: iot.v1.GetDeviceResponse.device:type_name -> iot.v1.Device
+	94,  // 47: iot.v1.DeleteDeviceResponse.operation:type_name -> iot.v1.Operation
+	94,  // 48: iot.v1.GetOperationResponse.operation:type_name -> iot.v1.Operation
+	89,  // 49: iot.v1.UpdateDeviceRequest.device:type_name -> iot.v1.Device
+	89,  // 50: iot.v1.UpdateDeviceResponse.device:type_name -> iot.v1.Device
+	89,  // 51: iot.v1.ListDevicesResponse.devices:type_name -> iot.v1.Device
+	90,  // 52: iot.v1.GetFleetSnapshotResponse.devices:type_name -> iot.v1.DeviceSnapshot
+	91,  // 53: iot.v1.CreateDeviceGroupResponse.group:type_name -> iot.v1.DeviceGroup
+	91,  // 54: iot.v1.GetDeviceGroupResponse.group:type_name -> iot.v1.DeviceGroup
+	91,  // 55: iot.v1.ListDeviceGroupsResponse.groups:type_name -> iot.v1.DeviceGroup
+	79,  // 56: iot.v1.ConfigureDeviceGroupRequest.settings:type_name -> iot.v1.ConfigSettings
+	79,  // 57: iot.v1.ConfigureDefaultsRequest.settings:type_name -> iot.v1.ConfigSettings
+	79,  // 58: iot.v1.GetEffectiveDeviceConfigResponse.settings:type_name -> iot.v1.ConfigSettings
+	95,  // 59: iot.v1.GetEffectiveDeviceConfigResponse.sources:type_name -> iot.v1.GetEffectiveDeviceConfigResponse.SourcesEntry
+	78,  // 60: iot.v1.ListDeviceConfigVersionsResponse.versions:type_name -> iot.v1.DeviceConfigVersion
+	78,  // 61: iot.v1.RollbackDeviceConfigResponse.version:type_name -> iot.v1.DeviceConfigVersion
+	79,  // 62: iot.v1.DeviceConfigVersion.settings:type_name -> iot.v1.ConfigSettings
+	97,  // 63: iot.v1.DeviceConfigVersion.created_at:type_name -> google.protobuf.Timestamp
+	92,  // 64: iot.v1.ConfigSettings.expressions:type_name -> iot.v1.AlertExpression
+	16,  // 65: iot.v1.ConfigSettings.cooldowns:type_name -> iot.v1.AlertCooldown
+	97,  // 66: iot.v1.Timeframe.start:type_name -> google.protobuf.Timestamp
+	97,  // 67: iot.v1.Timeframe.end:type_name -> google.protobuf.Timestamp
+	97,  // 68: iot.v1.Metric.timestamp:type_name -> google.protobuf.Timestamp
+	82,  // 69: iot.v1.Metric.values:type_name -> iot.v1.MetricValue
+	84,  // 70: iot.v1.MetricAggregates.buckets:type_name -> iot.v1.MetricBucket
+	97,  // 71: iot.v1.MetricBucket.start:type_name -> google.protobuf.Timestamp
+	4,   // 72: iot.v1.ReasonAlertCount.reason:type_name -> iot.v1.Alert.Reason
+	97,  // 73: iot.v1.AlertCountBucket.start:type_name -> google.protobuf.Timestamp
+	97,  // 74: iot.v1.Alert.timestamp:type_name -> google.protobuf.Timestamp
+	4,   // 75: iot.v1.Alert.reason:type_name -> iot.v1.Alert.Reason
+	3,   // 76: iot.v1.Alert.severity:type_name -> iot.v1.Severity
+	5,   // 77: iot.v1.Alert.state:type_name -> iot.v1.Alert.State
+	97,  // 78: iot.v1.Alert.acknowledged_at:type_name -> google.protobuf.Timestamp
+	97,  // 79: iot.v1.Alert.resolved_at:type_name -> google.protobuf.Timestamp
+	97,  // 80: iot.v1.Alert.last_seen:type_name -> google.protobuf.Timestamp
+	96,  // 81: iot.v1.Device.labels:type_name -> iot.v1.Device.LabelsEntry
+	97,  // 82: iot.v1.Device.created_at:type_name -> google.protobuf.Timestamp
+	97,  // 83: iot.v1.Device.last_seen:type_name -> google.protobuf.Timestamp
+	89,  // 84: iot.v1.DeviceSnapshot.device:type_name -> iot.v1.Device
+	81,  // 85: iot.v1.DeviceSnapshot.latest_metric:type_name -> iot.v1.Metric
+	97,  // 86: iot.v1.DeviceGroup.created_at:type_name -> google.protobuf.Timestamp
+	3,   // 87: iot.v1.AlertExpression.severity:type_name -> iot.v1.Severity
+	6,   // 88: iot.v1.AlertRule.operator:type_name -> iot.v1.AlertRule.Operator
+	3,   // 89: iot.v1.AlertRule.severity:type_name -> iot.v1.Severity
+	7,   // 90: iot.v1.Operation.type:type_name -> iot.v1.Operation.Type
+	8,   // 91: iot.v1.Operation.state:type_name -> iot.v1.Operation.State
+	97,  // 92: iot.v1.Operation.created_at:type_name -> google.protobuf.Timestamp
+	97,  // 93: iot.v1.Operation.updated_at:type_name -> google.protobuf.Timestamp
+	0,   // 94: iot.v1.GetEffectiveDeviceConfigResponse.SourcesEntry.value:type_name -> iot.v1.ConfigLevel
+	9,   // 95: iot.v1.DeviceService.RecordMetric:input_type -> iot.v1.RecordMetricRequest
+	11,  // 96: iot.v1.DeviceService.RecordMetrics:input_type -> iot.v1.RecordMetricsRequest
+	13,  // 97: iot.v1.DeviceService.StreamMetrics:input_type -> iot.v1.StreamMetricsRequest
+	15,  // 98: iot.v1.DeviceService.ConfigureDevice:input_type -> iot.v1.ConfigureDeviceRequest
+	18,  // 99: iot.v1.DeviceService.GetDeviceConfig:input_type -> iot.v1.GetDeviceConfigRequest
+	20,  // 100: iot.v1.DeviceService.UpdateDeviceConfig:input_type -> iot.v1.UpdateDeviceConfigRequest
+	22,  // 101: iot.v1.DeviceService.GetDeviceMetrics:input_type -> iot.v1.GetDeviceMetricsRequest
+	24,  // 102: iot.v1.DeviceService.GetDeviceMetricAggregates:input_type -> iot.v1.GetDeviceMetricAggregatesRequest
+	26,  // 103: iot.v1.DeviceService.GetDeviceAlerts:input_type -> iot.v1.GetDeviceAlertsRequest
+	28,  // 104: iot.v1.DeviceService.SearchAlerts:input_type -> iot.v1.SearchAlertsRequest
+	30,  // 105: iot.v1.DeviceService.GetAlertStats:input_type -> iot.v1.GetAlertStatsRequest
+	32,  // 106: iot.v1.DeviceService.WatchDeviceAlerts:input_type -> iot.v1.WatchDeviceAlertsRequest
+	34,  // 107: iot.v1.DeviceService.CreateAlertRule:input_type -> iot.v1.CreateAlertRuleRequest
+	36,  // 108: iot.v1.DeviceService.GetAlertRule:input_type -> iot.v1.GetAlertRuleRequest
+	38,  // 109: iot.v1.DeviceService.ListAlertRules:input_type -> iot.v1.ListAlertRulesRequest
+	40,  // 110: iot.v1.DeviceService.UpdateAlertRule:input_type -> iot.v1.UpdateAlertRuleRequest
+	42,  // 111: iot.v1.DeviceService.DeleteAlertRule:input_type -> iot.v1.DeleteAlertRuleRequest
+	44,  // 112: iot.v1.DeviceService.AcknowledgeAlert:input_type -> iot.v1.AcknowledgeAlertRequest
+	46,  // 113: iot.v1.DeviceService.ResolveAlert:input_type -> iot.v1.ResolveAlertRequest
+	48,  // 114: iot.v1.DeviceService.CreateDevice:input_type -> iot.v1.CreateDeviceRequest
+	50,  // 115: iot.v1.DeviceService.GetDevice:input_type -> iot.v1.GetDeviceRequest
+	56,  // 116: iot.v1.DeviceService.UpdateDevice:input_type -> iot.v1.UpdateDeviceRequest
+	58,  // 117: iot.v1.DeviceService.ListDevices:input_type -> iot.v1.ListDevicesRequest
+	60,  // 118: iot.v1.DeviceService.GetFleetSnapshot:input_type -> iot.v1.GetFleetSnapshotRequest
+	52,  // 119: iot.v1.DeviceService.DeleteDevice:input_type -> iot.v1.DeleteDeviceRequest
+	54,  // 120: iot.v1.DeviceService.GetOperation:input_type -> iot.v1.GetOperationRequest
+	62,  // 121: iot.v1.DeviceService.CreateDeviceGroup:input_type -> iot.v1.CreateDeviceGroupRequest
+	64,  // 122: iot.v1.DeviceService.GetDeviceGroup:input_type -> iot.v1.GetDeviceGroupRequest
+	66,  // 123: iot.v1.DeviceService.ListDeviceGroups:input_type -> iot.v1.ListDeviceGroupsRequest
+	68,  // 124: iot.v1.DeviceService.ConfigureDeviceGroup:input_type -> iot.v1.ConfigureDeviceGroupRequest
+	70,  // 125: iot.v1.DeviceService.ConfigureDefaults:input_type -> iot.v1.ConfigureDefaultsRequest
+	72,  // 126: iot.v1.DeviceService.GetEffectiveDeviceConfig:input_type -> iot.v1.GetEffectiveDeviceConfigRequest
+	74,  // 127: iot.v1.DeviceService.ListDeviceConfigVersions:input_type -> iot.v1.ListDeviceConfigVersionsRequest
+	76,  // 128: iot.v1.DeviceService.RollbackDeviceConfig:input_type -> iot.v1.RollbackDeviceConfigRequest
+	10,  // 129: iot.v1.DeviceService.RecordMetric:output_type -> iot.v1.RecordMetricResponse
+	12,  // 130: iot.v1.DeviceService.RecordMetrics:output_type -> iot.v1.RecordMetricsResponse
+	14,  // 131: iot.v1.DeviceService.StreamMetrics:output_type -> iot.v1.StreamMetricsResponse
+	17,  // 132: iot.v1.DeviceService.ConfigureDevice:output_type -> iot.v1.ConfigureDeviceResponse
+	19,  // 133: iot.v1.DeviceService.GetDeviceConfig:output_type -> iot.v1.GetDeviceConfigResponse
+	21,  // 134: iot.v1.DeviceService.UpdateDeviceConfig:output_type -> iot.v1.UpdateDeviceConfigResponse
+	23,  // 135: iot.v1.DeviceService.GetDeviceMetrics:output_type -> iot.v1.GetDeviceMetricsResponse
+	25,  // 136: iot.v1.DeviceService.GetDeviceMetricAggregates:output_type -> iot.v1.GetDeviceMetricAggregatesResponse
+	27,  // 137: iot.v1.DeviceService.GetDeviceAlerts:output_type -> iot.v1.GetDeviceAlertsResponse
+	29,  // 138: iot.v1.DeviceService.SearchAlerts:output_type -> iot.v1.SearchAlertsResponse
+	31,  // 139: iot.v1.DeviceService.GetAlertStats:output_type -> iot.v1.GetAlertStatsResponse
+	33,  // 140: iot.v1.DeviceService.WatchDeviceAlerts:output_type -> iot.v1.WatchDeviceAlertsResponse
+	35,  // 141: iot.v1.DeviceService.CreateAlertRule:output_type -> iot.v1.CreateAlertRuleResponse
+	37,  // 142: iot.v1.DeviceService.GetAlertRule:output_type -> iot.v1.GetAlertRuleResponse
+	39,  // 143: iot.v1.DeviceService.ListAlertRules:output_type -> iot.v1.ListAlertRulesResponse
+	41,  // 144: iot.v1.DeviceService.UpdateAlertRule:output_type -> iot.v1.UpdateAlertRuleResponse
+	43,  // 145: iot.v1.DeviceService.DeleteAlertRule:output_type -> iot.v1.DeleteAlertRuleResponse
+	45,  // 146: iot.v1.DeviceService.AcknowledgeAlert:output_type -> iot.v1.AcknowledgeAlertResponse
+	47,  // 147: iot.v1.DeviceService.ResolveAlert:output_type -> iot.v1.ResolveAlertResponse
+	49,  // 148: iot.v1.DeviceService.CreateDevice:output_type -> iot.v1.CreateDeviceResponse
+	51,  // 149: iot.v1.DeviceService.GetDevice:output_type -> iot.v1.GetDeviceResponse
+	57,  // 150: iot.v1.DeviceService.UpdateDevice:output_type -> iot.v1.UpdateDeviceResponse
+	59,  // 151: iot.v1.DeviceService.ListDevices:output_type -> iot.v1.ListDevicesResponse
+	61,  // 152: iot.v1.DeviceService.GetFleetSnapshot:output_type -> iot.v1.GetFleetSnapshotResponse
+	53,  // 153: iot.v1.DeviceService.DeleteDevice:output_type -> iot.v1.DeleteDeviceResponse
+	55,  // 154: iot.v1.DeviceService.GetOperation:output_type -> iot.v1.GetOperationResponse
+	63,  // 155: iot.v1.DeviceService.CreateDeviceGroup:output_type -> iot.v1.CreateDeviceGroupResponse
+	65,  // 156: iot.v1.DeviceService.GetDeviceGroup:output_type -> iot.v1.GetDeviceGroupResponse
+	67,  // 157: iot.v1.DeviceService.ListDeviceGroups:output_type -> iot.v1.ListDeviceGroupsResponse
+	69,  // 158: iot.v1.DeviceService.ConfigureDeviceGroup:output_type -> iot.v1.ConfigureDeviceGroupResponse
+	71,  // 159: iot.v1.DeviceService.ConfigureDefaults:output_type -> iot.v1.ConfigureDefaultsResponse
+	73,  // 160: iot.v1.DeviceService.GetEffectiveDeviceConfig:output_type -> iot.v1.GetEffectiveDeviceConfigResponse
+	75,  // 161: iot.v1.DeviceService.ListDeviceConfigVersions:output_type -> iot.v1.ListDeviceConfigVersionsResponse
+	77,  // 162: iot.v1.DeviceService.RollbackDeviceConfig:output_type -> iot.v1.RollbackDeviceConfigResponse
+	129, // [129:163] is the sub-list for method output_type
+	95,  // [95:129] is the sub-list for method input_type
+	95,  // [95:95] is the sub-list for extension type_name
+	95,  // [95:95] is the sub-list for extension extendee
+	0,   // [0:95] is the sub-list for field type_name
 }
 
 func init() { file_iot_v1_service_proto_init() }
@@ -602,15 +6382,24 @@ func file_iot_v1_service_proto_init() {
 	if File_iot_v1_service_proto != nil {
 		return
 	}
-	file_iot_v1_service_proto_msgTypes[4].OneofWrappers = []any{}
 	file_iot_v1_service_proto_msgTypes[6].OneofWrappers = []any{}
+	file_iot_v1_service_proto_msgTypes[11].OneofWrappers = []any{}
+	file_iot_v1_service_proto_msgTypes[13].OneofWrappers = []any{}
+	file_iot_v1_service_proto_msgTypes[17].OneofWrappers = []any{}
+	file_iot_v1_service_proto_msgTypes[19].OneofWrappers = []any{}
+	file_iot_v1_service_proto_msgTypes[67].OneofWrappers = []any{}
+	file_iot_v1_service_proto_msgTypes[70].OneofWrappers = []any{}
+	file_iot_v1_service_proto_msgTypes[71].OneofWrappers = []any{}
+	file_iot_v1_service_proto_msgTypes[75].OneofWrappers = []any{}
+	file_iot_v1_service_proto_msgTypes[79].OneofWrappers = []any{}
+	file_iot_v1_service_proto_msgTypes[80].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_iot_v1_service_proto_rawDesc), len(file_iot_v1_service_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   8,
+			NumEnums:      9,
+			NumMessages:   88,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	msqlite "modernc.org/sqlite"
+
+	"github.com/joshjon/iot-metrics/device"
+)
+
+func init() {
+	// Queries filter devices by label selector with labels_match, so that the
+	// semantics of label selectors are defined in one place.
+	msqlite.MustRegisterDeterministicScalarFunction("labels_match", 2, labelsMatch)
+}
+
+// labelRequirementJSON is the representation of a device.LabelRequirement
+// that labels_match matches device labels against.
+type labelRequirementJSON struct {
+	Key   string `json:"key"`
+	Op    string `json:"op"`
+	Value string `json:"value"`
+}
+
+func marshalLabelSelector(selector device.LabelSelector) (string, error) {
+	requirements := make([]labelRequirementJSON, len(selector))
+	for i, req := range selector {
+		requirements[i] = labelRequirementJSON{
+			Key:   req.Key,
+			Op:    string(req.Operator),
+			Value: req.Value,
+		}
+	}
+	b, err := json.Marshal(requirements)
+	if err != nil {
+		return "", fmt.Errorf("marshal label selector: %w", err)
+	}
+	return string(b), nil
+}
+
+// labelsMatch implements the SQL function labels_match(labels, selector),
+// which returns 1 if the JSON object of device labels meets all requirements
+// of the JSON array label selector, otherwise 0.
+func labelsMatch(_ *msqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	var labels map[string]string
+	if err := json.Unmarshal(textArg(args[0]), &labels); err != nil {
+		return nil, fmt.Errorf("labels_match: unmarshal labels: %w", err)
+	}
+	var requirements []labelRequirementJSON
+	if err := json.Unmarshal(textArg(args[1]), &requirements); err != nil {
+		return nil, fmt.Errorf("labels_match: unmarshal selector: %w", err)
+	}
+
+	for _, req := range requirements {
+		value, ok := labels[req.Key]
+		var met bool
+		switch device.LabelOperator(req.Op) {
+		case device.LabelOperatorExists:
+			met = ok
+		case device.LabelOperatorNotExists:
+			met = !ok
+		case device.LabelOperatorEquals:
+			met = ok && value == req.Value
+		case device.LabelOperatorNotEquals:
+			met = !ok || value != req.Value
+		default:
+			return nil, fmt.Errorf("labels_match: unknown operator %q", req.Op)
+		}
+		if !met {
+			return int64(0), nil
+		}
+	}
+	return int64(1), nil
+}
+
+func textArg(v driver.Value) []byte {
+	switch v := v.(type) {
+	case string:
+		return []byte(v)
+	case []byte:
+		return v
+	default:
+		return nil
+	}
+}
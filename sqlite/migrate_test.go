@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshjon/iot-metrics/device"
+	"github.com/joshjon/iot-metrics/sqlite/migrations"
+)
+
+func TestMigrate_metricValues(t *testing.T) {
+	ctx := t.Context()
+	db, err := Open(ctx, WithDir(t.TempDir()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, db.Close())
+	})
+
+	initial, err := migrations.FS().ReadFile("0001_tables.up.sql")
+	require.NoError(t, err)
+	err = Migrate(db, fstest.MapFS{"0001_tables.up.sql": {Data: initial}})
+	require.NoError(t, err)
+
+	ts := time.Now().UTC().Truncate(time.Second)
+	_, err = db.ExecContext(ctx,
+		"INSERT INTO metrics (device_id, temperature, battery, timestamp) VALUES (?, ?, ?, ?)",
+		"foo", 25.5, 80, ts.Unix(),
+	)
+	require.NoError(t, err)
+
+	err = Migrate(db, migrations.FS())
+	require.NoError(t, err)
+
+	repo := NewDeviceRepository(db)
+	page, err := repo.GetDeviceMetrics(ctx, "foo", device.Timeframe{}, device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	require.Equal(t, []device.Metric{{
+		Values: []device.MetricValue{
+			{Name: device.MetricTemperature, Value: 25.5},
+			{Name: device.MetricBattery, Value: 80},
+		},
+		Time: ts,
+	}}, page.Items)
+}
@@ -0,0 +1,22 @@
+CREATE TABLE metric_values
+(
+    id        INTEGER PRIMARY KEY AUTOINCREMENT,
+    metric_id INTEGER NOT NULL REFERENCES metrics (id) ON DELETE CASCADE,
+    name      TEXT    NOT NULL,
+    value     REAL    NOT NULL,
+    unit      TEXT    NOT NULL DEFAULT '',
+    UNIQUE (metric_id, name)
+);
+
+INSERT INTO metric_values (metric_id, name, value)
+SELECT id, 'temperature', temperature
+FROM metrics;
+
+INSERT INTO metric_values (metric_id, name, value)
+SELECT id, 'battery', battery
+FROM metrics;
+
+ALTER TABLE metrics DROP COLUMN temperature;
+ALTER TABLE metrics DROP COLUMN battery;
+
+CREATE INDEX metrics_device_id_timestamp_idx ON metrics (device_id, timestamp, id);
@@ -0,0 +1,16 @@
+CREATE TABLE alert_rules
+(
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    device_id      TEXT    NOT NULL,
+    metric         TEXT    NOT NULL,
+    operator       TEXT    NOT NULL,
+    threshold      REAL    NOT NULL,
+    threshold_high REAL    NOT NULL DEFAULT 0,
+    severity       TEXT    NOT NULL
+);
+
+CREATE INDEX alert_rules_device_id_idx ON alert_rules (device_id);
+
+-- rule_id is null for alerts triggered by the device config thresholds
+ALTER TABLE alerts ADD COLUMN rule_id INTEGER;
+ALTER TABLE alerts ADD COLUMN severity TEXT NOT NULL DEFAULT 'WARNING';
@@ -0,0 +1,2 @@
+-- JSON array of CEL alert expressions
+ALTER TABLE configs ADD COLUMN expressions TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,12 @@
+ALTER TABLE configs ADD COLUMN consecutive_breaches INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE configs ADD COLUMN hysteresis REAL NOT NULL DEFAULT 0;
+
+-- debouncing state of each condition (alert rule or expression) of a device
+CREATE TABLE alert_states
+(
+    device_id TEXT    NOT NULL,
+    condition TEXT    NOT NULL,
+    breaches  INTEGER NOT NULL,
+    active    BOOLEAN NOT NULL,
+    PRIMARY KEY (device_id, condition)
+);
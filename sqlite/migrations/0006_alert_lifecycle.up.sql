@@ -0,0 +1,18 @@
+ALTER TABLE alerts ADD COLUMN state TEXT NOT NULL DEFAULT 'OPEN';
+-- alert rule or expression that triggered the alert, e.g. rule:1
+ALTER TABLE alerts ADD COLUMN condition TEXT NOT NULL DEFAULT '';
+ALTER TABLE alerts ADD COLUMN acknowledged_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE alerts ADD COLUMN ack_comment TEXT NOT NULL DEFAULT '';
+ALTER TABLE alerts ADD COLUMN acknowledged_at INTEGER; -- unix
+ALTER TABLE alerts ADD COLUMN resolved_at INTEGER; -- unix
+
+-- backfill conditions of existing alerts so that they resolve once cleared
+UPDATE alerts
+SET condition = CASE
+                    WHEN rule_id IS NOT NULL THEN 'rule:' || rule_id
+                    WHEN reason = 'TEMPERATURE_HIGH' THEN 'config:temperature'
+                    WHEN reason = 'BATTERY_LOW' THEN 'config:battery'
+                    ELSE ''
+    END;
+
+CREATE INDEX alerts_unresolved_condition_idx ON alerts (device_id, condition) WHERE state != 'RESOLVED';
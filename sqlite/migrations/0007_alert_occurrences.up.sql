@@ -0,0 +1,10 @@
+ALTER TABLE alerts ADD COLUMN occurrences INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE alerts ADD COLUMN last_seen INTEGER NOT NULL DEFAULT 0; -- unix
+
+UPDATE alerts
+SET last_seen = timestamp;
+
+-- JSON array of cooldown windows by alert reason
+ALTER TABLE configs ADD COLUMN cooldowns TEXT NOT NULL DEFAULT '[]';
+
+CREATE INDEX alerts_device_id_condition_idx ON alerts (device_id, condition, id);
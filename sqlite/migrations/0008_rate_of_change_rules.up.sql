@@ -0,0 +1,2 @@
+-- window_seconds is only used by rate of change rules
+ALTER TABLE alert_rules ADD COLUMN window_seconds INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- 0 disables offline detection for the device
+ALTER TABLE configs ADD COLUMN reporting_interval_seconds INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,25 @@
+CREATE TABLE devices
+(
+    seq              INTEGER PRIMARY KEY AUTOINCREMENT, -- pagination tiebreaker
+    id               TEXT    NOT NULL UNIQUE,
+    display_name     TEXT    NOT NULL DEFAULT '',
+    model            TEXT    NOT NULL DEFAULT '',
+    firmware_version TEXT    NOT NULL DEFAULT '',
+    location         TEXT    NOT NULL DEFAULT '',
+    labels           TEXT    NOT NULL DEFAULT '{}', -- JSON object of label keys to values
+    created_at       INTEGER NOT NULL,              -- unix
+    last_seen        INTEGER                        -- unix, null until a metric is recorded
+);
+
+CREATE INDEX devices_created_at_idx ON devices (created_at, seq);
+
+-- register devices that already exist implicitly through their config or metrics
+INSERT INTO devices (id, created_at, last_seen)
+SELECT device_id, COALESCE(MIN(first_ts), CAST(strftime('%s', 'now') AS INTEGER)), MAX(last_ts)
+FROM (SELECT device_id, MIN(timestamp) AS first_ts, MAX(timestamp) AS last_ts
+      FROM metrics
+      GROUP BY device_id
+      UNION ALL
+      SELECT device_id, NULL, NULL
+      FROM configs)
+GROUP BY device_id;
@@ -0,0 +1,41 @@
+CREATE TABLE device_groups
+(
+    id           TEXT PRIMARY KEY,
+    display_name TEXT    NOT NULL DEFAULT '',
+    created_at   INTEGER NOT NULL -- unix
+);
+
+ALTER TABLE devices ADD COLUMN group_id TEXT REFERENCES device_groups (id);
+
+CREATE INDEX devices_group_id_idx ON devices (group_id);
+
+-- config settings set at each level, where NULL settings are inherited from
+-- the next level: the device, then its group, then the global defaults
+CREATE TABLE config_settings
+(
+    level                      TEXT NOT NULL, -- DEVICE, GROUP or GLOBAL
+    scope_id                   TEXT NOT NULL, -- device or group ID, empty for GLOBAL
+    temperature_threshold      REAL,
+    battery_threshold          INTEGER,
+    expressions                TEXT,          -- JSON array of CEL alert expressions
+    consecutive_breaches       INTEGER,
+    hysteresis                 REAL,
+    cooldowns                  TEXT,          -- JSON array of cooldown windows by alert reason
+    reporting_interval_seconds INTEGER,
+    PRIMARY KEY (level, scope_id)
+);
+
+INSERT INTO config_settings (level, scope_id, temperature_threshold, battery_threshold, expressions,
+                             consecutive_breaches, hysteresis, cooldowns, reporting_interval_seconds)
+SELECT 'DEVICE',
+       device_id,
+       temperature_threshold,
+       battery_threshold,
+       expressions,
+       consecutive_breaches,
+       hysteresis,
+       cooldowns,
+       reporting_interval_seconds
+FROM configs;
+
+DROP TABLE configs;
@@ -0,0 +1,45 @@
+-- append-only history of the settings set for each device, where the current
+-- version is copied to config_settings
+CREATE TABLE device_config_versions
+(
+    device_id                  TEXT    NOT NULL,
+    version                    INTEGER NOT NULL,
+    temperature_threshold      REAL,
+    battery_threshold          INTEGER,
+    expressions                TEXT, -- JSON array of CEL alert expressions
+    consecutive_breaches       INTEGER,
+    hysteresis                 REAL,
+    cooldowns                  TEXT, -- JSON array of cooldown windows by alert reason
+    reporting_interval_seconds INTEGER,
+    created_at                 INTEGER NOT NULL, -- unix
+    author                     TEXT    NOT NULL DEFAULT '',
+    reason                     TEXT    NOT NULL DEFAULT '',
+    PRIMARY KEY (device_id, version)
+);
+
+-- version of the current device settings, 0 for group and global settings
+ALTER TABLE config_settings ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+
+-- the existing device settings become the first version of each device
+UPDATE config_settings
+SET version = 1
+WHERE level = 'DEVICE';
+
+INSERT INTO device_config_versions (device_id, version, temperature_threshold, battery_threshold, expressions,
+                                    consecutive_breaches, hysteresis, cooldowns, reporting_interval_seconds,
+                                    created_at)
+SELECT scope_id,
+       version,
+       temperature_threshold,
+       battery_threshold,
+       expressions,
+       consecutive_breaches,
+       hysteresis,
+       cooldowns,
+       reporting_interval_seconds,
+       CAST(strftime('%s', 'now') AS INTEGER)
+FROM config_settings
+WHERE level = 'DEVICE';
+
+-- version of the device config an alert was evaluated against, 0 if none
+ALTER TABLE alerts ADD COLUMN config_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,17 @@
+-- long-running operations, such as purging the data of a deleted device
+CREATE TABLE operations
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    type            TEXT    NOT NULL,
+    device_id       TEXT    NOT NULL,
+    state           TEXT    NOT NULL,
+    deleted_metrics INTEGER NOT NULL DEFAULT 0,
+    deleted_alerts  INTEGER NOT NULL DEFAULT 0,
+    max_metric_id   INTEGER NOT NULL DEFAULT 0,
+    max_alert_id    INTEGER NOT NULL DEFAULT 0,
+    error           TEXT    NOT NULL DEFAULT '',
+    created_at      INTEGER NOT NULL, -- unix
+    updated_at      INTEGER NOT NULL  -- unix
+);
+
+CREATE INDEX operations_state_idx ON operations (state);
@@ -0,0 +1,17 @@
+-- latest metric recorded by each device, maintained as metrics are saved so
+-- that the fleet snapshot does not scan the metrics table
+CREATE TABLE device_latest
+(
+    device_id TEXT PRIMARY KEY,
+    metric_id INTEGER NOT NULL,
+    timestamp INTEGER NOT NULL -- unix
+);
+
+INSERT INTO device_latest (device_id, metric_id, timestamp)
+SELECT device_id, id, timestamp
+FROM (SELECT device_id,
+             id,
+             timestamp,
+             ROW_NUMBER() OVER (PARTITION BY device_id ORDER BY timestamp DESC, id DESC) AS rn
+      FROM metrics) AS ranked
+WHERE rn = 1;
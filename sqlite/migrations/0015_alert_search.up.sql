@@ -0,0 +1,4 @@
+-- alerts are paginated by timestamp and id, both for a device and across all
+-- devices when searching
+CREATE INDEX alerts_device_id_timestamp_idx ON alerts (device_id, timestamp, id);
+CREATE INDEX alerts_timestamp_idx ON alerts (timestamp, id);
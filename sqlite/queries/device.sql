@@ -1,7 +1,27 @@
--- name: SaveDeviceMetric :exec
-INSERT INTO metrics (device_id, temperature, battery, timestamp)
+-- name: SaveDeviceMetric :one
+INSERT INTO metrics (device_id, timestamp)
+VALUES (?, ?)
+RETURNING id;
+
+-- name: SaveMetricValue :exec
+INSERT INTO metric_values (metric_id, name, value, unit)
 VALUES (?, ?, ?, ?);
 
+-- name: UpsertDeviceLatest :exec
+-- tracks the latest metric of a device, keeping the later metric when metrics
+-- are recorded out of order
+INSERT INTO device_latest (device_id, metric_id, timestamp)
+VALUES (?, ?, ?)
+ON CONFLICT(device_id) DO UPDATE SET metric_id = excluded.metric_id,
+                                     timestamp = excluded.timestamp
+WHERE excluded.timestamp >= device_latest.timestamp;
+
+-- name: GetMetricValues :many
+SELECT *
+FROM metric_values
+WHERE metric_id IN (sqlc.slice('metric_ids'))
+ORDER BY metric_id, id;
+
 -- name: GetDeviceMetrics :many
 SELECT *
 FROM metrics
@@ -26,29 +46,170 @@ WHERE device_id = :device_id
 ORDER BY timestamp DESC, id DESC
 LIMIT :limit;
 
--- name: UpsertDeviceConfig :exec
-INSERT INTO configs (device_id, temperature_threshold, battery_threshold)
-VALUES (?, ?, ?)
-ON CONFLICT(device_id) DO UPDATE
+-- name: GetDeviceMetricsAsc :many
+-- pages metrics oldest first, see GetDeviceMetrics
+SELECT *
+FROM metrics
+WHERE device_id = :device_id
+  -- time window
+  AND (CAST(sqlc.narg('start_ts') AS INTEGER) IS NULL OR timestamp >= sqlc.narg('start_ts'))
+  AND (CAST(sqlc.narg('end_ts') AS INTEGER) IS NULL OR timestamp <= sqlc.narg('end_ts'))
+  -- composite cursor
+  AND (
+    CAST(sqlc.narg('last_ts') AS INTEGER) IS NULL
+        OR (
+        -- timestamp greater than previous page last row
+        timestamp > sqlc.narg('last_ts')
+            OR (
+            -- or timestamp equal to previous page last row
+            timestamp = sqlc.narg('last_ts')
+                -- but is greater than last row id
+                AND (CAST(sqlc.narg('last_id') AS INTEGER) IS NULL OR id > sqlc.narg('last_id'))
+            )
+        )
+    )
+ORDER BY timestamp, id
+LIMIT :limit;
+
+-- name: GetDeviceMetricAggregates :many
+-- aggregates the values of each metric in buckets aligned to the unix epoch
+SELECT name,
+       bucket_start,
+       CAST(MIN(value) AS REAL)       AS min_value,
+       CAST(MAX(value) AS REAL)       AS max_value,
+       CAST(AVG(value) AS REAL)       AS avg_value,
+       COUNT(*)                       AS count,
+       CAST(MIN(earliest) AS REAL)    AS first_reading,
+       CAST(MIN(latest) AS REAL)      AS last_reading
+FROM (SELECT name,
+             bucket_start,
+             value,
+             -- values of the earliest and latest metric in the bucket
+             FIRST_VALUE(value) OVER (PARTITION BY name, bucket_start ORDER BY timestamp, id
+                 ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) AS earliest,
+             LAST_VALUE(value) OVER (PARTITION BY name, bucket_start ORDER BY timestamp, id
+                 ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING)  AS latest
+      FROM (SELECT mv.name,
+                   CAST(m.timestamp - m.timestamp % sqlc.arg('bucket_seconds') AS INTEGER) AS bucket_start,
+                   m.timestamp,
+                   m.id,
+                   mv.value
+            FROM metrics m
+                     JOIN metric_values mv ON mv.metric_id = m.id
+            WHERE m.device_id = sqlc.arg('device_id')
+              AND m.timestamp >= sqlc.arg('start_ts')
+              AND m.timestamp <= sqlc.arg('end_ts')) AS bucketed) AS windowed
+GROUP BY name, bucket_start
+ORDER BY name, bucket_start;
+
+-- name: GetMetricRange :one
+-- the lowest and highest values of a metric recorded in [start_ts, end_ts)
+SELECT COUNT(*)                                 AS count,
+       CAST(COALESCE(MIN(mv.value), 0) AS REAL) AS low,
+       CAST(COALESCE(MAX(mv.value), 0) AS REAL) AS high
+FROM metrics m
+         JOIN metric_values mv ON mv.metric_id = m.id
+WHERE m.device_id = sqlc.arg('device_id')
+  AND m.timestamp >= sqlc.arg('start_ts')
+  AND m.timestamp < sqlc.arg('end_ts')
+  AND mv.name = sqlc.arg('name');
+
+-- name: UpsertConfigSettings :exec
+INSERT INTO config_settings (level, scope_id, temperature_threshold, battery_threshold, expressions,
+                             consecutive_breaches, hysteresis, cooldowns, reporting_interval_seconds, version)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(level, scope_id) DO UPDATE
     SET temperature_threshold=excluded.temperature_threshold,
-        battery_threshold=excluded.battery_threshold;
+        battery_threshold=excluded.battery_threshold,
+        expressions=excluded.expressions,
+        consecutive_breaches=excluded.consecutive_breaches,
+        hysteresis=excluded.hysteresis,
+        cooldowns=excluded.cooldowns,
+        reporting_interval_seconds=excluded.reporting_interval_seconds,
+        version=excluded.version;
 
--- name: GetDeviceConfig :one
-SELECT temperature_threshold, battery_threshold
-FROM configs
-WHERE device_id = ?;
+-- name: SaveDeviceConfigVersion :one
+-- versions are numbered from 1 per device, and no version is saved if
+-- expected_version is set and is not the current version (0 if none)
+INSERT INTO device_config_versions (device_id, version, temperature_threshold, battery_threshold, expressions,
+                                    consecutive_breaches, hysteresis, cooldowns, reporting_interval_seconds,
+                                    created_at, author, reason)
+SELECT sqlc.arg('device_id'),
+       cur.version + 1,
+       sqlc.narg('temperature_threshold'), sqlc.narg('battery_threshold'), sqlc.narg('expressions'),
+       sqlc.narg('consecutive_breaches'), sqlc.narg('hysteresis'), sqlc.narg('cooldowns'),
+       sqlc.narg('reporting_interval_seconds'), sqlc.arg('created_at'), sqlc.arg('author'), sqlc.arg('reason')
+FROM (SELECT COALESCE(MAX(v.version), 0) AS version
+      FROM device_config_versions v
+      WHERE v.device_id = sqlc.arg('device_id')) cur
+WHERE CAST(sqlc.narg('expected_version') AS INTEGER) IS NULL
+   OR cur.version = sqlc.narg('expected_version')
+RETURNING version;
 
--- name: SaveDeviceAlert :exec
-INSERT INTO alerts (device_id, reason, desc, timestamp)
-VALUES (?, ?, ?, ?);
+-- name: GetDeviceConfigVersion :one
+SELECT *
+FROM device_config_versions
+WHERE device_id = ?
+  AND version = ?;
+
+-- name: GetLatestDeviceConfigVersion :one
+SELECT *
+FROM device_config_versions
+WHERE device_id = ?
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: ListDeviceConfigVersions :many
+SELECT *
+FROM device_config_versions
+WHERE device_id = :device_id
+  AND (CAST(sqlc.narg('last_version') AS INTEGER) IS NULL OR version < sqlc.narg('last_version'))
+ORDER BY version DESC
+LIMIT :limit;
+
+-- name: GetDeviceConfigSettings :many
+-- settings of each level that applies to the device
+SELECT cs.*
+FROM config_settings cs
+WHERE (cs.level = 'DEVICE' AND cs.scope_id = sqlc.arg('device_id'))
+   OR (cs.level = 'GROUP' AND cs.scope_id = (SELECT d.group_id FROM devices d WHERE d.id = sqlc.arg('device_id')))
+   OR (cs.level = 'GLOBAL' AND cs.scope_id = '');
+
+-- name: ListReportingDevices :many
+-- reporting_interval_seconds is resolved from the device, then its group, then
+-- the global defaults, and last_reported is 0 for devices without metrics
+SELECT d.id                                                  AS device_id,
+       CAST(COALESCE(dc.reporting_interval_seconds, gc.reporting_interval_seconds,
+                     g.reporting_interval_seconds, 0) AS INTEGER) AS reporting_interval_seconds,
+       CAST(COALESCE(d.last_seen, 0) AS INTEGER)             AS last_reported
+FROM devices d
+         LEFT JOIN config_settings dc ON dc.level = 'DEVICE' AND dc.scope_id = d.id
+         LEFT JOIN config_settings gc ON gc.level = 'GROUP' AND gc.scope_id = d.group_id
+         LEFT JOIN config_settings g ON g.level = 'GLOBAL' AND g.scope_id = ''
+WHERE COALESCE(dc.reporting_interval_seconds, gc.reporting_interval_seconds, g.reporting_interval_seconds, 0) > 0
+ORDER BY d.id;
+
+-- name: SaveDeviceAlert :one
+INSERT INTO alerts (device_id, rule_id, reason, severity, desc, timestamp, state, condition, occurrences, last_seen,
+                    config_version)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id;
 
 -- name: GetDeviceAlerts :many
+-- reasons is a JSON array of alert reasons, or empty to not filter by reason
+WITH filter AS (SELECT CAST(sqlc.arg('reasons') AS TEXT) AS reasons)
 SELECT *
 FROM alerts
 WHERE device_id = :device_id
   -- time window
   AND (CAST(sqlc.narg('start_ts') AS INTEGER) IS NULL OR timestamp >= sqlc.narg('start_ts'))
   AND (CAST(sqlc.narg('end_ts') AS INTEGER) IS NULL OR timestamp <= sqlc.narg('end_ts'))
+  AND (CAST(sqlc.narg('state') AS TEXT) IS NULL OR state = sqlc.narg('state'))
+  AND (CAST(sqlc.narg('severity') AS TEXT) IS NULL OR severity = sqlc.narg('severity'))
+  AND (
+    (SELECT json_array_length(f.reasons) FROM filter f) = 0
+        OR reason IN (SELECT r.value FROM filter f, json_each(f.reasons) r)
+    )
   -- composite cursor
   AND (
     CAST(sqlc.narg('last_ts') AS INTEGER) IS NULL
@@ -64,4 +225,428 @@ WHERE device_id = :device_id
         )
     )
 ORDER BY timestamp DESC, id DESC
-LIMIT :limit;
\ No newline at end of file
+LIMIT :limit;
+
+-- name: GetDeviceAlertsAsc :many
+-- pages alerts oldest first, see GetDeviceAlerts
+-- reasons is a JSON array of alert reasons, or empty to not filter by reason
+WITH filter AS (SELECT CAST(sqlc.arg('reasons') AS TEXT) AS reasons)
+SELECT *
+FROM alerts
+WHERE device_id = :device_id
+  -- time window
+  AND (CAST(sqlc.narg('start_ts') AS INTEGER) IS NULL OR timestamp >= sqlc.narg('start_ts'))
+  AND (CAST(sqlc.narg('end_ts') AS INTEGER) IS NULL OR timestamp <= sqlc.narg('end_ts'))
+  AND (CAST(sqlc.narg('state') AS TEXT) IS NULL OR state = sqlc.narg('state'))
+  AND (CAST(sqlc.narg('severity') AS TEXT) IS NULL OR severity = sqlc.narg('severity'))
+  AND (
+    (SELECT json_array_length(f.reasons) FROM filter f) = 0
+        OR reason IN (SELECT r.value FROM filter f, json_each(f.reasons) r)
+    )
+  -- composite cursor
+  AND (
+    CAST(sqlc.narg('last_ts') AS INTEGER) IS NULL
+        OR (
+        -- timestamp greater than previous page last row
+        timestamp > sqlc.narg('last_ts')
+            OR (
+            -- or timestamp equal to previous page last row
+            timestamp = sqlc.narg('last_ts')
+                -- but is greater than last row id
+                AND (CAST(sqlc.narg('last_id') AS INTEGER) IS NULL OR id > sqlc.narg('last_id'))
+            )
+        )
+    )
+ORDER BY timestamp, id
+LIMIT :limit;
+
+-- name: SearchAlerts :many
+-- reasons is a JSON array, or empty to not filter by it, and selector is a JSON
+-- array of label requirements that the devices of the alerts must all meet, see
+-- labels_match in labels.go. start_ts and end_ts are always set so that the
+-- time window is a range on alerts_timestamp_idx:
+--   SEARCH a USING INDEX alerts_timestamp_idx (timestamp>? AND timestamp<?)
+WITH filter AS (SELECT CAST(sqlc.arg('reasons') AS TEXT) AS reasons),
+     selector AS (SELECT CAST(sqlc.arg('selector') AS TEXT) AS requirements)
+SELECT a.*
+FROM alerts a
+WHERE a.timestamp >= sqlc.arg('start_ts')
+  AND a.timestamp <= sqlc.arg('end_ts')
+  AND (
+    (SELECT json_array_length(s.requirements) FROM selector s) = 0
+        OR a.device_id IN (SELECT d.id FROM devices d, selector s WHERE labels_match(d.labels, s.requirements))
+    )
+  AND (CAST(sqlc.narg('state') AS TEXT) IS NULL OR a.state = sqlc.narg('state'))
+  AND (CAST(sqlc.narg('severity') AS TEXT) IS NULL OR a.severity = sqlc.narg('severity'))
+  AND (
+    (SELECT json_array_length(f.reasons) FROM filter f) = 0
+        OR a.reason IN (SELECT r.value FROM filter f, json_each(f.reasons) r)
+    )
+  -- composite cursor, where end_ts is at most the previous page last row
+  -- timestamp
+  AND (
+    CAST(sqlc.narg('last_id') AS INTEGER) IS NULL
+        OR a.timestamp < sqlc.arg('end_ts')
+        OR a.id < sqlc.narg('last_id')
+    )
+ORDER BY a.timestamp DESC, a.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: SearchDeviceAlerts :many
+-- SearchAlerts for a list of devices, which is a separate query so that the
+-- device and time filters are ranges on alerts_device_id_timestamp_idx:
+--   SEARCH a USING INDEX alerts_device_id_timestamp_idx (device_id=? AND timestamp>? AND timestamp<?)
+-- device_ids is a JSON array that must not be empty.
+WITH filter AS (SELECT CAST(sqlc.arg('device_ids') AS TEXT) AS device_ids,
+                       CAST(sqlc.arg('reasons') AS TEXT)    AS reasons),
+     selector AS (SELECT CAST(sqlc.arg('selector') AS TEXT) AS requirements)
+SELECT a.*
+FROM alerts a
+WHERE a.device_id IN (SELECT i.value FROM filter f, json_each(f.device_ids) i)
+  AND a.timestamp >= sqlc.arg('start_ts')
+  AND a.timestamp <= sqlc.arg('end_ts')
+  AND (
+    (SELECT json_array_length(s.requirements) FROM selector s) = 0
+        OR a.device_id IN (SELECT d.id FROM devices d, selector s WHERE labels_match(d.labels, s.requirements))
+    )
+  AND (CAST(sqlc.narg('state') AS TEXT) IS NULL OR a.state = sqlc.narg('state'))
+  AND (CAST(sqlc.narg('severity') AS TEXT) IS NULL OR a.severity = sqlc.narg('severity'))
+  AND (
+    (SELECT json_array_length(f.reasons) FROM filter f) = 0
+        OR a.reason IN (SELECT r.value FROM filter f, json_each(f.reasons) r)
+    )
+  -- composite cursor, where end_ts is at most the previous page last row
+  -- timestamp
+  AND (
+    CAST(sqlc.narg('last_id') AS INTEGER) IS NULL
+        OR a.timestamp < sqlc.arg('end_ts')
+        OR a.id < sqlc.narg('last_id')
+    )
+ORDER BY a.timestamp DESC, a.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetDeviceAlert :one
+SELECT *
+FROM alerts
+WHERE device_id = ?
+  AND id = ?;
+
+-- name: GetLatestConditionAlert :one
+SELECT *
+FROM alerts
+WHERE device_id = ?
+  AND condition = ?
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: RecordAlertOccurrence :execrows
+UPDATE alerts
+SET occurrences = occurrences + 1,
+    last_seen   = MAX(last_seen, CAST(sqlc.arg('last_seen') AS INTEGER))
+WHERE device_id = sqlc.arg('device_id')
+  AND id = sqlc.arg('id');
+
+-- name: AcknowledgeDeviceAlert :execrows
+UPDATE alerts
+SET state           = 'ACKNOWLEDGED',
+    acknowledged_by = ?,
+    ack_comment     = ?,
+    acknowledged_at = ?
+WHERE device_id = ?
+  AND id = ?
+  AND state = 'OPEN';
+
+-- name: ResolveDeviceAlert :execrows
+UPDATE alerts
+SET state       = 'RESOLVED',
+    resolved_at = ?
+WHERE device_id = ?
+  AND id = ?
+  AND state != 'RESOLVED';
+
+-- name: ResolveDeviceAlertsByCondition :execrows
+UPDATE alerts
+SET state       = 'RESOLVED',
+    resolved_at = ?
+WHERE device_id = ?
+  AND condition IN (sqlc.slice('conditions'))
+  AND state != 'RESOLVED';
+
+-- name: GetAlertsAfterID :many
+SELECT *
+FROM alerts
+WHERE id > :after_id
+  AND (CAST(sqlc.narg('device_id') AS TEXT) IS NULL OR device_id = sqlc.narg('device_id'))
+ORDER BY id
+LIMIT :limit;
+
+-- name: CreateAlertRule :one
+INSERT INTO alert_rules (device_id, metric, operator, threshold, threshold_high, severity, window_seconds)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id;
+
+-- name: GetAlertRule :one
+SELECT *
+FROM alert_rules
+WHERE device_id = ?
+  AND id = ?;
+
+-- name: ListAlertRules :many
+SELECT *
+FROM alert_rules
+WHERE device_id = ?
+ORDER BY id;
+
+-- name: UpdateAlertRule :execrows
+UPDATE alert_rules
+SET metric         = ?,
+    operator       = ?,
+    threshold      = ?,
+    threshold_high = ?,
+    severity       = ?,
+    window_seconds = ?
+WHERE device_id = ?
+  AND id = ?;
+
+-- name: DeleteAlertRule :execrows
+DELETE
+FROM alert_rules
+WHERE device_id = ?
+  AND id = ?;
+
+-- name: GetConditionStates :many
+SELECT *
+FROM alert_states
+WHERE device_id = ?;
+
+-- name: UpsertConditionState :exec
+INSERT INTO alert_states (device_id, condition, breaches, active)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(device_id, condition) DO UPDATE
+    SET breaches=excluded.breaches,
+        active=excluded.active;
+
+-- name: CreateDevice :execrows
+INSERT INTO devices (id, display_name, model, firmware_version, location, labels, created_at, group_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO NOTHING;
+
+-- name: RegisterDevice :exec
+-- registers a device that is configured before it is created
+INSERT INTO devices (id, created_at)
+VALUES (?, ?)
+ON CONFLICT(id) DO NOTHING;
+
+-- name: GetDevice :one
+SELECT *
+FROM devices
+WHERE id = ?;
+
+-- name: UpdateDevice :execrows
+UPDATE devices
+SET display_name     = ?,
+    model            = ?,
+    firmware_version = ?,
+    location         = ?,
+    labels           = ?,
+    group_id         = ?
+WHERE id = ?;
+
+-- name: TouchDevice :exec
+-- registers a device recording a metric for the first time and tracks when it
+-- was last seen
+INSERT INTO devices (id, created_at, last_seen)
+VALUES (sqlc.arg('id'), sqlc.arg('seen_at'), sqlc.arg('seen_at'))
+ON CONFLICT(id) DO UPDATE SET last_seen = MAX(COALESCE(last_seen, 0), excluded.last_seen);
+
+-- name: ListDevices :many
+-- selector is a JSON array of label requirements that devices must all meet,
+-- see labels_match in labels.go
+SELECT d.*
+FROM devices d
+WHERE labels_match(d.labels, CAST(sqlc.arg('selector') AS TEXT))
+  -- composite cursor
+  AND (
+    CAST(sqlc.narg('last_ts') AS INTEGER) IS NULL
+        OR (
+        -- created before previous page last row
+        d.created_at < sqlc.narg('last_ts')
+            OR (
+            -- or created at the same time as previous page last row
+            d.created_at = sqlc.narg('last_ts')
+                -- but is less than last row seq
+                AND (CAST(sqlc.narg('last_seq') AS INTEGER) IS NULL OR d.seq < sqlc.narg('last_seq'))
+            )
+        )
+    )
+ORDER BY d.created_at DESC, d.seq DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetFleetSnapshot :many
+-- selector is a JSON array of label requirements that devices must all meet,
+-- see labels_match in labels.go
+SELECT sqlc.embed(d),
+       l.metric_id AS latest_metric_id,
+       l.timestamp AS latest_ts,
+       (SELECT COUNT(*)
+        FROM alerts a
+        WHERE a.device_id = d.id
+          AND a.state != 'RESOLVED') AS open_alerts
+FROM devices d
+         LEFT JOIN device_latest l ON l.device_id = d.id
+WHERE labels_match(d.labels, CAST(sqlc.arg('selector') AS TEXT))
+  AND (CAST(sqlc.narg('group_id') AS TEXT) IS NULL OR d.group_id = sqlc.narg('group_id'))
+  -- composite cursor
+  AND (
+    CAST(sqlc.narg('last_ts') AS INTEGER) IS NULL
+        OR (
+        -- created before previous page last row
+        d.created_at < sqlc.narg('last_ts')
+            OR (
+            -- or created at the same time as previous page last row
+            d.created_at = sqlc.narg('last_ts')
+                -- but is less than last row seq
+                AND (CAST(sqlc.narg('last_seq') AS INTEGER) IS NULL OR d.seq < sqlc.narg('last_seq'))
+            )
+        )
+    )
+ORDER BY d.created_at DESC, d.seq DESC
+LIMIT sqlc.arg('limit');
+
+-- name: DeleteDeviceLatest :exec
+DELETE
+FROM device_latest
+WHERE device_id = ?;
+
+-- name: CreateDeviceGroup :execrows
+INSERT INTO device_groups (id, display_name, created_at)
+VALUES (?, ?, ?)
+ON CONFLICT(id) DO NOTHING;
+
+-- name: GetDeviceGroup :one
+SELECT *
+FROM device_groups
+WHERE id = ?;
+
+-- name: ListDeviceGroups :many
+SELECT *
+FROM device_groups
+ORDER BY id;
+
+-- name: DeleteDevice :execrows
+DELETE
+FROM devices
+WHERE id = ?;
+
+-- name: DeleteDeviceConfigSettings :exec
+DELETE
+FROM config_settings
+WHERE level = 'DEVICE'
+  AND scope_id = ?;
+
+-- name: DeleteDeviceConfigVersions :exec
+DELETE
+FROM device_config_versions
+WHERE device_id = ?;
+
+-- name: DeleteDeviceAlertRules :exec
+DELETE
+FROM alert_rules
+WHERE device_id = ?;
+
+-- name: DeleteDeviceConditionStates :exec
+DELETE
+FROM alert_states
+WHERE device_id = ?;
+
+-- name: GetDeviceMaxMetricID :one
+SELECT CAST(COALESCE(MAX(id), 0) AS INTEGER) AS max_id
+FROM metrics
+WHERE device_id = ?;
+
+-- name: GetDeviceMaxAlertID :one
+SELECT CAST(COALESCE(MAX(id), 0) AS INTEGER) AS max_id
+FROM alerts
+WHERE device_id = ?;
+
+-- name: DeleteDeviceMetricsBatch :execrows
+-- metric values are deleted by cascade
+DELETE
+FROM metrics
+WHERE id IN (SELECT m.id
+             FROM metrics m
+             WHERE m.device_id = sqlc.arg('device_id')
+               AND m.id <= sqlc.arg('max_id')
+             ORDER BY m.id
+             LIMIT sqlc.arg('limit'));
+
+-- name: DeleteDeviceAlertsBatch :execrows
+DELETE
+FROM alerts
+WHERE id IN (SELECT a.id
+             FROM alerts a
+             WHERE a.device_id = sqlc.arg('device_id')
+               AND a.id <= sqlc.arg('max_id')
+             ORDER BY a.id
+             LIMIT sqlc.arg('limit'));
+
+-- name: CreateOperation :one
+INSERT INTO operations (type, device_id, state, deleted_metrics, deleted_alerts, max_metric_id, max_alert_id, error,
+                        created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id;
+
+-- name: UpdateOperation :execrows
+UPDATE operations
+SET state           = ?,
+    deleted_metrics = ?,
+    deleted_alerts  = ?,
+    error           = ?,
+    updated_at      = ?
+WHERE id = ?;
+
+-- name: GetOperation :one
+SELECT *
+FROM operations
+WHERE id = ?;
+
+-- name: ListOperationsByState :many
+SELECT *
+FROM operations
+WHERE state = ?
+ORDER BY id;
+
+-- name: CountAlertsByReason :many
+SELECT reason, COUNT(*) AS count
+FROM alerts
+WHERE timestamp >= sqlc.arg('start_ts')
+  AND timestamp <= sqlc.arg('end_ts')
+GROUP BY reason
+ORDER BY reason;
+
+-- name: CountAlertsByDevice :many
+SELECT device_id, COUNT(*) AS count
+FROM alerts
+WHERE timestamp >= sqlc.arg('start_ts')
+  AND timestamp <= sqlc.arg('end_ts')
+GROUP BY device_id
+ORDER BY device_id;
+
+-- name: CountAlertsByBucket :many
+-- counts alerts in buckets aligned to the unix epoch
+SELECT CAST(timestamp - timestamp % sqlc.arg('bucket_seconds') AS INTEGER) AS bucket_start,
+       COUNT(*)                                                             AS count
+FROM alerts
+WHERE timestamp >= sqlc.arg('start_ts')
+  AND timestamp <= sqlc.arg('end_ts')
+GROUP BY bucket_start
+ORDER BY bucket_start;
+
+-- name: GetNoisiestDevices :many
+SELECT device_id, COUNT(*) AS count
+FROM alerts
+WHERE timestamp >= sqlc.arg('start_ts')
+  AND timestamp <= sqlc.arg('end_ts')
+GROUP BY device_id
+ORDER BY count DESC, device_id
+LIMIT sqlc.arg('limit');
@@ -3,7 +3,10 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/joshjon/iot-metrics/device"
@@ -13,29 +16,322 @@ import (
 var _ device.Repository = (*DeviceRepository)(nil)
 
 type DeviceRepository struct {
+	db      *sql.DB
 	querier sqlc.Querier
 }
 
 func NewDeviceRepository(db *sql.DB) *DeviceRepository {
 	return &DeviceRepository{
+		db:      db,
 		querier: sqlc.New(db),
 	}
 }
 
-func (d *DeviceRepository) UpsertDeviceConfig(ctx context.Context, deviceID string, config device.Config) error {
-	return d.querier.UpsertDeviceConfig(ctx, sqlc.UpsertDeviceConfigParams{
-		DeviceID:             deviceID,
-		TemperatureThreshold: config.TemperatureThreshold,
-		BatteryThreshold:     int64(config.BatteryThreshold),
+// withTx runs fn within a database transaction, committing if fn succeeds and
+// rolling back otherwise.
+func (d *DeviceRepository) withTx(ctx context.Context, fn func(querier sqlc.Querier) error) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err = fn(sqlc.New(tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// expressionJSON is the stored representation of a device.AlertExpression.
+type expressionJSON struct {
+	Expr     string `json:"expr"`
+	Severity string `json:"severity"`
+}
+
+// cooldownJSON is the stored representation of a device.AlertCooldown.
+type cooldownJSON struct {
+	Reason        string `json:"reason"`
+	WindowSeconds int64  `json:"window_seconds"`
+}
+
+func (d *DeviceRepository) UpsertDeviceConfig(
+	ctx context.Context,
+	deviceID string,
+	version device.DeviceConfigVersion,
+	expectedRevision *int64,
+) (int64, error) {
+	cols, err := configSettingsColumnsFrom(version.Settings)
+	if err != nil {
+		return 0, err
+	}
+
+	var num int64
+	err = d.withTx(ctx, func(querier sqlc.Querier) error {
+		err := querier.RegisterDevice(ctx, sqlc.RegisterDeviceParams{
+			ID:        deviceID,
+			CreatedAt: time.Now().Unix(),
+		})
+		if err != nil {
+			return err
+		}
+		num, err = querier.SaveDeviceConfigVersion(ctx, sqlc.SaveDeviceConfigVersionParams{
+			DeviceID:                 deviceID,
+			TemperatureThreshold:     cols.TemperatureThreshold,
+			BatteryThreshold:         cols.BatteryThreshold,
+			Expressions:              cols.Expressions,
+			ConsecutiveBreaches:      cols.ConsecutiveBreaches,
+			Hysteresis:               cols.Hysteresis,
+			Cooldowns:                cols.Cooldowns,
+			ReportingIntervalSeconds: cols.ReportingIntervalSeconds,
+			CreatedAt:                version.CreatedAt.Unix(),
+			Author:                   version.Author,
+			Reason:                   version.Reason,
+			ExpectedVersion:          expectedRevision,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return device.ErrRepoRevisionMismatch
+			}
+			return err
+		}
+		return upsertConfigSettings(ctx, querier, device.ConfigLevelDevice, deviceID, cols, num)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return num, nil
+}
+
+func (d *DeviceRepository) UpsertDeviceGroupConfig(ctx context.Context, groupID string, settings device.ConfigSettings) error {
+	cols, err := configSettingsColumnsFrom(settings)
+	if err != nil {
+		return err
+	}
+	return upsertConfigSettings(ctx, d.querier, device.ConfigLevelGroup, groupID, cols, 0)
+}
+
+func (d *DeviceRepository) UpsertGlobalConfig(ctx context.Context, settings device.ConfigSettings) error {
+	cols, err := configSettingsColumnsFrom(settings)
+	if err != nil {
+		return err
+	}
+	return upsertConfigSettings(ctx, d.querier, device.ConfigLevelGlobal, "", cols, 0)
+}
+
+func upsertConfigSettings(
+	ctx context.Context,
+	querier sqlc.Querier,
+	level device.ConfigLevel,
+	scopeID string,
+	cols configSettingsColumns,
+	version int64,
+) error {
+	return querier.UpsertConfigSettings(ctx, sqlc.UpsertConfigSettingsParams{
+		Level:                    string(level),
+		ScopeID:                  scopeID,
+		TemperatureThreshold:     cols.TemperatureThreshold,
+		BatteryThreshold:         cols.BatteryThreshold,
+		Expressions:              cols.Expressions,
+		ConsecutiveBreaches:      cols.ConsecutiveBreaches,
+		Hysteresis:               cols.Hysteresis,
+		Cooldowns:                cols.Cooldowns,
+		ReportingIntervalSeconds: cols.ReportingIntervalSeconds,
+		Version:                  version,
+	})
+}
+
+// configSettingsColumns are the stored columns of device.ConfigSettings, shared
+// by the current settings of each level and the device config versions.
+type configSettingsColumns struct {
+	TemperatureThreshold     *float64
+	BatteryThreshold         *int64
+	Expressions              *string
+	ConsecutiveBreaches      *int64
+	Hysteresis               *float64
+	Cooldowns                *string
+	ReportingIntervalSeconds *int64
+}
+
+func configSettingsColumnsFrom(settings device.ConfigSettings) (configSettingsColumns, error) {
+	cols := configSettingsColumns{
+		TemperatureThreshold: settings.TemperatureThreshold,
+		Hysteresis:           settings.Hysteresis,
+	}
+	if settings.BatteryThreshold != nil {
+		cols.BatteryThreshold = ptr(int64(*settings.BatteryThreshold))
+	}
+	if settings.ConsecutiveBreaches != nil {
+		cols.ConsecutiveBreaches = ptr(int64(*settings.ConsecutiveBreaches))
+	}
+	if settings.ReportingInterval != nil {
+		cols.ReportingIntervalSeconds = ptr(int64(*settings.ReportingInterval / time.Second))
+	}
+	if settings.Expressions != nil {
+		exprs := make([]expressionJSON, len(settings.Expressions))
+		for i, e := range settings.Expressions {
+			exprs[i] = expressionJSON{Expr: e.Expr, Severity: string(e.Severity)}
+		}
+		exprsJSON, err := json.Marshal(exprs)
+		if err != nil {
+			return configSettingsColumns{}, fmt.Errorf("marshal expressions: %w", err)
+		}
+		cols.Expressions = ptr(string(exprsJSON))
+	}
+	if settings.Cooldowns != nil {
+		cooldowns := make([]cooldownJSON, len(settings.Cooldowns))
+		for i, c := range settings.Cooldowns {
+			cooldowns[i] = cooldownJSON{Reason: string(c.Reason), WindowSeconds: int64(c.Window / time.Second)}
+		}
+		cooldownsJSON, err := json.Marshal(cooldowns)
+		if err != nil {
+			return configSettingsColumns{}, fmt.Errorf("marshal cooldowns: %w", err)
+		}
+		cols.Cooldowns = ptr(string(cooldownsJSON))
+	}
+	return cols, nil
+}
+
+func (d *DeviceRepository) ListDeviceConfigVersions(
+	ctx context.Context,
+	deviceID string,
+	pageOpts device.RepositoryPageOptions,
+) (device.RepositoryPage[device.DeviceConfigVersion], error) {
+	params := sqlc.ListDeviceConfigVersionsParams{
+		DeviceID: deviceID,
+		Limit:    int64(pageOpts.Size + 1),
+	}
+	if pageOpts.Token != nil {
+		params.LastVersion = pageOpts.Token.LastID
+	}
+
+	rows, err := d.querier.ListDeviceConfigVersions(ctx, params)
+	if err != nil {
+		return device.RepositoryPage[device.DeviceConfigVersion]{}, err
+	}
+
+	var nextPageTkn *device.RepositoryPageToken
+	// check if another page exists
+	if len(rows) == int(params.Limit) {
+		rows = rows[:len(rows)-1] // remove peeked row
+		lastRow := rows[len(rows)-1]
+
+		nextPageTkn = &device.RepositoryPageToken{
+			LastID:   &lastRow.Version,
+			LastTime: ptr(time.Unix(lastRow.CreatedAt, 0).UTC()),
+		}
+	}
+
+	versions := make([]device.DeviceConfigVersion, len(rows))
+	for i, row := range rows {
+		if versions[i], err = deviceConfigVersionFromRow(row); err != nil {
+			return device.RepositoryPage[device.DeviceConfigVersion]{}, err
+		}
+	}
+
+	return device.RepositoryPage[device.DeviceConfigVersion]{
+		Items:         versions,
+		NextPageToken: nextPageTkn,
+	}, nil
+}
+
+func (d *DeviceRepository) GetDeviceConfigVersion(ctx context.Context, deviceID string, version int64) (device.DeviceConfigVersion, error) {
+	row, err := d.querier.GetDeviceConfigVersion(ctx, sqlc.GetDeviceConfigVersionParams{
+		DeviceID: deviceID,
+		Version:  version,
 	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return device.DeviceConfigVersion{}, device.ErrRepoItemNotFound
+		}
+		return device.DeviceConfigVersion{}, err
+	}
+	return deviceConfigVersionFromRow(row)
+}
+
+func (d *DeviceRepository) GetLatestDeviceConfigVersion(ctx context.Context, deviceID string) (device.DeviceConfigVersion, error) {
+	row, err := d.querier.GetLatestDeviceConfigVersion(ctx, deviceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return device.DeviceConfigVersion{}, device.ErrRepoItemNotFound
+		}
+		return device.DeviceConfigVersion{}, err
+	}
+	return deviceConfigVersionFromRow(row)
+}
+
+func deviceConfigVersionFromRow(row *sqlc.DeviceConfigVersion) (device.DeviceConfigVersion, error) {
+	settings, err := configSettingsColumns{
+		TemperatureThreshold:     row.TemperatureThreshold,
+		BatteryThreshold:         row.BatteryThreshold,
+		Expressions:              row.Expressions,
+		ConsecutiveBreaches:      row.ConsecutiveBreaches,
+		Hysteresis:               row.Hysteresis,
+		Cooldowns:                row.Cooldowns,
+		ReportingIntervalSeconds: row.ReportingIntervalSeconds,
+	}.settings()
+	if err != nil {
+		return device.DeviceConfigVersion{}, err
+	}
+	return device.DeviceConfigVersion{
+		Version:   row.Version,
+		Settings:  settings,
+		CreatedAt: time.Unix(row.CreatedAt, 0).UTC(),
+		Author:    row.Author,
+		Reason:    row.Reason,
+	}, nil
 }
 
 func (d *DeviceRepository) SaveDeviceMetric(ctx context.Context, deviceID string, metric device.Metric) error {
-	return d.querier.SaveDeviceMetric(ctx, sqlc.SaveDeviceMetricParams{
-		DeviceID:    deviceID,
-		Temperature: metric.Temperature,
-		Battery:     int64(metric.Battery),
-		Timestamp:   metric.Time.Unix(),
+	return d.withTx(ctx, func(querier sqlc.Querier) error {
+		return saveDeviceMetric(ctx, querier, deviceID, metric)
+	})
+}
+
+func (d *DeviceRepository) SaveDeviceMetrics(ctx context.Context, deviceID string, metrics []device.Metric) error {
+	return d.withTx(ctx, func(querier sqlc.Querier) error {
+		for _, metric := range metrics {
+			if err := saveDeviceMetric(ctx, querier, deviceID, metric); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func saveDeviceMetric(ctx context.Context, querier sqlc.Querier, deviceID string, metric device.Metric) error {
+	err := querier.TouchDevice(ctx, sqlc.TouchDeviceParams{
+		ID:     deviceID,
+		SeenAt: metric.Time.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	metricID, err := querier.SaveDeviceMetric(ctx, sqlc.SaveDeviceMetricParams{
+		DeviceID:  deviceID,
+		Timestamp: metric.Time.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, v := range metric.Values {
+		err = querier.SaveMetricValue(ctx, sqlc.SaveMetricValueParams{
+			MetricID: metricID,
+			Name:     v.Name,
+			Value:    v.Value,
+			Unit:     v.Unit,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return querier.UpsertDeviceLatest(ctx, sqlc.UpsertDeviceLatestParams{
+		DeviceID:  deviceID,
+		MetricID:  metricID,
+		Timestamp: metric.Time.Unix(),
 	})
 }
 
@@ -60,7 +356,13 @@ func (d *DeviceRepository) GetDeviceMetrics(
 		params.LastTs = ptr(pageOpts.Token.LastTime.Unix())
 	}
 
-	rows, err := d.querier.GetDeviceMetrics(ctx, params)
+	var rows []*sqlc.Metric
+	var err error
+	if pageOpts.Order == device.SortOrderAsc {
+		rows, err = d.querier.GetDeviceMetricsAsc(ctx, sqlc.GetDeviceMetricsAscParams(params))
+	} else {
+		rows, err = d.querier.GetDeviceMetrics(ctx, params)
+	}
 	if err != nil {
 		return device.RepositoryPage[device.Metric]{}, err
 	}
@@ -74,15 +376,24 @@ func (d *DeviceRepository) GetDeviceMetrics(
 		nextPageTkn = &device.RepositoryPageToken{
 			LastID:   &lastRow.ID,
 			LastTime: ptr(time.Unix(lastRow.Timestamp, 0).UTC()),
+			Order:    pageOpts.Order,
 		}
 	}
 
+	metricIDs := make([]int64, len(rows))
+	for i, row := range rows {
+		metricIDs[i] = row.ID
+	}
+	values, err := d.getMetricValues(ctx, metricIDs)
+	if err != nil {
+		return device.RepositoryPage[device.Metric]{}, err
+	}
+
 	metrics := make([]device.Metric, len(rows))
 	for i, row := range rows {
 		metrics[i] = device.Metric{
-			Temperature: row.Temperature,
-			Battery:     int32(row.Battery),
-			Time:        time.Unix(row.Timestamp, 0).UTC(),
+			Values: values[row.ID],
+			Time:   time.Unix(row.Timestamp, 0).UTC(),
 		}
 	}
 
@@ -92,37 +403,268 @@ func (d *DeviceRepository) GetDeviceMetrics(
 	}, nil
 }
 
-func (d *DeviceRepository) GetDeviceConfig(ctx context.Context, deviceID string) (device.Config, error) {
-	cfg, err := d.querier.GetDeviceConfig(ctx, deviceID)
+// getMetricValues returns the values of metrics by metric ID.
+func (d *DeviceRepository) getMetricValues(ctx context.Context, metricIDs []int64) (map[int64][]device.MetricValue, error) {
+	rows, err := d.querier.GetMetricValues(ctx, metricIDs)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return device.Config{}, device.ErrRepoItemNotFound
+		return nil, err
+	}
+	values := make(map[int64][]device.MetricValue, len(metricIDs))
+	for _, row := range rows {
+		values[row.MetricID] = append(values[row.MetricID], device.MetricValue{
+			Name:  row.Name,
+			Value: row.Value,
+			Unit:  row.Unit,
+		})
+	}
+	return values, nil
+}
+
+func (d *DeviceRepository) GetDeviceMetricAggregates(
+	ctx context.Context,
+	deviceID string,
+	start time.Time,
+	end time.Time,
+	width time.Duration,
+) ([]device.MetricAggregates, error) {
+	rows, err := d.querier.GetDeviceMetricAggregates(ctx, sqlc.GetDeviceMetricAggregatesParams{
+		BucketSeconds: int64(width / time.Second),
+		DeviceID:      deviceID,
+		StartTs:       start.Unix(),
+		EndTs:         end.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var aggregates []device.MetricAggregates
+	for _, row := range rows {
+		// rows are ordered by name, so a new name starts the next metric
+		if len(aggregates) == 0 || aggregates[len(aggregates)-1].Name != row.Name {
+			aggregates = append(aggregates, device.MetricAggregates{Name: row.Name})
 		}
-		return device.Config{}, err
+		a := &aggregates[len(aggregates)-1]
+		a.Buckets = append(a.Buckets, device.MetricBucket{
+			Start: time.Unix(row.BucketStart, 0).UTC(),
+			Count: row.Count,
+			Min:   ptr(row.MinValue),
+			Max:   ptr(row.MaxValue),
+			Avg:   ptr(row.AvgValue),
+			First: ptr(row.FirstReading),
+			Last:  ptr(row.LastReading),
+		})
 	}
-	return device.Config{
-		TemperatureThreshold: cfg.TemperatureThreshold,
-		BatteryThreshold:     int32(cfg.BatteryThreshold),
-	}, nil
+	return aggregates, nil
 }
 
-func (d *DeviceRepository) SaveDeviceAlert(ctx context.Context, deviceID string, alert device.Alert) error {
-	return d.querier.SaveDeviceAlert(ctx, sqlc.SaveDeviceAlertParams{
-		DeviceID:  deviceID,
-		Reason:    string(alert.Reason),
-		Desc:      alert.Desc,
-		Timestamp: alert.Time.Unix(),
+func (d *DeviceRepository) GetMetricRange(
+	ctx context.Context,
+	deviceID string,
+	name string,
+	start time.Time,
+	end time.Time,
+) (device.MetricRange, error) {
+	row, err := d.querier.GetMetricRange(ctx, sqlc.GetMetricRangeParams{
+		DeviceID: deviceID,
+		StartTs:  start.Unix(),
+		EndTs:    end.Unix(),
+		Name:     name,
+	})
+	if err != nil {
+		return device.MetricRange{}, err
+	}
+	if row.Count == 0 {
+		return device.MetricRange{}, device.ErrRepoItemNotFound
+	}
+	return device.MetricRange{Low: row.Low, High: row.High}, nil
+}
+
+func (d *DeviceRepository) GetAlertStats(
+	ctx context.Context,
+	start time.Time,
+	end time.Time,
+	width time.Duration,
+	topN int,
+) (device.AlertStats, error) {
+	var stats device.AlertStats
+	// count within a transaction so that all counts are of the same alerts
+	err := d.withTx(ctx, func(querier sqlc.Querier) error {
+		reasonRows, err := querier.CountAlertsByReason(ctx, sqlc.CountAlertsByReasonParams{
+			StartTs: start.Unix(),
+			EndTs:   end.Unix(),
+		})
+		if err != nil {
+			return fmt.Errorf("count alerts by reason: %w", err)
+		}
+		for _, row := range reasonRows {
+			stats.Total += row.Count
+			stats.ByReason = append(stats.ByReason, device.ReasonAlertCount{
+				Reason: device.AlertReason(row.Reason),
+				Count:  row.Count,
+			})
+		}
+
+		deviceRows, err := querier.CountAlertsByDevice(ctx, sqlc.CountAlertsByDeviceParams{
+			StartTs: start.Unix(),
+			EndTs:   end.Unix(),
+		})
+		if err != nil {
+			return fmt.Errorf("count alerts by device: %w", err)
+		}
+		for _, row := range deviceRows {
+			stats.ByDevice = append(stats.ByDevice, device.DeviceAlertCount{
+				DeviceID: row.DeviceID,
+				Count:    row.Count,
+			})
+		}
+
+		bucketRows, err := querier.CountAlertsByBucket(ctx, sqlc.CountAlertsByBucketParams{
+			BucketSeconds: int64(width / time.Second),
+			StartTs:       start.Unix(),
+			EndTs:         end.Unix(),
+		})
+		if err != nil {
+			return fmt.Errorf("count alerts by bucket: %w", err)
+		}
+		for _, row := range bucketRows {
+			stats.Buckets = append(stats.Buckets, device.AlertCountBucket{
+				Start: time.Unix(row.BucketStart, 0).UTC(),
+				Count: row.Count,
+			})
+		}
+
+		topRows, err := querier.GetNoisiestDevices(ctx, sqlc.GetNoisiestDevicesParams{
+			StartTs: start.Unix(),
+			EndTs:   end.Unix(),
+			Limit:   int64(topN),
+		})
+		if err != nil {
+			return fmt.Errorf("get noisiest devices: %w", err)
+		}
+		for _, row := range topRows {
+			stats.TopDevices = append(stats.TopDevices, device.DeviceAlertCount{
+				DeviceID: row.DeviceID,
+				Count:    row.Count,
+			})
+		}
+		return nil
 	})
+	if err != nil {
+		return device.AlertStats{}, err
+	}
+	return stats, nil
+}
+
+func (d *DeviceRepository) GetDeviceConfig(ctx context.Context, deviceID string) (device.Config, error) {
+	rows, err := d.querier.GetDeviceConfigSettings(ctx, deviceID)
+	if err != nil {
+		return device.Config{}, err
+	}
+
+	levels := make(map[device.ConfigLevel]device.ConfigSettings, len(rows))
+	var version int64
+	for _, row := range rows {
+		settings, err := configSettingsColumns{
+			TemperatureThreshold:     row.TemperatureThreshold,
+			BatteryThreshold:         row.BatteryThreshold,
+			Expressions:              row.Expressions,
+			ConsecutiveBreaches:      row.ConsecutiveBreaches,
+			Hysteresis:               row.Hysteresis,
+			Cooldowns:                row.Cooldowns,
+			ReportingIntervalSeconds: row.ReportingIntervalSeconds,
+		}.settings()
+		if err != nil {
+			return device.Config{}, err
+		}
+		levels[device.ConfigLevel(row.Level)] = settings
+		if device.ConfigLevel(row.Level) == device.ConfigLevelDevice {
+			version = row.Version
+		}
+	}
+	cfg := device.ResolveConfig(levels)
+	if len(cfg.Sources) == 0 {
+		return device.Config{}, device.ErrRepoItemNotFound
+	}
+	cfg.Version = version
+	return cfg, nil
+}
+
+func (c configSettingsColumns) settings() (device.ConfigSettings, error) {
+	settings := device.ConfigSettings{
+		TemperatureThreshold: c.TemperatureThreshold,
+		Hysteresis:           c.Hysteresis,
+	}
+	if c.BatteryThreshold != nil {
+		settings.BatteryThreshold = ptr(int32(*c.BatteryThreshold))
+	}
+	if c.ConsecutiveBreaches != nil {
+		settings.ConsecutiveBreaches = ptr(int32(*c.ConsecutiveBreaches))
+	}
+	if c.ReportingIntervalSeconds != nil {
+		settings.ReportingInterval = ptr(time.Duration(*c.ReportingIntervalSeconds) * time.Second)
+	}
+	if c.Expressions != nil {
+		var exprs []expressionJSON
+		if err := json.Unmarshal([]byte(*c.Expressions), &exprs); err != nil {
+			return device.ConfigSettings{}, fmt.Errorf("unmarshal expressions: %w", err)
+		}
+		settings.Expressions = make([]device.AlertExpression, len(exprs))
+		for i, e := range exprs {
+			settings.Expressions[i] = device.AlertExpression{
+				Expr:     e.Expr,
+				Severity: device.AlertSeverity(e.Severity),
+			}
+		}
+	}
+	if c.Cooldowns != nil {
+		var cooldowns []cooldownJSON
+		if err := json.Unmarshal([]byte(*c.Cooldowns), &cooldowns); err != nil {
+			return device.ConfigSettings{}, fmt.Errorf("unmarshal cooldowns: %w", err)
+		}
+		settings.Cooldowns = make([]device.AlertCooldown, len(cooldowns))
+		for i, c := range cooldowns {
+			settings.Cooldowns[i] = device.AlertCooldown{
+				Reason: device.AlertReason(c.Reason),
+				Window: time.Duration(c.WindowSeconds) * time.Second,
+			}
+		}
+	}
+	return settings, nil
+}
+
+func (d *DeviceRepository) SaveDeviceAlert(ctx context.Context, deviceID string, alert device.Alert) (int64, error) {
+	params := sqlc.SaveDeviceAlertParams{
+		DeviceID:      deviceID,
+		Reason:        string(alert.Reason),
+		Severity:      string(alert.Severity),
+		Desc:          alert.Desc,
+		Timestamp:     alert.Time.Unix(),
+		State:         string(alert.State),
+		Condition:     alert.Condition,
+		Occurrences:   alert.Occurrences,
+		LastSeen:      alert.LastSeen.Unix(),
+		ConfigVersion: alert.ConfigVersion,
+	}
+	if alert.RuleID != 0 {
+		params.RuleID = &alert.RuleID
+	}
+	return d.querier.SaveDeviceAlert(ctx, params)
 }
 
 func (d *DeviceRepository) GetDeviceAlerts(
 	ctx context.Context,
 	deviceID string,
 	timeframe device.Timeframe,
+	filter device.AlertFilter,
 	pageOpts device.RepositoryPageOptions,
 ) (device.RepositoryPage[device.Alert], error) {
+	reasons, err := marshalStrings(filter.Reasons)
+	if err != nil {
+		return device.RepositoryPage[device.Alert]{}, err
+	}
 	params := sqlc.GetDeviceAlertsParams{
 		DeviceID: deviceID,
+		Reasons:  reasons,
 		Limit:    int64(pageOpts.Size + 1),
 	}
 	if timeframe.Start != nil {
@@ -131,41 +673,816 @@ func (d *DeviceRepository) GetDeviceAlerts(
 	if timeframe.End != nil {
 		params.EndTs = ptr(timeframe.End.Unix())
 	}
+	if filter.State != "" {
+		params.State = ptr(string(filter.State))
+	}
+	if filter.Severity != "" {
+		params.Severity = ptr(string(filter.Severity))
+	}
 	if pageOpts.Token != nil {
 		params.LastID = pageOpts.Token.LastID
 		params.LastTs = ptr(pageOpts.Token.LastTime.Unix())
 	}
 
-	rows, err := d.querier.GetDeviceAlerts(ctx, params)
+	var rows []*sqlc.Alert
+	if pageOpts.Order == device.SortOrderAsc {
+		rows, err = d.querier.GetDeviceAlertsAsc(ctx, sqlc.GetDeviceAlertsAscParams(params))
+	} else {
+		rows, err = d.querier.GetDeviceAlerts(ctx, params)
+	}
+	if err != nil {
+		return device.RepositoryPage[device.Alert]{}, err
+	}
+	return alertsPage(rows, int(params.Limit), pageOpts.Order), nil
+}
+
+func (d *DeviceRepository) SearchAlerts(
+	ctx context.Context,
+	deviceIDs []string,
+	selector device.LabelSelector,
+	timeframe device.Timeframe,
+	filter device.AlertFilter,
+	pageOpts device.RepositoryPageOptions,
+) (device.RepositoryPage[device.Alert], error) {
+	reasons, err := marshalStrings(filter.Reasons)
+	if err != nil {
+		return device.RepositoryPage[device.Alert]{}, err
+	}
+	selectorJSON, err := marshalLabelSelector(selector)
+	if err != nil {
+		return device.RepositoryPage[device.Alert]{}, err
+	}
+	// the time window is always bounded so that it is a range on the index
+	params := sqlc.SearchAlertsParams{
+		StartTs:  math.MinInt64,
+		EndTs:    math.MaxInt64,
+		Reasons:  reasons,
+		Selector: selectorJSON,
+		Limit:    int64(pageOpts.Size + 1),
+	}
+	if timeframe.Start != nil {
+		params.StartTs = timeframe.Start.Unix()
+	}
+	if timeframe.End != nil {
+		params.EndTs = timeframe.End.Unix()
+	}
+	if filter.State != "" {
+		params.State = ptr(string(filter.State))
+	}
+	if filter.Severity != "" {
+		params.Severity = ptr(string(filter.Severity))
+	}
+	if pageOpts.Token != nil {
+		// the cursor narrows the end of the window to the previous page last
+		// row, unless every alert in the window is before it anyway
+		if lastTs := pageOpts.Token.LastTime.Unix(); lastTs <= params.EndTs {
+			params.EndTs = lastTs
+			params.LastID = pageOpts.Token.LastID
+		}
+	}
+
+	var rows []*sqlc.Alert
+	if len(deviceIDs) > 0 {
+		var deviceIDsJSON string
+		if deviceIDsJSON, err = marshalStrings(deviceIDs); err != nil {
+			return device.RepositoryPage[device.Alert]{}, err
+		}
+		rows, err = d.querier.SearchDeviceAlerts(ctx, sqlc.SearchDeviceAlertsParams{
+			StartTs:   params.StartTs,
+			EndTs:     params.EndTs,
+			State:     params.State,
+			Severity:  params.Severity,
+			LastID:    params.LastID,
+			Limit:     params.Limit,
+			DeviceIds: deviceIDsJSON,
+			Reasons:   params.Reasons,
+			Selector:  params.Selector,
+		})
+	} else {
+		rows, err = d.querier.SearchAlerts(ctx, params)
+	}
 	if err != nil {
 		return device.RepositoryPage[device.Alert]{}, err
 	}
+	return alertsPage(rows, int(params.Limit), pageOpts.Order), nil
+}
 
+// alertsPage converts alert rows queried with the limit in the order into a
+// page, using the peeked row beyond the page size to determine if another page
+// exists.
+func alertsPage(rows []*sqlc.Alert, limit int, order device.SortOrder) device.RepositoryPage[device.Alert] {
 	var nextPageTkn *device.RepositoryPageToken
 	// check if another page exists
-	if len(rows) == int(params.Limit) {
+	if len(rows) == limit {
 		rows = rows[:len(rows)-1] // remove peeked row
 		lastRow := rows[len(rows)-1]
 
 		nextPageTkn = &device.RepositoryPageToken{
 			LastID:   &lastRow.ID,
 			LastTime: ptr(time.Unix(lastRow.Timestamp, 0).UTC()),
+			Order:    order,
 		}
 	}
 
 	alerts := make([]device.Alert, len(rows))
 	for i, row := range rows {
-		alerts[i] = device.Alert{
-			Reason: device.AlertReason(row.Reason),
-			Desc:   row.Desc,
-			Time:   time.Unix(row.Timestamp, 0).UTC(),
-		}
+		alerts[i] = alertFromRow(row)
 	}
 
 	return device.RepositoryPage[device.Alert]{
 		Items:         alerts,
 		NextPageToken: nextPageTkn,
-	}, nil
+	}
+}
+
+func (d *DeviceRepository) GetDeviceAlert(ctx context.Context, deviceID string, alertID int64) (device.Alert, error) {
+	row, err := d.querier.GetDeviceAlert(ctx, sqlc.GetDeviceAlertParams{
+		DeviceID: deviceID,
+		ID:       alertID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return device.Alert{}, device.ErrRepoItemNotFound
+		}
+		return device.Alert{}, err
+	}
+	return alertFromRow(row), nil
+}
+
+func (d *DeviceRepository) GetLatestConditionAlert(ctx context.Context, deviceID string, condition string) (device.Alert, error) {
+	row, err := d.querier.GetLatestConditionAlert(ctx, sqlc.GetLatestConditionAlertParams{
+		DeviceID:  deviceID,
+		Condition: condition,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return device.Alert{}, device.ErrRepoItemNotFound
+		}
+		return device.Alert{}, err
+	}
+	return alertFromRow(row), nil
+}
+
+func (d *DeviceRepository) RecordAlertOccurrence(ctx context.Context, deviceID string, alertID int64, at time.Time) error {
+	n, err := d.querier.RecordAlertOccurrence(ctx, sqlc.RecordAlertOccurrenceParams{
+		LastSeen: at.Unix(),
+		DeviceID: deviceID,
+		ID:       alertID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return device.ErrRepoItemNotFound
+	}
+	return nil
+}
+
+func (d *DeviceRepository) AcknowledgeDeviceAlert(
+	ctx context.Context,
+	deviceID string,
+	alertID int64,
+	by string,
+	comment string,
+	at time.Time,
+) error {
+	n, err := d.querier.AcknowledgeDeviceAlert(ctx, sqlc.AcknowledgeDeviceAlertParams{
+		AcknowledgedBy: by,
+		AckComment:     comment,
+		AcknowledgedAt: ptr(at.Unix()),
+		DeviceID:       deviceID,
+		ID:             alertID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return device.ErrRepoItemNotFound
+	}
+	return nil
+}
+
+func (d *DeviceRepository) ResolveDeviceAlert(ctx context.Context, deviceID string, alertID int64, at time.Time) error {
+	n, err := d.querier.ResolveDeviceAlert(ctx, sqlc.ResolveDeviceAlertParams{
+		ResolvedAt: ptr(at.Unix()),
+		DeviceID:   deviceID,
+		ID:         alertID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return device.ErrRepoItemNotFound
+	}
+	return nil
+}
+
+func (d *DeviceRepository) ResolveDeviceAlertsByCondition(
+	ctx context.Context,
+	deviceID string,
+	conditions []string,
+	at time.Time,
+) (int64, error) {
+	if len(conditions) == 0 {
+		return 0, nil
+	}
+	return d.querier.ResolveDeviceAlertsByCondition(ctx, sqlc.ResolveDeviceAlertsByConditionParams{
+		ResolvedAt: ptr(at.Unix()),
+		DeviceID:   deviceID,
+		Conditions: conditions,
+	})
+}
+
+func (d *DeviceRepository) GetAlertsAfterID(ctx context.Context, deviceID string, afterID int64, limit int) ([]device.Alert, error) {
+	params := sqlc.GetAlertsAfterIDParams{
+		AfterID: afterID,
+		Limit:   int64(limit),
+	}
+	if deviceID != "" {
+		params.DeviceID = &deviceID
+	}
+
+	rows, err := d.querier.GetAlertsAfterID(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]device.Alert, len(rows))
+	for i, row := range rows {
+		alerts[i] = alertFromRow(row)
+	}
+	return alerts, nil
+}
+
+func (d *DeviceRepository) CreateAlertRule(ctx context.Context, deviceID string, rule device.AlertRule) (int64, error) {
+	return d.querier.CreateAlertRule(ctx, sqlc.CreateAlertRuleParams{
+		DeviceID:      deviceID,
+		Metric:        rule.Metric,
+		Operator:      string(rule.Operator),
+		Threshold:     rule.Threshold,
+		ThresholdHigh: rule.ThresholdHigh,
+		Severity:      string(rule.Severity),
+		WindowSeconds: rule.WindowSeconds,
+	})
+}
+
+func (d *DeviceRepository) GetAlertRule(ctx context.Context, deviceID string, ruleID int64) (device.AlertRule, error) {
+	row, err := d.querier.GetAlertRule(ctx, sqlc.GetAlertRuleParams{
+		DeviceID: deviceID,
+		ID:       ruleID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return device.AlertRule{}, device.ErrRepoItemNotFound
+		}
+		return device.AlertRule{}, err
+	}
+	return alertRuleFromRow(row), nil
+}
+
+func (d *DeviceRepository) ListAlertRules(ctx context.Context, deviceID string) ([]device.AlertRule, error) {
+	rows, err := d.querier.ListAlertRules(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]device.AlertRule, len(rows))
+	for i, row := range rows {
+		rules[i] = alertRuleFromRow(row)
+	}
+	return rules, nil
+}
+
+func (d *DeviceRepository) UpdateAlertRule(ctx context.Context, deviceID string, rule device.AlertRule) error {
+	n, err := d.querier.UpdateAlertRule(ctx, sqlc.UpdateAlertRuleParams{
+		Metric:        rule.Metric,
+		Operator:      string(rule.Operator),
+		Threshold:     rule.Threshold,
+		ThresholdHigh: rule.ThresholdHigh,
+		Severity:      string(rule.Severity),
+		WindowSeconds: rule.WindowSeconds,
+		DeviceID:      deviceID,
+		ID:            rule.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return device.ErrRepoItemNotFound
+	}
+	return nil
+}
+
+func (d *DeviceRepository) DeleteAlertRule(ctx context.Context, deviceID string, ruleID int64) error {
+	n, err := d.querier.DeleteAlertRule(ctx, sqlc.DeleteAlertRuleParams{
+		DeviceID: deviceID,
+		ID:       ruleID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return device.ErrRepoItemNotFound
+	}
+	return nil
+}
+
+func (d *DeviceRepository) GetConditionStates(ctx context.Context, deviceID string) (map[string]device.ConditionState, error) {
+	rows, err := d.querier.GetConditionStates(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]device.ConditionState, len(rows))
+	for _, row := range rows {
+		states[row.Condition] = device.ConditionState{
+			Breaches: int32(row.Breaches),
+			Active:   row.Active,
+		}
+	}
+	return states, nil
+}
+
+func (d *DeviceRepository) SaveConditionStates(ctx context.Context, deviceID string, states map[string]device.ConditionState) error {
+	return d.withTx(ctx, func(querier sqlc.Querier) error {
+		for condition, state := range states {
+			err := querier.UpsertConditionState(ctx, sqlc.UpsertConditionStateParams{
+				DeviceID:  deviceID,
+				Condition: condition,
+				Breaches:  int64(state.Breaches),
+				Active:    state.Active,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *DeviceRepository) ListReportingDevices(ctx context.Context) ([]device.ReportingDevice, error) {
+	rows, err := d.querier.ListReportingDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]device.ReportingDevice, len(rows))
+	for i, row := range rows {
+		devices[i] = device.ReportingDevice{
+			DeviceID:          row.DeviceID,
+			ReportingInterval: time.Duration(row.ReportingIntervalSeconds) * time.Second,
+		}
+		if row.LastReported != 0 {
+			devices[i].LastReported = time.Unix(row.LastReported, 0).UTC()
+		}
+	}
+	return devices, nil
+}
+
+func (d *DeviceRepository) CreateDevice(ctx context.Context, dev device.Device) error {
+	labels, err := marshalLabels(dev.Labels)
+	if err != nil {
+		return err
+	}
+	n, err := d.querier.CreateDevice(ctx, sqlc.CreateDeviceParams{
+		ID:              dev.ID,
+		DisplayName:     dev.DisplayName,
+		Model:           dev.Model,
+		FirmwareVersion: dev.FirmwareVersion,
+		Location:        dev.Location,
+		Labels:          labels,
+		CreatedAt:       dev.CreatedAt.Unix(),
+		GroupID:         groupIDParam(dev.GroupID),
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return device.ErrRepoItemExists
+	}
+	return nil
+}
+
+func (d *DeviceRepository) GetDevice(ctx context.Context, deviceID string) (device.Device, error) {
+	row, err := d.querier.GetDevice(ctx, deviceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return device.Device{}, device.ErrRepoItemNotFound
+		}
+		return device.Device{}, err
+	}
+	return deviceFromRow(row)
+}
+
+func (d *DeviceRepository) UpdateDevice(ctx context.Context, dev device.Device) error {
+	labels, err := marshalLabels(dev.Labels)
+	if err != nil {
+		return err
+	}
+	n, err := d.querier.UpdateDevice(ctx, sqlc.UpdateDeviceParams{
+		DisplayName:     dev.DisplayName,
+		Model:           dev.Model,
+		FirmwareVersion: dev.FirmwareVersion,
+		Location:        dev.Location,
+		Labels:          labels,
+		GroupID:         groupIDParam(dev.GroupID),
+		ID:              dev.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return device.ErrRepoItemNotFound
+	}
+	return nil
+}
+
+func (d *DeviceRepository) ListDevices(
+	ctx context.Context,
+	selector device.LabelSelector,
+	pageOpts device.RepositoryPageOptions,
+) (device.RepositoryPage[device.Device], error) {
+	selectorJSON, err := marshalLabelSelector(selector)
+	if err != nil {
+		return device.RepositoryPage[device.Device]{}, err
+	}
+
+	params := sqlc.ListDevicesParams{
+		Selector: selectorJSON,
+		Limit:    int64(pageOpts.Size + 1),
+	}
+	if pageOpts.Token != nil {
+		params.LastSeq = pageOpts.Token.LastID
+		params.LastTs = ptr(pageOpts.Token.LastTime.Unix())
+	}
+
+	rows, err := d.querier.ListDevices(ctx, params)
+	if err != nil {
+		return device.RepositoryPage[device.Device]{}, err
+	}
+
+	var nextPageTkn *device.RepositoryPageToken
+	// check if another page exists
+	if len(rows) == int(params.Limit) {
+		rows = rows[:len(rows)-1] // remove peeked row
+		lastRow := rows[len(rows)-1]
+
+		nextPageTkn = &device.RepositoryPageToken{
+			LastID:   &lastRow.Seq,
+			LastTime: ptr(time.Unix(lastRow.CreatedAt, 0).UTC()),
+		}
+	}
+
+	devices := make([]device.Device, len(rows))
+	for i, row := range rows {
+		if devices[i], err = deviceFromRow(row); err != nil {
+			return device.RepositoryPage[device.Device]{}, err
+		}
+	}
+
+	return device.RepositoryPage[device.Device]{
+		Items:         devices,
+		NextPageToken: nextPageTkn,
+	}, nil
+}
+
+func (d *DeviceRepository) GetFleetSnapshot(
+	ctx context.Context,
+	selector device.LabelSelector,
+	groupID string,
+	pageOpts device.RepositoryPageOptions,
+) (device.RepositoryPage[device.DeviceSnapshot], error) {
+	selectorJSON, err := marshalLabelSelector(selector)
+	if err != nil {
+		return device.RepositoryPage[device.DeviceSnapshot]{}, err
+	}
+
+	params := sqlc.GetFleetSnapshotParams{
+		Selector: selectorJSON,
+		Limit:    int64(pageOpts.Size + 1),
+	}
+	if groupID != "" {
+		params.GroupID = &groupID
+	}
+	if pageOpts.Token != nil {
+		params.LastSeq = pageOpts.Token.LastID
+		params.LastTs = ptr(pageOpts.Token.LastTime.Unix())
+	}
+
+	rows, err := d.querier.GetFleetSnapshot(ctx, params)
+	if err != nil {
+		return device.RepositoryPage[device.DeviceSnapshot]{}, err
+	}
+
+	var nextPageTkn *device.RepositoryPageToken
+	// check if another page exists
+	if len(rows) == int(params.Limit) {
+		rows = rows[:len(rows)-1] // remove peeked row
+		lastRow := rows[len(rows)-1]
+
+		nextPageTkn = &device.RepositoryPageToken{
+			LastID:   &lastRow.Device.Seq,
+			LastTime: ptr(time.Unix(lastRow.Device.CreatedAt, 0).UTC()),
+		}
+	}
+
+	var metricIDs []int64
+	for _, row := range rows {
+		if row.LatestMetricID != nil {
+			metricIDs = append(metricIDs, *row.LatestMetricID)
+		}
+	}
+	values, err := d.getMetricValues(ctx, metricIDs)
+	if err != nil {
+		return device.RepositoryPage[device.DeviceSnapshot]{}, err
+	}
+
+	snapshots := make([]device.DeviceSnapshot, len(rows))
+	for i, row := range rows {
+		dev, err := deviceFromRow(&row.Device)
+		if err != nil {
+			return device.RepositoryPage[device.DeviceSnapshot]{}, err
+		}
+		snapshots[i] = device.DeviceSnapshot{
+			Device:     dev,
+			OpenAlerts: row.OpenAlerts,
+		}
+		if row.LatestMetricID != nil && row.LatestTs != nil {
+			snapshots[i].LatestMetric = &device.Metric{
+				Values: values[*row.LatestMetricID],
+				Time:   time.Unix(*row.LatestTs, 0).UTC(),
+			}
+		}
+	}
+
+	return device.RepositoryPage[device.DeviceSnapshot]{
+		Items:         snapshots,
+		NextPageToken: nextPageTkn,
+	}, nil
+}
+
+// marshalStrings marshals values into a JSON array, which is empty rather
+// than null if there are no values.
+func marshalStrings[T ~string](values []T) (string, error) {
+	if len(values) == 0 {
+		return "[]", nil
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("marshal strings: %w", err)
+	}
+	return string(b), nil
+}
+
+func marshalLabels(labels map[string]string) (string, error) {
+	if len(labels) == 0 {
+		return "{}", nil
+	}
+	b, err := json.Marshal(labels)
+	if err != nil {
+		return "", fmt.Errorf("marshal device labels: %w", err)
+	}
+	return string(b), nil
+}
+
+func deviceFromRow(row *sqlc.Device) (device.Device, error) {
+	dev := device.Device{
+		ID:              row.ID,
+		DisplayName:     row.DisplayName,
+		Model:           row.Model,
+		FirmwareVersion: row.FirmwareVersion,
+		Location:        row.Location,
+		CreatedAt:       time.Unix(row.CreatedAt, 0).UTC(),
+	}
+	if err := json.Unmarshal([]byte(row.Labels), &dev.Labels); err != nil {
+		return device.Device{}, fmt.Errorf("unmarshal device labels: %w", err)
+	}
+	if len(dev.Labels) == 0 {
+		dev.Labels = nil
+	}
+	if row.LastSeen != nil {
+		dev.LastSeen = ptr(time.Unix(*row.LastSeen, 0).UTC())
+	}
+	if row.GroupID != nil {
+		dev.GroupID = *row.GroupID
+	}
+	return dev, nil
+}
+
+// groupIDParam converts the group of a device to a nullable column value.
+func groupIDParam(groupID string) *string {
+	if groupID == "" {
+		return nil
+	}
+	return &groupID
+}
+
+func (d *DeviceRepository) CreateDeviceGroup(ctx context.Context, group device.DeviceGroup) error {
+	n, err := d.querier.CreateDeviceGroup(ctx, sqlc.CreateDeviceGroupParams{
+		ID:          group.ID,
+		DisplayName: group.DisplayName,
+		CreatedAt:   group.CreatedAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return device.ErrRepoItemExists
+	}
+	return nil
+}
+
+func (d *DeviceRepository) GetDeviceGroup(ctx context.Context, groupID string) (device.DeviceGroup, error) {
+	row, err := d.querier.GetDeviceGroup(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return device.DeviceGroup{}, device.ErrRepoItemNotFound
+		}
+		return device.DeviceGroup{}, err
+	}
+	return deviceGroupFromRow(row), nil
+}
+
+func (d *DeviceRepository) ListDeviceGroups(ctx context.Context) ([]device.DeviceGroup, error) {
+	rows, err := d.querier.ListDeviceGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]device.DeviceGroup, len(rows))
+	for i, row := range rows {
+		groups[i] = deviceGroupFromRow(row)
+	}
+	return groups, nil
+}
+
+func (d *DeviceRepository) DeleteDevice(ctx context.Context, deviceID string, op device.Operation) (device.Operation, error) {
+	err := d.withTx(ctx, func(querier sqlc.Querier) error {
+		n, err := querier.DeleteDevice(ctx, deviceID)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return device.ErrRepoItemNotFound
+		}
+		if err = querier.DeleteDeviceConfigSettings(ctx, deviceID); err != nil {
+			return err
+		}
+		if err = querier.DeleteDeviceConfigVersions(ctx, deviceID); err != nil {
+			return err
+		}
+		if err = querier.DeleteDeviceAlertRules(ctx, deviceID); err != nil {
+			return err
+		}
+		if err = querier.DeleteDeviceConditionStates(ctx, deviceID); err != nil {
+			return err
+		}
+		if err = querier.DeleteDeviceLatest(ctx, deviceID); err != nil {
+			return err
+		}
+		// data recorded once the device is registered again is newer than these
+		// and must be kept by the purge
+		if op.MaxMetricID, err = querier.GetDeviceMaxMetricID(ctx, deviceID); err != nil {
+			return err
+		}
+		if op.MaxAlertID, err = querier.GetDeviceMaxAlertID(ctx, deviceID); err != nil {
+			return err
+		}
+		op.ID, err = querier.CreateOperation(ctx, sqlc.CreateOperationParams{
+			Type:           string(op.Type),
+			DeviceID:       op.DeviceID,
+			State:          string(op.State),
+			DeletedMetrics: op.DeletedMetrics,
+			DeletedAlerts:  op.DeletedAlerts,
+			MaxMetricID:    op.MaxMetricID,
+			MaxAlertID:     op.MaxAlertID,
+			Error:          op.Error,
+			CreatedAt:      op.CreatedAt.Unix(),
+			UpdatedAt:      op.UpdatedAt.Unix(),
+		})
+		return err
+	})
+	if err != nil {
+		return device.Operation{}, err
+	}
+	return op, nil
+}
+
+func (d *DeviceRepository) DeleteDeviceMetrics(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error) {
+	return d.querier.DeleteDeviceMetricsBatch(ctx, sqlc.DeleteDeviceMetricsBatchParams{
+		DeviceID: deviceID,
+		MaxID:    maxID,
+		Limit:    int64(limit),
+	})
+}
+
+func (d *DeviceRepository) DeleteDeviceAlerts(ctx context.Context, deviceID string, maxID int64, limit int) (int64, error) {
+	return d.querier.DeleteDeviceAlertsBatch(ctx, sqlc.DeleteDeviceAlertsBatchParams{
+		DeviceID: deviceID,
+		MaxID:    maxID,
+		Limit:    int64(limit),
+	})
+}
+
+func (d *DeviceRepository) GetOperation(ctx context.Context, id int64) (device.Operation, error) {
+	row, err := d.querier.GetOperation(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return device.Operation{}, device.ErrRepoItemNotFound
+		}
+		return device.Operation{}, err
+	}
+	return operationFromRow(row), nil
+}
+
+func (d *DeviceRepository) UpdateOperation(ctx context.Context, op device.Operation) error {
+	n, err := d.querier.UpdateOperation(ctx, sqlc.UpdateOperationParams{
+		State:          string(op.State),
+		DeletedMetrics: op.DeletedMetrics,
+		DeletedAlerts:  op.DeletedAlerts,
+		Error:          op.Error,
+		UpdatedAt:      op.UpdatedAt.Unix(),
+		ID:             op.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return device.ErrRepoItemNotFound
+	}
+	return nil
+}
+
+func (d *DeviceRepository) ListOperations(ctx context.Context, state device.OperationState) ([]device.Operation, error) {
+	rows, err := d.querier.ListOperationsByState(ctx, string(state))
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]device.Operation, len(rows))
+	for i, row := range rows {
+		ops[i] = operationFromRow(row)
+	}
+	return ops, nil
+}
+
+func operationFromRow(row *sqlc.Operation) device.Operation {
+	return device.Operation{
+		ID:             row.ID,
+		Type:           device.OperationType(row.Type),
+		DeviceID:       row.DeviceID,
+		State:          device.OperationState(row.State),
+		DeletedMetrics: row.DeletedMetrics,
+		DeletedAlerts:  row.DeletedAlerts,
+		MaxMetricID:    row.MaxMetricID,
+		MaxAlertID:     row.MaxAlertID,
+		Error:          row.Error,
+		CreatedAt:      time.Unix(row.CreatedAt, 0).UTC(),
+		UpdatedAt:      time.Unix(row.UpdatedAt, 0).UTC(),
+	}
+}
+
+func deviceGroupFromRow(row *sqlc.DeviceGroup) device.DeviceGroup {
+	return device.DeviceGroup{
+		ID:          row.ID,
+		DisplayName: row.DisplayName,
+		CreatedAt:   time.Unix(row.CreatedAt, 0).UTC(),
+	}
+}
+
+func alertFromRow(row *sqlc.Alert) device.Alert {
+	alert := device.Alert{
+		ID:             row.ID,
+		DeviceID:       row.DeviceID,
+		Reason:         device.AlertReason(row.Reason),
+		Severity:       device.AlertSeverity(row.Severity),
+		Desc:           row.Desc,
+		Time:           time.Unix(row.Timestamp, 0).UTC(),
+		State:          device.AlertState(row.State),
+		Occurrences:    row.Occurrences,
+		LastSeen:       time.Unix(row.LastSeen, 0).UTC(),
+		ConfigVersion:  row.ConfigVersion,
+		Condition:      row.Condition,
+		AcknowledgedBy: row.AcknowledgedBy,
+		AckComment:     row.AckComment,
+	}
+	if row.RuleID != nil {
+		alert.RuleID = *row.RuleID
+	}
+	if row.AcknowledgedAt != nil {
+		alert.AcknowledgedAt = ptr(time.Unix(*row.AcknowledgedAt, 0).UTC())
+	}
+	if row.ResolvedAt != nil {
+		alert.ResolvedAt = ptr(time.Unix(*row.ResolvedAt, 0).UTC())
+	}
+	return alert
+}
+
+func alertRuleFromRow(row *sqlc.AlertRule) device.AlertRule {
+	return device.AlertRule{
+		ID:            row.ID,
+		Metric:        row.Metric,
+		Operator:      device.RuleOperator(row.Operator),
+		Threshold:     row.Threshold,
+		ThresholdHigh: row.ThresholdHigh,
+		Severity:      device.AlertSeverity(row.Severity),
+		WindowSeconds: row.WindowSeconds,
+	}
 }
 
 func ptr[T any](v T) *T {
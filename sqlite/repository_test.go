@@ -2,8 +2,11 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"slices"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 
 	"github.com/joshjon/iot-metrics/device"
 	"github.com/joshjon/iot-metrics/sqlite/migrations"
+	"github.com/joshjon/iot-metrics/sqlite/sqlc"
 )
 
 func TestDeviceRepository_UpsertGetDeviceConfig(t *testing.T) {
@@ -19,21 +23,267 @@ func TestDeviceRepository_UpsertGetDeviceConfig(t *testing.T) {
 	repo := newRepo(t, ctx)
 
 	deviceID := "foo"
-	cfg := device.Config{
-		TemperatureThreshold: 5.55,
-		BatteryThreshold:     5,
+	settings := device.ConfigSettings{
+		TemperatureThreshold: ptr(5.55),
+		BatteryThreshold:     ptr(int32(5)),
 	}
-	err := repo.UpsertDeviceConfig(ctx, deviceID, cfg)
+	version, err := repo.UpsertDeviceConfig(ctx, deviceID, device.DeviceConfigVersion{Settings: settings, CreatedAt: time.Now()}, nil)
 	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+
+	wantCfg := device.ResolveConfig(map[device.ConfigLevel]device.ConfigSettings{
+		device.ConfigLevelDevice: settings,
+	})
+	wantCfg.Version = version
 
 	gotCfg, err := repo.GetDeviceConfig(ctx, deviceID)
 	require.NoError(t, err)
-	require.Equal(t, cfg, gotCfg)
+	require.Equal(t, wantCfg, gotCfg)
+
+	settings.Expressions = []device.AlertExpression{
+		{Expr: "temperature > 40 && battery < 15", Severity: device.AlertSeverityCritical},
+		{Expr: `temperature - prev["temperature"] > 5`, Severity: device.AlertSeverityWarning},
+	}
+	settings.ConsecutiveBreaches = ptr(int32(3))
+	settings.Hysteresis = ptr(2.5)
+	settings.Cooldowns = []device.AlertCooldown{
+		{Reason: device.AlertReasonBatteryLow, Window: 15 * time.Minute},
+	}
+	settings.ReportingInterval = ptr(time.Minute)
+	version, err = repo.UpsertDeviceConfig(ctx, deviceID, device.DeviceConfigVersion{Settings: settings, CreatedAt: time.Now()}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+
+	wantCfg = device.ResolveConfig(map[device.ConfigLevel]device.ConfigSettings{
+		device.ConfigLevelDevice: settings,
+	})
+	wantCfg.Version = version
+
+	gotCfg, err = repo.GetDeviceConfig(ctx, deviceID)
+	require.NoError(t, err)
+	require.Equal(t, wantCfg, gotCfg)
+
+	// configuring a device registers it
+	_, err = repo.GetDevice(ctx, deviceID)
+	require.NoError(t, err)
 
 	_, err = repo.GetDeviceConfig(ctx, "not_exists")
 	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
 }
 
+func TestDeviceRepository_ConfigLevels(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	group := device.DeviceGroup{ID: "sensors", DisplayName: "Sensors", CreatedAt: now}
+	require.NoError(t, repo.CreateDeviceGroup(ctx, group))
+	err := repo.CreateDeviceGroup(ctx, group)
+	require.ErrorIs(t, err, device.ErrRepoItemExists)
+
+	gotGroup, err := repo.GetDeviceGroup(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, group, gotGroup)
+	_, err = repo.GetDeviceGroup(ctx, "not_exists")
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+
+	require.NoError(t, repo.CreateDeviceGroup(ctx, device.DeviceGroup{ID: "actuators", CreatedAt: now}))
+	groups, err := repo.ListDeviceGroups(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"actuators", "sensors"}, []string{groups[0].ID, groups[1].ID})
+
+	require.NoError(t, repo.CreateDevice(ctx, device.Device{ID: "foo", GroupID: group.ID, CreatedAt: now}))
+	require.NoError(t, repo.CreateDevice(ctx, device.Device{ID: "bar", CreatedAt: now}))
+	gotDevice, err := repo.GetDevice(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, group.ID, gotDevice.GroupID)
+
+	err = repo.CreateDevice(ctx, device.Device{ID: "baz", GroupID: "not_exists", CreatedAt: now})
+	require.Error(t, err)
+
+	global := device.ConfigSettings{
+		TemperatureThreshold: ptr(60.0),
+		BatteryThreshold:     ptr(int32(10)),
+		Hysteresis:           ptr(1.0),
+	}
+	groupSettings := device.ConfigSettings{
+		TemperatureThreshold: ptr(40.0),
+		Expressions:          []device.AlertExpression{{Expr: "humidity > 80", Severity: device.AlertSeverityInfo}},
+	}
+	deviceSettings := device.ConfigSettings{
+		TemperatureThreshold: ptr(35.0),
+		// an empty list overrides the expressions of the group
+		Expressions: []device.AlertExpression{},
+	}
+	require.NoError(t, repo.UpsertGlobalConfig(ctx, global))
+	require.NoError(t, repo.UpsertDeviceGroupConfig(ctx, group.ID, groupSettings))
+	_, err = repo.UpsertDeviceConfig(ctx, "foo", device.DeviceConfigVersion{Settings: deviceSettings, CreatedAt: now}, nil)
+	require.NoError(t, err)
+
+	got, err := repo.GetDeviceConfig(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, device.Config{
+		TemperatureThreshold: 35,
+		BatteryThreshold:     10,
+		Expressions:          []device.AlertExpression{},
+		Hysteresis:           1,
+		Sources: map[device.ConfigSetting]device.ConfigLevel{
+			device.SettingTemperatureThreshold: device.ConfigLevelDevice,
+			device.SettingBatteryThreshold:     device.ConfigLevelGlobal,
+			device.SettingExpressions:          device.ConfigLevelDevice,
+			device.SettingHysteresis:           device.ConfigLevelGlobal,
+		},
+		Version: 1,
+	}, got)
+
+	// devices outside a group inherit the global defaults
+	got, err = repo.GetDeviceConfig(ctx, "bar")
+	require.NoError(t, err)
+	assert.Equal(t, device.ResolveConfig(map[device.ConfigLevel]device.ConfigSettings{
+		device.ConfigLevelGlobal: global,
+	}), got)
+
+	// devices without device settings inherit the group
+	require.NoError(t, repo.UpdateDevice(ctx, device.Device{ID: "bar", GroupID: group.ID}))
+	got, err = repo.GetDeviceConfig(ctx, "bar")
+	require.NoError(t, err)
+	assert.Equal(t, device.ResolveConfig(map[device.ConfigLevel]device.ConfigSettings{
+		device.ConfigLevelGroup:  groupSettings,
+		device.ConfigLevelGlobal: global,
+	}), got)
+	assert.Equal(t, 40.0, got.TemperatureThreshold)
+	assert.Equal(t, device.ConfigLevelGroup, got.Sources[device.SettingTemperatureThreshold])
+}
+
+func TestDeviceRepository_DeviceConfigVersions(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	deviceID := "foo"
+	want := []device.DeviceConfigVersion{
+		{
+			Settings:  device.ConfigSettings{TemperatureThreshold: ptr(30.0)},
+			CreatedAt: now.Add(-2 * time.Hour),
+			Author:    "jane",
+			Reason:    "Initial thresholds",
+		},
+		{
+			Settings: device.ConfigSettings{
+				TemperatureThreshold: ptr(35.0),
+				Cooldowns:            []device.AlertCooldown{},
+			},
+			CreatedAt: now.Add(-time.Hour),
+		},
+		{
+			Settings:  device.ConfigSettings{BatteryThreshold: ptr(int32(10))},
+			CreatedAt: now,
+			Author:    "john",
+		},
+	}
+	for i := range want {
+		version, err := repo.UpsertDeviceConfig(ctx, deviceID, want[i], nil)
+		require.NoError(t, err)
+		want[i].Version = version
+	}
+	assert.Equal(t, []int64{1, 2, 3}, []int64{want[0].Version, want[1].Version, want[2].Version})
+
+	// versions are numbered per device
+	version, err := repo.UpsertDeviceConfig(ctx, "bar", device.DeviceConfigVersion{CreatedAt: now}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+
+	page, err := repo.ListDeviceConfigVersions(ctx, deviceID, device.RepositoryPageOptions{Size: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []device.DeviceConfigVersion{want[2], want[1]}, page.Items)
+	require.NotNil(t, page.NextPageToken)
+
+	page, err = repo.ListDeviceConfigVersions(ctx, deviceID, device.RepositoryPageOptions{
+		Size:  2,
+		Token: page.NextPageToken,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []device.DeviceConfigVersion{want[0]}, page.Items)
+	assert.Nil(t, page.NextPageToken)
+
+	got, err := repo.GetDeviceConfigVersion(ctx, deviceID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, want[1], got)
+
+	_, err = repo.GetDeviceConfigVersion(ctx, deviceID, 4)
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+
+	got, err = repo.GetLatestDeviceConfigVersion(ctx, deviceID)
+	require.NoError(t, err)
+	assert.Equal(t, want[2], got)
+
+	_, err = repo.GetLatestDeviceConfigVersion(ctx, "not_exists")
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+
+	// the current config is the latest version
+	cfg, err := repo.GetDeviceConfig(ctx, deviceID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), cfg.Version)
+	assert.False(t, cfg.IsSet(device.SettingTemperatureThreshold))
+	assert.Equal(t, int32(10), cfg.BatteryThreshold)
+
+	// alerts record the config version they were evaluated against
+	alertID, err := repo.SaveDeviceAlert(ctx, deviceID, device.Alert{
+		Reason:        device.AlertReasonBatteryLow,
+		Severity:      device.AlertSeverityWarning,
+		Time:          now,
+		LastSeen:      now,
+		State:         device.AlertStateOpen,
+		Occurrences:   1,
+		ConfigVersion: cfg.Version,
+	})
+	require.NoError(t, err)
+	alert, err := repo.GetDeviceAlert(ctx, deviceID, alertID)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Version, alert.ConfigVersion)
+}
+
+func TestDeviceRepository_UpsertDeviceConfig_expectedRevision(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	now := time.Now().UTC().Truncate(time.Second)
+	deviceID := "foo"
+
+	// revision 0 expects a device without a config
+	version, err := repo.UpsertDeviceConfig(ctx, deviceID, device.DeviceConfigVersion{
+		Settings:  device.ConfigSettings{TemperatureThreshold: ptr(30.0)},
+		CreatedAt: now,
+	}, ptr(int64(0)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+
+	for _, expected := range []int64{0, 2} {
+		_, err = repo.UpsertDeviceConfig(ctx, deviceID, device.DeviceConfigVersion{
+			Settings:  device.ConfigSettings{TemperatureThreshold: ptr(40.0)},
+			CreatedAt: now,
+		}, &expected)
+		require.ErrorIs(t, err, device.ErrRepoRevisionMismatch)
+	}
+
+	// nothing is written on a mismatch
+	cfg, err := repo.GetDeviceConfig(ctx, deviceID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), cfg.Version)
+	assert.Equal(t, 30.0, cfg.TemperatureThreshold)
+
+	version, err = repo.UpsertDeviceConfig(ctx, deviceID, device.DeviceConfigVersion{
+		Settings:  device.ConfigSettings{TemperatureThreshold: ptr(40.0)},
+		CreatedAt: now,
+	}, ptr(int64(1)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+
+	cfg, err = repo.GetDeviceConfig(ctx, deviceID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), cfg.Version)
+	assert.Equal(t, 40.0, cfg.TemperatureThreshold)
+}
+
 func TestDeviceRepository_SaveGetDeviceMetrics(t *testing.T) {
 	ctx := t.Context()
 	repo := newRepo(t, ctx)
@@ -47,9 +297,11 @@ func TestDeviceRepository_SaveGetDeviceMetrics(t *testing.T) {
 
 	for i := 0; i < count; i++ {
 		metric := device.Metric{
-			Temperature: float64(i),
-			Battery:     int32(i),
-			Time:        middle,
+			Values: []device.MetricValue{
+				{Name: device.MetricTemperature, Value: float64(i)},
+				{Name: "humidity", Value: float64(i) / 2, Unit: "%"},
+			},
+			Time: middle,
 		}
 		// first and last outside timeframe
 		switch i {
@@ -95,6 +347,155 @@ func TestDeviceRepository_SaveGetDeviceMetrics(t *testing.T) {
 	require.Nil(t, p2.NextPageToken) // no more pages
 }
 
+func TestDeviceRepository_GetDeviceMetrics_ascending(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	deviceID := "foo"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	saved := make([]device.Metric, 5)
+	for i := range saved {
+		saved[i] = device.Metric{
+			Values: []device.MetricValue{{Name: device.MetricTemperature, Value: float64(i)}},
+			// metrics at the same time are paged by id
+			Time: now.Add(time.Duration(i/2) * time.Second),
+		}
+		require.NoError(t, repo.SaveDeviceMetric(ctx, deviceID, saved[i]))
+	}
+
+	opts := device.RepositoryPageOptions{Size: 3, Order: device.SortOrderAsc}
+	p1, err := repo.GetDeviceMetrics(ctx, deviceID, device.Timeframe{}, opts)
+	require.NoError(t, err)
+	require.Equal(t, saved[:3], p1.Items)
+	require.NotNil(t, p1.NextPageToken)
+	require.Equal(t, device.SortOrderAsc, p1.NextPageToken.Order)
+
+	opts.Token = p1.NextPageToken
+	p2, err := repo.GetDeviceMetrics(ctx, deviceID, device.Timeframe{}, opts)
+	require.NoError(t, err)
+	require.Equal(t, saved[3:], p2.Items)
+	require.Nil(t, p2.NextPageToken)
+}
+
+func TestDeviceRepository_GetDeviceMetricAggregates(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	deviceID := "foo"
+
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	readings := []struct {
+		offset      time.Duration
+		temperature float64
+		humidity    *float64
+	}{
+		{offset: -time.Second, temperature: 100}, // before timeframe
+		{offset: 10 * time.Second, temperature: 3, humidity: ptr(40.0)},
+		{offset: 20 * time.Second, temperature: 1},
+		{offset: 50 * time.Second, temperature: 2},
+		// nothing in the second bucket
+		{offset: 2*time.Minute + 5*time.Second, temperature: 7, humidity: ptr(42.0)},
+		{offset: 3 * time.Minute, temperature: 100}, // after timeframe
+	}
+	for _, r := range readings {
+		metric := device.Metric{
+			Values: []device.MetricValue{{Name: device.MetricTemperature, Value: r.temperature}},
+			Time:   start.Add(r.offset),
+		}
+		if r.humidity != nil {
+			metric.Values = append(metric.Values, device.MetricValue{Name: "humidity", Value: *r.humidity})
+		}
+		require.NoError(t, repo.SaveDeviceMetric(ctx, deviceID, metric))
+	}
+	// other devices are not aggregated
+	require.NoError(t, repo.SaveDeviceMetric(ctx, "bar", device.Metric{
+		Values: []device.MetricValue{{Name: device.MetricTemperature, Value: 100}},
+		Time:   start.Add(10 * time.Second),
+	}))
+
+	got, err := repo.GetDeviceMetricAggregates(ctx, deviceID, start, start.Add(3*time.Minute-time.Second), time.Minute)
+	require.NoError(t, err)
+
+	want := []device.MetricAggregates{
+		{
+			Name: "humidity",
+			Buckets: []device.MetricBucket{
+				{Start: start, Count: 1, Min: ptr(40.0), Max: ptr(40.0), Avg: ptr(40.0), First: ptr(40.0), Last: ptr(40.0)},
+				{Start: start.Add(2 * time.Minute), Count: 1, Min: ptr(42.0), Max: ptr(42.0), Avg: ptr(42.0), First: ptr(42.0), Last: ptr(42.0)},
+			},
+		},
+		{
+			Name: device.MetricTemperature,
+			Buckets: []device.MetricBucket{
+				{Start: start, Count: 3, Min: ptr(1.0), Max: ptr(3.0), Avg: ptr(2.0), First: ptr(3.0), Last: ptr(2.0)},
+				{Start: start.Add(2 * time.Minute), Count: 1, Min: ptr(7.0), Max: ptr(7.0), Avg: ptr(7.0), First: ptr(7.0), Last: ptr(7.0)},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestDeviceRepository_GetMetricRange(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	deviceID := "foo"
+
+	end := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	start := end.Add(-10 * time.Minute)
+	readings := []struct {
+		offset      time.Duration
+		temperature float64
+	}{
+		{offset: -11 * time.Minute, temperature: 1}, // before start
+		{offset: -10 * time.Minute, temperature: 20},
+		{offset: -5 * time.Minute, temperature: 25},
+		{offset: -time.Minute, temperature: 22},
+		{offset: 0, temperature: 40}, // end is exclusive
+	}
+	for _, r := range readings {
+		require.NoError(t, repo.SaveDeviceMetric(ctx, deviceID, device.Metric{
+			Values: []device.MetricValue{{Name: device.MetricTemperature, Value: r.temperature}},
+			Time:   end.Add(r.offset),
+		}))
+	}
+	// other devices are not included
+	require.NoError(t, repo.SaveDeviceMetric(ctx, "bar", device.Metric{
+		Values: []device.MetricValue{{Name: device.MetricTemperature, Value: 100}},
+		Time:   end.Add(-time.Minute),
+	}))
+
+	got, err := repo.GetMetricRange(ctx, deviceID, device.MetricTemperature, start, end)
+	require.NoError(t, err)
+	assert.Equal(t, device.MetricRange{Low: 20, High: 25}, got)
+
+	_, err = repo.GetMetricRange(ctx, deviceID, "humidity", start, end)
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+}
+
+func TestDeviceRepository_SaveDeviceMetrics(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	deviceID := "foo"
+
+	now := time.Now().UTC().Truncate(time.Second)
+	saved := make([]device.Metric, 3)
+	for i := range saved {
+		saved[i] = device.Metric{
+			Values: []device.MetricValue{{Name: device.MetricBattery, Value: float64(i)}},
+			Time:   now.Add(time.Duration(i) * time.Second),
+		}
+	}
+
+	err := repo.SaveDeviceMetrics(ctx, deviceID, saved)
+	require.NoError(t, err)
+
+	page, err := repo.GetDeviceMetrics(ctx, deviceID, device.Timeframe{}, device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+
+	slices.Reverse(saved) // expect to descend by timestamp
+	require.Equal(t, saved, page.Items)
+	require.Nil(t, page.NextPageToken)
+}
+
 func TestDeviceRepository_SaveGetDeviceAlerts(t *testing.T) {
 	ctx := t.Context()
 	repo := newRepo(t, ctx)
@@ -108,9 +509,14 @@ func TestDeviceRepository_SaveGetDeviceAlerts(t *testing.T) {
 
 	for i := 0; i < count; i++ {
 		alert := device.Alert{
-			Reason: device.AlertReasonBatteryLow,
-			Desc:   "desc " + strconv.Itoa(i),
-			Time:   middle,
+			DeviceID:    deviceID,
+			RuleID:      int64(i % 2), // 0 is stored as null
+			Reason:      device.AlertReasonBatteryLow,
+			Severity:    device.AlertSeverityWarning,
+			Desc:        "desc " + strconv.Itoa(i),
+			Time:        middle,
+			State:       device.AlertStateOpen,
+			Occurrences: 1,
 		}
 		// first and last outside timeframe
 		switch i {
@@ -119,8 +525,10 @@ func TestDeviceRepository_SaveGetDeviceAlerts(t *testing.T) {
 		case count - 1:
 			alert.Time = end.Add(time.Second)
 		}
-		err := repo.SaveDeviceAlert(ctx, deviceID, alert)
+		alert.LastSeen = alert.Time
+		id, err := repo.SaveDeviceAlert(ctx, deviceID, alert)
 		require.NoError(t, err)
+		alert.ID = id
 		saved[i] = alert
 	}
 
@@ -129,7 +537,7 @@ func TestDeviceRepository_SaveGetDeviceAlerts(t *testing.T) {
 
 	size := 5
 	timeframe := device.Timeframe{Start: &start, End: &end}
-	p1, err := repo.GetDeviceAlerts(ctx, deviceID, timeframe, device.RepositoryPageOptions{Size: size})
+	p1, err := repo.GetDeviceAlerts(ctx, deviceID, timeframe, device.AlertFilter{}, device.RepositoryPageOptions{Size: size})
 	require.NoError(t, err)
 	require.Len(t, p1.Items, size)
 
@@ -143,7 +551,7 @@ func TestDeviceRepository_SaveGetDeviceAlerts(t *testing.T) {
 	require.Positive(t, *p1.NextPageToken.LastID)
 	require.Equal(t, wantP1Items[len(wantP1Items)-1].Time, *p1.NextPageToken.LastTime)
 
-	p2, err := repo.GetDeviceAlerts(ctx, deviceID, timeframe, device.RepositoryPageOptions{
+	p2, err := repo.GetDeviceAlerts(ctx, deviceID, timeframe, device.AlertFilter{}, device.RepositoryPageOptions{
 		Size:  size,
 		Token: p1.NextPageToken,
 	})
@@ -156,6 +564,786 @@ func TestDeviceRepository_SaveGetDeviceAlerts(t *testing.T) {
 	require.Nil(t, p2.NextPageToken) // no more pages
 }
 
+func TestDeviceRepository_GetDeviceAlerts_ascendingReasons(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	deviceID := "foo"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	reasons := []device.AlertReason{
+		device.AlertReasonTemperatureHigh,
+		device.AlertReasonBatteryLow,
+		device.AlertReasonTemperatureHigh,
+		device.AlertReasonDeviceOffline,
+		device.AlertReasonTemperatureHigh,
+		device.AlertReasonDeviceOffline,
+	}
+	saved := make([]device.Alert, len(reasons))
+	for i, reason := range reasons {
+		alert := device.Alert{
+			DeviceID:    deviceID,
+			Reason:      reason,
+			Severity:    device.AlertSeverityWarning,
+			Desc:        "desc " + strconv.Itoa(i),
+			Time:        now.Add(time.Duration(i/2) * time.Second),
+			State:       device.AlertStateOpen,
+			Occurrences: 1,
+		}
+		alert.LastSeen = alert.Time
+		id, err := repo.SaveDeviceAlert(ctx, deviceID, alert)
+		require.NoError(t, err)
+		alert.ID = id
+		saved[i] = alert
+	}
+
+	filter := device.AlertFilter{Reasons: []device.AlertReason{device.AlertReasonTemperatureHigh, device.AlertReasonDeviceOffline}}
+	opts := device.RepositoryPageOptions{Size: 3, Order: device.SortOrderAsc}
+	p1, err := repo.GetDeviceAlerts(ctx, deviceID, device.Timeframe{}, filter, opts)
+	require.NoError(t, err)
+	require.Equal(t, []device.Alert{saved[0], saved[2], saved[3]}, p1.Items)
+	require.NotNil(t, p1.NextPageToken)
+	require.Equal(t, device.SortOrderAsc, p1.NextPageToken.Order)
+
+	opts.Token = p1.NextPageToken
+	p2, err := repo.GetDeviceAlerts(ctx, deviceID, device.Timeframe{}, filter, opts)
+	require.NoError(t, err)
+	require.Equal(t, []device.Alert{saved[4], saved[5]}, p2.Items)
+	require.Nil(t, p2.NextPageToken)
+
+	// descending
+	page, err := repo.GetDeviceAlerts(ctx, deviceID, device.Timeframe{}, filter, device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	require.Equal(t, []device.Alert{saved[5], saved[4], saved[3], saved[2], saved[0]}, page.Items)
+}
+
+func TestDeviceRepository_SearchAlerts(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, repo.CreateDevice(ctx, device.Device{ID: "foo", Labels: map[string]string{"env": "prod"}, CreatedAt: now}))
+	require.NoError(t, repo.CreateDevice(ctx, device.Device{ID: "bar", Labels: map[string]string{"env": "prod"}, CreatedAt: now}))
+	require.NoError(t, repo.CreateDevice(ctx, device.Device{ID: "baz", Labels: map[string]string{"env": "staging"}, CreatedAt: now}))
+
+	alerts := []device.Alert{
+		{DeviceID: "foo", Reason: device.AlertReasonTemperatureHigh, Severity: device.AlertSeverityWarning, Time: now.Add(-3 * time.Minute)},
+		{DeviceID: "bar", Reason: device.AlertReasonBatteryLow, Severity: device.AlertSeverityCritical, Time: now.Add(-2 * time.Minute)},
+		{DeviceID: "baz", Reason: device.AlertReasonTemperatureHigh, Severity: device.AlertSeverityCritical, Time: now.Add(-2 * time.Minute)},
+		{DeviceID: "foo", Reason: device.AlertReasonDeviceOffline, Severity: device.AlertSeverityCritical, Time: now.Add(-time.Minute)},
+		{DeviceID: "bar", Reason: device.AlertReasonTemperatureHigh, Severity: device.AlertSeverityWarning, Time: now},
+	}
+	for i := range alerts {
+		alerts[i].Desc = "desc " + strconv.Itoa(i)
+		alerts[i].State = device.AlertStateOpen
+		alerts[i].Occurrences = 1
+		alerts[i].LastSeen = alerts[i].Time
+		id, err := repo.SaveDeviceAlert(ctx, alerts[i].DeviceID, alerts[i])
+		require.NoError(t, err)
+		alerts[i].ID = id
+	}
+
+	search := func(deviceIDs []string, selector device.LabelSelector, timeframe device.Timeframe, filter device.AlertFilter) []device.Alert {
+		page, err := repo.SearchAlerts(ctx, deviceIDs, selector, timeframe, filter, device.RepositoryPageOptions{Size: 10})
+		require.NoError(t, err)
+		require.Nil(t, page.NextPageToken)
+		return page.Items
+	}
+
+	// all devices, most recent first
+	require.Equal(t, []device.Alert{alerts[4], alerts[3], alerts[2], alerts[1], alerts[0]}, search(nil, nil, device.Timeframe{}, device.AlertFilter{}))
+
+	got := search([]string{"foo", "baz"}, nil, device.Timeframe{}, device.AlertFilter{})
+	require.Equal(t, []device.Alert{alerts[3], alerts[2], alerts[0]}, got)
+
+	prod := device.LabelSelector{{Key: "env", Operator: device.LabelOperatorEquals, Value: "prod"}}
+	got = search(nil, prod, device.Timeframe{}, device.AlertFilter{})
+	require.Equal(t, []device.Alert{alerts[4], alerts[3], alerts[1], alerts[0]}, got)
+
+	filter := device.AlertFilter{Reasons: []device.AlertReason{device.AlertReasonTemperatureHigh, device.AlertReasonDeviceOffline}}
+	got = search(nil, nil, device.Timeframe{}, filter)
+	require.Equal(t, []device.Alert{alerts[4], alerts[3], alerts[2], alerts[0]}, got)
+
+	filter = device.AlertFilter{Severity: device.AlertSeverityCritical}
+	timeframe := device.Timeframe{Start: ptr(now.Add(-2 * time.Minute)), End: ptr(now.Add(-time.Minute))}
+	got = search(nil, prod, timeframe, filter)
+	require.Equal(t, []device.Alert{alerts[3], alerts[1]}, got)
+
+	// paginate across devices with alerts at the same time
+	p1, err := repo.SearchAlerts(ctx, nil, nil, device.Timeframe{}, device.AlertFilter{}, device.RepositoryPageOptions{Size: 3})
+	require.NoError(t, err)
+	require.Equal(t, []device.Alert{alerts[4], alerts[3], alerts[2]}, p1.Items)
+	require.NotNil(t, p1.NextPageToken)
+
+	p2, err := repo.SearchAlerts(ctx, nil, nil, device.Timeframe{}, device.AlertFilter{}, device.RepositoryPageOptions{
+		Size:  3,
+		Token: p1.NextPageToken,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []device.Alert{alerts[1], alerts[0]}, p2.Items)
+	require.Nil(t, p2.NextPageToken)
+
+	// paginate across a list of devices
+	deviceIDs := []string{"foo", "bar"}
+	p1, err = repo.SearchAlerts(ctx, deviceIDs, nil, device.Timeframe{}, device.AlertFilter{}, device.RepositoryPageOptions{Size: 3})
+	require.NoError(t, err)
+	require.Equal(t, []device.Alert{alerts[4], alerts[3], alerts[1]}, p1.Items)
+	require.NotNil(t, p1.NextPageToken)
+
+	p2, err = repo.SearchAlerts(ctx, deviceIDs, nil, device.Timeframe{}, device.AlertFilter{}, device.RepositoryPageOptions{
+		Size:  3,
+		Token: p1.NextPageToken,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []device.Alert{alerts[0]}, p2.Items)
+	require.Nil(t, p2.NextPageToken)
+}
+
+func TestDeviceRepository_SearchAlerts_queryPlan(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	db := &planDB{DBTX: repo.db}
+	repo.querier = sqlc.New(db)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	timeframe := device.Timeframe{Start: ptr(now.Add(-time.Hour)), End: ptr(now)}
+	prod := device.LabelSelector{{Key: "env", Operator: device.LabelOperatorEquals, Value: "prod"}}
+	filter := device.AlertFilter{Reasons: []device.AlertReason{device.AlertReasonTemperatureHigh}}
+	pageOpts := device.RepositoryPageOptions{
+		Size:  10,
+		Token: &device.RepositoryPageToken{LastID: ptr[int64](5), LastTime: ptr(now.Add(-time.Minute))},
+	}
+
+	tests := []struct {
+		name      string
+		deviceIDs []string
+		timeframe device.Timeframe
+		want      string
+	}{
+		{
+			name:      "devices",
+			deviceIDs: []string{"foo", "bar"},
+			timeframe: timeframe,
+			want:      "SEARCH a USING INDEX alerts_device_id_timestamp_idx (device_id=? AND timestamp>? AND timestamp<?)",
+		},
+		{
+			name:      "devices without timeframe",
+			deviceIDs: []string{"foo"},
+			want:      "SEARCH a USING INDEX alerts_device_id_timestamp_idx (device_id=? AND timestamp>? AND timestamp<?)",
+		},
+		{
+			name:      "all devices",
+			timeframe: timeframe,
+			want:      "SEARCH a USING INDEX alerts_timestamp_idx (timestamp>? AND timestamp<?)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db.plans = nil
+			_, err := repo.SearchAlerts(ctx, tt.deviceIDs, prod, tt.timeframe, filter, pageOpts)
+			require.NoError(t, err)
+			require.Len(t, db.plans, 1)
+			assert.Contains(t, db.plans[0], tt.want)
+			assert.NotContains(t, db.plans[0], "SCAN a")
+		})
+	}
+}
+
+// planDB records the query plan of each query run through it.
+type planDB struct {
+	sqlc.DBTX
+	plans []string
+}
+
+func (db *planDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := db.DBTX.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var details []string
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err = rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			return nil, errors.Join(err, rows.Close())
+		}
+		details = append(details, detail)
+	}
+	if err = errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, err
+	}
+	db.plans = append(db.plans, strings.Join(details, "\n"))
+	return db.DBTX.QueryContext(ctx, query, args...)
+}
+
+func TestDeviceRepository_GetAlertStats(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(2*time.Hour - time.Second)
+
+	alerts := []struct {
+		deviceID string
+		reason   device.AlertReason
+		time     time.Time
+	}{
+		{"foo", device.AlertReasonTemperatureHigh, start},
+		{"foo", device.AlertReasonTemperatureHigh, start.Add(30 * time.Minute)},
+		{"foo", device.AlertReasonBatteryLow, start.Add(90 * time.Minute)},
+		{"bar", device.AlertReasonTemperatureHigh, start.Add(time.Hour)},
+		{"baz", device.AlertReasonDeviceOffline, start.Add(time.Hour)},
+		{"baz", device.AlertReasonDeviceOffline, end},
+		// outside timeframe
+		{"bar", device.AlertReasonBatteryLow, start.Add(-time.Second)},
+		{"qux", device.AlertReasonBatteryLow, end.Add(time.Second)},
+	}
+	for i, a := range alerts {
+		_, err := repo.SaveDeviceAlert(ctx, a.deviceID, device.Alert{
+			Reason:      a.reason,
+			Severity:    device.AlertSeverityWarning,
+			Desc:        "desc " + strconv.Itoa(i),
+			Time:        a.time,
+			LastSeen:    a.time,
+			State:       device.AlertStateOpen,
+			Occurrences: 1,
+		})
+		require.NoError(t, err)
+	}
+
+	got, err := repo.GetAlertStats(ctx, start, end, time.Hour, 2)
+	require.NoError(t, err)
+
+	want := device.AlertStats{
+		Total: 6,
+		ByReason: []device.ReasonAlertCount{
+			{Reason: device.AlertReasonBatteryLow, Count: 1},
+			{Reason: device.AlertReasonDeviceOffline, Count: 2},
+			{Reason: device.AlertReasonTemperatureHigh, Count: 3},
+		},
+		ByDevice: []device.DeviceAlertCount{
+			{DeviceID: "bar", Count: 1},
+			{DeviceID: "baz", Count: 2},
+			{DeviceID: "foo", Count: 3},
+		},
+		Buckets: []device.AlertCountBucket{
+			{Start: start, Count: 2},
+			{Start: start.Add(time.Hour), Count: 4},
+		},
+		TopDevices: []device.DeviceAlertCount{
+			{DeviceID: "foo", Count: 3},
+			{DeviceID: "baz", Count: 2},
+		},
+	}
+	require.Equal(t, want, got)
+
+	got, err = repo.GetAlertStats(ctx, end.Add(time.Hour), end.Add(2*time.Hour), time.Hour, 2)
+	require.NoError(t, err)
+	require.Equal(t, device.AlertStats{}, got)
+}
+
+func TestDeviceRepository_AlertLifecycle(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	deviceID := "foo"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	conditions := []string{"config:temperature", "rule:1", "config:temperature"}
+	saved := make([]device.Alert, len(conditions))
+	for i, condition := range conditions {
+		alert := device.Alert{
+			DeviceID:    deviceID,
+			Reason:      device.AlertReasonThresholdBreached,
+			Severity:    device.AlertSeverityWarning,
+			Desc:        "desc " + strconv.Itoa(i),
+			Time:        now.Add(time.Duration(i) * time.Second),
+			State:       device.AlertStateOpen,
+			Condition:   condition,
+			Occurrences: 1,
+		}
+		alert.LastSeen = alert.Time
+		id, err := repo.SaveDeviceAlert(ctx, deviceID, alert)
+		require.NoError(t, err)
+		alert.ID = id
+		saved[i] = alert
+	}
+
+	got, err := repo.GetDeviceAlert(ctx, deviceID, saved[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, saved[0], got)
+
+	_, err = repo.GetDeviceAlert(ctx, "bar", saved[0].ID)
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+
+	// acknowledge
+	ackAt := now.Add(time.Minute)
+	err = repo.AcknowledgeDeviceAlert(ctx, deviceID, saved[1].ID, "jane", "replacing sensor", ackAt)
+	require.NoError(t, err)
+	err = repo.AcknowledgeDeviceAlert(ctx, deviceID, saved[1].ID, "john", "", ackAt)
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound, "only open alerts can be acknowledged")
+
+	saved[1].State = device.AlertStateAcknowledged
+	saved[1].AcknowledgedBy = "jane"
+	saved[1].AckComment = "replacing sensor"
+	saved[1].AcknowledgedAt = &ackAt
+
+	filter := device.AlertFilter{State: device.AlertStateAcknowledged}
+	page, err := repo.GetDeviceAlerts(ctx, deviceID, device.Timeframe{}, filter, device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	require.Equal(t, []device.Alert{saved[1]}, page.Items)
+
+	// resolve by condition
+	resolveAt := now.Add(2 * time.Minute)
+	n, err := repo.ResolveDeviceAlertsByCondition(ctx, deviceID, []string{"config:temperature", "rule:2"}, resolveAt)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+
+	filter = device.AlertFilter{State: device.AlertStateResolved}
+	page, err = repo.GetDeviceAlerts(ctx, deviceID, device.Timeframe{}, filter, device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	for _, alert := range page.Items {
+		require.Equal(t, "config:temperature", alert.Condition)
+		require.Equal(t, resolveAt, *alert.ResolvedAt)
+	}
+
+	// resolve by id
+	err = repo.ResolveDeviceAlert(ctx, deviceID, saved[1].ID, resolveAt)
+	require.NoError(t, err)
+	err = repo.ResolveDeviceAlert(ctx, deviceID, saved[1].ID, resolveAt)
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound, "resolved alerts cannot be resolved again")
+
+	got, err = repo.GetDeviceAlert(ctx, deviceID, saved[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, device.AlertStateResolved, got.State)
+	require.Equal(t, "jane", got.AcknowledgedBy)
+
+	n, err = repo.ResolveDeviceAlertsByCondition(ctx, deviceID, []string{"config:temperature"}, resolveAt)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestDeviceRepository_AlertOccurrences(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	deviceID := "foo"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	_, err := repo.GetLatestConditionAlert(ctx, deviceID, "config:battery")
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+
+	var saved []device.Alert
+	for i, condition := range []string{"config:battery", "config:battery", "config:temperature"} {
+		alert := device.Alert{
+			DeviceID:    deviceID,
+			Reason:      device.AlertReasonBatteryLow,
+			Severity:    device.AlertSeverityWarning,
+			Time:        now.Add(time.Duration(i) * time.Second),
+			State:       device.AlertStateOpen,
+			Condition:   condition,
+			Occurrences: 1,
+		}
+		alert.LastSeen = alert.Time
+		id, err := repo.SaveDeviceAlert(ctx, deviceID, alert)
+		require.NoError(t, err)
+		alert.ID = id
+		saved = append(saved, alert)
+	}
+
+	got, err := repo.GetLatestConditionAlert(ctx, deviceID, "config:battery")
+	require.NoError(t, err)
+	require.Equal(t, saved[1], got)
+
+	lastSeen := now.Add(time.Minute)
+	err = repo.RecordAlertOccurrence(ctx, deviceID, saved[1].ID, lastSeen)
+	require.NoError(t, err)
+	// an out of order reading does not move last seen back
+	err = repo.RecordAlertOccurrence(ctx, deviceID, saved[1].ID, now)
+	require.NoError(t, err)
+
+	got, err = repo.GetDeviceAlert(ctx, deviceID, saved[1].ID)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, got.Occurrences)
+	require.Equal(t, saved[1].Time, got.Time)
+	require.Equal(t, lastSeen, got.LastSeen)
+
+	err = repo.RecordAlertOccurrence(ctx, "bar", saved[1].ID, lastSeen)
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+}
+
+func TestDeviceRepository_GetAlertsAfterID(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	var saved []device.Alert
+	for i := 0; i < 6; i++ {
+		alert := device.Alert{
+			DeviceID: "device-" + strconv.Itoa(i%2),
+			Reason:   device.AlertReasonTemperatureHigh,
+			Desc:     "desc " + strconv.Itoa(i),
+			Time:     now,
+			LastSeen: now,
+		}
+		id, err := repo.SaveDeviceAlert(ctx, alert.DeviceID, alert)
+		require.NoError(t, err)
+		alert.ID = id
+		saved = append(saved, alert)
+	}
+
+	got, err := repo.GetAlertsAfterID(ctx, "", saved[1].ID, 3)
+	require.NoError(t, err)
+	require.Equal(t, saved[2:5], got)
+
+	got, err = repo.GetAlertsAfterID(ctx, "device-1", saved[1].ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, []device.Alert{saved[3], saved[5]}, got)
+}
+
+func TestDeviceRepository_AlertRules(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	deviceID := "foo"
+
+	rules := []device.AlertRule{
+		{Metric: "humidity", Operator: device.RuleOperatorOutsideRange, Threshold: 20, ThresholdHigh: 80, Severity: device.AlertSeverityCritical},
+		{Metric: device.MetricTemperature, Operator: device.RuleOperatorGreaterThan, Threshold: 30.5, Severity: device.AlertSeverityWarning},
+	}
+	for i := range rules {
+		id, err := repo.CreateAlertRule(ctx, deviceID, rules[i])
+		require.NoError(t, err)
+		rules[i].ID = id
+	}
+	_, err := repo.CreateAlertRule(ctx, "bar", rules[0])
+	require.NoError(t, err)
+
+	got, err := repo.ListAlertRules(ctx, deviceID)
+	require.NoError(t, err)
+	require.Equal(t, rules, got)
+
+	gotRule, err := repo.GetAlertRule(ctx, deviceID, rules[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, rules[0], gotRule)
+
+	_, err = repo.GetAlertRule(ctx, "bar", rules[1].ID)
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+
+	rules[1].Operator = device.RuleOperatorRise
+	rules[1].Threshold = 5
+	rules[1].WindowSeconds = 600
+	err = repo.UpdateAlertRule(ctx, deviceID, rules[1])
+	require.NoError(t, err)
+
+	gotRule, err = repo.GetAlertRule(ctx, deviceID, rules[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, rules[1], gotRule)
+
+	err = repo.UpdateAlertRule(ctx, "bar", rules[1])
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+
+	err = repo.DeleteAlertRule(ctx, deviceID, rules[0].ID)
+	require.NoError(t, err)
+
+	err = repo.DeleteAlertRule(ctx, deviceID, rules[0].ID)
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+
+	got, err = repo.ListAlertRules(ctx, deviceID)
+	require.NoError(t, err)
+	require.Equal(t, rules[1:], got)
+}
+
+func TestDeviceRepository_ConditionStates(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	deviceID := "foo"
+
+	got, err := repo.GetConditionStates(ctx, deviceID)
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	states := map[string]device.ConditionState{
+		"config:temperature": {Breaches: 2},
+		"rule:1":             {Breaches: 3, Active: true},
+	}
+	err = repo.SaveConditionStates(ctx, deviceID, states)
+	require.NoError(t, err)
+	err = repo.SaveConditionStates(ctx, "bar", map[string]device.ConditionState{"rule:2": {Breaches: 1}})
+	require.NoError(t, err)
+
+	got, err = repo.GetConditionStates(ctx, deviceID)
+	require.NoError(t, err)
+	require.Equal(t, states, got)
+
+	states["config:temperature"] = device.ConditionState{Breaches: 3, Active: true}
+	states["rule:1"] = device.ConditionState{}
+	err = repo.SaveConditionStates(ctx, deviceID, states)
+	require.NoError(t, err)
+
+	got, err = repo.GetConditionStates(ctx, deviceID)
+	require.NoError(t, err)
+	require.Equal(t, states, got)
+}
+
+func TestDeviceRepository_ListReportingDevices(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for deviceID, interval := range map[string]time.Duration{"foo": time.Minute, "bar": time.Hour, "baz": 0} {
+		_, err := repo.UpsertDeviceConfig(ctx, deviceID, device.DeviceConfigVersion{
+			Settings:  device.ConfigSettings{ReportingInterval: ptr(interval)},
+			CreatedAt: now,
+		}, nil)
+		require.NoError(t, err)
+	}
+	// devices inherit the reporting interval of their group
+	require.NoError(t, repo.CreateDeviceGroup(ctx, device.DeviceGroup{ID: "sensors", CreatedAt: now}))
+	require.NoError(t, repo.UpsertDeviceGroupConfig(ctx, "sensors", device.ConfigSettings{ReportingInterval: ptr(2 * time.Minute)}))
+	require.NoError(t, repo.CreateDevice(ctx, device.Device{ID: "qux", GroupID: "sensors", CreatedAt: now}))
+	for _, m := range []device.Metric{
+		{Time: now.Add(-time.Minute), Values: []device.MetricValue{{Name: device.MetricTemperature, Value: 20}}},
+		{Time: now, Values: []device.MetricValue{{Name: device.MetricTemperature, Value: 21}}},
+	} {
+		require.NoError(t, repo.SaveDeviceMetric(ctx, "foo", m))
+	}
+	require.NoError(t, repo.SaveDeviceMetric(ctx, "baz", device.Metric{Time: now}))
+
+	got, err := repo.ListReportingDevices(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []device.ReportingDevice{
+		{DeviceID: "bar", ReportingInterval: time.Hour},
+		{DeviceID: "foo", ReportingInterval: time.Minute, LastReported: now},
+		{DeviceID: "qux", ReportingInterval: 2 * time.Minute},
+	}, got)
+}
+
+func TestDeviceRepository_Devices(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	devices := []device.Device{
+		{ID: "foo", DisplayName: "Foo", Model: "TH-200", Labels: map[string]string{"env": "prod", "site": "north"}, CreatedAt: now.Add(-2 * time.Minute)},
+		{ID: "bar", FirmwareVersion: "1.0.0", Labels: map[string]string{"env": "prod"}, CreatedAt: now.Add(-time.Minute)},
+		{ID: "baz", Location: "warehouse", Labels: map[string]string{"env": "staging", "site": "north"}, CreatedAt: now.Add(-time.Minute)},
+		{ID: "qux", CreatedAt: now},
+	}
+	for _, dev := range devices {
+		require.NoError(t, repo.CreateDevice(ctx, dev))
+	}
+	err := repo.CreateDevice(ctx, device.Device{ID: "foo", CreatedAt: now})
+	require.ErrorIs(t, err, device.ErrRepoItemExists)
+
+	got, err := repo.GetDevice(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, devices[0], got)
+
+	_, err = repo.GetDevice(ctx, "unknown")
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+
+	// update
+	devices[0].DisplayName = "Foo sensor"
+	devices[0].Labels = map[string]string{"env": "staging"}
+	require.NoError(t, repo.UpdateDevice(ctx, devices[0]))
+	got, err = repo.GetDevice(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, devices[0], got)
+
+	err = repo.UpdateDevice(ctx, device.Device{ID: "unknown"})
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+
+	// last seen tracks the latest metric and registers unknown devices
+	require.NoError(t, repo.SaveDeviceMetric(ctx, "bar", device.Metric{Time: now}))
+	require.NoError(t, repo.SaveDeviceMetric(ctx, "bar", device.Metric{Time: now.Add(-time.Hour)}))
+	got, err = repo.GetDevice(ctx, "bar")
+	require.NoError(t, err)
+	require.NotNil(t, got.LastSeen)
+	assert.Equal(t, now, *got.LastSeen)
+
+	require.NoError(t, repo.SaveDeviceMetric(ctx, "new", device.Metric{Time: now.Add(-time.Hour)}))
+	got, err = repo.GetDevice(ctx, "new")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-time.Hour), got.CreatedAt)
+	require.NotNil(t, got.LastSeen)
+	assert.Equal(t, now.Add(-time.Hour), *got.LastSeen)
+
+	tests := []struct {
+		name     string
+		selector device.LabelSelector
+		wantIDs  []string
+	}{
+		{
+			name:    "no selector",
+			wantIDs: []string{"qux", "baz", "bar", "foo", "new"},
+		},
+		{
+			name:     "equals",
+			selector: device.LabelSelector{{Key: "env", Operator: device.LabelOperatorEquals, Value: "staging"}},
+			wantIDs:  []string{"baz", "foo"},
+		},
+		{
+			name:     "not equals",
+			selector: device.LabelSelector{{Key: "env", Operator: device.LabelOperatorNotEquals, Value: "staging"}},
+			wantIDs:  []string{"qux", "bar", "new"},
+		},
+		{
+			name:     "exists",
+			selector: device.LabelSelector{{Key: "site", Operator: device.LabelOperatorExists}},
+			wantIDs:  []string{"baz"},
+		},
+		{
+			name:     "not exists",
+			selector: device.LabelSelector{{Key: "env", Operator: device.LabelOperatorNotExists}},
+			wantIDs:  []string{"qux", "new"},
+		},
+		{
+			name: "multiple requirements",
+			selector: device.LabelSelector{
+				{Key: "env", Operator: device.LabelOperatorExists},
+				{Key: "site", Operator: device.LabelOperatorNotEquals, Value: "north"},
+			},
+			wantIDs: []string{"bar", "foo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// page one device at a time to exercise the cursor
+			var gotIDs []string
+			pageOpts := device.RepositoryPageOptions{Size: 1}
+			for {
+				page, err := repo.ListDevices(ctx, tt.selector, pageOpts)
+				require.NoError(t, err)
+				for _, dev := range page.Items {
+					gotIDs = append(gotIDs, dev.ID)
+				}
+				if page.NextPageToken == nil {
+					break
+				}
+				pageOpts.Token = page.NextPageToken
+			}
+			assert.Equal(t, tt.wantIDs, gotIDs)
+		})
+	}
+}
+
+func TestDeviceRepository_GetFleetSnapshot_acknowledgedAlerts(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, repo.CreateDevice(ctx, device.Device{ID: "foo", CreatedAt: now}))
+	var ids []int64
+	for range 2 {
+		id, err := repo.SaveDeviceAlert(ctx, "foo", device.Alert{
+			Reason:      device.AlertReasonTemperatureHigh,
+			Severity:    device.AlertSeverityWarning,
+			Time:        now,
+			LastSeen:    now,
+			State:       device.AlertStateOpen,
+			Occurrences: 1,
+		})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	openAlerts := func() int64 {
+		page, err := repo.GetFleetSnapshot(ctx, nil, "", device.RepositoryPageOptions{Size: 1})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		return page.Items[0].OpenAlerts
+	}
+
+	// acknowledged alerts are still unresolved
+	require.NoError(t, repo.AcknowledgeDeviceAlert(ctx, "foo", ids[0], "jane", "", now))
+	assert.Equal(t, int64(2), openAlerts())
+
+	require.NoError(t, repo.ResolveDeviceAlert(ctx, "foo", ids[0], now))
+	assert.Equal(t, int64(1), openAlerts())
+}
+
+func TestDeviceRepository_GetFleetSnapshot(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, repo.CreateDeviceGroup(ctx, device.DeviceGroup{ID: "greenhouse", CreatedAt: now}))
+	devices := []device.Device{
+		{ID: "foo", Labels: map[string]string{"env": "prod"}, GroupID: "greenhouse", CreatedAt: now.Add(-2 * time.Minute)},
+		{ID: "bar", Labels: map[string]string{"env": "prod"}, CreatedAt: now.Add(-time.Minute)},
+		{ID: "baz", Labels: map[string]string{"env": "staging"}, CreatedAt: now},
+	}
+	for _, dev := range devices {
+		require.NoError(t, repo.CreateDevice(ctx, dev))
+	}
+
+	latest := device.Metric{
+		Values: []device.MetricValue{{Name: device.MetricTemperature, Value: 21.5}, {Name: "humidity", Value: 40, Unit: "%"}},
+		Time:   now,
+	}
+	require.NoError(t, repo.SaveDeviceMetric(ctx, "foo", latest))
+	// metrics recorded out of order do not replace the latest metric
+	require.NoError(t, repo.SaveDeviceMetrics(ctx, "foo", []device.Metric{
+		{Values: []device.MetricValue{{Name: device.MetricTemperature, Value: 18}}, Time: now.Add(-time.Hour)},
+	}))
+	require.NoError(t, repo.SaveDeviceMetric(ctx, "bar", device.Metric{
+		Values: []device.MetricValue{{Name: device.MetricTemperature, Value: 30}},
+		Time:   now.Add(-time.Minute),
+	}))
+
+	for _, state := range []device.AlertState{device.AlertStateOpen, device.AlertStateOpen, device.AlertStateResolved} {
+		_, err := repo.SaveDeviceAlert(ctx, "foo", device.Alert{
+			Reason:      device.AlertReasonTemperatureHigh,
+			Severity:    device.AlertSeverityWarning,
+			Time:        now,
+			LastSeen:    now,
+			State:       state,
+			Occurrences: 1,
+		})
+		require.NoError(t, err)
+	}
+
+	p1, err := repo.GetFleetSnapshot(ctx, nil, "", device.RepositoryPageOptions{Size: 2})
+	require.NoError(t, err)
+	require.Len(t, p1.Items, 2)
+	require.NotNil(t, p1.NextPageToken)
+
+	baz := p1.Items[0]
+	assert.Equal(t, "baz", baz.Device.ID)
+	assert.Nil(t, baz.LatestMetric)
+	assert.Nil(t, baz.Device.LastSeen)
+	assert.Zero(t, baz.OpenAlerts)
+
+	bar := p1.Items[1]
+	assert.Equal(t, "bar", bar.Device.ID)
+	require.NotNil(t, bar.LatestMetric)
+	assert.Equal(t, now.Add(-time.Minute), bar.LatestMetric.Time)
+
+	p2, err := repo.GetFleetSnapshot(ctx, nil, "", device.RepositoryPageOptions{Size: 2, Token: p1.NextPageToken})
+	require.NoError(t, err)
+	require.Len(t, p2.Items, 1)
+	assert.Nil(t, p2.NextPageToken)
+
+	foo := p2.Items[0]
+	assert.Equal(t, "foo", foo.Device.ID)
+	require.NotNil(t, foo.Device.LastSeen)
+	assert.Equal(t, now, *foo.Device.LastSeen)
+	require.NotNil(t, foo.LatestMetric)
+	assert.Equal(t, latest, *foo.LatestMetric)
+	assert.Equal(t, int64(2), foo.OpenAlerts)
+
+	// filtered by label selector and group
+	prod := device.LabelSelector{{Key: "env", Operator: device.LabelOperatorEquals, Value: "prod"}}
+	got, err := repo.GetFleetSnapshot(ctx, prod, "", device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	require.Len(t, got.Items, 2)
+	assert.Equal(t, "bar", got.Items[0].Device.ID)
+	assert.Equal(t, "foo", got.Items[1].Device.ID)
+
+	got, err = repo.GetFleetSnapshot(ctx, prod, "greenhouse", device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	require.Len(t, got.Items, 1)
+	assert.Equal(t, "foo", got.Items[0].Device.ID)
+}
+
 func newRepo(t *testing.T, ctx context.Context) *DeviceRepository {
 	db, err := Open(ctx, WithDir(t.TempDir()))
 	require.NoError(t, err)
@@ -167,3 +1355,192 @@ func newRepo(t *testing.T, ctx context.Context) *DeviceRepository {
 	repo := NewDeviceRepository(db)
 	return repo
 }
+
+func TestDeviceRepository_DeleteDevice(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for _, deviceID := range []string{"foo", "bar"} {
+		_, err := repo.UpsertDeviceConfig(ctx, deviceID, device.DeviceConfigVersion{
+			Settings:  device.ConfigSettings{TemperatureThreshold: ptr(30.0)},
+			CreatedAt: now,
+		}, nil)
+		require.NoError(t, err)
+		_, err = repo.CreateAlertRule(ctx, deviceID, device.AlertRule{
+			Metric:    device.MetricTemperature,
+			Operator:  device.RuleOperatorGreaterThan,
+			Threshold: 30,
+			Severity:  device.AlertSeverityWarning,
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveConditionStates(ctx, deviceID, map[string]device.ConditionState{
+			"rule:1": {Breaches: 1},
+		}))
+		for i := range 5 {
+			require.NoError(t, repo.SaveDeviceMetric(ctx, deviceID, device.Metric{
+				Values: []device.MetricValue{{Name: device.MetricTemperature, Value: float64(i)}},
+				Time:   now.Add(time.Duration(i) * time.Second),
+			}))
+		}
+		for range 3 {
+			_, err = repo.SaveDeviceAlert(ctx, deviceID, device.Alert{
+				Reason:      device.AlertReasonTemperatureHigh,
+				Severity:    device.AlertSeverityWarning,
+				Time:        now,
+				LastSeen:    now,
+				State:       device.AlertStateOpen,
+				Occurrences: 1,
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	op := device.Operation{
+		Type:      device.OperationTypeDeleteDevice,
+		DeviceID:  "foo",
+		State:     device.OperationStateRunning,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	op, err := repo.DeleteDevice(ctx, "foo", op)
+	require.NoError(t, err)
+	assert.NotZero(t, op.ID)
+	assert.NotZero(t, op.MaxMetricID)
+	assert.NotZero(t, op.MaxAlertID)
+
+	_, err = repo.DeleteDevice(ctx, "foo", op)
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+
+	// the device, its config and rules are deleted with the device
+	_, err = repo.GetDevice(ctx, "foo")
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+	_, err = repo.GetDeviceConfig(ctx, "foo")
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+	_, err = repo.GetLatestDeviceConfigVersion(ctx, "foo")
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+	rules, err := repo.ListAlertRules(ctx, "foo")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+	states, err := repo.GetConditionStates(ctx, "foo")
+	require.NoError(t, err)
+	assert.Empty(t, states)
+
+	// metrics and alerts are deleted in batches
+	var deleted []int64
+	for {
+		n, err := repo.DeleteDeviceMetrics(ctx, "foo", op.MaxMetricID, 2)
+		require.NoError(t, err)
+		if n == 0 {
+			break
+		}
+		deleted = append(deleted, n)
+	}
+	assert.Equal(t, []int64{2, 2, 1}, deleted)
+	metrics, err := repo.GetDeviceMetrics(ctx, "foo", device.Timeframe{}, device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	assert.Empty(t, metrics.Items)
+
+	n, err := repo.DeleteDeviceAlerts(ctx, "foo", op.MaxAlertID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	alerts, err := repo.GetDeviceAlerts(ctx, "foo", device.Timeframe{}, device.AlertFilter{}, device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	assert.Empty(t, alerts.Items)
+
+	// other devices are unaffected
+	_, err = repo.GetDevice(ctx, "bar")
+	require.NoError(t, err)
+	rules, err = repo.ListAlertRules(ctx, "bar")
+	require.NoError(t, err)
+	assert.Len(t, rules, 1)
+	metrics, err = repo.GetDeviceMetrics(ctx, "bar", device.Timeframe{}, device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	assert.Len(t, metrics.Items, 5)
+	alerts, err = repo.GetDeviceAlerts(ctx, "bar", device.Timeframe{}, device.AlertFilter{}, device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	assert.Len(t, alerts.Items, 3)
+
+	got, err := repo.GetOperation(ctx, op.ID)
+	require.NoError(t, err)
+	assert.Equal(t, op, got)
+
+	ops, err := repo.ListOperations(ctx, device.OperationStateRunning)
+	require.NoError(t, err)
+	assert.Equal(t, []device.Operation{op}, ops)
+
+	op.State = device.OperationStateSucceeded
+	op.DeletedMetrics, op.DeletedAlerts = 5, 3
+	op.UpdatedAt = now.Add(time.Second)
+	require.NoError(t, repo.UpdateOperation(ctx, op))
+
+	got, err = repo.GetOperation(ctx, op.ID)
+	require.NoError(t, err)
+	assert.Equal(t, op, got)
+
+	ops, err = repo.ListOperations(ctx, device.OperationStateRunning)
+	require.NoError(t, err)
+	assert.Empty(t, ops)
+
+	_, err = repo.GetOperation(ctx, op.ID+1)
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+	err = repo.UpdateOperation(ctx, device.Operation{ID: op.ID + 1})
+	require.ErrorIs(t, err, device.ErrRepoItemNotFound)
+}
+
+func TestDeviceRepository_DeleteDevice_recordedAfterDelete(t *testing.T) {
+	ctx := t.Context()
+	repo := newRepo(t, ctx)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	saveData := func(value float64, ts time.Time) {
+		require.NoError(t, repo.SaveDeviceMetric(ctx, "foo", device.Metric{
+			Values: []device.MetricValue{{Name: device.MetricTemperature, Value: value}},
+			Time:   ts,
+		}))
+		_, err := repo.SaveDeviceAlert(ctx, "foo", device.Alert{
+			Reason:      device.AlertReasonTemperatureHigh,
+			Severity:    device.AlertSeverityWarning,
+			Time:        ts,
+			LastSeen:    ts,
+			State:       device.AlertStateOpen,
+			Occurrences: 1,
+		})
+		require.NoError(t, err)
+	}
+
+	saveData(1, now)
+	op, err := repo.DeleteDevice(ctx, "foo", device.Operation{
+		Type:      device.OperationTypeDeleteDevice,
+		DeviceID:  "foo",
+		State:     device.OperationStateRunning,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	require.NoError(t, err)
+
+	// the device is registered again by recording data before the purge runs
+	saveData(2, now.Add(time.Second))
+
+	n, err := repo.DeleteDeviceMetrics(ctx, "foo", op.MaxMetricID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	n, err = repo.DeleteDeviceAlerts(ctx, "foo", op.MaxAlertID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	metrics, err := repo.GetDeviceMetrics(ctx, "foo", device.Timeframe{}, device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	require.Len(t, metrics.Items, 1)
+	assert.Equal(t, 2.0, metrics.Items[0].Values[0].Value)
+	alerts, err := repo.GetDeviceAlerts(ctx, "foo", device.Timeframe{}, device.AlertFilter{}, device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	assert.Len(t, alerts.Items, 1)
+
+	// the latest metric of the registered device still exists
+	page, err := repo.GetFleetSnapshot(ctx, nil, "", device.RepositoryPageOptions{Size: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.NotNil(t, page.Items[0].LatestMetric)
+	assert.Equal(t, metrics.Items[0], *page.Items[0].LatestMetric)
+}
@@ -7,50 +7,2003 @@ package sqlc
 
 import (
 	"context"
+	"strings"
 )
 
+const acknowledgeDeviceAlert = `-- name: AcknowledgeDeviceAlert :execrows
+UPDATE alerts
+SET state           = 'ACKNOWLEDGED',
+    acknowledged_by = ?,
+    ack_comment     = ?,
+    acknowledged_at = ?
+WHERE device_id = ?
+  AND id = ?
+  AND state = 'OPEN'
+`
+
+type AcknowledgeDeviceAlertParams struct {
+	AcknowledgedBy string
+	AckComment     string
+	AcknowledgedAt *int64
+	DeviceID       string
+	ID             int64
+}
+
+func (q *Queries) AcknowledgeDeviceAlert(ctx context.Context, arg AcknowledgeDeviceAlertParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, acknowledgeDeviceAlert,
+		arg.AcknowledgedBy,
+		arg.AckComment,
+		arg.AcknowledgedAt,
+		arg.DeviceID,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countAlertsByBucket = `-- name: CountAlertsByBucket :many
+SELECT CAST(timestamp - timestamp % ?1 AS INTEGER) AS bucket_start,
+       COUNT(*)                                                             AS count
+FROM alerts
+WHERE timestamp >= ?2
+  AND timestamp <= ?3
+GROUP BY bucket_start
+ORDER BY bucket_start
+`
+
+type CountAlertsByBucketParams struct {
+	BucketSeconds int64
+	StartTs       int64
+	EndTs         int64
+}
+
+type CountAlertsByBucketRow struct {
+	BucketStart int64
+	Count       int64
+}
+
+// counts alerts in buckets aligned to the unix epoch
+func (q *Queries) CountAlertsByBucket(ctx context.Context, arg CountAlertsByBucketParams) ([]*CountAlertsByBucketRow, error) {
+	rows, err := q.db.QueryContext(ctx, countAlertsByBucket, arg.BucketSeconds, arg.StartTs, arg.EndTs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*CountAlertsByBucketRow
+	for rows.Next() {
+		var i CountAlertsByBucketRow
+		if err := rows.Scan(&i.BucketStart, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countAlertsByDevice = `-- name: CountAlertsByDevice :many
+SELECT device_id, COUNT(*) AS count
+FROM alerts
+WHERE timestamp >= ?1
+  AND timestamp <= ?2
+GROUP BY device_id
+ORDER BY device_id
+`
+
+type CountAlertsByDeviceParams struct {
+	StartTs int64
+	EndTs   int64
+}
+
+type CountAlertsByDeviceRow struct {
+	DeviceID string
+	Count    int64
+}
+
+func (q *Queries) CountAlertsByDevice(ctx context.Context, arg CountAlertsByDeviceParams) ([]*CountAlertsByDeviceRow, error) {
+	rows, err := q.db.QueryContext(ctx, countAlertsByDevice, arg.StartTs, arg.EndTs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*CountAlertsByDeviceRow
+	for rows.Next() {
+		var i CountAlertsByDeviceRow
+		if err := rows.Scan(&i.DeviceID, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countAlertsByReason = `-- name: CountAlertsByReason :many
+SELECT reason, COUNT(*) AS count
+FROM alerts
+WHERE timestamp >= ?1
+  AND timestamp <= ?2
+GROUP BY reason
+ORDER BY reason
+`
+
+type CountAlertsByReasonParams struct {
+	StartTs int64
+	EndTs   int64
+}
+
+type CountAlertsByReasonRow struct {
+	Reason string
+	Count  int64
+}
+
+func (q *Queries) CountAlertsByReason(ctx context.Context, arg CountAlertsByReasonParams) ([]*CountAlertsByReasonRow, error) {
+	rows, err := q.db.QueryContext(ctx, countAlertsByReason, arg.StartTs, arg.EndTs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*CountAlertsByReasonRow
+	for rows.Next() {
+		var i CountAlertsByReasonRow
+		if err := rows.Scan(&i.Reason, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createAlertRule = `-- name: CreateAlertRule :one
+INSERT INTO alert_rules (device_id, metric, operator, threshold, threshold_high, severity, window_seconds)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id
+`
+
+type CreateAlertRuleParams struct {
+	DeviceID      string
+	Metric        string
+	Operator      string
+	Threshold     float64
+	ThresholdHigh float64
+	Severity      string
+	WindowSeconds int64
+}
+
+func (q *Queries) CreateAlertRule(ctx context.Context, arg CreateAlertRuleParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createAlertRule,
+		arg.DeviceID,
+		arg.Metric,
+		arg.Operator,
+		arg.Threshold,
+		arg.ThresholdHigh,
+		arg.Severity,
+		arg.WindowSeconds,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createDevice = `-- name: CreateDevice :execrows
+INSERT INTO devices (id, display_name, model, firmware_version, location, labels, created_at, group_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO NOTHING
+`
+
+type CreateDeviceParams struct {
+	ID              string
+	DisplayName     string
+	Model           string
+	FirmwareVersion string
+	Location        string
+	Labels          string
+	CreatedAt       int64
+	GroupID         *string
+}
+
+func (q *Queries) CreateDevice(ctx context.Context, arg CreateDeviceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createDevice,
+		arg.ID,
+		arg.DisplayName,
+		arg.Model,
+		arg.FirmwareVersion,
+		arg.Location,
+		arg.Labels,
+		arg.CreatedAt,
+		arg.GroupID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createDeviceGroup = `-- name: CreateDeviceGroup :execrows
+INSERT INTO device_groups (id, display_name, created_at)
+VALUES (?, ?, ?)
+ON CONFLICT(id) DO NOTHING
+`
+
+type CreateDeviceGroupParams struct {
+	ID          string
+	DisplayName string
+	CreatedAt   int64
+}
+
+func (q *Queries) CreateDeviceGroup(ctx context.Context, arg CreateDeviceGroupParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createDeviceGroup, arg.ID, arg.DisplayName, arg.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createOperation = `-- name: CreateOperation :one
+INSERT INTO operations (type, device_id, state, deleted_metrics, deleted_alerts, max_metric_id, max_alert_id, error,
+                        created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id
+`
+
+type CreateOperationParams struct {
+	Type           string
+	DeviceID       string
+	State          string
+	DeletedMetrics int64
+	DeletedAlerts  int64
+	MaxMetricID    int64
+	MaxAlertID     int64
+	Error          string
+	CreatedAt      int64
+	UpdatedAt      int64
+}
+
+func (q *Queries) CreateOperation(ctx context.Context, arg CreateOperationParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createOperation,
+		arg.Type,
+		arg.DeviceID,
+		arg.State,
+		arg.DeletedMetrics,
+		arg.DeletedAlerts,
+		arg.MaxMetricID,
+		arg.MaxAlertID,
+		arg.Error,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteAlertRule = `-- name: DeleteAlertRule :execrows
+DELETE
+FROM alert_rules
+WHERE device_id = ?
+  AND id = ?
+`
+
+type DeleteAlertRuleParams struct {
+	DeviceID string
+	ID       int64
+}
+
+func (q *Queries) DeleteAlertRule(ctx context.Context, arg DeleteAlertRuleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAlertRule, arg.DeviceID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteDevice = `-- name: DeleteDevice :execrows
+DELETE
+FROM devices
+WHERE id = ?
+`
+
+func (q *Queries) DeleteDevice(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteDevice, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteDeviceAlertRules = `-- name: DeleteDeviceAlertRules :exec
+DELETE
+FROM alert_rules
+WHERE device_id = ?
+`
+
+func (q *Queries) DeleteDeviceAlertRules(ctx context.Context, deviceID string) error {
+	_, err := q.db.ExecContext(ctx, deleteDeviceAlertRules, deviceID)
+	return err
+}
+
+const deleteDeviceAlertsBatch = `-- name: DeleteDeviceAlertsBatch :execrows
+DELETE
+FROM alerts
+WHERE id IN (SELECT a.id
+             FROM alerts a
+             WHERE a.device_id = ?1
+               AND a.id <= ?2
+             ORDER BY a.id
+             LIMIT ?3)
+`
+
+type DeleteDeviceAlertsBatchParams struct {
+	DeviceID string
+	MaxID    int64
+	Limit    int64
+}
+
+func (q *Queries) DeleteDeviceAlertsBatch(ctx context.Context, arg DeleteDeviceAlertsBatchParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteDeviceAlertsBatch, arg.DeviceID, arg.MaxID, arg.Limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteDeviceConditionStates = `-- name: DeleteDeviceConditionStates :exec
+DELETE
+FROM alert_states
+WHERE device_id = ?
+`
+
+func (q *Queries) DeleteDeviceConditionStates(ctx context.Context, deviceID string) error {
+	_, err := q.db.ExecContext(ctx, deleteDeviceConditionStates, deviceID)
+	return err
+}
+
+const deleteDeviceConfigSettings = `-- name: DeleteDeviceConfigSettings :exec
+DELETE
+FROM config_settings
+WHERE level = 'DEVICE'
+  AND scope_id = ?
+`
+
+func (q *Queries) DeleteDeviceConfigSettings(ctx context.Context, scopeID string) error {
+	_, err := q.db.ExecContext(ctx, deleteDeviceConfigSettings, scopeID)
+	return err
+}
+
+const deleteDeviceConfigVersions = `-- name: DeleteDeviceConfigVersions :exec
+DELETE
+FROM device_config_versions
+WHERE device_id = ?
+`
+
+func (q *Queries) DeleteDeviceConfigVersions(ctx context.Context, deviceID string) error {
+	_, err := q.db.ExecContext(ctx, deleteDeviceConfigVersions, deviceID)
+	return err
+}
+
+const deleteDeviceLatest = `-- name: DeleteDeviceLatest :exec
+DELETE
+FROM device_latest
+WHERE device_id = ?
+`
+
+func (q *Queries) DeleteDeviceLatest(ctx context.Context, deviceID string) error {
+	_, err := q.db.ExecContext(ctx, deleteDeviceLatest, deviceID)
+	return err
+}
+
+const deleteDeviceMetricsBatch = `-- name: DeleteDeviceMetricsBatch :execrows
+DELETE
+FROM metrics
+WHERE id IN (SELECT m.id
+             FROM metrics m
+             WHERE m.device_id = ?1
+               AND m.id <= ?2
+             ORDER BY m.id
+             LIMIT ?3)
+`
+
+type DeleteDeviceMetricsBatchParams struct {
+	DeviceID string
+	MaxID    int64
+	Limit    int64
+}
+
+// metric values are deleted by cascade
+func (q *Queries) DeleteDeviceMetricsBatch(ctx context.Context, arg DeleteDeviceMetricsBatchParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteDeviceMetricsBatch, arg.DeviceID, arg.MaxID, arg.Limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAlertRule = `-- name: GetAlertRule :one
+SELECT id, device_id, metric, operator, threshold, threshold_high, severity, window_seconds
+FROM alert_rules
+WHERE device_id = ?
+  AND id = ?
+`
+
+type GetAlertRuleParams struct {
+	DeviceID string
+	ID       int64
+}
+
+func (q *Queries) GetAlertRule(ctx context.Context, arg GetAlertRuleParams) (*AlertRule, error) {
+	row := q.db.QueryRowContext(ctx, getAlertRule, arg.DeviceID, arg.ID)
+	var i AlertRule
+	err := row.Scan(
+		&i.ID,
+		&i.DeviceID,
+		&i.Metric,
+		&i.Operator,
+		&i.Threshold,
+		&i.ThresholdHigh,
+		&i.Severity,
+		&i.WindowSeconds,
+	)
+	return &i, err
+}
+
+const getAlertsAfterID = `-- name: GetAlertsAfterID :many
+SELECT id, device_id, reason, "desc", timestamp, rule_id, severity, state, condition, acknowledged_by, ack_comment, acknowledged_at, resolved_at, occurrences, last_seen, config_version
+FROM alerts
+WHERE id > ?1
+  AND (CAST(?2 AS TEXT) IS NULL OR device_id = ?2)
+ORDER BY id
+LIMIT ?3
+`
+
+type GetAlertsAfterIDParams struct {
+	AfterID  int64
+	DeviceID *string
+	Limit    int64
+}
+
+func (q *Queries) GetAlertsAfterID(ctx context.Context, arg GetAlertsAfterIDParams) ([]*Alert, error) {
+	rows, err := q.db.QueryContext(ctx, getAlertsAfterID, arg.AfterID, arg.DeviceID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.DeviceID,
+			&i.Reason,
+			&i.Desc,
+			&i.Timestamp,
+			&i.RuleID,
+			&i.Severity,
+			&i.State,
+			&i.Condition,
+			&i.AcknowledgedBy,
+			&i.AckComment,
+			&i.AcknowledgedAt,
+			&i.ResolvedAt,
+			&i.Occurrences,
+			&i.LastSeen,
+			&i.ConfigVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getConditionStates = `-- name: GetConditionStates :many
+SELECT device_id, condition, breaches, active
+FROM alert_states
+WHERE device_id = ?
+`
+
+func (q *Queries) GetConditionStates(ctx context.Context, deviceID string) ([]*AlertState, error) {
+	rows, err := q.db.QueryContext(ctx, getConditionStates, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*AlertState
+	for rows.Next() {
+		var i AlertState
+		if err := rows.Scan(
+			&i.DeviceID,
+			&i.Condition,
+			&i.Breaches,
+			&i.Active,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDevice = `-- name: GetDevice :one
+SELECT seq, id, display_name, model, firmware_version, location, labels, created_at, last_seen, group_id
+FROM devices
+WHERE id = ?
+`
+
+func (q *Queries) GetDevice(ctx context.Context, id string) (*Device, error) {
+	row := q.db.QueryRowContext(ctx, getDevice, id)
+	var i Device
+	err := row.Scan(
+		&i.Seq,
+		&i.ID,
+		&i.DisplayName,
+		&i.Model,
+		&i.FirmwareVersion,
+		&i.Location,
+		&i.Labels,
+		&i.CreatedAt,
+		&i.LastSeen,
+		&i.GroupID,
+	)
+	return &i, err
+}
+
+const getDeviceAlert = `-- name: GetDeviceAlert :one
+SELECT id, device_id, reason, "desc", timestamp, rule_id, severity, state, condition, acknowledged_by, ack_comment, acknowledged_at, resolved_at, occurrences, last_seen, config_version
+FROM alerts
+WHERE device_id = ?
+  AND id = ?
+`
+
+type GetDeviceAlertParams struct {
+	DeviceID string
+	ID       int64
+}
+
+func (q *Queries) GetDeviceAlert(ctx context.Context, arg GetDeviceAlertParams) (*Alert, error) {
+	row := q.db.QueryRowContext(ctx, getDeviceAlert, arg.DeviceID, arg.ID)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.DeviceID,
+		&i.Reason,
+		&i.Desc,
+		&i.Timestamp,
+		&i.RuleID,
+		&i.Severity,
+		&i.State,
+		&i.Condition,
+		&i.AcknowledgedBy,
+		&i.AckComment,
+		&i.AcknowledgedAt,
+		&i.ResolvedAt,
+		&i.Occurrences,
+		&i.LastSeen,
+		&i.ConfigVersion,
+	)
+	return &i, err
+}
+
 const getDeviceAlerts = `-- name: GetDeviceAlerts :many
-SELECT id, device_id, reason, "desc", timestamp
+WITH filter AS (SELECT CAST(?9 AS TEXT) AS reasons)
+SELECT id, device_id, reason, "desc", timestamp, rule_id, severity, state, condition, acknowledged_by, ack_comment, acknowledged_at, resolved_at, occurrences, last_seen, config_version
+FROM alerts
+WHERE device_id = ?1
+  -- time window
+  AND (CAST(?2 AS INTEGER) IS NULL OR timestamp >= ?2)
+  AND (CAST(?3 AS INTEGER) IS NULL OR timestamp <= ?3)
+  AND (CAST(?4 AS TEXT) IS NULL OR state = ?4)
+  AND (CAST(?5 AS TEXT) IS NULL OR severity = ?5)
+  AND (
+    (SELECT json_array_length(f.reasons) FROM filter f) = 0
+        OR reason IN (SELECT r.value FROM filter f, json_each(f.reasons) r)
+    )
+  -- composite cursor
+  AND (
+    CAST(?6 AS INTEGER) IS NULL
+        OR (
+        -- timestamp less than previous page last row
+        timestamp < ?6
+            OR (
+            -- or timestamp equal to previous page last row
+            timestamp = ?6
+                -- but is less than last row id
+                AND (CAST(?7 AS INTEGER) IS NULL OR id < ?7)
+            )
+        )
+    )
+ORDER BY timestamp DESC, id DESC
+LIMIT ?8
+`
+
+type GetDeviceAlertsParams struct {
+	DeviceID string
+	StartTs  *int64
+	EndTs    *int64
+	State    *string
+	Severity *string
+	LastTs   *int64
+	LastID   *int64
+	Limit    int64
+	Reasons  string
+}
+
+// reasons is a JSON array of alert reasons, or empty to not filter by reason
+func (q *Queries) GetDeviceAlerts(ctx context.Context, arg GetDeviceAlertsParams) ([]*Alert, error) {
+	rows, err := q.db.QueryContext(ctx, getDeviceAlerts,
+		arg.DeviceID,
+		arg.StartTs,
+		arg.EndTs,
+		arg.State,
+		arg.Severity,
+		arg.LastTs,
+		arg.LastID,
+		arg.Limit,
+		arg.Reasons,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.DeviceID,
+			&i.Reason,
+			&i.Desc,
+			&i.Timestamp,
+			&i.RuleID,
+			&i.Severity,
+			&i.State,
+			&i.Condition,
+			&i.AcknowledgedBy,
+			&i.AckComment,
+			&i.AcknowledgedAt,
+			&i.ResolvedAt,
+			&i.Occurrences,
+			&i.LastSeen,
+			&i.ConfigVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeviceAlertsAsc = `-- name: GetDeviceAlertsAsc :many
+WITH filter AS (SELECT CAST(?9 AS TEXT) AS reasons)
+SELECT id, device_id, reason, "desc", timestamp, rule_id, severity, state, condition, acknowledged_by, ack_comment, acknowledged_at, resolved_at, occurrences, last_seen, config_version
+FROM alerts
+WHERE device_id = ?1
+  -- time window
+  AND (CAST(?2 AS INTEGER) IS NULL OR timestamp >= ?2)
+  AND (CAST(?3 AS INTEGER) IS NULL OR timestamp <= ?3)
+  AND (CAST(?4 AS TEXT) IS NULL OR state = ?4)
+  AND (CAST(?5 AS TEXT) IS NULL OR severity = ?5)
+  AND (
+    (SELECT json_array_length(f.reasons) FROM filter f) = 0
+        OR reason IN (SELECT r.value FROM filter f, json_each(f.reasons) r)
+    )
+  -- composite cursor
+  AND (
+    CAST(?6 AS INTEGER) IS NULL
+        OR (
+        -- timestamp greater than previous page last row
+        timestamp > ?6
+            OR (
+            -- or timestamp equal to previous page last row
+            timestamp = ?6
+                -- but is greater than last row id
+                AND (CAST(?7 AS INTEGER) IS NULL OR id > ?7)
+            )
+        )
+    )
+ORDER BY timestamp, id
+LIMIT ?8
+`
+
+type GetDeviceAlertsAscParams struct {
+	DeviceID string
+	StartTs  *int64
+	EndTs    *int64
+	State    *string
+	Severity *string
+	LastTs   *int64
+	LastID   *int64
+	Limit    int64
+	Reasons  string
+}
+
+// pages alerts oldest first, see GetDeviceAlerts
+// reasons is a JSON array of alert reasons, or empty to not filter by reason
+func (q *Queries) GetDeviceAlertsAsc(ctx context.Context, arg GetDeviceAlertsAscParams) ([]*Alert, error) {
+	rows, err := q.db.QueryContext(ctx, getDeviceAlertsAsc,
+		arg.DeviceID,
+		arg.StartTs,
+		arg.EndTs,
+		arg.State,
+		arg.Severity,
+		arg.LastTs,
+		arg.LastID,
+		arg.Limit,
+		arg.Reasons,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.DeviceID,
+			&i.Reason,
+			&i.Desc,
+			&i.Timestamp,
+			&i.RuleID,
+			&i.Severity,
+			&i.State,
+			&i.Condition,
+			&i.AcknowledgedBy,
+			&i.AckComment,
+			&i.AcknowledgedAt,
+			&i.ResolvedAt,
+			&i.Occurrences,
+			&i.LastSeen,
+			&i.ConfigVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeviceConfigSettings = `-- name: GetDeviceConfigSettings :many
+SELECT cs.level, cs.scope_id, cs.temperature_threshold, cs.battery_threshold, cs.expressions, cs.consecutive_breaches, cs.hysteresis, cs.cooldowns, cs.reporting_interval_seconds, cs.version
+FROM config_settings cs
+WHERE (cs.level = 'DEVICE' AND cs.scope_id = ?1)
+   OR (cs.level = 'GROUP' AND cs.scope_id = (SELECT d.group_id FROM devices d WHERE d.id = ?1))
+   OR (cs.level = 'GLOBAL' AND cs.scope_id = '')
+`
+
+// settings of each level that applies to the device
+func (q *Queries) GetDeviceConfigSettings(ctx context.Context, deviceID string) ([]*ConfigSetting, error) {
+	rows, err := q.db.QueryContext(ctx, getDeviceConfigSettings, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ConfigSetting
+	for rows.Next() {
+		var i ConfigSetting
+		if err := rows.Scan(
+			&i.Level,
+			&i.ScopeID,
+			&i.TemperatureThreshold,
+			&i.BatteryThreshold,
+			&i.Expressions,
+			&i.ConsecutiveBreaches,
+			&i.Hysteresis,
+			&i.Cooldowns,
+			&i.ReportingIntervalSeconds,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeviceConfigVersion = `-- name: GetDeviceConfigVersion :one
+SELECT device_id, version, temperature_threshold, battery_threshold, expressions, consecutive_breaches, hysteresis, cooldowns, reporting_interval_seconds, created_at, author, reason
+FROM device_config_versions
+WHERE device_id = ?
+  AND version = ?
+`
+
+type GetDeviceConfigVersionParams struct {
+	DeviceID string
+	Version  int64
+}
+
+func (q *Queries) GetDeviceConfigVersion(ctx context.Context, arg GetDeviceConfigVersionParams) (*DeviceConfigVersion, error) {
+	row := q.db.QueryRowContext(ctx, getDeviceConfigVersion, arg.DeviceID, arg.Version)
+	var i DeviceConfigVersion
+	err := row.Scan(
+		&i.DeviceID,
+		&i.Version,
+		&i.TemperatureThreshold,
+		&i.BatteryThreshold,
+		&i.Expressions,
+		&i.ConsecutiveBreaches,
+		&i.Hysteresis,
+		&i.Cooldowns,
+		&i.ReportingIntervalSeconds,
+		&i.CreatedAt,
+		&i.Author,
+		&i.Reason,
+	)
+	return &i, err
+}
+
+const getDeviceGroup = `-- name: GetDeviceGroup :one
+SELECT id, display_name, created_at
+FROM device_groups
+WHERE id = ?
+`
+
+func (q *Queries) GetDeviceGroup(ctx context.Context, id string) (*DeviceGroup, error) {
+	row := q.db.QueryRowContext(ctx, getDeviceGroup, id)
+	var i DeviceGroup
+	err := row.Scan(&i.ID, &i.DisplayName, &i.CreatedAt)
+	return &i, err
+}
+
+const getDeviceMaxAlertID = `-- name: GetDeviceMaxAlertID :one
+SELECT CAST(COALESCE(MAX(id), 0) AS INTEGER) AS max_id
+FROM alerts
+WHERE device_id = ?
+`
+
+func (q *Queries) GetDeviceMaxAlertID(ctx context.Context, deviceID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getDeviceMaxAlertID, deviceID)
+	var max_id int64
+	err := row.Scan(&max_id)
+	return max_id, err
+}
+
+const getDeviceMaxMetricID = `-- name: GetDeviceMaxMetricID :one
+SELECT CAST(COALESCE(MAX(id), 0) AS INTEGER) AS max_id
+FROM metrics
+WHERE device_id = ?
+`
+
+func (q *Queries) GetDeviceMaxMetricID(ctx context.Context, deviceID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getDeviceMaxMetricID, deviceID)
+	var max_id int64
+	err := row.Scan(&max_id)
+	return max_id, err
+}
+
+const getDeviceMetricAggregates = `-- name: GetDeviceMetricAggregates :many
+SELECT name,
+       bucket_start,
+       CAST(MIN(value) AS REAL)       AS min_value,
+       CAST(MAX(value) AS REAL)       AS max_value,
+       CAST(AVG(value) AS REAL)       AS avg_value,
+       COUNT(*)                       AS count,
+       CAST(MIN(earliest) AS REAL)    AS first_reading,
+       CAST(MIN(latest) AS REAL)      AS last_reading
+FROM (SELECT name,
+             bucket_start,
+             value,
+             -- values of the earliest and latest metric in the bucket
+             FIRST_VALUE(value) OVER (PARTITION BY name, bucket_start ORDER BY timestamp, id
+                 ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) AS earliest,
+             LAST_VALUE(value) OVER (PARTITION BY name, bucket_start ORDER BY timestamp, id
+                 ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING)  AS latest
+      FROM (SELECT mv.name,
+                   CAST(m.timestamp - m.timestamp % ?1 AS INTEGER) AS bucket_start,
+                   m.timestamp,
+                   m.id,
+                   mv.value
+            FROM metrics m
+                     JOIN metric_values mv ON mv.metric_id = m.id
+            WHERE m.device_id = ?2
+              AND m.timestamp >= ?3
+              AND m.timestamp <= ?4) AS bucketed) AS windowed
+GROUP BY name, bucket_start
+ORDER BY name, bucket_start
+`
+
+type GetDeviceMetricAggregatesParams struct {
+	BucketSeconds int64
+	DeviceID      string
+	StartTs       int64
+	EndTs         int64
+}
+
+type GetDeviceMetricAggregatesRow struct {
+	Name         string
+	BucketStart  int64
+	MinValue     float64
+	MaxValue     float64
+	AvgValue     float64
+	Count        int64
+	FirstReading float64
+	LastReading  float64
+}
+
+// aggregates the values of each metric in buckets aligned to the unix epoch
+func (q *Queries) GetDeviceMetricAggregates(ctx context.Context, arg GetDeviceMetricAggregatesParams) ([]*GetDeviceMetricAggregatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDeviceMetricAggregates,
+		arg.BucketSeconds,
+		arg.DeviceID,
+		arg.StartTs,
+		arg.EndTs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*GetDeviceMetricAggregatesRow
+	for rows.Next() {
+		var i GetDeviceMetricAggregatesRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.BucketStart,
+			&i.MinValue,
+			&i.MaxValue,
+			&i.AvgValue,
+			&i.Count,
+			&i.FirstReading,
+			&i.LastReading,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeviceMetrics = `-- name: GetDeviceMetrics :many
+SELECT id, device_id, timestamp
+FROM metrics
+WHERE device_id = ?1
+  -- time window
+  AND (CAST(?2 AS INTEGER) IS NULL OR timestamp >= ?2)
+  AND (CAST(?3 AS INTEGER) IS NULL OR timestamp <= ?3)
+  -- composite cursor
+  AND (
+    CAST(?4 AS INTEGER) IS NULL
+        OR (
+        -- timestamp less than previous page last row
+        timestamp < ?4
+            OR (
+            -- or timestamp equal to previous page last row
+            timestamp = ?4
+                -- but is less than last row id
+                AND (CAST(?5 AS INTEGER) IS NULL OR id < ?5)
+            )
+        )
+    )
+ORDER BY timestamp DESC, id DESC
+LIMIT ?6
+`
+
+type GetDeviceMetricsParams struct {
+	DeviceID string
+	StartTs  *int64
+	EndTs    *int64
+	LastTs   *int64
+	LastID   *int64
+	Limit    int64
+}
+
+func (q *Queries) GetDeviceMetrics(ctx context.Context, arg GetDeviceMetricsParams) ([]*Metric, error) {
+	rows, err := q.db.QueryContext(ctx, getDeviceMetrics,
+		arg.DeviceID,
+		arg.StartTs,
+		arg.EndTs,
+		arg.LastTs,
+		arg.LastID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Metric
+	for rows.Next() {
+		var i Metric
+		if err := rows.Scan(&i.ID, &i.DeviceID, &i.Timestamp); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeviceMetricsAsc = `-- name: GetDeviceMetricsAsc :many
+SELECT id, device_id, timestamp
+FROM metrics
+WHERE device_id = ?1
+  -- time window
+  AND (CAST(?2 AS INTEGER) IS NULL OR timestamp >= ?2)
+  AND (CAST(?3 AS INTEGER) IS NULL OR timestamp <= ?3)
+  -- composite cursor
+  AND (
+    CAST(?4 AS INTEGER) IS NULL
+        OR (
+        -- timestamp greater than previous page last row
+        timestamp > ?4
+            OR (
+            -- or timestamp equal to previous page last row
+            timestamp = ?4
+                -- but is greater than last row id
+                AND (CAST(?5 AS INTEGER) IS NULL OR id > ?5)
+            )
+        )
+    )
+ORDER BY timestamp, id
+LIMIT ?6
+`
+
+type GetDeviceMetricsAscParams struct {
+	DeviceID string
+	StartTs  *int64
+	EndTs    *int64
+	LastTs   *int64
+	LastID   *int64
+	Limit    int64
+}
+
+// pages metrics oldest first, see GetDeviceMetrics
+func (q *Queries) GetDeviceMetricsAsc(ctx context.Context, arg GetDeviceMetricsAscParams) ([]*Metric, error) {
+	rows, err := q.db.QueryContext(ctx, getDeviceMetricsAsc,
+		arg.DeviceID,
+		arg.StartTs,
+		arg.EndTs,
+		arg.LastTs,
+		arg.LastID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Metric
+	for rows.Next() {
+		var i Metric
+		if err := rows.Scan(&i.ID, &i.DeviceID, &i.Timestamp); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFleetSnapshot = `-- name: GetFleetSnapshot :many
+SELECT d.seq, d.id, d.display_name, d.model, d.firmware_version, d.location, d.labels, d.created_at, d.last_seen, d.group_id,
+       l.metric_id AS latest_metric_id,
+       l.timestamp AS latest_ts,
+       (SELECT COUNT(*)
+        FROM alerts a
+        WHERE a.device_id = d.id
+          AND a.state != 'RESOLVED') AS open_alerts
+FROM devices d
+         LEFT JOIN device_latest l ON l.device_id = d.id
+WHERE labels_match(d.labels, CAST(?1 AS TEXT))
+  AND (CAST(?2 AS TEXT) IS NULL OR d.group_id = ?2)
+  -- composite cursor
+  AND (
+    CAST(?3 AS INTEGER) IS NULL
+        OR (
+        -- created before previous page last row
+        d.created_at < ?3
+            OR (
+            -- or created at the same time as previous page last row
+            d.created_at = ?3
+                -- but is less than last row seq
+                AND (CAST(?4 AS INTEGER) IS NULL OR d.seq < ?4)
+            )
+        )
+    )
+ORDER BY d.created_at DESC, d.seq DESC
+LIMIT ?5
+`
+
+type GetFleetSnapshotParams struct {
+	Selector string
+	GroupID  *string
+	LastTs   *int64
+	LastSeq  *int64
+	Limit    int64
+}
+
+type GetFleetSnapshotRow struct {
+	Device         Device
+	LatestMetricID *int64
+	LatestTs       *int64
+	OpenAlerts     int64
+}
+
+// selector is a JSON array of label requirements that devices must all meet,
+// see labels_match in labels.go
+func (q *Queries) GetFleetSnapshot(ctx context.Context, arg GetFleetSnapshotParams) ([]*GetFleetSnapshotRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFleetSnapshot,
+		arg.Selector,
+		arg.GroupID,
+		arg.LastTs,
+		arg.LastSeq,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*GetFleetSnapshotRow
+	for rows.Next() {
+		var i GetFleetSnapshotRow
+		if err := rows.Scan(
+			&i.Device.Seq,
+			&i.Device.ID,
+			&i.Device.DisplayName,
+			&i.Device.Model,
+			&i.Device.FirmwareVersion,
+			&i.Device.Location,
+			&i.Device.Labels,
+			&i.Device.CreatedAt,
+			&i.Device.LastSeen,
+			&i.Device.GroupID,
+			&i.LatestMetricID,
+			&i.LatestTs,
+			&i.OpenAlerts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLatestConditionAlert = `-- name: GetLatestConditionAlert :one
+SELECT id, device_id, reason, "desc", timestamp, rule_id, severity, state, condition, acknowledged_by, ack_comment, acknowledged_at, resolved_at, occurrences, last_seen, config_version
+FROM alerts
+WHERE device_id = ?
+  AND condition = ?
+ORDER BY id DESC
+LIMIT 1
+`
+
+type GetLatestConditionAlertParams struct {
+	DeviceID  string
+	Condition string
+}
+
+func (q *Queries) GetLatestConditionAlert(ctx context.Context, arg GetLatestConditionAlertParams) (*Alert, error) {
+	row := q.db.QueryRowContext(ctx, getLatestConditionAlert, arg.DeviceID, arg.Condition)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.DeviceID,
+		&i.Reason,
+		&i.Desc,
+		&i.Timestamp,
+		&i.RuleID,
+		&i.Severity,
+		&i.State,
+		&i.Condition,
+		&i.AcknowledgedBy,
+		&i.AckComment,
+		&i.AcknowledgedAt,
+		&i.ResolvedAt,
+		&i.Occurrences,
+		&i.LastSeen,
+		&i.ConfigVersion,
+	)
+	return &i, err
+}
+
+const getLatestDeviceConfigVersion = `-- name: GetLatestDeviceConfigVersion :one
+SELECT device_id, version, temperature_threshold, battery_threshold, expressions, consecutive_breaches, hysteresis, cooldowns, reporting_interval_seconds, created_at, author, reason
+FROM device_config_versions
+WHERE device_id = ?
+ORDER BY version DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestDeviceConfigVersion(ctx context.Context, deviceID string) (*DeviceConfigVersion, error) {
+	row := q.db.QueryRowContext(ctx, getLatestDeviceConfigVersion, deviceID)
+	var i DeviceConfigVersion
+	err := row.Scan(
+		&i.DeviceID,
+		&i.Version,
+		&i.TemperatureThreshold,
+		&i.BatteryThreshold,
+		&i.Expressions,
+		&i.ConsecutiveBreaches,
+		&i.Hysteresis,
+		&i.Cooldowns,
+		&i.ReportingIntervalSeconds,
+		&i.CreatedAt,
+		&i.Author,
+		&i.Reason,
+	)
+	return &i, err
+}
+
+const getMetricRange = `-- name: GetMetricRange :one
+SELECT COUNT(*)                                 AS count,
+       CAST(COALESCE(MIN(mv.value), 0) AS REAL) AS low,
+       CAST(COALESCE(MAX(mv.value), 0) AS REAL) AS high
+FROM metrics m
+         JOIN metric_values mv ON mv.metric_id = m.id
+WHERE m.device_id = ?1
+  AND m.timestamp >= ?2
+  AND m.timestamp < ?3
+  AND mv.name = ?4
+`
+
+type GetMetricRangeParams struct {
+	DeviceID string
+	StartTs  int64
+	EndTs    int64
+	Name     string
+}
+
+type GetMetricRangeRow struct {
+	Count int64
+	Low   float64
+	High  float64
+}
+
+// the lowest and highest values of a metric recorded in [start_ts, end_ts)
+func (q *Queries) GetMetricRange(ctx context.Context, arg GetMetricRangeParams) (*GetMetricRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getMetricRange,
+		arg.DeviceID,
+		arg.StartTs,
+		arg.EndTs,
+		arg.Name,
+	)
+	var i GetMetricRangeRow
+	err := row.Scan(&i.Count, &i.Low, &i.High)
+	return &i, err
+}
+
+const getMetricValues = `-- name: GetMetricValues :many
+SELECT id, metric_id, name, value, unit
+FROM metric_values
+WHERE metric_id IN (/*SLICE:metric_ids*/?)
+ORDER BY metric_id, id
+`
+
+func (q *Queries) GetMetricValues(ctx context.Context, metricIds []int64) ([]*MetricValue, error) {
+	query := getMetricValues
+	var queryParams []interface{}
+	if len(metricIds) > 0 {
+		for _, v := range metricIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:metric_ids*/?", strings.Repeat(",?", len(metricIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:metric_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*MetricValue
+	for rows.Next() {
+		var i MetricValue
+		if err := rows.Scan(
+			&i.ID,
+			&i.MetricID,
+			&i.Name,
+			&i.Value,
+			&i.Unit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNoisiestDevices = `-- name: GetNoisiestDevices :many
+SELECT device_id, COUNT(*) AS count
 FROM alerts
+WHERE timestamp >= ?1
+  AND timestamp <= ?2
+GROUP BY device_id
+ORDER BY count DESC, device_id
+LIMIT ?3
+`
+
+type GetNoisiestDevicesParams struct {
+	StartTs int64
+	EndTs   int64
+	Limit   int64
+}
+
+type GetNoisiestDevicesRow struct {
+	DeviceID string
+	Count    int64
+}
+
+func (q *Queries) GetNoisiestDevices(ctx context.Context, arg GetNoisiestDevicesParams) ([]*GetNoisiestDevicesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNoisiestDevices, arg.StartTs, arg.EndTs, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*GetNoisiestDevicesRow
+	for rows.Next() {
+		var i GetNoisiestDevicesRow
+		if err := rows.Scan(&i.DeviceID, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOperation = `-- name: GetOperation :one
+SELECT id, type, device_id, state, deleted_metrics, deleted_alerts, max_metric_id, max_alert_id, error, created_at, updated_at
+FROM operations
+WHERE id = ?
+`
+
+func (q *Queries) GetOperation(ctx context.Context, id int64) (*Operation, error) {
+	row := q.db.QueryRowContext(ctx, getOperation, id)
+	var i Operation
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.DeviceID,
+		&i.State,
+		&i.DeletedMetrics,
+		&i.DeletedAlerts,
+		&i.MaxMetricID,
+		&i.MaxAlertID,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const listAlertRules = `-- name: ListAlertRules :many
+SELECT id, device_id, metric, operator, threshold, threshold_high, severity, window_seconds
+FROM alert_rules
+WHERE device_id = ?
+ORDER BY id
+`
+
+func (q *Queries) ListAlertRules(ctx context.Context, deviceID string) ([]*AlertRule, error) {
+	rows, err := q.db.QueryContext(ctx, listAlertRules, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*AlertRule
+	for rows.Next() {
+		var i AlertRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.DeviceID,
+			&i.Metric,
+			&i.Operator,
+			&i.Threshold,
+			&i.ThresholdHigh,
+			&i.Severity,
+			&i.WindowSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeviceConfigVersions = `-- name: ListDeviceConfigVersions :many
+SELECT device_id, version, temperature_threshold, battery_threshold, expressions, consecutive_breaches, hysteresis, cooldowns, reporting_interval_seconds, created_at, author, reason
+FROM device_config_versions
 WHERE device_id = ?1
-  -- time window
-  AND (CAST(?2 AS INTEGER) IS NULL OR timestamp >= ?2)
-  AND (CAST(?3 AS INTEGER) IS NULL OR timestamp <= ?3)
+  AND (CAST(?2 AS INTEGER) IS NULL OR version < ?2)
+ORDER BY version DESC
+LIMIT ?3
+`
+
+type ListDeviceConfigVersionsParams struct {
+	DeviceID    string
+	LastVersion *int64
+	Limit       int64
+}
+
+func (q *Queries) ListDeviceConfigVersions(ctx context.Context, arg ListDeviceConfigVersionsParams) ([]*DeviceConfigVersion, error) {
+	rows, err := q.db.QueryContext(ctx, listDeviceConfigVersions, arg.DeviceID, arg.LastVersion, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*DeviceConfigVersion
+	for rows.Next() {
+		var i DeviceConfigVersion
+		if err := rows.Scan(
+			&i.DeviceID,
+			&i.Version,
+			&i.TemperatureThreshold,
+			&i.BatteryThreshold,
+			&i.Expressions,
+			&i.ConsecutiveBreaches,
+			&i.Hysteresis,
+			&i.Cooldowns,
+			&i.ReportingIntervalSeconds,
+			&i.CreatedAt,
+			&i.Author,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeviceGroups = `-- name: ListDeviceGroups :many
+SELECT id, display_name, created_at
+FROM device_groups
+ORDER BY id
+`
+
+func (q *Queries) ListDeviceGroups(ctx context.Context) ([]*DeviceGroup, error) {
+	rows, err := q.db.QueryContext(ctx, listDeviceGroups)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*DeviceGroup
+	for rows.Next() {
+		var i DeviceGroup
+		if err := rows.Scan(&i.ID, &i.DisplayName, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDevices = `-- name: ListDevices :many
+SELECT d.seq, d.id, d.display_name, d.model, d.firmware_version, d.location, d.labels, d.created_at, d.last_seen, d.group_id
+FROM devices d
+WHERE labels_match(d.labels, CAST(?1 AS TEXT))
   -- composite cursor
   AND (
-    CAST(?4 AS INTEGER) IS NULL
-        OR (
-        -- timestamp less than previous page last row
-        timestamp < ?4
-            OR (
-            -- or timestamp equal to previous page last row
-            timestamp = ?4
-                -- but is less than last row id
-                AND (CAST(?5 AS INTEGER) IS NULL OR id < ?5)
-            )
-        )
+    CAST(?2 AS INTEGER) IS NULL
+        OR (
+        -- created before previous page last row
+        d.created_at < ?2
+            OR (
+            -- or created at the same time as previous page last row
+            d.created_at = ?2
+                -- but is less than last row seq
+                AND (CAST(?3 AS INTEGER) IS NULL OR d.seq < ?3)
+            )
+        )
+    )
+ORDER BY d.created_at DESC, d.seq DESC
+LIMIT ?4
+`
+
+type ListDevicesParams struct {
+	Selector string
+	LastTs   *int64
+	LastSeq  *int64
+	Limit    int64
+}
+
+// selector is a JSON array of label requirements that devices must all meet,
+// see labels_match in labels.go
+func (q *Queries) ListDevices(ctx context.Context, arg ListDevicesParams) ([]*Device, error) {
+	rows, err := q.db.QueryContext(ctx, listDevices,
+		arg.Selector,
+		arg.LastTs,
+		arg.LastSeq,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Device
+	for rows.Next() {
+		var i Device
+		if err := rows.Scan(
+			&i.Seq,
+			&i.ID,
+			&i.DisplayName,
+			&i.Model,
+			&i.FirmwareVersion,
+			&i.Location,
+			&i.Labels,
+			&i.CreatedAt,
+			&i.LastSeen,
+			&i.GroupID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOperationsByState = `-- name: ListOperationsByState :many
+SELECT id, type, device_id, state, deleted_metrics, deleted_alerts, max_metric_id, max_alert_id, error, created_at, updated_at
+FROM operations
+WHERE state = ?
+ORDER BY id
+`
+
+func (q *Queries) ListOperationsByState(ctx context.Context, state string) ([]*Operation, error) {
+	rows, err := q.db.QueryContext(ctx, listOperationsByState, state)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Operation
+	for rows.Next() {
+		var i Operation
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.DeviceID,
+			&i.State,
+			&i.DeletedMetrics,
+			&i.DeletedAlerts,
+			&i.MaxMetricID,
+			&i.MaxAlertID,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReportingDevices = `-- name: ListReportingDevices :many
+SELECT d.id                                                  AS device_id,
+       CAST(COALESCE(dc.reporting_interval_seconds, gc.reporting_interval_seconds,
+                     g.reporting_interval_seconds, 0) AS INTEGER) AS reporting_interval_seconds,
+       CAST(COALESCE(d.last_seen, 0) AS INTEGER)             AS last_reported
+FROM devices d
+         LEFT JOIN config_settings dc ON dc.level = 'DEVICE' AND dc.scope_id = d.id
+         LEFT JOIN config_settings gc ON gc.level = 'GROUP' AND gc.scope_id = d.group_id
+         LEFT JOIN config_settings g ON g.level = 'GLOBAL' AND g.scope_id = ''
+WHERE COALESCE(dc.reporting_interval_seconds, gc.reporting_interval_seconds, g.reporting_interval_seconds, 0) > 0
+ORDER BY d.id
+`
+
+type ListReportingDevicesRow struct {
+	DeviceID                 string
+	ReportingIntervalSeconds int64
+	LastReported             int64
+}
+
+// reporting_interval_seconds is resolved from the device, then its group, then
+// the global defaults, and last_reported is 0 for devices without metrics
+func (q *Queries) ListReportingDevices(ctx context.Context) ([]*ListReportingDevicesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listReportingDevices)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListReportingDevicesRow
+	for rows.Next() {
+		var i ListReportingDevicesRow
+		if err := rows.Scan(&i.DeviceID, &i.ReportingIntervalSeconds, &i.LastReported); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordAlertOccurrence = `-- name: RecordAlertOccurrence :execrows
+UPDATE alerts
+SET occurrences = occurrences + 1,
+    last_seen   = MAX(last_seen, CAST(?1 AS INTEGER))
+WHERE device_id = ?2
+  AND id = ?3
+`
+
+type RecordAlertOccurrenceParams struct {
+	LastSeen int64
+	DeviceID string
+	ID       int64
+}
+
+func (q *Queries) RecordAlertOccurrence(ctx context.Context, arg RecordAlertOccurrenceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, recordAlertOccurrence, arg.LastSeen, arg.DeviceID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const registerDevice = `-- name: RegisterDevice :exec
+INSERT INTO devices (id, created_at)
+VALUES (?, ?)
+ON CONFLICT(id) DO NOTHING
+`
+
+type RegisterDeviceParams struct {
+	ID        string
+	CreatedAt int64
+}
+
+// registers a device that is configured before it is created
+func (q *Queries) RegisterDevice(ctx context.Context, arg RegisterDeviceParams) error {
+	_, err := q.db.ExecContext(ctx, registerDevice, arg.ID, arg.CreatedAt)
+	return err
+}
+
+const resolveDeviceAlert = `-- name: ResolveDeviceAlert :execrows
+UPDATE alerts
+SET state       = 'RESOLVED',
+    resolved_at = ?
+WHERE device_id = ?
+  AND id = ?
+  AND state != 'RESOLVED'
+`
+
+type ResolveDeviceAlertParams struct {
+	ResolvedAt *int64
+	DeviceID   string
+	ID         int64
+}
+
+func (q *Queries) ResolveDeviceAlert(ctx context.Context, arg ResolveDeviceAlertParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, resolveDeviceAlert, arg.ResolvedAt, arg.DeviceID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const resolveDeviceAlertsByCondition = `-- name: ResolveDeviceAlertsByCondition :execrows
+UPDATE alerts
+SET state       = 'RESOLVED',
+    resolved_at = ?
+WHERE device_id = ?
+  AND condition IN (/*SLICE:conditions*/?)
+  AND state != 'RESOLVED'
+`
+
+type ResolveDeviceAlertsByConditionParams struct {
+	ResolvedAt *int64
+	DeviceID   string
+	Conditions []string
+}
+
+func (q *Queries) ResolveDeviceAlertsByCondition(ctx context.Context, arg ResolveDeviceAlertsByConditionParams) (int64, error) {
+	query := resolveDeviceAlertsByCondition
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.ResolvedAt)
+	queryParams = append(queryParams, arg.DeviceID)
+	if len(arg.Conditions) > 0 {
+		for _, v := range arg.Conditions {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:conditions*/?", strings.Repeat(",?", len(arg.Conditions))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:conditions*/?", "NULL", 1)
+	}
+	result, err := q.db.ExecContext(ctx, query, queryParams...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const saveDeviceAlert = `-- name: SaveDeviceAlert :one
+INSERT INTO alerts (device_id, rule_id, reason, severity, desc, timestamp, state, condition, occurrences, last_seen,
+                    config_version)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id
+`
+
+type SaveDeviceAlertParams struct {
+	DeviceID      string
+	RuleID        *int64
+	Reason        string
+	Severity      string
+	Desc          string
+	Timestamp     int64
+	State         string
+	Condition     string
+	Occurrences   int64
+	LastSeen      int64
+	ConfigVersion int64
+}
+
+func (q *Queries) SaveDeviceAlert(ctx context.Context, arg SaveDeviceAlertParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, saveDeviceAlert,
+		arg.DeviceID,
+		arg.RuleID,
+		arg.Reason,
+		arg.Severity,
+		arg.Desc,
+		arg.Timestamp,
+		arg.State,
+		arg.Condition,
+		arg.Occurrences,
+		arg.LastSeen,
+		arg.ConfigVersion,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const saveDeviceConfigVersion = `-- name: SaveDeviceConfigVersion :one
+INSERT INTO device_config_versions (device_id, version, temperature_threshold, battery_threshold, expressions,
+                                    consecutive_breaches, hysteresis, cooldowns, reporting_interval_seconds,
+                                    created_at, author, reason)
+SELECT ?1,
+       cur.version + 1,
+       ?2, ?3, ?4,
+       ?5, ?6, ?7,
+       ?8, ?9, ?10, ?11
+FROM (SELECT COALESCE(MAX(v.version), 0) AS version
+      FROM device_config_versions v
+      WHERE v.device_id = ?1) cur
+WHERE CAST(?12 AS INTEGER) IS NULL
+   OR cur.version = ?12
+RETURNING version
+`
+
+type SaveDeviceConfigVersionParams struct {
+	DeviceID                 string
+	TemperatureThreshold     *float64
+	BatteryThreshold         *int64
+	Expressions              *string
+	ConsecutiveBreaches      *int64
+	Hysteresis               *float64
+	Cooldowns                *string
+	ReportingIntervalSeconds *int64
+	CreatedAt                int64
+	Author                   string
+	Reason                   string
+	ExpectedVersion          *int64
+}
+
+// versions are numbered from 1 per device, and no version is saved if
+// expected_version is set and is not the current version (0 if none)
+func (q *Queries) SaveDeviceConfigVersion(ctx context.Context, arg SaveDeviceConfigVersionParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, saveDeviceConfigVersion,
+		arg.DeviceID,
+		arg.TemperatureThreshold,
+		arg.BatteryThreshold,
+		arg.Expressions,
+		arg.ConsecutiveBreaches,
+		arg.Hysteresis,
+		arg.Cooldowns,
+		arg.ReportingIntervalSeconds,
+		arg.CreatedAt,
+		arg.Author,
+		arg.Reason,
+		arg.ExpectedVersion,
+	)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
+const saveDeviceMetric = `-- name: SaveDeviceMetric :one
+INSERT INTO metrics (device_id, timestamp)
+VALUES (?, ?)
+RETURNING id
+`
+
+type SaveDeviceMetricParams struct {
+	DeviceID  string
+	Timestamp int64
+}
+
+func (q *Queries) SaveDeviceMetric(ctx context.Context, arg SaveDeviceMetricParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, saveDeviceMetric, arg.DeviceID, arg.Timestamp)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const saveMetricValue = `-- name: SaveMetricValue :exec
+INSERT INTO metric_values (metric_id, name, value, unit)
+VALUES (?, ?, ?, ?)
+`
+
+type SaveMetricValueParams struct {
+	MetricID int64
+	Name     string
+	Value    float64
+	Unit     string
+}
+
+func (q *Queries) SaveMetricValue(ctx context.Context, arg SaveMetricValueParams) error {
+	_, err := q.db.ExecContext(ctx, saveMetricValue,
+		arg.MetricID,
+		arg.Name,
+		arg.Value,
+		arg.Unit,
+	)
+	return err
+}
+
+const searchAlerts = `-- name: SearchAlerts :many
+WITH filter AS (SELECT CAST(?7 AS TEXT) AS reasons),
+     selector AS (SELECT CAST(?8 AS TEXT) AS requirements)
+SELECT a.id, a.device_id, a.reason, a."desc", a.timestamp, a.rule_id, a.severity, a.state, a.condition, a.acknowledged_by, a.ack_comment, a.acknowledged_at, a.resolved_at, a.occurrences, a.last_seen, a.config_version
+FROM alerts a
+WHERE a.timestamp >= ?1
+  AND a.timestamp <= ?2
+  AND (
+    (SELECT json_array_length(s.requirements) FROM selector s) = 0
+        OR a.device_id IN (SELECT d.id FROM devices d, selector s WHERE labels_match(d.labels, s.requirements))
     )
-ORDER BY timestamp DESC, id DESC
+  AND (CAST(?3 AS TEXT) IS NULL OR a.state = ?3)
+  AND (CAST(?4 AS TEXT) IS NULL OR a.severity = ?4)
+  AND (
+    (SELECT json_array_length(f.reasons) FROM filter f) = 0
+        OR a.reason IN (SELECT r.value FROM filter f, json_each(f.reasons) r)
+    )
+  -- composite cursor, where end_ts is at most the previous page last row
+  -- timestamp
+  AND (
+    CAST(?5 AS INTEGER) IS NULL
+        OR a.timestamp < ?2
+        OR a.id < ?5
+    )
+ORDER BY a.timestamp DESC, a.id DESC
 LIMIT ?6
 `
 
-type GetDeviceAlertsParams struct {
-	DeviceID string
-	StartTs  *int64
-	EndTs    *int64
-	LastTs   *int64
+type SearchAlertsParams struct {
+	StartTs  int64
+	EndTs    int64
+	State    *string
+	Severity *string
 	LastID   *int64
 	Limit    int64
+	Reasons  string
+	Selector string
 }
 
-func (q *Queries) GetDeviceAlerts(ctx context.Context, arg GetDeviceAlertsParams) ([]*Alert, error) {
-	rows, err := q.db.QueryContext(ctx, getDeviceAlerts,
-		arg.DeviceID,
+// reasons is a JSON array, or empty to not filter by it, and selector is a JSON
+// array of label requirements that the devices of the alerts must all meet, see
+// labels_match in labels.go. start_ts and end_ts are always set so that the
+// time window is a range on alerts_timestamp_idx:
+//
+//	SEARCH a USING INDEX alerts_timestamp_idx (timestamp>? AND timestamp<?)
+func (q *Queries) SearchAlerts(ctx context.Context, arg SearchAlertsParams) ([]*Alert, error) {
+	rows, err := q.db.QueryContext(ctx, searchAlerts,
 		arg.StartTs,
 		arg.EndTs,
-		arg.LastTs,
+		arg.State,
+		arg.Severity,
 		arg.LastID,
 		arg.Limit,
+		arg.Reasons,
+		arg.Selector,
 	)
 	if err != nil {
 		return nil, err
@@ -65,6 +2018,17 @@ func (q *Queries) GetDeviceAlerts(ctx context.Context, arg GetDeviceAlertsParams
 			&i.Reason,
 			&i.Desc,
 			&i.Timestamp,
+			&i.RuleID,
+			&i.Severity,
+			&i.State,
+			&i.Condition,
+			&i.AcknowledgedBy,
+			&i.AckComment,
+			&i.AcknowledgedAt,
+			&i.ResolvedAt,
+			&i.Occurrences,
+			&i.LastSeen,
+			&i.ConfigVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -79,80 +2043,90 @@ func (q *Queries) GetDeviceAlerts(ctx context.Context, arg GetDeviceAlertsParams
 	return items, nil
 }
 
-const getDeviceConfig = `-- name: GetDeviceConfig :one
-SELECT temperature_threshold, battery_threshold
-FROM configs
-WHERE device_id = ?
-`
-
-type GetDeviceConfigRow struct {
-	TemperatureThreshold float64
-	BatteryThreshold     int64
-}
-
-func (q *Queries) GetDeviceConfig(ctx context.Context, deviceID string) (*GetDeviceConfigRow, error) {
-	row := q.db.QueryRowContext(ctx, getDeviceConfig, deviceID)
-	var i GetDeviceConfigRow
-	err := row.Scan(&i.TemperatureThreshold, &i.BatteryThreshold)
-	return &i, err
-}
-
-const getDeviceMetrics = `-- name: GetDeviceMetrics :many
-SELECT id, device_id, temperature, battery, timestamp
-FROM metrics
-WHERE device_id = ?1
-  -- time window
-  AND (CAST(?2 AS INTEGER) IS NULL OR timestamp >= ?2)
-  AND (CAST(?3 AS INTEGER) IS NULL OR timestamp <= ?3)
-  -- composite cursor
+const searchDeviceAlerts = `-- name: SearchDeviceAlerts :many
+WITH filter AS (SELECT CAST(?7 AS TEXT) AS device_ids,
+                       CAST(?8 AS TEXT)    AS reasons),
+     selector AS (SELECT CAST(?9 AS TEXT) AS requirements)
+SELECT a.id, a.device_id, a.reason, a."desc", a.timestamp, a.rule_id, a.severity, a.state, a.condition, a.acknowledged_by, a.ack_comment, a.acknowledged_at, a.resolved_at, a.occurrences, a.last_seen, a.config_version
+FROM alerts a
+WHERE a.device_id IN (SELECT i.value FROM filter f, json_each(f.device_ids) i)
+  AND a.timestamp >= ?1
+  AND a.timestamp <= ?2
   AND (
-    CAST(?4 AS INTEGER) IS NULL
-        OR (
-        -- timestamp less than previous page last row
-        timestamp < ?4
-            OR (
-            -- or timestamp equal to previous page last row
-            timestamp = ?4
-                -- but is less than last row id
-                AND (CAST(?5 AS INTEGER) IS NULL OR id < ?5)
-            )
-        )
+    (SELECT json_array_length(s.requirements) FROM selector s) = 0
+        OR a.device_id IN (SELECT d.id FROM devices d, selector s WHERE labels_match(d.labels, s.requirements))
     )
-ORDER BY timestamp DESC, id DESC
+  AND (CAST(?3 AS TEXT) IS NULL OR a.state = ?3)
+  AND (CAST(?4 AS TEXT) IS NULL OR a.severity = ?4)
+  AND (
+    (SELECT json_array_length(f.reasons) FROM filter f) = 0
+        OR a.reason IN (SELECT r.value FROM filter f, json_each(f.reasons) r)
+    )
+  -- composite cursor, where end_ts is at most the previous page last row
+  -- timestamp
+  AND (
+    CAST(?5 AS INTEGER) IS NULL
+        OR a.timestamp < ?2
+        OR a.id < ?5
+    )
+ORDER BY a.timestamp DESC, a.id DESC
 LIMIT ?6
 `
 
-type GetDeviceMetricsParams struct {
-	DeviceID string
-	StartTs  *int64
-	EndTs    *int64
-	LastTs   *int64
-	LastID   *int64
-	Limit    int64
+type SearchDeviceAlertsParams struct {
+	StartTs   int64
+	EndTs     int64
+	State     *string
+	Severity  *string
+	LastID    *int64
+	Limit     int64
+	DeviceIds string
+	Reasons   string
+	Selector  string
 }
 
-func (q *Queries) GetDeviceMetrics(ctx context.Context, arg GetDeviceMetricsParams) ([]*Metric, error) {
-	rows, err := q.db.QueryContext(ctx, getDeviceMetrics,
-		arg.DeviceID,
+// SearchAlerts for a list of devices, which is a separate query so that the
+// device and time filters are ranges on alerts_device_id_timestamp_idx:
+//
+//	SEARCH a USING INDEX alerts_device_id_timestamp_idx (device_id=? AND timestamp>? AND timestamp<?)
+//
+// device_ids is a JSON array that must not be empty.
+func (q *Queries) SearchDeviceAlerts(ctx context.Context, arg SearchDeviceAlertsParams) ([]*Alert, error) {
+	rows, err := q.db.QueryContext(ctx, searchDeviceAlerts,
 		arg.StartTs,
 		arg.EndTs,
-		arg.LastTs,
+		arg.State,
+		arg.Severity,
 		arg.LastID,
 		arg.Limit,
+		arg.DeviceIds,
+		arg.Reasons,
+		arg.Selector,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []*Metric
+	var items []*Alert
 	for rows.Next() {
-		var i Metric
+		var i Alert
 		if err := rows.Scan(
 			&i.ID,
 			&i.DeviceID,
-			&i.Temperature,
-			&i.Battery,
+			&i.Reason,
+			&i.Desc,
 			&i.Timestamp,
+			&i.RuleID,
+			&i.Severity,
+			&i.State,
+			&i.Condition,
+			&i.AcknowledgedBy,
+			&i.AckComment,
+			&i.AcknowledgedAt,
+			&i.ResolvedAt,
+			&i.Occurrences,
+			&i.LastSeen,
+			&i.ConfigVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -167,65 +2141,221 @@ func (q *Queries) GetDeviceMetrics(ctx context.Context, arg GetDeviceMetricsPara
 	return items, nil
 }
 
-const saveDeviceAlert = `-- name: SaveDeviceAlert :exec
-INSERT INTO alerts (device_id, reason, desc, timestamp)
+const touchDevice = `-- name: TouchDevice :exec
+INSERT INTO devices (id, created_at, last_seen)
+VALUES (?1, ?2, ?2)
+ON CONFLICT(id) DO UPDATE SET last_seen = MAX(COALESCE(last_seen, 0), excluded.last_seen)
+`
+
+type TouchDeviceParams struct {
+	ID     string
+	SeenAt int64
+}
+
+// registers a device recording a metric for the first time and tracks when it
+// was last seen
+func (q *Queries) TouchDevice(ctx context.Context, arg TouchDeviceParams) error {
+	_, err := q.db.ExecContext(ctx, touchDevice, arg.ID, arg.SeenAt)
+	return err
+}
+
+const updateAlertRule = `-- name: UpdateAlertRule :execrows
+UPDATE alert_rules
+SET metric         = ?,
+    operator       = ?,
+    threshold      = ?,
+    threshold_high = ?,
+    severity       = ?,
+    window_seconds = ?
+WHERE device_id = ?
+  AND id = ?
+`
+
+type UpdateAlertRuleParams struct {
+	Metric        string
+	Operator      string
+	Threshold     float64
+	ThresholdHigh float64
+	Severity      string
+	WindowSeconds int64
+	DeviceID      string
+	ID            int64
+}
+
+func (q *Queries) UpdateAlertRule(ctx context.Context, arg UpdateAlertRuleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateAlertRule,
+		arg.Metric,
+		arg.Operator,
+		arg.Threshold,
+		arg.ThresholdHigh,
+		arg.Severity,
+		arg.WindowSeconds,
+		arg.DeviceID,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateDevice = `-- name: UpdateDevice :execrows
+UPDATE devices
+SET display_name     = ?,
+    model            = ?,
+    firmware_version = ?,
+    location         = ?,
+    labels           = ?,
+    group_id         = ?
+WHERE id = ?
+`
+
+type UpdateDeviceParams struct {
+	DisplayName     string
+	Model           string
+	FirmwareVersion string
+	Location        string
+	Labels          string
+	GroupID         *string
+	ID              string
+}
+
+func (q *Queries) UpdateDevice(ctx context.Context, arg UpdateDeviceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateDevice,
+		arg.DisplayName,
+		arg.Model,
+		arg.FirmwareVersion,
+		arg.Location,
+		arg.Labels,
+		arg.GroupID,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateOperation = `-- name: UpdateOperation :execrows
+UPDATE operations
+SET state           = ?,
+    deleted_metrics = ?,
+    deleted_alerts  = ?,
+    error           = ?,
+    updated_at      = ?
+WHERE id = ?
+`
+
+type UpdateOperationParams struct {
+	State          string
+	DeletedMetrics int64
+	DeletedAlerts  int64
+	Error          string
+	UpdatedAt      int64
+	ID             int64
+}
+
+func (q *Queries) UpdateOperation(ctx context.Context, arg UpdateOperationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateOperation,
+		arg.State,
+		arg.DeletedMetrics,
+		arg.DeletedAlerts,
+		arg.Error,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertConditionState = `-- name: UpsertConditionState :exec
+INSERT INTO alert_states (device_id, condition, breaches, active)
 VALUES (?, ?, ?, ?)
+ON CONFLICT(device_id, condition) DO UPDATE
+    SET breaches=excluded.breaches,
+        active=excluded.active
 `
 
-type SaveDeviceAlertParams struct {
+type UpsertConditionStateParams struct {
 	DeviceID  string
-	Reason    string
-	Desc      string
-	Timestamp int64
+	Condition string
+	Breaches  int64
+	Active    bool
 }
 
-func (q *Queries) SaveDeviceAlert(ctx context.Context, arg SaveDeviceAlertParams) error {
-	_, err := q.db.ExecContext(ctx, saveDeviceAlert,
+func (q *Queries) UpsertConditionState(ctx context.Context, arg UpsertConditionStateParams) error {
+	_, err := q.db.ExecContext(ctx, upsertConditionState,
 		arg.DeviceID,
-		arg.Reason,
-		arg.Desc,
-		arg.Timestamp,
+		arg.Condition,
+		arg.Breaches,
+		arg.Active,
 	)
 	return err
 }
 
-const saveDeviceMetric = `-- name: SaveDeviceMetric :exec
-INSERT INTO metrics (device_id, temperature, battery, timestamp)
-VALUES (?, ?, ?, ?)
+const upsertConfigSettings = `-- name: UpsertConfigSettings :exec
+INSERT INTO config_settings (level, scope_id, temperature_threshold, battery_threshold, expressions,
+                             consecutive_breaches, hysteresis, cooldowns, reporting_interval_seconds, version)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(level, scope_id) DO UPDATE
+    SET temperature_threshold=excluded.temperature_threshold,
+        battery_threshold=excluded.battery_threshold,
+        expressions=excluded.expressions,
+        consecutive_breaches=excluded.consecutive_breaches,
+        hysteresis=excluded.hysteresis,
+        cooldowns=excluded.cooldowns,
+        reporting_interval_seconds=excluded.reporting_interval_seconds,
+        version=excluded.version
 `
 
-type SaveDeviceMetricParams struct {
-	DeviceID    string
-	Temperature float64
-	Battery     int64
-	Timestamp   int64
+type UpsertConfigSettingsParams struct {
+	Level                    string
+	ScopeID                  string
+	TemperatureThreshold     *float64
+	BatteryThreshold         *int64
+	Expressions              *string
+	ConsecutiveBreaches      *int64
+	Hysteresis               *float64
+	Cooldowns                *string
+	ReportingIntervalSeconds *int64
+	Version                  int64
 }
 
-func (q *Queries) SaveDeviceMetric(ctx context.Context, arg SaveDeviceMetricParams) error {
-	_, err := q.db.ExecContext(ctx, saveDeviceMetric,
-		arg.DeviceID,
-		arg.Temperature,
-		arg.Battery,
-		arg.Timestamp,
+func (q *Queries) UpsertConfigSettings(ctx context.Context, arg UpsertConfigSettingsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertConfigSettings,
+		arg.Level,
+		arg.ScopeID,
+		arg.TemperatureThreshold,
+		arg.BatteryThreshold,
+		arg.Expressions,
+		arg.ConsecutiveBreaches,
+		arg.Hysteresis,
+		arg.Cooldowns,
+		arg.ReportingIntervalSeconds,
+		arg.Version,
 	)
 	return err
 }
 
-const upsertDeviceConfig = `-- name: UpsertDeviceConfig :exec
-INSERT INTO configs (device_id, temperature_threshold, battery_threshold)
+const upsertDeviceLatest = `-- name: UpsertDeviceLatest :exec
+INSERT INTO device_latest (device_id, metric_id, timestamp)
 VALUES (?, ?, ?)
-ON CONFLICT(device_id) DO UPDATE
-    SET temperature_threshold=excluded.temperature_threshold,
-        battery_threshold=excluded.battery_threshold
+ON CONFLICT(device_id) DO UPDATE SET metric_id = excluded.metric_id,
+                                     timestamp = excluded.timestamp
+WHERE excluded.timestamp >= device_latest.timestamp
 `
 
-type UpsertDeviceConfigParams struct {
-	DeviceID             string
-	TemperatureThreshold float64
-	BatteryThreshold     int64
+type UpsertDeviceLatestParams struct {
+	DeviceID  string
+	MetricID  int64
+	Timestamp int64
 }
 
-func (q *Queries) UpsertDeviceConfig(ctx context.Context, arg UpsertDeviceConfigParams) error {
-	_, err := q.db.ExecContext(ctx, upsertDeviceConfig, arg.DeviceID, arg.TemperatureThreshold, arg.BatteryThreshold)
+// tracks the latest metric of a device, keeping the later metric when metrics
+// are recorded out of order
+func (q *Queries) UpsertDeviceLatest(ctx context.Context, arg UpsertDeviceLatestParams) error {
+	_, err := q.db.ExecContext(ctx, upsertDeviceLatest, arg.DeviceID, arg.MetricID, arg.Timestamp)
 	return err
 }
@@ -5,23 +5,119 @@
 package sqlc
 
 type Alert struct {
+	ID             int64
+	DeviceID       string
+	Reason         string
+	Desc           string
+	Timestamp      int64
+	RuleID         *int64
+	Severity       string
+	State          string
+	Condition      string
+	AcknowledgedBy string
+	AckComment     string
+	AcknowledgedAt *int64
+	ResolvedAt     *int64
+	Occurrences    int64
+	LastSeen       int64
+	ConfigVersion  int64
+}
+
+type AlertRule struct {
+	ID            int64
+	DeviceID      string
+	Metric        string
+	Operator      string
+	Threshold     float64
+	ThresholdHigh float64
+	Severity      string
+	WindowSeconds int64
+}
+
+type AlertState struct {
+	DeviceID  string
+	Condition string
+	Breaches  int64
+	Active    bool
+}
+
+type ConfigSetting struct {
+	Level                    string
+	ScopeID                  string
+	TemperatureThreshold     *float64
+	BatteryThreshold         *int64
+	Expressions              *string
+	ConsecutiveBreaches      *int64
+	Hysteresis               *float64
+	Cooldowns                *string
+	ReportingIntervalSeconds *int64
+	Version                  int64
+}
+
+type Device struct {
+	Seq             int64
+	ID              string
+	DisplayName     string
+	Model           string
+	FirmwareVersion string
+	Location        string
+	Labels          string
+	CreatedAt       int64
+	LastSeen        *int64
+	GroupID         *string
+}
+
+type DeviceConfigVersion struct {
+	DeviceID                 string
+	Version                  int64
+	TemperatureThreshold     *float64
+	BatteryThreshold         *int64
+	Expressions              *string
+	ConsecutiveBreaches      *int64
+	Hysteresis               *float64
+	Cooldowns                *string
+	ReportingIntervalSeconds *int64
+	CreatedAt                int64
+	Author                   string
+	Reason                   string
+}
+
+type DeviceGroup struct {
+	ID          string
+	DisplayName string
+	CreatedAt   int64
+}
+
+type DeviceLatest struct {
+	DeviceID  string
+	MetricID  int64
+	Timestamp int64
+}
+
+type Metric struct {
 	ID        int64
 	DeviceID  string
-	Reason    string
-	Desc      string
 	Timestamp int64
 }
 
-type Config struct {
-	DeviceID             string
-	TemperatureThreshold float64
-	BatteryThreshold     int64
+type MetricValue struct {
+	ID       int64
+	MetricID int64
+	Name     string
+	Value    float64
+	Unit     string
 }
 
-type Metric struct {
-	ID          int64
-	DeviceID    string
-	Temperature float64
-	Battery     int64
-	Timestamp   int64
+type Operation struct {
+	ID             int64
+	Type           string
+	DeviceID       string
+	State          string
+	DeletedMetrics int64
+	DeletedAlerts  int64
+	MaxMetricID    int64
+	MaxAlertID     int64
+	Error          string
+	CreatedAt      int64
+	UpdatedAt      int64
 }
@@ -9,12 +9,99 @@ import (
 )
 
 type Querier interface {
+	AcknowledgeDeviceAlert(ctx context.Context, arg AcknowledgeDeviceAlertParams) (int64, error)
+	// counts alerts in buckets aligned to the unix epoch
+	CountAlertsByBucket(ctx context.Context, arg CountAlertsByBucketParams) ([]*CountAlertsByBucketRow, error)
+	CountAlertsByDevice(ctx context.Context, arg CountAlertsByDeviceParams) ([]*CountAlertsByDeviceRow, error)
+	CountAlertsByReason(ctx context.Context, arg CountAlertsByReasonParams) ([]*CountAlertsByReasonRow, error)
+	CreateAlertRule(ctx context.Context, arg CreateAlertRuleParams) (int64, error)
+	CreateDevice(ctx context.Context, arg CreateDeviceParams) (int64, error)
+	CreateDeviceGroup(ctx context.Context, arg CreateDeviceGroupParams) (int64, error)
+	CreateOperation(ctx context.Context, arg CreateOperationParams) (int64, error)
+	DeleteAlertRule(ctx context.Context, arg DeleteAlertRuleParams) (int64, error)
+	DeleteDevice(ctx context.Context, id string) (int64, error)
+	DeleteDeviceAlertRules(ctx context.Context, deviceID string) error
+	DeleteDeviceAlertsBatch(ctx context.Context, arg DeleteDeviceAlertsBatchParams) (int64, error)
+	DeleteDeviceConditionStates(ctx context.Context, deviceID string) error
+	DeleteDeviceConfigSettings(ctx context.Context, scopeID string) error
+	DeleteDeviceConfigVersions(ctx context.Context, deviceID string) error
+	DeleteDeviceLatest(ctx context.Context, deviceID string) error
+	// metric values are deleted by cascade
+	DeleteDeviceMetricsBatch(ctx context.Context, arg DeleteDeviceMetricsBatchParams) (int64, error)
+	GetAlertRule(ctx context.Context, arg GetAlertRuleParams) (*AlertRule, error)
+	GetAlertsAfterID(ctx context.Context, arg GetAlertsAfterIDParams) ([]*Alert, error)
+	GetConditionStates(ctx context.Context, deviceID string) ([]*AlertState, error)
+	GetDevice(ctx context.Context, id string) (*Device, error)
+	GetDeviceAlert(ctx context.Context, arg GetDeviceAlertParams) (*Alert, error)
+	// reasons is a JSON array of alert reasons, or empty to not filter by reason
 	GetDeviceAlerts(ctx context.Context, arg GetDeviceAlertsParams) ([]*Alert, error)
-	GetDeviceConfig(ctx context.Context, deviceID string) (*GetDeviceConfigRow, error)
+	// pages alerts oldest first, see GetDeviceAlerts
+	// reasons is a JSON array of alert reasons, or empty to not filter by reason
+	GetDeviceAlertsAsc(ctx context.Context, arg GetDeviceAlertsAscParams) ([]*Alert, error)
+	// settings of each level that applies to the device
+	GetDeviceConfigSettings(ctx context.Context, deviceID string) ([]*ConfigSetting, error)
+	GetDeviceConfigVersion(ctx context.Context, arg GetDeviceConfigVersionParams) (*DeviceConfigVersion, error)
+	GetDeviceGroup(ctx context.Context, id string) (*DeviceGroup, error)
+	GetDeviceMaxAlertID(ctx context.Context, deviceID string) (int64, error)
+	GetDeviceMaxMetricID(ctx context.Context, deviceID string) (int64, error)
+	// aggregates the values of each metric in buckets aligned to the unix epoch
+	GetDeviceMetricAggregates(ctx context.Context, arg GetDeviceMetricAggregatesParams) ([]*GetDeviceMetricAggregatesRow, error)
 	GetDeviceMetrics(ctx context.Context, arg GetDeviceMetricsParams) ([]*Metric, error)
-	SaveDeviceAlert(ctx context.Context, arg SaveDeviceAlertParams) error
-	SaveDeviceMetric(ctx context.Context, arg SaveDeviceMetricParams) error
-	UpsertDeviceConfig(ctx context.Context, arg UpsertDeviceConfigParams) error
+	// pages metrics oldest first, see GetDeviceMetrics
+	GetDeviceMetricsAsc(ctx context.Context, arg GetDeviceMetricsAscParams) ([]*Metric, error)
+	// selector is a JSON array of label requirements that devices must all meet,
+	// see labels_match in labels.go
+	GetFleetSnapshot(ctx context.Context, arg GetFleetSnapshotParams) ([]*GetFleetSnapshotRow, error)
+	GetLatestConditionAlert(ctx context.Context, arg GetLatestConditionAlertParams) (*Alert, error)
+	GetLatestDeviceConfigVersion(ctx context.Context, deviceID string) (*DeviceConfigVersion, error)
+	// the lowest and highest values of a metric recorded in [start_ts, end_ts)
+	GetMetricRange(ctx context.Context, arg GetMetricRangeParams) (*GetMetricRangeRow, error)
+	GetMetricValues(ctx context.Context, metricIds []int64) ([]*MetricValue, error)
+	GetNoisiestDevices(ctx context.Context, arg GetNoisiestDevicesParams) ([]*GetNoisiestDevicesRow, error)
+	GetOperation(ctx context.Context, id int64) (*Operation, error)
+	ListAlertRules(ctx context.Context, deviceID string) ([]*AlertRule, error)
+	ListDeviceConfigVersions(ctx context.Context, arg ListDeviceConfigVersionsParams) ([]*DeviceConfigVersion, error)
+	ListDeviceGroups(ctx context.Context) ([]*DeviceGroup, error)
+	// selector is a JSON array of label requirements that devices must all meet,
+	// see labels_match in labels.go
+	ListDevices(ctx context.Context, arg ListDevicesParams) ([]*Device, error)
+	ListOperationsByState(ctx context.Context, state string) ([]*Operation, error)
+	// reporting_interval_seconds is resolved from the device, then its group, then
+	// the global defaults, and last_reported is 0 for devices without metrics
+	ListReportingDevices(ctx context.Context) ([]*ListReportingDevicesRow, error)
+	RecordAlertOccurrence(ctx context.Context, arg RecordAlertOccurrenceParams) (int64, error)
+	// registers a device that is configured before it is created
+	RegisterDevice(ctx context.Context, arg RegisterDeviceParams) error
+	ResolveDeviceAlert(ctx context.Context, arg ResolveDeviceAlertParams) (int64, error)
+	ResolveDeviceAlertsByCondition(ctx context.Context, arg ResolveDeviceAlertsByConditionParams) (int64, error)
+	SaveDeviceAlert(ctx context.Context, arg SaveDeviceAlertParams) (int64, error)
+	// versions are numbered from 1 per device, and no version is saved if
+	// expected_version is set and is not the current version (0 if none)
+	SaveDeviceConfigVersion(ctx context.Context, arg SaveDeviceConfigVersionParams) (int64, error)
+	SaveDeviceMetric(ctx context.Context, arg SaveDeviceMetricParams) (int64, error)
+	SaveMetricValue(ctx context.Context, arg SaveMetricValueParams) error
+	// reasons is a JSON array, or empty to not filter by it, and selector is a JSON
+	// array of label requirements that the devices of the alerts must all meet, see
+	// labels_match in labels.go. start_ts and end_ts are always set so that the
+	// time window is a range on alerts_timestamp_idx:
+	//   SEARCH a USING INDEX alerts_timestamp_idx (timestamp>? AND timestamp<?)
+	SearchAlerts(ctx context.Context, arg SearchAlertsParams) ([]*Alert, error)
+	// SearchAlerts for a list of devices, which is a separate query so that the
+	// device and time filters are ranges on alerts_device_id_timestamp_idx:
+	//   SEARCH a USING INDEX alerts_device_id_timestamp_idx (device_id=? AND timestamp>? AND timestamp<?)
+	// device_ids is a JSON array that must not be empty.
+	SearchDeviceAlerts(ctx context.Context, arg SearchDeviceAlertsParams) ([]*Alert, error)
+	// registers a device recording a metric for the first time and tracks when it
+	// was last seen
+	TouchDevice(ctx context.Context, arg TouchDeviceParams) error
+	UpdateAlertRule(ctx context.Context, arg UpdateAlertRuleParams) (int64, error)
+	UpdateDevice(ctx context.Context, arg UpdateDeviceParams) (int64, error)
+	UpdateOperation(ctx context.Context, arg UpdateOperationParams) (int64, error)
+	UpsertConditionState(ctx context.Context, arg UpsertConditionStateParams) error
+	UpsertConfigSettings(ctx context.Context, arg UpsertConfigSettingsParams) error
+	// tracks the latest metric of a device, keeping the later metric when metrics
+	// are recorded out of order
+	UpsertDeviceLatest(ctx context.Context, arg UpsertDeviceLatestParams) error
 }
 
 var _ Querier = (*Queries)(nil)